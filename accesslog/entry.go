@@ -0,0 +1,160 @@
+package accesslog
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy/ccpa"
+)
+
+// Entry is the line of the access log of a request. The middleware fills the details of the HTTP request and response,
+// the endpoint those of the transaction. An Entry is only written by the goroutine handling the request.
+type Entry struct {
+	Time          time.Time `json:"time"`
+	RequestID     string    `json:"request_id,omitempty"`
+	Endpoint      string    `json:"endpoint"`
+	Method        string    `json:"method"`
+	Status        int       `json:"status"`
+	LatencyMillis int64     `json:"latency_ms"`
+	RequestBytes  int64     `json:"request_bytes"`
+	ResponseBytes int64     `json:"response_bytes"`
+	IP            string    `json:"ip,omitempty"`
+	UserAgent     string    `json:"ua,omitempty"`
+	Account       string    `json:"account,omitempty"`
+	Imps          int       `json:"imps,omitempty"`
+	Bidders       []string  `json:"bidders,omitempty"`
+	Bids          int       `json:"bids,omitempty"`
+	Privacy       Privacy   `json:"privacy"`
+	// Anonymized tells whether the IP and user agent were anonymized
+	Anonymized bool `json:"anonymized,omitempty"`
+
+	// ipConf are the bits of the IPs kept on anonymization, those of the account once known
+	ipConf *config.AccountPrivacy
+}
+
+// Privacy holds the privacy signals applied to the request
+type Privacy struct {
+	// GDPR tells whether GDPR was enforced
+	GDPR      bool   `json:"gdpr,omitempty"`
+	COPPA     bool   `json:"coppa,omitempty"`
+	USPrivacy string `json:"us_privacy,omitempty"`
+	GPPSID    []int8 `json:"gpp_sid,omitempty"`
+	// GPC is the Global Privacy Control signal of the Sec-GPC header
+	GPC bool `json:"gpc,omitempty"`
+}
+
+// restricted tells whether any of the signals restricts the processing of the personal data of the user
+func (p Privacy) restricted() bool {
+	if p.GDPR || p.COPPA || p.GPC {
+		return true
+	}
+	if p.USPrivacy == "" {
+		return false
+	}
+	policy, err := ccpa.Policy{Consent: p.USPrivacy}.Parse(nil)
+	return err == nil && policy.ShouldEnforce("")
+}
+
+type entryKey struct{}
+
+// NewContext returns a copy of ctx carrying the entry
+func NewContext(ctx context.Context, entry *Entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, entry)
+}
+
+// FromContext returns the entry of the request, or nil if the request isn't logged. The setters of Entry do nothing
+// on nil, so the endpoints don't need to check.
+func FromContext(ctx context.Context) *Entry {
+	entry, _ := ctx.Value(entryKey{}).(*Entry)
+	return entry
+}
+
+// SetAccount records the account of the request, whose privacy settings drive the anonymization of the IP
+func (e *Entry) SetAccount(account *config.Account) {
+	if e == nil || account == nil {
+		return
+	}
+	e.Account = account.ID
+	e.ipConf = &account.Privacy
+}
+
+// SetRequest records the number of imps, the device and the privacy signals of the bid request
+func (e *Entry) SetRequest(req *openrtb_ext.RequestWrapper) {
+	if e == nil || req == nil || req.BidRequest == nil {
+		return
+	}
+	e.Imps = len(req.Imp)
+
+	if req.Device != nil {
+		if ip := req.Device.IP; ip != "" {
+			e.IP = ip
+		} else if ip := req.Device.IPv6; ip != "" {
+			e.IP = ip
+		}
+		if req.Device.UA != "" {
+			e.UserAgent = req.Device.UA
+		}
+	}
+
+	if req.Regs != nil {
+		e.Privacy.COPPA = req.Regs.COPPA == 1
+		e.Privacy.USPrivacy = req.Regs.USPrivacy
+		e.Privacy.GPPSID = req.Regs.GPPSID
+	}
+	if e.Privacy.USPrivacy == "" {
+		if regExt, err := req.GetRegExt(); err == nil {
+			e.Privacy.USPrivacy = regExt.GetUSPrivacy()
+		}
+	}
+}
+
+// SetGDPR records whether GDPR was enforced
+func (e *Entry) SetGDPR(enforced bool) {
+	if e == nil {
+		return
+	}
+	e.Privacy.GDPR = enforced
+}
+
+// SetBidders records the bidders called, sorted by name
+func (e *Entry) SetBidders(bidders []string) {
+	if e == nil {
+		return
+	}
+	e.Bidders = slices.Sorted(slices.Values(bidders))
+}
+
+// SetBidResponse records the number of bids returned, and the bidders called as reported by the response times of
+// the extension of the response
+func (e *Entry) SetBidResponse(resp *openrtb2.BidResponse, ext *openrtb_ext.ExtBidResponse) {
+	if e == nil {
+		return
+	}
+	if resp != nil {
+		e.Bids = 0
+		for _, seatBid := range resp.SeatBid {
+			e.Bids += len(seatBid.Bid)
+		}
+	}
+	if ext != nil && len(ext.ResponseTimeMillis) > 0 {
+		bidders := make([]string, 0, len(ext.ResponseTimeMillis))
+		for bidder := range ext.ResponseTimeMillis {
+			bidders = append(bidders, string(bidder))
+		}
+		e.SetBidders(bidders)
+	}
+}
+
+// SetPrivacySignals records the privacy signals of the requests without a bid request, like those of the user syncs
+func (e *Entry) SetPrivacySignals(gdpr bool, usPrivacy string, gppSID []int8) {
+	if e == nil {
+		return
+	}
+	e.Privacy.GDPR = gdpr
+	e.Privacy.USPrivacy = usPrivacy
+	e.Privacy.GPPSID = gppSID
+}
@@ -0,0 +1,123 @@
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	entry := &Entry{Endpoint: "/openrtb2/auction"}
+	assert.Same(t, entry, FromContext(NewContext(context.Background(), entry)))
+	assert.Nil(t, FromContext(context.Background()))
+}
+
+func TestNilEntry(t *testing.T) {
+	var entry *Entry
+	assert.NotPanics(t, func() {
+		entry.SetAccount(&config.Account{ID: "account"})
+		entry.SetRequest(&openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}})
+		entry.SetGDPR(true)
+		entry.SetBidders([]string{"appnexus"})
+		entry.SetBidResponse(&openrtb2.BidResponse{}, &openrtb_ext.ExtBidResponse{})
+		entry.SetPrivacySignals(true, "1YYN", nil)
+	})
+}
+
+func TestSetRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		request  *openrtb2.BidRequest
+		expected Entry
+	}{
+		{
+			name: "ipv4",
+			request: &openrtb2.BidRequest{
+				Imp:    []openrtb2.Imp{{ID: "1"}, {ID: "2"}},
+				Device: &openrtb2.Device{IP: "1.2.3.4", IPv6: "2001:db8::1", UA: "agent"},
+				Regs:   &openrtb2.Regs{COPPA: 1, USPrivacy: "1YYN", GPPSID: []int8{7}},
+			},
+			expected: Entry{
+				Imps:      2,
+				IP:        "1.2.3.4",
+				UserAgent: "agent",
+				Privacy:   Privacy{COPPA: true, USPrivacy: "1YYN", GPPSID: []int8{7}},
+			},
+		},
+		{
+			name: "ipv6",
+			request: &openrtb2.BidRequest{
+				Imp:    []openrtb2.Imp{{ID: "1"}},
+				Device: &openrtb2.Device{IPv6: "2001:db8::1"},
+			},
+			expected: Entry{
+				Imps:      1,
+				IP:        "2001:db8::1",
+				UserAgent: "http agent",
+			},
+		},
+		{
+			name: "us_privacy_in_ext",
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{Ext: json.RawMessage(`{"us_privacy":"1NNN"}`)},
+			},
+			expected: Entry{
+				UserAgent: "http agent",
+				Privacy:   Privacy{USPrivacy: "1NNN"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			entry := &Entry{UserAgent: "http agent"}
+			entry.SetRequest(&openrtb_ext.RequestWrapper{BidRequest: test.request})
+			assert.Equal(t, test.expected, *entry)
+		})
+	}
+}
+
+func TestSetBidResponse(t *testing.T) {
+	response := &openrtb2.BidResponse{
+		SeatBid: []openrtb2.SeatBid{
+			{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "1"}, {ID: "2"}}},
+			{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "3"}}},
+		},
+	}
+	ext := &openrtb_ext.ExtBidResponse{
+		ResponseTimeMillis: map[openrtb_ext.BidderName]int{"rubicon": 10, "appnexus": 20, "pubmatic": 30},
+	}
+
+	entry := &Entry{}
+	entry.SetBidResponse(response, ext)
+	assert.Equal(t, 3, entry.Bids)
+	assert.Equal(t, []string{"appnexus", "pubmatic", "rubicon"}, entry.Bidders)
+}
+
+func TestPrivacyRestricted(t *testing.T) {
+	testCases := []struct {
+		name     string
+		privacy  Privacy
+		expected bool
+	}{
+		{name: "none", privacy: Privacy{}, expected: false},
+		{name: "gdpr", privacy: Privacy{GDPR: true}, expected: true},
+		{name: "coppa", privacy: Privacy{COPPA: true}, expected: true},
+		{name: "gpc", privacy: Privacy{GPC: true}, expected: true},
+		{name: "us_privacy_opt_out", privacy: Privacy{USPrivacy: "1YYN"}, expected: true},
+		{name: "us_privacy_no_opt_out", privacy: Privacy{USPrivacy: "1YNN"}, expected: false},
+		{name: "us_privacy_invalid", privacy: Privacy{USPrivacy: "invalid"}, expected: false},
+		{name: "gpp_sid_only", privacy: Privacy{GPPSID: []int8{2}}, expected: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.privacy.restricted())
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package accesslog
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/docker/go-units"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/util/filelog"
+	"github.com/prebid/prebid-server/v3/util/httputil"
+	"github.com/prebid/prebid-server/v3/util/iputil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// Logger writes the entries of the access log as JSON lines, to a rotated file. Entries are serialized by the caller
+// and written asynchronously. They are dropped when the queue of the writer is full.
+//
+// Once the file reaches the max size, and on shutdown, it's renamed after the time it was started, e.g.
+// "access-20240102T030405.000000000Z.log" for "access.log", keeping the max number of rotated files.
+type Logger struct {
+	sampleRate  float64
+	sample      func() float64
+	anonymize   string
+	hostPrivacy config.AccountPrivacy
+	ipValidator iputil.IPValidator
+	clock       clock.Clock
+	writer      *filelog.AsyncWriter[[]byte]
+}
+
+// fileSink is the filelog.Sink writing the lines of the Logger to its rotated file
+type fileSink struct {
+	file *filelog.RotatingFile
+}
+
+// NewLogger returns the access log writing to cfg.Filename. The IPs of the entries are anonymized with the bits of
+// hostPrivacy until the account of the request is known.
+func NewLogger(cfg config.AccessLog, hostPrivacy config.AccountPrivacy, ipValidator iputil.IPValidator, clock clock.Clock) (*Logger, error) {
+	maxSize, err := units.FromHumanSize(cfg.MaxSize)
+	if err != nil {
+		return nil, fmt.Errorf("max_size: %v", err)
+	}
+	flushInterval, err := time.ParseDuration(cfg.FlushInterval)
+	if err != nil {
+		return nil, fmt.Errorf("flush_interval: %v", err)
+	}
+
+	file, err := filelog.NewRotatingFile(filelog.RotatingFileConfig{Path: cfg.Filename, MaxSize: maxSize, MaxBackups: cfg.MaxBackups}, clock)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Logger{
+		sampleRate:  cfg.SampleRate,
+		sample:      rand.Float64,
+		anonymize:   cfg.Anonymize,
+		hostPrivacy: hostPrivacy,
+		ipValidator: ipValidator,
+		clock:       clock,
+		writer:      filelog.NewAsyncWriter[[]byte]("[AccessLog]", "entries", fileSink{file}, cfg.QueueSize, flushInterval, clock),
+	}
+
+	logger.Infof("[AccessLog] Writing the access log to %s", cfg.Filename)
+	return l, nil
+}
+
+func (s fileSink) Write(line []byte) {
+	if err := s.file.Write(line); err != nil {
+		logger.Errorf("[AccessLog] Failed to write the access log: %v", err)
+	}
+}
+
+func (s fileSink) Tick() {
+	if err := s.file.Flush(); err != nil {
+		logger.Errorf("[AccessLog] Failed to write the access log: %v", err)
+	}
+}
+
+func (s fileSink) Close() {
+	if err := s.file.Close(); err != nil {
+		logger.Errorf("[AccessLog] Failed to close the access log: %v", err)
+	}
+}
+
+// Sampled tells whether a request should be logged
+func (l *Logger) Sampled() bool {
+	return l.sampleRate >= 1 || l.sample() < l.sampleRate
+}
+
+// NewEntry returns the entry of a request to the endpoint, with the details of the HTTP request
+func (l *Logger) NewEntry(r *http.Request, endpoint string) *Entry {
+	entry := &Entry{
+		Time:      l.clock.Now(),
+		Endpoint:  endpoint,
+		Method:    r.Method,
+		UserAgent: r.UserAgent(),
+	}
+	if ip, _ := httputil.FindIP(r, l.ipValidator); ip != nil {
+		entry.IP = ip.String()
+	}
+	entry.Privacy.GPC = r.Header.Get("Sec-GPC") == "1"
+	return entry
+}
+
+// Log writes the entry, once the request is complete
+func (l *Logger) Log(entry *Entry) {
+	entry.LatencyMillis = l.clock.Since(entry.Time).Milliseconds()
+	l.anonymizeEntry(entry)
+
+	data, err := jsonutil.Marshal(entry)
+	if err != nil {
+		logger.Errorf("[AccessLog] Error serializing the entry: %v", err)
+		return
+	}
+	data = append(data, '\n')
+	l.writer.Write(data)
+}
+
+// anonymizeEntry masks the IP with the bits kept by the privacy settings of the account, and drops the user agent,
+// if the entry is to be anonymized
+func (l *Logger) anonymizeEntry(entry *Entry) {
+	switch l.anonymize {
+	case config.AccessLogAnonymizeNever:
+		return
+	case config.AccessLogAnonymizePrivacy:
+		if !entry.Privacy.restricted() {
+			return
+		}
+	}
+
+	privacy := l.hostPrivacy
+	if entry.ipConf != nil {
+		privacy = *entry.ipConf
+	}
+	entry.IP = anonymizeIP(entry.IP, privacy)
+	entry.UserAgent = ""
+	entry.Anonymized = true
+}
+
+func anonymizeIP(ip string, privacy config.AccountPrivacy) string {
+	parsed, version := iputil.ParseIP(ip)
+	switch version {
+	case iputil.IPv4:
+		return parsed.Mask(net.CIDRMask(privacy.IPv4Config.AnonKeepBits, iputil.IPv4BitSize)).String()
+	case iputil.IPv6:
+		return parsed.Mask(net.CIDRMask(privacy.IPv6Config.AnonKeepBits, iputil.IPv6BitSize)).String()
+	default:
+		return ""
+	}
+}
+
+// Shutdown writes the queued entries and closes the file
+func (l *Logger) Shutdown() {
+	l.writer.Shutdown()
+}
@@ -0,0 +1,176 @@
+package accesslog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/iputil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig(path string) config.AccessLog {
+	return config.AccessLog{
+		Enabled:       true,
+		Filename:      path,
+		MaxSize:       "1MB",
+		MaxBackups:    1,
+		SampleRate:    1,
+		Anonymize:     config.AccessLogAnonymizePrivacy,
+		QueueSize:     10,
+		FlushInterval: "1s",
+	}
+}
+
+var hostPrivacy = config.AccountPrivacy{
+	IPv4Config: config.IPv4{AnonKeepBits: 24},
+	IPv6Config: config.IPv6{AnonKeepBits: 56},
+}
+
+func TestNewLoggerInvalidConfig(t *testing.T) {
+	cfg := newTestConfig(filepath.Join(t.TempDir(), "access.log"))
+	cfg.MaxSize = "big"
+	_, err := NewLogger(cfg, hostPrivacy, iputil.VersionIPValidator{}, clock.NewMock())
+	assert.EqualError(t, err, "max_size: invalid size: 'big'")
+
+	cfg = newTestConfig(filepath.Join(t.TempDir(), "access.log"))
+	cfg.FlushInterval = "soon"
+	_, err = NewLogger(cfg, hostPrivacy, iputil.VersionIPValidator{}, clock.NewMock())
+	assert.EqualError(t, err, `flush_interval: time: invalid duration "soon"`)
+}
+
+func TestLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	mockClock := clock.NewMock()
+	l, err := NewLogger(newTestConfig(path), hostPrivacy, iputil.PublicNetworkIPValidator{}, mockClock)
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	r.RemoteAddr = "1.2.3.4:8000"
+	r.Header.Set("User-Agent", "agent")
+	entry := l.NewEntry(r, "/openrtb2/auction")
+	entry.Account = "account"
+	entry.Status = http.StatusOK
+	mockClock.Add(50 * time.Millisecond)
+	l.Log(entry)
+
+	r.Header.Set("Sec-GPC", "1")
+	l.Log(l.NewEntry(r, "/setuid"))
+	l.Shutdown()
+
+	// the file is rotated on shutdown
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	rotated, err := filepath.Glob(filepath.Join(filepath.Dir(path), "access-*.log"))
+	require.NoError(t, err)
+	require.Len(t, rotated, 1)
+	content, err := os.ReadFile(rotated[0])
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	var logged Entry
+	require.NoError(t, jsonutil.Unmarshal([]byte(lines[0]), &logged))
+	assert.Equal(t, "/openrtb2/auction", logged.Endpoint)
+	assert.Equal(t, http.MethodPost, logged.Method)
+	assert.Equal(t, "account", logged.Account)
+	assert.Equal(t, int64(50), logged.LatencyMillis)
+	assert.Equal(t, "1.2.3.4", logged.IP)
+	assert.Equal(t, "agent", logged.UserAgent)
+	assert.False(t, logged.Anonymized)
+
+	logged = Entry{}
+	require.NoError(t, jsonutil.Unmarshal([]byte(lines[1]), &logged))
+	assert.Equal(t, "/setuid", logged.Endpoint)
+	assert.Equal(t, "1.2.3.0", logged.IP)
+	assert.Empty(t, logged.UserAgent)
+	assert.True(t, logged.Privacy.GPC)
+	assert.True(t, logged.Anonymized)
+}
+
+func TestLoggerDropsAfterShutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	l, err := NewLogger(newTestConfig(path), hostPrivacy, iputil.VersionIPValidator{}, clock.NewMock())
+	require.NoError(t, err)
+	l.Shutdown()
+	l.Shutdown()
+
+	assert.NotPanics(t, func() { l.Log(&Entry{Endpoint: "/event"}) })
+}
+
+func TestSampled(t *testing.T) {
+	l := &Logger{sampleRate: 0.5, sample: func() float64 { return 0.4 }}
+	assert.True(t, l.Sampled())
+	l.sample = func() float64 { return 0.6 }
+	assert.False(t, l.Sampled())
+	l.sampleRate = 1
+	assert.True(t, l.Sampled())
+}
+
+func TestAnonymizeEntry(t *testing.T) {
+	accountPrivacy := &config.AccountPrivacy{
+		IPv4Config: config.IPv4{AnonKeepBits: 16},
+		IPv6Config: config.IPv6{AnonKeepBits: 32},
+	}
+
+	testCases := []struct {
+		name      string
+		anonymize string
+		entry     Entry
+		expected  Entry
+	}{
+		{
+			name:      "never",
+			anonymize: config.AccessLogAnonymizeNever,
+			entry:     Entry{IP: "1.2.3.4", UserAgent: "agent", Privacy: Privacy{GDPR: true}},
+			expected:  Entry{IP: "1.2.3.4", UserAgent: "agent", Privacy: Privacy{GDPR: true}},
+		},
+		{
+			name:      "privacy_not_restricted",
+			anonymize: config.AccessLogAnonymizePrivacy,
+			entry:     Entry{IP: "1.2.3.4", UserAgent: "agent"},
+			expected:  Entry{IP: "1.2.3.4", UserAgent: "agent"},
+		},
+		{
+			name:      "privacy_restricted_host_bits",
+			anonymize: config.AccessLogAnonymizePrivacy,
+			entry:     Entry{IP: "1.2.3.4", UserAgent: "agent", Privacy: Privacy{COPPA: true}},
+			expected:  Entry{IP: "1.2.3.0", Privacy: Privacy{COPPA: true}, Anonymized: true},
+		},
+		{
+			name:      "always_account_bits_ipv4",
+			anonymize: config.AccessLogAnonymizeAlways,
+			entry:     Entry{IP: "1.2.3.4", UserAgent: "agent", ipConf: accountPrivacy},
+			expected:  Entry{IP: "1.2.0.0", Anonymized: true, ipConf: accountPrivacy},
+		},
+		{
+			name:      "always_account_bits_ipv6",
+			anonymize: config.AccessLogAnonymizeAlways,
+			entry:     Entry{IP: "2001:db8:1:2::1", ipConf: accountPrivacy},
+			expected:  Entry{IP: "2001:db8::", Anonymized: true, ipConf: accountPrivacy},
+		},
+		{
+			name:      "always_invalid_ip",
+			anonymize: config.AccessLogAnonymizeAlways,
+			entry:     Entry{IP: "invalid"},
+			expected:  Entry{Anonymized: true},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			l := &Logger{anonymize: test.anonymize, hostPrivacy: hostPrivacy}
+			entry := test.entry
+			l.anonymizeEntry(&entry)
+			assert.Equal(t, test.expected, entry)
+		})
+	}
+}
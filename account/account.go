@@ -11,12 +11,18 @@ import (
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/util/iputil"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 )
 
 // GetAccount looks up the config.Account object referenced by the given accountID, with access rules applied
 func GetAccount(ctx context.Context, cfg *config.Configuration, fetcher stored_requests.AccountFetcher, accountID string, me metrics.MetricsEngine) (account *config.Account, errs []error) {
+	ctx, span := tracing.Start(ctx, tracing.SpanFetchAccount, tracing.AttributeAccount.String(accountID))
+	defer func() {
+		tracing.EndWithErrors(span, errs)
+	}()
+
 	if cfg.AccountRequired && accountID == metrics.PublisherUnknown {
 		return nil, []error{&errortypes.AcctRequired{
 			Message: "Prebid-server has been configured to discard requests without a valid Account ID. Please reach out to the prebid server host.",
@@ -26,6 +32,10 @@ func GetAccount(ctx context.Context, cfg *config.Configuration, fetcher stored_r
 	if accountJSON, accErrs := fetcher.FetchAccount(ctx, cfg.AccountDefaultsJSON(), accountID); len(accErrs) > 0 || accountJSON == nil {
 		// accountID does not reference a valid account
 		for _, e := range accErrs {
+			// accountID references an account rejected by the validation of the stored accounts
+			if _, ok := e.(*errortypes.MalformedAcct); ok {
+				return nil, []error{e}
+			}
 			if _, ok := e.(stored_requests.NotFoundError); !ok {
 				errs = append(errs, e)
 			}
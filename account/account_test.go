@@ -40,6 +40,9 @@ func (af mockAccountFetcher) FetchAccount(ctx context.Context, accountDefaultsJS
 	if account, ok := mockAccountData[accountID]; ok {
 		return account, nil
 	}
+	if accountID == "rejected_acct" {
+		return nil, []error{&errortypes.MalformedAcct{Message: "rejected by the validation of the stored accounts"}}
+	}
 	return nil, []error{stored_requests.NotFoundError{ID: accountID, DataType: "Account"}}
 }
 
@@ -105,6 +108,10 @@ func TestGetAccount(t *testing.T) {
 		{accountID: "malformed_acct", required: false, disabled: true, err: &errortypes.MalformedAcct{}},
 		{accountID: "malformed_acct", required: true, disabled: true, err: &errortypes.MalformedAcct{}},
 
+		// pubID given and matches a host account rejected by the validation of the stored accounts
+		{accountID: "rejected_acct", required: false, disabled: false, err: &errortypes.MalformedAcct{}},
+		{accountID: "rejected_acct", required: true, disabled: true, err: &errortypes.MalformedAcct{}},
+
 		// account not provided (does not exist)
 		{accountID: "", required: false, disabled: false, err: nil},
 		{accountID: "", required: true, disabled: false, err: nil},
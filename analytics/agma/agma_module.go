@@ -17,6 +17,7 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
 )
 
 type httpSender = func(payload []byte) error
@@ -26,6 +27,16 @@ const (
 	p9        = 9
 )
 
+// ModuleName is the name of the module in the analytics config of the accounts
+const ModuleName = "agma"
+
+// accountOptions are the options of the module in the analytics config of an account. They track the events of the
+// account with the given code, like the accounts of the host config.
+type accountOptions struct {
+	Code      string `json:"code"`
+	SiteAppId string `json:"site_app_id"`
+}
+
 type AgmaLogger struct {
 	sender            httpSender
 	clock             clock.Clock
@@ -177,7 +188,9 @@ func (l *AgmaLogger) extractPublisherAndSite(requestWrapper *openrtb_ext.Request
 	return publisherId, appSiteId
 }
 
-func (l *AgmaLogger) shouldTrackEvent(requestWrapper *openrtb_ext.RequestWrapper) (bool, string) {
+// shouldTrackEvent tells whether the event is tracked, and with which code. The options of the account take precedence
+// over the accounts of the host config.
+func (l *AgmaLogger) shouldTrackEvent(requestWrapper *openrtb_ext.RequestWrapper, accountCfg *config.Account) (bool, string) {
 	if requestWrapper.User == nil {
 		return false, ""
 	}
@@ -199,6 +212,17 @@ func (l *AgmaLogger) shouldTrackEvent(requestWrapper *openrtb_ext.RequestWrapper
 		return false, ""
 	}
 
+	if accountCfg != nil {
+		if options := accountCfg.Analytics.ModuleOptions(ModuleName); len(options) > 0 {
+			var accountOpts accountOptions
+			if err := jsonutil.Unmarshal(options, &accountOpts); err != nil {
+				logger.Warnf("[AgmaAnalytics] Invalid options of account %s: %v", accountCfg.ID, err)
+			} else if accountOpts.Code != "" && (accountOpts.SiteAppId == "" || accountOpts.SiteAppId == appSiteId) {
+				return true, accountOpts.Code
+			}
+		}
+	}
+
 	for _, account := range l.accounts {
 		if account.PublisherId == publisherId {
 			if account.SiteAppId == "" {
@@ -217,7 +241,7 @@ func (l *AgmaLogger) LogAuctionObject(event *analytics.AuctionObject) {
 	if event == nil || event.Status != http.StatusOK || event.RequestWrapper == nil {
 		return
 	}
-	shouldTrack, code := l.shouldTrackEvent(event.RequestWrapper)
+	shouldTrack, code := l.shouldTrackEvent(event.RequestWrapper, event.Account)
 	if !shouldTrack {
 		return
 	}
@@ -233,7 +257,7 @@ func (l *AgmaLogger) LogAmpObject(event *analytics.AmpObject) {
 	if event == nil || event.Status != http.StatusOK || event.RequestWrapper == nil {
 		return
 	}
-	shouldTrack, code := l.shouldTrackEvent(event.RequestWrapper)
+	shouldTrack, code := l.shouldTrackEvent(event.RequestWrapper, event.Account)
 	if !shouldTrack {
 		return
 	}
@@ -249,7 +273,7 @@ func (l *AgmaLogger) LogVideoObject(event *analytics.VideoObject) {
 	if event == nil || event.Status != http.StatusOK || event.RequestWrapper == nil {
 		return
 	}
-	shouldTrack, code := l.shouldTrackEvent(event.RequestWrapper)
+	shouldTrack, code := l.shouldTrackEvent(event.RequestWrapper, event.Account)
 	if !shouldTrack {
 		return
 	}
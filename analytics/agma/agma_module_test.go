@@ -1,6 +1,7 @@
 package agma
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -214,7 +215,7 @@ func TestShouldTrackEvent(t *testing.T) {
 				Consent: agmaConsent,
 			},
 		},
-	})
+	}, nil)
 
 	assert.False(t, shouldTrack)
 	assert.Equal(t, "", code)
@@ -229,7 +230,7 @@ func TestShouldTrackEvent(t *testing.T) {
 				},
 			},
 		},
-	})
+	}, nil)
 
 	assert.False(t, shouldTrack)
 	assert.Equal(t, "", code)
@@ -247,7 +248,7 @@ func TestShouldTrackEvent(t *testing.T) {
 				Consent: "CP4LywcP4LywcLRAAAENCZCAAAIAAAIAAAAAIxQAQIwgAAAA.II7Nd_X__bX9n-_7_6ft0eY1f9_r37uQzDhfNs-8F3L_W_LwX32E7NF36tq4KmR4ku1bBIQNtHMnUDUmxaolVrzHsak2cpyNKJ_JkknsZe2dYGF9Pn9lD-YKZ7_5_9_f52T_9_9_-39z3_9f___dv_-__-vjf_599n_v9fV_78_Kf9______-____________8A",
 			},
 		},
-	})
+	}, nil)
 
 	assert.False(t, shouldTrack)
 	assert.Equal(t, "", code)
@@ -265,7 +266,7 @@ func TestShouldTrackEvent(t *testing.T) {
 				Consent: "CP4LywcP4LywcLRAAAENCZCAAIAAAAAAAAAAIxQAQIxAAAAA.II7Nd_X__bX9n-_7_6ft0eY1f9_r37uQzDhfNs-8F3L_W_LwX32E7NF36tq4KmR4ku1bBIQNtHMnUDUmxaolVrzHsak2cpyNKJ_JkknsZe2dYGF9Pn9lD-YKZ7_5_9_f52T_9_9_-39z3_9f___dv_-__-vjf_599n_v9fV_78_Kf9______-____________8A",
 			},
 		},
-	})
+	}, nil)
 
 	assert.False(t, shouldTrack)
 	assert.Equal(t, "", code)
@@ -283,7 +284,7 @@ func TestShouldTrackEvent(t *testing.T) {
 				Consent: agmaConsent,
 			},
 		},
-	})
+	}, nil)
 
 	assert.False(t, shouldTrack)
 	assert.Equal(t, "", code)
@@ -298,7 +299,7 @@ func TestShouldTrackEvent(t *testing.T) {
 				Consent: agmaConsent,
 			},
 		},
-	})
+	}, nil)
 
 	assert.True(t, shouldTrack)
 	assert.Equal(t, "abc", code)
@@ -313,7 +314,7 @@ func TestShouldTrackEvent(t *testing.T) {
 				Consent: agmaConsent,
 			},
 		},
-	})
+	}, nil)
 
 	assert.True(t, shouldTrack)
 	assert.Equal(t, "abc", code)
@@ -361,7 +362,7 @@ func TestShouldTrackMultipleAccounts(t *testing.T) {
 				Consent: agmaConsent,
 			},
 		},
-	})
+	}, nil)
 
 	assert.True(t, shouldTrack)
 	assert.Equal(t, "abc", code)
@@ -379,12 +380,98 @@ func TestShouldTrackMultipleAccounts(t *testing.T) {
 				Consent: agmaConsent,
 			},
 		},
-	})
+	}, nil)
 
 	assert.True(t, shouldTrack)
 	assert.Equal(t, "123", code)
 }
 
+func TestShouldTrackAccountOptions(t *testing.T) {
+	cfg := config.AgmaAnalytics{
+		Enabled: true,
+		Endpoint: config.AgmaAnalyticsHttpEndpoint{
+			Url:     "http://localhost:8000/event",
+			Timeout: "5s",
+		},
+		Buffers: config.AgmaAnalyticsBuffer{
+			EventCount: 1,
+			BufferSize: "1Kb",
+			Timeout:    "1s",
+		},
+		Accounts: []config.AgmaAnalyticsAccount{
+			{
+				PublisherId: "track-me",
+				Code:        "host-code",
+			},
+		},
+	}
+	mockedSender := new(MockedSender)
+	mockedSender.On("Send", mock.Anything).Return(nil)
+	logger, err := newAgmaLogger(cfg, mockedSender.Send, clock.NewMock())
+	assert.NoError(t, err)
+
+	newAccount := func(options string) *config.Account {
+		return &config.Account{ID: "account", Analytics: config.AccountAnalytics{Modules: map[string]config.AccountAnalyticsModule{
+			ModuleName: {Options: json.RawMessage(options)},
+		}}}
+	}
+	newRequest := func(publisherID string) *openrtb_ext.RequestWrapper {
+		return &openrtb_ext.RequestWrapper{
+			BidRequest: &openrtb2.BidRequest{
+				Site: &openrtb2.Site{
+					ID:        "site-test",
+					Publisher: &openrtb2.Publisher{ID: publisherID},
+				},
+				User: &openrtb2.User{Consent: agmaConsent},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		publisherID   string
+		account       *config.Account
+		expectedTrack bool
+		expectedCode  string
+	}{
+		{
+			name:          "account-code",
+			publisherID:   "other",
+			account:       newAccount(`{"code":"account-code"}`),
+			expectedTrack: true,
+			expectedCode:  "account-code",
+		},
+		{
+			name:          "account-code-precedes-host",
+			publisherID:   "track-me",
+			account:       newAccount(`{"code":"account-code","site_app_id":"site-test"}`),
+			expectedTrack: true,
+			expectedCode:  "account-code",
+		},
+		{
+			name:          "other-site",
+			publisherID:   "other",
+			account:       newAccount(`{"code":"account-code","site_app_id":"other-site"}`),
+			expectedTrack: false,
+		},
+		{
+			name:          "invalid-options",
+			publisherID:   "track-me",
+			account:       newAccount(`{"code":1}`),
+			expectedTrack: true,
+			expectedCode:  "host-code",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			shouldTrack, code := logger.shouldTrackEvent(newRequest(test.publisherID), test.account)
+			assert.Equal(t, test.expectedTrack, shouldTrack)
+			assert.Equal(t, test.expectedCode, code)
+		})
+	}
+}
+
 func TestShouldNotTrackLog(t *testing.T) {
 	testCases := []struct {
 		name   string
@@ -2,13 +2,16 @@ package build
 
 import (
 	"encoding/json"
+	"math/rand"
 
 	"github.com/benbjohnson/clock"
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/analytics/agma"
 	"github.com/prebid/prebid-server/v3/analytics/clients"
 	"github.com/prebid/prebid-server/v3/analytics/filesystem"
+	"github.com/prebid/prebid-server/v3/analytics/parquetlog"
 	"github.com/prebid/prebid-server/v3/analytics/pubstack"
+	"github.com/prebid/prebid-server/v3/analytics/webhook"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
@@ -19,7 +22,13 @@ import (
 // Modules that need to be logged to need to be initialized here
 func New(analytics *config.Analytics) analytics.Runner {
 	modules := make(enabledAnalytics, 0)
-	if len(analytics.File.Filename) > 0 {
+	if len(analytics.File.Directory) > 0 {
+		if mod, err := filesystem.NewJSONLinesLogger(analytics.File, clock.New()); err == nil {
+			modules["filelogger"] = mod
+		} else {
+			logger.Fatalf("Could not initialize FileLogger for directory %v :%v", analytics.File.Directory, err)
+		}
+	} else if len(analytics.File.Filename) > 0 {
 		if mod, err := filesystem.NewFileLogger(analytics.File.Filename); err == nil {
 			modules["filelogger"] = mod
 		} else {
@@ -50,20 +59,47 @@ func New(analytics *config.Analytics) analytics.Runner {
 			analytics.Agma,
 			clock.New())
 		if err == nil {
-			modules["agma"] = agmaModule
+			modules[agma.ModuleName] = agmaModule
 		} else {
 			logger.Errorf("Could not initialize Agma Anayltics: %v", err)
 		}
 	}
 
+	if analytics.HTTP.Enabled {
+		httpModule, err := webhook.NewModule(
+			clients.GetDefaultHttpInstance(),
+			analytics.HTTP,
+			clock.New())
+		if err == nil {
+			modules["http"] = httpModule
+		} else {
+			logger.Errorf("Could not initialize HTTP Analytics: %v", err)
+		}
+	}
+
+	if analytics.Parquet.Enabled {
+		parquetModule, err := parquetlog.NewModule(analytics.Parquet, clock.New())
+		if err == nil {
+			modules["parquet"] = parquetModule
+		} else {
+			logger.Errorf("Could not initialize Parquet Analytics: %v", err)
+		}
+	}
+
 	return modules
 }
 
+// sample returns a random number in [0, 1), to sample the events of the accounts
+var sample = rand.Float64
+
 // Collection of all the correctly configured analytics modules - implements the PBSAnalyticsModule interface
 type enabledAnalytics map[string]analytics.Module
 
 func (ea enabledAnalytics) LogAuctionObject(ao *analytics.AuctionObject, ac privacy.ActivityControl) {
 	for name, module := range ea {
+		if !isSampledForAccount(ao.Account, name, config.AnalyticsEventAuction) {
+			continue
+		}
 		if isAllowed, cloneBidderReq := evaluateActivities(ao.RequestWrapper, ac, name); isAllowed {
 			if cloneBidderReq != nil {
 				ao.RequestWrapper = cloneBidderReq
@@ -79,6 +115,9 @@ func (ea enabledAnalytics) LogAuctionObject(ao *analytics.AuctionObject, ac priv
 
 func (ea enabledAnalytics) LogVideoObject(vo *analytics.VideoObject, ac privacy.ActivityControl) {
 	for name, module := range ea {
+		if !isSampledForAccount(vo.Account, name, config.AnalyticsEventVideo) {
+			continue
+		}
 		if isAllowed, cloneBidderReq := evaluateActivities(vo.RequestWrapper, ac, name); isAllowed {
 			if cloneBidderReq != nil {
 				vo.RequestWrapper = cloneBidderReq
@@ -94,19 +133,26 @@ func (ea enabledAnalytics) LogVideoObject(vo *analytics.VideoObject, ac privacy.
 }
 
 func (ea enabledAnalytics) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
-	for _, module := range ea {
-		module.LogCookieSyncObject(cso)
+	for name, module := range ea {
+		if isSampledForAccount(cso.Account, name, config.AnalyticsEventCookieSync) {
+			module.LogCookieSyncObject(cso)
+		}
 	}
 }
 
 func (ea enabledAnalytics) LogSetUIDObject(so *analytics.SetUIDObject) {
-	for _, module := range ea {
-		module.LogSetUIDObject(so)
+	for name, module := range ea {
+		if isSampledForAccount(so.Account, name, config.AnalyticsEventSetUID) {
+			module.LogSetUIDObject(so)
+		}
 	}
 }
 
 func (ea enabledAnalytics) LogAmpObject(ao *analytics.AmpObject, ac privacy.ActivityControl) {
 	for name, module := range ea {
+		if !isSampledForAccount(ao.Account, name, config.AnalyticsEventAmp) {
+			continue
+		}
 		if isAllowed, cloneBidderReq := evaluateActivities(ao.RequestWrapper, ac, name); isAllowed {
 			if cloneBidderReq != nil {
 				ao.RequestWrapper = cloneBidderReq
@@ -122,6 +168,9 @@ func (ea enabledAnalytics) LogAmpObject(ao *analytics.AmpObject, ac privacy.Acti
 
 func (ea enabledAnalytics) LogNotificationEventObject(ne *analytics.NotificationEvent, ac privacy.ActivityControl) {
 	for name, module := range ea {
+		if !isSampledForAccount(ne.Account, name, config.AnalyticsEventNotification) {
+			continue
+		}
 		component := privacy.Component{Type: privacy.ComponentTypeAnalytics, Name: name}
 		if ac.Allow(privacy.ActivityReportAnalytics, component, privacy.ActivityRequest{}) {
 			module.LogNotificationEventObject(ne)
@@ -136,6 +185,16 @@ func (ea enabledAnalytics) Shutdown() {
 	}
 }
 
+// isSampledForAccount tells whether the event is sent to the module, according to the analytics config of the account.
+// Events without an account are sent to all the modules.
+func isSampledForAccount(account *config.Account, moduleName, eventType string) bool {
+	if account == nil {
+		return true
+	}
+	sampleRate := account.Analytics.SampleRate(moduleName, eventType)
+	return sampleRate >= 1 || (sampleRate > 0 && sample() < sampleRate)
+}
+
 func evaluateActivities(rw *openrtb_ext.RequestWrapper, ac privacy.ActivityControl, componentName string) (bool, *openrtb_ext.RequestWrapper) {
 	// returned nil request wrapper means that request wrapper was not modified by activities and doesn't have to be changed in analytics object
 	// it is needed in order to use one function for all analytics objects with RequestWrapper
@@ -145,8 +204,9 @@ func evaluateActivities(rw *openrtb_ext.RequestWrapper, ac privacy.ActivityContr
 	}
 	blockUserFPD := !ac.Allow(privacy.ActivityTransmitUserFPD, component, privacy.ActivityRequest{})
 	blockPreciseGeo := !ac.Allow(privacy.ActivityTransmitPreciseGeo, component, privacy.ActivityRequest{})
+	pseudonymize := rw != nil && ac.Pseudonymizes(component)
 
-	if !blockUserFPD && !blockPreciseGeo {
+	if !blockUserFPD && !blockPreciseGeo && !pseudonymize {
 		return true, nil
 	}
 
@@ -158,9 +218,12 @@ func evaluateActivities(rw *openrtb_ext.RequestWrapper, ac privacy.ActivityContr
 		privacy.ScrubUserFPD(cloneReq)
 	}
 	if blockPreciseGeo {
-		ipConf := privacy.IPConf{IPV6: ac.IPv6Config, IPV4: ac.IPv4Config}
+		ipConf := privacy.IPConf{IPV6: ac.IPv6Config, IPV4: ac.IPv4Config, Precision: ac.GeoPrecision(component, privacy.ActivityRequest{})}
 		privacy.ScrubGeoAndDeviceIP(cloneReq, ipConf)
 	}
+	if pseudonymize {
+		ac.Pseudonymize(cloneReq, component)
+	}
 
 	cloneReq.RebuildRequest()
 	return true, cloneReq
@@ -130,6 +130,33 @@ func TestNewPBSAnalytics_FileLogger(t *testing.T) {
 	assert.Equal(t, len(instance), 1)
 }
 
+func TestNewPBSAnalytics_JSONLinesFileLogger(t *testing.T) {
+	analytics := New(&config.Analytics{File: config.FileLogs{
+		Directory: t.TempDir(),
+		Rotation:  config.FileLogsRotation{MaxSize: "1MB", MaxAge: "1h"},
+		Buffers:   config.FileLogsBuffer{QueueSize: 10, FlushInterval: "1s"},
+	}})
+	instance := analytics.(enabledAnalytics)
+
+	assert.Contains(t, instance, "filelogger")
+	instance.Shutdown()
+}
+
+func TestNewPBSAnalytics_Parquet(t *testing.T) {
+	analytics := New(&config.Analytics{Parquet: config.ParquetAnalytics{
+		Enabled:   true,
+		Directory: t.TempDir(),
+		Rotation:  config.ParquetAnalyticsRotation{MaxRows: 10, MaxAge: "1h"},
+	}})
+	instance := analytics.(enabledAnalytics)
+
+	assert.Contains(t, instance, "parquet")
+	instance.Shutdown()
+
+	instanceWithError := New(&config.Analytics{Parquet: config.ParquetAnalytics{Enabled: true}}).(enabledAnalytics)
+	assert.Empty(t, instanceWithError)
+}
+
 func TestNewPBSAnalytics_Pubstack(t *testing.T) {
 	pbsAnalyticsWithoutError := New(&config.Analytics{
 		Pubstack: config.Pubstack{
@@ -191,6 +218,39 @@ func TestNewModuleHttp(t *testing.T) {
 	assert.Equal(t, len(instanceWithError), 0)
 }
 
+func TestNewModuleGenericHttp(t *testing.T) {
+	httpAnalyticsWithoutError := New(&config.Analytics{
+		HTTP: config.HTTPAnalytics{
+			Enabled: true,
+			Endpoint: config.HTTPAnalyticsEndpoint{
+				Url:     "http://localhost:8080",
+				Timeout: "1s",
+			},
+			Buffers: config.HTTPAnalyticsBuffer{
+				BufferSize: "100KB",
+				EventCount: 50,
+				Timeout:    "30s",
+			},
+			Retry: config.HTTPAnalyticsRetry{
+				MaxRetries: 1,
+				Backoff:    "1s",
+				MaxBackoff: "1s",
+			},
+		},
+	})
+	instanceWithoutError := httpAnalyticsWithoutError.(enabledAnalytics)
+	assert.Contains(t, instanceWithoutError, "http")
+	instanceWithoutError.Shutdown()
+
+	httpAnalyticsWithError := New(&config.Analytics{
+		HTTP: config.HTTPAnalytics{
+			Enabled: true,
+		},
+	})
+	instanceWithError := httpAnalyticsWithError.(enabledAnalytics)
+	assert.Equal(t, len(instanceWithError), 0)
+}
+
 func TestSampleModuleActivitiesAllowed(t *testing.T) {
 	var count int
 	am := initAnalytics(&count)
@@ -293,6 +353,89 @@ func TestSampleModuleActivitiesDenied(t *testing.T) {
 	}
 }
 
+func TestSampleModuleAccountAnalytics(t *testing.T) {
+	accountFor := func(analyticsCfg config.AccountAnalytics) *config.Account {
+		return &config.Account{ID: "account", Analytics: analyticsCfg}
+	}
+	ac := privacy.NewActivityControl(getActivityConfig("sampleModule", true, true, true))
+
+	tests := []struct {
+		name      string
+		account   *config.Account
+		sample    float64
+		wantCount int
+	}{
+		{
+			name:      "no-account",
+			account:   nil,
+			wantCount: 6,
+		},
+		{
+			name:      "no-analytics-config",
+			account:   accountFor(config.AccountAnalytics{}),
+			wantCount: 6,
+		},
+		{
+			name: "module-disabled",
+			account: accountFor(config.AccountAnalytics{Modules: map[string]config.AccountAnalyticsModule{
+				"sampleModule": {Enabled: ptrutil.ToPtr(false)},
+			}}),
+			wantCount: 0,
+		},
+		{
+			name:      "unlisted-modules-disabled",
+			account:   accountFor(config.AccountAnalytics{DefaultEnabled: ptrutil.ToPtr(false)}),
+			wantCount: 0,
+		},
+		{
+			name: "sampled-in",
+			account: accountFor(config.AccountAnalytics{Modules: map[string]config.AccountAnalyticsModule{
+				"sampleModule": {SampleRate: ptrutil.ToPtr(0.5)},
+			}}),
+			sample:    0.4,
+			wantCount: 6,
+		},
+		{
+			name: "sampled-out",
+			account: accountFor(config.AccountAnalytics{Modules: map[string]config.AccountAnalyticsModule{
+				"sampleModule": {SampleRate: ptrutil.ToPtr(0.5)},
+			}}),
+			sample:    0.5,
+			wantCount: 0,
+		},
+		{
+			name: "event-sample-rates",
+			account: accountFor(config.AccountAnalytics{Modules: map[string]config.AccountAnalyticsModule{
+				"sampleModule": {
+					SampleRate: ptrutil.ToPtr(0.0),
+					EventSampleRates: map[string]float64{
+						config.AnalyticsEventAuction: 1,
+						config.AnalyticsEventSetUID:  1,
+					},
+				},
+			}}),
+			wantCount: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(original func() float64) { sample = original }(sample)
+			sample = func() float64 { return tt.sample }
+
+			var count int
+			am := initAnalytics(&count)
+			am.LogAuctionObject(&analytics.AuctionObject{RequestWrapper: &openrtb_ext.RequestWrapper{}, Account: tt.account}, ac)
+			am.LogAmpObject(&analytics.AmpObject{RequestWrapper: &openrtb_ext.RequestWrapper{}, Account: tt.account}, ac)
+			am.LogVideoObject(&analytics.VideoObject{RequestWrapper: &openrtb_ext.RequestWrapper{}, Account: tt.account}, ac)
+			am.LogSetUIDObject(&analytics.SetUIDObject{Account: tt.account})
+			am.LogCookieSyncObject(&analytics.CookieSyncObject{Account: tt.account})
+			am.LogNotificationEventObject(&analytics.NotificationEvent{Account: tt.account}, ac)
+			assert.Equal(t, tt.wantCount, count)
+		})
+	}
+}
+
 func TestEvaluateActivities(t *testing.T) {
 	testCases := []struct {
 		description             string
@@ -329,6 +472,17 @@ func TestEvaluateActivities(t *testing.T) {
 			},
 			expectedAllowActivities: true,
 		},
+		{
+			// the device ifa is removed rather than sent as is, since no pseudonymization key is active
+			description: "device ifa pseudonymized",
+			givenActivityControl: privacy.NewActivityControl(&config.AccountPrivacy{
+				Pseudonymization: config.AccountPseudonymization{Enabled: true, Analytics: []string{"*"}, DeviceIFA: true},
+			}),
+			expectedRequest: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{ID: "test_request", User: &openrtb2.User{ID: "user-id"}, Device: &openrtb2.Device{IFA: "", IP: "127.0.0.1"}},
+			},
+			expectedAllowActivities: true,
+		},
 	}
 
 	for _, test := range testCases {
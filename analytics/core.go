@@ -41,6 +41,7 @@ type AmpObject struct {
 	AuctionResponse      *openrtb2.BidResponse
 	AmpTargetingValues   map[string]string
 	Origin               string
+	Account              *config.Account
 	StartTime            time.Time
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
@@ -54,6 +55,7 @@ type VideoObject struct {
 	Response       *openrtb2.BidResponse
 	VideoRequest   *openrtb_ext.BidRequestVideo
 	VideoResponse  *openrtb_ext.BidResponseVideo
+	Account        *config.Account
 	StartTime      time.Time
 	SeatNonBid     []openrtb_ext.SeatNonBid
 	RequestWrapper *openrtb_ext.RequestWrapper
@@ -66,6 +68,7 @@ type SetUIDObject struct {
 	UID     string
 	Errors  []error
 	Success bool
+	Account *config.Account
 }
 
 // Loggable object of a transaction at /cookie_sync
@@ -73,6 +76,7 @@ type CookieSyncObject struct {
 	Status       int
 	Errors       []error
 	BidderStatus []*CookieSyncBidder
+	Account      *config.Account
 }
 
 type CookieSyncBidder struct {
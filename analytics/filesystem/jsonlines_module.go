@@ -0,0 +1,145 @@
+package filesystem
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/docker/go-units"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/util/filelog"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// JSONLinesLogger writes the analytics events as versioned JSON lines, to a rotated file per event type. Events are
+// serialized by the caller and written asynchronously. They are dropped when the queue of the writer is full.
+type JSONLinesLogger struct {
+	sampleRates map[string]float64
+	sample      func() float64
+	clock       clock.Clock
+	writer      *filelog.AsyncWriter[line]
+}
+
+// NewJSONLinesLogger returns the JSON lines file logger writing to cfg.Directory
+func NewJSONLinesLogger(cfg config.FileLogs, clock clock.Clock) (analytics.Module, error) {
+	maxSize, err := units.FromHumanSize(cfg.Rotation.MaxSize)
+	if err != nil {
+		return nil, fmt.Errorf("rotation.max_size: %v", err)
+	}
+	maxAge, err := time.ParseDuration(cfg.Rotation.MaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("rotation.max_age: %v", err)
+	}
+	flushInterval, err := time.ParseDuration(cfg.Buffers.FlushInterval)
+	if err != nil {
+		return nil, fmt.Errorf("buffers.flush_interval: %v", err)
+	}
+	if maxSize <= 0 || maxAge <= 0 || flushInterval <= 0 {
+		return nil, fmt.Errorf("rotation.max_size, rotation.max_age and buffers.flush_interval must be positive")
+	}
+	if cfg.Buffers.QueueSize < 0 {
+		return nil, fmt.Errorf("buffers.queue_size must be 0 or greater. Got %d", cfg.Buffers.QueueSize)
+	}
+	sampleRates, err := newSampleRates(cfg.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(cfg.Directory, 0755); err != nil {
+		return nil, err
+	}
+
+	segments := newSegmentWriter(cfg.Directory, maxSize, maxAge, cfg.Rotation.Compress, clock)
+	l := &JSONLinesLogger{
+		sampleRates: sampleRates,
+		sample:      rand.Float64,
+		clock:       clock,
+		writer:      filelog.NewAsyncWriter[line]("[FileLogger]", "events", segments, cfg.Buffers.QueueSize, flushInterval, clock),
+	}
+
+	logger.Infof("[FileLogger] Writing JSON lines analytics to %s", cfg.Directory)
+	return l, nil
+}
+
+// newSampleRates returns the sample rates of the enabled event types
+func newSampleRates(cfg config.FileLogsEvents) (map[string]float64, error) {
+	events := map[string]config.FileLogsEvent{
+		eventTypeAuction:      cfg.Auction,
+		eventTypeAmp:          cfg.AMP,
+		eventTypeVideo:        cfg.Video,
+		eventTypeSetUID:       cfg.SetUID,
+		eventTypeCookieSync:   cfg.CookieSync,
+		eventTypeNotification: cfg.Notification,
+	}
+
+	sampleRates := make(map[string]float64, len(events))
+	for eventType, event := range events {
+		if event.SampleRate < 0 || event.SampleRate > 1 {
+			return nil, fmt.Errorf("events.%s.sample_rate must be between 0 and 1. Got %f", eventType, event.SampleRate)
+		}
+		if event.Enabled {
+			sampleRates[eventType] = event.SampleRate
+		}
+	}
+	return sampleRates, nil
+}
+
+func (l *JSONLinesLogger) shouldLog(eventType string) bool {
+	sampleRate, enabled := l.sampleRates[eventType]
+	return enabled && (sampleRate >= 1 || l.sample() < sampleRate)
+}
+
+func (l *JSONLinesLogger) log(eventType string, record interface{}) {
+	data, err := jsonutil.Marshal(record)
+	if err != nil {
+		logger.Errorf("[FileLogger] Error serializing %s event: %v", eventType, err)
+		return
+	}
+	data = append(data, '\n')
+	l.writer.Write(line{eventType: eventType, data: data})
+}
+
+func (l *JSONLinesLogger) LogAuctionObject(ao *analytics.AuctionObject) {
+	if ao != nil && l.shouldLog(eventTypeAuction) {
+		l.log(eventTypeAuction, newAuctionRecord(ao, l.clock.Now()))
+	}
+}
+
+func (l *JSONLinesLogger) LogVideoObject(vo *analytics.VideoObject) {
+	if vo != nil && l.shouldLog(eventTypeVideo) {
+		l.log(eventTypeVideo, newVideoRecord(vo, l.clock.Now()))
+	}
+}
+
+func (l *JSONLinesLogger) LogAmpObject(ao *analytics.AmpObject) {
+	if ao != nil && l.shouldLog(eventTypeAmp) {
+		l.log(eventTypeAmp, newAmpRecord(ao, l.clock.Now()))
+	}
+}
+
+func (l *JSONLinesLogger) LogSetUIDObject(so *analytics.SetUIDObject) {
+	if so != nil && l.shouldLog(eventTypeSetUID) {
+		l.log(eventTypeSetUID, newSetUIDRecord(so, l.clock.Now()))
+	}
+}
+
+func (l *JSONLinesLogger) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	if cso != nil && l.shouldLog(eventTypeCookieSync) {
+		l.log(eventTypeCookieSync, newCookieSyncRecord(cso, l.clock.Now()))
+	}
+}
+
+func (l *JSONLinesLogger) LogNotificationEventObject(ne *analytics.NotificationEvent) {
+	if ne != nil && l.shouldLog(eventTypeNotification) {
+		l.log(eventTypeNotification, newNotificationRecord(ne, l.clock.Now()))
+	}
+}
+
+// Shutdown writes the queued events and closes the files, so all of them can be ingested
+func (l *JSONLinesLogger) Shutdown() {
+	l.writer.Shutdown()
+}
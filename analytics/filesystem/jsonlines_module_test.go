@@ -0,0 +1,188 @@
+package filesystem
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func newJSONLinesConfig(dir string) config.FileLogs {
+	enabled := config.FileLogsEvent{Enabled: true, SampleRate: 1}
+	return config.FileLogs{
+		Directory: dir,
+		Rotation:  config.FileLogsRotation{MaxSize: "100MB", MaxAge: "1h", Compress: true},
+		Buffers:   config.FileLogsBuffer{QueueSize: 100, FlushInterval: "1s"},
+		Events: config.FileLogsEvents{
+			Auction:      enabled,
+			AMP:          enabled,
+			Video:        enabled,
+			SetUID:       enabled,
+			CookieSync:   enabled,
+			Notification: enabled,
+		},
+	}
+}
+
+// readEvents returns the lines of the closed segments of each event type
+func readEvents(t *testing.T, dir string) map[string][]string {
+	events := make(map[string][]string)
+	for _, name := range listFiles(t, dir) {
+		require.True(t, strings.HasSuffix(name, activeSuffix+compressedSuffix), "only compressed segments should be left, got %s", name)
+		eventType := name[:strings.Index(name, "-")]
+		data := strings.TrimSuffix(readFile(t, filepath.Join(dir, name)), "\n")
+		events[eventType] = append(events[eventType], strings.Split(data, "\n")...)
+	}
+	return events
+}
+
+func TestJSONLinesLoggerFollowsSchemas(t *testing.T) {
+	dir := t.TempDir()
+	clockMock := clock.NewMock()
+	clockMock.Set(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	l, err := NewJSONLinesLogger(newJSONLinesConfig(dir), clockMock)
+	require.NoError(t, err)
+
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		ID:     "req",
+		Imp:    []openrtb2.Imp{{ID: "imp", TagID: "tag", Banner: &openrtb2.Banner{}, Video: &openrtb2.Video{}, BidFloor: 0.5, BidFloorCur: "EUR"}},
+		Site:   &openrtb2.Site{Domain: "example.com", Page: "https://example.com/page", Publisher: &openrtb2.Publisher{ID: "pub"}},
+		Device: &openrtb2.Device{DeviceType: adcom1.DeviceMobile, OS: "ios", IP: "1.2.3.4", IFA: "ifa", Geo: &openrtb2.Geo{Country: "FRA"}},
+		User:   &openrtb2.User{ID: "user"},
+		TMax:   500,
+	}}
+	response := &openrtb2.BidResponse{
+		ID:      "req",
+		Cur:     "USD",
+		SeatBid: []openrtb2.SeatBid{{Seat: "bidder", Bid: []openrtb2.Bid{{ID: "bid", ImpID: "imp", Price: 1.5, CrID: "creative", ADomain: []string{"advertiser.com"}, W: 300, H: 250, AdM: "<div></div>"}}}},
+	}
+	seatNonBid := []openrtb_ext.SeatNonBid{{Seat: "other", NonBid: []openrtb_ext.NonBid{{ImpId: "imp", StatusCode: 301}}}}
+	l.LogAuctionObject(&analytics.AuctionObject{Status: http.StatusOK, RequestWrapper: request, Errors: []error{errors.New("warning")}, Response: response, SeatNonBid: seatNonBid})
+	l.LogAmpObject(&analytics.AmpObject{Status: http.StatusOK, RequestWrapper: request, Origin: "https://example.com", AmpTargetingValues: map[string]string{"hb_pb": "1.00"}})
+	l.LogVideoObject(&analytics.VideoObject{
+		Status:         http.StatusOK,
+		RequestWrapper: request,
+		VideoRequest:   &openrtb_ext.BidRequestVideo{StoredRequestId: "stored", PodConfig: openrtb_ext.PodConfig{Pods: []openrtb_ext.Pod{{PodId: 1, AdPodDurationSec: 30, ConfigId: "config"}}}},
+		VideoResponse:  &openrtb_ext.BidResponseVideo{AdPods: []*openrtb_ext.AdPod{{PodId: 1, Targeting: []openrtb_ext.VideoTargeting{{HbPb: "1.00", HbCacheID: "cache"}}}}},
+	})
+	l.LogSetUIDObject(&analytics.SetUIDObject{Status: http.StatusOK, Bidder: "bidder", UID: "uid", Success: true})
+	l.LogCookieSyncObject(&analytics.CookieSyncObject{Status: http.StatusOK, BidderStatus: []*analytics.CookieSyncBidder{{BidderCode: "bidder", NoCookie: true}}})
+	l.LogNotificationEventObject(&analytics.NotificationEvent{Request: &analytics.EventRequest{Type: analytics.Win, BidID: "bid", AccountID: "pub"}})
+	l.Shutdown()
+
+	events := readEvents(t, dir)
+	require.Len(t, events, len(eventTypes), "every event type should have its own file")
+	for _, eventType := range eventTypes {
+		t.Run(eventType, func(t *testing.T) {
+			schema, err := gojsonschema.NewSchema(gojsonschema.NewReferenceLoader("file://" + filepath.Join(absPath(t, "schemas/v1"), eventType+".json")))
+			require.NoError(t, err)
+			require.Len(t, events[eventType], 1)
+
+			result, err := schema.Validate(gojsonschema.NewStringLoader(events[eventType][0]))
+			require.NoError(t, err)
+			assert.True(t, result.Valid(), "%s: %v", events[eventType][0], result.Errors())
+		})
+	}
+
+	assert.JSONEq(t, `{"schema_version":1,"type":"auction","timestamp":"2024-01-02T03:04:05Z","account_id":"pub","status":200,"errors":["warning"],"start_time":"0001-01-01T00:00:00Z",`+
+		`"request":{"id":"req","imps":[{"id":"imp","tag_id":"tag","media_types":["banner","video"],"bid_floor":0.5,"bid_floor_cur":"EUR"}],"site":{"domain":"example.com","page":"https://example.com/page","publisher_id":"pub"},"device":{"type":1,"os":"ios","country":"FRA"},"tmax":500},`+
+		`"response":{"id":"req","currency":"USD","seat_bids":[{"seat":"bidder","bids":[{"id":"bid","imp_id":"imp","price":1.5,"creative_id":"creative","adomains":["advertiser.com"],"width":300,"height":250}]}]},`+
+		`"seat_non_bid":[{"seat":"other","non_bids":[{"imp_id":"imp","status_code":301}]}]}`, events[eventTypeAuction][0])
+	assert.JSONEq(t, `{"schema_version":1,"type":"setuid","timestamp":"2024-01-02T03:04:05Z","status":200,"bidder":"bidder","uid":"uid","success":true}`, events[eventTypeSetUID][0])
+	assert.JSONEq(t, `{"schema_version":1,"type":"notification","timestamp":"2024-01-02T03:04:05Z","account_id":"pub","event_type":"win","bid_id":"bid"}`, events[eventTypeNotification][0])
+}
+
+func absPath(t *testing.T, path string) string {
+	abs, err := filepath.Abs(path)
+	require.NoError(t, err)
+	return abs
+}
+
+func TestJSONLinesLoggerSampling(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newJSONLinesConfig(dir)
+	cfg.Rotation.Compress = false
+	cfg.Events.Auction.SampleRate = 0.5
+	cfg.Events.SetUID.Enabled = false
+	module, err := NewJSONLinesLogger(cfg, clock.NewMock())
+	require.NoError(t, err)
+	l := module.(*JSONLinesLogger)
+
+	samples := []float64{0.2, 0.7}
+	l.sample = func() float64 {
+		sample := samples[0]
+		samples = samples[1:]
+		return sample
+	}
+	l.LogAuctionObject(&analytics.AuctionObject{Status: http.StatusOK, Account: &config.Account{ID: "sampled"}})
+	l.LogAuctionObject(&analytics.AuctionObject{Status: http.StatusOK, Account: &config.Account{ID: "skipped"}})
+	l.LogSetUIDObject(&analytics.SetUIDObject{Status: http.StatusOK})
+	l.Shutdown()
+
+	files := listFiles(t, dir)
+	require.Len(t, files, 1)
+	data := readFile(t, filepath.Join(dir, files[0]))
+	assert.Contains(t, data, `"account_id":"sampled"`)
+	assert.NotContains(t, data, `"account_id":"skipped"`)
+}
+
+func TestJSONLinesLoggerAfterShutdown(t *testing.T) {
+	dir := t.TempDir()
+	l, err := NewJSONLinesLogger(newJSONLinesConfig(dir), clock.NewMock())
+	require.NoError(t, err)
+
+	l.Shutdown()
+	l.LogSetUIDObject(&analytics.SetUIDObject{})
+	l.Shutdown()
+
+	assert.Empty(t, listFiles(t, dir))
+}
+
+func TestNewJSONLinesLoggerConfigErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		update      func(cfg *config.FileLogs)
+	}{
+		{
+			description: "max-size",
+			update:      func(cfg *config.FileLogs) { cfg.Rotation.MaxSize = "large" },
+		},
+		{
+			description: "max-age",
+			update:      func(cfg *config.FileLogs) { cfg.Rotation.MaxAge = "0s" },
+		},
+		{
+			description: "flush-interval",
+			update:      func(cfg *config.FileLogs) { cfg.Buffers.FlushInterval = "soon" },
+		},
+		{
+			description: "queue-size",
+			update:      func(cfg *config.FileLogs) { cfg.Buffers.QueueSize = -1 },
+		},
+		{
+			description: "sample-rate",
+			update:      func(cfg *config.FileLogs) { cfg.Events.Video.SampleRate = 2 },
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := newJSONLinesConfig(t.TempDir())
+			test.update(&cfg)
+			_, err := NewJSONLinesLogger(cfg, clock.NewMock())
+			assert.Error(t, err)
+		})
+	}
+}
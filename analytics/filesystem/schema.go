@@ -0,0 +1,431 @@
+package filesystem
+
+import (
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// schemaVersion is the version of the JSON lines written by the JSONLinesLogger, documented by the JSON schemas in
+// the schemas directory. Changes which break consumers of the files, like removing or retyping fields, need a new
+// version. Adding optional fields doesn't.
+//
+// The records below are the version 1 schema. They only hold the fields which are exported, copied from the
+// OpenRTB and Prebid objects, so that changes of those objects don't change the files.
+const schemaVersion = 1
+
+// Event types of the JSONLinesLogger, which are also the base names of their files
+const (
+	eventTypeAuction      = "auction"
+	eventTypeAmp          = "amp"
+	eventTypeVideo        = "video"
+	eventTypeSetUID       = "setuid"
+	eventTypeCookieSync   = "cookie_sync"
+	eventTypeNotification = "notification"
+)
+
+var eventTypes = []string{eventTypeAuction, eventTypeAmp, eventTypeVideo, eventTypeSetUID, eventTypeCookieSync, eventTypeNotification}
+
+type eventHeader struct {
+	SchemaVersion int       `json:"schema_version"`
+	Type          string    `json:"type"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+type auctionRecord struct {
+	eventHeader
+	AccountID  string             `json:"account_id,omitempty"`
+	Status     int                `json:"status"`
+	Errors     []string           `json:"errors,omitempty"`
+	StartTime  time.Time          `json:"start_time"`
+	Request    *requestRecord     `json:"request,omitempty"`
+	Response   *responseRecord    `json:"response,omitempty"`
+	SeatNonBid []seatNonBidRecord `json:"seat_non_bid,omitempty"`
+}
+
+type ampRecord struct {
+	eventHeader
+	AccountID  string             `json:"account_id,omitempty"`
+	Status     int                `json:"status"`
+	Errors     []string           `json:"errors,omitempty"`
+	StartTime  time.Time          `json:"start_time"`
+	Origin     string             `json:"origin,omitempty"`
+	Request    *requestRecord     `json:"request,omitempty"`
+	Response   *responseRecord    `json:"response,omitempty"`
+	Targeting  map[string]string  `json:"targeting,omitempty"`
+	SeatNonBid []seatNonBidRecord `json:"seat_non_bid,omitempty"`
+}
+
+type videoRecord struct {
+	eventHeader
+	AccountID     string               `json:"account_id,omitempty"`
+	Status        int                  `json:"status"`
+	Errors        []string             `json:"errors,omitempty"`
+	StartTime     time.Time            `json:"start_time"`
+	Request       *requestRecord       `json:"request,omitempty"`
+	Response      *responseRecord      `json:"response,omitempty"`
+	VideoRequest  *videoRequestRecord  `json:"video_request,omitempty"`
+	VideoResponse *videoResponseRecord `json:"video_response,omitempty"`
+	SeatNonBid    []seatNonBidRecord   `json:"seat_non_bid,omitempty"`
+}
+
+// requestRecord is the part of the bid request which is exported, after stored request resolution. It leaves out
+// the user, and the device fields which identify the user.
+type requestRecord struct {
+	ID     string        `json:"id"`
+	Imps   []impRecord   `json:"imps,omitempty"`
+	Site   *siteRecord   `json:"site,omitempty"`
+	App    *appRecord    `json:"app,omitempty"`
+	Device *deviceRecord `json:"device,omitempty"`
+	TMax   int64         `json:"tmax,omitempty"`
+	Test   bool          `json:"test,omitempty"`
+}
+
+type impRecord struct {
+	ID          string   `json:"id"`
+	TagID       string   `json:"tag_id,omitempty"`
+	MediaTypes  []string `json:"media_types,omitempty"`
+	BidFloor    float64  `json:"bid_floor,omitempty"`
+	BidFloorCur string   `json:"bid_floor_cur,omitempty"`
+}
+
+type siteRecord struct {
+	Domain      string `json:"domain,omitempty"`
+	Page        string `json:"page,omitempty"`
+	PublisherID string `json:"publisher_id,omitempty"`
+}
+
+type appRecord struct {
+	Bundle      string `json:"bundle,omitempty"`
+	PublisherID string `json:"publisher_id,omitempty"`
+}
+
+type deviceRecord struct {
+	Type    int8   `json:"type,omitempty"`
+	OS      string `json:"os,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+type responseRecord struct {
+	ID          string          `json:"id"`
+	Currency    string          `json:"currency,omitempty"`
+	NoBidReason *int64          `json:"no_bid_reason,omitempty"`
+	SeatBids    []seatBidRecord `json:"seat_bids,omitempty"`
+}
+
+type seatBidRecord struct {
+	Seat string      `json:"seat"`
+	Bids []bidRecord `json:"bids"`
+}
+
+type bidRecord struct {
+	ID         string   `json:"id"`
+	ImpID      string   `json:"imp_id"`
+	Price      float64  `json:"price"`
+	DealID     string   `json:"deal_id,omitempty"`
+	CreativeID string   `json:"creative_id,omitempty"`
+	ADomains   []string `json:"adomains,omitempty"`
+	Width      int64    `json:"width,omitempty"`
+	Height     int64    `json:"height,omitempty"`
+}
+
+type seatNonBidRecord struct {
+	Seat    string         `json:"seat"`
+	NonBids []nonBidRecord `json:"non_bids"`
+}
+
+type nonBidRecord struct {
+	ImpID      string `json:"imp_id"`
+	StatusCode int    `json:"status_code"`
+}
+
+type videoRequestRecord struct {
+	StoredRequestID string      `json:"stored_request_id,omitempty"`
+	Pods            []podRecord `json:"pods,omitempty"`
+}
+
+type podRecord struct {
+	ID          int    `json:"id"`
+	DurationSec int    `json:"duration_sec"`
+	ConfigID    string `json:"config_id,omitempty"`
+}
+
+type videoResponseRecord struct {
+	AdPods []adPodRecord `json:"ad_pods,omitempty"`
+}
+
+type adPodRecord struct {
+	ID        int64                  `json:"id"`
+	Targeting []videoTargetingRecord `json:"targeting,omitempty"`
+	Errors    []string               `json:"errors,omitempty"`
+}
+
+// videoTargetingRecord holds the targeting key values of an ad of a pod, as returned to the video player
+type videoTargetingRecord struct {
+	PriceBucket         string `json:"hb_pb,omitempty"`
+	PriceBucketCategory string `json:"hb_pb_cat_dur,omitempty"`
+	CacheID             string `json:"hb_cache_id,omitempty"`
+	Deal                string `json:"hb_deal,omitempty"`
+}
+
+type setUIDRecord struct {
+	eventHeader
+	Status  int      `json:"status"`
+	Errors  []string `json:"errors,omitempty"`
+	Bidder  string   `json:"bidder,omitempty"`
+	UID     string   `json:"uid,omitempty"`
+	Success bool     `json:"success"`
+}
+
+type cookieSyncBidderRecord struct {
+	Bidder   string `json:"bidder"`
+	NoCookie bool   `json:"no_cookie"`
+}
+
+type cookieSyncRecord struct {
+	eventHeader
+	Status  int                      `json:"status"`
+	Errors  []string                 `json:"errors,omitempty"`
+	Bidders []cookieSyncBidderRecord `json:"bidders,omitempty"`
+}
+
+type notificationRecord struct {
+	eventHeader
+	AccountID   string `json:"account_id,omitempty"`
+	EventType   string `json:"event_type,omitempty"`
+	VastType    string `json:"vast_type,omitempty"`
+	BidID       string `json:"bid_id,omitempty"`
+	Bidder      string `json:"bidder,omitempty"`
+	Integration string `json:"integration,omitempty"`
+	// EventTimestamp is the timestamp given by the event request, in milliseconds
+	EventTimestamp int64 `json:"event_timestamp,omitempty"`
+}
+
+func newEventHeader(eventType string, now time.Time) eventHeader {
+	return eventHeader{SchemaVersion: schemaVersion, Type: eventType, Timestamp: now.UTC()}
+}
+
+func newAuctionRecord(ao *analytics.AuctionObject, now time.Time) *auctionRecord {
+	return &auctionRecord{
+		eventHeader: newEventHeader(eventTypeAuction, now),
+		AccountID:   analytics.AccountID(ao.Account, ao.RequestWrapper),
+		Status:      ao.Status,
+		Errors:      analytics.ErrorStrings(ao.Errors),
+		StartTime:   ao.StartTime.UTC(),
+		Request:     newRequestRecord(analytics.BidRequest(ao.RequestWrapper)),
+		Response:    newResponseRecord(ao.Response),
+		SeatNonBid:  newSeatNonBidRecords(ao.SeatNonBid),
+	}
+}
+
+func newAmpRecord(ao *analytics.AmpObject, now time.Time) *ampRecord {
+	return &ampRecord{
+		eventHeader: newEventHeader(eventTypeAmp, now),
+		AccountID:   analytics.AccountID(ao.Account, ao.RequestWrapper),
+		Status:      ao.Status,
+		Errors:      analytics.ErrorStrings(ao.Errors),
+		StartTime:   ao.StartTime.UTC(),
+		Origin:      ao.Origin,
+		Request:     newRequestRecord(analytics.BidRequest(ao.RequestWrapper)),
+		Response:    newResponseRecord(ao.AuctionResponse),
+		Targeting:   ao.AmpTargetingValues,
+		SeatNonBid:  newSeatNonBidRecords(ao.SeatNonBid),
+	}
+}
+
+func newVideoRecord(vo *analytics.VideoObject, now time.Time) *videoRecord {
+	return &videoRecord{
+		eventHeader:   newEventHeader(eventTypeVideo, now),
+		AccountID:     analytics.AccountID(vo.Account, vo.RequestWrapper),
+		Status:        vo.Status,
+		Errors:        analytics.ErrorStrings(vo.Errors),
+		StartTime:     vo.StartTime.UTC(),
+		Request:       newRequestRecord(analytics.BidRequest(vo.RequestWrapper)),
+		Response:      newResponseRecord(vo.Response),
+		VideoRequest:  newVideoRequestRecord(vo.VideoRequest),
+		VideoResponse: newVideoResponseRecord(vo.VideoResponse),
+		SeatNonBid:    newSeatNonBidRecords(vo.SeatNonBid),
+	}
+}
+
+func newSetUIDRecord(so *analytics.SetUIDObject, now time.Time) *setUIDRecord {
+	return &setUIDRecord{
+		eventHeader: newEventHeader(eventTypeSetUID, now),
+		Status:      so.Status,
+		Errors:      analytics.ErrorStrings(so.Errors),
+		Bidder:      so.Bidder,
+		UID:         so.UID,
+		Success:     so.Success,
+	}
+}
+
+func newCookieSyncRecord(cso *analytics.CookieSyncObject, now time.Time) *cookieSyncRecord {
+	var bidders []cookieSyncBidderRecord
+	for _, bidder := range cso.BidderStatus {
+		if bidder != nil {
+			bidders = append(bidders, cookieSyncBidderRecord{Bidder: bidder.BidderCode, NoCookie: bidder.NoCookie})
+		}
+	}
+	return &cookieSyncRecord{
+		eventHeader: newEventHeader(eventTypeCookieSync, now),
+		Status:      cso.Status,
+		Errors:      analytics.ErrorStrings(cso.Errors),
+		Bidders:     bidders,
+	}
+}
+
+func newNotificationRecord(ne *analytics.NotificationEvent, now time.Time) *notificationRecord {
+	record := &notificationRecord{eventHeader: newEventHeader(eventTypeNotification, now)}
+	if ne.Account != nil {
+		record.AccountID = ne.Account.ID
+	}
+	if ne.Request != nil {
+		if record.AccountID == "" {
+			record.AccountID = ne.Request.AccountID
+		}
+		record.EventType = string(ne.Request.Type)
+		record.VastType = string(ne.Request.VType)
+		record.BidID = ne.Request.BidID
+		record.Bidder = ne.Request.Bidder
+		record.Integration = ne.Request.Integration
+		record.EventTimestamp = ne.Request.Timestamp
+	}
+	return record
+}
+
+func newRequestRecord(request *openrtb2.BidRequest) *requestRecord {
+	if request == nil {
+		return nil
+	}
+
+	record := &requestRecord{
+		ID:   request.ID,
+		TMax: request.TMax,
+		Test: request.Test == 1,
+	}
+	for _, imp := range request.Imp {
+		record.Imps = append(record.Imps, impRecord{
+			ID:          imp.ID,
+			TagID:       imp.TagID,
+			MediaTypes:  impMediaTypes(&imp),
+			BidFloor:    imp.BidFloor,
+			BidFloorCur: imp.BidFloorCur,
+		})
+	}
+	if request.Site != nil {
+		record.Site = &siteRecord{Domain: request.Site.Domain, Page: request.Site.Page}
+		if request.Site.Publisher != nil {
+			record.Site.PublisherID = request.Site.Publisher.ID
+		}
+	}
+	if request.App != nil {
+		record.App = &appRecord{Bundle: request.App.Bundle}
+		if request.App.Publisher != nil {
+			record.App.PublisherID = request.App.Publisher.ID
+		}
+	}
+	if request.Device != nil {
+		record.Device = &deviceRecord{Type: int8(request.Device.DeviceType), OS: request.Device.OS}
+		if request.Device.Geo != nil {
+			record.Device.Country = request.Device.Geo.Country
+		}
+	}
+	return record
+}
+
+// impMediaTypes returns the media types of the imp, in the order of openrtb_ext.BidType
+func impMediaTypes(imp *openrtb2.Imp) []string {
+	var mediaTypes []string
+	if imp.Banner != nil {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeBanner))
+	}
+	if imp.Video != nil {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeVideo))
+	}
+	if imp.Audio != nil {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeAudio))
+	}
+	if imp.Native != nil {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeNative))
+	}
+	return mediaTypes
+}
+
+func newResponseRecord(response *openrtb2.BidResponse) *responseRecord {
+	if response == nil {
+		return nil
+	}
+
+	record := &responseRecord{ID: response.ID, Currency: response.Cur}
+	if response.NBR != nil {
+		noBidReason := int64(*response.NBR)
+		record.NoBidReason = &noBidReason
+	}
+	for _, seatBid := range response.SeatBid {
+		seatBidRecord := seatBidRecord{Seat: seatBid.Seat, Bids: make([]bidRecord, 0, len(seatBid.Bid))}
+		for _, bid := range seatBid.Bid {
+			seatBidRecord.Bids = append(seatBidRecord.Bids, bidRecord{
+				ID:         bid.ID,
+				ImpID:      bid.ImpID,
+				Price:      bid.Price,
+				DealID:     bid.DealID,
+				CreativeID: bid.CrID,
+				ADomains:   bid.ADomain,
+				Width:      bid.W,
+				Height:     bid.H,
+			})
+		}
+		record.SeatBids = append(record.SeatBids, seatBidRecord)
+	}
+	return record
+}
+
+func newSeatNonBidRecords(seatNonBids []openrtb_ext.SeatNonBid) []seatNonBidRecord {
+	var records []seatNonBidRecord
+	for _, seatNonBid := range seatNonBids {
+		record := seatNonBidRecord{Seat: seatNonBid.Seat, NonBids: make([]nonBidRecord, 0, len(seatNonBid.NonBid))}
+		for _, nonBid := range seatNonBid.NonBid {
+			record.NonBids = append(record.NonBids, nonBidRecord{ImpID: nonBid.ImpId, StatusCode: nonBid.StatusCode})
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func newVideoRequestRecord(videoRequest *openrtb_ext.BidRequestVideo) *videoRequestRecord {
+	if videoRequest == nil {
+		return nil
+	}
+
+	record := &videoRequestRecord{StoredRequestID: videoRequest.StoredRequestId}
+	for _, pod := range videoRequest.PodConfig.Pods {
+		record.Pods = append(record.Pods, podRecord{ID: pod.PodId, DurationSec: pod.AdPodDurationSec, ConfigID: pod.ConfigId})
+	}
+	return record
+}
+
+func newVideoResponseRecord(videoResponse *openrtb_ext.BidResponseVideo) *videoResponseRecord {
+	if videoResponse == nil {
+		return nil
+	}
+
+	record := &videoResponseRecord{}
+	for _, adPod := range videoResponse.AdPods {
+		if adPod == nil {
+			continue
+		}
+		adPodRecord := adPodRecord{ID: adPod.PodId, Errors: adPod.Errors}
+		for _, targeting := range adPod.Targeting {
+			adPodRecord.Targeting = append(adPodRecord.Targeting, videoTargetingRecord{
+				PriceBucket:         targeting.HbPb,
+				PriceBucketCategory: targeting.HbPbCatDur,
+				CacheID:             targeting.HbCacheID,
+				Deal:                targeting.HbDeal,
+			})
+		}
+		record.AdPods = append(record.AdPods, adPodRecord)
+	}
+	return record
+}
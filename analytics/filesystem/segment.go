@@ -0,0 +1,81 @@
+package filesystem
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/util/filelog"
+)
+
+const activeSuffix = ".jsonl"
+
+type line struct {
+	eventType string
+	data      []byte
+}
+
+// segmentWriter writes the JSON lines of each event type to "<type>.jsonl" in its directory. Once the file reaches the
+// max size or age it is closed and renamed to "<type>-<start time>.jsonl", then gzipped if compression is enabled.
+// It is the filelog.Sink of the JSONLinesLogger.
+type segmentWriter struct {
+	dir      string
+	maxSize  int64
+	maxAge   time.Duration
+	compress bool
+	clock    clock.Clock
+
+	segments map[string]*filelog.RotatingFile
+}
+
+func newSegmentWriter(dir string, maxSize int64, maxAge time.Duration, compress bool, clock clock.Clock) *segmentWriter {
+	return &segmentWriter{
+		dir:      dir,
+		maxSize:  maxSize,
+		maxAge:   maxAge,
+		compress: compress,
+		clock:    clock,
+		segments: make(map[string]*filelog.RotatingFile),
+	}
+}
+
+func (w *segmentWriter) Write(event line) {
+	seg, ok := w.segments[event.eventType]
+	if !ok {
+		var err error
+		seg, err = filelog.NewRotatingFile(filelog.RotatingFileConfig{
+			Path:     filepath.Join(w.dir, event.eventType+activeSuffix),
+			MaxSize:  w.maxSize,
+			MaxAge:   w.maxAge,
+			Compress: w.compress,
+		}, w.clock)
+		if err != nil {
+			logger.Errorf("[FileLogger] Failed to write %s event: %v", event.eventType, err)
+			return
+		}
+		w.segments[event.eventType] = seg
+	}
+
+	if err := seg.Write(event.data); err != nil {
+		logger.Errorf("[FileLogger] Failed to write %s event: %v", event.eventType, err)
+	}
+}
+
+// Tick writes the buffered lines to the files, and closes the segments which reached their max age
+func (w *segmentWriter) Tick() {
+	for eventType, seg := range w.segments {
+		if err := seg.Flush(); err != nil {
+			logger.Errorf("[FileLogger] Failed to write %s events: %v", eventType, err)
+		}
+	}
+}
+
+// Close closes all the segments and waits for their compression
+func (w *segmentWriter) Close() {
+	for eventType, seg := range w.segments {
+		if err := seg.Close(); err != nil {
+			logger.Errorf("[FileLogger] Failed to close the %s segment: %v", eventType, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package filesystem
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compressedSuffix is the suffix filelog gives to the compressed segments
+const compressedSuffix = ".gz"
+
+func listFiles(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var reader io.Reader = file
+	if filepath.Ext(path) == compressedSuffix {
+		gz, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		reader = gz
+	}
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestSegmentWriterRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	clockMock := clock.NewMock()
+	clockMock.Set(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	writer := newSegmentWriter(dir, 10, time.Hour, false, clockMock)
+
+	writer.Write(line{"auction", []byte("1234\n")})
+	writer.Write(line{"auction", []byte("5678\n")})
+	clockMock.Add(time.Second)
+	writer.Write(line{"auction", []byte("9\n")})
+	writer.Tick()
+
+	assert.Equal(t, []string{"auction-20240102T030405.000000000Z.jsonl", "auction.jsonl"}, listFiles(t, dir))
+	assert.Equal(t, "1234\n5678\n", readFile(t, filepath.Join(dir, "auction-20240102T030405.000000000Z.jsonl")))
+	assert.Equal(t, "9\n", readFile(t, filepath.Join(dir, "auction.jsonl")))
+}
+
+func TestSegmentWriterRotatesByAge(t *testing.T) {
+	dir := t.TempDir()
+	clockMock := clock.NewMock()
+	writer := newSegmentWriter(dir, 1000, time.Minute, false, clockMock)
+
+	writer.Write(line{"amp", []byte("1\n")})
+	writer.Tick()
+	assert.Equal(t, []string{"amp.jsonl"}, listFiles(t, dir), "the segment should be kept until its max age")
+
+	clockMock.Add(time.Minute)
+	writer.Tick()
+	assert.Equal(t, []string{"amp-19700101T000000.000000000Z.jsonl"}, listFiles(t, dir))
+}
+
+func TestSegmentWriterCompresses(t *testing.T) {
+	dir := t.TempDir()
+	writer := newSegmentWriter(dir, 1000, time.Hour, true, clock.NewMock())
+
+	writer.Write(line{"video", []byte("1\n")})
+	writer.Write(line{"setuid", []byte("2\n")})
+	writer.Close()
+
+	assert.Equal(t, []string{"setuid-19700101T000000.000000000Z.jsonl.gz", "video-19700101T000000.000000000Z.jsonl.gz"}, listFiles(t, dir))
+	assert.Equal(t, "1\n", readFile(t, filepath.Join(dir, "video-19700101T000000.000000000Z.jsonl.gz")))
+}
+
+func TestSegmentWriterClosesLeftoverFile(t *testing.T) {
+	dir := t.TempDir()
+	leftover := filepath.Join(dir, "auction.jsonl")
+	require.NoError(t, os.WriteFile(leftover, []byte("old\n"), 0644))
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(leftover, modTime, modTime))
+
+	writer := newSegmentWriter(dir, 1000, time.Hour, false, clock.NewMock())
+	writer.Write(line{"auction", []byte("new\n")})
+	writer.Close()
+
+	assert.Equal(t, []string{"auction-19700101T000000.000000000Z.jsonl", "auction-20240101T000000.000000000Z.jsonl"}, listFiles(t, dir))
+	assert.Equal(t, "old\n", readFile(t, filepath.Join(dir, "auction-20240101T000000.000000000Z.jsonl")))
+}
@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// AccountID returns the ID of the account, falling back to the publisher ID of the request
+func AccountID(account *config.Account, requestWrapper *openrtb_ext.RequestWrapper) string {
+	if account != nil && account.ID != "" {
+		return account.ID
+	}
+	if requestWrapper == nil || requestWrapper.BidRequest == nil {
+		return ""
+	}
+	switch {
+	case requestWrapper.Site != nil && requestWrapper.Site.Publisher != nil:
+		return requestWrapper.Site.Publisher.ID
+	case requestWrapper.App != nil && requestWrapper.App.Publisher != nil:
+		return requestWrapper.App.Publisher.ID
+	case requestWrapper.DOOH != nil && requestWrapper.DOOH.Publisher != nil:
+		return requestWrapper.DOOH.Publisher.ID
+	}
+	return ""
+}
+
+// BidRequest returns the bid request of the wrapper, if any
+func BidRequest(requestWrapper *openrtb_ext.RequestWrapper) *openrtb2.BidRequest {
+	if requestWrapper == nil {
+		return nil
+	}
+	return requestWrapper.BidRequest
+}
+
+// ErrorStrings returns the messages of the errors, or nil if there are none
+func ErrorStrings(errs []error) []string {
+	if len(errs) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
@@ -0,0 +1,57 @@
+package analytics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountID(t *testing.T) {
+	testCases := []struct {
+		description string
+		account     *config.Account
+		request     *openrtb2.BidRequest
+		expected    string
+	}{
+		{
+			description: "account",
+			account:     &config.Account{ID: "account"},
+			request:     &openrtb2.BidRequest{Site: &openrtb2.Site{Publisher: &openrtb2.Publisher{ID: "pub"}}},
+			expected:    "account",
+		},
+		{
+			description: "site-publisher",
+			request:     &openrtb2.BidRequest{Site: &openrtb2.Site{Publisher: &openrtb2.Publisher{ID: "pub"}}},
+			expected:    "pub",
+		},
+		{
+			description: "app-publisher",
+			request:     &openrtb2.BidRequest{App: &openrtb2.App{Publisher: &openrtb2.Publisher{ID: "pub"}}},
+			expected:    "pub",
+		},
+		{
+			description: "dooh-publisher",
+			request:     &openrtb2.BidRequest{DOOH: &openrtb2.DOOH{Publisher: &openrtb2.Publisher{ID: "pub"}}},
+			expected:    "pub",
+		},
+		{
+			description: "none",
+			request:     &openrtb2.BidRequest{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, AccountID(test.account, &openrtb_ext.RequestWrapper{BidRequest: test.request}))
+		})
+	}
+}
+
+func TestErrorStrings(t *testing.T) {
+	assert.Nil(t, ErrorStrings(nil))
+	assert.Equal(t, []string{"first", "second"}, ErrorStrings([]error{errors.New("first"), errors.New("second")}))
+}
@@ -0,0 +1,185 @@
+// Package parquetlog implements the "parquet" analytics module, which flattens the auctions into a row per imp, bidder
+// and bid, written to rotated Parquet files for warehouses to load.
+package parquetlog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/parquet-go/parquet-go"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/util/filelog"
+)
+
+const (
+	fileSuffix = ".parquet"
+	// partialSuffix marks the file being written, which isn't a valid Parquet file until closed
+	partialSuffix = ".tmp"
+	// fileTimeFormat names the files after the time they were started, so they sort chronologically
+	fileTimeFormat = "20060102T150405.000000000Z"
+	rowGroupSize   = 64 * 1024
+	// maxRotationCheckInterval bounds how late a file is closed after reaching its max age
+	maxRotationCheckInterval = time.Minute
+)
+
+type ParquetLogger struct {
+	clock  clock.Clock
+	writer *filelog.AsyncWriter[[]bidRow]
+}
+
+// parquetFiles writes the rows of the auctions to the current Parquet file, and closes it once it reaches its max rows
+// or age. It is the filelog.Sink of the ParquetLogger.
+type parquetFiles struct {
+	dir     string
+	maxRows int64
+	maxAge  time.Duration
+	clock   clock.Clock
+
+	file    *os.File
+	writer  *parquet.GenericWriter[bidRow]
+	rows    int64
+	started time.Time
+}
+
+func NewModule(cfg config.ParquetAnalytics, clock clock.Clock) (analytics.Module, error) {
+	if cfg.Directory == "" {
+		return nil, errors.New("directory is required")
+	}
+	if cfg.Rotation.MaxRows <= 0 {
+		return nil, fmt.Errorf("rotation.max_rows must be positive. Got %d", cfg.Rotation.MaxRows)
+	}
+	maxAge, err := time.ParseDuration(cfg.Rotation.MaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("rotation.max_age: %v", err)
+	}
+	if maxAge <= 0 {
+		return nil, fmt.Errorf("rotation.max_age must be positive. Got %s", cfg.Rotation.MaxAge)
+	}
+	if cfg.QueueSize < 0 {
+		return nil, fmt.Errorf("queue_size must be 0 or greater. Got %d", cfg.QueueSize)
+	}
+	if err := os.MkdirAll(cfg.Directory, 0755); err != nil {
+		return nil, err
+	}
+
+	files := &parquetFiles{
+		dir:     cfg.Directory,
+		maxRows: int64(cfg.Rotation.MaxRows),
+		maxAge:  maxAge,
+		clock:   clock,
+	}
+	l := &ParquetLogger{
+		clock:  clock,
+		writer: filelog.NewAsyncWriter[[]bidRow]("[ParquetAnalytics]", "auctions", files, cfg.QueueSize, min(maxAge, maxRotationCheckInterval), clock),
+	}
+
+	logger.Infof("[ParquetAnalytics] Writing auction rows to %s", cfg.Directory)
+	return l, nil
+}
+
+func (f *parquetFiles) Write(rows []bidRow) {
+	if f.writer == nil {
+		if err := f.openFile(); err != nil {
+			logger.Errorf("[ParquetAnalytics] Failed to create a file, dropping %d rows: %v", len(rows), err)
+			return
+		}
+	}
+
+	n, err := f.writer.Write(rows)
+	f.rows += int64(n)
+	if err != nil {
+		logger.Errorf("[ParquetAnalytics] Failed to write rows: %v", err)
+	}
+	if f.rows >= f.maxRows {
+		f.closeFile()
+	}
+}
+
+// Tick closes the file once it reaches its max age
+func (f *parquetFiles) Tick() {
+	if f.writer != nil && f.clock.Since(f.started) >= f.maxAge {
+		f.closeFile()
+	}
+}
+
+// Close closes the current file, if any
+func (f *parquetFiles) Close() {
+	f.closeFile()
+}
+
+func (f *parquetFiles) openFile() error {
+	f.started = f.clock.Now()
+	path := f.path(f.started) + partialSuffix
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	f.file = file
+	f.writer = parquet.NewGenericWriter[bidRow](file, parquet.Compression(&parquet.Snappy), parquet.MaxRowsPerRowGroup(rowGroupSize))
+	f.rows = 0
+	return nil
+}
+
+// closeFile writes the footer of the Parquet file, and gives it its final name
+func (f *parquetFiles) closeFile() {
+	if f.writer == nil {
+		return
+	}
+	partialPath := f.file.Name()
+	err := f.writer.Close()
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	f.writer = nil
+	f.file = nil
+
+	if err != nil {
+		logger.Errorf("[ParquetAnalytics] Failed to close %s: %v", partialPath, err)
+		return
+	}
+	if err := os.Rename(partialPath, strings.TrimSuffix(partialPath, partialSuffix)); err != nil {
+		logger.Errorf("[ParquetAnalytics] Failed to rename %s: %v", partialPath, err)
+	}
+}
+
+// path names the file after its start time, adding a counter in the unlikely case the name is taken
+func (f *parquetFiles) path(started time.Time) string {
+	base := filepath.Join(f.dir, "bids-"+started.UTC().Format(fileTimeFormat))
+	path := base + fileSuffix
+	for i := 1; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = fmt.Sprintf("%s-%d%s", base, i, fileSuffix)
+	}
+}
+
+func (l *ParquetLogger) LogAuctionObject(ao *analytics.AuctionObject) {
+	if ao == nil {
+		return
+	}
+	// The auction is flattened right away, since the object may change once logged
+	rows := flatten(ao, l.clock.Now())
+	if len(rows) == 0 {
+		return
+	}
+	l.writer.Write(rows)
+}
+
+func (l *ParquetLogger) LogVideoObject(vo *analytics.VideoObject)                   {}
+func (l *ParquetLogger) LogCookieSyncObject(cso *analytics.CookieSyncObject)        {}
+func (l *ParquetLogger) LogSetUIDObject(so *analytics.SetUIDObject)                 {}
+func (l *ParquetLogger) LogAmpObject(ao *analytics.AmpObject)                       {}
+func (l *ParquetLogger) LogNotificationEventObject(ne *analytics.NotificationEvent) {}
+
+// Shutdown writes the queued auctions and closes the current file
+func (l *ParquetLogger) Shutdown() {
+	l.writer.Shutdown()
+}
@@ -0,0 +1,110 @@
+package parquetlog
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/parquet-go/parquet-go"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listFiles(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newTestModule(t *testing.T, dir string, maxRows int, clock clock.Clock) *ParquetLogger {
+	module, err := NewModule(config.ParquetAnalytics{
+		Enabled:   true,
+		Directory: dir,
+		Rotation:  config.ParquetAnalyticsRotation{MaxRows: maxRows, MaxAge: "1h"},
+		QueueSize: 10,
+	}, clock)
+	require.NoError(t, err)
+	return module.(*ParquetLogger)
+}
+
+func TestParquetLoggerWritesRows(t *testing.T) {
+	dir := t.TempDir()
+	clockMock := clock.NewMock()
+	clockMock.Set(testTime)
+	l := newTestModule(t, dir, 1000, clockMock)
+
+	l.LogAuctionObject(newTestAuction())
+	l.LogAuctionObject(&analytics.AuctionObject{})
+	l.Shutdown()
+
+	files := listFiles(t, dir)
+	require.Equal(t, []string{"bids-20240102T030405.000000000Z.parquet"}, files)
+	rows, err := parquet.ReadFile[bidRow](filepath.Join(dir, files[0]))
+	require.NoError(t, err)
+	assert.Equal(t, flatten(newTestAuction(), testTime), rows)
+}
+
+func TestParquetLoggerRotatesByRows(t *testing.T) {
+	dir := t.TempDir()
+	l := newTestModule(t, dir, 4, clock.NewMock())
+
+	l.LogAuctionObject(newTestAuction())
+	l.LogAuctionObject(newTestAuction())
+	l.Shutdown()
+
+	assert.Equal(t, []string{"bids-19700101T000000.000000000Z-1.parquet", "bids-19700101T000000.000000000Z.parquet"}, listFiles(t, dir))
+}
+
+func TestParquetLoggerRotatesByAge(t *testing.T) {
+	dir := t.TempDir()
+	clockMock := clock.NewMock()
+	l := newTestModule(t, dir, 1000, clockMock)
+	defer l.Shutdown()
+
+	l.LogAuctionObject(newTestAuction())
+	assert.Eventually(t, func() bool {
+		clockMock.Add(time.Minute)
+		return len(listFiles(t, dir)) == 1 && filepath.Ext(listFiles(t, dir)[0]) == fileSuffix
+	}, time.Second, 10*time.Millisecond, "the file should be closed once it reaches its max age")
+}
+
+func TestNewModuleConfigErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		cfg         config.ParquetAnalytics
+	}{
+		{
+			description: "directory",
+			cfg:         config.ParquetAnalytics{Rotation: config.ParquetAnalyticsRotation{MaxRows: 1, MaxAge: "1h"}},
+		},
+		{
+			description: "max-rows",
+			cfg:         config.ParquetAnalytics{Directory: t.TempDir(), Rotation: config.ParquetAnalyticsRotation{MaxRows: 0, MaxAge: "1h"}},
+		},
+		{
+			description: "max-age",
+			cfg:         config.ParquetAnalytics{Directory: t.TempDir(), Rotation: config.ParquetAnalyticsRotation{MaxRows: 1, MaxAge: "soon"}},
+		},
+		{
+			description: "queue-size",
+			cfg:         config.ParquetAnalytics{Directory: t.TempDir(), Rotation: config.ParquetAnalyticsRotation{MaxRows: 1, MaxAge: "1h"}, QueueSize: -1},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := NewModule(test.cfg, clock.NewMock())
+			assert.Error(t, err)
+		})
+	}
+}
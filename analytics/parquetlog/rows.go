@@ -0,0 +1,234 @@
+package parquetlog
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+)
+
+// Status of the bidder for the imp
+const (
+	statusBid    = "bid"
+	statusNonBid = "non_bid"
+	statusNoBid  = "no_bid"
+)
+
+// defaultCurrency is the currency of responses without one, as defined by OpenRTB
+const defaultCurrency = "USD"
+
+// bidRow is the Parquet schema of the exported rows. There is a row per bid, per non bid reported in the seat non
+// bids, and per imp and bidder which neither bid nor reported a non bid.
+type bidRow struct {
+	Timestamp int64  `parquet:"timestamp,timestamp(millisecond)"`
+	AuctionID string `parquet:"auction_id"`
+	AccountID string `parquet:"account_id"`
+	ImpID     string `parquet:"imp_id"`
+	// Bidder is the adapter which was called, and Seat the seat it bid for, which differ for alternate bidder codes
+	Bidder string `parquet:"bidder"`
+	Seat   string `parquet:"seat"`
+	Status string `parquet:"status"`
+	BidID  string `parquet:"bid_id,optional"`
+	// Price is the price returned by the bidder in OriginalCurrency, and AdjustedPrice the price after bid adjustments
+	// and conversion to Currency
+	Price            *float64 `parquet:"price,optional"`
+	AdjustedPrice    *float64 `parquet:"adjusted_price,optional"`
+	Currency         string   `parquet:"currency,optional"`
+	OriginalCurrency string   `parquet:"original_currency,optional"`
+	DealID           string   `parquet:"deal_id,optional"`
+	MediaType        string   `parquet:"media_type,optional"`
+	Width            *int64   `parquet:"width,optional"`
+	Height           *int64   `parquet:"height,optional"`
+	NonBidReason     *int32   `parquet:"non_bid_reason,optional"`
+	LatencyMs        *int32   `parquet:"latency_ms,optional"`
+	GDPR             bool     `parquet:"gdpr"`
+	GDPRConsent      bool     `parquet:"gdpr_consent"`
+	COPPA            bool     `parquet:"coppa"`
+	USPrivacy        bool     `parquet:"us_privacy"`
+	GPP              bool     `parquet:"gpp"`
+	LMT              bool     `parquet:"lmt"`
+}
+
+// bidExt is the part of seatbid[].bid[].ext set by the exchange which is exported
+type bidExt struct {
+	OriginalBidCPM *float64                  `json:"origbidcpm"`
+	OriginalBidCur string                    `json:"origbidcur"`
+	Prebid         *openrtb_ext.ExtBidPrebid `json:"prebid"`
+}
+
+var markupTypeMediaTypes = map[openrtb2.MarkupType]openrtb_ext.BidType{
+	openrtb2.MarkupBanner: openrtb_ext.BidTypeBanner,
+	openrtb2.MarkupVideo:  openrtb_ext.BidTypeVideo,
+	openrtb2.MarkupAudio:  openrtb_ext.BidTypeAudio,
+	openrtb2.MarkupNative: openrtb_ext.BidTypeNative,
+}
+
+// flatten returns the rows of the auction, sorted by imp and bidder
+func flatten(ao *analytics.AuctionObject, now time.Time) []bidRow {
+	if ao.RequestWrapper == nil || ao.RequestWrapper.BidRequest == nil {
+		return nil
+	}
+
+	base := bidRow{
+		Timestamp: now.UnixMilli(),
+		AuctionID: ao.RequestWrapper.ID,
+	}
+	if ao.Account != nil {
+		base.AccountID = ao.Account.ID
+	}
+	setPrivacyFlags(&base, ao.RequestWrapper.BidRequest)
+
+	var latencies map[openrtb_ext.BidderName]int
+	currency := defaultCurrency
+	var rows []bidRow
+	if ao.Response != nil {
+		if ao.Response.Cur != "" {
+			currency = ao.Response.Cur
+		}
+		var responseExt openrtb_ext.ExtBidResponse
+		if len(ao.Response.Ext) > 0 && jsonutil.Unmarshal(ao.Response.Ext, &responseExt) == nil {
+			latencies = responseExt.ResponseTimeMillis
+		}
+		for _, seatBid := range ao.Response.SeatBid {
+			for i := range seatBid.Bid {
+				rows = append(rows, bidRowOf(base, seatBid.Seat, &seatBid.Bid[i], currency))
+			}
+		}
+	}
+
+	for _, seatNonBid := range ao.SeatNonBid {
+		for i := range seatNonBid.NonBid {
+			rows = append(rows, nonBidRowOf(base, seatNonBid.Seat, &seatNonBid.NonBid[i], currency))
+		}
+	}
+
+	rows = append(rows, noBidRows(base, ao.RequestWrapper, rows)...)
+
+	for i := range rows {
+		if latency, ok := latencies[openrtb_ext.BidderName(rows[i].Bidder)]; ok {
+			rows[i].LatencyMs = ptrutil.ToPtr(int32(latency))
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].ImpID != rows[j].ImpID {
+			return rows[i].ImpID < rows[j].ImpID
+		}
+		return rows[i].Bidder < rows[j].Bidder
+	})
+	return rows
+}
+
+func bidRowOf(base bidRow, seat string, bid *openrtb2.Bid, currency string) bidRow {
+	row := base
+	row.ImpID = bid.ImpID
+	row.Bidder = seat
+	row.Seat = seat
+	row.Status = statusBid
+	row.BidID = bid.ID
+	row.AdjustedPrice = ptrutil.ToPtr(bid.Price)
+	row.Currency = currency
+	row.DealID = bid.DealID
+	row.MediaType = string(markupTypeMediaTypes[bid.MType])
+	row.Width = nonZero(bid.W)
+	row.Height = nonZero(bid.H)
+
+	var ext bidExt
+	if len(bid.Ext) > 0 && jsonutil.Unmarshal(bid.Ext, &ext) == nil {
+		row.Price = ext.OriginalBidCPM
+		row.OriginalCurrency = ext.OriginalBidCur
+		if ext.Prebid != nil {
+			if ext.Prebid.Type != "" {
+				row.MediaType = string(ext.Prebid.Type)
+			}
+			if ext.Prebid.BidId != "" {
+				row.BidID = ext.Prebid.BidId
+			}
+			if ext.Prebid.Meta != nil && ext.Prebid.Meta.AdapterCode != "" {
+				row.Bidder = ext.Prebid.Meta.AdapterCode
+			}
+		}
+	}
+	return row
+}
+
+func nonBidRowOf(base bidRow, seat string, nonBid *openrtb_ext.NonBid, currency string) bidRow {
+	row := base
+	row.ImpID = nonBid.ImpId
+	row.Bidder = seat
+	row.Seat = seat
+	row.Status = statusNonBid
+	row.NonBidReason = ptrutil.ToPtr(int32(nonBid.StatusCode))
+
+	if nonBid.Ext != nil {
+		bid := nonBid.Ext.Prebid.Bid
+		if bid.OriginalBidCPM != 0 {
+			row.Price = ptrutil.ToPtr(bid.OriginalBidCPM)
+			row.OriginalCurrency = bid.OriginalBidCur
+		}
+		if bid.Price != 0 {
+			row.AdjustedPrice = ptrutil.ToPtr(bid.Price)
+			row.Currency = currency
+		}
+		row.DealID = bid.DealID
+		row.MediaType = string(markupTypeMediaTypes[bid.MType])
+		row.Width = nonZero(bid.W)
+		row.Height = nonZero(bid.H)
+	}
+	return row
+}
+
+// noBidRows returns the rows of the bidders requested for an imp, which have no row yet
+func noBidRows(base bidRow, requestWrapper *openrtb_ext.RequestWrapper, rows []bidRow) []bidRow {
+	type impBidder struct{ impID, bidder string }
+	seen := make(map[impBidder]struct{}, len(rows))
+	for _, row := range rows {
+		seen[impBidder{row.ImpID, row.Bidder}] = struct{}{}
+	}
+
+	var noBids []bidRow
+	for _, imp := range requestWrapper.GetImp() {
+		impExt, err := imp.GetImpExt()
+		if err != nil || impExt.GetPrebid() == nil {
+			continue
+		}
+		for bidder := range impExt.GetPrebid().Bidder {
+			if _, ok := seen[impBidder{imp.ID, bidder}]; ok {
+				continue
+			}
+			row := base
+			row.ImpID = imp.ID
+			row.Bidder = bidder
+			row.Seat = bidder
+			row.Status = statusNoBid
+			noBids = append(noBids, row)
+		}
+	}
+	return noBids
+}
+
+func setPrivacyFlags(row *bidRow, request *openrtb2.BidRequest) {
+	if request.Regs != nil {
+		row.GDPR = request.Regs.GDPR != nil && *request.Regs.GDPR == 1
+		row.COPPA = request.Regs.COPPA == 1
+		row.USPrivacy = request.Regs.USPrivacy != ""
+		row.GPP = request.Regs.GPP != ""
+	}
+	if request.User != nil {
+		row.GDPRConsent = request.User.Consent != ""
+	}
+	if request.Device != nil {
+		row.LMT = request.Device.Lmt != nil && *request.Device.Lmt == 1
+	}
+}
+
+func nonZero(value int64) *int64 {
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
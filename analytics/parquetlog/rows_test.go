@@ -0,0 +1,109 @@
+package parquetlog
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func newTestAuction() *analytics.AuctionObject {
+	return &analytics.AuctionObject{
+		Account: &config.Account{ID: "account"},
+		RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			ID: "auction",
+			Imp: []openrtb2.Imp{
+				{ID: "imp1", Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus":{},"rubicon":{},"openx":{}}}}`)},
+				{ID: "imp2", Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus":{}}}}`)},
+			},
+			Regs:   &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1), GPP: "gpp"},
+			User:   &openrtb2.User{Consent: "consent"},
+			Device: &openrtb2.Device{Lmt: ptrutil.ToPtr[int8](1)},
+		}},
+		Response: &openrtb2.BidResponse{
+			Cur: "EUR",
+			SeatBid: []openrtb2.SeatBid{
+				{
+					Seat: "alternate",
+					Bid: []openrtb2.Bid{{
+						ID: "bid1", ImpID: "imp1", Price: 0.9, DealID: "deal", W: 300, H: 250,
+						Ext: json.RawMessage(`{"origbidcpm":1,"origbidcur":"USD","prebid":{"type":"banner","bidid":"generated","meta":{"adaptercode":"appnexus"}}}`),
+					}},
+				},
+				{
+					Seat: "appnexus",
+					Bid:  []openrtb2.Bid{{ID: "bid2", ImpID: "imp2", Price: 2, MType: openrtb2.MarkupVideo}},
+				},
+			},
+			Ext: json.RawMessage(`{"responsetimemillis":{"appnexus":42,"rubicon":100}}`),
+		},
+		SeatNonBid: []openrtb_ext.SeatNonBid{{
+			Seat: "rubicon",
+			NonBid: []openrtb_ext.NonBid{{
+				ImpId:      "imp1",
+				StatusCode: 301,
+				Ext:        &openrtb_ext.NonBidExt{Prebid: openrtb_ext.ExtResponseNonBidPrebid{Bid: openrtb_ext.NonBidObject{Price: 0.1, OriginalBidCPM: 0.2, OriginalBidCur: "USD", W: 728, H: 90}}},
+			}},
+		}},
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	base := bidRow{
+		Timestamp:   testTime.UnixMilli(),
+		AuctionID:   "auction",
+		AccountID:   "account",
+		GDPR:        true,
+		GDPRConsent: true,
+		GPP:         true,
+		LMT:         true,
+	}
+	with := func(update func(row *bidRow)) bidRow {
+		row := base
+		update(&row)
+		return row
+	}
+
+	expected := []bidRow{
+		with(func(row *bidRow) {
+			row.ImpID, row.Bidder, row.Seat, row.Status, row.BidID = "imp1", "appnexus", "alternate", statusBid, "generated"
+			row.Price, row.AdjustedPrice, row.Currency, row.OriginalCurrency = ptrutil.ToPtr(1.0), ptrutil.ToPtr(0.9), "EUR", "USD"
+			row.DealID, row.MediaType, row.Width, row.Height = "deal", "banner", ptrutil.ToPtr[int64](300), ptrutil.ToPtr[int64](250)
+			row.LatencyMs = ptrutil.ToPtr[int32](42)
+		}),
+		with(func(row *bidRow) {
+			row.ImpID, row.Bidder, row.Seat, row.Status = "imp1", "openx", "openx", statusNoBid
+		}),
+		with(func(row *bidRow) {
+			row.ImpID, row.Bidder, row.Seat, row.Status = "imp1", "rubicon", "rubicon", statusNonBid
+			row.Price, row.AdjustedPrice, row.Currency, row.OriginalCurrency = ptrutil.ToPtr(0.2), ptrutil.ToPtr(0.1), "EUR", "USD"
+			row.Width, row.Height = ptrutil.ToPtr[int64](728), ptrutil.ToPtr[int64](90)
+			row.NonBidReason, row.LatencyMs = ptrutil.ToPtr[int32](301), ptrutil.ToPtr[int32](100)
+		}),
+		with(func(row *bidRow) {
+			row.ImpID, row.Bidder, row.Seat, row.Status, row.BidID = "imp2", "appnexus", "appnexus", statusBid, "bid2"
+			row.AdjustedPrice, row.Currency, row.MediaType = ptrutil.ToPtr(2.0), "EUR", "video"
+			row.LatencyMs = ptrutil.ToPtr[int32](42)
+		}),
+	}
+
+	assert.Equal(t, expected, flatten(newTestAuction(), testTime))
+}
+
+func TestFlattenWithoutResponse(t *testing.T) {
+	rows := flatten(&analytics.AuctionObject{RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		ID:  "auction",
+		Imp: []openrtb2.Imp{{ID: "imp", Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus":{}}}}`)}},
+	}}}, testTime)
+
+	assert.Equal(t, []bidRow{{Timestamp: testTime.UnixMilli(), AuctionID: "auction", ImpID: "imp", Bidder: "appnexus", Seat: "appnexus", Status: statusNoBid}}, rows)
+	assert.Empty(t, flatten(&analytics.AuctionObject{}, testTime), "auctions without requests have no rows")
+}
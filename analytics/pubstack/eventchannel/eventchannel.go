@@ -27,6 +27,8 @@ type EventChannel struct {
 
 	ch          chan []byte
 	endCh       chan int
+	doneCh      chan struct{}
+	sending     sync.WaitGroup
 	metrics     Metrics
 	muxGzBuffer sync.RWMutex
 	send        Sender
@@ -43,6 +45,7 @@ func NewEventChannel(sender Sender, clock clock.Clock, maxByteSize, maxEventCoun
 		buff:    b,
 		ch:      make(chan []byte),
 		endCh:   make(chan int),
+		doneCh:  make(chan struct{}),
 		metrics: Metrics{},
 		send:    sender,
 		limit:   Limit{maxByteSize, maxEventCount, maxTime},
@@ -60,6 +63,12 @@ func (c *EventChannel) Close() {
 	c.endCh <- 1
 }
 
+// Wait blocks until the channel is closed and the sends of all flushed events are done
+func (c *EventChannel) Wait() {
+	<-c.doneCh
+	c.sending.Wait()
+}
+
 func (c *EventChannel) buffer(event []byte) {
 	c.muxGzBuffer.Lock()
 	defer c.muxGzBuffer.Unlock()
@@ -117,7 +126,11 @@ func (c *EventChannel) flush() {
 	}
 
 	// send events (async)
-	go c.send(payload)
+	c.sending.Add(1)
+	go func() {
+		defer c.sending.Done()
+		c.send(payload)
+	}()
 }
 
 func (c *EventChannel) start() {
@@ -127,6 +140,7 @@ func (c *EventChannel) start() {
 		select {
 		case <-c.endCh:
 			c.flush()
+			close(c.doneCh)
 			return
 
 		// event is received
@@ -145,3 +145,20 @@ func TestEventChannelPush(t *testing.T) {
 	data, _ := readChanOrTimeout(t, dataSent)
 	assert.ElementsMatch(t, []byte{'1', '2', '3'}, []byte(readGz(data)))
 }
+
+func TestEventChannelWait(t *testing.T) {
+	sent := false
+	send := func([]byte) error {
+		time.Sleep(10 * time.Millisecond)
+		sent = true
+		return nil
+	}
+	clockMock := clock.NewMock()
+
+	eventChannel := NewEventChannel(send, clockMock, largeBufferSize, largeEventCount, maxTime)
+	eventChannel.Push([]byte("one"))
+	eventChannel.Close()
+	eventChannel.Wait()
+
+	assert.True(t, sent, "the flushed events should be sent when Wait returns")
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/prebid/prebid-server/v3/privacy"
 )
 
+// Runner logs the events to the analytics modules. The events of an account are only logged to the modules selected
+// by the analytics config of the account, at their sample rates.
 type Runner interface {
 	LogAuctionObject(*AuctionObject, privacy.ActivityControl)
 	LogVideoObject(*VideoObject, privacy.ActivityControl)
@@ -0,0 +1,200 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	eventTypeAuction      = "auction"
+	eventTypeAmp          = "amp"
+	eventTypeVideo        = "video"
+	eventTypeSetUID       = "setuid"
+	eventTypeCookieSync   = "cookie_sync"
+	eventTypeNotification = "notification"
+)
+
+// typeField is always logged, so the events of a batch can be told apart whichever fields are selected
+const typeField = "type"
+
+type eventHeader struct {
+	Type      string `json:"type"`
+	AccountID string `json:"account_id,omitempty"`
+}
+
+type auctionEvent struct {
+	eventHeader
+	Status     int                      `json:"status"`
+	Errors     []string                 `json:"errors,omitempty"`
+	StartTime  time.Time                `json:"start_time"`
+	Request    *openrtb2.BidRequest     `json:"request,omitempty"`
+	Response   *openrtb2.BidResponse    `json:"response,omitempty"`
+	SeatNonBid []openrtb_ext.SeatNonBid `json:"seat_non_bid,omitempty"`
+}
+
+type ampEvent struct {
+	eventHeader
+	Status     int                      `json:"status"`
+	Errors     []string                 `json:"errors,omitempty"`
+	StartTime  time.Time                `json:"start_time"`
+	Origin     string                   `json:"origin,omitempty"`
+	Request    *openrtb2.BidRequest     `json:"request,omitempty"`
+	Response   *openrtb2.BidResponse    `json:"response,omitempty"`
+	Targeting  map[string]string        `json:"targeting,omitempty"`
+	SeatNonBid []openrtb_ext.SeatNonBid `json:"seat_non_bid,omitempty"`
+}
+
+type videoEvent struct {
+	eventHeader
+	Status        int                           `json:"status"`
+	Errors        []string                      `json:"errors,omitempty"`
+	StartTime     time.Time                     `json:"start_time"`
+	Request       *openrtb2.BidRequest          `json:"request,omitempty"`
+	Response      *openrtb2.BidResponse         `json:"response,omitempty"`
+	VideoRequest  *openrtb_ext.BidRequestVideo  `json:"video_request,omitempty"`
+	VideoResponse *openrtb_ext.BidResponseVideo `json:"video_response,omitempty"`
+	SeatNonBid    []openrtb_ext.SeatNonBid      `json:"seat_non_bid,omitempty"`
+}
+
+type setUIDEvent struct {
+	eventHeader
+	Status  int      `json:"status"`
+	Errors  []string `json:"errors,omitempty"`
+	Bidder  string   `json:"bidder,omitempty"`
+	UID     string   `json:"uid,omitempty"`
+	Success bool     `json:"success"`
+}
+
+type cookieSyncEvent struct {
+	eventHeader
+	Status       int                           `json:"status"`
+	Errors       []string                      `json:"errors,omitempty"`
+	BidderStatus []*analytics.CookieSyncBidder `json:"bidder_status,omitempty"`
+}
+
+type notificationEvent struct {
+	eventHeader
+	Request *analytics.EventRequest `json:"request,omitempty"`
+}
+
+func newAuctionEvent(ao *analytics.AuctionObject) *auctionEvent {
+	return &auctionEvent{
+		eventHeader: eventHeader{Type: eventTypeAuction, AccountID: analytics.AccountID(ao.Account, ao.RequestWrapper)},
+		Status:      ao.Status,
+		Errors:      analytics.ErrorStrings(ao.Errors),
+		StartTime:   ao.StartTime,
+		Request:     analytics.BidRequest(ao.RequestWrapper),
+		Response:    ao.Response,
+		SeatNonBid:  ao.SeatNonBid,
+	}
+}
+
+func newAmpEvent(ao *analytics.AmpObject) *ampEvent {
+	return &ampEvent{
+		eventHeader: eventHeader{Type: eventTypeAmp, AccountID: analytics.AccountID(ao.Account, ao.RequestWrapper)},
+		Status:      ao.Status,
+		Errors:      analytics.ErrorStrings(ao.Errors),
+		StartTime:   ao.StartTime,
+		Origin:      ao.Origin,
+		Request:     analytics.BidRequest(ao.RequestWrapper),
+		Response:    ao.AuctionResponse,
+		Targeting:   ao.AmpTargetingValues,
+		SeatNonBid:  ao.SeatNonBid,
+	}
+}
+
+func newVideoEvent(vo *analytics.VideoObject) *videoEvent {
+	return &videoEvent{
+		eventHeader:   eventHeader{Type: eventTypeVideo, AccountID: analytics.AccountID(vo.Account, vo.RequestWrapper)},
+		Status:        vo.Status,
+		Errors:        analytics.ErrorStrings(vo.Errors),
+		StartTime:     vo.StartTime,
+		Request:       analytics.BidRequest(vo.RequestWrapper),
+		Response:      vo.Response,
+		VideoRequest:  vo.VideoRequest,
+		VideoResponse: vo.VideoResponse,
+		SeatNonBid:    vo.SeatNonBid,
+	}
+}
+
+func newSetUIDEvent(so *analytics.SetUIDObject) *setUIDEvent {
+	return &setUIDEvent{
+		eventHeader: eventHeader{Type: eventTypeSetUID},
+		Status:      so.Status,
+		Errors:      analytics.ErrorStrings(so.Errors),
+		Bidder:      so.Bidder,
+		UID:         so.UID,
+		Success:     so.Success,
+	}
+}
+
+func newCookieSyncEvent(cso *analytics.CookieSyncObject) *cookieSyncEvent {
+	return &cookieSyncEvent{
+		eventHeader:  eventHeader{Type: eventTypeCookieSync},
+		Status:       cso.Status,
+		Errors:       analytics.ErrorStrings(cso.Errors),
+		BidderStatus: cso.BidderStatus,
+	}
+}
+
+func newNotificationEvent(ne *analytics.NotificationEvent) *notificationEvent {
+	id := ""
+	if ne.Account != nil {
+		id = ne.Account.ID
+	} else if ne.Request != nil {
+		id = ne.Request.AccountID
+	}
+	return &notificationEvent{
+		eventHeader: eventHeader{Type: eventTypeNotification, AccountID: id},
+		Request:     ne.Request,
+	}
+}
+
+// validateFields checks that the fields are plain dot separated paths, since the gjson query syntax can't be used to
+// build the projected event
+func validateFields(fields []string) error {
+	for _, field := range fields {
+		if field == "" || strings.ContainsAny(field, `*?#|@\!=<>%`) {
+			return fmt.Errorf("invalid field %q", field)
+		}
+	}
+	return nil
+}
+
+// serialize marshals the event as a JSON line, keeping only the given fields. All fields are kept if none are given.
+func serialize(event interface{}, fields []string) ([]byte, error) {
+	data, err := jsonutil.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) > 0 {
+		if data, err = project(data, fields); err != nil {
+			return nil, err
+		}
+	}
+	return append(data, '\n'), nil
+}
+
+func project(data []byte, fields []string) ([]byte, error) {
+	projected := []byte(`{}`)
+	var err error
+	for _, field := range append([]string{typeField}, fields...) {
+		value := gjson.GetBytes(data, field)
+		if !value.Exists() {
+			continue
+		}
+		if projected, err = sjson.SetRawBytes(projected, field, []byte(value.Raw)); err != nil {
+			return nil, err
+		}
+	}
+	return projected, nil
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerialize(t *testing.T) {
+	event := newAuctionEvent(&analytics.AuctionObject{
+		Status: 200,
+		Errors: []error{errors.New("some error")},
+		RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			ID:   "req",
+			Site: &openrtb2.Site{Page: "https://example.com", Publisher: &openrtb2.Publisher{ID: "pub"}},
+			Imp:  []openrtb2.Imp{{ID: "imp"}},
+		}},
+	})
+
+	testCases := []struct {
+		description string
+		fields      []string
+		expected    string
+	}{
+		{
+			description: "selected-fields",
+			fields:      []string{"account_id", "request.site.page", "request.imp", "response"},
+			expected:    `{"type":"auction","account_id":"pub","request":{"site":{"page":"https://example.com"},"imp":[{"id":"imp"}]}}`,
+		},
+		{
+			description: "array-element",
+			fields:      []string{"errors.0"},
+			expected:    `{"type":"auction","errors":["some error"]}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			data, err := serialize(event, test.fields)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expected, string(data))
+			assert.Equal(t, byte('\n'), data[len(data)-1], "events must be JSON lines")
+		})
+	}
+}
+
+func TestSerializeAllFields(t *testing.T) {
+	data, err := serialize(newSetUIDEvent(&analytics.SetUIDObject{Status: 200, Bidder: "bidder", UID: "uid", Success: true}), nil)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"setuid","status":200,"bidder":"bidder","uid":"uid","success":true}`, string(data))
+}
+
+func TestValidateFields(t *testing.T) {
+	assert.NoError(t, validateFields([]string{"request.site.page", "errors.0"}))
+	assert.Error(t, validateFields([]string{""}))
+	assert.Error(t, validateFields([]string{"request.imp.#.id"}))
+	assert.Error(t, validateFields([]string{"request.*"}))
+}
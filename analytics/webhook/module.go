@@ -0,0 +1,215 @@
+// Package webhook implements the generic "http" analytics module, which posts batches of analytics events as gzipped
+// JSON lines to a configured endpoint.
+package webhook
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/docker/go-units"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/analytics/pubstack/eventchannel"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+)
+
+type eventConfig struct {
+	enabled    bool
+	sampleRate float64
+	fields     []string
+}
+
+type HTTPLogger struct {
+	channel  *eventchannel.EventChannel
+	events   map[string]eventConfig
+	accounts map[string]struct{}
+	sample   func() float64
+
+	// closed guards the event channel, which no longer accepts events once closed
+	mux    sync.RWMutex
+	closed bool
+	stopCh chan struct{}
+}
+
+func NewModule(httpClient *http.Client, cfg config.HTTPAnalytics, clock clock.Clock) (analytics.Module, error) {
+	sender, err := createHttpSender(httpClient, cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return newHTTPLogger(cfg, sender, clock)
+}
+
+func newHTTPLogger(cfg config.HTTPAnalytics, sender httpSender, clock clock.Clock) (*HTTPLogger, error) {
+	size, err := units.FromHumanSize(cfg.Buffers.BufferSize)
+	if err != nil {
+		return nil, fmt.Errorf("buffers.size: %v", err)
+	}
+	timeout, err := time.ParseDuration(cfg.Buffers.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("buffers.timeout: %v", err)
+	}
+	retry, err := newRetryConfig(cfg.Retry)
+	if err != nil {
+		return nil, err
+	}
+	events, err := newEventConfigs(cfg.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts map[string]struct{}
+	if len(cfg.Accounts) > 0 {
+		accounts = make(map[string]struct{}, len(cfg.Accounts))
+		for _, account := range cfg.Accounts {
+			accounts[account] = struct{}{}
+		}
+	}
+
+	l := &HTTPLogger{
+		events:   events,
+		accounts: accounts,
+		sample:   rand.Float64,
+		stopCh:   make(chan struct{}),
+	}
+	send := withRetries(sender, retry, clock, l.stopCh)
+	l.channel = eventchannel.NewEventChannel(send, clock, size, int64(cfg.Buffers.EventCount), timeout)
+
+	logger.Infof("[httpAnalytics] HTTP analytics configured and ready, endpoint=%s", cfg.Endpoint.Url)
+	return l, nil
+}
+
+func newEventConfigs(cfg config.HTTPAnalyticsEvents) (map[string]eventConfig, error) {
+	events := map[string]config.HTTPAnalyticsEvent{
+		eventTypeAuction:      cfg.Auction,
+		eventTypeAmp:          cfg.AMP,
+		eventTypeVideo:        cfg.Video,
+		eventTypeSetUID:       cfg.SetUID,
+		eventTypeCookieSync:   cfg.CookieSync,
+		eventTypeNotification: cfg.Notification,
+	}
+
+	configs := make(map[string]eventConfig, len(events))
+	for eventType, event := range events {
+		if event.SampleRate < 0 || event.SampleRate > 1 {
+			return nil, fmt.Errorf("events.%s.sample_rate must be between 0 and 1. Got %f", eventType, event.SampleRate)
+		}
+		if err := validateFields(event.Fields); err != nil {
+			return nil, fmt.Errorf("events.%s.fields: %v", eventType, err)
+		}
+		configs[eventType] = eventConfig{
+			enabled:    event.Enabled,
+			sampleRate: event.SampleRate,
+			fields:     event.Fields,
+		}
+	}
+	return configs, nil
+}
+
+// shouldLog checks whether the event type is enabled for the account, and whether the event is sampled
+func (l *HTTPLogger) shouldLog(eventType, accountID string) bool {
+	event := l.events[eventType]
+	if !event.enabled {
+		return false
+	}
+	if l.accounts != nil {
+		if _, ok := l.accounts[accountID]; !ok {
+			return false
+		}
+	}
+	return event.sampleRate >= 1 || l.sample() < event.sampleRate
+}
+
+func (l *HTTPLogger) log(eventType string, event interface{}) {
+	data, err := serialize(event, l.events[eventType].fields)
+	if err != nil {
+		logger.Errorf("[httpAnalytics] Error serializing %s event: %v", eventType, err)
+		return
+	}
+
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	if !l.closed {
+		l.channel.Push(data)
+	}
+}
+
+func (l *HTTPLogger) LogAuctionObject(ao *analytics.AuctionObject) {
+	if ao == nil {
+		return
+	}
+	event := newAuctionEvent(ao)
+	if l.shouldLog(eventTypeAuction, event.AccountID) {
+		l.log(eventTypeAuction, event)
+	}
+}
+
+func (l *HTTPLogger) LogAmpObject(ao *analytics.AmpObject) {
+	if ao == nil {
+		return
+	}
+	event := newAmpEvent(ao)
+	if l.shouldLog(eventTypeAmp, event.AccountID) {
+		l.log(eventTypeAmp, event)
+	}
+}
+
+func (l *HTTPLogger) LogVideoObject(vo *analytics.VideoObject) {
+	if vo == nil {
+		return
+	}
+	event := newVideoEvent(vo)
+	if l.shouldLog(eventTypeVideo, event.AccountID) {
+		l.log(eventTypeVideo, event)
+	}
+}
+
+// LogSetUIDObject logs /setuid events, which have no account. They are skipped when the module is limited to accounts.
+func (l *HTTPLogger) LogSetUIDObject(so *analytics.SetUIDObject) {
+	if so == nil {
+		return
+	}
+	if l.shouldLog(eventTypeSetUID, "") {
+		l.log(eventTypeSetUID, newSetUIDEvent(so))
+	}
+}
+
+// LogCookieSyncObject logs /cookie_sync events, which have no account. They are skipped when the module is limited to
+// accounts.
+func (l *HTTPLogger) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	if cso == nil {
+		return
+	}
+	if l.shouldLog(eventTypeCookieSync, "") {
+		l.log(eventTypeCookieSync, newCookieSyncEvent(cso))
+	}
+}
+
+func (l *HTTPLogger) LogNotificationEventObject(ne *analytics.NotificationEvent) {
+	if ne == nil {
+		return
+	}
+	event := newNotificationEvent(ne)
+	if l.shouldLog(eventTypeNotification, event.AccountID) {
+		l.log(eventTypeNotification, event)
+	}
+}
+
+// Shutdown flushes the buffered events and waits for the pending sends. Retries of failed sends are abandoned.
+func (l *HTTPLogger) Shutdown() {
+	l.mux.Lock()
+	if l.closed {
+		l.mux.Unlock()
+		return
+	}
+	l.closed = true
+	l.mux.Unlock()
+
+	logger.Infof("[httpAnalytics] Shutdown, trying to flush buffer")
+	l.channel.Close()
+	close(l.stopCh)
+	l.channel.Wait()
+}
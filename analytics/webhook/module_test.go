@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSender struct {
+	mux      sync.Mutex
+	payloads [][]byte
+}
+
+func (s *recordingSender) send(payload []byte) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.payloads = append(s.payloads, payload)
+	return nil
+}
+
+// lines returns the JSON lines of all the sent payloads
+func (s *recordingSender) lines(t *testing.T) []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	var lines []string
+	for _, payload := range s.payloads {
+		reader, err := gzip.NewReader(bytes.NewReader(payload))
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		lines = append(lines, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")...)
+	}
+	return lines
+}
+
+func newTestConfig() config.HTTPAnalytics {
+	enabled := config.HTTPAnalyticsEvent{Enabled: true, SampleRate: 1}
+	return config.HTTPAnalytics{
+		Enabled:  true,
+		Endpoint: config.HTTPAnalyticsEndpoint{Url: "http://localhost", Timeout: "1s"},
+		Buffers:  config.HTTPAnalyticsBuffer{BufferSize: "1MB", EventCount: 100, Timeout: "1h"},
+		Retry:    config.HTTPAnalyticsRetry{MaxRetries: 0, Backoff: "1s", MaxBackoff: "1s"},
+		Events: config.HTTPAnalyticsEvents{
+			Auction:      enabled,
+			AMP:          enabled,
+			Video:        enabled,
+			SetUID:       enabled,
+			CookieSync:   enabled,
+			Notification: enabled,
+		},
+	}
+}
+
+func newAuctionObject(publisherID string) *analytics.AuctionObject {
+	return &analytics.AuctionObject{
+		Status: 200,
+		RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			ID:   "req",
+			Site: &openrtb2.Site{Publisher: &openrtb2.Publisher{ID: publisherID}},
+		}},
+	}
+}
+
+func TestLogAllEvents(t *testing.T) {
+	sender := &recordingSender{}
+	l, err := newHTTPLogger(newTestConfig(), sender.send, clock.NewMock())
+	require.NoError(t, err)
+
+	l.LogAuctionObject(newAuctionObject("pub"))
+	l.LogAmpObject(&analytics.AmpObject{Status: 200})
+	l.LogVideoObject(&analytics.VideoObject{Status: 200})
+	l.LogSetUIDObject(&analytics.SetUIDObject{Status: 200})
+	l.LogCookieSyncObject(&analytics.CookieSyncObject{Status: 200})
+	l.LogNotificationEventObject(&analytics.NotificationEvent{Request: &analytics.EventRequest{AccountID: "pub"}})
+	l.Shutdown()
+
+	lines := sender.lines(t)
+	require.Len(t, lines, 6)
+	assert.JSONEq(t, `{"type":"auction","account_id":"pub","status":200,"start_time":"0001-01-01T00:00:00Z","request":{"id":"req","imp":null,"site":{"publisher":{"id":"pub"}}}}`, lines[0])
+	assert.Contains(t, lines[1], `"type":"amp"`)
+	assert.Contains(t, lines[2], `"type":"video"`)
+	assert.Contains(t, lines[3], `"type":"setuid"`)
+	assert.Contains(t, lines[4], `"type":"cookie_sync"`)
+	assert.JSONEq(t, `{"type":"notification","account_id":"pub","request":{"account_id":"pub"}}`, lines[5])
+}
+
+func TestLogFlushesFullBuffer(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Buffers.EventCount = 2
+	sent := make(chan struct{}, 1)
+	l, err := newHTTPLogger(cfg, func([]byte) error {
+		sent <- struct{}{}
+		return nil
+	}, clock.NewMock())
+	require.NoError(t, err)
+	defer l.Shutdown()
+
+	l.LogAuctionObject(newAuctionObject("pub"))
+	l.LogAuctionObject(newAuctionObject("pub"))
+
+	<-sent
+}
+
+func TestLogFieldSelection(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Events.Auction.Fields = []string{"request.id"}
+	sender := &recordingSender{}
+	l, err := newHTTPLogger(cfg, sender.send, clock.NewMock())
+	require.NoError(t, err)
+
+	l.LogAuctionObject(newAuctionObject("pub"))
+	l.Shutdown()
+
+	lines := sender.lines(t)
+	require.Len(t, lines, 1)
+	assert.JSONEq(t, `{"type":"auction","request":{"id":"req"}}`, lines[0])
+}
+
+func TestLogAccounts(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Accounts = []string{"pub"}
+	sender := &recordingSender{}
+	l, err := newHTTPLogger(cfg, sender.send, clock.NewMock())
+	require.NoError(t, err)
+
+	l.LogAuctionObject(newAuctionObject("pub"))
+	l.LogAuctionObject(newAuctionObject("other"))
+	l.LogSetUIDObject(&analytics.SetUIDObject{Status: 200})
+	l.Shutdown()
+
+	lines := sender.lines(t)
+	require.Len(t, lines, 1, "only the events of the configured accounts should be logged")
+	assert.Contains(t, lines[0], `"account_id":"pub"`)
+}
+
+func TestLogSampling(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Events.Auction.SampleRate = 0.5
+	cfg.Events.AMP.Enabled = false
+	sender := &recordingSender{}
+	l, err := newHTTPLogger(cfg, sender.send, clock.NewMock())
+	require.NoError(t, err)
+
+	samples := []float64{0.2, 0.7}
+	l.sample = func() float64 {
+		sample := samples[0]
+		samples = samples[1:]
+		return sample
+	}
+
+	l.LogAuctionObject(newAuctionObject("sampled"))
+	l.LogAuctionObject(newAuctionObject("skipped"))
+	l.LogAmpObject(&analytics.AmpObject{Status: 200})
+	l.Shutdown()
+
+	lines := sender.lines(t)
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"account_id":"sampled"`)
+}
+
+func TestLogAfterShutdown(t *testing.T) {
+	sender := &recordingSender{}
+	l, err := newHTTPLogger(newTestConfig(), sender.send, clock.NewMock())
+	require.NoError(t, err)
+
+	l.Shutdown()
+	l.LogAuctionObject(newAuctionObject("pub"))
+	l.Shutdown()
+
+	assert.Empty(t, sender.lines(t))
+}
+
+func TestNewHTTPLoggerConfigErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		update      func(cfg *config.HTTPAnalytics)
+	}{
+		{
+			description: "buffer-size",
+			update:      func(cfg *config.HTTPAnalytics) { cfg.Buffers.BufferSize = "large" },
+		},
+		{
+			description: "buffer-timeout",
+			update:      func(cfg *config.HTTPAnalytics) { cfg.Buffers.Timeout = "soon" },
+		},
+		{
+			description: "retry",
+			update:      func(cfg *config.HTTPAnalytics) { cfg.Retry.MaxRetries = -1 },
+		},
+		{
+			description: "sample-rate",
+			update:      func(cfg *config.HTTPAnalytics) { cfg.Events.Video.SampleRate = 1.5 },
+		},
+		{
+			description: "fields",
+			update:      func(cfg *config.HTTPAnalytics) { cfg.Events.SetUID.Fields = []string{"errors.#"} },
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := newTestConfig()
+			test.update(&cfg)
+			_, err := newHTTPLogger(cfg, func([]byte) error { return nil }, clock.NewMock())
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/version"
+)
+
+type httpSender = func(payload []byte) error
+
+// permanentError is returned for failures which won't go away by sending the same payload again
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func createHttpSender(httpClient *http.Client, endpoint config.HTTPAnalyticsEndpoint) (httpSender, error) {
+	if _, err := url.ParseRequestURI(endpoint.Url); err != nil {
+		return nil, fmt.Errorf("invalid endpoint url: %v", err)
+	}
+
+	httpTimeout, err := time.ParseDuration(endpoint.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(payload []byte) error {
+		ctx, cancel := context.WithTimeout(context.Background(), httpTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(payload))
+		if err != nil {
+			return permanentError{err}
+		}
+
+		for name, value := range endpoint.Headers {
+			req.Header.Set(name, value)
+		}
+		req.Header.Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		req.Header.Set("Content-Encoding", "gzip")
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+				logger.Errorf("[httpAnalytics] Draining response body failed: %v", err)
+			}
+			resp.Body.Close()
+		}()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			err := fmt.Errorf("wrong code received %d", resp.StatusCode)
+			if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+				return permanentError{err}
+			}
+			return err
+		}
+		return nil
+	}, nil
+}
+
+type retryConfig struct {
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+func newRetryConfig(cfg config.HTTPAnalyticsRetry) (retryConfig, error) {
+	if cfg.MaxRetries < 0 {
+		return retryConfig{}, fmt.Errorf("retry.max_retries must be 0 or greater. Got %d", cfg.MaxRetries)
+	}
+	backoff, err := time.ParseDuration(cfg.Backoff)
+	if err != nil {
+		return retryConfig{}, fmt.Errorf("retry.backoff: %v", err)
+	}
+	maxBackoff, err := time.ParseDuration(cfg.MaxBackoff)
+	if err != nil {
+		return retryConfig{}, fmt.Errorf("retry.max_backoff: %v", err)
+	}
+	if maxBackoff < backoff {
+		maxBackoff = backoff
+	}
+	return retryConfig{maxRetries: cfg.MaxRetries, backoff: backoff, maxBackoff: maxBackoff}, nil
+}
+
+// withRetries retries failed sends with an exponential backoff. Pending retries are abandoned once stopCh is closed.
+func withRetries(send httpSender, cfg retryConfig, clock clock.Clock, stopCh <-chan struct{}) httpSender {
+	return func(payload []byte) error {
+		backoff := cfg.backoff
+		for attempt := 0; ; attempt++ {
+			err := send(payload)
+			if err == nil {
+				return nil
+			}
+			if errors.As(err, &permanentError{}) || attempt >= cfg.maxRetries {
+				logger.Errorf("[httpAnalytics] Sending events failed after %d attempts, dropping them: %v", attempt+1, err)
+				return err
+			}
+
+			timer := clock.Timer(backoff)
+			select {
+			case <-stopCh:
+				timer.Stop()
+				logger.Warnf("[httpAnalytics] Shutting down, dropping events which failed to send: %v", err)
+				return err
+			case <-timer.C:
+			}
+
+			backoff *= 2
+			if backoff > cfg.maxBackoff {
+				backoff = cfg.maxBackoff
+			}
+		}
+	}
+}
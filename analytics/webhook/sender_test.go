@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHttpSender(t *testing.T) {
+	testCases := []struct {
+		description string
+		status      int
+		expectedErr bool
+		permanent   bool
+	}{
+		{description: "ok", status: http.StatusOK},
+		{description: "no-content", status: http.StatusNoContent},
+		{description: "server-error", status: http.StatusBadGateway, expectedErr: true},
+		{description: "too-many-requests", status: http.StatusTooManyRequests, expectedErr: true},
+		{description: "bad-request", status: http.StatusBadRequest, expectedErr: true, permanent: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var received *http.Request
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			sender, err := createHttpSender(server.Client(), config.HTTPAnalyticsEndpoint{
+				Url:     server.URL,
+				Timeout: "1s",
+				Headers: map[string]string{"Authorization": "Bearer token"},
+			})
+			require.NoError(t, err)
+
+			err = sender([]byte("payload"))
+
+			if test.expectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, test.permanent, errors.As(err, &permanentError{}))
+			} else {
+				assert.NoError(t, err)
+			}
+			require.NotNil(t, received)
+			assert.Equal(t, "payload", string(body))
+			assert.Equal(t, "gzip", received.Header.Get("Content-Encoding"))
+			assert.Equal(t, "application/x-ndjson", received.Header.Get("Content-Type"))
+			assert.Equal(t, "Bearer token", received.Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestCreateHttpSenderConfigErrors(t *testing.T) {
+	_, err := createHttpSender(http.DefaultClient, config.HTTPAnalyticsEndpoint{Url: "", Timeout: "1s"})
+	assert.Error(t, err)
+
+	_, err = createHttpSender(http.DefaultClient, config.HTTPAnalyticsEndpoint{Url: "http://localhost", Timeout: "soon"})
+	assert.Error(t, err)
+}
+
+func TestWithRetries(t *testing.T) {
+	testCases := []struct {
+		description      string
+		errs             []error
+		expectedAttempts int
+		expectedErr      bool
+	}{
+		{
+			description:      "success",
+			errs:             []error{nil},
+			expectedAttempts: 1,
+		},
+		{
+			description:      "success-after-retries",
+			errs:             []error{errors.New("failure"), errors.New("failure"), nil},
+			expectedAttempts: 3,
+		},
+		{
+			description:      "retries-exhausted",
+			errs:             []error{errors.New("failure"), errors.New("failure"), errors.New("failure"), errors.New("failure")},
+			expectedAttempts: 3,
+			expectedErr:      true,
+		},
+		{
+			description:      "permanent-error",
+			errs:             []error{permanentError{errors.New("failure")}, nil},
+			expectedAttempts: 1,
+			expectedErr:      true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			attempts := 0
+			send := func([]byte) error {
+				err := test.errs[attempts]
+				attempts++
+				return err
+			}
+			retry := retryConfig{maxRetries: 2, backoff: time.Millisecond, maxBackoff: 2 * time.Millisecond}
+
+			err := withRetries(send, retry, clock.New(), make(chan struct{}))([]byte("payload"))
+
+			assert.Equal(t, test.expectedErr, err != nil)
+			assert.Equal(t, test.expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestWithRetriesStops(t *testing.T) {
+	stopCh := make(chan struct{})
+	close(stopCh)
+	attempts := 0
+	send := func([]byte) error {
+		attempts++
+		return errors.New("failure")
+	}
+	retry := retryConfig{maxRetries: 5, backoff: time.Hour, maxBackoff: time.Hour}
+
+	err := withRetries(send, retry, clock.New(), stopCh)([]byte("payload"))
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestNewRetryConfig(t *testing.T) {
+	retry, err := newRetryConfig(config.HTTPAnalyticsRetry{MaxRetries: 3, Backoff: "1s", MaxBackoff: "30s"})
+	require.NoError(t, err)
+	assert.Equal(t, retryConfig{maxRetries: 3, backoff: time.Second, maxBackoff: 30 * time.Second}, retry)
+
+	_, err = newRetryConfig(config.HTTPAnalyticsRetry{MaxRetries: -1, Backoff: "1s", MaxBackoff: "30s"})
+	assert.Error(t, err)
+
+	_, err = newRetryConfig(config.HTTPAnalyticsRetry{MaxRetries: 3, Backoff: "soon", MaxBackoff: "30s"})
+	assert.Error(t, err)
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"strings"
 
 	"github.com/prebid/go-gdpr/consentconstants"
@@ -35,6 +37,7 @@ const (
 // Account represents a publisher account configuration
 type Account struct {
 	ID                      string                                      `mapstructure:"id" json:"id"`
+	Parent                  string                                      `mapstructure:"parent" json:"parent,omitempty"`
 	Disabled                bool                                        `mapstructure:"disabled" json:"disabled"`
 	CacheTTL                DefaultTTLs                                 `mapstructure:"cache_ttl" json:"cache_ttl"`
 	CCPA                    AccountCCPA                                 `mapstructure:"ccpa" json:"ccpa"`
@@ -54,14 +57,30 @@ type Account struct {
 	Privacy                 AccountPrivacy                              `mapstructure:"privacy" json:"privacy"`
 	PreferredMediaType      openrtb_ext.PreferredMediaType              `mapstructure:"preferredmediatype" json:"preferredmediatype"`
 	TargetingPrefix         string                                      `mapstructure:"targeting_prefix" json:"targeting_prefix"`
+	Analytics               AccountAnalytics                            `mapstructure:"analytics" json:"analytics"`
+}
+
+// Validate validates the settings of the account which are checked for the account defaults at startup and, if
+// validation is enabled, for the stored accounts when they're loaded or saved. The messages are relative to the
+// account, e.g. "analytics.modules...".
+func (a *Account) Validate(errs []error) []error {
+	errs = a.Analytics.Validate(errs)
+	errs = a.Privacy.validatePrecisionPolicies(errs)
+	errs = a.Privacy.Modules.USNat.validate(errs)
+	errs = a.CookieSync.Cookie.Validate(errs)
+	for _, permission := range a.Privacy.EIDPermissions {
+		errs = permission.validate(errs)
+	}
+	return errs
 }
 
 // CookieSync represents the account-level defaults for the cookie sync endpoint.
 type CookieSync struct {
-	DefaultLimit    *int       `mapstructure:"default_limit" json:"default_limit"`
-	MaxLimit        *int       `mapstructure:"max_limit" json:"max_limit"`
-	DefaultCoopSync *bool      `mapstructure:"default_coop_sync" json:"default_coop_sync"`
-	PriorityGroups  [][]string `mapstructure:"priority_groups" json:"priority_groups"`
+	DefaultLimit    *int              `mapstructure:"default_limit" json:"default_limit"`
+	MaxLimit        *int              `mapstructure:"max_limit" json:"max_limit"`
+	DefaultCoopSync *bool             `mapstructure:"default_coop_sync" json:"default_coop_sync"`
+	PriorityGroups  [][]string        `mapstructure:"priority_groups" json:"priority_groups"`
+	Cookie          AccountUIDsCookie `mapstructure:"cookie" json:"cookie"`
 }
 
 // AccountCCPA represents account-specific CCPA configuration
@@ -156,23 +175,23 @@ type AccountGDPR struct {
 	Enabled        *bool          `mapstructure:"enabled" json:"enabled,omitempty"`
 	ChannelEnabled AccountChannel `mapstructure:"channel_enabled" json:"channel_enabled"`
 	// Array of basic enforcement vendors that is used to create the hash table so vendor names can be instantly accessed
-	BasicEnforcementVendors    []string `mapstructure:"basic_enforcement_vendors" json:"basic_enforcement_vendors"`
-	BasicEnforcementVendorsMap map[string]struct{}
-	Purpose1                   AccountGDPRPurpose `mapstructure:"purpose1" json:"purpose1"`
-	Purpose2                   AccountGDPRPurpose `mapstructure:"purpose2" json:"purpose2"`
-	Purpose3                   AccountGDPRPurpose `mapstructure:"purpose3" json:"purpose3"`
-	Purpose4                   AccountGDPRPurpose `mapstructure:"purpose4" json:"purpose4"`
-	Purpose5                   AccountGDPRPurpose `mapstructure:"purpose5" json:"purpose5"`
-	Purpose6                   AccountGDPRPurpose `mapstructure:"purpose6" json:"purpose6"`
-	Purpose7                   AccountGDPRPurpose `mapstructure:"purpose7" json:"purpose7"`
-	Purpose8                   AccountGDPRPurpose `mapstructure:"purpose8" json:"purpose8"`
-	Purpose9                   AccountGDPRPurpose `mapstructure:"purpose9" json:"purpose9"`
-	Purpose10                  AccountGDPRPurpose `mapstructure:"purpose10" json:"purpose10"`
+	BasicEnforcementVendors    []string            `mapstructure:"basic_enforcement_vendors" json:"basic_enforcement_vendors"`
+	BasicEnforcementVendorsMap map[string]struct{} `json:"-"`
+	Purpose1                   AccountGDPRPurpose  `mapstructure:"purpose1" json:"purpose1"`
+	Purpose2                   AccountGDPRPurpose  `mapstructure:"purpose2" json:"purpose2"`
+	Purpose3                   AccountGDPRPurpose  `mapstructure:"purpose3" json:"purpose3"`
+	Purpose4                   AccountGDPRPurpose  `mapstructure:"purpose4" json:"purpose4"`
+	Purpose5                   AccountGDPRPurpose  `mapstructure:"purpose5" json:"purpose5"`
+	Purpose6                   AccountGDPRPurpose  `mapstructure:"purpose6" json:"purpose6"`
+	Purpose7                   AccountGDPRPurpose  `mapstructure:"purpose7" json:"purpose7"`
+	Purpose8                   AccountGDPRPurpose  `mapstructure:"purpose8" json:"purpose8"`
+	Purpose9                   AccountGDPRPurpose  `mapstructure:"purpose9" json:"purpose9"`
+	Purpose10                  AccountGDPRPurpose  `mapstructure:"purpose10" json:"purpose10"`
 	// Hash table of purpose configs for convenient purpose config lookup
-	PurposeConfigs      map[consentconstants.Purpose]*AccountGDPRPurpose
-	PurposeOneTreatment AccountGDPRPurposeOneTreatment `mapstructure:"purpose_one_treatment" json:"purpose_one_treatment"`
-	SpecialFeature1     AccountGDPRSpecialFeature      `mapstructure:"special_feature1" json:"special_feature1"`
-	EEACountries        []string                       `mapstructure:"eea_countries" json:"eea_countries"`
+	PurposeConfigs      map[consentconstants.Purpose]*AccountGDPRPurpose `json:"-"`
+	PurposeOneTreatment AccountGDPRPurposeOneTreatment                   `mapstructure:"purpose_one_treatment" json:"purpose_one_treatment"`
+	SpecialFeature1     AccountGDPRSpecialFeature                        `mapstructure:"special_feature1" json:"special_feature1"`
+	EEACountries        []string                                         `mapstructure:"eea_countries" json:"eea_countries"`
 }
 
 // EnabledForChannelType indicates whether GDPR is turned on at the account level for the specified channel type
@@ -272,20 +291,20 @@ func (a *AccountGDPR) PurposeOneTreatmentAccessAllowed() (value, exists bool) {
 type AccountGDPRPurpose struct {
 	EnforceAlgo string `mapstructure:"enforce_algo" json:"enforce_algo,omitempty"`
 	// Integer representation of enforcement algo for performance improvement on compares
-	EnforceAlgoID  TCF2EnforcementAlgo
-	EnforcePurpose *bool `mapstructure:"enforce_purpose" json:"enforce_purpose,omitempty"`
-	EnforceVendors *bool `mapstructure:"enforce_vendors" json:"enforce_vendors,omitempty"`
+	EnforceAlgoID  TCF2EnforcementAlgo `json:"-"`
+	EnforcePurpose *bool               `mapstructure:"enforce_purpose" json:"enforce_purpose,omitempty"`
+	EnforceVendors *bool               `mapstructure:"enforce_vendors" json:"enforce_vendors,omitempty"`
 	// Array of vendor exceptions that is used to create the hash table VendorExceptionMap so vendor names can be instantly accessed
-	VendorExceptions   []string `mapstructure:"vendor_exceptions" json:"vendor_exceptions"`
-	VendorExceptionMap map[string]struct{}
+	VendorExceptions   []string            `mapstructure:"vendor_exceptions" json:"vendor_exceptions"`
+	VendorExceptionMap map[string]struct{} `json:"-"`
 }
 
 // AccountGDPRSpecialFeature represents account-specific GDPR special feature configuration
 type AccountGDPRSpecialFeature struct {
 	Enforce *bool `mapstructure:"enforce" json:"enforce"`
 	// Array of vendor exceptions that is used to create the hash table VendorExceptionMap so vendor names can be instantly accessed
-	VendorExceptions   []openrtb_ext.BidderName `mapstructure:"vendor_exceptions" json:"vendor_exceptions"`
-	VendorExceptionMap map[openrtb_ext.BidderName]struct{}
+	VendorExceptions   []openrtb_ext.BidderName            `mapstructure:"vendor_exceptions" json:"vendor_exceptions"`
+	VendorExceptionMap map[openrtb_ext.BidderName]struct{} `json:"-"`
 }
 
 // AccountGDPRPurposeOneTreatment represents account-specific GDPR purpose one treatment configuration
@@ -347,12 +366,205 @@ func (m AccountModules) ModuleConfig(id string) (json.RawMessage, error) {
 	return m[vendor][module], nil
 }
 
+// Analytics event types, which the account analytics sample rates are set for
+const (
+	AnalyticsEventAuction      = "auction"
+	AnalyticsEventAmp          = "amp"
+	AnalyticsEventVideo        = "video"
+	AnalyticsEventSetUID       = "setuid"
+	AnalyticsEventCookieSync   = "cookie_sync"
+	AnalyticsEventNotification = "notification"
+)
+
+var analyticsEvents = []string{AnalyticsEventAuction, AnalyticsEventAmp, AnalyticsEventVideo, AnalyticsEventSetUID, AnalyticsEventCookieSync, AnalyticsEventNotification}
+
+// AccountAnalytics selects the analytics modules which receive the events of the account
+type AccountAnalytics struct {
+	// DefaultEnabled tells whether the modules missing from Modules receive the events of the account. Defaults to true.
+	DefaultEnabled *bool                             `mapstructure:"default_enabled" json:"default_enabled,omitempty"`
+	Modules        map[string]AccountAnalyticsModule `mapstructure:"modules" json:"modules,omitempty"`
+}
+
+// AccountAnalyticsModule is the account-level config of an analytics module, keyed by the name of the module
+type AccountAnalyticsModule struct {
+	Enabled *bool `mapstructure:"enabled" json:"enabled,omitempty"`
+	// SampleRate is the share of the events sent to the module, between 0 and 1. Defaults to 1.
+	SampleRate *float64 `mapstructure:"sample_rate" json:"sample_rate,omitempty"`
+	// EventSampleRates overrides SampleRate for the given event types
+	EventSampleRates map[string]float64 `mapstructure:"event_sample_rates" json:"event_sample_rates,omitempty"`
+	// Options are read by the module, for settings like vendor specific account IDs, e.g. the code of the agma module
+	Options json.RawMessage `mapstructure:"options" json:"options,omitempty"`
+}
+
+// SampleRate returns the share of the events of the given type which the module receives for the account. It's 0 if
+// the module is disabled for the account.
+func (a *AccountAnalytics) SampleRate(module, eventType string) float64 {
+	moduleCfg, ok := a.Modules[module]
+	if !ok {
+		if a.DefaultEnabled != nil && !*a.DefaultEnabled {
+			return 0
+		}
+		return 1
+	}
+	if moduleCfg.Enabled != nil && !*moduleCfg.Enabled {
+		return 0
+	}
+	if rate, ok := moduleCfg.EventSampleRates[eventType]; ok {
+		return rate
+	}
+	if moduleCfg.SampleRate != nil {
+		return *moduleCfg.SampleRate
+	}
+	return 1
+}
+
+// ModuleOptions returns the account-level options of the analytics module, if any
+func (a *AccountAnalytics) ModuleOptions(module string) json.RawMessage {
+	return a.Modules[module].Options
+}
+
+// Validate validates the analytics config. The messages are relative to the account, e.g. "analytics.modules...".
+func (a *AccountAnalytics) Validate(errs []error) []error {
+	for name, module := range a.Modules {
+		if module.SampleRate != nil && (*module.SampleRate < 0 || *module.SampleRate > 1) {
+			errs = append(errs, fmt.Errorf("analytics.modules.%s.sample_rate must be between 0 and 1. Got %f", name, *module.SampleRate))
+		}
+		for eventType, rate := range module.EventSampleRates {
+			if !slices.Contains(analyticsEvents, eventType) {
+				errs = append(errs, fmt.Errorf("analytics.modules.%s.event_sample_rates has an unknown event type %s. Valid types are %s", name, eventType, strings.Join(analyticsEvents, ", ")))
+			} else if rate < 0 || rate > 1 {
+				errs = append(errs, fmt.Errorf("analytics.modules.%s.event_sample_rates.%s must be between 0 and 1. Got %f", name, eventType, rate))
+			}
+		}
+	}
+	return errs
+}
+
 type AccountPrivacy struct {
 	AllowActivities *AllowActivities `mapstructure:"allowactivities" json:"allowactivities"`
 	DSA             *AccountDSA      `mapstructure:"dsa" json:"dsa"`
 	IPv6Config      IPv6             `mapstructure:"ipv6" json:"ipv6"`
 	IPv4Config      IPv4             `mapstructure:"ipv4" json:"ipv4"`
 	PrivacySandbox  PrivacySandbox   `mapstructure:"privacysandbox" json:"privacysandbox"`
+	Modules         PrivacyModules   `mapstructure:"modules" json:"modules"`
+	// EIDPermissions restrict the bidders receiving the user EIDs of a source
+	EIDPermissions []AccountEIDPermission `mapstructure:"eidpermissions" json:"eidpermissions"`
+	// PrecisionPolicies are the named policies reducing the precision of the IP addresses and geo of the requests
+	// which can't transmit precise geo. The transmitPreciseGeo rules select them by name.
+	PrecisionPolicies map[string]PrecisionPolicy `mapstructure:"precision_policies" json:"precision_policies"`
+	// PrecisionPolicy is the policy applied when no rule selects one. The ipv4 and ipv6 anon_keep_bits apply without
+	// one, with the lat/lon rounded to 2 decimals.
+	PrecisionPolicy string `mapstructure:"precision_policy" json:"precision_policy"`
+	// Pseudonymization replaces the user IDs sent to the selected recipients with pseudonyms
+	Pseudonymization AccountPseudonymization `mapstructure:"pseudonymization" json:"pseudonymization"`
+}
+
+// AccountPseudonymization replaces the user IDs sent to the selected bidders and analytics modules with a keyed HMAC
+// of the account, the recipient and the ID. The pseudonyms are stable for a recipient until the key is rotated, but
+// can't be joined across accounts or recipients.
+type AccountPseudonymization struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// Bidders and Analytics are the bidders and analytics modules receiving the pseudonyms, "*" selecting all of them
+	Bidders   []string `mapstructure:"bidders" json:"bidders"`
+	Analytics []string `mapstructure:"analytics" json:"analytics"`
+	UserID    bool     `mapstructure:"user_id" json:"user_id"`
+	DeviceIFA bool     `mapstructure:"device_ifa" json:"device_ifa"`
+	// EIDSources are the sources of the user EIDs whose IDs are replaced, "*" selecting all of them
+	EIDSources []string `mapstructure:"eid_sources" json:"eid_sources"`
+}
+
+// PrecisionPolicy reduces the precision of the IP addresses and geo. Its unset fields keep the ipv4 and ipv6
+// anon_keep_bits and the rounding of the lat/lon to 2 decimals.
+type PrecisionPolicy struct {
+	IPv4KeepBits *int `mapstructure:"ipv4_keep_bits" json:"ipv4_keep_bits"`
+	IPv6KeepBits *int `mapstructure:"ipv6_keep_bits" json:"ipv6_keep_bits"`
+	GeoDecimals  *int `mapstructure:"geo_decimals" json:"geo_decimals"`
+	DropZip      bool `mapstructure:"drop_zip" json:"drop_zip"`
+	DropMetro    bool `mapstructure:"drop_metro" json:"drop_metro"`
+}
+
+// maxGeoDecimals is the number of decimals of the lat/lon precise to about 1 meter
+const maxGeoDecimals = 5
+
+func (p PrecisionPolicy) Validate(name string, errs []error) []error {
+	if p.IPv4KeepBits != nil && (*p.IPv4KeepBits < 0 || *p.IPv4KeepBits > iputil.IPv4BitSize) {
+		errs = append(errs, fmt.Errorf("privacy.precision_policies.%s.ipv4_keep_bits must be between 0 and %d", name, iputil.IPv4BitSize))
+	}
+	if p.IPv6KeepBits != nil && (*p.IPv6KeepBits < 0 || *p.IPv6KeepBits > iputil.IPv6BitSize) {
+		errs = append(errs, fmt.Errorf("privacy.precision_policies.%s.ipv6_keep_bits must be between 0 and %d", name, iputil.IPv6BitSize))
+	}
+	if p.GeoDecimals != nil && (*p.GeoDecimals < 0 || *p.GeoDecimals > maxGeoDecimals) {
+		errs = append(errs, fmt.Errorf("privacy.precision_policies.%s.geo_decimals must be between 0 and %d", name, maxGeoDecimals))
+	}
+	return errs
+}
+
+// validatePrecisionPolicies validates the precision policies, and that the default policy and the transmitPreciseGeo
+// rules select existing ones
+func (p *AccountPrivacy) validatePrecisionPolicies(errs []error) []error {
+	for name, policy := range p.PrecisionPolicies {
+		errs = policy.Validate(name, errs)
+	}
+	if _, ok := p.PrecisionPolicies[p.PrecisionPolicy]; p.PrecisionPolicy != "" && !ok {
+		errs = append(errs, fmt.Errorf("privacy.precision_policy %s is not a precision policy", p.PrecisionPolicy))
+	}
+	if p.AllowActivities != nil {
+		for _, rule := range p.AllowActivities.TransmitPreciseGeo.Rules {
+			if _, ok := p.PrecisionPolicies[rule.PrecisionPolicy]; rule.PrecisionPolicy != "" && !ok {
+				errs = append(errs, fmt.Errorf("privacy.allowactivities.transmitPreciseGeo rule precision_policy %s is not a precision policy", rule.PrecisionPolicy))
+			}
+		}
+	}
+	return errs
+}
+
+// AccountEIDPermission restricts the bidders receiving the user EIDs of a source, like the request level
+// ext.prebid.data.eidpermissions, optionally only with the consent of the user. The EIDs of the sources without
+// permissions are sent to every bidder.
+type AccountEIDPermission struct {
+	Source string `mapstructure:"source" json:"source"`
+	// Bidders are the bidders allowed to receive the EIDs, or "*" for all of them
+	Bidders []string `mapstructure:"bidders" json:"bidders"`
+	// GDPRPurposes are the purposes the user must consent to in the TCF string for the EIDs to be sent, when GDPR
+	// is enforced
+	GDPRPurposes []int `mapstructure:"gdpr_purposes" json:"gdpr_purposes"`
+	// DenyGPPOptOut removes the EIDs if the user opted out of the sale or sharing of their data, or of targeted
+	// advertising, in a US section of the GPP string which applies to the request
+	DenyGPPOptOut bool `mapstructure:"deny_gpp_opt_out" json:"deny_gpp_opt_out"`
+}
+
+func (p AccountEIDPermission) validate(errs []error) []error {
+	if p.Source == "" {
+		errs = append(errs, errors.New("privacy.eidpermissions: source is required"))
+	}
+	for _, purpose := range p.GDPRPurposes {
+		if purpose < 1 || purpose > 10 {
+			errs = append(errs, fmt.Errorf("privacy.eidpermissions: gdpr purpose %d of source %s must be between 1 and 10", purpose, p.Source))
+		}
+	}
+	return errs
+}
+
+// PrivacyModules configures the privacy modules enforcing the activity controls from the privacy signals of the request
+type PrivacyModules struct {
+	USNat USNatModule `mapstructure:"usnat" json:"usnat"`
+}
+
+// USNatModule enforces the US National and US State sections of the GPP string
+type USNatModule struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// SkipSIDs lists the sections which aren't enforced, like the states enforced by the bidders themselves
+	SkipSIDs []int8 `mapstructure:"skip_sids" json:"skip_sids"`
+	// Normalize maps the US State sections onto the US National section and enforces them alike. It must be true,
+	// since the US State sections aren't enforced otherwise: the states enforced by the bidders are listed in SkipSIDs.
+	Normalize bool `mapstructure:"normalize" json:"normalize"`
+}
+
+func (m *USNatModule) validate(errs []error) []error {
+	if m.Enabled && !m.Normalize {
+		errs = append(errs, errors.New("privacy.modules.usnat.normalize must be true, or the US State sections wouldn't be enforced. List the sections enforced by the bidders in privacy.modules.usnat.skip_sids instead"))
+	}
+	return errs
 }
 
 type PrivacySandbox struct {
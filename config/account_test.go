@@ -7,6 +7,7 @@ import (
 
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -1011,3 +1012,195 @@ func TestIPMaskingValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountAnalyticsSampleRate(t *testing.T) {
+	analytics := AccountAnalytics{
+		Modules: map[string]AccountAnalyticsModule{
+			"disabled": {Enabled: ptrutil.ToPtr(false), SampleRate: ptrutil.ToPtr(1.0)},
+			"sampled": {
+				SampleRate:       ptrutil.ToPtr(0.5),
+				EventSampleRates: map[string]float64{AnalyticsEventAmp: 0.1},
+			},
+			"options": {Options: json.RawMessage(`{"id":"1"}`)},
+		},
+	}
+	defaultDisabled := AccountAnalytics{DefaultEnabled: ptrutil.ToPtr(false), Modules: analytics.Modules}
+
+	tests := []struct {
+		name      string
+		analytics AccountAnalytics
+		module    string
+		eventType string
+		want      float64
+	}{
+		{name: "no-config", analytics: AccountAnalytics{}, module: "any", eventType: AnalyticsEventAuction, want: 1},
+		{name: "unlisted", analytics: analytics, module: "any", eventType: AnalyticsEventAuction, want: 1},
+		{name: "unlisted-default-disabled", analytics: defaultDisabled, module: "any", eventType: AnalyticsEventAuction, want: 0},
+		{name: "listed-default-disabled", analytics: defaultDisabled, module: "options", eventType: AnalyticsEventAuction, want: 1},
+		{name: "disabled", analytics: analytics, module: "disabled", eventType: AnalyticsEventAuction, want: 0},
+		{name: "module-rate", analytics: analytics, module: "sampled", eventType: AnalyticsEventAuction, want: 0.5},
+		{name: "event-rate", analytics: analytics, module: "sampled", eventType: AnalyticsEventAmp, want: 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.analytics.SampleRate(tt.module, tt.eventType))
+		})
+	}
+
+	assert.JSONEq(t, `{"id":"1"}`, string(analytics.ModuleOptions("options")))
+	assert.Nil(t, analytics.ModuleOptions("sampled"))
+}
+
+func TestAccountAnalyticsValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		analytics AccountAnalytics
+		want      []error
+	}{
+		{
+			name: "valid",
+			analytics: AccountAnalytics{Modules: map[string]AccountAnalyticsModule{
+				"module": {SampleRate: ptrutil.ToPtr(0.5), EventSampleRates: map[string]float64{AnalyticsEventAuction: 0, AnalyticsEventSetUID: 1}},
+			}},
+		},
+		{
+			name: "invalid-rates",
+			analytics: AccountAnalytics{Modules: map[string]AccountAnalyticsModule{
+				"module": {SampleRate: ptrutil.ToPtr(1.5), EventSampleRates: map[string]float64{AnalyticsEventAuction: -1}},
+			}},
+			want: []error{
+				errors.New("analytics.modules.module.sample_rate must be between 0 and 1. Got 1.500000"),
+				errors.New("analytics.modules.module.event_sample_rates.auction must be between 0 and 1. Got -1.000000"),
+			},
+		},
+		{
+			name: "unknown-event-type",
+			analytics: AccountAnalytics{Modules: map[string]AccountAnalyticsModule{
+				"module": {EventSampleRates: map[string]float64{"other": 1}},
+			}},
+			want: []error{
+				errors.New("analytics.modules.module.event_sample_rates has an unknown event type other. Valid types are auction, amp, video, setuid, cookie_sync, notification"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.want, tt.analytics.Validate(nil))
+		})
+	}
+}
+
+func TestAccountEIDPermissionValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission AccountEIDPermission
+		want       []error
+	}{
+		{
+			name:       "valid",
+			permission: AccountEIDPermission{Source: "liveramp.com", Bidders: []string{"bidderA"}, GDPRPurposes: []int{1, 4}},
+		},
+		{
+			name:       "missing-source",
+			permission: AccountEIDPermission{Bidders: []string{"*"}},
+			want:       []error{errors.New("privacy.eidpermissions: source is required")},
+		},
+		{
+			name:       "invalid-purpose",
+			permission: AccountEIDPermission{Source: "liveramp.com", GDPRPurposes: []int{0, 11}},
+			want: []error{
+				errors.New("privacy.eidpermissions: gdpr purpose 0 of source liveramp.com must be between 1 and 10"),
+				errors.New("privacy.eidpermissions: gdpr purpose 11 of source liveramp.com must be between 1 and 10"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.want, tt.permission.validate(nil))
+		})
+	}
+}
+
+func TestUSNatModuleValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		module USNatModule
+		want   []error
+	}{
+		{
+			name:   "normalized",
+			module: USNatModule{Enabled: true, Normalize: true},
+		},
+		{
+			name:   "disabled-not-validated",
+			module: USNatModule{Normalize: false},
+		},
+		{
+			name:   "not-normalized",
+			module: USNatModule{Enabled: true, Normalize: false},
+			want:   []error{errors.New("privacy.modules.usnat.normalize must be true, or the US State sections wouldn't be enforced. List the sections enforced by the bidders in privacy.modules.usnat.skip_sids instead")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.module.validate(nil))
+		})
+	}
+}
+
+func TestAccountPrivacyValidatePrecisionPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		privacy AccountPrivacy
+		want    []error
+	}{
+		{
+			name: "valid",
+			privacy: AccountPrivacy{
+				AllowActivities: &AllowActivities{
+					TransmitPreciseGeo: Activity{Rules: []ActivityRule{{PrecisionPolicy: "coarse"}}},
+				},
+				PrecisionPolicies: map[string]PrecisionPolicy{
+					"coarse": {IPv4KeepBits: ptrutil.ToPtr(16), IPv6KeepBits: ptrutil.ToPtr(32), GeoDecimals: ptrutil.ToPtr(1)},
+				},
+				PrecisionPolicy: "coarse",
+			},
+		},
+		{
+			name: "out-of-range",
+			privacy: AccountPrivacy{
+				PrecisionPolicies: map[string]PrecisionPolicy{
+					"coarse": {IPv4KeepBits: ptrutil.ToPtr(33), IPv6KeepBits: ptrutil.ToPtr(-1), GeoDecimals: ptrutil.ToPtr(6)},
+				},
+			},
+			want: []error{
+				errors.New("privacy.precision_policies.coarse.ipv4_keep_bits must be between 0 and 32"),
+				errors.New("privacy.precision_policies.coarse.ipv6_keep_bits must be between 0 and 128"),
+				errors.New("privacy.precision_policies.coarse.geo_decimals must be between 0 and 5"),
+			},
+		},
+		{
+			name: "unknown-policy",
+			privacy: AccountPrivacy{
+				AllowActivities: &AllowActivities{
+					TransmitPreciseGeo: Activity{Rules: []ActivityRule{{PrecisionPolicy: "fine"}}},
+				},
+				PrecisionPolicy: "coarse",
+			},
+			want: []error{
+				errors.New("privacy.precision_policy coarse is not a precision policy"),
+				errors.New("privacy.allowactivities.transmitPreciseGeo rule precision_policy fine is not a precision policy"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.want, tt.privacy.validatePrecisionPolicies(nil))
+		})
+	}
+}
@@ -19,9 +19,27 @@ type Activity struct {
 type ActivityRule struct {
 	Condition ActivityCondition `mapstructure:"condition" json:"condition"`
 	Allow     bool              `mapstructure:"allow" json:"allow"`
+	// PrecisionPolicy names the precision policy of the account applied when the rule denies transmitPreciseGeo
+	PrecisionPolicy string `mapstructure:"precision_policy" json:"precision_policy"`
 }
 
+// ActivityCondition matches the rule to the activities whose component and request satisfy all of its clauses. Clauses
+// which aren't set match any activity.
 type ActivityCondition struct {
 	ComponentName []string `mapstructure:"componentName" json:"componentName"`
 	ComponentType []string `mapstructure:"componentType" json:"componentType"`
+	// GPPSID matches the requests listing any of the GPP section ids as applicable
+	GPPSID []int8 `mapstructure:"gppSid" json:"gppSid"`
+	// Geo matches the requests from any of the countries, as ISO-3166-1 alpha-3 codes, or country regions, like "USA.CA"
+	Geo []string `mapstructure:"geo" json:"geo"`
+	// GPC matches the requests whose Global Privacy Control signal is set, or unset if false
+	GPC *bool `mapstructure:"gpc" json:"gpc"`
+	// COPPA matches the requests subject to COPPA, or not subject to it if false
+	COPPA *bool `mapstructure:"coppa" json:"coppa"`
+	// TCFInScope matches the requests in the scope of TCF, or out of its scope if false
+	TCFInScope *bool `mapstructure:"tcfInScope" json:"tcfInScope"`
+	// Channel matches the requests from any of the channels, like "web", "app" or "amp". The channel is the one of
+	// ext.prebid.channel, or else the one of the endpoint for amp and video, or else "app", "dooh" or "web" depending
+	// on the object the request has.
+	Channel []string `mapstructure:"channel" json:"channel"`
 }
@@ -49,6 +49,8 @@ type BidderInfo struct {
 	AppSecret  string `yaml:"app_secret" mapstructure:"app_secret"`
 	// EndpointCompression determines, if set, the type of compression the bid request will undergo before being sent to the corresponding bid server
 	EndpointCompression string `yaml:"endpointCompression" mapstructure:"endpointCompression"`
+
+	Tracing BidderInfoTracing `yaml:"tracing" mapstructure:"tracing"`
 }
 
 type aliasNillableFields struct {
@@ -56,6 +58,7 @@ type aliasNillableFields struct {
 	ModifyingVastXmlAllowed *bool                 `yaml:"modifyingVastXmlAllowed" mapstructure:"modifyingVastXmlAllowed"`
 	Experiment              *BidderInfoExperiment `yaml:"experiment" mapstructure:"experiment"`
 	XAPI                    *AdapterXAPI          `yaml:"xapi" mapstructure:"xapi"`
+	Tracing                 *BidderInfoTracing    `yaml:"tracing" mapstructure:"tracing"`
 }
 
 // BidderInfoExperiment specifies non-production ready feature config for a bidder
@@ -63,6 +66,12 @@ type BidderInfoExperiment struct {
 	AdsCert BidderAdsCert `yaml:"adsCert" mapstructure:"adsCert"`
 }
 
+// BidderInfoTracing configures the tracing of the requests to a bidder
+type BidderInfoTracing struct {
+	// PropagateTraceContext sends the W3C traceparent header of the auction to the bidder, so its spans join the trace
+	PropagateTraceContext bool `yaml:"propagateTraceContext" mapstructure:"propagateTraceContext"`
+}
+
 // BidderAdsCert enables Call Sign feature for bidder
 type BidderAdsCert struct {
 	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
@@ -423,6 +432,9 @@ func processBidderAliases(aliasNillableFieldsByBidder map[string]aliasNillableFi
 		if alias.XAPI == nil {
 			aliasBidderInfo.XAPI = parentBidderInfo.XAPI
 		}
+		if alias.Tracing == nil {
+			aliasBidderInfo.Tracing = parentBidderInfo.Tracing
+		}
 		bidderInfos[bidderName] = aliasBidderInfo
 	}
 	return bidderInfos, nil
@@ -757,6 +769,9 @@ func applyBidderInfoConfigOverrides(configBidderInfos nillableFieldBidderInfos,
 		if configBidderInfo.bidderInfo.EndpointCompression != "" {
 			mergedBidderInfo.EndpointCompression = configBidderInfo.bidderInfo.EndpointCompression
 		}
+		if configBidderInfo.bidderInfo.Tracing.PropagateTraceContext {
+			mergedBidderInfo.Tracing.PropagateTraceContext = true
+		}
 		if configBidderInfo.bidderInfo.OpenRTB != nil {
 			mergedBidderInfo.OpenRTB = configBidderInfo.bidderInfo.OpenRTB
 		}
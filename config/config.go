@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/errortypes"
@@ -16,6 +17,7 @@ import (
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/spf13/viper"
+	"golang.org/x/text/currency"
 )
 
 // Configuration specifies the static application config.
@@ -36,7 +38,9 @@ type Configuration struct {
 	GarbageCollectorThreshold int `mapstructure:"garbage_collector_threshold"`
 	// StatusResponse is the string which will be returned by the /status endpoint when things are OK.
 	// If empty, it will return a 204 with no content.
-	StatusResponse    string          `mapstructure:"status_response"`
+	StatusResponse string `mapstructure:"status_response"`
+	// Readiness configures the /status/ready endpoint, which checks the dependencies of Prebid Server
+	Readiness         Readiness       `mapstructure:"readiness"`
 	AuctionTimeouts   AuctionTimeouts `mapstructure:"auction_timeouts_ms"`
 	TmaxAdjustments   TmaxAdjustments `mapstructure:"tmax_adjustments"`
 	TmaxDefault       int             `mapstructure:"tmax_default"`
@@ -85,6 +89,14 @@ type Configuration struct {
 	RequestTimeoutHeaders RequestTimeoutHeaders `mapstructure:"request_timeout_headers"`
 	// Debug/logging flags go here
 	Debug Debug `mapstructure:"debug"`
+	// Logging configures the application logs
+	Logging Logging `mapstructure:"logging"`
+	// Tracing exports the traces of the requests with OpenTelemetry
+	Tracing Tracing `mapstructure:"tracing"`
+	// Pseudonymization configures the keys of the pseudonymous user IDs sent to the recipients selected by the accounts
+	Pseudonymization Pseudonymization `mapstructure:"pseudonymization"`
+	// OptOut configures the registry of the users who objected to the processing of their data
+	OptOut OptOut `mapstructure:"optout"`
 	// RequestValidation specifies the request validation options.
 	RequestValidation RequestValidation `mapstructure:"request_validation"`
 	// When true, PBS will assign a randomly generated UUID to req.Source.TID if it is empty
@@ -171,8 +183,16 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.GDPR.validate(v, errs)
 	errs = cfg.CurrencyConverter.validate(errs)
 	errs = cfg.Debug.validate(errs)
+	errs = cfg.Logging.validate(errs)
+	errs = cfg.Tracing.validate(errs)
+	errs = cfg.Readiness.validate(errs)
+	errs = cfg.Pseudonymization.validate(errs)
+	errs = cfg.OptOut.validate(errs)
+	errs = cfg.UserSync.IDStore.validate(errs)
+	errs = cfg.HostCookie.validate(errs)
 	errs = cfg.ExtCacheURL.validate(errs)
 	errs = cfg.AccountDefaults.PriceFloors.validate(errs)
+	errs = appendAccountDefaultsErrors(errs, cfg.AccountDefaults.Validate(nil))
 	if cfg.AccountDefaults.Disabled {
 		logger.Warnf(`With account_defaults.disabled=true, host-defined accounts must exist and have "disabled":false. All other requests will be rejected.`)
 	}
@@ -181,6 +201,10 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 		logger.Warnf(`account_defaults.events has no effect as the feature is under development.`)
 	}
 
+	if cfg.AccountDefaults.Parent != "" {
+		errs = append(errs, errors.New("account_defaults.parent must be empty. The account defaults are always applied beneath the root of an account hierarchy"))
+	}
+
 	errs = cfg.Experiment.validate(errs)
 	errs = cfg.BidderInfos.validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv6Config.Validate(errs)
@@ -189,6 +213,15 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	return errs
 }
 
+// appendAccountDefaultsErrors appends the errors of the account defaults, prefixing their messages which are relative
+// to the account
+func appendAccountDefaultsErrors(errs []error, accountErrs []error) []error {
+	for _, err := range accountErrs {
+		errs = append(errs, fmt.Errorf("account_defaults.%w", err))
+	}
+	return errs
+}
+
 type AuctionTimeouts struct {
 	// The default timeout is used if the user's request didn't define one. Use 0 if there's no default.
 	Default uint64 `mapstructure:"default"`
@@ -278,6 +311,18 @@ type GDPR struct {
 	// to DefaultValue
 	EEACountries    []string `mapstructure:"eea_countries"`
 	EEACountriesMap map[string]struct{}
+	// VendorListCache configures where the vendor lists are persisted, and loaded from at startup
+	VendorListCache GDPRVendorListCache `mapstructure:"vendorlist_cache"`
+}
+
+// GDPRVendorListCache configures the on-disk vendor lists, which follow the layout of the vendor list URLs, like
+// "v3/vendor-list-v42.json". They are loaded at startup before any network fetch, so TCF can be enforced without
+// network access.
+type GDPRVendorListCache struct {
+	// Dir is where the fetched vendor lists are persisted. Persistence is disabled if empty.
+	Dir string `mapstructure:"dir"`
+	// SnapshotDir holds the vendor lists bundled with Prebid Server, loaded after the lists of Dir
+	SnapshotDir string `mapstructure:"snapshot_dir"`
 }
 
 func (cfg *GDPR) validate(v *viper.Viper, errs []error) []error {
@@ -474,9 +519,11 @@ type LMT struct {
 }
 
 type Analytics struct {
-	File     FileLogs      `mapstructure:"file"`
-	Agma     AgmaAnalytics `mapstructure:"agma"`
-	Pubstack Pubstack      `mapstructure:"pubstack"`
+	File     FileLogs         `mapstructure:"file"`
+	Agma     AgmaAnalytics    `mapstructure:"agma"`
+	Pubstack Pubstack         `mapstructure:"pubstack"`
+	HTTP     HTTPAnalytics    `mapstructure:"http"`
+	Parquet  ParquetAnalytics `mapstructure:"parquet"`
 }
 
 type CurrencyConverter struct {
@@ -521,9 +568,106 @@ type AgmaAnalyticsAccount struct {
 	SiteAppId   string `mapstructure:"site_app_id"`
 }
 
+// HTTPAnalytics configures the generic http analytics module, which posts batches of gzipped JSON lines to an endpoint
+type HTTPAnalytics struct {
+	Enabled  bool                  `mapstructure:"enabled"`
+	Endpoint HTTPAnalyticsEndpoint `mapstructure:"endpoint"`
+	Buffers  HTTPAnalyticsBuffer   `mapstructure:"buffers"`
+	Retry    HTTPAnalyticsRetry    `mapstructure:"retry"`
+	Events   HTTPAnalyticsEvents   `mapstructure:"events"`
+	// Accounts limits the module to the events of these accounts. All accounts are logged if empty.
+	Accounts []string `mapstructure:"accounts"`
+}
+
+type HTTPAnalyticsEndpoint struct {
+	Url     string            `mapstructure:"url"`
+	Timeout string            `mapstructure:"timeout"`
+	Headers map[string]string `mapstructure:"headers"`
+}
+
+type HTTPAnalyticsBuffer struct {
+	BufferSize string `mapstructure:"size"`
+	EventCount int    `mapstructure:"count"`
+	Timeout    string `mapstructure:"timeout"`
+}
+
+// HTTPAnalyticsRetry configures the retries of failed batches. The backoff doubles after every attempt, up to MaxBackoff.
+type HTTPAnalyticsRetry struct {
+	MaxRetries int    `mapstructure:"max_retries"`
+	Backoff    string `mapstructure:"backoff"`
+	MaxBackoff string `mapstructure:"max_backoff"`
+}
+
+type HTTPAnalyticsEvents struct {
+	Auction      HTTPAnalyticsEvent `mapstructure:"auction"`
+	AMP          HTTPAnalyticsEvent `mapstructure:"amp"`
+	Video        HTTPAnalyticsEvent `mapstructure:"video"`
+	SetUID       HTTPAnalyticsEvent `mapstructure:"setuid"`
+	CookieSync   HTTPAnalyticsEvent `mapstructure:"cookie_sync"`
+	Notification HTTPAnalyticsEvent `mapstructure:"notification"`
+}
+
+type HTTPAnalyticsEvent struct {
+	Enabled bool `mapstructure:"enabled"`
+	// SampleRate is the share of events which are logged, between 0 and 1
+	SampleRate float64 `mapstructure:"sample_rate"`
+	// Fields are the dot separated paths of the event fields to log, e.g. "request.site.page". All fields are logged if empty.
+	Fields []string `mapstructure:"fields"`
+}
+
+// ParquetAnalytics configures the export of the auctions to Parquet files, with a row per imp, bidder and bid
+type ParquetAnalytics struct {
+	Enabled   bool                     `mapstructure:"enabled"`
+	Directory string                   `mapstructure:"directory"`
+	Rotation  ParquetAnalyticsRotation `mapstructure:"rotation"`
+	// QueueSize is the number of auctions waiting to be written, above which auctions are dropped
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+// ParquetAnalyticsRotation configures when a Parquet file is closed and a new one started, whichever comes first
+type ParquetAnalyticsRotation struct {
+	MaxRows int    `mapstructure:"max_rows"`
+	MaxAge  string `mapstructure:"max_age"`
+}
+
 // FileLogs Corresponding config for FileLogger as a PBS Analytics Module
 type FileLogs struct {
+	// Filename enables the legacy file logger, which writes every event to a single daily file. Deprecated: use Directory.
 	Filename string `mapstructure:"filename"`
+	// Directory enables the JSON lines file logger, which writes the events of each type to rotated files in this directory
+	Directory string           `mapstructure:"directory"`
+	Rotation  FileLogsRotation `mapstructure:"rotation"`
+	Buffers   FileLogsBuffer   `mapstructure:"buffers"`
+	Events    FileLogsEvents   `mapstructure:"events"`
+}
+
+// FileLogsRotation configures when the file of an event type is closed and a new one started, whichever comes first
+type FileLogsRotation struct {
+	MaxSize string `mapstructure:"max_size"`
+	MaxAge  string `mapstructure:"max_age"`
+	// Compress gzips the closed files
+	Compress bool `mapstructure:"compress"`
+}
+
+type FileLogsBuffer struct {
+	// QueueSize is the number of events waiting to be written, above which events are dropped
+	QueueSize     int    `mapstructure:"queue_size"`
+	FlushInterval string `mapstructure:"flush_interval"`
+}
+
+type FileLogsEvents struct {
+	Auction      FileLogsEvent `mapstructure:"auction"`
+	AMP          FileLogsEvent `mapstructure:"amp"`
+	Video        FileLogsEvent `mapstructure:"video"`
+	SetUID       FileLogsEvent `mapstructure:"setuid"`
+	CookieSync   FileLogsEvent `mapstructure:"cookie_sync"`
+	Notification FileLogsEvent `mapstructure:"notification"`
+}
+
+type FileLogsEvent struct {
+	Enabled bool `mapstructure:"enabled"`
+	// SampleRate is the share of events which are logged, between 0 and 1
+	SampleRate float64 `mapstructure:"sample_rate"`
 }
 
 type Pubstack struct {
@@ -560,6 +704,11 @@ type HostCookie struct {
 	OptOutCookie       Cookie `mapstructure:"optout_cookie"`
 	// Cookie timeout in days
 	TTL int64 `mapstructure:"ttl_days"`
+	// Mode is the mode of the uids cookie, third_party or partitioned. The accounts may override it.
+	Mode UIDsCookieMode `mapstructure:"mode"`
+	// UIDsCookieName is the name of the uids cookie, uids if empty. It's only set by the accounts in the first_party
+	// mode.
+	UIDsCookieName string `mapstructure:"-"`
 }
 
 func (cfg *HostCookie) TTLDuration() time.Duration {
@@ -574,6 +723,9 @@ type RequestTimeoutHeaders struct {
 type Metrics struct {
 	Influxdb   InfluxMetrics     `mapstructure:"influxdb"`
 	Prometheus PrometheusMetrics `mapstructure:"prometheus"`
+	OTLP       OTLPMetrics       `mapstructure:"otlp"`
+	StatsD     StatsDMetrics     `mapstructure:"statsd"`
+	Auction    AuctionMetrics    `mapstructure:"auction"`
 	Disabled   DisabledMetrics   `mapstructure:"disabled_metrics"`
 }
 
@@ -606,7 +758,10 @@ type DisabledMetrics struct {
 }
 
 func (cfg *Metrics) validate(errs []error) []error {
-	return cfg.Prometheus.validate(errs)
+	errs = cfg.Prometheus.validate(errs)
+	errs = cfg.OTLP.validate(errs)
+	errs = cfg.StatsD.validate(errs)
+	return cfg.Auction.validate(errs)
 }
 
 type InfluxMetrics struct {
@@ -637,6 +792,100 @@ func (m *PrometheusMetrics) Timeout() time.Duration {
 	return time.Duration(m.TimeoutMillisRaw) * time.Millisecond
 }
 
+// OTLPMetrics configures the export of the metrics to an OpenTelemetry collector
+type OTLPMetrics struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Host and port of the OTLP/HTTP endpoint of the collector
+	Endpoint string `mapstructure:"endpoint"`
+	// Export over HTTP rather than HTTPS
+	Insecure    bool   `mapstructure:"insecure"`
+	ServiceName string `mapstructure:"service_name"`
+	// Interval between two exports of the metrics
+	ExportIntervalMS int `mapstructure:"export_interval_ms"`
+	// Timeout of an export
+	TimeoutMS int `mapstructure:"timeout_ms"`
+}
+
+func (cfg *OTLPMetrics) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Endpoint == "" {
+		errs = append(errs, errors.New("metrics.otlp.endpoint is required when the OTLP metrics are enabled"))
+	}
+	if cfg.ServiceName == "" {
+		errs = append(errs, errors.New("metrics.otlp.service_name is required when the OTLP metrics are enabled"))
+	}
+	if cfg.ExportIntervalMS <= 0 {
+		errs = append(errs, fmt.Errorf("metrics.otlp.export_interval_ms must be positive. Got %d", cfg.ExportIntervalMS))
+	}
+	if cfg.TimeoutMS <= 0 || cfg.TimeoutMS > cfg.ExportIntervalMS {
+		errs = append(errs, fmt.Errorf("metrics.otlp.timeout_ms must be positive and at most metrics.otlp.export_interval_ms. Got %d", cfg.TimeoutMS))
+	}
+	return errs
+}
+
+// StatsDMetrics configures the metrics sent to a StatsD or DogStatsD agent
+type StatsDMetrics struct {
+	// Address of the agent, as host:port over UDP or unix:///path/to/socket over a Unix socket. The metrics aren't
+	// sent if empty.
+	Address string `mapstructure:"address"`
+	// Prefix of the names of the metrics
+	Namespace string `mapstructure:"namespace"`
+	// Tags added to all the metrics, as key:value
+	Tags []string `mapstructure:"tags"`
+	// Fraction of the timings and histograms values sent to the agent
+	SampleRate float64 `mapstructure:"sample_rate"`
+	// Interval of the client side aggregation of the counters
+	AggregationIntervalMS int `mapstructure:"aggregation_interval_ms"`
+	// Aggregate the timings and histograms client side too. Requires a DogStatsD agent.
+	ExtendedAggregation bool `mapstructure:"extended_aggregation"`
+}
+
+func (cfg *StatsDMetrics) validate(errs []error) []error {
+	if cfg.Address == "" {
+		return errs
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf("metrics.statsd.sample_rate must be greater than 0 and at most 1. Got %f", cfg.SampleRate))
+	}
+	if cfg.AggregationIntervalMS <= 0 {
+		errs = append(errs, fmt.Errorf("metrics.statsd.aggregation_interval_ms must be positive. Got %d", cfg.AggregationIntervalMS))
+	}
+	return errs
+}
+
+// AuctionMetrics configures the metrics of the bids which took part in the auction, recorded once it is decided
+type AuctionMetrics struct {
+	// Currency the CPM of the bids is converted to
+	Currency string `mapstructure:"currency"`
+	// Upper bounds of the buckets of the bid CPM histograms. Only the Prometheus and OTLP engines use them: the
+	// go-metrics histograms are sampled, and StatsD sends the raw values to be aggregated by the agent.
+	PriceBuckets []float64 `mapstructure:"price_buckets"`
+	// Upper bounds of the buckets of the bid to floor ratio histograms. Only the Prometheus and OTLP engines use them.
+	FloorRatioBuckets []float64 `mapstructure:"floor_ratio_buckets"`
+}
+
+func (cfg *AuctionMetrics) validate(errs []error) []error {
+	if _, err := currency.ParseISO(cfg.Currency); err != nil {
+		errs = append(errs, fmt.Errorf("metrics.auction.currency must be an ISO 4217 currency code. Got %s", cfg.Currency))
+	}
+	errs = validateBuckets(errs, "metrics.auction.price_buckets", cfg.PriceBuckets)
+	return validateBuckets(errs, "metrics.auction.floor_ratio_buckets", cfg.FloorRatioBuckets)
+}
+
+func validateBuckets(errs []error, name string, buckets []float64) []error {
+	if len(buckets) == 0 {
+		return append(errs, fmt.Errorf("%s must not be empty", name))
+	}
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return append(errs, fmt.Errorf("%s must be in increasing order. Got %v", name, buckets))
+		}
+	}
+	return errs
+}
+
 // ExternalCache configures the externally accessible cache url.
 type ExternalCache struct {
 	Scheme string `mapstructure:"scheme"`
@@ -708,6 +957,221 @@ func (cfg *Debug) validate(errs []error) []error {
 	return cfg.TimeoutNotification.validate(errs)
 }
 
+// Log formats
+const (
+	LogFormatGlog = "glog"
+	LogFormatJSON = "json"
+)
+
+// Logging selects the format of the application logs
+type Logging struct {
+	// Format is "glog", the default, or "json" to log JSON objects with the fields of the requests, like their ID
+	// and account
+	Format string `mapstructure:"format"`
+	// Level is the minimum level of the json logs: debug, info, warn or error. Glog is configured by its flags.
+	Level     string           `mapstructure:"level"`
+	RateLimit LoggingRateLimit `mapstructure:"rate_limit"`
+	AccessLog AccessLog        `mapstructure:"access_log"`
+}
+
+// LoggingRateLimit limits how many times the same message is logged, in both formats
+type LoggingRateLimit struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Burst is the number of times a message is logged per interval
+	Burst    int    `mapstructure:"burst"`
+	Interval string `mapstructure:"interval"`
+}
+
+// Anonymization modes of the access log
+const (
+	AccessLogAnonymizeNever   = "never"
+	AccessLogAnonymizePrivacy = "privacy"
+	AccessLogAnonymizeAlways  = "always"
+)
+
+// AccessLog writes a JSON line per request to the public endpoints: /openrtb2/*, /cookie_sync, /setuid, /event and
+// /vtrack
+type AccessLog struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Filename is the file being written. Rotated files are named after the time they were started, e.g.
+	// "access-20240102T030405.000000000Z.log" for "access.log".
+	Filename string `mapstructure:"filename"`
+	// MaxSize is the size of the file above which it's rotated, like "100MB"
+	MaxSize string `mapstructure:"max_size"`
+	// MaxBackups is the number of rotated files kept, or 0 to keep all of them
+	MaxBackups int `mapstructure:"max_backups"`
+	// SampleRate is the share of requests which are logged, between 0 and 1
+	SampleRate float64 `mapstructure:"sample_rate"`
+	// Anonymize is "privacy" to mask the IP and drop the user agent of the requests restricted by their privacy signals,
+	// "always" or "never". The IP keeps the bits configured by the privacy settings of the account.
+	Anonymize string `mapstructure:"anonymize"`
+	// QueueSize is the number of entries waiting to be written, above which entries are dropped
+	QueueSize     int    `mapstructure:"queue_size"`
+	FlushInterval string `mapstructure:"flush_interval"`
+}
+
+func (cfg *AccessLog) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Filename == "" {
+		errs = append(errs, errors.New("logging.access_log.filename is required when the access log is enabled"))
+	}
+	if maxSize, err := units.FromHumanSize(cfg.MaxSize); err != nil {
+		errs = append(errs, fmt.Errorf("logging.access_log.max_size: %v", err))
+	} else if maxSize <= 0 {
+		errs = append(errs, fmt.Errorf("logging.access_log.max_size must be positive. Got %s", cfg.MaxSize))
+	}
+	if cfg.MaxBackups < 0 {
+		errs = append(errs, fmt.Errorf("logging.access_log.max_backups must be 0 or greater. Got %d", cfg.MaxBackups))
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf("logging.access_log.sample_rate must be between 0 and 1. Got %f", cfg.SampleRate))
+	}
+	switch cfg.Anonymize {
+	case AccessLogAnonymizeNever, AccessLogAnonymizePrivacy, AccessLogAnonymizeAlways:
+	default:
+		errs = append(errs, fmt.Errorf("logging.access_log.anonymize must be %s, %s or %s. Got %s", AccessLogAnonymizeNever, AccessLogAnonymizePrivacy, AccessLogAnonymizeAlways, cfg.Anonymize))
+	}
+	if cfg.QueueSize < 0 {
+		errs = append(errs, fmt.Errorf("logging.access_log.queue_size must be 0 or greater. Got %d", cfg.QueueSize))
+	}
+	if interval, err := time.ParseDuration(cfg.FlushInterval); err != nil {
+		errs = append(errs, fmt.Errorf("logging.access_log.flush_interval: %v", err))
+	} else if interval <= 0 {
+		errs = append(errs, fmt.Errorf("logging.access_log.flush_interval must be positive. Got %s", cfg.FlushInterval))
+	}
+	return errs
+}
+
+func (cfg *Logging) validate(errs []error) []error {
+	switch cfg.Format {
+	case "", LogFormatGlog:
+	case LogFormatJSON:
+		if _, err := logger.ParseLevel(cfg.Level); err != nil {
+			errs = append(errs, fmt.Errorf("logging.level: %v", err))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("logging.format must be %s or %s. Got %s", LogFormatGlog, LogFormatJSON, cfg.Format))
+	}
+	if cfg.RateLimit.Enabled {
+		if cfg.RateLimit.Burst <= 0 {
+			errs = append(errs, fmt.Errorf("logging.rate_limit.burst must be positive. Got %d", cfg.RateLimit.Burst))
+		}
+		if interval, err := time.ParseDuration(cfg.RateLimit.Interval); err != nil {
+			errs = append(errs, fmt.Errorf("logging.rate_limit.interval: %v", err))
+		} else if interval <= 0 {
+			errs = append(errs, fmt.Errorf("logging.rate_limit.interval must be positive. Got %s", cfg.RateLimit.Interval))
+		}
+	}
+	return cfg.AccessLog.validate(errs)
+}
+
+// Tracing exports the spans of the requests to an OpenTelemetry collector, over OTLP/HTTP
+type Tracing struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint is the host and port of the collector, like "localhost:4318"
+	Endpoint string `mapstructure:"endpoint"`
+	// Insecure exports over HTTP rather than HTTPS
+	Insecure    bool   `mapstructure:"insecure"`
+	ServiceName string `mapstructure:"service_name"`
+	// SampleRate is the share of the traces started by Prebid Server which are sampled, between 0 and 1. The traces
+	// continued from a traceparent header follow the sampling decision of the caller.
+	SampleRate float64 `mapstructure:"sample_rate"`
+	// Timeout bounds each export to the collector
+	TimeoutMS int `mapstructure:"timeout_ms"`
+}
+
+func (cfg *Tracing) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Endpoint == "" {
+		errs = append(errs, errors.New("tracing.endpoint is required when tracing is enabled"))
+	}
+	if cfg.ServiceName == "" {
+		errs = append(errs, errors.New("tracing.service_name is required when tracing is enabled"))
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_rate must be between 0 and 1. Got %f", cfg.SampleRate))
+	}
+	if cfg.TimeoutMS <= 0 {
+		errs = append(errs, fmt.Errorf("tracing.timeout_ms must be positive. Got %d", cfg.TimeoutMS))
+	}
+	return errs
+}
+
+type Readiness struct {
+	Enabled bool `mapstructure:"enabled"`
+	// TimeoutMS bounds the time given to all the checks of a readiness request
+	TimeoutMS int `mapstructure:"timeout_ms"`
+	// Critical lists the checks which must pass for Prebid Server to be ready. A check is listed by its name, like
+	// "gdpr_vendor_list", or by its group, like "stored_requests" for "stored_requests.http_events". The other
+	// checks are reported without affecting the readiness.
+	Critical []string `mapstructure:"critical"`
+}
+
+func (cfg *Readiness) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.TimeoutMS <= 0 {
+		errs = append(errs, fmt.Errorf("readiness.timeout_ms must be positive. Got %d", cfg.TimeoutMS))
+	}
+	for _, check := range cfg.Critical {
+		if check == "" {
+			errs = append(errs, errors.New("readiness.critical must not contain empty check names"))
+			break
+		}
+	}
+	return errs
+}
+
+// Pseudonymization configures the keys of the pseudonymous user IDs. The keys are the files of KeyDir named after
+// the UTC date they're active from, like "2026-10-01.key", the latest active key being used. The files are reloaded
+// every RefreshIntervalSeconds, so the keys can be rotated by adding the files of the next ones ahead of time.
+type Pseudonymization struct {
+	KeyDir                 string `mapstructure:"key_dir"`
+	RefreshIntervalSeconds int    `mapstructure:"refresh_interval_seconds"`
+}
+
+func (cfg *Pseudonymization) validate(errs []error) []error {
+	if cfg.KeyDir != "" && cfg.RefreshIntervalSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("pseudonymization.refresh_interval_seconds must be positive. Got %d", cfg.RefreshIntervalSeconds))
+	}
+	return errs
+}
+
+// OptOut configures the registry of the device IFAs, hashed emails and user EIDs of the users who objected to the
+// processing of their data. The auctions of these users deny every privacy activity but fetchBids, so their IDs are
+// scrubbed from the bidder requests and the analytics aren't reported.
+//
+// The entries are loaded from File, one per line, which is reloaded when it's modified, and from the stored requests
+// of HTTPEvents, whose IDs are the entries. They can also be added and removed through the admin endpoint, which
+// writes them to File, if any.
+type OptOut struct {
+	Enabled bool   `mapstructure:"enabled"`
+	File    string `mapstructure:"file"`
+	// RefreshIntervalSeconds is the interval at which File is checked for modifications
+	RefreshIntervalSeconds int `mapstructure:"refresh_interval_seconds"`
+	// HTTPEvents reuses the HTTP events API of the stored requests: the entries are the IDs of the "requests" it
+	// returns, whose data is ignored
+	HTTPEvents HTTPEventsConfig `mapstructure:"http_events"`
+}
+
+func (cfg *OptOut) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.File != "" && cfg.RefreshIntervalSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("optout.refresh_interval_seconds must be positive. Got %d", cfg.RefreshIntervalSeconds))
+	}
+	if cfg.HTTPEvents.Endpoint != "" && cfg.HTTPEvents.RefreshRate <= 0 {
+		errs = append(errs, fmt.Errorf("optout.http_events.refresh_rate_seconds must be positive. Got %d", cfg.HTTPEvents.RefreshRate))
+	}
+	return errs
+}
+
 type TimeoutNotification struct {
 	// Log timeout notifications in the application log
 	Log bool `mapstructure:"log"`
@@ -966,6 +1430,7 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("host_cookie.value", "")
 	v.SetDefault("host_cookie.ttl_days", 90)
 	v.SetDefault("host_cookie.max_cookie_size_bytes", 0)
+	v.SetDefault("host_cookie.mode", "third_party")
 	v.SetDefault("host_schain_node", nil)
 	v.SetDefault("validations.banner_creative_max_size", ValidationSkip)
 	v.SetDefault("validations.secure_markup", ValidationSkip)
@@ -1011,6 +1476,21 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("metrics.prometheus.namespace", "")
 	v.SetDefault("metrics.prometheus.subsystem", "")
 	v.SetDefault("metrics.prometheus.timeout_ms", 10000)
+	v.SetDefault("metrics.otlp.enabled", false)
+	v.SetDefault("metrics.otlp.endpoint", "localhost:4318")
+	v.SetDefault("metrics.otlp.insecure", false)
+	v.SetDefault("metrics.otlp.service_name", "prebid-server")
+	v.SetDefault("metrics.otlp.export_interval_ms", 60000)
+	v.SetDefault("metrics.otlp.timeout_ms", 10000)
+	v.SetDefault("metrics.statsd.address", "")
+	v.SetDefault("metrics.statsd.namespace", "prebidserver.")
+	v.SetDefault("metrics.statsd.tags", []string{})
+	v.SetDefault("metrics.statsd.sample_rate", 1.0)
+	v.SetDefault("metrics.statsd.aggregation_interval_ms", 2000)
+	v.SetDefault("metrics.statsd.extended_aggregation", false)
+	v.SetDefault("metrics.auction.currency", "USD")
+	v.SetDefault("metrics.auction.price_buckets", []float64{0.1, 0.25, 0.5, 0.75, 1, 1.5, 2, 3, 5, 10, 20, 50})
+	v.SetDefault("metrics.auction.floor_ratio_buckets", []float64{0.5, 0.8, 1, 1.1, 1.25, 1.5, 2, 3, 5, 10})
 	v.SetDefault("category_mapping.filesystem.enabled", true)
 	v.SetDefault("category_mapping.filesystem.directorypath", "./static/category-mapping")
 	v.SetDefault("category_mapping.http.endpoint", "")
@@ -1045,11 +1525,16 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_requests.in_memory_cache.request_cache_size_bytes", 0)
 	v.SetDefault("stored_requests.in_memory_cache.imp_cache_size_bytes", 0)
 	v.SetDefault("stored_requests.in_memory_cache.resp_cache_size_bytes", 0)
+	v.SetDefault("stored_requests.in_memory_cache.not_found_ttl_seconds", 0)
+	v.SetDefault("stored_requests.in_memory_cache.stale_while_revalidate_seconds", 0)
+	v.SetDefault("stored_requests.in_memory_cache.stale_if_error_seconds", 0)
 	v.SetDefault("stored_requests.cache_events_api", false)
 	v.SetDefault("stored_requests.http_events.endpoint", "")
 	v.SetDefault("stored_requests.http_events.amp_endpoint", "")
 	v.SetDefault("stored_requests.http_events.refresh_rate_seconds", 0)
 	v.SetDefault("stored_requests.http_events.timeout_ms", 0)
+	v.SetDefault("stored_requests.validation.enabled", false)
+	v.SetDefault("stored_requests.validation.quarantine", false)
 	// stored_video is short for stored_video_requests.
 	// PBS is not in the business of storing video content beyond the normal prebid cache system.
 	v.SetDefault("stored_video_req.database.connection.driver", "")
@@ -1079,11 +1564,16 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_video_req.in_memory_cache.request_cache_size_bytes", 0)
 	v.SetDefault("stored_video_req.in_memory_cache.imp_cache_size_bytes", 0)
 	v.SetDefault("stored_video_req.in_memory_cache.resp_cache_size_bytes", 0)
+	v.SetDefault("stored_video_req.in_memory_cache.not_found_ttl_seconds", 0)
+	v.SetDefault("stored_video_req.in_memory_cache.stale_while_revalidate_seconds", 0)
+	v.SetDefault("stored_video_req.in_memory_cache.stale_if_error_seconds", 0)
 	v.SetDefault("stored_video_req.cache_events.enabled", false)
 	v.SetDefault("stored_video_req.cache_events.endpoint", "")
 	v.SetDefault("stored_video_req.http_events.endpoint", "")
 	v.SetDefault("stored_video_req.http_events.refresh_rate_seconds", 0)
 	v.SetDefault("stored_video_req.http_events.timeout_ms", 0)
+	v.SetDefault("stored_video_req.validation.enabled", false)
+	v.SetDefault("stored_video_req.validation.quarantine", false)
 	v.SetDefault("stored_responses.database.connection.driver", "")
 	v.SetDefault("stored_responses.database.connection.dbname", "")
 	v.SetDefault("stored_responses.database.connection.host", "")
@@ -1111,11 +1601,16 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_responses.in_memory_cache.request_cache_size_bytes", 0)
 	v.SetDefault("stored_responses.in_memory_cache.imp_cache_size_bytes", 0)
 	v.SetDefault("stored_responses.in_memory_cache.resp_cache_size_bytes", 0)
+	v.SetDefault("stored_responses.in_memory_cache.not_found_ttl_seconds", 0)
+	v.SetDefault("stored_responses.in_memory_cache.stale_while_revalidate_seconds", 0)
+	v.SetDefault("stored_responses.in_memory_cache.stale_if_error_seconds", 0)
 	v.SetDefault("stored_responses.cache_events.enabled", false)
 	v.SetDefault("stored_responses.cache_events.endpoint", "")
 	v.SetDefault("stored_responses.http_events.endpoint", "")
 	v.SetDefault("stored_responses.http_events.refresh_rate_seconds", 0)
 	v.SetDefault("stored_responses.http_events.timeout_ms", 0)
+	v.SetDefault("stored_responses.validation.enabled", false)
+	v.SetDefault("stored_responses.validation.quarantine", false)
 
 	v.SetDefault("vtrack.timeout_ms", 2000)
 	v.SetDefault("vtrack.allow_unknown_bidder", true)
@@ -1124,6 +1619,12 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("event.timeout_ms", 1000)
 
 	v.SetDefault("user_sync.priority_groups", [][]string{})
+	v.SetDefault("user_sync.id_store.enabled", false)
+	v.SetDefault("user_sync.id_store.key_cookie", "")
+	v.SetDefault("user_sync.id_store.dir", "")
+	v.SetDefault("user_sync.id_store.ttl_days", 90)
+	v.SetDefault("user_sync.id_store.cleanup_interval_seconds", 3600)
+	v.SetDefault("user_sync.id_store.max_size_mb", 1024)
 
 	v.SetDefault("accounts.filesystem.enabled", false)
 	v.SetDefault("accounts.filesystem.directorypath", "./stored_requests/data/by_id")
@@ -1132,11 +1633,18 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("accounts.in_memory_cache.type", "none")
 	v.SetDefault("accounts.in_memory_cache.ttl_seconds", 0)
 	v.SetDefault("accounts.in_memory_cache.size_bytes", 0)
+	v.SetDefault("accounts.in_memory_cache.not_found_ttl_seconds", 0)
+	v.SetDefault("accounts.in_memory_cache.stale_while_revalidate_seconds", 0)
+	v.SetDefault("accounts.in_memory_cache.stale_if_error_seconds", 0)
 	v.SetDefault("accounts.cache_events.enabled", false)
 	v.SetDefault("accounts.cache_events.endpoint", "")
 	v.SetDefault("accounts.http_events.endpoint", "")
 	v.SetDefault("accounts.http_events.refresh_rate_seconds", 0)
 	v.SetDefault("accounts.http_events.timeout_ms", 0)
+	v.SetDefault("accounts.hierarchy.enabled", false)
+	v.SetDefault("accounts.hierarchy.max_depth", 5)
+	v.SetDefault("accounts.validation.enabled", false)
+	v.SetDefault("accounts.validation.quarantine", false)
 
 	v.BindEnv("user_sync.external_url")
 	v.BindEnv("user_sync.coop_sync.default")
@@ -1147,6 +1655,24 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 
 	v.SetDefault("max_request_size", 1024*256)
 	v.SetDefault("analytics.file.filename", "")
+	v.SetDefault("analytics.file.directory", "")
+	v.SetDefault("analytics.file.rotation.max_size", "100MB")
+	v.SetDefault("analytics.file.rotation.max_age", "1h")
+	v.SetDefault("analytics.file.rotation.compress", true)
+	v.SetDefault("analytics.file.buffers.queue_size", 10000)
+	v.SetDefault("analytics.file.buffers.flush_interval", "1s")
+	v.SetDefault("analytics.file.events.auction.enabled", true)
+	v.SetDefault("analytics.file.events.auction.sample_rate", 1.0)
+	v.SetDefault("analytics.file.events.amp.enabled", true)
+	v.SetDefault("analytics.file.events.amp.sample_rate", 1.0)
+	v.SetDefault("analytics.file.events.video.enabled", true)
+	v.SetDefault("analytics.file.events.video.sample_rate", 1.0)
+	v.SetDefault("analytics.file.events.setuid.enabled", true)
+	v.SetDefault("analytics.file.events.setuid.sample_rate", 1.0)
+	v.SetDefault("analytics.file.events.cookie_sync.enabled", true)
+	v.SetDefault("analytics.file.events.cookie_sync.sample_rate", 1.0)
+	v.SetDefault("analytics.file.events.notification.enabled", true)
+	v.SetDefault("analytics.file.events.notification.sample_rate", 1.0)
 	v.SetDefault("analytics.pubstack.endpoint", "https://s2s.pbstck.com/v1")
 	v.SetDefault("analytics.pubstack.scopeid", "change-me")
 	v.SetDefault("analytics.pubstack.enabled", false)
@@ -1162,12 +1688,47 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("analytics.agma.buffers.count", 100)
 	v.SetDefault("analytics.agma.buffers.timeout", "15m")
 	v.SetDefault("analytics.agma.accounts", []AgmaAnalyticsAccount{})
+	v.SetDefault("analytics.http.enabled", false)
+	v.SetDefault("analytics.http.endpoint.url", "")
+	v.SetDefault("analytics.http.endpoint.timeout", "2s")
+	v.SetDefault("analytics.http.buffers.size", "2MB")
+	v.SetDefault("analytics.http.buffers.count", 100)
+	v.SetDefault("analytics.http.buffers.timeout", "15m")
+	v.SetDefault("analytics.http.retry.max_retries", 3)
+	v.SetDefault("analytics.http.retry.backoff", "1s")
+	v.SetDefault("analytics.http.retry.max_backoff", "30s")
+	v.SetDefault("analytics.http.events.auction.enabled", false)
+	v.SetDefault("analytics.http.events.auction.sample_rate", 1.0)
+	v.SetDefault("analytics.http.events.auction.fields", []string{})
+	v.SetDefault("analytics.http.events.amp.enabled", false)
+	v.SetDefault("analytics.http.events.amp.sample_rate", 1.0)
+	v.SetDefault("analytics.http.events.amp.fields", []string{})
+	v.SetDefault("analytics.http.events.video.enabled", false)
+	v.SetDefault("analytics.http.events.video.sample_rate", 1.0)
+	v.SetDefault("analytics.http.events.video.fields", []string{})
+	v.SetDefault("analytics.http.events.setuid.enabled", false)
+	v.SetDefault("analytics.http.events.setuid.sample_rate", 1.0)
+	v.SetDefault("analytics.http.events.setuid.fields", []string{})
+	v.SetDefault("analytics.http.events.cookie_sync.enabled", false)
+	v.SetDefault("analytics.http.events.cookie_sync.sample_rate", 1.0)
+	v.SetDefault("analytics.http.events.cookie_sync.fields", []string{})
+	v.SetDefault("analytics.http.events.notification.enabled", false)
+	v.SetDefault("analytics.http.events.notification.sample_rate", 1.0)
+	v.SetDefault("analytics.http.events.notification.fields", []string{})
+	v.SetDefault("analytics.http.accounts", []string{})
+	v.SetDefault("analytics.parquet.enabled", false)
+	v.SetDefault("analytics.parquet.directory", "")
+	v.SetDefault("analytics.parquet.rotation.max_rows", 1000000)
+	v.SetDefault("analytics.parquet.rotation.max_age", "1h")
+	v.SetDefault("analytics.parquet.queue_size", 10000)
 	v.SetDefault("amp_timeout_adjustment_ms", 0)
 	v.BindEnv("gdpr.default_value")
 	v.SetDefault("gdpr.enabled", true)
 	v.SetDefault("gdpr.host_vendor_id", 0)
 	v.SetDefault("gdpr.timeouts_ms.init_vendorlist_fetches", 0)
 	v.SetDefault("gdpr.timeouts_ms.active_vendorlist_fetch", 0)
+	v.SetDefault("gdpr.vendorlist_cache.dir", "")
+	v.SetDefault("gdpr.vendorlist_cache.snapshot_dir", "./static/vendorlist")
 	v.SetDefault("gdpr.non_standard_publishers", []string{""})
 	v.SetDefault("gdpr.tcf2.enabled", true)
 	v.SetDefault("gdpr.tcf2.purpose1.enforce_vendors", true)
@@ -1232,6 +1793,9 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.BindEnv("account_defaults.privacy.dsa.gdpr_only")
 	v.SetDefault("account_defaults.privacy.ipv6.anon_keep_bits", 56)
 	v.SetDefault("account_defaults.privacy.ipv4.anon_keep_bits", 24)
+	v.SetDefault("account_defaults.privacy.modules.usnat.enabled", false)
+	v.SetDefault("account_defaults.privacy.modules.usnat.skip_sids", []int8{})
+	v.SetDefault("account_defaults.privacy.modules.usnat.normalize", true)
 
 	//Defaults for Price floor fetcher
 	v.SetDefault("price_floors.fetcher.worker", 20)
@@ -1264,6 +1828,36 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("debug.timeout_notification.sampling_rate", 0.0)
 	v.SetDefault("debug.timeout_notification.fail_only", false)
 	v.SetDefault("debug.override_token", "")
+	v.SetDefault("logging.format", LogFormatGlog)
+	v.SetDefault("logging.level", "info")
+	v.SetDefault("logging.rate_limit.enabled", false)
+	v.SetDefault("logging.rate_limit.burst", 10)
+	v.SetDefault("logging.rate_limit.interval", "1m")
+	v.SetDefault("logging.access_log.enabled", false)
+	v.SetDefault("logging.access_log.filename", "access.log")
+	v.SetDefault("logging.access_log.max_size", "100MB")
+	v.SetDefault("logging.access_log.max_backups", 10)
+	v.SetDefault("logging.access_log.sample_rate", 1.0)
+	v.SetDefault("logging.access_log.anonymize", AccessLogAnonymizePrivacy)
+	v.SetDefault("logging.access_log.queue_size", 10000)
+	v.SetDefault("logging.access_log.flush_interval", "1s")
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.endpoint", "localhost:4318")
+	v.SetDefault("tracing.insecure", false)
+	v.SetDefault("tracing.service_name", "prebid-server")
+	v.SetDefault("tracing.sample_rate", 0.01)
+	v.SetDefault("tracing.timeout_ms", 10000)
+	v.SetDefault("readiness.enabled", false)
+	v.SetDefault("readiness.timeout_ms", 1000)
+	v.SetDefault("readiness.critical", []string{"currency_rates", "gdpr_vendor_list"})
+	v.SetDefault("pseudonymization.key_dir", "")
+	v.SetDefault("pseudonymization.refresh_interval_seconds", 3600)
+	v.SetDefault("optout.enabled", false)
+	v.SetDefault("optout.file", "")
+	v.SetDefault("optout.refresh_interval_seconds", 60)
+	v.SetDefault("optout.http_events.endpoint", "")
+	v.SetDefault("optout.http_events.refresh_rate_seconds", 0)
+	v.SetDefault("optout.http_events.timeout_ms", 1000)
 
 	v.SetDefault("tmax_adjustments.enabled", false)
 	v.SetDefault("tmax_adjustments.bidder_response_duration_min_ms", 0)
@@ -148,12 +148,59 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "admin_port", 6060, cfg.AdminPort)
 	cmpInts(t, "auction_timeouts_ms.max", 0, int(cfg.AuctionTimeouts.Max))
 	cmpInts(t, "max_request_size", 1024*256, int(cfg.MaxRequestSize))
+	cmpStrings(t, "logging.format", "glog", cfg.Logging.Format)
+	cmpStrings(t, "logging.level", "info", cfg.Logging.Level)
+	cmpBools(t, "logging.rate_limit.enabled", false, cfg.Logging.RateLimit.Enabled)
+	cmpInts(t, "logging.rate_limit.burst", 10, cfg.Logging.RateLimit.Burst)
+	cmpStrings(t, "logging.rate_limit.interval", "1m", cfg.Logging.RateLimit.Interval)
+	cmpBools(t, "logging.access_log.enabled", false, cfg.Logging.AccessLog.Enabled)
+	cmpStrings(t, "logging.access_log.filename", "access.log", cfg.Logging.AccessLog.Filename)
+	cmpStrings(t, "logging.access_log.max_size", "100MB", cfg.Logging.AccessLog.MaxSize)
+	cmpInts(t, "logging.access_log.max_backups", 10, cfg.Logging.AccessLog.MaxBackups)
+	assert.Equal(t, 1.0, cfg.Logging.AccessLog.SampleRate, "logging.access_log.sample_rate")
+	cmpStrings(t, "logging.access_log.anonymize", "privacy", cfg.Logging.AccessLog.Anonymize)
+	cmpInts(t, "logging.access_log.queue_size", 10000, cfg.Logging.AccessLog.QueueSize)
+	cmpStrings(t, "logging.access_log.flush_interval", "1s", cfg.Logging.AccessLog.FlushInterval)
+	cmpBools(t, "tracing.enabled", false, cfg.Tracing.Enabled)
+	cmpStrings(t, "tracing.endpoint", "localhost:4318", cfg.Tracing.Endpoint)
+	cmpBools(t, "tracing.insecure", false, cfg.Tracing.Insecure)
+	cmpStrings(t, "tracing.service_name", "prebid-server", cfg.Tracing.ServiceName)
+	assert.Equal(t, 0.01, cfg.Tracing.SampleRate, "tracing.sample_rate")
+	cmpInts(t, "tracing.timeout_ms", 10000, cfg.Tracing.TimeoutMS)
+	cmpBools(t, "readiness.enabled", false, cfg.Readiness.Enabled)
+	cmpInts(t, "readiness.timeout_ms", 1000, cfg.Readiness.TimeoutMS)
+	assert.Equal(t, []string{"currency_rates", "gdpr_vendor_list"}, cfg.Readiness.Critical, "readiness.critical")
+	cmpStrings(t, "pseudonymization.key_dir", "", cfg.Pseudonymization.KeyDir)
+	cmpInts(t, "pseudonymization.refresh_interval_seconds", 3600, cfg.Pseudonymization.RefreshIntervalSeconds)
+	cmpBools(t, "optout.enabled", false, cfg.OptOut.Enabled)
+	cmpInts(t, "optout.refresh_interval_seconds", 60, cfg.OptOut.RefreshIntervalSeconds)
+	cmpInts(t, "optout.http_events.timeout_ms", 1000, cfg.OptOut.HTTPEvents.Timeout)
+	cmpBools(t, "user_sync.id_store.enabled", false, cfg.UserSync.IDStore.Enabled)
+	cmpInts(t, "user_sync.id_store.ttl_days", 90, cfg.UserSync.IDStore.TTLDays)
+	cmpInts(t, "user_sync.id_store.cleanup_interval_seconds", 3600, cfg.UserSync.IDStore.CleanupIntervalSeconds)
+	cmpInts(t, "user_sync.id_store.max_size_mb", 1024, cfg.UserSync.IDStore.MaxSizeMB)
 	cmpInts(t, "host_cookie.ttl_days", 90, int(cfg.HostCookie.TTL))
 	cmpInts(t, "host_cookie.max_cookie_size_bytes", 0, cfg.HostCookie.MaxCookieSizeBytes)
+	cmpStrings(t, "host_cookie.mode", "third_party", string(cfg.HostCookie.Mode))
 	cmpInts(t, "currency_converter.fetch_interval_seconds", 1800, cfg.CurrencyConverter.FetchIntervalSeconds)
 	cmpStrings(t, "currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json", cfg.CurrencyConverter.FetchURL)
 	cmpBools(t, "account_required", false, cfg.AccountRequired)
 	cmpInts(t, "metrics.influxdb.collection_rate_seconds", 20, cfg.Metrics.Influxdb.MetricSendInterval)
+	cmpBools(t, "metrics.otlp.enabled", false, cfg.Metrics.OTLP.Enabled)
+	cmpStrings(t, "metrics.otlp.endpoint", "localhost:4318", cfg.Metrics.OTLP.Endpoint)
+	cmpBools(t, "metrics.otlp.insecure", false, cfg.Metrics.OTLP.Insecure)
+	cmpStrings(t, "metrics.otlp.service_name", "prebid-server", cfg.Metrics.OTLP.ServiceName)
+	cmpInts(t, "metrics.otlp.export_interval_ms", 60000, cfg.Metrics.OTLP.ExportIntervalMS)
+	cmpInts(t, "metrics.otlp.timeout_ms", 10000, cfg.Metrics.OTLP.TimeoutMS)
+	cmpStrings(t, "metrics.statsd.address", "", cfg.Metrics.StatsD.Address)
+	cmpStrings(t, "metrics.statsd.namespace", "prebidserver.", cfg.Metrics.StatsD.Namespace)
+	assert.Empty(t, cfg.Metrics.StatsD.Tags, "metrics.statsd.tags")
+	assert.Equal(t, 1.0, cfg.Metrics.StatsD.SampleRate, "metrics.statsd.sample_rate")
+	cmpInts(t, "metrics.statsd.aggregation_interval_ms", 2000, cfg.Metrics.StatsD.AggregationIntervalMS)
+	cmpBools(t, "metrics.statsd.extended_aggregation", false, cfg.Metrics.StatsD.ExtendedAggregation)
+	cmpStrings(t, "metrics.auction.currency", "USD", cfg.Metrics.Auction.Currency)
+	assert.Equal(t, []float64{0.1, 0.25, 0.5, 0.75, 1, 1.5, 2, 3, 5, 10, 20, 50}, cfg.Metrics.Auction.PriceBuckets, "metrics.auction.price_buckets")
+	assert.Equal(t, []float64{0.5, 0.8, 1, 1.1, 1.25, 1.5, 2, 3, 5, 10}, cfg.Metrics.Auction.FloorRatioBuckets, "metrics.auction.floor_ratio_buckets")
 	cmpBools(t, "account_adapter_details", false, cfg.Metrics.Disabled.AccountAdapterDetails)
 	cmpBools(t, "account_debug", true, cfg.Metrics.Disabled.AccountDebug)
 	cmpBools(t, "account_stored_responses", true, cfg.Metrics.Disabled.AccountStoredResponses)
@@ -244,6 +291,12 @@ func TestDefaults(t *testing.T) {
 
 	cmpInts(t, "account_defaults.privacy.ipv6.anon_keep_bits", 56, cfg.AccountDefaults.Privacy.IPv6Config.AnonKeepBits)
 	cmpInts(t, "account_defaults.privacy.ipv4.anon_keep_bits", 24, cfg.AccountDefaults.Privacy.IPv4Config.AnonKeepBits)
+	cmpBools(t, "account_defaults.privacy.modules.usnat.enabled", false, cfg.AccountDefaults.Privacy.Modules.USNat.Enabled)
+	assert.Empty(t, cfg.AccountDefaults.Privacy.Modules.USNat.SkipSIDs, "account_defaults.privacy.modules.usnat.skip_sids")
+	cmpBools(t, "account_defaults.privacy.modules.usnat.normalize", true, cfg.AccountDefaults.Privacy.Modules.USNat.Normalize)
+	assert.Empty(t, cfg.AccountDefaults.Privacy.EIDPermissions, "account_defaults.privacy.eidpermissions")
+	assert.Empty(t, cfg.AccountDefaults.Privacy.PrecisionPolicies, "account_defaults.privacy.precision_policies")
+	cmpStrings(t, "account_defaults.privacy.precision_policy", "", cfg.AccountDefaults.Privacy.PrecisionPolicy)
 
 	//Assert purpose VendorExceptionMap hash tables were built correctly
 	cmpBools(t, "analytics.agma.enabled", false, cfg.Analytics.Agma.Enabled)
@@ -254,6 +307,29 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "analytics.agma.buffers.count", 100, cfg.Analytics.Agma.Buffers.EventCount)
 	cmpStrings(t, "analytics.agma.buffers.timeout", "15m", cfg.Analytics.Agma.Buffers.Timeout)
 	cmpInts(t, "analytics.agma.accounts", 0, len(cfg.Analytics.Agma.Accounts))
+	cmpStrings(t, "analytics.file.directory", "", cfg.Analytics.File.Directory)
+	cmpStrings(t, "analytics.file.rotation.max_size", "100MB", cfg.Analytics.File.Rotation.MaxSize)
+	cmpStrings(t, "analytics.file.rotation.max_age", "1h", cfg.Analytics.File.Rotation.MaxAge)
+	cmpBools(t, "analytics.file.rotation.compress", true, cfg.Analytics.File.Rotation.Compress)
+	cmpInts(t, "analytics.file.buffers.queue_size", 10000, cfg.Analytics.File.Buffers.QueueSize)
+	cmpStrings(t, "analytics.file.buffers.flush_interval", "1s", cfg.Analytics.File.Buffers.FlushInterval)
+	cmpBools(t, "analytics.file.events.notification.enabled", true, cfg.Analytics.File.Events.Notification.Enabled)
+	assert.Equal(t, 1.0, cfg.Analytics.File.Events.Notification.SampleRate, "analytics.file.events.notification.sample_rate")
+	cmpBools(t, "analytics.parquet.enabled", false, cfg.Analytics.Parquet.Enabled)
+	cmpInts(t, "analytics.parquet.rotation.max_rows", 1000000, cfg.Analytics.Parquet.Rotation.MaxRows)
+	cmpStrings(t, "analytics.parquet.rotation.max_age", "1h", cfg.Analytics.Parquet.Rotation.MaxAge)
+	cmpInts(t, "analytics.parquet.queue_size", 10000, cfg.Analytics.Parquet.QueueSize)
+	cmpBools(t, "analytics.http.enabled", false, cfg.Analytics.HTTP.Enabled)
+	cmpStrings(t, "analytics.http.endpoint.timeout", "2s", cfg.Analytics.HTTP.Endpoint.Timeout)
+	cmpStrings(t, "analytics.http.buffers.size", "2MB", cfg.Analytics.HTTP.Buffers.BufferSize)
+	cmpInts(t, "analytics.http.buffers.count", 100, cfg.Analytics.HTTP.Buffers.EventCount)
+	cmpStrings(t, "analytics.http.buffers.timeout", "15m", cfg.Analytics.HTTP.Buffers.Timeout)
+	cmpInts(t, "analytics.http.retry.max_retries", 3, cfg.Analytics.HTTP.Retry.MaxRetries)
+	cmpStrings(t, "analytics.http.retry.backoff", "1s", cfg.Analytics.HTTP.Retry.Backoff)
+	cmpStrings(t, "analytics.http.retry.max_backoff", "30s", cfg.Analytics.HTTP.Retry.MaxBackoff)
+	cmpBools(t, "analytics.http.events.auction.enabled", false, cfg.Analytics.HTTP.Events.Auction.Enabled)
+	assert.Equal(t, 1.0, cfg.Analytics.HTTP.Events.Auction.SampleRate, "analytics.http.events.auction.sample_rate")
+	cmpInts(t, "analytics.http.accounts", 0, len(cfg.Analytics.HTTP.Accounts))
 	expectedTCF2 := TCF2{
 		Enabled: true,
 		Purpose1: TCF2Purpose{
@@ -359,6 +435,8 @@ func TestDefaults(t *testing.T) {
 		10: &expectedTCF2.Purpose10,
 	}
 	assert.Equal(t, expectedTCF2, cfg.GDPR.TCF2, "gdpr.tcf2")
+	cmpStrings(t, "gdpr.vendorlist_cache.dir", "", cfg.GDPR.VendorListCache.Dir)
+	cmpStrings(t, "gdpr.vendorlist_cache.snapshot_dir", "./static/vendorlist", cfg.GDPR.VendorListCache.SnapshotDir)
 }
 
 // When adding a new field, make sure the indentations are spaces not tabs otherwise read config may fail to parse the new field value.
@@ -571,6 +649,31 @@ account_defaults:
             anon_keep_bits: 50
         ipv4:
             anon_keep_bits: 20
+        modules:
+            usnat:
+                enabled: true
+                skip_sids: [8, 10]
+                normalize: true
+        eidpermissions:
+            - source: liveramp.com
+              bidders: [bidderA]
+              gdpr_purposes: [1, 4]
+              deny_gpp_opt_out: true
+        precision_policies:
+            eu:
+                ipv4_keep_bits: 16
+                ipv6_keep_bits: 32
+                geo_decimals: 1
+                drop_zip: true
+                drop_metro: true
+        precision_policy: eu
+        pseudonymization:
+            enabled: true
+            bidders: [bidderA]
+            analytics: ["*"]
+            user_id: true
+            device_ifa: true
+            eid_sources: [liveramp.com]
         dsa:
             default: "{\"dsarequired\":3,\"pubrender\":1,\"datatopub\":2,\"transparency\":[{\"domain\":\"domain.com\",\"dsaparams\":[1]}]}"
             gdpr_only: true
@@ -750,6 +853,24 @@ func TestFullConfig(t *testing.T) {
 
 	cmpInts(t, "account_defaults.privacy.ipv6.anon_keep_bits", 50, cfg.AccountDefaults.Privacy.IPv6Config.AnonKeepBits)
 	cmpInts(t, "account_defaults.privacy.ipv4.anon_keep_bits", 20, cfg.AccountDefaults.Privacy.IPv4Config.AnonKeepBits)
+	cmpBools(t, "account_defaults.privacy.modules.usnat.enabled", true, cfg.AccountDefaults.Privacy.Modules.USNat.Enabled)
+	assert.Equal(t, []int8{8, 10}, cfg.AccountDefaults.Privacy.Modules.USNat.SkipSIDs, "account_defaults.privacy.modules.usnat.skip_sids")
+	cmpBools(t, "account_defaults.privacy.modules.usnat.normalize", true, cfg.AccountDefaults.Privacy.Modules.USNat.Normalize)
+	assert.Equal(t, []AccountEIDPermission{
+		{Source: "liveramp.com", Bidders: []string{"bidderA"}, GDPRPurposes: []int{1, 4}, DenyGPPOptOut: true},
+	}, cfg.AccountDefaults.Privacy.EIDPermissions, "account_defaults.privacy.eidpermissions")
+	assert.Equal(t, map[string]PrecisionPolicy{
+		"eu": {IPv4KeepBits: ptrutil.ToPtr(16), IPv6KeepBits: ptrutil.ToPtr(32), GeoDecimals: ptrutil.ToPtr(1), DropZip: true, DropMetro: true},
+	}, cfg.AccountDefaults.Privacy.PrecisionPolicies, "account_defaults.privacy.precision_policies")
+	cmpStrings(t, "account_defaults.privacy.precision_policy", "eu", cfg.AccountDefaults.Privacy.PrecisionPolicy)
+	assert.Equal(t, AccountPseudonymization{
+		Enabled:    true,
+		Bidders:    []string{"bidderA"},
+		Analytics:  []string{"*"},
+		UserID:     true,
+		DeviceIFA:  true,
+		EIDSources: []string{"liveramp.com"},
+	}, cfg.AccountDefaults.Privacy.Pseudonymization, "account_defaults.privacy.pseudonymization")
 
 	cmpStrings(t, "account_defaults.privacy.topicsdomain", "test.com", cfg.AccountDefaults.Privacy.PrivacySandbox.TopicsDomain)
 	cmpBools(t, "account_defaults.privacy.cookiedeprecation.enabled", true, cfg.AccountDefaults.Privacy.PrivacySandbox.CookieDeprecation.Enabled)
@@ -996,6 +1117,13 @@ func TestValidateConfig(t *testing.T) {
 				},
 			},
 		},
+		Metrics: Metrics{
+			Auction: AuctionMetrics{
+				Currency:          "USD",
+				PriceBuckets:      []float64{0.5, 1, 2},
+				FloorRatioBuckets: []float64{1, 2},
+			},
+		},
 	}
 
 	v := viper.New()
@@ -1434,6 +1562,409 @@ func TestValidateDebug(t *testing.T) {
 	assert.NotNil(t, err, "cfg.debug.timeout_notification.sampling_rate should not be allowed to be greater than 1.0, but it was allowed")
 }
 
+func TestValidateLogging(t *testing.T) {
+	testCases := []struct {
+		name         string
+		logging      Logging
+		expectedErrs []error
+	}{
+		{
+			name:    "glog",
+			logging: Logging{Format: "glog", Level: "unused"},
+		},
+		{
+			name:    "json-rate-limited",
+			logging: Logging{Format: "json", Level: "warn", RateLimit: LoggingRateLimit{Enabled: true, Burst: 5, Interval: "30s"}},
+		},
+		{
+			name:         "unknown-format",
+			logging:      Logging{Format: "text"},
+			expectedErrs: []error{errors.New("logging.format must be glog or json. Got text")},
+		},
+		{
+			name:         "unknown-level",
+			logging:      Logging{Format: "json", Level: "verbose"},
+			expectedErrs: []error{errors.New(`logging.level: unknown log level "verbose", must be debug, info, warn or error`)},
+		},
+		{
+			name:    "invalid-rate-limit",
+			logging: Logging{Format: "glog", RateLimit: LoggingRateLimit{Enabled: true, Burst: 0, Interval: "-1s"}},
+			expectedErrs: []error{
+				errors.New("logging.rate_limit.burst must be positive. Got 0"),
+				errors.New("logging.rate_limit.interval must be positive. Got -1s"),
+			},
+		},
+		{
+			name: "access-log",
+			logging: Logging{Format: "glog", AccessLog: AccessLog{
+				Enabled: true, Filename: "access.log", MaxSize: "10MB", MaxBackups: 2, SampleRate: 0.5,
+				Anonymize: "always", QueueSize: 100, FlushInterval: "1s",
+			}},
+		},
+		{
+			name:    "access-log-disabled-not-validated",
+			logging: Logging{Format: "glog", AccessLog: AccessLog{Enabled: false, SampleRate: 2}},
+		},
+		{
+			name: "invalid-access-log",
+			logging: Logging{Format: "glog", AccessLog: AccessLog{
+				Enabled: true, MaxSize: "0", MaxBackups: -1, SampleRate: 1.5,
+				Anonymize: "sometimes", QueueSize: -1, FlushInterval: "never",
+			}},
+			expectedErrs: []error{
+				errors.New("logging.access_log.filename is required when the access log is enabled"),
+				errors.New("logging.access_log.max_size must be positive. Got 0"),
+				errors.New("logging.access_log.max_backups must be 0 or greater. Got -1"),
+				errors.New("logging.access_log.sample_rate must be between 0 and 1. Got 1.500000"),
+				errors.New("logging.access_log.anonymize must be never, privacy or always. Got sometimes"),
+				errors.New("logging.access_log.queue_size must be 0 or greater. Got -1"),
+				errors.New(`logging.access_log.flush_interval: time: invalid duration "never"`),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrs, test.logging.validate(nil))
+		})
+	}
+}
+
+func TestValidateOTLPMetrics(t *testing.T) {
+	testCases := []struct {
+		name         string
+		otlp         OTLPMetrics
+		expectedErrs []error
+	}{
+		{
+			name: "enabled",
+			otlp: OTLPMetrics{Enabled: true, Endpoint: "collector:4318", ServiceName: "prebid-server", ExportIntervalMS: 60000, TimeoutMS: 10000},
+		},
+		{
+			name: "disabled-not-validated",
+			otlp: OTLPMetrics{Enabled: false},
+		},
+		{
+			name: "invalid",
+			otlp: OTLPMetrics{Enabled: true, ExportIntervalMS: 1000, TimeoutMS: 2000},
+			expectedErrs: []error{
+				errors.New("metrics.otlp.endpoint is required when the OTLP metrics are enabled"),
+				errors.New("metrics.otlp.service_name is required when the OTLP metrics are enabled"),
+				errors.New("metrics.otlp.timeout_ms must be positive and at most metrics.otlp.export_interval_ms. Got 2000"),
+			},
+		},
+		{
+			name: "invalid-interval",
+			otlp: OTLPMetrics{Enabled: true, Endpoint: "collector:4318", ServiceName: "prebid-server", ExportIntervalMS: 0, TimeoutMS: 0},
+			expectedErrs: []error{
+				errors.New("metrics.otlp.export_interval_ms must be positive. Got 0"),
+				errors.New("metrics.otlp.timeout_ms must be positive and at most metrics.otlp.export_interval_ms. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrs, test.otlp.validate(nil))
+		})
+	}
+}
+
+func TestValidateStatsDMetrics(t *testing.T) {
+	testCases := []struct {
+		name         string
+		statsd       StatsDMetrics
+		expectedErrs []error
+	}{
+		{
+			name:   "enabled",
+			statsd: StatsDMetrics{Address: "unix:///var/run/datadog/dsd.socket", SampleRate: 0.1, AggregationIntervalMS: 2000},
+		},
+		{
+			name:   "disabled-not-validated",
+			statsd: StatsDMetrics{Address: ""},
+		},
+		{
+			name:   "invalid",
+			statsd: StatsDMetrics{Address: "localhost:8125", SampleRate: 0, AggregationIntervalMS: -1},
+			expectedErrs: []error{
+				errors.New("metrics.statsd.sample_rate must be greater than 0 and at most 1. Got 0.000000"),
+				errors.New("metrics.statsd.aggregation_interval_ms must be positive. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrs, test.statsd.validate(nil))
+		})
+	}
+}
+
+func TestValidateAuctionMetrics(t *testing.T) {
+	testCases := []struct {
+		name         string
+		auction      AuctionMetrics
+		expectedErrs []error
+	}{
+		{
+			name:    "valid",
+			auction: AuctionMetrics{Currency: "EUR", PriceBuckets: []float64{0.5, 1, 2}, FloorRatioBuckets: []float64{1}},
+		},
+		{
+			name:    "invalid-currency",
+			auction: AuctionMetrics{Currency: "EURO", PriceBuckets: []float64{0.5, 1, 2}, FloorRatioBuckets: []float64{1}},
+			expectedErrs: []error{
+				errors.New("metrics.auction.currency must be an ISO 4217 currency code. Got EURO"),
+			},
+		},
+		{
+			name:    "invalid-buckets",
+			auction: AuctionMetrics{Currency: "USD", PriceBuckets: []float64{1, 0.5, 2}},
+			expectedErrs: []error{
+				errors.New("metrics.auction.price_buckets must be in increasing order. Got [1 0.5 2]"),
+				errors.New("metrics.auction.floor_ratio_buckets must not be empty"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrs, test.auction.validate(nil))
+		})
+	}
+}
+
+func TestValidateTracing(t *testing.T) {
+	testCases := []struct {
+		name         string
+		tracing      Tracing
+		expectedErrs []error
+	}{
+		{
+			name:    "enabled",
+			tracing: Tracing{Enabled: true, Endpoint: "collector:4318", ServiceName: "prebid-server", SampleRate: 0.5, TimeoutMS: 1000},
+		},
+		{
+			name:    "disabled-not-validated",
+			tracing: Tracing{Enabled: false, SampleRate: 2},
+		},
+		{
+			name:    "invalid",
+			tracing: Tracing{Enabled: true, SampleRate: -0.5, TimeoutMS: 0},
+			expectedErrs: []error{
+				errors.New("tracing.endpoint is required when tracing is enabled"),
+				errors.New("tracing.service_name is required when tracing is enabled"),
+				errors.New("tracing.sample_rate must be between 0 and 1. Got -0.500000"),
+				errors.New("tracing.timeout_ms must be positive. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrs, test.tracing.validate(nil))
+		})
+	}
+}
+
+func TestValidateReadiness(t *testing.T) {
+	testCases := []struct {
+		name         string
+		readiness    Readiness
+		expectedErrs []error
+	}{
+		{
+			name:      "enabled",
+			readiness: Readiness{Enabled: true, TimeoutMS: 1000, Critical: []string{"currency_rates", "stored_requests"}},
+		},
+		{
+			name:      "disabled-not-validated",
+			readiness: Readiness{Enabled: false, TimeoutMS: -1},
+		},
+		{
+			name:      "invalid",
+			readiness: Readiness{Enabled: true, TimeoutMS: 0, Critical: []string{"currency_rates", "", ""}},
+			expectedErrs: []error{
+				errors.New("readiness.timeout_ms must be positive. Got 0"),
+				errors.New("readiness.critical must not contain empty check names"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrs, test.readiness.validate(nil))
+		})
+	}
+}
+
+func TestValidatePseudonymization(t *testing.T) {
+	testCases := []struct {
+		name             string
+		pseudonymization Pseudonymization
+		expectedErrs     []error
+	}{
+		{
+			name:             "valid",
+			pseudonymization: Pseudonymization{KeyDir: "/etc/keys", RefreshIntervalSeconds: 3600},
+		},
+		{
+			name:             "no-key-dir-not-validated",
+			pseudonymization: Pseudonymization{RefreshIntervalSeconds: 0},
+		},
+		{
+			name:             "invalid-refresh-interval",
+			pseudonymization: Pseudonymization{KeyDir: "/etc/keys", RefreshIntervalSeconds: 0},
+			expectedErrs: []error{
+				errors.New("pseudonymization.refresh_interval_seconds must be positive. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrs, test.pseudonymization.validate(nil))
+		})
+	}
+}
+
+func TestValidateOptOut(t *testing.T) {
+	testCases := []struct {
+		name         string
+		optOut       OptOut
+		expectedErrs []error
+	}{
+		{
+			name:   "valid",
+			optOut: OptOut{Enabled: true, File: "/etc/optout.txt", RefreshIntervalSeconds: 60, HTTPEvents: HTTPEventsConfig{Endpoint: "http://optout.example.com", RefreshRate: 60}},
+		},
+		{
+			name:   "disabled-not-validated",
+			optOut: OptOut{File: "/etc/optout.txt"},
+		},
+		{
+			name:   "invalid-refresh-intervals",
+			optOut: OptOut{Enabled: true, File: "/etc/optout.txt", HTTPEvents: HTTPEventsConfig{Endpoint: "http://optout.example.com"}},
+			expectedErrs: []error{
+				errors.New("optout.refresh_interval_seconds must be positive. Got 0"),
+				errors.New("optout.http_events.refresh_rate_seconds must be positive. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrs, test.optOut.validate(nil))
+		})
+	}
+}
+
+func TestValidateUserSyncIDStore(t *testing.T) {
+	testCases := []struct {
+		name         string
+		idStore      UserSyncIDStore
+		expectedErrs []error
+	}{
+		{
+			name:    "valid",
+			idStore: UserSyncIDStore{Enabled: true, Dir: "/var/lib/pbs/uids", TTLDays: 90, CleanupIntervalSeconds: 3600, MaxSizeMB: 1024},
+		},
+		{
+			name:    "disabled-not-validated",
+			idStore: UserSyncIDStore{},
+		},
+		{
+			name:    "invalid",
+			idStore: UserSyncIDStore{Enabled: true, TTLDays: -1},
+			expectedErrs: []error{
+				errors.New("user_sync.id_store.dir is required when the id store is enabled"),
+				errors.New("user_sync.id_store.ttl_days must be positive. Got -1"),
+				errors.New("user_sync.id_store.cleanup_interval_seconds must be positive. Got 0"),
+				errors.New("user_sync.id_store.max_size_mb must be positive. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrs, test.idStore.validate(nil))
+		})
+	}
+}
+
+func TestValidateUIDsCookieMode(t *testing.T) {
+	testCases := []struct {
+		name         string
+		hostCookie   HostCookie
+		cookie       AccountUIDsCookie
+		expectedErrs []error
+	}{
+		{
+			name:       "valid",
+			hostCookie: HostCookie{Mode: UIDsCookieModePartitioned},
+			cookie:     AccountUIDsCookie{Mode: UIDsCookieModeFirstParty, Domain: "publisher.com", ExternalURL: "https://pbs.publisher.com"},
+		},
+		{
+			name: "default",
+		},
+		{
+			name:       "invalid",
+			hostCookie: HostCookie{Mode: UIDsCookieModeFirstParty},
+			cookie:     AccountUIDsCookie{Mode: UIDsCookieModeFirstParty},
+			expectedErrs: []error{
+				errors.New("host_cookie.mode must be third_party or partitioned. Got first_party"),
+				errors.New("cookie_sync.cookie.domain is required in the first_party mode"),
+				errors.New("cookie_sync.cookie.external_url is required in the first_party mode"),
+			},
+		},
+		{
+			name:   "unknown-account-mode",
+			cookie: AccountUIDsCookie{Mode: "first-party"},
+			expectedErrs: []error{
+				errors.New("cookie_sync.cookie.mode must be third_party, partitioned or first_party. Got first-party"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			errs := test.hostCookie.validate(nil)
+			errs = test.cookie.Validate(errs)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
+
+func TestHostCookieForAccount(t *testing.T) {
+	hostCookie := &HostCookie{Domain: "pbs.example.com", Family: "family", TTL: 90}
+
+	assert.Same(t, hostCookie, hostCookie.ForAccount(nil))
+	assert.Same(t, hostCookie, hostCookie.ForAccount(&Account{}))
+	assert.Equal(t, UIDsCookieModeThirdParty, hostCookie.UIDsCookieMode())
+	assert.Equal(t, "uids", hostCookie.UIDsCookie())
+
+	partitioned := hostCookie.ForAccount(&Account{CookieSync: CookieSync{Cookie: AccountUIDsCookie{Mode: UIDsCookieModePartitioned, Domain: "ignored.com"}}})
+	assert.Equal(t, &HostCookie{Domain: "pbs.example.com", Family: "family", TTL: 90, Mode: UIDsCookieModePartitioned}, partitioned)
+
+	firstParty := hostCookie.ForAccount(&Account{CookieSync: CookieSync{Cookie: AccountUIDsCookie{Mode: UIDsCookieModeFirstParty, Name: "pbs_uids", Domain: "publisher.com"}}})
+	assert.Equal(t, &HostCookie{Domain: "publisher.com", Family: "family", TTL: 90, Mode: UIDsCookieModeFirstParty, UIDsCookieName: "pbs_uids"}, firstParty)
+	assert.Equal(t, "pbs_uids", firstParty.UIDsCookie())
+	assert.Equal(t, UIDsCookieModeThirdParty, hostCookie.UIDsCookieMode(), "the host cookie isn't modified")
+}
+
+func TestInvalidAccountDefaultsUIDsCookie(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.AccountDefaults.CookieSync.Cookie = AccountUIDsCookie{Mode: "first-party"}
+	assertOneError(t, cfg.validate(v), "account_defaults.cookie_sync.cookie.mode must be third_party, partitioned or first_party. Got first-party")
+}
+
+func TestInvalidAccountDefaultsEIDPermission(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.AccountDefaults.Privacy.EIDPermissions = []AccountEIDPermission{{Bidders: []string{"*"}}}
+	assertOneError(t, cfg.validate(v), "account_defaults.privacy.eidpermissions: source is required")
+}
+
 func TestValidateAccountsConfigRestrictions(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.Accounts.Files.Enabled = true
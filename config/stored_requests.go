@@ -71,6 +71,51 @@ type StoredRequests struct {
 	// HTTPEvents configures an instance of stored_requests/events/http/http.go.
 	// If non-nil, the server will use those endpoints to populate and update the cache.
 	HTTPEvents HTTPEventsConfig `mapstructure:"http_events"`
+	// Hierarchy configures the resolution of accounts which inherit their configuration from a parent account.
+	// It only applies to the accounts section.
+	Hierarchy AccountHierarchy `mapstructure:"hierarchy"`
+	// Validation configures the validation of the data saved to the caches by events, including their initial load.
+	// Validators are in stored_requests/validation
+	Validation StoredDataValidation `mapstructure:"validation"`
+}
+
+// StoredDataValidation configures stored_requests/validation/validation.go
+type StoredDataValidation struct {
+	// Enabled should be true if invalid data must be kept out of the caches
+	Enabled bool `mapstructure:"enabled"`
+	// Quarantine should be true if invalid data must be kept for inspection through the admin endpoint
+	Quarantine bool `mapstructure:"quarantine"`
+}
+
+func (cfg *StoredDataValidation) validate(dataType DataType, errs []error) []error {
+	if dataType == CategoryDataType && cfg.Enabled {
+		errs = append(errs, fmt.Errorf("%s: validation is not supported for categories", dataType.Section()))
+	}
+	if cfg.Quarantine && !cfg.Enabled {
+		errs = append(errs, fmt.Errorf("%s: validation.quarantine requires validation.enabled", dataType.Section()))
+	}
+	return errs
+}
+
+// AccountHierarchy configures stored_requests/account_hierarchy.go
+type AccountHierarchy struct {
+	// Enabled should be true if accounts may declare a parent account to inherit configuration from.
+	Enabled bool `mapstructure:"enabled"`
+	// MaxDepth is the maximum number of parent accounts which may be chained above an account.
+	MaxDepth int `mapstructure:"max_depth"`
+}
+
+func (cfg *AccountHierarchy) validate(dataType DataType, errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if dataType != AccountDataType {
+		return append(errs, fmt.Errorf("%s: hierarchy is only supported for accounts", dataType.Section()))
+	}
+	if cfg.MaxDepth <= 0 {
+		errs = append(errs, fmt.Errorf("%s: hierarchy.max_depth must be > 0. Got %d", dataType.Section(), cfg.MaxDepth))
+	}
+	return errs
 }
 
 // HTTPEventsConfig configures stored_requests/events/http/http.go
@@ -143,6 +188,8 @@ func (cfg *StoredRequests) validate(errs []error) []error {
 	} else {
 		errs = cfg.Database.validate(cfg.DataType(), errs)
 	}
+	errs = cfg.Hierarchy.validate(cfg.DataType(), errs)
+	errs = cfg.Validation.validate(cfg.DataType(), errs)
 
 	// Categories do not use cache so none of the following checks apply
 	if cfg.DataType() == CategoryDataType {
@@ -323,6 +370,16 @@ type InMemoryCache struct {
 	ImpCacheSize int `mapstructure:"imp_cache_size_bytes"`
 	// ResponsesCacheSize is the max number of bytes allowed in the cache for Stored Responses. Values <= 0 will have no limit
 	RespCacheSize int `mapstructure:"resp_cache_size_bytes"`
+	// NotFoundTTL is the number of seconds IDs which are unknown to the backend are remembered, so that repeated
+	// lookups of missing data don't reach the backend. Values <= 0 disable negative caching. Only applies to lru caches.
+	NotFoundTTL int `mapstructure:"not_found_ttl_seconds"`
+	// StaleWhileRevalidate is the number of seconds past the TTL during which data is served from the cache
+	// while it is refreshed from the backend in the background. Only applies to lru caches with a TTL.
+	StaleWhileRevalidate int `mapstructure:"stale_while_revalidate_seconds"`
+	// StaleIfError is the number of seconds past the TTL during which data is served from the cache
+	// if the backend fails to return it. Data which the backend reports as not found is evicted instead, since it was
+	// deleted. Only applies to lru caches with a TTL.
+	StaleIfError int `mapstructure:"stale_if_error_seconds"`
 }
 
 func (cfg *InMemoryCache) validate(dataType DataType, errs []error) []error {
@@ -378,5 +435,27 @@ func (cfg *InMemoryCache) validate(dataType DataType, errs []error) []error {
 	default:
 		errs = append(errs, fmt.Errorf("%s: in_memory_cache.type %s is invalid", section, cfg.Type))
 	}
+	return cfg.validateOptions(section, errs)
+}
+
+func (cfg *InMemoryCache) validateOptions(section string, errs []error) []error {
+	options := []struct {
+		name  string
+		value int
+	}{
+		{"not_found_ttl_seconds", cfg.NotFoundTTL},
+		{"stale_while_revalidate_seconds", cfg.StaleWhileRevalidate},
+		{"stale_if_error_seconds", cfg.StaleIfError},
+	}
+	for _, option := range options {
+		if option.value < 0 {
+			errs = append(errs, fmt.Errorf("%s: in_memory_cache.%s must be >= 0. Got %d", section, option.name, option.value))
+		} else if option.value > 0 && cfg.Type != "lru" {
+			errs = append(errs, fmt.Errorf("%s: in_memory_cache.%s is only supported when in_memory_cache.type=lru", section, option.name))
+		}
+	}
+	if (cfg.StaleWhileRevalidate > 0 || cfg.StaleIfError > 0) && cfg.Type == "lru" && cfg.TTL <= 0 {
+		errs = append(errs, fmt.Errorf("%s: in_memory_cache.stale_while_revalidate_seconds and stale_if_error_seconds require in_memory_cache.ttl_seconds > 0", section))
+	}
 	return errs
 }
@@ -103,6 +103,72 @@ func TestInMemoryCacheValidationSingleCache(t *testing.T) {
 	}).validate(AccountDataType, nil))
 }
 
+func TestInMemoryCacheValidationOptions(t *testing.T) {
+	assertNoErrs(t, (&InMemoryCache{
+		Type:                 "lru",
+		Size:                 1000,
+		TTL:                  300,
+		NotFoundTTL:          30,
+		StaleWhileRevalidate: 60,
+		StaleIfError:         3600,
+	}).validate(AccountDataType, nil))
+	assertNoErrs(t, (&InMemoryCache{
+		Type:        "lru",
+		Size:        1000,
+		NotFoundTTL: 30,
+	}).validate(AccountDataType, nil))
+	assertErrsExist(t, (&InMemoryCache{
+		Type:        "unbounded",
+		NotFoundTTL: 30,
+	}).validate(AccountDataType, nil))
+	assertErrsExist(t, (&InMemoryCache{
+		Type:                 "none",
+		StaleWhileRevalidate: 60,
+	}).validate(RequestDataType, nil))
+	assertErrsExist(t, (&InMemoryCache{
+		Type:        "lru",
+		Size:        1000,
+		NotFoundTTL: -1,
+	}).validate(AccountDataType, nil))
+	assertErrsExist(t, (&InMemoryCache{
+		Type:         "lru",
+		Size:         1000,
+		StaleIfError: 3600,
+	}).validate(AccountDataType, nil))
+}
+
+func TestStoredDataValidationValidation(t *testing.T) {
+	assertNoErrs(t, (&StoredDataValidation{
+		Enabled:    true,
+		Quarantine: true,
+	}).validate(RequestDataType, nil))
+	assertNoErrs(t, (&StoredDataValidation{}).validate(CategoryDataType, nil))
+	assertErrsExist(t, (&StoredDataValidation{
+		Enabled: true,
+	}).validate(CategoryDataType, nil))
+	assertErrsExist(t, (&StoredDataValidation{
+		Quarantine: true,
+	}).validate(AccountDataType, nil))
+}
+
+func TestAccountHierarchyValidation(t *testing.T) {
+	assertNoErrs(t, (&AccountHierarchy{
+		Enabled: false,
+	}).validate(RequestDataType, nil))
+	assertNoErrs(t, (&AccountHierarchy{
+		Enabled:  true,
+		MaxDepth: 5,
+	}).validate(AccountDataType, nil))
+	assertErrsExist(t, (&AccountHierarchy{
+		Enabled:  true,
+		MaxDepth: 0,
+	}).validate(AccountDataType, nil))
+	assertErrsExist(t, (&AccountHierarchy{
+		Enabled:  true,
+		MaxDepth: 5,
+	}).validate(RequestDataType, nil))
+}
+
 func TestDatabaseConfigValidation(t *testing.T) {
 	tests := []struct {
 		description            string
@@ -1,14 +1,138 @@
 package config
 
+import (
+	"errors"
+	"fmt"
+)
+
 // UserSync specifies the static global user sync configuration.
 type UserSync struct {
 	Cooperative    UserSyncCooperative `mapstructure:"coop_sync"`
 	ExternalURL    string              `mapstructure:"external_url"`
 	RedirectURL    string              `mapstructure:"redirect_url"`
 	PriorityGroups [][]string          `mapstructure:"priority_groups"`
+	IDStore        UserSyncIDStore     `mapstructure:"id_store"`
 }
 
 // UserSyncCooperative specifies the static global default cooperative cookie sync
 type UserSyncCooperative struct {
 	EnabledByDefault bool `mapstructure:"default"`
 }
+
+// UserSyncIDStore keeps the UIDs of the users server side rather than in the uids cookie, which then only carries the
+// key to look them up. /setuid writes the UIDs to the store, while /cookie_sync and the auctions read them from it.
+type UserSyncIDStore struct {
+	Enabled bool `mapstructure:"enabled"`
+	// KeyCookie is the name of a first-party cookie whose value is the key of the user. The users without it are
+	// given a random key by Prebid Server.
+	KeyCookie string `mapstructure:"key_cookie"`
+	// Dir is the directory of the embedded on-disk store
+	Dir string `mapstructure:"dir"`
+	// TTLDays is the time the UIDs of a user are kept after they were last set
+	TTLDays int `mapstructure:"ttl_days"`
+	// CleanupIntervalSeconds is the interval at which the expired UIDs are removed from the disk
+	CleanupIntervalSeconds int `mapstructure:"cleanup_interval_seconds"`
+	// MaxSizeMB bounds the disk space used by the UIDs. Once it is reached, the UIDs of the users which were set the
+	// longest ago are removed first.
+	MaxSizeMB int `mapstructure:"max_size_mb"`
+}
+
+func (cfg *UserSyncIDStore) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Dir == "" {
+		errs = append(errs, errors.New("user_sync.id_store.dir is required when the id store is enabled"))
+	}
+	if cfg.TTLDays <= 0 {
+		errs = append(errs, fmt.Errorf("user_sync.id_store.ttl_days must be positive. Got %d", cfg.TTLDays))
+	}
+	if cfg.CleanupIntervalSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("user_sync.id_store.cleanup_interval_seconds must be positive. Got %d", cfg.CleanupIntervalSeconds))
+	}
+	if cfg.MaxSizeMB <= 0 {
+		errs = append(errs, fmt.Errorf("user_sync.id_store.max_size_mb must be positive. Got %d", cfg.MaxSizeMB))
+	}
+	return errs
+}
+
+// UIDsCookieMode is how the browsers scope the uids cookie
+type UIDsCookieMode string
+
+const (
+	// UIDsCookieModeThirdParty sets the uids cookie under the domain of Prebid Server, shared by every site
+	UIDsCookieModeThirdParty UIDsCookieMode = "third_party"
+	// UIDsCookieModePartitioned sets a partitioned (CHIPS) uids cookie, kept apart by the browsers for each top-level
+	// site
+	UIDsCookieModePartitioned UIDsCookieMode = "partitioned"
+	// UIDsCookieModeFirstParty sets the uids cookie under the domain of the publisher, whose CNAME serves Prebid Server
+	UIDsCookieModeFirstParty UIDsCookieMode = "first_party"
+)
+
+// AccountUIDsCookie overrides the uids cookie of the host for an account
+type AccountUIDsCookie struct {
+	// Mode is the mode of the cookie, or the mode of the host if empty
+	Mode UIDsCookieMode `mapstructure:"mode" json:"mode,omitempty"`
+	// Name, Domain and ExternalURL are the name and domain of the cookie in the first_party mode, and the URL of the
+	// CNAME of Prebid Server the /setuid requests are sent to. The cookie is named uids if Name is empty.
+	Name        string `mapstructure:"name" json:"name,omitempty"`
+	Domain      string `mapstructure:"domain" json:"domain,omitempty"`
+	ExternalURL string `mapstructure:"external_url" json:"external_url,omitempty"`
+}
+
+// Validate appends the errors of the uids cookie of an account to errs
+func (cfg *AccountUIDsCookie) Validate(errs []error) []error {
+	switch cfg.Mode {
+	case "", UIDsCookieModeThirdParty, UIDsCookieModePartitioned:
+	case UIDsCookieModeFirstParty:
+		if cfg.Domain == "" {
+			errs = append(errs, errors.New("cookie_sync.cookie.domain is required in the first_party mode"))
+		}
+		if cfg.ExternalURL == "" {
+			errs = append(errs, errors.New("cookie_sync.cookie.external_url is required in the first_party mode"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("cookie_sync.cookie.mode must be third_party, partitioned or first_party. Got %s", cfg.Mode))
+	}
+	return errs
+}
+
+func (cfg *HostCookie) validate(errs []error) []error {
+	switch cfg.Mode {
+	case "", UIDsCookieModeThirdParty, UIDsCookieModePartitioned:
+	default:
+		errs = append(errs, fmt.Errorf("host_cookie.mode must be third_party or partitioned. Got %s", cfg.Mode))
+	}
+	return errs
+}
+
+// UIDsCookieMode returns the mode of the uids cookie, third_party if not set
+func (cfg *HostCookie) UIDsCookieMode() UIDsCookieMode {
+	if cfg.Mode == "" {
+		return UIDsCookieModeThirdParty
+	}
+	return cfg.Mode
+}
+
+// UIDsCookie returns the name of the uids cookie
+func (cfg *HostCookie) UIDsCookie() string {
+	if cfg.UIDsCookieName == "" {
+		return "uids"
+	}
+	return cfg.UIDsCookieName
+}
+
+// ForAccount returns the host cookie config with the uids cookie overridden by the account, if it is
+func (cfg *HostCookie) ForAccount(account *Account) *HostCookie {
+	if account == nil || account.CookieSync.Cookie.Mode == "" {
+		return cfg
+	}
+
+	accountCfg := *cfg
+	accountCfg.Mode = account.CookieSync.Cookie.Mode
+	if accountCfg.Mode == UIDsCookieModeFirstParty {
+		accountCfg.UIDsCookieName = account.CookieSync.Cookie.Name
+		accountCfg.Domain = account.CookieSync.Cookie.Domain
+	}
+	return &accountCfg
+}
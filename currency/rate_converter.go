@@ -2,6 +2,7 @@ package currency
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -149,6 +150,18 @@ func (rc *RateConverter) checkStaleRates() bool {
 	return false
 }
 
+// Ready returns an error if the rates were never fetched, or are stale
+func (rc *RateConverter) Ready(_ context.Context) error {
+	lastUpdated := rc.LastUpdated()
+	if lastUpdated.IsZero() {
+		return errors.New("the currency rates were never fetched")
+	}
+	if rc.checkStaleRates() {
+		return fmt.Errorf("the currency rates are stale, they were last fetched at %s", lastUpdated.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
 // GetInfo returns setup information about the converter
 func (rc *RateConverter) GetInfo() ConverterInfo {
 	var rates *map[string]map[string]float64 = rc.Rates().GetRates()
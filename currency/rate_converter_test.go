@@ -1,6 +1,7 @@
 package currency
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -236,6 +237,30 @@ func TestRateStaleness(t *testing.T) {
 	assert.Equal(t, (initialFakeTime.Add(thirtyOneSec)), currencyConverter.LastUpdated(), "LastUpdated should be set")
 }
 
+func TestReady(t *testing.T) {
+	mockedHttpServer := httptest.NewServer(http.HandlerFunc(
+		func(rw http.ResponseWriter, req *http.Request) {
+			rw.Write([]byte(getMockRates()))
+		}),
+	)
+	defer mockedHttpServer.Close()
+
+	initialFakeTime := time.Date(2018, time.September, 12, 30, 0, 0, 0, time.UTC)
+	fakeTime := &FakeTime{time: initialFakeTime}
+	currencyConverter := NewRateConverter(&http.Client{}, 60*time.Second, mockedHttpServer.URL, 30*time.Second)
+	currencyConverter.time = fakeTime
+
+	// Not ready until the rates are fetched
+	assert.EqualError(t, currencyConverter.Ready(context.Background()), "the currency rates were never fetched")
+
+	assert.NoError(t, currencyConverter.Run())
+	assert.NoError(t, currencyConverter.Ready(context.Background()))
+
+	// Not ready once the rates are stale
+	fakeTime.time = fakeTime.time.Add(31 * time.Second)
+	assert.EqualError(t, currencyConverter.Ready(context.Background()), "the currency rates are stale, they were last fetched at 2018-09-13T06:00:00Z")
+}
+
 func TestRatesAreNeverConsideredStale(t *testing.T) {
 	callCount := 0
 	mockedHttpServer := httptest.NewServer(http.HandlerFunc(
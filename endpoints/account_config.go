@@ -0,0 +1,126 @@
+package endpoints
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	accountService "github.com/prebid/prebid-server/v3/account"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// secretFieldPattern matches the names of JSON fields whose values must not be exposed by the admin endpoints
+var secretFieldPattern = regexp.MustCompile(`(?i)(password|secret|token|api_?key|credential|private_?key)`)
+
+const redactedValue = "<REDACTED>"
+
+type accountConfigResponse struct {
+	ID         string                      `json:"id"`
+	Account    json.RawMessage             `json:"account,omitempty"`
+	Resolution *stored_requests.Inspection `json:"resolution,omitempty"`
+	Errors     []string                    `json:"errors,omitempty"`
+}
+
+// NewAccountConfigEndpoint returns a handler which writes the effective configuration of the account given in the
+// "id" query parameter, as resolved for auctions, along with how the accounts fetcher resolves the account data.
+// The account is resolved without the caches, which are left untouched, and no metrics are recorded. Secrets are
+// redacted.
+func NewAccountConfigEndpoint(cfg *config.Configuration, accountsFetcher stored_requests.AccountFetcher) http.HandlerFunc {
+	me := &metricsConf.NilMetricsEngine{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID := r.URL.Query().Get("id")
+		if accountID == "" {
+			http.Error(w, `Missing required query parameter "id"`, http.StatusBadRequest)
+			return
+		}
+
+		response := accountConfigResponse{ID: accountID}
+
+		resolution, errs := stored_requests.InspectAccount(r.Context(), accountsFetcher, accountID)
+		redactInspection(&resolution)
+		response.Resolution = &resolution
+		response.Errors = appendErrorStrings(response.Errors, errs)
+
+		account, errs := accountService.GetAccount(r.Context(), cfg, stored_requests.Uncached(accountsFetcher), accountID, me)
+		response.Errors = appendErrorStrings(response.Errors, errs)
+		if account != nil {
+			accountJSON, err := jsonutil.Marshal(account)
+			if err != nil {
+				logger.Errorf("/account Critical error when trying to marshal account %s: %v", accountID, err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			response.Account = redactSecrets(accountJSON)
+		}
+
+		writeAdminResponse(w, "/account", response)
+	}
+}
+
+func writeAdminResponse(w http.ResponseWriter, endpoint string, response interface{}) {
+	jsonOutput, err := jsonutil.Marshal(response)
+	if err != nil {
+		logger.Errorf("%s Critical error when trying to marshal response: %v", endpoint, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonOutput)
+}
+
+func appendErrorStrings(messages []string, errs []error) []string {
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+func redactInspection(inspection *stored_requests.Inspection) {
+	inspection.Cached = redactSecrets(inspection.Cached)
+	inspection.Backend = redactSecrets(inspection.Backend)
+}
+
+// redactSecrets replaces the values of all fields of the JSON document whose names look like they hold secrets.
+// Documents which can't be parsed are dropped entirely, since they can't be checked.
+func redactSecrets(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return data
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil
+	}
+
+	redacted, err := jsonutil.Marshal(redactValue(document))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if secretFieldPattern.MatchString(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = redactValue(element)
+		}
+	}
+	return value
+}
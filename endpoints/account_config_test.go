@@ -0,0 +1,133 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountConfigEndpoint(t *testing.T) {
+	fetcher := FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+		"valid":    json.RawMessage(`{"id":"valid","debug_allow":true,"hooks":{"modules":{"vendor":{"module":{"api_key":"1234","enabled":true}}}}}`),
+		"disabled": json.RawMessage(`{"id":"disabled","disabled":true}`),
+	}}
+
+	testCases := []struct {
+		description        string
+		query              string
+		expectedCode       int
+		expectAccount      bool
+		expectedModuleCfg  string
+		expectedBackend    string
+		expectedErrorCount int
+	}{
+		{
+			description:  "missing-id",
+			query:        "",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:       "valid-account-redacted",
+			query:             "?id=valid",
+			expectedCode:      http.StatusOK,
+			expectAccount:     true,
+			expectedModuleCfg: `{"api_key":"<REDACTED>","enabled":true}`,
+			expectedBackend:   `{"id":"valid","debug_allow":true,"hooks":{"modules":{"vendor":{"module":{"api_key":"<REDACTED>","enabled":true}}}}}`,
+		},
+		{
+			description:        "disabled-account",
+			query:              "?id=disabled",
+			expectedCode:       http.StatusOK,
+			expectedBackend:    `{"id":"disabled","disabled":true}`,
+			expectedErrorCount: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			endpoint := NewAccountConfigEndpoint(&config.Configuration{}, fetcher)
+
+			request := httptest.NewRequest(http.MethodGet, "/account"+test.query, nil)
+			recorder := httptest.NewRecorder()
+			endpoint(recorder, request)
+
+			require.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				ID      string `json:"id"`
+				Account *struct {
+					ID    string `json:"id"`
+					Hooks struct {
+						Modules map[string]map[string]json.RawMessage `json:"modules"`
+					} `json:"hooks"`
+				} `json:"account"`
+				Resolution struct {
+					Cache   string          `json:"cache"`
+					Backend json.RawMessage `json:"backend"`
+				} `json:"resolution"`
+				Errors []string `json:"errors"`
+			}
+			require.NoError(t, jsonutil.UnmarshalValid(recorder.Body.Bytes(), &response))
+
+			assert.Equal(t, "none", response.Resolution.Cache)
+			assert.JSONEq(t, test.expectedBackend, string(response.Resolution.Backend))
+			assert.Len(t, response.Errors, test.expectedErrorCount)
+			if !test.expectAccount {
+				assert.Nil(t, response.Account)
+				return
+			}
+			require.NotNil(t, response.Account)
+			assert.Equal(t, response.ID, response.Account.ID)
+			assert.JSONEq(t, test.expectedModuleCfg, string(response.Account.Hooks.Modules["vendor"]["module"]))
+		})
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	testCases := []struct {
+		description string
+		given       string
+		expected    string
+	}{
+		{
+			description: "empty",
+			given:       ``,
+			expected:    ``,
+		},
+		{
+			description: "nested-fields",
+			given:       `{"a":{"password":"p","user":"u"},"b":[{"clientSecret":"s"},{"token":1}],"apiKey":"k"}`,
+			expected:    `{"a":{"password":"<REDACTED>","user":"u"},"b":[{"clientSecret":"<REDACTED>"},{"token":"<REDACTED>"}],"apiKey":"<REDACTED>"}`,
+		},
+		{
+			description: "large-numbers-preserved",
+			given:       `{"id":12345678901234567890}`,
+			expected:    `{"id":12345678901234567890}`,
+		},
+		{
+			description: "malformed-dropped",
+			given:       `{"password":`,
+			expected:    ``,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			result := redactSecrets(json.RawMessage(test.given))
+			if test.expected == "" {
+				assert.Empty(t, result)
+				return
+			}
+			assert.JSONEq(t, test.expected, string(result))
+		})
+	}
+}
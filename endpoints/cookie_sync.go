@@ -15,6 +15,7 @@ import (
 	"github.com/julienschmidt/httprouter"
 	gpplib "github.com/prebid/go-gpp"
 	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/prebid-server/v3/accesslog"
 	accountService "github.com/prebid/prebid-server/v3/account"
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
@@ -58,7 +59,8 @@ func NewCookieSyncEndpoint(
 	metrics metrics.MetricsEngine,
 	analyticsRunner analytics.Runner,
 	accountsFetcher stored_requests.AccountFetcher,
-	bidders map[string]openrtb_ext.BidderName) HTTPRouterHandler {
+	bidders map[string]openrtb_ext.BidderName,
+	cookieStore *usersync.CookieStore) HTTPRouterHandler {
 
 	bidderHashSet := make(map[string]struct{}, len(bidders))
 	for _, bidder := range bidders {
@@ -79,6 +81,7 @@ func NewCookieSyncEndpoint(
 		pbsAnalytics:    analyticsRunner,
 		accountsFetcher: accountsFetcher,
 		time:            &timeutil.RealTime{},
+		cookieStore:     cookieStore,
 	}
 }
 
@@ -90,34 +93,46 @@ type cookieSyncEndpoint struct {
 	pbsAnalytics    analytics.Runner
 	accountsFetcher stored_requests.AccountFetcher
 	time            timeutil.Time
+	cookieStore     *usersync.CookieStore
 }
 
 func (c *cookieSyncEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	request, privacyMacros, account, err := c.parseRequest(r)
 	c.setCookieDeprecationHeader(w, r, account)
+	accessLogEntry := accesslog.FromContext(r.Context())
+	accessLogEntry.SetAccount(account)
 	if err != nil {
 		c.writeParseRequestErrorMetrics(err)
-		c.handleError(w, err, http.StatusBadRequest)
+		c.handleError(w, err, http.StatusBadRequest, account)
 		return
 	}
 	decoder := usersync.Base64Decoder{}
 
-	cookie := usersync.ReadCookie(r, decoder, &c.config.HostCookie)
-	usersync.SyncHostCookie(r, cookie, &c.config.HostCookie)
+	hostCookie := c.config.HostCookie.ForAccount(account)
+	cookie := c.cookieStore.ReadCookie(r, decoder, hostCookie)
+	usersync.SyncHostCookie(r, cookie, hostCookie)
 
 	result := c.chooser.Choose(request, cookie)
 
+	gppSID, _ := stringutil.StrToInt8Slice(privacyMacros.GPPSID)
+	accessLogEntry.SetPrivacySignals(privacyMacros.GDPR == "1", privacyMacros.USPrivacy, gppSID)
+	syncedBidders := make([]string, 0, len(result.SyncersChosen))
+	for _, syncerChoice := range result.SyncersChosen {
+		syncedBidders = append(syncedBidders, syncerChoice.Bidder)
+	}
+	accessLogEntry.SetBidders(syncedBidders)
+
 	switch result.Status {
 	case usersync.StatusBlockedByUserOptOut:
 		c.metrics.RecordCookieSync(metrics.CookieSyncOptOut)
-		c.handleError(w, errCookieSyncOptOut, http.StatusUnauthorized)
+		c.handleError(w, errCookieSyncOptOut, http.StatusUnauthorized, account)
 	case usersync.StatusBlockedByPrivacy:
 		c.metrics.RecordCookieSync(metrics.CookieSyncGDPRHostCookieBlocked)
-		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, nil, result.BiddersEvaluated, request.Debug)
+		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, nil, result.BiddersEvaluated, request.Debug, hostCookie, account)
 	case usersync.StatusOK:
 		c.metrics.RecordCookieSync(metrics.CookieSyncOK)
 		c.writeSyncerMetrics(result.BiddersEvaluated)
-		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, result.SyncersChosen, result.BiddersEvaluated, request.Debug)
+		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, result.SyncersChosen, result.BiddersEvaluated, request.Debug, hostCookie, account)
 	}
 }
 
@@ -148,6 +163,7 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, ma
 	if err != nil {
 		return usersync.Request{}, macros.UserSyncPrivacy{}, account, err
 	}
+	privacyPolicies.GPC = r.Header.Get("Sec-GPC") == "1"
 
 	ccpaParsedPolicy := ccpa.ParsedPolicy{}
 	if request.USPrivacy != "" {
@@ -252,7 +268,9 @@ func extractPrivacyPolicies(request cookieSyncRequest, usersyncDefaultGDPRValue
 	}
 
 	privacyPolicies := privacy.Policies{
-		GPPSID: gppSID,
+		GPPSID:     gppSID,
+		GPP:        request.GPP,
+		TCFInScope: gdprSignal == gdpr.SignalYes,
 	}
 
 	return privacyMacros, gdprSignal, privacyPolicies, nil
@@ -400,12 +418,13 @@ func parseBidderFilter(filter *cookieSyncRequestFilter) (usersync.BidderFilter,
 	}
 }
 
-func (c *cookieSyncEndpoint) handleError(w http.ResponseWriter, err error, httpStatus int) {
+func (c *cookieSyncEndpoint) handleError(w http.ResponseWriter, err error, httpStatus int, account *config.Account) {
 	http.Error(w, err.Error(), httpStatus)
 	c.pbsAnalytics.LogCookieSyncObject(&analytics.CookieSyncObject{
 		Status:       httpStatus,
 		Errors:       []error{err},
 		BidderStatus: []*analytics.CookieSyncBidder{},
+		Account:      account,
 	})
 }
 
@@ -443,7 +462,7 @@ func (c *cookieSyncEndpoint) writeSyncerMetrics(biddersEvaluated []usersync.Bidd
 	}
 }
 
-func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.SyncTypeFilter, co *usersync.Cookie, m macros.UserSyncPrivacy, s []usersync.SyncerChoice, biddersEvaluated []usersync.BidderEvaluation, debug bool) {
+func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.SyncTypeFilter, co *usersync.Cookie, m macros.UserSyncPrivacy, s []usersync.SyncerChoice, biddersEvaluated []usersync.BidderEvaluation, debug bool, hostCookie *config.HostCookie, account *config.Account) {
 	status := "no_cookie"
 	if co.HasAnyLiveSyncs() {
 		status = "ok"
@@ -451,9 +470,16 @@ func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.S
 
 	response := cookieSyncResponse{
 		Status:       status,
+		CookieMode:   string(hostCookie.UIDsCookieMode()),
 		BidderStatus: make([]cookieSyncResponseBidder, 0, len(s)),
 	}
 
+	if hostCookie.UIDsCookieMode() == config.UIDsCookieModeFirstParty {
+		// the /setuid redirects point to the CNAME of Prebid Server of the account, so that the uids cookie is set under
+		// the domain of the publisher
+		m.ExternalURL = account.CookieSync.Cookie.ExternalURL
+	}
+
 	for _, syncerChoice := range s {
 		syncTypes := tf.ForBidder(syncerChoice.Bidder)
 		sync, err := syncerChoice.Syncer.GetSync(syncTypes, m)
@@ -494,6 +520,7 @@ func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.S
 	c.pbsAnalytics.LogCookieSyncObject(&analytics.CookieSyncObject{
 		Status:       http.StatusOK,
 		BidderStatus: mapBidderStatusToAnalytics(response.BidderStatus),
+		Account:      account,
 	})
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -593,6 +620,7 @@ type cookieSyncRequestFilter struct {
 
 type cookieSyncResponse struct {
 	Status       string                     `json:"status"`
+	CookieMode   string                     `json:"cookie_mode"`
 	BidderStatus []cookieSyncResponseBidder `json:"bidder_status"`
 	Debug        []cookieSyncResponseDebug  `json:"debug,omitempty"`
 }
@@ -9,6 +9,7 @@ import (
 	"math"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
 	"strings"
 	"testing"
@@ -75,6 +76,7 @@ func TestNewCookieSyncEndpoint(t *testing.T) {
 		&analytics,
 		&fetcher,
 		bidders,
+		nil,
 	)
 	result := endpoint.(*cookieSyncEndpoint)
 
@@ -143,7 +145,7 @@ func TestCookieSyncHandle(t *testing.T) {
 				SyncersChosen:    []usersync.SyncerChoice{{Bidder: "a", Syncer: &syncer}},
 			},
 			expectedStatusCode: 200,
-			expectedBody: `{"status":"ok","bidder_status":[` +
+			expectedBody: `{"status":"ok","cookie_mode":"third_party","bidder_status":[` +
 				`{"bidder":"a","no_cookie":true,"usersync":{"url":"aURL","type":"redirect","supportCORS":true}}` +
 				`]}` + "\n",
 			setMetricsExpectations: func(m *metrics.MetricsEngineMock) {
@@ -162,7 +164,7 @@ func TestCookieSyncHandle(t *testing.T) {
 						},
 					},
 				}
-				a.On("LogCookieSyncObject", &expected).Once()
+				a.On("LogCookieSyncObject", matchCookieSyncObject(expected, "unknown")).Once()
 			},
 		},
 		{
@@ -175,7 +177,7 @@ func TestCookieSyncHandle(t *testing.T) {
 				SyncersChosen:    []usersync.SyncerChoice{{Bidder: "a", Syncer: &syncer}},
 			},
 			expectedStatusCode: 200,
-			expectedBody: `{"status":"no_cookie","bidder_status":[` +
+			expectedBody: `{"status":"no_cookie","cookie_mode":"third_party","bidder_status":[` +
 				`{"bidder":"a","no_cookie":true,"usersync":{"url":"aURL","type":"redirect","supportCORS":true}}` +
 				`]}` + "\n",
 			setMetricsExpectations: func(m *metrics.MetricsEngineMock) {
@@ -194,7 +196,7 @@ func TestCookieSyncHandle(t *testing.T) {
 						},
 					},
 				}
-				a.On("LogCookieSyncObject", &expected).Once()
+				a.On("LogCookieSyncObject", matchCookieSyncObject(expected, "unknown")).Once()
 			},
 		},
 		{
@@ -217,7 +219,7 @@ func TestCookieSyncHandle(t *testing.T) {
 					Errors:       []error{errors.New("JSON parsing failed: expect { or n, but found m")},
 					BidderStatus: []*analytics.CookieSyncBidder{},
 				}
-				a.On("LogCookieSyncObject", &expected).Once()
+				a.On("LogCookieSyncObject", matchCookieSyncObject(expected, "")).Once()
 			},
 		},
 		{
@@ -240,7 +242,7 @@ func TestCookieSyncHandle(t *testing.T) {
 					Errors:       []error{errors.New("User has opted out")},
 					BidderStatus: []*analytics.CookieSyncBidder{},
 				}
-				a.On("LogCookieSyncObject", &expected).Once()
+				a.On("LogCookieSyncObject", matchCookieSyncObject(expected, "unknown")).Once()
 			},
 		},
 		{
@@ -253,7 +255,7 @@ func TestCookieSyncHandle(t *testing.T) {
 				SyncersChosen:    []usersync.SyncerChoice{{Bidder: "a", Syncer: &syncer}},
 			},
 			expectedStatusCode: 200,
-			expectedBody:       `{"status":"ok","bidder_status":[]}` + "\n",
+			expectedBody:       `{"status":"ok","cookie_mode":"third_party","bidder_status":[]}` + "\n",
 			setMetricsExpectations: func(m *metrics.MetricsEngineMock) {
 				m.On("RecordCookieSync", metrics.CookieSyncGDPRHostCookieBlocked).Once()
 			},
@@ -263,7 +265,7 @@ func TestCookieSyncHandle(t *testing.T) {
 					Errors:       nil,
 					BidderStatus: []*analytics.CookieSyncBidder{},
 				}
-				a.On("LogCookieSyncObject", &expected).Once()
+				a.On("LogCookieSyncObject", matchCookieSyncObject(expected, "unknown")).Once()
 			},
 		},
 		{
@@ -276,7 +278,7 @@ func TestCookieSyncHandle(t *testing.T) {
 				SyncersChosen:    []usersync.SyncerChoice{{Bidder: "a", Syncer: &syncer}},
 			},
 			expectedStatusCode: 200,
-			expectedBody: `{"status":"ok","bidder_status":[` +
+			expectedBody: `{"status":"ok","cookie_mode":"third_party","bidder_status":[` +
 				`{"bidder":"a","no_cookie":true,"usersync":{"url":"aURL","type":"redirect","supportCORS":true}}` +
 				`],"debug":[{"bidder":"a","error":"Already in sync"}]}` + "\n",
 			setMetricsExpectations: func(m *metrics.MetricsEngineMock) {
@@ -295,7 +297,7 @@ func TestCookieSyncHandle(t *testing.T) {
 						},
 					},
 				}
-				a.On("LogCookieSyncObject", &expected).Once()
+				a.On("LogCookieSyncObject", matchCookieSyncObject(expected, "unknown")).Once()
 			},
 		},
 		{
@@ -312,7 +314,7 @@ func TestCookieSyncHandle(t *testing.T) {
 			},
 			expectedStatusCode:              200,
 			expectedCookieDeprecationHeader: true,
-			expectedBody: `{"status":"ok","bidder_status":[` +
+			expectedBody: `{"status":"ok","cookie_mode":"third_party","bidder_status":[` +
 				`{"bidder":"a","no_cookie":true,"usersync":{"url":"aURL","type":"redirect","supportCORS":true}}` +
 				`]}` + "\n",
 			setMetricsExpectations: func(m *metrics.MetricsEngineMock) {
@@ -331,7 +333,7 @@ func TestCookieSyncHandle(t *testing.T) {
 						},
 					},
 				}
-				a.On("LogCookieSyncObject", &expected).Once()
+				a.On("LogCookieSyncObject", matchCookieSyncObject(expected, "1")).Once()
 			},
 		},
 	}
@@ -403,6 +405,19 @@ func TestCookieSyncHandle(t *testing.T) {
 	}
 }
 
+// matchCookieSyncObject matches the logged cookie sync object, which carries the account of the given ID. The account
+// isn't compared as a whole, since it's filled with the defaults and derived config by the account service.
+func matchCookieSyncObject(expected analytics.CookieSyncObject, accountID string) interface{} {
+	return mock.MatchedBy(func(cso *analytics.CookieSyncObject) bool {
+		if accountID == "" && cso.Account != nil || accountID != "" && (cso.Account == nil || cso.Account.ID != accountID) {
+			return false
+		}
+		actual := *cso
+		actual.Account = nil
+		return assert.ObjectsAreEqual(expected, actual)
+	})
+}
+
 func TestExtractGDPRSignal(t *testing.T) {
 	type testInput struct {
 		requestGDPR *int
@@ -566,7 +581,7 @@ func TestExtractPrivacyPolicies(t *testing.T) {
 					GPPSID:      "6",
 				},
 				gdprSignal: gdpr.SignalNo,
-				policies:   privacy.Policies{GPPSID: []int8{6}},
+				policies:   privacy.Policies{GPPSID: []int8{6}, GPP: "DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN"},
 				err:        nil,
 			},
 		},
@@ -636,7 +651,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				Privacy: usersyncPrivacy{
 					gdprPermissions:  &fakePermissions{},
 					ccpaParsedPolicy: expectedCCPAParsedPolicy,
-					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: []int8{2}}),
+					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: []int8{2}, GPP: "DBABMA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA", TCFInScope: true}),
 					gdprSignal:       1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -678,7 +693,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				Privacy: usersyncPrivacy{
 					gdprPermissions:  &fakePermissions{},
 					ccpaParsedPolicy: expectedCCPAParsedPolicy,
-					activityRequest:  emptyActivityPoliciesRequest,
+					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{TCFInScope: true}),
 					gdprSignal:       1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -1479,7 +1494,7 @@ func TestWriteParseRequestErrorMetrics(t *testing.T) {
 	writer := httptest.NewRecorder()
 
 	endpoint := cookieSyncEndpoint{pbsAnalytics: &mockAnalytics}
-	endpoint.handleError(writer, err, 418)
+	endpoint.handleError(writer, err, 418, nil)
 
 	assert.Equal(t, writer.Code, 418)
 	assert.Equal(t, writer.Body.String(), "anyError\n")
@@ -1702,7 +1717,7 @@ func TestCookieSyncHandleError(t *testing.T) {
 	writer := httptest.NewRecorder()
 
 	endpoint := cookieSyncEndpoint{pbsAnalytics: &mockAnalytics}
-	endpoint.handleError(writer, err, 418)
+	endpoint.handleError(writer, err, 418, nil)
 
 	assert.Equal(t, writer.Code, 418)
 	assert.Equal(t, writer.Body.String(), "anyError\n")
@@ -1829,14 +1844,14 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 			description:         "None",
 			givenCookieHasSyncs: true,
 			givenSyncersChosen:  []usersync.SyncerChoice{},
-			expectedJSON:        `{"status":"ok","bidder_status":[]}` + "\n",
+			expectedJSON:        `{"status":"ok","cookie_mode":"third_party","bidder_status":[]}` + "\n",
 			expectedAnalytics:   analytics.CookieSyncObject{Status: 200, BidderStatus: []*analytics.CookieSyncBidder{}},
 		},
 		{
 			description:         "One",
 			givenCookieHasSyncs: true,
 			givenSyncersChosen:  []usersync.SyncerChoice{{Bidder: "foo", Syncer: &syncerA}},
-			expectedJSON: `{"status":"ok","bidder_status":[` +
+			expectedJSON: `{"status":"ok","cookie_mode":"third_party","bidder_status":[` +
 				`{"bidder":"foo","no_cookie":true,"usersync":{"url":"https://syncA.com/sync?a=1&b=2","type":"redirect","supportCORS":true}}` +
 				`]}` + "\n",
 			expectedAnalytics: analytics.CookieSyncObject{
@@ -1854,7 +1869,7 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 			description:         "Many",
 			givenCookieHasSyncs: true,
 			givenSyncersChosen:  []usersync.SyncerChoice{{Bidder: "foo", Syncer: &syncerA}, {Bidder: "bar", Syncer: &syncerB}},
-			expectedJSON: `{"status":"ok","bidder_status":[` +
+			expectedJSON: `{"status":"ok","cookie_mode":"third_party","bidder_status":[` +
 				`{"bidder":"foo","no_cookie":true,"usersync":{"url":"https://syncA.com/sync?a=1&b=2","type":"redirect","supportCORS":true}},` +
 				`{"bidder":"bar","no_cookie":true,"usersync":{"url":"https://syncB.com/sync?a=1&b=2","type":"redirect"}}` +
 				`]}` + "\n",
@@ -1878,7 +1893,7 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 			description:         "Many With One GetSync Error",
 			givenCookieHasSyncs: true,
 			givenSyncersChosen:  []usersync.SyncerChoice{{Bidder: "foo", Syncer: &syncerWithError}, {Bidder: "bar", Syncer: &syncerB}},
-			expectedJSON: `{"status":"ok","bidder_status":[` +
+			expectedJSON: `{"status":"ok","cookie_mode":"third_party","bidder_status":[` +
 				`{"bidder":"bar","no_cookie":true,"usersync":{"url":"https://syncB.com/sync?a=1&b=2","type":"redirect"}}` +
 				`]}` + "\n",
 			expectedAnalytics: analytics.CookieSyncObject{
@@ -1896,7 +1911,7 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 			description:         "No Existing Syncs",
 			givenCookieHasSyncs: false,
 			givenSyncersChosen:  []usersync.SyncerChoice{},
-			expectedJSON:        `{"status":"no_cookie","bidder_status":[]}` + "\n",
+			expectedJSON:        `{"status":"no_cookie","cookie_mode":"third_party","bidder_status":[]}` + "\n",
 			expectedAnalytics:   analytics.CookieSyncObject{Status: 200, BidderStatus: []*analytics.CookieSyncBidder{}},
 		},
 		{
@@ -1904,7 +1919,7 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 			givenCookieHasSyncs: true,
 			givenDebug:          true,
 			givenSyncersChosen:  []usersync.SyncerChoice{},
-			expectedJSON:        `{"status":"ok","bidder_status":[],"debug":[{"bidder":"Bidder1","error":"Already in sync"},{"bidder":"Bidder2","error":"Unsupported bidder"},{"bidder":"Bidder3","error":"No sync config"},{"bidder":"Bidder4","error":"Rejected by privacy"},{"bidder":"Bidder5","error":"Rejected by request filter"},{"bidder":"Bidder6","error":"Status blocked by user opt out"},{"bidder":"Bidder7","error":"Sync disabled by config"},{"bidder":"BidderA","error":"Duplicate bidder synced as syncerB"}]}` + "\n",
+			expectedJSON:        `{"status":"ok","cookie_mode":"third_party","bidder_status":[],"debug":[{"bidder":"Bidder1","error":"Already in sync"},{"bidder":"Bidder2","error":"Unsupported bidder"},{"bidder":"Bidder3","error":"No sync config"},{"bidder":"Bidder4","error":"Rejected by privacy"},{"bidder":"Bidder5","error":"Rejected by request filter"},{"bidder":"Bidder6","error":"Status blocked by user opt out"},{"bidder":"Bidder7","error":"Sync disabled by config"},{"bidder":"BidderA","error":"Duplicate bidder synced as syncerB"}]}` + "\n",
 			expectedAnalytics:   analytics.CookieSyncObject{Status: 200, BidderStatus: []*analytics.CookieSyncBidder{}},
 		},
 	}
@@ -1929,7 +1944,7 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 		} else {
 			bidderEval = []usersync.BidderEvaluation{}
 		}
-		endpoint.handleResponse(writer, syncTypeFilter, cookie, privacyMacros, test.givenSyncersChosen, bidderEval, test.givenDebug)
+		endpoint.handleResponse(writer, syncTypeFilter, cookie, privacyMacros, test.givenSyncersChosen, bidderEval, test.givenDebug, &config.HostCookie{}, nil)
 
 		if assert.Equal(t, writer.Code, http.StatusOK, test.description+":http_status") {
 			assert.Equal(t, writer.Header().Get("Content-Type"), "application/json; charset=utf-8", test.description+":http_header")
@@ -1939,6 +1954,31 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 	}
 }
 
+func TestCookieSyncHandleResponseFirstParty(t *testing.T) {
+	syncTypeFilter := usersync.SyncTypeFilter{
+		IFrame:   usersync.NewUniformBidderFilter(usersync.BidderFilterModeExclude),
+		Redirect: usersync.NewUniformBidderFilter(usersync.BidderFilterModeInclude),
+	}
+	privacyMacros := macros.UserSyncPrivacy{}
+	syncURL := "https://sync.com/sync?redirect=" + url.QueryEscape("https://pbs.publisher.com/setuid?bidder=foo&uid=$UID")
+	syncer := MockSyncer{}
+	syncer.On("GetSync", []usersync.SyncType{usersync.SyncTypeRedirect}, macros.UserSyncPrivacy{ExternalURL: "https://pbs.publisher.com"}).Return(usersync.Sync{URL: syncURL, Type: usersync.SyncTypeRedirect}, nil)
+
+	account := &config.Account{CookieSync: config.CookieSync{Cookie: config.AccountUIDsCookie{Mode: config.UIDsCookieModeFirstParty, Domain: "publisher.com", ExternalURL: "https://pbs.publisher.com"}}}
+	cfg := &config.Configuration{ExternalURL: "https://pbs.example.com"}
+	mockAnalytics := MockAnalyticsRunner{}
+	mockAnalytics.On("LogCookieSyncObject", mock.Anything)
+	endpoint := cookieSyncEndpoint{config: cfg, pbsAnalytics: &mockAnalytics}
+
+	writer := httptest.NewRecorder()
+	endpoint.handleResponse(writer, syncTypeFilter, usersync.NewCookie(), privacyMacros, []usersync.SyncerChoice{{Bidder: "foo", Syncer: &syncer}}, nil, false, cfg.HostCookie.ForAccount(account), account)
+
+	assert.Equal(t, `{"status":"no_cookie","cookie_mode":"first_party","bidder_status":[`+
+		`{"bidder":"foo","no_cookie":true,"usersync":{"url":"`+syncURL+`","type":"redirect"}}`+
+		`]}`+"\n", writer.Body.String())
+	syncer.AssertExpectations(t)
+}
+
 func TestMapBidderStatusToAnalytics(t *testing.T) {
 	testCases := []struct {
 		description string
@@ -2754,6 +2794,7 @@ func TestCookieSyncPriorityGroupsIntegration(t *testing.T) {
 					},
 				},
 				bidders,
+				nil,
 			)
 			// Create test request
 			request := httptest.NewRequest("POST", "/cookie_sync", strings.NewReader(tc.givenRequestBody))
@@ -2927,6 +2968,7 @@ func TestCookieSyncPriorityGroupsEdgeCases(t *testing.T) {
 					},
 				},
 				bidders,
+				nil,
 			)
 
 			// Create test request
@@ -13,6 +13,7 @@ import (
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/accesslog"
 	accountService "github.com/prebid/prebid-server/v3/account"
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
@@ -110,6 +111,8 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 		return
 	}
 
+	accesslog.FromContext(r.Context()).SetAccount(account)
+
 	// Check if events are enabled for the account
 	if !account.Events.Enabled {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/accesslog"
 	accountService "github.com/prebid/prebid-server/v3/account"
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
@@ -109,6 +110,8 @@ func (v *vtrackEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httpro
 		return
 	}
 
+	accesslog.FromContext(r.Context()).SetAccount(account)
+
 	// insert impression tracking if account allows events and bidder allows VAST modification
 	if v.Cache != nil {
 		cachingResponse, errs := v.handleVTrackRequest(ctx, req, account, integrationType)
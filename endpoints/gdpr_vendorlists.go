@@ -0,0 +1,19 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/prebid/prebid-server/v3/gdpr"
+)
+
+type vendorListsResponse struct {
+	VendorLists []gdpr.VendorListVersions `json:"vendor_lists"`
+}
+
+// NewVendorListsEndpoint returns a handler which writes the cached GDPR vendor list versions of each spec version,
+// with when and from where the latest one was fetched.
+func NewVendorListsEndpoint(versions func() []gdpr.VendorListVersions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeAdminResponse(w, "/gdpr/vendorlists", vendorListsResponse{VendorLists: versions()})
+	}
+}
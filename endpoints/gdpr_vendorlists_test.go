@@ -0,0 +1,32 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVendorListsEndpoint(t *testing.T) {
+	fetched := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	versions := func() []gdpr.VendorListVersions {
+		return []gdpr.VendorListVersions{
+			{SpecVersion: 2, ListVersions: []uint16{1, 2}, Latest: 2, LatestFetched: fetched, LatestSource: gdpr.VendorListSourceSnapshot},
+			{SpecVersion: 3, ListVersions: []uint16{42}, Latest: 42, LatestFetched: fetched, LatestSource: gdpr.VendorListSourceNetwork},
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	NewVendorListsEndpoint(versions)(recorder, httptest.NewRequest(http.MethodGet, "/gdpr/vendorlists", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"vendor_lists":[
+		{"spec_version":2,"list_versions":[1,2],"latest":2,"latest_fetched":"2024-05-01T12:00:00Z","latest_source":"snapshot"},
+		{"spec_version":3,"list_versions":[42],"latest":42,"latest_fetched":"2024-05-01T12:00:00Z","latest_source":"network"}
+	]}`, recorder.Body.String())
+}
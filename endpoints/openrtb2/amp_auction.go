@@ -20,6 +20,7 @@ import (
 	"github.com/prebid/prebid-server/v3/util/uuidutil"
 	jsonpatch "gopkg.in/evanphx/json-patch.v5"
 
+	"github.com/prebid/prebid-server/v3/accesslog"
 	accountService "github.com/prebid/prebid-server/v3/account"
 	"github.com/prebid/prebid-server/v3/amp"
 	"github.com/prebid/prebid-server/v3/analytics"
@@ -31,9 +32,12 @@ import (
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/privacy/optout"
+	"github.com/prebid/prebid-server/v3/privacy/pseudonym"
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_responses"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/iputil"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
@@ -70,6 +74,9 @@ func NewAmpEndpoint(
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	pseudonymKeys *pseudonym.Keyring,
+	optOut *optout.Registry,
+	cookieStore *usersync.CookieStore,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
@@ -104,6 +111,9 @@ func NewAmpEndpoint(
 		hookExecutionPlanBuilder,
 		tmaxAdjustments,
 		openrtb_ext.NormalizeBidderName,
+		pseudonymKeys,
+		optOut,
+		cookieStore,
 	}).AmpAuction), nil
 
 }
@@ -136,7 +146,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 
 	defer func() {
 		deps.metricsEngine.RecordRequest(labels)
-		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
+		metrics.RecordRequestTime(r.Context(), deps.metricsEngine, labels, time.Since(start))
 		deps.analytics.LogAmpObject(&ao, activityControl)
 	}()
 
@@ -177,7 +187,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 
 	ao.RequestWrapper = reqWrapper
 
-	ctx := context.Background()
+	ctx := auctionContext(r)
 	var cancel context.CancelFunc
 	if reqWrapper.TMax > 0 {
 		ctx, cancel = context.WithDeadline(ctx, start.Add(time.Duration(reqWrapper.TMax)*time.Millisecond))
@@ -186,16 +196,11 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	}
 	defer cancel()
 
-	// Read UserSyncs/Cookie from Request
-	usersyncs := usersync.ReadCookie(r, usersync.Base64Decoder{}, &deps.cfg.HostCookie)
-	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
-	if usersyncs.HasAnyLiveSyncs() {
-		labels.CookieFlag = metrics.CookieFlagYes
-	} else {
-		labels.CookieFlag = metrics.CookieFlagNo
-	}
-
 	labels.PubID = getAccountID(reqWrapper.Site.Publisher)
+	// Read UserSyncs/Cookie from Request before the account lookup, so that the cookie metric is recorded even if it fails
+	usersyncs := deps.readCookie(r, nil)
+	labels.CookieFlag = cookieFlag(usersyncs)
+
 	// Look up account now that we have resolved the pubID value
 	account, acctIDErrs := accountService.GetAccount(ctx, deps.cfg, deps.accounts, labels.PubID, deps.metricsEngine)
 	if len(acctIDErrs) > 0 {
@@ -227,6 +232,16 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		ao.Errors = append(ao.Errors, acctIDErrs...)
 		return
 	}
+	ao.Account = account
+
+	// Read UserSyncs/Cookie again if the account overrides the cookie mode of the host
+	if account.CookieSync.Cookie.Mode != "" {
+		usersyncs = deps.readCookie(r, account)
+		labels.CookieFlag = cookieFlag(usersyncs)
+	}
+	ctx = logger.WithFields(ctx, logger.FieldAccountID, account.ID)
+	accessLogEntry := accesslog.FromContext(r.Context())
+	accessLogEntry.SetAccount(account)
 
 	// Populate any "missing" OpenRTB fields with info from other sources, (e.g. HTTP request headers).
 	if errs := deps.setFieldsImplicitly(r, reqWrapper, account); len(errs) > 0 {
@@ -247,12 +262,18 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	}
 
 	activityControl = privacy.NewActivityControl(&account.Privacy)
+	activityControl.SetPseudonymKeys(deps.pseudonymKeys, account.ID)
+	deps.applyOptOut(&activityControl, reqWrapper)
+	activityControl.SetGPCHeader(r.Header.Get(secGPCKey))
+	activityControl.SetEndpointChannel(config.ChannelAMP)
 
 	hookExecutor.SetActivityControl(activityControl)
 	hookExecutor.SetAccount(account)
 
 	tcf2Config, gdprSignal, gdprEnforced, gdprErrs := deps.processGDPR(reqWrapper, account.GDPR, labels.RType)
 	errL = append(errL, gdprErrs...)
+	accessLogEntry.SetRequest(reqWrapper)
+	accessLogEntry.SetGDPR(gdprEnforced)
 
 	secGPC := r.Header.Get("Sec-GPC")
 
@@ -286,6 +307,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	var response *openrtb2.BidResponse
 	if auctionResponse != nil {
 		response = auctionResponse.BidResponse
+		accessLogEntry.SetBidResponse(response, auctionResponse.ExtBidResponse)
 	}
 	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
 	ao.AuctionResponse = response
@@ -293,7 +315,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	if err != nil && !isRejectErr {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "Critical error while running the auction: %v", err)
-		logger.Errorf("/openrtb2/amp Critical error: %v", err)
+		logger.WithContext(ctx).Errorf("/openrtb2/amp Critical error: %v", err)
 		ao.Status = http.StatusInternalServerError
 		ao.Errors = append(ao.Errors, err)
 		return
@@ -304,7 +326,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	if err := reqWrapper.RebuildRequest(); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "Critical error while running the auction: %v", err)
-		logger.Errorf("/openrtb2/amp Critical error: %v", err)
+		logger.WithContext(ctx).Errorf("/openrtb2/amp Critical error: %v", err)
 		ao.Status = http.StatusInternalServerError
 		ao.Errors = append(ao.Errors, err)
 		return
@@ -504,8 +526,13 @@ func getExtBidResponse(
 //
 // If the errors list has at least one element, then no guarantees are made about the returned request.
 func (deps *endpointDeps) parseAmpRequest(httpRequest *http.Request, labels metrics.Labels) (req *openrtb_ext.RequestWrapper, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImp stored_responses.BidderImpReplaceImpID, errs []error) {
+	spanCtx, span := tracing.Start(httpRequest.Context(), tracing.SpanParseRequest)
+	defer func() {
+		tracing.EndWithErrors(span, errortypes.FatalOnly(errs))
+	}()
+
 	// Load the stored request for the AMP ID.
-	reqNormal, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, e := deps.loadRequestJSONForAmp(httpRequest, labels)
+	reqNormal, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, e := deps.loadRequestJSONForAmp(httpRequest.WithContext(spanCtx), labels)
 	if errs = append(errs, e...); errortypes.ContainsFatalError(errs) {
 		return
 	}
@@ -545,10 +572,12 @@ func (deps *endpointDeps) loadRequestJSONForAmp(httpRequest *http.Request, label
 		return nil, nil, nil, nil, []error{err}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(deps.cfg.StoredRequestsTimeout)*time.Millisecond)
+	ctx, cancel := context.WithTimeout(tracing.ContextWithSpanFrom(context.Background(), httpRequest.Context()), time.Duration(deps.cfg.StoredRequestsTimeout)*time.Millisecond)
 	defer cancel()
 
-	storedRequests, _, errs := deps.storedReqFetcher.FetchRequests(ctx, []string{ampParams.StoredRequestID}, nil)
+	fetchCtx, fetchSpan := tracing.Start(ctx, tracing.SpanFetchStoredRequests)
+	storedRequests, _, errs := deps.storedReqFetcher.FetchRequests(fetchCtx, []string{ampParams.StoredRequestID}, nil)
+	tracing.EndWithErrors(fetchSpan, errs)
 	if len(errs) > 0 {
 		return nil, nil, nil, nil, errs
 	}
@@ -35,6 +35,7 @@ import (
 	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/privacy"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
+	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 )
 
@@ -205,6 +206,77 @@ func TestAccountErrors(t *testing.T) {
 	}
 }
 
+func TestAmpCookieFlagOnAccountError(t *testing.T) {
+	liveCookie := usersync.NewCookie()
+	require.NoError(t, liveCookie.Sync("adnxs", "some-uid"))
+	encodedCookie, err := usersync.Base64Encoder{}.Encode(liveCookie)
+	require.NoError(t, err)
+
+	tests := []struct {
+		description    string
+		cookie         *http.Cookie
+		wantCookieFlag metrics.CookieFlag
+	}{
+		{
+			description:    "no_cookie",
+			wantCookieFlag: metrics.CookieFlagNo,
+		},
+		{
+			description:    "live_syncs",
+			cookie:         &http.Cookie{Name: "uids", Value: encodedCookie},
+			wantCookieFlag: metrics.CookieFlagYes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			stored := map[string]json.RawMessage{
+				"1": json.RawMessage(validRequest(t, "site.json")),
+			}
+			metricsEngine := &labelsRecordingMetricsEngine{}
+
+			endpoint, _ := NewAmpEndpoint(
+				fakeUUIDGenerator{},
+				&mockAmpExchange{},
+				ortb.NewRequestValidator(openrtb_ext.BuildBidderMap(), map[string]string{}, newParamsValidator(t)),
+				&mockAmpStoredReqFetcher{stored},
+				empty_fetcher.EmptyFetcher{},
+				&config.Configuration{MaxRequestSize: maxSize, AccountRequired: true},
+				metricsEngine,
+				analyticsBuild.New(&config.Analytics{}),
+				map[string]string{},
+				[]byte{},
+				openrtb_ext.BuildBidderMap(),
+				empty_fetcher.EmptyFetcher{},
+				hooks.EmptyPlanBuilder{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			request := httptest.NewRequest("GET", "/openrtb2/auction/amp?tag_id=1", nil)
+			if tt.cookie != nil {
+				request.AddCookie(tt.cookie)
+			}
+			recorder := httptest.NewRecorder()
+			endpoint(recorder, request, nil)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, "the account lookup must fail")
+			assert.Equal(t, tt.wantCookieFlag, metricsEngine.labels.CookieFlag)
+		})
+	}
+}
+
+// labelsRecordingMetricsEngine records the labels of the last request
+type labelsRecordingMetricsEngine struct {
+	metricsConfig.NilMetricsEngine
+	labels metrics.Labels
+}
+
+func (me *labelsRecordingMetricsEngine) RecordRequest(labels metrics.Labels) {
+	me.labels = labels
+}
+
 // Prevents #683
 func TestAMPPageInfo(t *testing.T) {
 	const page = "http://test.somepage.co.uk:1234?myquery=1&other=2"
@@ -228,6 +300,9 @@ func TestAMPPageInfo(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	request := httptest.NewRequest("GET", fmt.Sprintf("/openrtb2/auction/amp?tag_id=1&curl=%s", url.QueryEscape(page)), nil)
 	recorder := httptest.NewRecorder()
@@ -325,6 +400,9 @@ func TestGDPRConsent(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -713,6 +791,9 @@ func TestCCPAConsent(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -820,6 +901,9 @@ func TestConsentWarnings(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -921,6 +1005,9 @@ func TestNewAndLegacyConsentBothProvided(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -969,6 +1056,9 @@ func TestAMPSiteExt(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	request, err := http.NewRequest("GET", "/openrtb2/auction/amp?tag_id=1", nil)
 	if !assert.NoError(t, err) {
@@ -1034,6 +1124,9 @@ func TestAmpBadRequests(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	for id, test := range badRequests {
@@ -1125,6 +1218,9 @@ func TestAmpDebug(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	for requestID := range requests {
@@ -1261,6 +1357,9 @@ func TestQueryParamOverrides(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	requestID := "1"
@@ -1419,6 +1518,9 @@ func (s formatOverrideSpec) execute(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	url := fmt.Sprintf("/openrtb2/auction/amp?tag_id=1&debug=1&w=%d&h=%d&ow=%d&oh=%d&ms=%s&account=%s", s.width, s.height, s.overrideWidth, s.overrideHeight, s.multisize, s.account)
@@ -1936,6 +2038,9 @@ func ampObjectTestSetup(t *testing.T, inTagId string, inStoredRequest json.RawMe
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	return &actualAmpObject, endpoint
 }
@@ -1989,6 +2094,9 @@ func TestAmpAuctionResponseHeaders(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	for _, test := range testCases {
@@ -2025,6 +2133,9 @@ func TestRequestWithTargeting(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	url, err := url.Parse("/openrtb2/auction/amp")
 	assert.NoError(t, err, "unexpected error received while parsing url")
@@ -2488,6 +2599,9 @@ func TestAmpAuctionDebugWarningsOnly(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	for _, test := range testCases {
@@ -26,11 +26,14 @@ import (
 	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/privacy/optout"
+	"github.com/prebid/prebid-server/v3/privacy/pseudonym"
 	"github.com/prebid/prebid-server/v3/privacysandbox"
 	"github.com/prebid/prebid-server/v3/schain"
 	"golang.org/x/net/publicsuffix"
 	jsonpatch "gopkg.in/evanphx/json-patch.v5"
 
+	"github.com/prebid/prebid-server/v3/accesslog"
 	accountService "github.com/prebid/prebid-server/v3/account"
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
@@ -47,6 +50,7 @@ import (
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_responses"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/httputil"
 	"github.com/prebid/prebid-server/v3/util/iputil"
@@ -98,6 +102,9 @@ func NewEndpoint(
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	pseudonymKeys *pseudonym.Keyring,
+	optOut *optout.Registry,
+	cookieStore *usersync.CookieStore,
 ) (httprouter.Handle, error) {
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
 		return nil, errors.New("NewEndpoint requires non-nil arguments.")
@@ -130,7 +137,9 @@ func NewEndpoint(
 		storedRespFetcher,
 		hookExecutionPlanBuilder,
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).Auction), nil
+		openrtb_ext.NormalizeBidderName,
+		pseudonymKeys,
+		optOut, cookieStore}).Auction), nil
 }
 
 type endpointDeps struct {
@@ -154,6 +163,40 @@ type endpointDeps struct {
 	hookExecutionPlanBuilder  hooks.ExecutionPlanBuilder
 	tmaxAdjustments           *exchange.TmaxAdjustmentsPreprocessed
 	normalizeBidderName       openrtb_ext.BidderNameNormalizer
+	pseudonymKeys             *pseudonym.Keyring
+	optOut                    *optout.Registry
+	cookieStore               *usersync.CookieStore
+}
+
+// applyOptOut denies the privacy activities of the auction if its user is in the opt-out registry
+func (deps *endpointDeps) applyOptOut(activityControl *privacy.ActivityControl, req *openrtb_ext.RequestWrapper) {
+	if entryType, ok := deps.optOut.Match(req.User, req.Device); ok {
+		activityControl.SetOptedOut()
+		deps.metricsEngine.RecordOptOutMatch(metrics.OptOutEntryType(entryType))
+	}
+}
+
+// auctionContext returns the context of the auction of the request. The auction outlives a canceled request, but its
+// logs carry the fields of the request and its spans belong to the trace of the request.
+func auctionContext(r *http.Request) context.Context {
+	ctx := logger.WithFields(context.Background(), logger.Fields(r.Context())...)
+	return tracing.ContextWithSpanFrom(ctx, r.Context())
+}
+
+// readCookie reads the usersyncs of the request in the cookie mode of the account, or of the host if the account is nil
+func (deps *endpointDeps) readCookie(r *http.Request, account *config.Account) *usersync.Cookie {
+	hostCookie := deps.cfg.HostCookie.ForAccount(account)
+	usersyncs := deps.cookieStore.ReadCookie(r, usersync.Base64Decoder{}, hostCookie)
+	usersync.SyncHostCookie(r, usersyncs, hostCookie)
+	return usersyncs
+}
+
+// cookieFlag returns the cookie metric label of the usersyncs
+func cookieFlag(usersyncs *usersync.Cookie) metrics.CookieFlag {
+	if usersyncs.HasAnyLiveSyncs() {
+		return metrics.CookieFlagYes
+	}
+	return metrics.CookieFlagNo
 }
 
 func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -184,7 +227,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	activityControl := privacy.ActivityControl{}
 	defer func() {
 		deps.metricsEngine.RecordRequest(labels)
-		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
+		metrics.RecordRequestTime(r.Context(), deps.metricsEngine, labels, time.Since(start))
 		deps.analytics.LogAuctionObject(&ao, activityControl)
 	}()
 
@@ -203,11 +246,18 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	}
 
 	activityControl = privacy.NewActivityControl(&account.Privacy)
+	activityControl.SetPseudonymKeys(deps.pseudonymKeys, account.ID)
+	deps.applyOptOut(&activityControl, req)
+	activityControl.SetGPCHeader(r.Header.Get(secGPCKey))
 
 	hookExecutor.SetActivityControl(activityControl)
 	hookExecutor.SetAccount(account)
 
-	ctx := context.Background()
+	accessLogEntry := accesslog.FromContext(r.Context())
+	accessLogEntry.SetAccount(account)
+	accessLogEntry.SetRequest(req)
+
+	ctx := logger.WithFields(auctionContext(r), logger.FieldAccountID, account.ID)
 
 	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(req.TMax) * time.Millisecond)
 	if timeout > 0 {
@@ -218,18 +268,13 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 
 	tcf2Config, gdprSignal, gdprEnforced, gdprErrs := deps.processGDPR(req, account.GDPR, labels.RType)
 	errL = append(errL, gdprErrs...)
+	accessLogEntry.SetGDPR(gdprEnforced)
 
-	// Read Usersyncs/Cookie
-	decoder := usersync.Base64Decoder{}
-	usersyncs := usersync.ReadCookie(r, decoder, &deps.cfg.HostCookie)
-	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
+	// Read Usersyncs/Cookie, in the cookie mode of the account
+	usersyncs := deps.readCookie(r, account)
 
 	if req.Site != nil {
-		if usersyncs.HasAnyLiveSyncs() {
-			labels.CookieFlag = metrics.CookieFlagYes
-		} else {
-			labels.CookieFlag = metrics.CookieFlagNo
-		}
+		labels.CookieFlag = cookieFlag(usersyncs)
 	}
 
 	// Set Integration Information
@@ -275,6 +320,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	var response *openrtb2.BidResponse
 	if auctionResponse != nil {
 		response = auctionResponse.BidResponse
+		accessLogEntry.SetBidResponse(response, auctionResponse.ExtBidResponse)
 	}
 	ao.Response = response
 	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
@@ -287,7 +333,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		labels.RequestStatus = metrics.RequestStatusErr
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "Critical error while running the auction: %v", err)
-		logger.Errorf("/openrtb2/auction Critical error: %v", err)
+		logger.WithContext(ctx).Errorf("/openrtb2/auction Critical error: %v", err)
 		ao.Status = http.StatusInternalServerError
 		ao.Errors = append(ao.Errors, err)
 		return
@@ -298,7 +344,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 
 	err = setSeatNonBidRaw(req, auctionResponse)
 	if err != nil {
-		logger.Errorf("Error setting seat non-bid: %v", err)
+		logger.WithContext(ctx).Errorf("Error setting seat non-bid: %v", err)
 	}
 	labels, ao = sendAuctionResponse(w, hookExecutor, response, req.BidRequest, account, labels, ao)
 }
@@ -417,6 +463,11 @@ func setBrowsingTopicsHeader(w http.ResponseWriter, r *http.Request) {
 //
 // If the errors list has at least one element, then no guarantees are made about the returned request.
 func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metrics.Labels, hookExecutor hookexecution.HookStageExecutor) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, errs []error) {
+	spanCtx, span := tracing.Start(httpRequest.Context(), tracing.SpanParseRequest)
+	defer func() {
+		tracing.EndWithErrors(span, errortypes.FatalOnly(errs))
+	}()
+
 	errs = nil
 	var err error
 	var errL []error
@@ -466,13 +517,13 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	if rejectErr != nil {
 		errs = []error{rejectErr}
 		if err = jsonutil.UnmarshalValid(requestJson, req.BidRequest); err != nil {
-			logger.Errorf("Failed to unmarshal BidRequest during entrypoint rejection: %s", err)
+			logger.WithContext(httpRequest.Context()).Errorf("Failed to unmarshal BidRequest during entrypoint rejection: %s", err)
 		}
 		return
 	}
 
 	timeout := parseTimeout(requestJson, time.Duration(deps.cfg.StoredRequestsTimeout)*time.Millisecond)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(tracing.ContextWithSpanFrom(context.Background(), spanCtx), timeout)
 	defer cancel()
 
 	impInfo, errs := parseImpInfo(requestJson)
@@ -514,7 +565,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	if rejectErr != nil {
 		errs = []error{rejectErr}
 		if err = jsonutil.UnmarshalValid(requestJson, req.BidRequest); err != nil {
-			logger.Errorf("Failed to unmarshal BidRequest during raw auction stage rejection: %s", err)
+			logger.WithContext(httpRequest.Context()).Errorf("Failed to unmarshal BidRequest during raw auction stage rejection: %s", err)
 		}
 		return
 	}
@@ -1681,7 +1732,9 @@ func (deps *endpointDeps) getStoredRequests(ctx context.Context, requestJson []b
 		}
 	}
 
-	storedRequests, storedImps, errs := deps.storedReqFetcher.FetchRequests(ctx, storedReqIds, impStoredReqIds)
+	fetchCtx, fetchSpan := tracing.Start(ctx, tracing.SpanFetchStoredRequests)
+	storedRequests, storedImps, errs := deps.storedReqFetcher.FetchRequests(fetchCtx, storedReqIds, impStoredReqIds)
+	tracing.EndWithErrors(fetchSpan, errs)
 	if len(errs) != 0 {
 		return "", false, nil, nil, errs
 	}
@@ -117,6 +117,9 @@ func BenchmarkOpenrtbEndpoint(b *testing.B) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	b.ResetTimer()
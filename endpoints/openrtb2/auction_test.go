@@ -37,12 +37,15 @@ import (
 	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/privacy/optout"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_responses"
 	"github.com/prebid/prebid-server/v3/util/iputil"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const jsonFileExtension string = ".json"
@@ -488,6 +491,9 @@ func TestExplicitUserId(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	endpoint(httptest.NewRecorder(), request, nil)
@@ -546,6 +552,9 @@ func doBadAliasRequest(t *testing.T, filename string, expectMsg string) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	request := httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(testBidRequest))
@@ -601,6 +610,9 @@ func TestNilExchange(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	if err == nil {
@@ -627,6 +639,9 @@ func TestExchangeError(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
@@ -754,6 +769,9 @@ func TestImplicitIPsEndToEnd(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, test.reqJSONFile)))
@@ -954,6 +972,9 @@ func TestImplicitDNTEndToEnd(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, test.reqJSONFile)))
@@ -1196,6 +1217,9 @@ func TestStoredRequests(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	testStoreVideoAttr := []bool{true, true, false, false, false}
@@ -1646,6 +1670,9 @@ func TestValidateRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	testCases := []struct {
@@ -2364,6 +2391,9 @@ func TestSetIntegrationType(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	testCases := []struct {
@@ -2431,6 +2461,9 @@ func TestStoredRequestGenerateUuid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	req := &openrtb2.BidRequest{}
@@ -2536,6 +2569,9 @@ func TestOversizedRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -2576,6 +2612,9 @@ func TestRequestSizeEdgeCase(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -2609,6 +2648,9 @@ func TestNoEncoding(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
 	recorder := httptest.NewRecorder()
@@ -2694,6 +2736,9 @@ func TestContentType(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
 	recorder := httptest.NewRecorder()
@@ -2737,6 +2782,9 @@ func TestCurrencyTrunc(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2786,6 +2834,9 @@ func TestCCPAInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2839,6 +2890,9 @@ func TestNoSaleInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2895,6 +2949,9 @@ func TestValidateSourceTID(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2941,6 +2998,9 @@ func TestSChainInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -3382,6 +3442,9 @@ func TestEidPermissionsInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -3656,6 +3719,9 @@ func TestIOS14EndToEnd(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "app-ios140-no-ifa.json")))
@@ -3724,6 +3790,9 @@ func TestAuctionWarnings(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
@@ -3771,6 +3840,9 @@ func TestParseRequestParseImpInfoError(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -3852,6 +3924,9 @@ func TestParseGzipedRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -3955,6 +4030,9 @@ func TestAuctionResponseHeaders(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	for _, test := range testCases {
@@ -4035,6 +4113,9 @@ func TestParseRequestMergeBidderParams(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				nil,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -4141,6 +4222,9 @@ func TestParseRequestStoredResponses(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				nil,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -4254,6 +4338,9 @@ func TestParseRequestStoredBidResponses(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				nil,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -4292,6 +4379,9 @@ func TestValidateStoredResp(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	testCases := []struct {
@@ -5115,6 +5205,9 @@ func TestParseRequestMultiBid(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				nil,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -5846,6 +5939,9 @@ func TestValidateRequestCookieDeprecation(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	for _, test := range testCases {
@@ -6616,3 +6712,128 @@ func TestProcessGDPR(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyOptOut(t *testing.T) {
+	registry, err := optout.NewRegistry(config.OptOut{Enabled: true}, nil)
+	require.NoError(t, err)
+	defer registry.Shutdown()
+	require.NoError(t, registry.Add([]string{"ifa:ab-cd"}))
+
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordOptOutMatch", metrics.OptOutEntryIFA).Once()
+	deps := &endpointDeps{metricsEngine: metricsMock, optOut: registry}
+	bidder := privacy.Component{Type: privacy.ComponentTypeBidder, Name: "appnexus"}
+
+	activityControl := privacy.NewActivityControl(&config.AccountPrivacy{})
+	deps.applyOptOut(&activityControl, &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{IFA: "ef-gh"}}})
+	assert.True(t, activityControl.Allow(privacy.ActivityTransmitUserFPD, bidder, privacy.ActivityRequest{}), "the users who didn't opt out are unaffected")
+
+	deps.applyOptOut(&activityControl, &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{IFA: "AB-CD"}}})
+	assert.False(t, activityControl.Allow(privacy.ActivityTransmitUserFPD, bidder, privacy.ActivityRequest{}))
+	assert.False(t, activityControl.Allow(privacy.ActivityReportAnalytics, bidder, privacy.ActivityRequest{}))
+	assert.True(t, activityControl.Allow(privacy.ActivityFetchBids, bidder, privacy.ActivityRequest{}))
+	metricsMock.AssertExpectations(t)
+}
+
+func TestGPCHeaderActivities(t *testing.T) {
+	cfg := &config.Configuration{
+		MaxRequestSize: maxSize,
+		AccountDefaults: config.Account{Privacy: config.AccountPrivacy{AllowActivities: &config.AllowActivities{
+			TransmitUserFPD: config.Activity{Rules: []config.ActivityRule{{Condition: config.ActivityCondition{GPC: ptrutil.ToPtr(true)}}}},
+		}}},
+	}
+	bidder := privacy.Component{Type: privacy.ComponentTypeBidder, Name: "appnexus"}
+
+	testCases := []struct {
+		name    string
+		handle  func(deps *endpointDeps, w http.ResponseWriter, r *http.Request)
+		request func() *http.Request
+	}{
+		{
+			name: "auction",
+			handle: func(deps *endpointDeps, w http.ResponseWriter, r *http.Request) {
+				deps.Auction(w, r, nil)
+			},
+			request: func() *http.Request {
+				return httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
+			},
+		},
+		{
+			name: "amp",
+			handle: func(deps *endpointDeps, w http.ResponseWriter, r *http.Request) {
+				deps.storedReqFetcher = &mockAmpStoredReqFetcher{map[string]json.RawMessage{"1": json.RawMessage(validRequest(t, "site.json"))}}
+				deps.AmpAuction(w, r, nil)
+			},
+			request: func() *http.Request {
+				return httptest.NewRequest("GET", "/openrtb2/auction/amp?tag_id=1", nil)
+			},
+		},
+		{
+			name: "video",
+			handle: func(deps *endpointDeps, w http.ResponseWriter, r *http.Request) {
+				deps.storedReqFetcher = &mockVideoStoredReqFetcher{}
+				deps.videoFetcher = &mockVideoStoredReqFetcher{}
+				deps.VideoAuctionEndpoint(w, r, nil)
+			},
+			request: func() *http.Request {
+				return httptest.NewRequest("POST", "/openrtb2/video", strings.NewReader(readVideoTestFile(t, "sample-requests/video/video_valid_sample.json")))
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		for _, secGPC := range []string{"", "1"} {
+			t.Run(test.name+"-gpc-"+secGPC, func(t *testing.T) {
+				ex := &activitiesCheckExchange{}
+				deps := &endpointDeps{
+					fakeUUIDGenerator{},
+					ex,
+					ortb.NewRequestValidator(openrtb_ext.BuildBidderMap(), map[string]string{}, mockBidderParamValidator{}),
+					&mockStoredReqFetcher{},
+					empty_fetcher.EmptyFetcher{},
+					empty_fetcher.EmptyFetcher{},
+					cfg,
+					&metricsConfig.NilMetricsEngine{},
+					analyticsBuild.New(&config.Analytics{}),
+					map[string]string{},
+					false,
+					[]byte{},
+					openrtb_ext.BuildBidderMap(),
+					nil,
+					nil,
+					hardcodedResponseIPValidator{response: true},
+					empty_fetcher.EmptyFetcher{},
+					hooks.EmptyPlanBuilder{},
+					nil,
+					openrtb_ext.NormalizeBidderName,
+					nil,
+					nil,
+					nil,
+				}
+				request := test.request()
+				if secGPC != "" {
+					request.Header.Set("Sec-GPC", secGPC)
+				}
+
+				test.handle(deps, httptest.NewRecorder(), request)
+
+				require.True(t, ex.called, "The auction should be held")
+				// the request carries no GPC signal of its own, so only the header can deny the activity
+				allowed := ex.activities.Allow(privacy.ActivityTransmitUserFPD, bidder, privacy.NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}))
+				assert.Equal(t, secGPC != "1", allowed)
+			})
+		}
+	}
+}
+
+// activitiesCheckExchange stores the activity control of the auction
+type activitiesCheckExchange struct {
+	called     bool
+	activities privacy.ActivityControl
+}
+
+func (e *activitiesCheckExchange) HoldAuction(ctx context.Context, r *exchange.AuctionRequest, debugLog *exchange.DebugLog) (*exchange.AuctionResponse, error) {
+	e.called = true
+	e.activities = r.Activities
+	return &exchange.AuctionResponse{BidResponse: &openrtb2.BidResponse{}}, nil
+}
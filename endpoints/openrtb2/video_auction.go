@@ -21,8 +21,11 @@ import (
 	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/privacy/optout"
+	"github.com/prebid/prebid-server/v3/privacy/pseudonym"
 	jsonpatch "gopkg.in/evanphx/json-patch.v5"
 
+	"github.com/prebid/prebid-server/v3/accesslog"
 	accountService "github.com/prebid/prebid-server/v3/account"
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
@@ -33,6 +36,7 @@ import (
 	"github.com/prebid/prebid-server/v3/prebid_cache_client"
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/iputil"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
@@ -58,6 +62,9 @@ func NewVideoEndpoint(
 	bidderMap map[string]openrtb_ext.BidderName,
 	cache prebid_cache_client.Client,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	pseudonymKeys *pseudonym.Keyring,
+	optOut *optout.Registry,
+	cookieStore *usersync.CookieStore,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || met == nil {
@@ -93,7 +100,9 @@ func NewVideoEndpoint(
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).VideoAuctionEndpoint), nil
+		openrtb_ext.NormalizeBidderName,
+		pseudonymKeys,
+		optOut, cookieStore}).VideoAuctionEndpoint), nil
 }
 
 /*
@@ -160,7 +169,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 			}
 		}
 		deps.metricsEngine.RecordRequest(labels)
-		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
+		metrics.RecordRequestTime(r.Context(), deps.metricsEngine, labels, time.Since(start))
 		deps.analytics.LogVideoObject(&vo, activityControl)
 	}()
 
@@ -197,7 +206,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 			return
 		}
 	} else {
-		storedRequest, errs := deps.loadStoredVideoRequest(context.Background(), storedRequestId)
+		storedRequest, errs := deps.loadStoredVideoRequest(tracing.ContextWithSpanFrom(context.Background(), r.Context()), storedRequestId)
 		if len(errs) > 0 {
 			handleError(&labels, w, errs, &vo, &debugLog)
 			return
@@ -271,7 +280,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	ctx := context.Background()
+	ctx := auctionContext(r)
 	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(bidReqWrapper.TMax) * time.Millisecond)
 	if timeout > 0 {
 		var cancel context.CancelFunc
@@ -279,33 +288,42 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		defer cancel()
 	}
 
-	// Read Usersyncs/Cookie
-	decoder := usersync.Base64Decoder{}
-	usersyncs := usersync.ReadCookie(r, decoder, &deps.cfg.HostCookie)
-	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
-
 	if bidReqWrapper.App != nil {
 		labels.Source = metrics.DemandApp
 		labels.PubID = getAccountID(bidReqWrapper.App.Publisher)
 	} else { // both bidReqWrapper.App == nil and bidReqWrapper.Site != nil are true
 		labels.Source = metrics.DemandWeb
-		if usersyncs.HasAnyLiveSyncs() {
-			labels.CookieFlag = metrics.CookieFlagYes
-		} else {
-			labels.CookieFlag = metrics.CookieFlagNo
-		}
 		labels.PubID = getAccountID(bidReqWrapper.Site.Publisher)
 	}
 
+	// Read Usersyncs/Cookie before the account lookup, so that the cookie metric is recorded even if it fails
+	usersyncs := deps.readCookie(r, nil)
+	if bidReqWrapper.App == nil {
+		labels.CookieFlag = cookieFlag(usersyncs)
+	}
+
 	// Look up account now that we have resolved the pubID value
 	account, acctIDErrs := accountService.GetAccount(ctx, deps.cfg, deps.accounts, labels.PubID, deps.metricsEngine)
 	if len(acctIDErrs) > 0 {
 		handleError(&labels, w, acctIDErrs, &vo, &debugLog)
 		return
 	}
+	vo.Account = account
+
+	// Read Usersyncs/Cookie again if the account overrides the cookie mode of the host
+	if account.CookieSync.Cookie.Mode != "" {
+		usersyncs = deps.readCookie(r, account)
+		if bidReqWrapper.App == nil {
+			labels.CookieFlag = cookieFlag(usersyncs)
+		}
+	}
+	ctx = logger.WithFields(ctx, logger.FieldAccountID, account.ID)
+	accessLogEntry := accesslog.FromContext(r.Context())
+	accessLogEntry.SetAccount(account)
 
 	tcf2Config, gdprSignal, gdprEnforced, gdprErrs := deps.processGDPR(bidReqWrapper, account.GDPR, labels.RType)
 	errL = append(errL, gdprErrs...)
+	accessLogEntry.SetGDPR(gdprEnforced)
 
 	// Populate any "missing" OpenRTB fields with info from other sources, (e.g. HTTP request headers).
 	if errs := deps.setFieldsImplicitly(r, bidReqWrapper, account); len(errs) > 0 {
@@ -320,6 +338,11 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 
 	activityControl = privacy.NewActivityControl(&account.Privacy)
+	activityControl.SetPseudonymKeys(deps.pseudonymKeys, account.ID)
+	deps.applyOptOut(&activityControl, bidReqWrapper)
+	activityControl.SetGPCHeader(r.Header.Get(secGPCKey))
+	activityControl.SetEndpointChannel(config.ChannelVideo)
+	accessLogEntry.SetRequest(bidReqWrapper)
 
 	warnings := errortypes.WarningOnly(errL)
 
@@ -352,6 +375,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	var response *openrtb2.BidResponse
 	if auctionResponse != nil {
 		response = auctionResponse.BidResponse
+		accessLogEntry.SetBidResponse(response, auctionResponse.ExtBidResponse)
 	}
 	vo.Response = response
 	vo.SeatNonBid = auctionResponse.GetSeatNonBid()
@@ -371,7 +395,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	if bidReq.Test == 1 {
 		err = setSeatNonBidRaw(bidReqWrapper, auctionResponse)
 		if err != nil {
-			logger.Errorf("Error setting seat non-bid: %v", err)
+			logger.WithContext(ctx).Errorf("Error setting seat non-bid: %v", err)
 		}
 		bidResp.Ext = response.Ext
 	}
@@ -635,7 +659,9 @@ func findAdPod(podInd int64, pods []*openrtb_ext.AdPod) *openrtb_ext.AdPod {
 }
 
 func (deps *endpointDeps) loadStoredVideoRequest(ctx context.Context, storedRequestId string) ([]byte, []error) {
-	storedRequests, _, errs := deps.videoFetcher.FetchRequests(ctx, []string{storedRequestId}, []string{})
+	fetchCtx, fetchSpan := tracing.Start(ctx, tracing.SpanFetchStoredRequests)
+	storedRequests, _, errs := deps.videoFetcher.FetchRequests(fetchCtx, []string{storedRequestId}, []string{})
+	tracing.EndWithErrors(fetchSpan, errs)
 	jsonString := storedRequests[storedRequestId]
 	return jsonString, errs
 }
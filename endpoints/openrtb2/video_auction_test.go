@@ -1287,6 +1287,9 @@ func mockDepsWithMetrics(t *testing.T, ex *mockExchangeVideo) (*endpointDeps, *m
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 	return deps, metrics, mockModule
 }
@@ -1338,6 +1341,9 @@ func mockDeps(t *testing.T, ex *mockExchangeVideo) *endpointDeps {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 }
 
@@ -1363,6 +1369,9 @@ func mockDepsAppendBidderNames(t *testing.T, ex *mockExchangeAppendBidderNames)
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	return deps
@@ -1390,6 +1399,9 @@ func mockDepsNoBids(t *testing.T, ex *mockExchangeVideoNoBids) *endpointDeps {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
 	}
 
 	return edep
@@ -0,0 +1,70 @@
+package endpoints
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/prebid/prebid-server/v3/privacy/optout"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+type optOutRequest struct {
+	Entries []string `json:"entries"`
+}
+
+type optOutResponse struct {
+	Counts   map[optout.EntryType]int `json:"counts"`
+	Contains map[string]bool          `json:"contains,omitempty"`
+}
+
+// NewOptOutEndpoint returns a handler which manages the entries of the opt-out registry. The posted entries are
+// added and the deleted ones are removed, like {"entries": ["ifa:<IFA>", "hem:<hashed email>", "eid:<source>:<ID>"]}.
+// They're written to the file of the registry, if any, and only kept in memory otherwise.
+// It writes the number of entries of each type, and whether the registry contains the comma separated entries of the
+// "entries" query parameter.
+func NewOptOutEndpoint(registry *optout.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := optOutResponse{}
+
+		switch r.Method {
+		case http.MethodGet:
+			if entries := splitIDs(r.URL.Query().Get("entries")); len(entries) > 0 {
+				response.Contains = make(map[string]bool, len(entries))
+				for _, entry := range entries {
+					response.Contains[entry] = registry.Contains(entry)
+				}
+			}
+		case http.MethodPost, http.MethodDelete:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Failed to read the request body: %v", err), http.StatusBadRequest)
+				return
+			}
+			var request optOutRequest
+			if err := jsonutil.UnmarshalValid(body, &request); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+				return
+			}
+			update := registry.Add
+			if r.Method == http.MethodDelete {
+				update = registry.Remove
+			}
+			if err := update(request.Entries); err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, optout.ErrPersist) {
+					status = http.StatusInternalServerError
+				}
+				http.Error(w, err.Error(), status)
+				return
+			}
+		default:
+			http.Error(w, "The opt-out endpoint only accepts GET, POST and DELETE requests", http.StatusMethodNotAllowed)
+			return
+		}
+
+		response.Counts = registry.Counts()
+		writeAdminResponse(w, "/privacy/optout", response)
+	}
+}
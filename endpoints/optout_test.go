@@ -0,0 +1,103 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/privacy/optout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptOutEndpoint(t *testing.T) {
+	registry, err := optout.NewRegistry(config.OptOut{Enabled: true}, nil)
+	require.NoError(t, err)
+	defer registry.Shutdown()
+	endpoint := NewOptOutEndpoint(registry)
+
+	testCases := []struct {
+		description  string
+		method       string
+		target       string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			description:  "add",
+			method:       http.MethodPost,
+			target:       "/privacy/optout",
+			body:         `{"entries":["ifa:ab-cd","eid:liveramp.com:xyz"]}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"counts":{"ifa":1,"hem":0,"eid":1}}`,
+		},
+		{
+			description:  "add-invalid",
+			method:       http.MethodPost,
+			target:       "/privacy/optout",
+			body:         `{"entries":["ifa:ef-gh","email:someone@example.com"]}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "malformed",
+			method:       http.MethodPost,
+			target:       "/privacy/optout",
+			body:         `{`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "remove",
+			method:       http.MethodDelete,
+			target:       "/privacy/optout",
+			body:         `{"entries":["eid:liveramp.com:xyz"]}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"counts":{"ifa":1,"hem":0,"eid":0}}`,
+		},
+		{
+			description:  "contains",
+			method:       http.MethodGet,
+			target:       "/privacy/optout?entries=ifa:AB-CD,ifa:ef-gh",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"counts":{"ifa":1,"hem":0,"eid":0},"contains":{"ifa:AB-CD":true,"ifa:ef-gh":false}}`,
+		},
+		{
+			description:  "unsupported-method",
+			method:       http.MethodPut,
+			target:       "/privacy/optout",
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			endpoint(recorder, httptest.NewRequest(test.method, test.target, strings.NewReader(test.body)))
+
+			require.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestOptOutEndpointPersistError(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "optout.txt")
+	require.NoError(t, os.WriteFile(file, []byte("ifa:ab-cd\n"), 0644))
+	registry, err := optout.NewRegistry(config.OptOut{Enabled: true, File: file, RefreshIntervalSeconds: 3600}, nil)
+	require.NoError(t, err)
+	defer registry.Shutdown()
+	require.NoError(t, os.Remove(file))
+
+	request := httptest.NewRequest(http.MethodPost, "/privacy/optout", strings.NewReader(`{"entries":["ifa:ef-gh"]}`))
+	recorder := httptest.NewRecorder()
+	NewOptOutEndpoint(registry)(recorder, request)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.False(t, registry.Contains("ifa:ef-gh"), "the entries which aren't persisted aren't added")
+}
@@ -0,0 +1,646 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/go-gdpr/vendorconsent"
+	tcf2 "github.com/prebid/go-gdpr/vendorconsent/tcf2"
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	accountService "github.com/prebid/prebid-server/v3/account"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange"
+	"github.com/prebid/prebid-server/v3/firstpartydata"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/metrics"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/privacy/optout"
+	"github.com/prebid/prebid-server/v3/privacy/pseudonym"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	tcfPurposes        = 10
+	tcfSpecialFeatures = 2
+	// tcfRestrictionTypes are the publisher restriction types: purpose flatly not allowed, require consent and
+	// require legitimate interest
+	tcfRestrictionTypes = 3
+)
+
+// privacyDebugActivities are the activities evaluated for the bidders
+var privacyDebugActivities = []privacy.Activity{
+	privacy.ActivitySyncUser,
+	privacy.ActivityFetchBids,
+	privacy.ActivityTransmitUserFPD,
+	privacy.ActivityTransmitPreciseGeo,
+	privacy.ActivityTransmitUniqueRequestIDs,
+	privacy.ActivityTransmitTIDs,
+}
+
+// privacyDebugRequest is either a bid request, or the privacy fields of one
+type privacyDebugRequest struct {
+	Account   string               `json:"account"`
+	Bidders   []string             `json:"bidders"`
+	Request   *openrtb2.BidRequest `json:"request"`
+	GDPR      *int8                `json:"gdpr"`
+	Consent   string               `json:"gdpr_consent"`
+	GPP       string               `json:"gpp"`
+	GPPSID    []int8               `json:"gpp_sid"`
+	USPrivacy string               `json:"us_privacy"`
+	COPPA     int8                 `json:"coppa"`
+}
+
+type privacyDebugResponse struct {
+	Account string               `json:"account"`
+	TCF     *tcfDebug            `json:"tcf,omitempty"`
+	GPP     *gppDebug            `json:"gpp,omitempty"`
+	GDPR    gdprDebug            `json:"gdpr"`
+	OptOut  string               `json:"opt_out,omitempty"` // the type of the opt-out registry entry the user matched
+	Bidders []bidderPrivacyDebug `json:"bidders"`
+	Errors  []string             `json:"errors,omitempty"`
+}
+
+type tcfDebug struct {
+	Consent               string                      `json:"consent"`
+	Version               uint8                       `json:"version"`
+	CmpID                 uint16                      `json:"cmp_id"`
+	CmpVersion            uint16                      `json:"cmp_version"`
+	ConsentLanguage       string                      `json:"consent_language"`
+	VendorListVersion     uint16                      `json:"vendor_list_version"`
+	PolicyVersion         uint8                       `json:"policy_version"`
+	PurposeOneTreatment   bool                        `json:"purpose_one_treatment"`
+	PurposeConsents       []int                       `json:"purpose_consents"`
+	PurposeLITransparency []int                       `json:"purpose_li_transparency"`
+	SpecialFeatureOptIns  []int                       `json:"special_feature_opt_ins"`
+	VendorConsents        []uint16                    `json:"vendor_consents"`
+	VendorLegitInterests  []uint16                    `json:"vendor_legit_interests"`
+	PublisherRestrictions []publisherRestrictionDebug `json:"publisher_restrictions"`
+}
+
+type publisherRestrictionDebug struct {
+	Purpose         int      `json:"purpose"`
+	RestrictionType int      `json:"restriction_type"`
+	Vendors         []uint16 `json:"vendors"`
+}
+
+type gppDebug struct {
+	Version  int               `json:"version"`
+	SIDs     []int             `json:"sids"`
+	Sections []gppSectionDebug `json:"sections"`
+}
+
+type gppSectionDebug struct {
+	SID     int         `json:"sid"`
+	Value   string      `json:"value"`
+	Decoded interface{} `json:"decoded,omitempty"`
+}
+
+type gdprDebug struct {
+	Signal                  string         `json:"signal"`
+	DefaultValue            string         `json:"default_value"`
+	ChannelEnabled          bool           `json:"channel_enabled"`
+	Enforced                bool           `json:"enforced"`
+	Purposes                []purposeDebug `json:"purposes"`
+	BasicEnforcementVendors []string       `json:"basic_enforcement_vendors,omitempty"`
+}
+
+type purposeDebug struct {
+	Purpose          int      `json:"purpose"`
+	EnforcePurpose   bool     `json:"enforce_purpose"`
+	EnforceVendors   bool     `json:"enforce_vendors"`
+	EnforceAlgo      string   `json:"enforce_algo"`
+	VendorExceptions []string `json:"vendor_exceptions,omitempty"`
+}
+
+type bidderPrivacyDebug struct {
+	Bidder     string              `json:"bidder"`
+	CoreBidder string              `json:"core_bidder"`
+	GDPR       *bidderGDPRDebug    `json:"gdpr,omitempty"`
+	Activities map[string]bool     `json:"activities"`
+	Blocked    bool                `json:"blocked"`
+	Scrubbed   []privacyScrubDebug `json:"scrubbed,omitempty"`
+}
+
+type bidderGDPRDebug struct {
+	AllowSync       bool `json:"allow_sync"`
+	AllowBidRequest bool `json:"allow_bid_request"`
+	PassGeo         bool `json:"pass_geo"`
+	PassID          bool `json:"pass_id"`
+	// EnforceAlgos are the enforcement algorithms applied to the bidder by purpose, which differ from the purpose
+	// config when the bidder is a basic enforcement vendor
+	EnforceAlgos map[int]string `json:"enforce_algos"`
+}
+
+type privacyScrubDebug struct {
+	Reason string   `json:"reason"`
+	Fields []string `json:"fields"`
+}
+
+// NewPrivacyDebugEndpoint returns a handler which explains how the privacy signals of the posted bid request, or of
+// its posted privacy fields, are enforced for each bidder: the decoded TCF and GPP strings, the GDPR enforcement, the
+// activity controls and the fields which would be scrubbed, as the exchange scrubs them. No auction is run, and no
+// metrics are recorded.
+func NewPrivacyDebugEndpoint(cfg *config.Configuration, accountsFetcher stored_requests.AccountFetcher, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, pseudonymKeys *pseudonym.Keyring, optOut *optout.Registry) http.HandlerFunc {
+	me := &metricsConf.NilMetricsEngine{}
+	privacyConfig := config.Privacy{
+		CCPA: cfg.CCPA,
+		GDPR: cfg.GDPR,
+		LMT:  cfg.LMT,
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "The privacy debug endpoint only accepts POST requests", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to read the request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		var debugRequest privacyDebugRequest
+		if err := jsonutil.UnmarshalValid(body, &debugRequest); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		bidRequest := debugRequest.bidRequest()
+		req := &openrtb_ext.RequestWrapper{BidRequest: bidRequest}
+		requestPrebid, err := getRequestPrebid(req)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request.ext: %v", err), http.StatusBadRequest)
+			return
+		}
+		fpd, fpdErrs := firstpartydata.ExtractFPDForBidders(req)
+		if len(fpdErrs) > 0 {
+			http.Error(w, fmt.Sprintf("Invalid first party data: %v", errors.Join(fpdErrs...)), http.StatusBadRequest)
+			return
+		}
+		if err := req.RebuildRequest(); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		requestJSON, err := jsonutil.Marshal(req.BidRequest)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		bidders := debugRequest.Bidders
+		if len(bidders) == 0 {
+			bidders = requestBidders(bidRequest)
+		}
+		if len(bidders) == 0 {
+			http.Error(w, `No bidders to debug, either "bidders" or the request imps must list some`, http.StatusBadRequest)
+			return
+		}
+
+		accountID := debugRequest.Account
+		if accountID == "" {
+			accountID = requestPublisherID(bidRequest)
+		}
+		response := privacyDebugResponse{Account: accountID}
+		account, errs := accountService.GetAccount(r.Context(), cfg, accountsFetcher, accountID, me)
+		response.Errors = appendErrorStrings(response.Errors, errs)
+		if account == nil {
+			writeAdminResponse(w, "/privacy/debug", response)
+			return
+		}
+
+		var gpp gpplib.GppContainer
+		if req.Regs != nil && len(req.Regs.GPP) > 0 {
+			gpp, errs = gpplib.Parse(req.Regs.GPP)
+			response.Errors = appendErrorStrings(response.Errors, errs)
+			response.GPP = describeGPP(gpp)
+		}
+
+		consent := gdpr.GetConsent(req, gpp)
+		if consent != "" {
+			tcf, err := describeTCF(consent)
+			if err != nil {
+				response.Errors = append(response.Errors, err.Error())
+			}
+			response.TCF = tcf
+		}
+
+		channel := config.ChannelWeb
+		if bidRequest.App != nil {
+			channel = config.ChannelApp
+		}
+		tcf2Cfg := tcf2CfgBuilder(cfg.GDPR.TCF2, account.GDPR)
+		gdprDefaultValue := gdpr.ParseGDPRDefaultValue(req, cfg.GDPR.DefaultValue, gdpr.SelectEEACountries(cfg.GDPR.EEACountries, account.GDPR.EEACountries))
+		gdprSignal, err := gdpr.GetGDPR(req)
+		if err != nil {
+			response.Errors = append(response.Errors, err.Error())
+		}
+		response.GDPR = describeGDPR(tcf2Cfg, gdprSignal, gdprDefaultValue, channel)
+
+		var gdprPerms gdpr.Permissions = &gdpr.AlwaysAllow{}
+		if response.GDPR.Enforced {
+			gdprPerms = gdprPermsBuilder(tcf2Cfg, gdpr.RequestInfo{
+				AliasGVLIDs: requestPrebid.AliasGVLIDs,
+				Consent:     consent,
+				GDPRSignal:  gdprSignal,
+				PublisherID: accountID,
+			})
+		}
+
+		activities := privacy.NewActivityControl(&account.Privacy)
+		activities.SetPseudonymKeys(pseudonymKeys, account.ID)
+		if entryType, ok := optOut.Match(bidRequest.User, bidRequest.Device); ok {
+			activities.SetOptedOut()
+			response.OptOut = string(entryType)
+		}
+		bidderPrivacy, err := exchange.NewBidderPrivacy(req, gpp, consent, response.GDPR.Enforced, privacyConfig, account, activities, requestPrebid.Aliases, channel, fpd)
+		if err != nil {
+			response.Errors = append(response.Errors, err.Error())
+		}
+
+		for _, bidder := range bidders {
+			coreBidder := resolveCoreBidder(bidder, requestPrebid.Aliases)
+			bidderDebug := bidderPrivacyDebug{
+				Bidder:     bidder,
+				CoreBidder: coreBidder.String(),
+				Activities: make(map[string]bool, len(privacyDebugActivities)),
+			}
+
+			bidderReq, err := unmarshalBidRequest(requestJSON)
+			if err != nil {
+				response.Errors = append(response.Errors, err.Error())
+				continue
+			}
+			component := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidder}
+			for _, activity := range privacyDebugActivities {
+				bidderDebug.Activities[activity.String()] = activities.Allow(activity, component, privacy.NewRequestFromBidRequest(*bidderReq))
+			}
+
+			auctionPermissions := gdprPerms.AuctionActivitiesAllowed(r.Context(), coreBidder, openrtb_ext.BidderName(bidder))
+			if response.GDPR.Enforced {
+				allowSync, _ := gdprPerms.BidderSyncAllowed(r.Context(), openrtb_ext.BidderName(bidder))
+				bidderDebug.GDPR = &bidderGDPRDebug{
+					AllowSync:       allowSync,
+					AllowBidRequest: auctionPermissions.AllowBidRequest,
+					PassGeo:         auctionPermissions.PassGeo,
+					PassID:          auctionPermissions.PassID,
+					EnforceAlgos:    describeBidderEnforcement(tcf2Cfg, coreBidder),
+				}
+			}
+
+			bidderDebug.Blocked = !bidderDebug.Activities[privacy.ActivityFetchBids.String()] || !auctionPermissions.AllowBidRequest
+			if !bidderDebug.Blocked {
+				_, isRequestAlias := requestPrebid.Aliases[bidder]
+				fpdUserEIDsPresent := bidderPrivacy.FPDUserEIDsPresent(req, bidder)
+				recorder := scrubRecorder{req: bidderReq}
+				if err := bidderPrivacy.Apply(bidderReq, coreBidder, bidder, isRequestAlias, fpdUserEIDsPresent, auctionPermissions, me, recorder.step); err != nil {
+					response.Errors = append(response.Errors, err.Error())
+				}
+				if recorder.err != nil {
+					response.Errors = append(response.Errors, recorder.err.Error())
+				}
+				bidderDebug.Scrubbed = recorder.scrubbed
+			}
+			response.Bidders = append(response.Bidders, bidderDebug)
+		}
+
+		writeAdminResponse(w, "/privacy/debug", response)
+	}
+}
+
+// bidRequest returns the posted bid request, or builds one from the posted privacy fields
+func (r privacyDebugRequest) bidRequest() *openrtb2.BidRequest {
+	if r.Request != nil {
+		return r.Request
+	}
+
+	bidRequest := &openrtb2.BidRequest{
+		Regs: &openrtb2.Regs{
+			GDPR:      r.GDPR,
+			GPP:       r.GPP,
+			GPPSID:    r.GPPSID,
+			USPrivacy: r.USPrivacy,
+			COPPA:     r.COPPA,
+		},
+	}
+	if r.Consent != "" {
+		bidRequest.User = &openrtb2.User{Consent: r.Consent}
+	}
+	return bidRequest
+}
+
+func getRequestPrebid(req *openrtb_ext.RequestWrapper) (*openrtb_ext.ExtRequestPrebid, error) {
+	requestExt, err := req.GetRequestExt()
+	if err != nil {
+		return nil, err
+	}
+	if prebid := requestExt.GetPrebid(); prebid != nil {
+		return prebid, nil
+	}
+	return &openrtb_ext.ExtRequestPrebid{}, nil
+}
+
+// requestBidders returns the bidders of the imps of the request, in order of appearance
+func requestBidders(bidRequest *openrtb2.BidRequest) []string {
+	var bidders []string
+	seen := make(map[string]struct{})
+	for _, imp := range bidRequest.Imp {
+		var impExt struct {
+			Prebid struct {
+				Bidder map[string]json.RawMessage `json:"bidder"`
+			} `json:"prebid"`
+		}
+		if len(imp.Ext) == 0 || jsonutil.Unmarshal(imp.Ext, &impExt) != nil {
+			continue
+		}
+		impBidders := make([]string, 0, len(impExt.Prebid.Bidder))
+		for bidder := range impExt.Prebid.Bidder {
+			if _, ok := seen[bidder]; !ok {
+				seen[bidder] = struct{}{}
+				impBidders = append(impBidders, bidder)
+			}
+		}
+		sort.Strings(impBidders)
+		bidders = append(bidders, impBidders...)
+	}
+	return bidders
+}
+
+func requestPublisherID(bidRequest *openrtb2.BidRequest) string {
+	if bidRequest.Site != nil && bidRequest.Site.Publisher != nil && bidRequest.Site.Publisher.ID != "" {
+		return bidRequest.Site.Publisher.ID
+	}
+	if bidRequest.App != nil && bidRequest.App.Publisher != nil && bidRequest.App.Publisher.ID != "" {
+		return bidRequest.App.Publisher.ID
+	}
+	return metrics.PublisherUnknown
+}
+
+func resolveCoreBidder(bidder string, aliases map[string]string) openrtb_ext.BidderName {
+	if coreBidder, ok := aliases[bidder]; ok {
+		return openrtb_ext.NormalizeBidderNameOrUnchanged(coreBidder)
+	}
+	return openrtb_ext.NormalizeBidderNameOrUnchanged(bidder)
+}
+
+func unmarshalBidRequest(requestJSON []byte) (*openrtb_ext.RequestWrapper, error) {
+	var bidRequest openrtb2.BidRequest
+	if err := jsonutil.Unmarshal(requestJSON, &bidRequest); err != nil {
+		return nil, err
+	}
+	return &openrtb_ext.RequestWrapper{BidRequest: &bidRequest}, nil
+}
+
+func describeTCF(consent string) (*tcfDebug, error) {
+	parsed, err := vendorconsent.ParseString(consent)
+	if err != nil {
+		return nil, fmt.Errorf("malformed TCF consent string %s: %v", consent, err)
+	}
+	metadata, ok := parsed.(tcf2.ConsentMetadata)
+	if !ok {
+		return nil, fmt.Errorf("the consent string %s is not a TCF 2 consent string", consent)
+	}
+
+	tcf := &tcfDebug{
+		Consent:               consent,
+		Version:               metadata.Version(),
+		CmpID:                 metadata.CmpID(),
+		CmpVersion:            metadata.CmpVersion(),
+		ConsentLanguage:       metadata.ConsentLanguage(),
+		VendorListVersion:     metadata.VendorListVersion(),
+		PolicyVersion:         metadata.TCFPolicyVersion(),
+		PurposeOneTreatment:   metadata.PurposeOneTreatment(),
+		PurposeConsents:       []int{},
+		PurposeLITransparency: []int{},
+		SpecialFeatureOptIns:  []int{},
+		VendorConsents:        []uint16{},
+		VendorLegitInterests:  []uint16{},
+		PublisherRestrictions: []publisherRestrictionDebug{},
+	}
+	for purpose := 1; purpose <= tcfPurposes; purpose++ {
+		if metadata.PurposeAllowed(consentconstants.Purpose(purpose)) {
+			tcf.PurposeConsents = append(tcf.PurposeConsents, purpose)
+		}
+		if metadata.PurposeLITransparency(consentconstants.Purpose(purpose)) {
+			tcf.PurposeLITransparency = append(tcf.PurposeLITransparency, purpose)
+		}
+	}
+	for feature := 1; feature <= tcfSpecialFeatures; feature++ {
+		if metadata.SpecialFeatureOptIn(uint16(feature)) {
+			tcf.SpecialFeatureOptIns = append(tcf.SpecialFeatureOptIns, feature)
+		}
+	}
+
+	maxVendorID := max(metadata.MaxVendorID(), metadata.VendorLegitInterestMaxID())
+	for vendor := uint16(1); vendor <= maxVendorID && vendor != 0; vendor++ {
+		if metadata.VendorConsent(vendor) {
+			tcf.VendorConsents = append(tcf.VendorConsents, vendor)
+		}
+		if metadata.VendorLegitInterest(vendor) {
+			tcf.VendorLegitInterests = append(tcf.VendorLegitInterests, vendor)
+		}
+	}
+	for purpose := 1; purpose <= tcfPurposes; purpose++ {
+		for restrictionType := 0; restrictionType < tcfRestrictionTypes; restrictionType++ {
+			var vendors []uint16
+			for vendor := uint16(1); vendor <= maxVendorID && vendor != 0; vendor++ {
+				if metadata.CheckPubRestriction(uint8(purpose), uint8(restrictionType), vendor) {
+					vendors = append(vendors, vendor)
+				}
+			}
+			if len(vendors) > 0 {
+				tcf.PublisherRestrictions = append(tcf.PublisherRestrictions, publisherRestrictionDebug{
+					Purpose:         purpose,
+					RestrictionType: restrictionType,
+					Vendors:         vendors,
+				})
+			}
+		}
+	}
+	return tcf, nil
+}
+
+func describeGPP(gpp gpplib.GppContainer) *gppDebug {
+	debug := &gppDebug{
+		Version:  gpp.Version,
+		SIDs:     make([]int, 0, len(gpp.SectionTypes)),
+		Sections: make([]gppSectionDebug, 0, len(gpp.Sections)),
+	}
+	for _, sid := range gpp.SectionTypes {
+		debug.SIDs = append(debug.SIDs, int(sid))
+	}
+	for _, section := range gpp.Sections {
+		debug.Sections = append(debug.Sections, gppSectionDebug{
+			SID:     int(section.GetID()),
+			Value:   section.GetValue(),
+			Decoded: decodedFields(reflect.ValueOf(section)),
+		})
+	}
+	return debug
+}
+
+// decodedFields returns the exported fields of a decoded GPP section, with the byte slices holding the values of
+// a field per category as lists of numbers rather than base64
+func decodedFields(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return decodedFields(v.Elem())
+	case reflect.Struct:
+		fields := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() {
+				if value := decodedFields(v.Field(i)); value != nil {
+					fields[field.Name] = value
+				}
+			}
+		}
+		if len(fields) == 0 {
+			return nil
+		}
+		return fields
+	case reflect.Slice, reflect.Array:
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			values[i] = decodedFields(v.Index(i))
+		}
+		return values
+	case reflect.Map, reflect.Func, reflect.Chan:
+		return nil
+	}
+	return v.Interface()
+}
+
+func describeGDPR(tcf2Cfg gdpr.TCF2ConfigReader, signal gdpr.Signal, defaultValue gdpr.Signal, channel config.ChannelType) gdprDebug {
+	channelEnabled := tcf2Cfg.ChannelEnabled(channel)
+	debug := gdprDebug{
+		Signal:                  signalName(signal),
+		DefaultValue:            signalName(defaultValue),
+		ChannelEnabled:          channelEnabled,
+		Enforced:                gdpr.EnforceGDPR(signal, defaultValue, channelEnabled),
+		Purposes:                make([]purposeDebug, 0, tcfPurposes),
+		BasicEnforcementVendors: sortedKeys(tcf2Cfg.BasicEnforcementVendors()),
+	}
+	for purpose := 1; purpose <= tcfPurposes; purpose++ {
+		p := consentconstants.Purpose(purpose)
+		debug.Purposes = append(debug.Purposes, purposeDebug{
+			Purpose:          purpose,
+			EnforcePurpose:   tcf2Cfg.PurposeEnforced(p),
+			EnforceVendors:   tcf2Cfg.PurposeEnforcingVendors(p),
+			EnforceAlgo:      enforceAlgoName(tcf2Cfg.PurposeEnforcementAlgo(p)),
+			VendorExceptions: sortedKeys(tcf2Cfg.PurposeVendorExceptions(p)),
+		})
+	}
+	return debug
+}
+
+func describeBidderEnforcement(tcf2Cfg gdpr.TCF2ConfigReader, coreBidder openrtb_ext.BidderName) map[int]string {
+	purposeEnforcerBuilder := gdpr.NewPurposeEnforcerBuilder(tcf2Cfg)
+	algos := make(map[int]string, tcfPurposes)
+	for purpose := 1; purpose <= tcfPurposes; purpose++ {
+		switch purposeEnforcerBuilder(consentconstants.Purpose(purpose), string(coreBidder)).(type) {
+		case *gdpr.BasicEnforcement:
+			algos[purpose] = config.TCF2EnforceAlgoBasic
+		case *gdpr.FullEnforcement:
+			algos[purpose] = config.TCF2EnforceAlgoFull
+		}
+	}
+	return algos
+}
+
+func signalName(signal gdpr.Signal) string {
+	switch signal {
+	case gdpr.SignalYes:
+		return "yes"
+	case gdpr.SignalNo:
+		return "no"
+	}
+	return "ambiguous"
+}
+
+func enforceAlgoName(algo config.TCF2EnforcementAlgo) string {
+	if algo == config.TCF2BasicEnforcement {
+		return config.TCF2EnforceAlgoBasic
+	}
+	return config.TCF2EnforceAlgoFull
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// scrubRecorder records the fields of the request of a bidder which are removed or changed by each step of
+// exchange.BidderPrivacy.Apply
+type scrubRecorder struct {
+	req      *openrtb_ext.RequestWrapper
+	scrubbed []privacyScrubDebug
+	err      error
+}
+
+func (s *scrubRecorder) step(reason string, apply func()) {
+	before, err := requestFields(s.req)
+	if err != nil {
+		s.err = err
+		apply()
+		return
+	}
+	apply()
+	after, err := requestFields(s.req)
+	if err != nil {
+		s.err = err
+		return
+	}
+	if fields := changedFields("", before, after); len(fields) > 0 {
+		s.scrubbed = append(s.scrubbed, privacyScrubDebug{Reason: reason, Fields: fields})
+	}
+}
+
+func requestFields(req *openrtb_ext.RequestWrapper) (map[string]interface{}, error) {
+	if err := req.RebuildRequest(); err != nil {
+		return nil, err
+	}
+	requestJSON, err := jsonutil.Marshal(req.BidRequest)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	err = jsonutil.Unmarshal(requestJSON, &fields)
+	return fields, err
+}
+
+// changedFields returns the sorted paths of the fields of before which were removed or changed in after. Arrays
+// are compared as a whole.
+func changedFields(prefix string, before, after map[string]interface{}) []string {
+	var fields []string
+	for name, beforeValue := range before {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		afterValue, ok := after[name]
+		beforeObject, beforeIsObject := beforeValue.(map[string]interface{})
+		afterObject, afterIsObject := afterValue.(map[string]interface{})
+		if ok && beforeIsObject && afterIsObject {
+			fields = append(fields, changedFields(path, beforeObject, afterObject)...)
+		} else if !ok || !reflect.DeepEqual(beforeValue, afterValue) {
+			fields = append(fields, path)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
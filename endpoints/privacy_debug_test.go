@@ -0,0 +1,162 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivacyDebugEndpoint(t *testing.T) {
+	fetcher := FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+		"valid": json.RawMessage(`{"id":"valid","privacy":{"allowactivities":{"transmitTid":{"rules":[{"condition":{"componentName":["appnexus"]},"allow":false}]}}}}`),
+		"eids":  json.RawMessage(`{"id":"eids","privacy":{"eidpermissions":[{"source":"src","bidders":["rubicon"]}]}}`),
+	}}
+	cfg := &config.Configuration{GDPR: config.GDPR{Enabled: true, DefaultValue: "0", TCF2: config.TCF2{Enabled: true}}}
+	permissionsBuilder := fakePermissionsBuilder{permissions: &fakePermissions{}}.Builder
+	endpoint := NewPrivacyDebugEndpoint(cfg, fetcher, permissionsBuilder, gdpr.NewTCF2Config, nil, nil)
+
+	t.Run("bid-request", func(t *testing.T) {
+		body := `{"request":{
+			"id":"req","source":{"tid":"tid"},
+			"site":{"publisher":{"id":"valid"}},
+			"imp":[{"id":"imp","ext":{"prebid":{"bidder":{"appnexus":{},"alias":{}}}}}],
+			"device":{"ip":"1.2.3.4","ifa":"ifa","geo":{"lat":1.2345,"lon":5.4321}},
+			"user":{"buyeruid":"buyeruid","yob":1980},
+			"regs":{"gdpr":1},
+			"ext":{"prebid":{"aliases":{"alias":"appnexus"}}}
+		}}`
+		recorder := httptest.NewRecorder()
+		endpoint(recorder, httptest.NewRequest(http.MethodPost, "/privacy/debug", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var response privacyDebugResponse
+		require.NoError(t, jsonutil.UnmarshalValid(recorder.Body.Bytes(), &response))
+		assert.Equal(t, "valid", response.Account)
+		assert.Nil(t, response.TCF)
+		assert.Equal(t, "yes", response.GDPR.Signal)
+		assert.True(t, response.GDPR.Enforced)
+		require.Len(t, response.Bidders, 2)
+
+		alias := response.Bidders[0]
+		assert.Equal(t, "alias", alias.Bidder)
+		assert.Equal(t, "appnexus", alias.CoreBidder)
+		assert.False(t, alias.Blocked)
+		assert.Equal(t, &bidderGDPRDebug{
+			AllowSync:       true,
+			AllowBidRequest: true,
+			EnforceAlgos:    map[int]string{1: "full", 2: "full", 3: "full", 4: "full", 5: "full", 6: "full", 7: "full", 8: "full", 9: "full", 10: "full"},
+		}, alias.GDPR)
+		assert.True(t, alias.Activities["transmitTid"])
+		assert.Equal(t, []privacyScrubDebug{
+			{Reason: "gdpr denies passing user ids", Fields: []string{"device.ifa", "user.buyeruid", "user.yob"}},
+			{Reason: "gdpr denies passing precise geo", Fields: []string{"device.geo.lat", "device.geo.lon", "device.ip"}},
+		}, alias.Scrubbed)
+
+		appnexus := response.Bidders[1]
+		assert.Equal(t, "appnexus", appnexus.Bidder)
+		assert.False(t, appnexus.Activities["transmitTid"])
+		assert.True(t, appnexus.Activities["fetchBids"])
+		assert.Equal(t, []privacyScrubDebug{
+			{Reason: "gdpr denies passing user ids", Fields: []string{"device.ifa", "user.buyeruid", "user.yob"}},
+			{Reason: "gdpr denies passing precise geo", Fields: []string{"device.geo.lat", "device.geo.lon", "device.ip"}},
+			{Reason: "activity transmitTid denied", Fields: []string{"source.tid"}},
+		}, appnexus.Scrubbed)
+	})
+
+	t.Run("fpd-and-eid-permissions", func(t *testing.T) {
+		body := `{"request":{
+			"id":"req",
+			"site":{"name":"site","page":"https://page.com","publisher":{"id":"eids"}},
+			"imp":[{"id":"imp","ext":{"prebid":{"bidder":{"appnexus":{},"rubicon":{}}}}}],
+			"user":{"eids":[{"source":"src","uids":[{"id":"id"}]}]},
+			"ext":{"prebid":{"bidderconfig":[{"bidders":["appnexus"],"config":{"ortb2":{"site":{"name":"bidder-site"}}}}]}}
+		}}`
+		recorder := httptest.NewRecorder()
+		endpoint(recorder, httptest.NewRequest(http.MethodPost, "/privacy/debug", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var response privacyDebugResponse
+		require.NoError(t, jsonutil.UnmarshalValid(recorder.Body.Bytes(), &response))
+		assert.Empty(t, response.Errors)
+		require.Len(t, response.Bidders, 2)
+
+		appnexus := response.Bidders[0]
+		assert.Equal(t, "appnexus", appnexus.Bidder)
+		assert.Equal(t, []privacyScrubDebug{
+			{Reason: "bidder first party data", Fields: []string{"site.name"}},
+			{Reason: "account eid permissions", Fields: []string{"user.eids"}},
+		}, appnexus.Scrubbed)
+
+		rubicon := response.Bidders[1]
+		assert.Equal(t, "rubicon", rubicon.Bidder)
+		assert.Empty(t, rubicon.Scrubbed)
+	})
+
+	t.Run("privacy-fields", func(t *testing.T) {
+		body := `{"account":"valid","bidders":["appnexus"],"gdpr":1,"gdpr_consent":"CPerMsAPerMsAAAAAAENCfCAAEAAAAAAAAAAAQAAAAAEAAAAAAAA","gpp":"DBABjw~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN","us_privacy":"1YNN"}`
+		recorder := httptest.NewRecorder()
+		endpoint(recorder, httptest.NewRequest(http.MethodPost, "/privacy/debug", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var response privacyDebugResponse
+		require.NoError(t, jsonutil.UnmarshalValid(recorder.Body.Bytes(), &response))
+		require.NotNil(t, response.TCF)
+		assert.Equal(t, "CPerMsAPerMsAAAAAAENCfCAAEAAAAAAAAAAAQAAAAAEAAAAAAAA", response.TCF.Consent)
+		require.NotNil(t, response.GPP)
+		assert.Equal(t, []int{5, 6}, response.GPP.SIDs)
+		require.Len(t, response.GPP.Sections, 2)
+		assert.Equal(t, "1YNN", response.GPP.Sections[1].Value)
+		require.Len(t, response.Bidders, 1)
+		assert.Empty(t, response.Bidders[0].Scrubbed)
+	})
+
+	t.Run("tcf", func(t *testing.T) {
+		tcf, err := describeTCF("CPerMsAPerMsAAAAAAENCfCAAEAAAAAAAAAAAQAAAAAEAAAAAAAA")
+		require.NoError(t, err)
+		assert.Equal(t, []int{2}, tcf.PurposeConsents)
+		assert.Empty(t, tcf.PurposeLITransparency)
+		assert.Equal(t, []uint16{32}, tcf.VendorConsents)
+		assert.Empty(t, tcf.PublisherRestrictions)
+
+		_, err = describeTCF("malformed")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		testCases := []struct {
+			description  string
+			method       string
+			body         string
+			expectedCode int
+		}{
+			{description: "get", method: http.MethodGet, expectedCode: http.StatusMethodNotAllowed},
+			{description: "malformed", method: http.MethodPost, body: `{`, expectedCode: http.StatusBadRequest},
+			{description: "no-bidders", method: http.MethodPost, body: `{"gdpr":1}`, expectedCode: http.StatusBadRequest},
+		}
+		for _, test := range testCases {
+			recorder := httptest.NewRecorder()
+			endpoint(recorder, httptest.NewRequest(test.method, "/privacy/debug", strings.NewReader(test.body)))
+			assert.Equal(t, test.expectedCode, recorder.Code, test.description)
+		}
+	})
+}
+
+func TestChangedFields(t *testing.T) {
+	before := map[string]interface{}{
+		"device": map[string]interface{}{"ip": "1.2.3.4", "ua": "ua"},
+		"user":   map[string]interface{}{"id": "id", "eids": []interface{}{"eid"}},
+	}
+	after := map[string]interface{}{
+		"device": map[string]interface{}{"ip": "1.2.3.0", "ua": "ua"},
+		"user":   map[string]interface{}{},
+	}
+	assert.Equal(t, []string{"device.ip", "user.eids", "user.id"}, changedFields("", before, after))
+}
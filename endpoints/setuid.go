@@ -12,6 +12,7 @@ import (
 	"github.com/julienschmidt/httprouter"
 	gpplib "github.com/prebid/go-gpp"
 	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/prebid-server/v3/accesslog"
 	accountService "github.com/prebid/prebid-server/v3/account"
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
@@ -37,7 +38,7 @@ const (
 
 const uidCookieName = "uids"
 
-func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, analyticsRunner analytics.Runner, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine) httprouter.Handle {
+func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, analyticsRunner analytics.Runner, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine, cookieStore *usersync.CookieStore) httprouter.Handle {
 	encoder := usersync.Base64Encoder{}
 	decoder := usersync.Base64Decoder{}
 
@@ -49,7 +50,7 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 
 		defer analyticsRunner.LogSetUIDObject(&so)
 
-		cookie := usersync.ReadCookie(r, decoder, &cfg.HostCookie)
+		cookie := cookieStore.ReadCookie(r, decoder, &cfg.HostCookie)
 		if !cookie.AllowSyncs() {
 			handleBadStatus(w, http.StatusUnauthorized, metrics.SetUidOptOut, nil, metricsEngine, &so)
 			return
@@ -93,6 +94,22 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 			return
 		}
 
+		so.Account = account
+
+		// The uids cookie of the accounts in the first_party mode is only known once the account is
+		hostCookie := cfg.HostCookie.ForAccount(account)
+		if hostCookie.UIDsCookieMode() == config.UIDsCookieModeFirstParty {
+			cookie = cookieStore.ReadCookie(r, decoder, hostCookie)
+			if !cookie.AllowSyncs() {
+				handleBadStatus(w, http.StatusUnauthorized, metrics.SetUidOptOut, nil, metricsEngine, &so)
+				return
+			}
+			usersync.SyncHostCookie(r, cookie, hostCookie)
+		}
+		accessLogEntry := accesslog.FromContext(r.Context())
+		accessLogEntry.SetAccount(account)
+		accessLogEntry.SetBidders([]string{so.Bidder})
+
 		activityControl := privacy.NewActivityControl(&account.Privacy)
 
 		gppSID, err := stringutil.StrToInt8Slice(query.Get("gpp_sid"))
@@ -107,7 +124,10 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 		}
 
 		policies := privacy.Policies{
-			GPPSID: gppSID,
+			GPPSID:     gppSID,
+			GPP:        query.Get("gpp"),
+			GPC:        r.Header.Get("Sec-GPC") == "1",
+			TCFInScope: query.Get("gdpr") == "1" || gppPrivacy.IsSIDInList(gppSID, gppConstants.SectionTCFEU2),
 		}
 
 		userSyncActivityAllowed := activityControl.Allow(privacy.ActivitySyncUser,
@@ -128,6 +148,8 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 			}
 		}
 
+		accessLogEntry.SetPrivacySignals(gdprRequestInfo.GDPRSignal == gdpr.SignalYes, "", gppSID)
+
 		tcf2Cfg := tcf2CfgBuilder(cfg.GDPR.TCF2, account.GDPR)
 
 		if shouldReturn, status, body := preventSyncsGDPR(gdprRequestInfo, gdprPermsBuilder, tcf2Cfg); shouldReturn {
@@ -163,19 +185,22 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 		priorityEjector.IsSyncerPriority = isSyncerPriority(bidderName, cfg.UserSync.PriorityGroups)
 
 		// Write Cookie
-		encodedCookie, err := cookie.PrepareCookieForWrite(&cfg.HostCookie, encoder, priorityEjector)
+		encodedCookie, err := cookieStore.PrepareCookieForWrite(r.Context(), cookie, hostCookie, encoder, priorityEjector)
 		if err != nil {
 			if err.Error() == errSyncerIsNotPriority.Error() {
 				w.WriteHeader(http.StatusOK)
 				w.Write([]byte("Warning: " + err.Error() + ", cookie not updated"))
 				so.Status = http.StatusOK
 				return
+			} else if errors.Is(err, usersync.ErrIDStore) {
+				handleBadStatus(w, http.StatusInternalServerError, metrics.SetUidIDStoreError, err, metricsEngine, &so)
+				return
 			} else {
 				handleBadStatus(w, http.StatusBadRequest, metrics.SetUidBadRequest, err, metricsEngine, &so)
 				return
 			}
 		}
-		usersync.WriteCookie(w, encodedCookie, &cfg.HostCookie, setSiteCookie)
+		usersync.WriteCookie(w, encodedCookie, hostCookie, setSiteCookie)
 
 		switch responseFormat {
 		case "i":
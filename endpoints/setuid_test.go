@@ -22,6 +22,8 @@ import (
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
 )
@@ -1146,7 +1148,7 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 					Errors:  []error{},
 					Success: true,
 				}
-				a.On("LogSetUIDObject", &expected).Once()
+				a.On("LogSetUIDObject", matchSetUIDObject(expected, "unknown")).Once()
 			},
 		},
 		{
@@ -1168,7 +1170,7 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 					Errors:  []error{},
 					Success: true,
 				}
-				a.On("LogSetUIDObject", &expected).Once()
+				a.On("LogSetUIDObject", matchSetUIDObject(expected, "unknown")).Once()
 			},
 		},
 		{
@@ -1189,7 +1191,7 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 					Errors:  []error{},
 					Success: false,
 				}
-				a.On("LogSetUIDObject", &expected).Once()
+				a.On("LogSetUIDObject", matchSetUIDObject(expected, "")).Once()
 			},
 		},
 		{
@@ -1210,7 +1212,7 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 					Errors:  []error{errors.New("The bidder name provided is not supported by Prebid Server")},
 					Success: false,
 				}
-				a.On("LogSetUIDObject", &expected).Once()
+				a.On("LogSetUIDObject", matchSetUIDObject(expected, "")).Once()
 			},
 		},
 		{
@@ -1231,7 +1233,7 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 					Errors:  []error{errors.New(`"f" query param is invalid. must be "b" or "i"`)},
 					Success: false,
 				}
-				a.On("LogSetUIDObject", &expected).Once()
+				a.On("LogSetUIDObject", matchSetUIDObject(expected, "")).Once()
 			},
 		},
 		{
@@ -1252,7 +1254,7 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 					Errors:  []error{errors.New("GDPR consent is required when gdpr signal equals 1")},
 					Success: false,
 				}
-				a.On("LogSetUIDObject", &expected).Once()
+				a.On("LogSetUIDObject", matchSetUIDObject(expected, "unknown")).Once()
 			},
 		},
 		{
@@ -1273,7 +1275,7 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 					Errors:  []error{errors.New("The gdpr_consent string prevents cookies from being saved")},
 					Success: false,
 				}
-				a.On("LogSetUIDObject", &expected).Once()
+				a.On("LogSetUIDObject", matchSetUIDObject(expected, "unknown")).Once()
 			},
 		},
 		{
@@ -1295,7 +1297,7 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 					Errors:  []error{errCookieSyncAccountInvalid},
 					Success: false,
 				}
-				a.On("LogSetUIDObject", &expected).Once()
+				a.On("LogSetUIDObject", matchSetUIDObject(expected, "")).Once()
 			},
 		},
 		{
@@ -1317,7 +1319,7 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 					Errors:  []error{errCookieSyncAccountConfigMalformed},
 					Success: false,
 				}
-				a.On("LogSetUIDObject", &expected).Once()
+				a.On("LogSetUIDObject", matchSetUIDObject(expected, "")).Once()
 			},
 		},
 		{
@@ -1339,7 +1341,7 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 					Errors:  []error{errCookieSyncAccountConfigMalformed},
 					Success: false,
 				}
-				a.On("LogSetUIDObject", &expected).Once()
+				a.On("LogSetUIDObject", matchSetUIDObject(expected, "")).Once()
 			},
 		},
 	}
@@ -1363,6 +1365,150 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 	}
 }
 
+// matchSetUIDObject matches the logged setuid object, which carries the account of the given ID once it's been fetched
+type fakeIDStore struct {
+	uids map[string]map[string]usersync.UIDEntry
+	err  error
+}
+
+func (s *fakeIDStore) Get(ctx context.Context, key string) (map[string]usersync.UIDEntry, error) {
+	return s.uids[key], s.err
+}
+
+func (s *fakeIDStore) Set(ctx context.Context, key string, uids map[string]usersync.UIDEntry) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.uids[key] = uids
+	return nil
+}
+
+func TestSetUIDEndpointIDStore(t *testing.T) {
+	expires := time.Now().Add(time.Hour).UTC()
+
+	testCases := []struct {
+		description        string
+		keyCookie          *http.Cookie
+		givenStored        map[string]map[string]usersync.UIDEntry
+		storeErr           error
+		expectedStatusCode int
+		expectedKey        string
+		expectedSyncs      map[string]string
+	}{
+		{
+			description:        "New user",
+			givenStored:        map[string]map[string]usersync.UIDEntry{},
+			expectedStatusCode: http.StatusOK,
+			expectedSyncs:      map[string]string{"pubmatic": "123"},
+		},
+		{
+			description:        "Known user",
+			keyCookie:          &http.Cookie{Name: "fpid", Value: "first-party-key"},
+			givenStored:        map[string]map[string]usersync.UIDEntry{"first-party-key": {"adnxs": {UID: "456", Expires: expires}}},
+			expectedStatusCode: http.StatusOK,
+			expectedKey:        "first-party-key",
+			expectedSyncs:      map[string]string{"pubmatic": "123", "adnxs": "456"},
+		},
+		{
+			description:        "Store error",
+			givenStored:        map[string]map[string]usersync.UIDEntry{},
+			storeErr:           errors.New("disk full"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			store := &fakeIDStore{uids: test.givenStored, err: test.storeErr}
+			cookieStore := usersync.NewCookieStore(config.UserSyncIDStore{Enabled: true, KeyCookie: "fpid"}, store)
+			cfg := &config.Configuration{}
+			syncersByBidder := map[string]usersync.Syncer{"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame}}
+			gdprPermsBuilder := fakePermissionsBuilder{permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true}}.Builder
+			tcf2ConfigBuilder := fakeTCF2ConfigBuilder{cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{})}.Builder
+
+			metricsEngine := &metrics.MetricsEngineMock{}
+			metricsEngine.On("RecordSetUid", mock.Anything)
+			metricsEngine.On("RecordSyncerSet", mock.Anything, mock.Anything)
+
+			request := httptest.NewRequest("GET", "/setuid?bidder=pubmatic&uid=123", nil)
+			if test.keyCookie != nil {
+				request.AddCookie(test.keyCookie)
+			}
+			response := httptest.NewRecorder()
+			endpoint := NewSetUIDEndpoint(cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analyticsBuild.New(&config.Analytics{}), FakeAccountsFetcher{}, metricsEngine, cookieStore)
+			endpoint(response, request, nil)
+
+			assert.Equal(t, test.expectedStatusCode, response.Code)
+			if test.expectedStatusCode != http.StatusOK {
+				metricsEngine.AssertCalled(t, "RecordSetUid", metrics.SetUidIDStoreError)
+				return
+			}
+
+			cookie := parseCookieString(t, response)
+			assert.Empty(t, cookie.GetUIDs(), "the cookie only carries the key")
+
+			request = httptest.NewRequest("GET", "/getuids", nil)
+			request.AddCookie(&http.Cookie{Name: "uids", Value: regexp.MustCompile("uids=(.*?);").FindStringSubmatch(response.Header().Get("Set-Cookie"))[1]})
+			if test.keyCookie != nil {
+				request.AddCookie(test.keyCookie)
+			}
+			stored := cookieStore.ReadCookie(request, usersync.Base64Decoder{}, &cfg.HostCookie)
+			assert.Equal(t, test.expectedSyncs, stored.GetUIDs())
+			if test.expectedKey != "" {
+				assert.Contains(t, store.uids, test.expectedKey)
+			}
+		})
+	}
+}
+
+func TestSetUIDEndpointFirstPartyCookie(t *testing.T) {
+	cfg := &config.Configuration{HostCookie: config.HostCookie{Domain: "pbs.example.com", TTL: 90}}
+	cfg.MarshalAccountDefaults()
+	syncersByBidder := map[string]usersync.Syncer{"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame}}
+	gdprPermsBuilder := fakePermissionsBuilder{permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true}}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{})}.Builder
+	accountsFetcher := FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+		"first_party_acct": json.RawMessage(`{"cookie_sync":{"cookie":{"mode":"first_party","name":"pbs_uids","domain":"publisher.com","external_url":"https://pbs.publisher.com"}}}`),
+	}}
+
+	thirdPartyCookie := usersync.NewCookie()
+	thirdPartyCookie.Sync("adnxs", "third-party-uid")
+	firstPartyCookie := usersync.NewCookie()
+	firstPartyCookie.Sync("rubicon", "first-party-uid")
+	encodedFirstPartyCookie, err := usersync.Base64Encoder{}.Encode(firstPartyCookie)
+	require.NoError(t, err)
+
+	request := httptest.NewRequest("GET", "/setuid?bidder=pubmatic&uid=123&account=first_party_acct", nil)
+	addCookie(request, thirdPartyCookie)
+	request.AddCookie(&http.Cookie{Name: "pbs_uids", Value: encodedFirstPartyCookie})
+	request.Header.Set("User-Agent", "Mozilla/5.0 Chrome/120.0.0.0 Safari/537.36")
+	response := httptest.NewRecorder()
+	endpoint := NewSetUIDEndpoint(cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analyticsBuild.New(&config.Analytics{}), accountsFetcher, &metricsConf.NilMetricsEngine{}, nil)
+	endpoint(response, request, nil)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	setCookie := response.Header().Get("Set-Cookie")
+	assert.True(t, strings.HasPrefix(setCookie, "pbs_uids="), "the cookie is named by the account")
+	assert.Contains(t, setCookie, "Domain=publisher.com")
+	assert.Contains(t, setCookie, "SameSite=Lax")
+
+	httpCookie, err := http.ParseSetCookie(setCookie)
+	require.NoError(t, err)
+	cookie := usersync.Base64Decoder{}.Decode(httpCookie.Value)
+	assert.Equal(t, map[string]string{"rubicon": "first-party-uid", "pubmatic": "123"}, cookie.GetUIDs(), "the uids are read from the cookie of the account")
+}
+
+func matchSetUIDObject(expected analytics.SetUIDObject, accountID string) interface{} {
+	return mock.MatchedBy(func(so *analytics.SetUIDObject) bool {
+		if accountID == "" && so.Account != nil || accountID != "" && (so.Account == nil || so.Account.ID != accountID) {
+			return false
+		}
+		actual := *so
+		actual.Account = nil
+		return assert.ObjectsAreEqual(expected, actual)
+	})
+}
+
 func TestOptedOut(t *testing.T) {
 	request := httptest.NewRequest("GET", "/setuid?bidder=pubmatic&uid=123", nil)
 	cookie := usersync.NewCookie()
@@ -1639,7 +1785,7 @@ func doRequest(req *http.Request, analytics analytics.Runner, metrics metrics.Me
 		"valid_acct_with_invalid_activities":                 json.RawMessage(`{"privacy":{"allowactivities":{"syncUser":{"rules":[{"condition":{"componentName": ["bidderA.bidderB.bidderC"]}}]}}}}`),
 	}}
 
-	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, fakeAccountsFetcher, metrics)
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, fakeAccountsFetcher, metrics, nil)
 	response := httptest.NewRecorder()
 	endpoint(response, req, nil)
 	return response
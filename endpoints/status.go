@@ -1,9 +1,12 @@
 package endpoints
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/readiness"
 )
 
 // NewStatusEndpoint returns a handler which writes the given response when the app is ready to serve requests.
@@ -20,3 +23,19 @@ func NewStatusEndpoint(response string) httprouter.Handle {
 		w.Write(responseBytes)
 	}
 }
+
+// NewReadinessEndpoint returns a handler which runs the readiness checks. It responds with a 200 if all the critical
+// checks pass, and a 503 otherwise, so that the instance stops receiving traffic until its dependencies are loaded.
+func NewReadinessEndpoint(checks *readiness.Checks) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		report := checks.Run(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			logger.Errorf("Failed to write the readiness report: %v", err)
+		}
+	}
+}
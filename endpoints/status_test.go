@@ -1,9 +1,15 @@
 package endpoints
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/readiness"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestStatusNoContent(t *testing.T) {
@@ -26,3 +32,50 @@ func TestStatusWithContent(t *testing.T) {
 		t.Errorf("Bad status body. Expected %s, got %s", "ready", w.Body.String())
 	}
 }
+
+func TestReadiness(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cacheErr     error
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "ready",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"ready":true,"checks":{"currency_rates":{"status":"ready","critical":true},"prebid_cache":{"status":"ready","critical":false}}}`,
+		},
+		{
+			name:         "non-critical-not-ready",
+			cacheErr:     errors.New("connection refused"),
+			expectedCode: http.StatusOK,
+			expectedBody: `{"ready":true,"checks":{"currency_rates":{"status":"ready","critical":true},"prebid_cache":{"status":"not_ready","critical":false,"error":"connection refused"}}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			checks := readiness.NewChecks(config.Readiness{Enabled: true, TimeoutMS: 100, Critical: []string{"currency_rates"}})
+			checks.Add("currency_rates", func(context.Context) error { return nil })
+			checks.Add("prebid_cache", func(context.Context) error { return test.cacheErr })
+
+			w := httptest.NewRecorder()
+			NewReadinessEndpoint(checks)(w, httptest.NewRequest("GET", "/status/ready", nil), nil)
+
+			assert.Equal(t, test.expectedCode, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.JSONEq(t, test.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestReadinessCriticalNotReady(t *testing.T) {
+	checks := readiness.NewChecks(config.Readiness{Enabled: true, TimeoutMS: 100, Critical: []string{"gdpr_vendor_list"}})
+	checks.Add("gdpr_vendor_list", func(context.Context) error { return errors.New("the latest vendor lists failed to load") })
+
+	w := httptest.NewRecorder()
+	NewReadinessEndpoint(checks)(w, httptest.NewRequest("GET", "/status/ready", nil), nil)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"ready":false,"checks":{"gdpr_vendor_list":{"status":"not_ready","critical":true,"error":"the latest vendor lists failed to load"}}}`, w.Body.String())
+}
@@ -0,0 +1,76 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/validation"
+)
+
+type storedDataQuarantineResponse struct {
+	Quarantined []validation.QuarantinedData `json:"quarantined"`
+}
+
+type storedRequestsResponse struct {
+	Endpoint   string                       `json:"endpoint"`
+	StoredData []stored_requests.Inspection `json:"stored_data"`
+	Errors     []string                     `json:"errors,omitempty"`
+}
+
+// NewStoredRequestsEndpoint returns a handler which writes how the Stored Requests and Imps given in the comma separated
+// "requests" and "imps" query parameters are resolved by the fetcher of the endpoint given in the "endpoint" query
+// parameter, which defaults to "auction". Secrets are redacted.
+func NewStoredRequestsEndpoint(fetchersByEndpoint map[string]stored_requests.Fetcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		endpoint := query.Get("endpoint")
+		if endpoint == "" {
+			endpoint = "auction"
+		}
+		fetcher, ok := fetchersByEndpoint[endpoint]
+		if !ok {
+			http.Error(w, fmt.Sprintf("Unknown endpoint %q", endpoint), http.StatusBadRequest)
+			return
+		}
+
+		requestIDs := splitIDs(query.Get("requests"))
+		impIDs := splitIDs(query.Get("imps"))
+		if len(requestIDs) == 0 && len(impIDs) == 0 {
+			http.Error(w, `At least one of the query parameters "requests" or "imps" is required`, http.StatusBadRequest)
+			return
+		}
+
+		inspections, errs := stored_requests.InspectRequests(r.Context(), fetcher, requestIDs, impIDs)
+		for i := range inspections {
+			redactInspection(&inspections[i])
+		}
+
+		writeAdminResponse(w, "/storedrequests", storedRequestsResponse{
+			Endpoint:   endpoint,
+			StoredData: inspections,
+			Errors:     appendErrorStrings(nil, errs),
+		})
+	}
+}
+
+// NewStoredDataQuarantineEndpoint returns a handler which writes the stored data rejected by validation and kept
+// in the quarantine, oldest first. Secrets are redacted.
+func NewStoredDataQuarantineEndpoint(quarantine *validation.Quarantine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		quarantined := quarantine.Data()
+		for i := range quarantined {
+			quarantined[i].Data = redactSecrets(quarantined[i].Data)
+		}
+		writeAdminResponse(w, "/storedrequests/quarantine", storedDataQuarantineResponse{Quarantined: quarantined})
+	}
+}
+
+func splitIDs(ids string) []string {
+	if ids == "" {
+		return nil
+	}
+	return strings.Split(ids, ",")
+}
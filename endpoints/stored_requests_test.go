@@ -0,0 +1,124 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+	"github.com/prebid/prebid-server/v3/stored_requests/validation"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoredRequestsEndpoint(t *testing.T) {
+	fetchers := map[string]stored_requests.Fetcher{
+		"auction": fakeStoredRequestsFetcher{
+			requests: map[string]json.RawMessage{"req": json.RawMessage(`{"id":"req"}`)},
+			imps:     map[string]json.RawMessage{"imp": json.RawMessage(`{"ext":{"prebid":{"bidder":{"a":{"secret":"s"}}}}}`)},
+		},
+		"amp": fakeStoredRequestsFetcher{
+			requests: map[string]json.RawMessage{"amp-req": json.RawMessage(`{"id":"amp-req"}`)},
+		},
+	}
+
+	testCases := []struct {
+		description  string
+		query        string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			description:  "no-ids",
+			query:        "",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "unknown-endpoint",
+			query:        "?endpoint=other&requests=req",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			description:  "default-endpoint",
+			query:        "?requests=req&imps=imp",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"endpoint":"auction","stored_data":[
+				{"id":"req","type":"Request","cache":"none","backend":{"id":"req"}},
+				{"id":"imp","type":"Imp","cache":"none","backend":{"ext":{"prebid":{"bidder":{"a":{"secret":"<REDACTED>"}}}}}}
+			]}`,
+		},
+		{
+			description:  "amp-endpoint",
+			query:        "?endpoint=amp&requests=amp-req",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"endpoint":"amp","stored_data":[{"id":"amp-req","type":"Request","cache":"none","backend":{"id":"amp-req"}}]}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			endpoint := NewStoredRequestsEndpoint(fetchers)
+
+			request := httptest.NewRequest(http.MethodGet, "/storedrequests"+test.query, nil)
+			recorder := httptest.NewRecorder()
+			endpoint(recorder, request)
+
+			require.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedCode == http.StatusOK {
+				assert.JSONEq(t, test.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestStoredDataQuarantineEndpoint(t *testing.T) {
+	quarantine := validation.NewQuarantine(10)
+	paramsValidator, err := openrtb_ext.NewBidderParamsValidator("../static/bidder-params")
+	require.NoError(t, err)
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordStoredDataError", mock.Anything)
+	validator := validation.NewValidator(config.AccountDataType, nil, paramsValidator, metricsEngine, quarantine)
+	validator.Validate(events.Save{Accounts: map[string]json.RawMessage{"account": json.RawMessage(`{"disabled":"no","api_key":"1234"}`)}})
+
+	recorder := httptest.NewRecorder()
+	NewStoredDataQuarantineEndpoint(quarantine)(recorder, httptest.NewRequest(http.MethodGet, "/storedrequests/quarantine", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Quarantined []struct {
+			Section string          `json:"section"`
+			Type    string          `json:"type"`
+			ID      string          `json:"id"`
+			Data    json.RawMessage `json:"data"`
+			Error   string          `json:"error"`
+		} `json:"quarantined"`
+	}
+	require.NoError(t, jsonutil.UnmarshalValid(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Quarantined, 1)
+	assert.Equal(t, "accounts", response.Quarantined[0].Section)
+	assert.Equal(t, "Account", response.Quarantined[0].Type)
+	assert.Equal(t, "account", response.Quarantined[0].ID)
+	assert.JSONEq(t, `{"disabled":"no","api_key":"<REDACTED>"}`, string(response.Quarantined[0].Data))
+	assert.NotEmpty(t, response.Quarantined[0].Error)
+}
+
+type fakeStoredRequestsFetcher struct {
+	requests map[string]json.RawMessage
+	imps     map[string]json.RawMessage
+}
+
+func (f fakeStoredRequestsFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	return f.requests, f.imps, nil
+}
+
+func (f fakeStoredRequestsFetcher) FetchResponses(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	return nil, nil
+}
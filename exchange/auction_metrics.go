@@ -0,0 +1,105 @@
+package exchange
+
+import (
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// auctionMetricsCurrency is the currency of the auction metrics if the host doesn't configure one
+const auctionMetricsCurrency = "USD"
+
+// recordAuctionMetrics records the imps offered to each bidder and the bids which took part in the auction, once the
+// winning bid of each imp is decided. The winning bids are taken from the auction if targeting ran one. The CPM of the
+// bids is converted to the metrics currency, and compared to the floor of their imp.
+func (e *exchange) recordAuctionMetrics(bidderRequests []BidderRequest, imps []openrtb2.Imp, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, auc *auction, preferDeals bool, pubID string, conversions currency.Conversions) {
+	metricsCurrency := e.metricsCurrency
+	if metricsCurrency == "" {
+		metricsCurrency = auctionMetricsCurrency
+	}
+
+	for _, bidderRequest := range bidderRequests {
+		if bidderRequest.BidRequest != nil {
+			e.me.RecordAdapterAuctionImps(bidderRequest.BidderCoreName, pubID, len(bidderRequest.BidRequest.Imp))
+		}
+	}
+
+	if len(adapterBids) == 0 {
+		return
+	}
+	var winningBids map[string]*entities.PbsOrtbBid
+	if auc != nil {
+		winningBids = auc.winningBids
+	} else {
+		winningBids = findWinningBids(adapterBids, len(imps), preferDeals)
+	}
+
+	floors := make(map[string]float64, len(imps))
+	for _, imp := range imps {
+		if imp.BidFloor > 0 {
+			if floor, err := convertPrice(imp.BidFloor, imp.BidFloorCur, metricsCurrency, conversions); err == nil {
+				floors[imp.ID] = floor
+			}
+		}
+	}
+
+	for _, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			cpm, err := convertPrice(bid.Bid.Price, seatBid.Currency, metricsCurrency, conversions)
+			if err != nil {
+				continue
+			}
+			var floorRatio float64
+			if floor, ok := floors[bid.Bid.ImpID]; ok {
+				floorRatio = cpm / floor
+			}
+
+			adapter := bid.AdapterCode
+			if adapter == "" {
+				adapter = openrtb_ext.BidderName(seatBid.Seat)
+			}
+			labels := metrics.AuctionBidLabels{
+				Adapter: adapter,
+				PubID:   pubID,
+				Deal:    bid.Bid.DealID != "",
+				Won:     winningBids[bid.Bid.ImpID] == bid,
+			}
+			e.me.RecordAuctionBid(labels, cpm, floorRatio)
+		}
+	}
+}
+
+// findWinningBids returns the winning bid of each imp, like newAuction without indexing every bid, since the auction
+// isn't needed by the response without targeting
+func findWinningBids(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, numImps int, preferDeals bool) map[string]*entities.PbsOrtbBid {
+	winningBids := make(map[string]*entities.PbsOrtbBid, numImps)
+	for _, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			if wbid, ok := winningBids[bid.Bid.ImpID]; !ok || isNewWinningBid(bid.Bid, wbid.Bid, preferDeals) {
+				winningBids[bid.Bid.ImpID] = bid
+			}
+		}
+	}
+	return winningBids
+}
+
+// convertPrice converts price from the from currency to the to currency
+func convertPrice(price float64, from, to string, conversions currency.Conversions) (float64, error) {
+	// OpenRTB defaults the currencies to USD
+	if from == "" {
+		from = "USD"
+	}
+	rate, err := conversions.GetRate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return price * rate, nil
+}
@@ -0,0 +1,108 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+func TestRecordAuctionMetrics(t *testing.T) {
+	imps := []openrtb2.Imp{
+		{ID: "imp1", BidFloor: 1},
+		{ID: "imp2", BidFloor: 2, BidFloorCur: "EUR"},
+		{ID: "imp3"},
+	}
+	bidderRequests := []BidderRequest{
+		{BidderName: openrtb_ext.BidderAppnexus, BidderCoreName: openrtb_ext.BidderAppnexus, BidRequest: &openrtb2.BidRequest{Imp: imps}},
+		{BidderName: openrtb_ext.BidderRubicon, BidderCoreName: openrtb_ext.BidderRubicon, BidRequest: &openrtb2.BidRequest{Imp: imps[:2]}},
+	}
+	appnexusBid1 := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ImpID: "imp1", Price: 1}, AdapterCode: openrtb_ext.BidderAppnexus}
+	appnexusBid2 := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ImpID: "imp2", Price: 1, DealID: "deal"}, AdapterCode: openrtb_ext.BidderAppnexus}
+	rubiconBid1 := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ImpID: "imp1", Price: 0.5}, AdapterCode: openrtb_ext.BidderRubicon}
+	rubiconBid2 := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ImpID: "imp2", Price: 1.5}, AdapterCode: openrtb_ext.BidderRubicon}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		openrtb_ext.BidderAppnexus: {Bids: []*entities.PbsOrtbBid{appnexusBid1, appnexusBid2}, Currency: "EUR", Seat: "appnexus"},
+		openrtb_ext.BidderRubicon:  {Bids: []*entities.PbsOrtbBid{rubiconBid1, rubiconBid2}, Currency: "EUR", Seat: "rubicon"},
+	}
+	conversions := currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 2}})
+
+	testCases := []struct {
+		name        string
+		preferDeals bool
+		auction     *auction
+		dealWins    bool
+	}{
+		{
+			name:        "without-targeting",
+			preferDeals: false,
+			dealWins:    false,
+		},
+		{
+			name:        "without-targeting-prefer-deals",
+			preferDeals: true,
+			dealWins:    true,
+		},
+		{
+			name:     "with-targeting",
+			auction:  &auction{winningBids: map[string]*entities.PbsOrtbBid{"imp1": appnexusBid1, "imp2": appnexusBid2}},
+			dealWins: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.On("RecordAdapterAuctionImps", openrtb_ext.BidderAppnexus, "acct-1", 3).Once()
+			metricsMock.On("RecordAdapterAuctionImps", openrtb_ext.BidderRubicon, "acct-1", 2).Once()
+			metricsMock.On("RecordAuctionBid", metrics.AuctionBidLabels{Adapter: openrtb_ext.BidderAppnexus, PubID: "acct-1", Won: true}, 2.0, 2.0).Once()
+			metricsMock.On("RecordAuctionBid", metrics.AuctionBidLabels{Adapter: openrtb_ext.BidderAppnexus, PubID: "acct-1", Deal: true, Won: test.dealWins}, 2.0, 0.5).Once()
+			metricsMock.On("RecordAuctionBid", metrics.AuctionBidLabels{Adapter: openrtb_ext.BidderRubicon, PubID: "acct-1"}, 1.0, 1.0).Once()
+			metricsMock.On("RecordAuctionBid", metrics.AuctionBidLabels{Adapter: openrtb_ext.BidderRubicon, PubID: "acct-1", Won: !test.dealWins}, 3.0, 0.75).Once()
+
+			e := exchange{me: metricsMock, metricsCurrency: "USD"}
+			e.recordAuctionMetrics(bidderRequests, imps, adapterBids, test.auction, test.preferDeals, "acct-1", conversions)
+
+			metricsMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecordAuctionMetricsCurrency(t *testing.T) {
+	imps := []openrtb2.Imp{{ID: "imp1", BidFloor: 1}}
+	bidderRequests := []BidderRequest{
+		{BidderName: openrtb_ext.BidderAppnexus, BidderCoreName: openrtb_ext.BidderAppnexus, BidRequest: &openrtb2.BidRequest{Imp: imps}},
+	}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		openrtb_ext.BidderAppnexus: {
+			Bids:     []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ImpID: "imp1", Price: 3}, AdapterCode: openrtb_ext.BidderAppnexus}},
+			Currency: "USD",
+		},
+	}
+
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordAdapterAuctionImps", openrtb_ext.BidderAppnexus, "acct-1", 1).Once()
+	metricsMock.On("RecordAuctionBid", metrics.AuctionBidLabels{Adapter: openrtb_ext.BidderAppnexus, PubID: "acct-1", Won: true}, 1.5, 3.0).Once()
+
+	e := exchange{me: metricsMock, metricsCurrency: "EUR"}
+	e.recordAuctionMetrics(bidderRequests, imps, adapterBids, nil, false, "acct-1", currency.NewRates(map[string]map[string]float64{"USD": {"EUR": 0.5}}))
+
+	metricsMock.AssertExpectations(t)
+}
+
+func TestRecordAuctionMetricsNoBids(t *testing.T) {
+	bidderRequests := []BidderRequest{
+		{BidderName: openrtb_ext.BidderAppnexus, BidderCoreName: openrtb_ext.BidderAppnexus, BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}},
+	}
+
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordAdapterAuctionImps", openrtb_ext.BidderAppnexus, "acct-1", 1).Once()
+
+	e := exchange{me: metricsMock}
+	e.recordAuctionMetrics(bidderRequests, nil, nil, nil, false, "acct-1", currency.NewConstantRates())
+
+	metricsMock.AssertExpectations(t)
+}
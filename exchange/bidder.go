@@ -37,6 +37,7 @@ import (
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"golang.org/x/net/context/ctxhttp"
 )
@@ -116,6 +117,7 @@ func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Config
 			DisableConnDialMetrics: cfg.Metrics.Disabled.AdapterConnectionDialMetrics,
 			DebugInfo:              config.DebugInfo{Allow: parseDebugInfo(debugInfo)},
 			EndpointCompression:    endpointCompression,
+			PropagateTraceContext:  cfg.BidderInfos[string(name)].Tracing.PropagateTraceContext,
 			ThrottleConfig: bidderAdapterThrottleConfig{
 				enabled:                 cfg.Client.Throttle.EnableThrottling,
 				simulateOnly:            cfg.Client.Throttle.SimulateThrottlingOnly,
@@ -159,6 +161,8 @@ type bidderAdapterConfig struct {
 	DebugInfo              config.DebugInfo
 	EndpointCompression    string
 	ThrottleConfig         bidderAdapterThrottleConfig
+	// PropagateTraceContext sends the traceparent header of the auction to the bidder
+	PropagateTraceContext bool
 }
 
 type bidderAdapterThrottleConfig struct {
@@ -203,7 +207,9 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 		if bidRequestOptions.tmaxAdjustments != nil && bidRequestOptions.tmaxAdjustments.IsEnforced {
 			bidderRequest.BidRequest.TMax = getBidderTmax(&bidderTmaxCtx{ctx}, bidderRequest.BidRequest.TMax, *bidRequestOptions.tmaxAdjustments)
 		}
+		_, makeRequestsSpan := tracing.Start(ctx, tracing.SpanMakeRequests, tracing.AttributeBidder.String(string(bidder.BidderName)))
 		reqData, errs = bidder.Bidder.MakeRequests(bidderRequest.BidRequest, reqInfo)
+		tracing.EndWithErrors(makeRequestsSpan, errortypes.FatalOnly(errs))
 
 		if len(reqData) == 0 {
 			// If the adapter failed to generate both requests and errors, this is an error.
@@ -303,7 +309,9 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 
 		if httpInfo.err == nil {
 			extraRespInfo.respProcessingStartTime = time.Now()
+			_, makeBidsSpan := tracing.Start(ctx, tracing.SpanMakeBids, tracing.AttributeBidder.String(string(bidder.BidderName)))
 			bidResponse, moreErrs := bidder.Bidder.MakeBids(bidderRequest.BidRequest, httpInfo.request, httpInfo.response)
+			tracing.EndWithErrors(makeBidsSpan, errortypes.FatalOnly(moreErrs))
 			errs = append(errs, moreErrs...)
 
 			if bidResponse != nil {
@@ -570,7 +578,13 @@ func makeExt(httpInfo *httpCallInfo) *openrtb_ext.ExtHttpCall {
 // Bidder interface.
 func (bidder *BidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
 	if bidder.shouldRequest() {
-		return bidder.doRequestImpl(ctx, req, loggerI.Warnf, bidderRequestStartTime, tmaxAdjustments)
+		ctx, span := tracing.StartClient(ctx, tracing.SpanBidderHTTPRequest, tracing.AttributeBidder.String(string(bidder.BidderName)))
+		httpInfo := bidder.doRequestImpl(ctx, req, loggerI.Warnf, bidderRequestStartTime, tmaxAdjustments)
+		if httpInfo.response != nil {
+			span.SetAttributes(tracing.AttributeStatus.Int(httpInfo.response.StatusCode))
+		}
+		tracing.End(span, httpInfo.err)
+		return httpInfo
 	}
 	return &httpCallInfo{
 		request: req,
@@ -594,6 +608,9 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 		}
 	}
 	httpReq.Header = req.Headers
+	if bidder.config.PropagateTraceContext {
+		tracing.Inject(ctx, httpReq.Header)
+	}
 
 	// If adapter connection metrics are not disabled, add the client trace
 	// to get complete connection info into our metrics
@@ -754,6 +771,7 @@ func (bidder *BidderAdapter) addClientTrace(ctx context.Context, dialMetricsDisa
 			}
 
 			bidder.me.RecordAdapterConnections(bidder.BidderName, info.Reused, connWaitTime)
+			tracing.AddDurationEvent(ctx, tracing.EventGetConn, connWaitTime, tracing.AttributeConnReused.Bool(info.Reused))
 		},
 		// DNSStart is called when a DNS lookup begins.
 		DNSStart: func(info httptrace.DNSStartInfo) {
@@ -764,6 +782,7 @@ func (bidder *BidderAdapter) addClientTrace(ctx context.Context, dialMetricsDisa
 			dnsLookupTime := time.Since(dnsStart)
 
 			bidder.me.RecordDNSTime(dnsLookupTime)
+			tracing.AddDurationEvent(ctx, tracing.EventDNS, dnsLookupTime)
 		},
 
 		TLSHandshakeStart: func() {
@@ -774,6 +793,7 @@ func (bidder *BidderAdapter) addClientTrace(ctx context.Context, dialMetricsDisa
 			tlsHandshakeTime := time.Since(tlsStart)
 
 			bidder.me.RecordTLSHandshakeTime(tlsHandshakeTime)
+			tracing.AddDurationEvent(ctx, tracing.EventTLSHandshake, tlsHandshakeTime)
 		},
 	}
 
@@ -789,6 +809,7 @@ func (bidder *BidderAdapter) addClientTrace(ctx context.Context, dialMetricsDisa
 		trace.ConnectDone = func(network, addr string, err error) {
 			dialStartTime := time.Since(dialStart)
 			bidder.me.RecordAdapterConnectionDialTime(bidder.BidderName, dialStartTime)
+			tracing.AddDurationEvent(ctx, tracing.EventConnect, dialStartTime)
 
 			if err != nil {
 				bidder.me.RecordAdapterConnectionDialError(bidder.BidderName)
@@ -0,0 +1,131 @@
+package exchange
+
+import (
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/firstpartydata"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/privacy"
+)
+
+// BidderPrivacy holds the privacy policies of a request, which are enforced on the request of each bidder
+type BidderPrivacy struct {
+	Account        *config.Account
+	Activities     privacy.ActivityControl
+	FirstPartyData map[openrtb_ext.BidderName]*firstpartydata.ResolvedFirstPartyData
+	CCPAEnforcer   privacy.PolicyEnforcer
+	LMT            bool
+	COPPA          bool
+
+	eidPermissions accountEIDPermissions
+}
+
+// PrivacyStep runs a step of the privacy enforcement, which may remove or mask fields of the request of a bidder.
+// The reason explains the step, e.g. for the privacy debug endpoint.
+type PrivacyStep func(reason string, apply func())
+
+// NewBidderPrivacy returns the privacy policies of the request. A malformed CCPA signal is returned as an error, and
+// isn't enforced.
+func NewBidderPrivacy(req *openrtb_ext.RequestWrapper, gpp gpplib.GppContainer, consent string, gdprEnforced bool, privacyConfig config.Privacy, account *config.Account, activities privacy.ActivityControl, requestAliases map[string]string, channel config.ChannelType, fpd map[openrtb_ext.BidderName]*firstpartydata.ResolvedFirstPartyData) (BidderPrivacy, error) {
+	ccpaEnforcer, err := extractCCPA(req.BidRequest, privacyConfig, account, requestAliases, channel, gpp)
+
+	return BidderPrivacy{
+		Account:        account,
+		Activities:     activities,
+		FirstPartyData: fpd,
+		CCPAEnforcer:   ccpaEnforcer,
+		LMT:            extractLMT(req.BidRequest, privacyConfig).ShouldEnforce(unknownBidder),
+		COPPA:          req.BidRequest.Regs != nil && req.BidRequest.Regs.COPPA == 1,
+		eidPermissions: newAccountEIDPermissions(account.Privacy.EIDPermissions, gdprEnforced, consent, req, gpp),
+	}, err
+}
+
+// FPDUserEIDsPresent tells whether the first party data of the bidder has its own user EIDs, rather than those of req
+func (p BidderPrivacy) FPDUserEIDsPresent(req *openrtb_ext.RequestWrapper, bidder string) bool {
+	return fpdUserEIDExists(req, p.FirstPartyData, bidder)
+}
+
+// Apply enforces the privacy policies on the request of a bidder which isn't blocked by them: it applies the first
+// party data of the bidder, removes the EIDs the account doesn't permit and scrubs the fields denied by the activity
+// controls, GDPR, CCPA, LMT and COPPA. Each step runs through step, if given.
+func (p BidderPrivacy) Apply(reqWrapper *openrtb_ext.RequestWrapper, coreBidderName openrtb_ext.BidderName, bidderName string, isRequestAlias bool, fpdUserEIDsPresent bool, auctionPermissions gdpr.AuctionPermissions, me metrics.MetricsEngine, step PrivacyStep) error {
+	if step == nil {
+		step = func(_ string, apply func()) { apply() }
+	}
+
+	step("bidder first party data", func() {
+		applyFPD(p.FirstPartyData, coreBidderName, openrtb_ext.BidderName(bidderName), isRequestAlias, reqWrapper, fpdUserEIDsPresent)
+	})
+
+	// account eid permissions, enforced after fpd so they also apply to the bidder specific eids
+	step("account eid permissions", func() {
+		for source, count := range p.eidPermissions.removeUnpermittedEIDs(reqWrapper, bidderName) {
+			me.RecordAdapterEIDsRemoved(coreBidderName, source, count)
+		}
+	})
+
+	return p.applyPrivacy(reqWrapper, coreBidderName, bidderName, auctionPermissions, me, step)
+}
+
+func (p BidderPrivacy) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper, coreBidderName openrtb_ext.BidderName, bidderName string, auctionPermissions gdpr.AuctionPermissions, me metrics.MetricsEngine, step PrivacyStep) error {
+	scope := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName}
+	bidRequest := ortb.CloneBidRequestPartial(reqWrapper.BidRequest)
+	reqWrapper.BidRequest = bidRequest
+
+	ipConf := privacy.IPConf{
+		IPV6:      p.Account.Privacy.IPv6Config,
+		IPV4:      p.Account.Privacy.IPv4Config,
+		Precision: p.Activities.GeoPrecision(scope, privacy.NewRequestFromBidRequest(*reqWrapper)),
+	}
+
+	passIDActivityAllowed := p.Activities.Allow(privacy.ActivityTransmitUserFPD, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	buyerUIDSet := reqWrapper.User != nil && reqWrapper.User.BuyerUID != ""
+	buyerUIDRemoved := false
+	if !passIDActivityAllowed {
+		step("activity transmitUfpd denied", func() { privacy.ScrubUserFPD(reqWrapper) })
+		buyerUIDRemoved = true
+	} else {
+		if !auctionPermissions.PassID {
+			step("gdpr denies passing user ids", func() { privacy.ScrubGdprID(reqWrapper) })
+			buyerUIDRemoved = true
+		}
+
+		if p.CCPAEnforcer.ShouldEnforce(bidderName) {
+			step("ccpa opt-out", func() { privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false) })
+			buyerUIDRemoved = true
+		}
+	}
+	if buyerUIDSet && buyerUIDRemoved {
+		me.RecordAdapterBuyerUIDScrubbed(coreBidderName)
+	}
+
+	passGeoActivityAllowed := p.Activities.Allow(privacy.ActivityTransmitPreciseGeo, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	if !passGeoActivityAllowed {
+		step("activity transmitPreciseGeo denied", func() { privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf) })
+	} else {
+		if !auctionPermissions.PassGeo {
+			step("gdpr denies passing precise geo", func() { privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf) })
+		}
+		if p.CCPAEnforcer.ShouldEnforce(bidderName) {
+			step("ccpa opt-out", func() { privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false) })
+		}
+	}
+
+	if p.COPPA {
+		step("coppa", func() { privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", true) })
+	} else if p.LMT {
+		step("lmt", func() { privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false) })
+	}
+
+	step("pseudonymization", func() { p.Activities.Pseudonymize(reqWrapper, scope) })
+
+	passTIDAllowed := p.Activities.Allow(privacy.ActivityTransmitTIDs, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	if !passTIDAllowed {
+		step("activity transmitTid denied", func() { privacy.ScrubTID(reqWrapper) })
+	}
+
+	return reqWrapper.RebuildRequest()
+}
@@ -41,6 +41,10 @@ import (
 	"github.com/prebid/prebid-server/v3/version"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 // TestSingleBidder makes sure that the following things work if the Bidder needs only one request.
@@ -467,6 +471,79 @@ func TestSetGPCHeaderNil(t *testing.T) {
 	assert.ElementsMatch(t, seatBids[0].HttpCalls, expectedHttpCall)
 }
 
+func TestPropagateTraceContext(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	testCases := []struct {
+		description string
+		propagate   bool
+	}{
+		{
+			description: "Propagated",
+			propagate:   true,
+		},
+		{
+			description: "Not propagated",
+			propagate:   false,
+		},
+	}
+
+	for _, test := range testCases {
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		var traceparent string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("traceparent")
+			w.Write([]byte("responseJson"))
+		}))
+
+		bidderImpl := &goodSingleBidder{
+			httpRequest: &adapters.RequestData{
+				Method: "POST",
+				Uri:    server.URL,
+				Body:   []byte("requestJson"),
+			},
+			bidResponse: &adapters.BidderResponse{},
+		}
+		cfg := &config.Configuration{
+			BidderInfos: config.BidderInfos{
+				string(openrtb_ext.BidderAppnexus): config.BidderInfo{Tracing: config.BidderInfoTracing{PropagateTraceContext: test.propagate}},
+			},
+		}
+		bidder := AdaptBidder(bidderImpl, server.Client(), cfg, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+		currencyConverter := currency.NewRateConverter(&http.Client{}, time.Duration(1), "", time.Duration(0))
+		bidderReq := BidderRequest{
+			BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+			BidderName: "test",
+		}
+
+		ctx, span := otel.Tracer("test").Start(context.Background(), "auction")
+		_, _, errs := bidder.requestBid(ctx, bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{bidAdjustments: map[string]float64{"test": 1}}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+		span.End()
+		server.Close()
+		assert.Empty(t, errs, test.description)
+
+		var httpSpan sdktrace.ReadOnlySpan
+		for _, ended := range recorder.Ended() {
+			if ended.Name() == "bidder.http_request" {
+				httpSpan = ended
+			}
+		}
+		if !assert.NotNil(t, httpSpan, test.description) {
+			continue
+		}
+		assert.Equal(t, oteltrace.SpanKindClient, httpSpan.SpanKind(), test.description)
+		assert.Equal(t, span.SpanContext().SpanID(), httpSpan.Parent().SpanID(), test.description)
+		if test.propagate {
+			expected := fmt.Sprintf("00-%s-%s-01", httpSpan.SpanContext().TraceID(), httpSpan.SpanContext().SpanID())
+			assert.Equal(t, expected, traceparent, test.description)
+		} else {
+			assert.Empty(t, traceparent, test.description)
+		}
+	}
+}
+
 // TestMultiBidder makes sure all the requests get sent, and the responses processed.
 // Because this is done in parallel, it should be run under the race detector.
 func TestMultiBidder(t *testing.T) {
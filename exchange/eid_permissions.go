@@ -0,0 +1,117 @@
+package exchange
+
+import (
+	"strings"
+
+	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/go-gdpr/vendorconsent"
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	gppPolicy "github.com/prebid/prebid-server/v3/privacy/gpp"
+)
+
+// accountEIDPermissions enforces the EID permissions of the account, with the consent signals of the request
+type accountEIDPermissions struct {
+	permissions map[string]config.AccountEIDPermission
+	// purposeConsents are the purposes the user consented to, or nil if GDPR isn't enforced
+	purposeConsents map[int]bool
+	gppOptOut       bool
+}
+
+// newAccountEIDPermissions returns the EID permissions of the account for the request. The TCF consent is only read
+// when GDPR is enforced, a malformed consent consenting to no purpose.
+func newAccountEIDPermissions(permissions []config.AccountEIDPermission, gdprEnforced bool, consent string, req *openrtb_ext.RequestWrapper, gpp gpplib.GppContainer) accountEIDPermissions {
+	if len(permissions) == 0 {
+		return accountEIDPermissions{}
+	}
+
+	p := accountEIDPermissions{
+		permissions: make(map[string]config.AccountEIDPermission, len(permissions)),
+	}
+	for _, permission := range permissions {
+		p.permissions[permission.Source] = permission
+	}
+
+	if gdprEnforced {
+		p.purposeConsents = make(map[int]bool)
+		if parsedConsent, err := vendorconsent.ParseString(consent); err == nil {
+			for purpose := 1; purpose <= 10; purpose++ {
+				p.purposeConsents[purpose] = parsedConsent.PurposeAllowed(consentconstants.Purpose(purpose))
+			}
+		}
+	}
+
+	if req.Regs != nil {
+		for _, section := range gpp.Sections {
+			if !gppPolicy.IsSIDInList(req.Regs.GPPSID, section.GetID()) {
+				continue
+			}
+			if usnat, ok := gppPolicy.ReadUSNat(section); ok && usnat.OptedOut() {
+				p.gppOptOut = true
+				break
+			}
+		}
+	}
+	return p
+}
+
+// removeUnpermittedEIDs removes the user EIDs the bidder isn't permitted to receive, returning the number of EIDs
+// removed by source
+func (p accountEIDPermissions) removeUnpermittedEIDs(reqWrapper *openrtb_ext.RequestWrapper, bidder string) map[string]int {
+	if len(p.permissions) == 0 || reqWrapper.User == nil || len(reqWrapper.User.EIDs) == 0 {
+		return nil
+	}
+
+	var removed map[string]int
+	eidsAllowed := make([]openrtb2.EID, 0, len(reqWrapper.User.EIDs))
+	for _, eid := range reqWrapper.User.EIDs {
+		if p.allowed(eid.Source, bidder) {
+			eidsAllowed = append(eidsAllowed, eid)
+			continue
+		}
+		if removed == nil {
+			removed = make(map[string]int)
+		}
+		removed[eid.Source]++
+	}
+
+	if len(removed) == 0 {
+		return nil
+	}
+
+	// the user is shared with the other bidders, so it's copied rather than modified
+	user := *reqWrapper.User
+	if len(eidsAllowed) == 0 {
+		user.EIDs = nil
+	} else {
+		user.EIDs = eidsAllowed
+	}
+	reqWrapper.User = &user
+	return removed
+}
+
+func (p accountEIDPermissions) allowed(source, bidder string) bool {
+	permission, ok := p.permissions[source]
+	if !ok {
+		return true
+	}
+
+	if p.gppOptOut && permission.DenyGPPOptOut {
+		return false
+	}
+	if p.purposeConsents != nil {
+		for _, purpose := range permission.GDPRPurposes {
+			if !p.purposeConsents[purpose] {
+				return false
+			}
+		}
+	}
+	for _, permittedBidder := range permission.Bidders {
+		if permittedBidder == "*" || strings.EqualFold(permittedBidder, bidder) {
+			return true
+		}
+	}
+	return false
+}
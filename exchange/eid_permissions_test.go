@@ -0,0 +1,136 @@
+package exchange
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// consentPurpose2 consents to purpose 2 only
+	consentPurpose2 = "CPerMsAPerMsAAAAAAENCfCAAEAAAAAAAAAAAAAAAAAA"
+)
+
+func TestAccountEIDPermissionsRemoveUnpermittedEIDs(t *testing.T) {
+	permissions := []config.AccountEIDPermission{
+		{Source: "liveramp.com", Bidders: []string{"bidderA"}},
+		{Source: "id5-sync.com", Bidders: []string{"*"}, GDPRPurposes: []int{2}},
+		{Source: "uidapi.com", Bidders: []string{"*"}, GDPRPurposes: []int{2, 4}},
+		{Source: "criteo.com", Bidders: []string{"*"}, DenyGPPOptOut: true},
+	}
+	eids := []openrtb2.EID{{Source: "liveramp.com"}, {Source: "id5-sync.com"}, {Source: "uidapi.com"}, {Source: "criteo.com"}, {Source: "other.com"}}
+
+	testCases := []struct {
+		name            string
+		bidder          string
+		gdprEnforced    bool
+		consent         string
+		regs            *openrtb2.Regs
+		gpp             gpplib.GppContainer
+		expectedSources []string
+		expectedRemoved map[string]int
+	}{
+		{
+			name:            "bidder_permitted",
+			bidder:          "BidderA",
+			expectedSources: []string{"liveramp.com", "id5-sync.com", "uidapi.com", "criteo.com", "other.com"},
+		},
+		{
+			name:            "bidder_not_permitted",
+			bidder:          "bidderB",
+			expectedSources: []string{"id5-sync.com", "uidapi.com", "criteo.com", "other.com"},
+			expectedRemoved: map[string]int{"liveramp.com": 1},
+		},
+		{
+			name:            "gdpr_purposes_consented",
+			bidder:          "bidderA",
+			gdprEnforced:    true,
+			consent:         consentPurpose2,
+			expectedSources: []string{"liveramp.com", "id5-sync.com", "criteo.com", "other.com"},
+			expectedRemoved: map[string]int{"uidapi.com": 1},
+		},
+		{
+			name:            "gdpr_malformed_consent",
+			bidder:          "bidderA",
+			gdprEnforced:    true,
+			consent:         "malformed",
+			expectedSources: []string{"liveramp.com", "criteo.com", "other.com"},
+			expectedRemoved: map[string]int{"id5-sync.com": 1, "uidapi.com": 1},
+		},
+		{
+			name:            "gpp_opt_out",
+			bidder:          "bidderA",
+			regs:            &openrtb2.Regs{GPPSID: []int8{7}},
+			gpp:             newOptedOutGPP(t),
+			expectedSources: []string{"liveramp.com", "id5-sync.com", "uidapi.com", "other.com"},
+			expectedRemoved: map[string]int{"criteo.com": 1},
+		},
+		{
+			name:            "gpp_opt_out_not_applicable",
+			bidder:          "bidderA",
+			regs:            &openrtb2.Regs{GPPSID: []int8{8}},
+			gpp:             newOptedOutGPP(t),
+			expectedSources: []string{"liveramp.com", "id5-sync.com", "uidapi.com", "criteo.com", "other.com"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			user := &openrtb2.User{ID: "id", EIDs: eids}
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: user, Regs: test.regs}}
+
+			p := newAccountEIDPermissions(permissions, test.gdprEnforced, test.consent, req, test.gpp)
+			removed := p.removeUnpermittedEIDs(req, test.bidder)
+
+			assert.Equal(t, test.expectedRemoved, removed)
+			sources := make([]string, 0, len(req.User.EIDs))
+			for _, eid := range req.User.EIDs {
+				sources = append(sources, eid.Source)
+			}
+			assert.Equal(t, test.expectedSources, sources)
+			assert.Len(t, user.EIDs, len(eids), "the shared user must not be modified")
+			assert.Equal(t, "id", req.User.ID)
+		})
+	}
+}
+
+func TestAccountEIDPermissionsRemoveAllEIDs(t *testing.T) {
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "liveramp.com"}, {Source: "liveramp.com"}}}}}
+	p := newAccountEIDPermissions([]config.AccountEIDPermission{{Source: "liveramp.com"}}, false, "", req, gpplib.GppContainer{})
+
+	assert.Equal(t, map[string]int{"liveramp.com": 2}, p.removeUnpermittedEIDs(req, "bidderA"))
+	assert.Nil(t, req.User.EIDs)
+}
+
+func TestAccountEIDPermissionsNone(t *testing.T) {
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "liveramp.com"}}}}}
+	p := newAccountEIDPermissions(nil, true, "", req, gpplib.GppContainer{})
+
+	assert.Nil(t, p.removeUnpermittedEIDs(req, "bidderA"))
+	assert.Len(t, req.User.EIDs, 1)
+}
+
+func newOptedOutGPP(t *testing.T) gpplib.GppContainer {
+	section := uspnat.USPNAT{
+		SectionID: 7,
+		CoreSegment: uspnat.USPNATCoreSegment{
+			Version:                         1,
+			SaleOptOut:                      1,
+			SensitiveDataProcessing:         make([]byte, 12),
+			KnownChildSensitiveDataConsents: make([]byte, 2),
+		},
+		GPCSegment: sections.CommonUSGPCSegment{SubsectionType: 1},
+	}
+	gppString, err := gpplib.Encode([]gpplib.Section{section})
+	require.NoError(t, err)
+	gpp, errs := gpplib.Parse(gppString)
+	require.Empty(t, errs)
+	return gpp
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/prebid/prebid-server/v3/privacy"
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_responses"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/maputil"
@@ -84,6 +85,7 @@ type exchange struct {
 	priceFloorEnabled        bool
 	priceFloorFetcher        floors.FloorFetcher
 	singleFormatBidders      map[openrtb_ext.BidderName]struct{}
+	metricsCurrency          string
 }
 
 // Container to pass out response ext data from the GetAllBids goroutines back into the main thread
@@ -178,6 +180,7 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		priceFloorEnabled:        cfg.PriceFloors.Enabled,
 		priceFloorFetcher:        priceFloorFetcher,
 		singleFormatBidders:      singleFormatBidders,
+		metricsCurrency:          cfg.Metrics.Auction.Currency,
 	}
 }
 
@@ -273,11 +276,15 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	}
 
 	// Get currency rates conversions for the auction
+	_, conversionSpan := tracing.Start(ctx, tracing.SpanCurrencyConversion)
 	conversions := currency.GetAuctionCurrencyRates(e.currencyConverter, requestExtPrebid.CurrencyConversions)
+	conversionSpan.End()
 
 	var floorErrs []error
 	if e.priceFloorEnabled {
+		_, floorsSpan := tracing.Start(ctx, tracing.SpanFloorsResolution)
 		floorErrs = floors.EnrichWithPriceFloors(r.BidRequestWrapper, r.Account, conversions, e.priceFloorFetcher)
+		tracing.EndWithErrors(floorsSpan, floorErrs)
 	}
 
 	responseDebugAllow, accountDebugAllow, debugLog := getDebugInfo(r.BidRequestWrapper.Test, requestExtPrebid, r.Account.DebugAllow, debugLog)
@@ -513,6 +520,11 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		}
 	}
 
+	if len(r.StoredAuctionResponses) == 0 {
+		preferDeals := targData != nil && targData.preferDeals
+		e.recordAuctionMetrics(bidderRequests, r.BidRequestWrapper.Imp, adapterBids, auc, preferDeals, r.PubID, conversions)
+	}
+
 	if !accountDebugAllow && !debugLog.DebugOverride {
 		accountDebugDisabledWarning := openrtb_ext.ExtBidderMessage{
 			Code:    errortypes.AccountLevelDebugDisabledWarningCode,
@@ -740,10 +752,11 @@ func (e *exchange) getAllBids(
 
 	for _, bidder := range bidderRequests {
 		// Here we actually call the adapters and collect the bids.
-		bidderRunner := e.recoverSafely(bidderRequests, func(bidderRequest BidderRequest, conversions currency.Conversions) {
+		bidderRunner := e.recoverSafely(ctx, bidderRequests, func(bidderRequest BidderRequest, conversions currency.Conversions) {
+			bidderCtx := logger.WithFields(ctx, logger.FieldBidder, bidderRequest.BidderName.String())
 			// Passing in aName so a doesn't change out from under the go routine
 			if bidderRequest.BidderLabels.Adapter == "" {
-				logger.Errorf("Exchange: bidlables for %s (%s) missing adapter string", bidderRequest.BidderName, bidderRequest.BidderCoreName)
+				logger.WithContext(bidderCtx).Errorf("Exchange: bidlables for %s (%s) missing adapter string", bidderRequest.BidderName, bidderRequest.BidderCoreName)
 				bidderRequest.BidderLabels.Adapter = bidderRequest.BidderCoreName
 			}
 			brw := new(bidResponseWrapper)
@@ -774,7 +787,7 @@ func (e *exchange) getAllBids(
 				bidderRequestStartTime: start,
 				responseDebugAllowed:   responseDebugAllowed,
 			}
-			seatBids, extraBidderRespInfo, err := e.adapterMap[bidderRequest.BidderCoreName].requestBid(ctx, bidderRequest, conversions, &reqInfo, e.adsCertSigner, bidReqOptions, alternateBidderCodes, hookExecutor, bidAdjustmentRules)
+			seatBids, extraBidderRespInfo, err := e.adapterMap[bidderRequest.BidderCoreName].requestBid(bidderCtx, bidderRequest, conversions, &reqInfo, e.adsCertSigner, bidReqOptions, alternateBidderCodes, hookExecutor, bidAdjustmentRules)
 			brw.bidderResponseStartTime = extraBidderRespInfo.respProcessingStartTime
 
 			// Add in time reporting
@@ -788,7 +801,7 @@ func (e *exchange) getAllBids(
 				ae.HttpCalls = seatBids[0].HttpCalls
 			}
 			// Timing statistics
-			e.me.RecordAdapterTime(bidderRequest.BidderLabels, elapsed)
+			metrics.RecordAdapterTime(bidderCtx, e.me, bidderRequest.BidderLabels, elapsed)
 			bidderRequest.BidderLabels.AdapterBids = bidsToMetric(brw.adapterSeatBids)
 			bidderRequest.BidderLabels.AdapterErrors = errorsToMetric(err)
 			// Append any bid validation errors to the error list
@@ -861,7 +874,7 @@ func collectFledgeFromSeatBid(fledge *openrtb_ext.Fledge, bidderName openrtb_ext
 	return fledge
 }
 
-func (e *exchange) recoverSafely(bidderRequests []BidderRequest,
+func (e *exchange) recoverSafely(ctx context.Context, bidderRequests []BidderRequest,
 	inner func(BidderRequest, currency.Conversions),
 	chBids chan *bidResponseWrapper) func(BidderRequest, currency.Conversions) {
 	return func(bidderRequest BidderRequest, conversions currency.Conversions) {
@@ -878,7 +891,8 @@ func (e *exchange) recoverSafely(bidderRequests []BidderRequest,
 					allBidders = sb.String()[:sb.Len()-1]
 				}
 
-				logger.Errorf("OpenRTB auction recovered panic from Bidder %s: %v. "+
+				bidderCtx := logger.WithFields(ctx, logger.FieldBidder, bidderRequest.BidderName.String())
+				logger.WithContext(bidderCtx).Errorf("OpenRTB auction recovered panic from Bidder %s: %v. "+
 					"Account id: %s, All Bidders: %s, Stack trace is: %v",
 					bidderRequest.BidderCoreName, r, bidderRequest.BidderLabels.PubID, allBidders, string(debug.Stack()))
 				e.me.RecordAdapterPanic(bidderRequest.BidderLabels)
@@ -1973,7 +1973,7 @@ func TestPanicRecovery(t *testing.T) {
 		},
 	}
 
-	recovered := e.recoverSafely(bidderRequests, panicker, chBids)
+	recovered := e.recoverSafely(context.Background(), bidderRequests, panicker, chBids)
 	recovered(bidderRequests[0], nil)
 }
 
@@ -115,21 +115,16 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 
 	consent := gdpr.GetConsent(req, gpp)
 
-	ccpaEnforcer, err := extractCCPA(req.BidRequest, rs.privacyConfig, &auctionReq.Account, requestAliases, ChannelTypeMap[auctionReq.LegacyLabels.RType], gpp)
+	// request level privacy policies
+	bidderPrivacy, err := NewBidderPrivacy(req, gpp, consent, auctionReq.GDPREnforced, rs.privacyConfig, &auctionReq.Account, auctionReq.Activities, requestAliases, ChannelTypeMap[auctionReq.LegacyLabels.RType], auctionReq.FirstPartyData)
 	if err != nil {
 		errs = append(errs, err)
 	}
 
-	lmtEnforcer := extractLMT(req.BidRequest, rs.privacyConfig)
-
-	// request level privacy policies
-	coppa := req.BidRequest.Regs != nil && req.BidRequest.Regs.COPPA == 1
-	lmt := lmtEnforcer.ShouldEnforce(unknownBidder)
-
-	privacyLabels.CCPAProvided = ccpaEnforcer.CanEnforce()
-	privacyLabels.CCPAEnforced = ccpaEnforcer.ShouldEnforce(unknownBidder)
-	privacyLabels.COPPAEnforced = coppa
-	privacyLabels.LMTEnforced = lmt
+	privacyLabels.CCPAProvided = bidderPrivacy.CCPAEnforcer.CanEnforce()
+	privacyLabels.CCPAEnforced = bidderPrivacy.CCPAEnforcer.ShouldEnforce(unknownBidder)
+	privacyLabels.COPPAEnforced = bidderPrivacy.COPPA
+	privacyLabels.LMTEnforced = bidderPrivacy.LMT
 
 	var gdprPerms gdpr.Permissions = &gdpr.AlwaysAllow{}
 
@@ -153,7 +148,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 	bidderRequests = make([]BidderRequest, 0, len(impsByBidder))
 
 	for bidder, imps := range impsByBidder {
-		fpdUserEIDsPresent := fpdUserEIDExists(req, auctionReq.FirstPartyData, bidder)
+		fpdUserEIDsPresent := bidderPrivacy.FPDUserEIDsPresent(req, bidder)
 		reqWrapperCopy := req.CloneAndClearImpWrappers()
 		bidRequestCopy := *req.BidRequest
 		reqWrapperCopy.BidRequest = &bidRequestCopy
@@ -197,11 +192,8 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 			continue
 		}
 
-		// fpd
-		applyFPD(auctionReq.FirstPartyData, coreBidder, openrtb_ext.BidderName(bidder), isRequestAlias, reqWrapperCopy, fpdUserEIDsPresent)
-
-		// privacy scrubbing
-		if err := rs.applyPrivacy(reqWrapperCopy, coreBidder, bidder, auctionReq, auctionPermissions, ccpaEnforcer, lmt, coppa); err != nil {
+		// fpd, account eid permissions and privacy scrubbing
+		if err := bidderPrivacy.Apply(reqWrapperCopy, coreBidder, bidder, isRequestAlias, fpdUserEIDsPresent, auctionPermissions, rs.me, nil); err != nil {
 			errs = append(errs, err)
 			continue
 		}
@@ -363,63 +355,6 @@ func (rs *requestSplitter) isBidderBlockedByPrivacy(r *openrtb_ext.RequestWrappe
 	return false
 }
 
-func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper, coreBidderName openrtb_ext.BidderName, bidderName string, auctionReq AuctionRequest, auctionPermissions gdpr.AuctionPermissions, ccpaEnforcer privacy.PolicyEnforcer, lmt bool, coppa bool) error {
-	scope := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName}
-	ipConf := privacy.IPConf{IPV6: auctionReq.Account.Privacy.IPv6Config, IPV4: auctionReq.Account.Privacy.IPv4Config}
-
-	bidRequest := ortb.CloneBidRequestPartial(reqWrapper.BidRequest)
-	reqWrapper.BidRequest = bidRequest
-
-	passIDActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitUserFPD, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
-	buyerUIDSet := reqWrapper.User != nil && reqWrapper.User.BuyerUID != ""
-	buyerUIDRemoved := false
-	if !passIDActivityAllowed {
-		privacy.ScrubUserFPD(reqWrapper)
-		buyerUIDRemoved = true
-	} else {
-		if !auctionPermissions.PassID {
-			privacy.ScrubGdprID(reqWrapper)
-			buyerUIDRemoved = true
-		}
-
-		if ccpaEnforcer.ShouldEnforce(bidderName) {
-			privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false)
-			buyerUIDRemoved = true
-		}
-	}
-	if buyerUIDSet && buyerUIDRemoved {
-		rs.me.RecordAdapterBuyerUIDScrubbed(coreBidderName)
-	}
-
-	passGeoActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitPreciseGeo, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
-	if !passGeoActivityAllowed {
-		privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
-	} else {
-		if !auctionPermissions.PassGeo {
-			privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
-		}
-		if ccpaEnforcer.ShouldEnforce(bidderName) {
-			privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false)
-		}
-	}
-
-	if lmt || coppa {
-		privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", coppa)
-	}
-
-	passTIDAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitTIDs, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
-	if !passTIDAllowed {
-		privacy.ScrubTID(reqWrapper)
-	}
-
-	if err := reqWrapper.RebuildRequest(); err != nil {
-		return err
-	}
-
-	// *bidRequest = *reqWrapper.BidRequest
-	return nil
-}
-
 func shouldSetLegacyPrivacy(bidderInfo config.BidderInfos, bidder string) bool {
 	binfo, defined := bidderInfo[bidder]
 
@@ -5246,6 +5246,73 @@ func TestCleanOpenRTBRequestsActivities(t *testing.T) {
 			},
 			expectedSource: expectedSourceDefault,
 		},
+		{
+			name:              "transmit_precise_geo_deny_precision_policy",
+			req:               newBidRequest(),
+			privacyConfig:     getTransmitPreciseGeoPrecisionPolicyConfig("appnexus"),
+			ortbVersion:       "2.6",
+			expectedReqNumber: 1,
+			expectedUser: openrtb2.User{
+				ID:       "our-id",
+				BuyerUID: "their-id",
+				Yob:      1982,
+				Geo:      &openrtb2.Geo{Lat: ptrutil.ToPtr(123.5), Lon: ptrutil.ToPtr(11.3)},
+				Gender:   "test",
+				Ext:      json.RawMessage(`{"data": 1, "test": 2}`),
+				EIDs: []openrtb2.EID{
+					{Source: "eids-source"},
+				},
+				Data: []openrtb2.Data{{ID: "data-id"}},
+			},
+			expectedDevice: openrtb2.Device{
+				UA:       deviceUA,
+				IP:       "132.0.0.0",
+				Language: "EN",
+				DIDMD5:   "DIDMD5",
+				IFA:      "IFA",
+				DIDSHA1:  "DIDSHA1",
+				DPIDMD5:  "DPIDMD5",
+				DPIDSHA1: "DPIDSHA1",
+				MACMD5:   "MACMD5",
+				MACSHA1:  "MACSHA1",
+				Geo:      &openrtb2.Geo{Lat: ptrutil.ToPtr(123.5), Lon: ptrutil.ToPtr(11.3)},
+			},
+			expectedSource: expectedSourceDefault,
+		},
+		{
+			// the ids are removed rather than sent as is, since no pseudonymization key is active
+			name:              "pseudonymization_without_keys",
+			req:               newBidRequest(),
+			privacyConfig:     config.AccountPrivacy{Pseudonymization: config.AccountPseudonymization{Enabled: true, Bidders: []string{"appnexus"}, UserID: true, DeviceIFA: true}},
+			ortbVersion:       "2.6",
+			expectedReqNumber: 1,
+			expectedUser: openrtb2.User{
+				ID:       "",
+				BuyerUID: "their-id",
+				Yob:      1982,
+				Gender:   "test",
+				Ext:      json.RawMessage(`{"data": 1, "test": 2}`),
+				Geo:      &openrtb2.Geo{Lat: ptrutil.ToPtr(123.456), Lon: ptrutil.ToPtr(11.278)},
+				EIDs: []openrtb2.EID{
+					{Source: "eids-source"},
+				},
+				Data: []openrtb2.Data{{ID: "data-id"}},
+			},
+			expectedDevice: openrtb2.Device{
+				UA:       deviceUA,
+				IP:       "132.173.230.74",
+				Language: "EN",
+				DIDMD5:   "DIDMD5",
+				IFA:      "",
+				DIDSHA1:  "DIDSHA1",
+				DPIDMD5:  "DPIDMD5",
+				DPIDSHA1: "DPIDSHA1",
+				MACMD5:   "MACMD5",
+				MACSHA1:  "MACSHA1",
+				Geo:      &openrtb2.Geo{Lat: ptrutil.ToPtr(123.456), Lon: ptrutil.ToPtr(11.278)},
+			},
+			expectedSource: expectedSourceDefault,
+		},
 		{
 			name:              "transmit_tid_allowed",
 			req:               newBidRequest(),
@@ -5362,6 +5429,15 @@ func getTransmitPreciseGeoActivityConfig(componentName string, allow bool) confi
 	}
 }
 
+func getTransmitPreciseGeoPrecisionPolicyConfig(componentName string) config.AccountPrivacy {
+	privacyConfig := getTransmitPreciseGeoActivityConfig(componentName, false)
+	privacyConfig.AllowActivities.TransmitPreciseGeo.Rules[0].PrecisionPolicy = "coarse"
+	privacyConfig.PrecisionPolicies = map[string]config.PrecisionPolicy{
+		"coarse": {IPv4KeepBits: ptrutil.ToPtr(8), GeoDecimals: ptrutil.ToPtr(1)},
+	}
+	return privacyConfig
+}
+
 func getTransmitTIDActivityConfig(componentName string, allow bool) config.AccountPrivacy {
 	return config.AccountPrivacy{
 		AllowActivities: &config.AllowActivities{
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"math"
 	"net/http"
@@ -181,6 +182,15 @@ func (f *PriceFloorFetcher) worker(fetchConfig fetchInfo) {
 	}
 }
 
+// Ready returns an error if the queue of floors to fetch is full, as requests missing the floors cache then wait for
+// the queue to drain.
+func (f *PriceFloorFetcher) Ready(_ context.Context) error {
+	if queued, capacity := len(f.configReceiver), cap(f.configReceiver); queued >= capacity {
+		return fmt.Errorf("the floors fetch queue is full with %d of %d fetches", queued, capacity)
+	}
+	return nil
+}
+
 // Stop terminates price floor fetcher
 func (f *PriceFloorFetcher) Stop() {
 	if f == nil {
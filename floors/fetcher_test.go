@@ -1,6 +1,7 @@
 package floors
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -1305,3 +1306,14 @@ func TestFetchWhenPriceFloorsDisabled(t *testing.T) {
 	assert.Equal(t, (*openrtb_ext.PriceFloorRules)(nil), data, "floor data should be nil as fetcher instance does not created")
 	assert.Equal(t, openrtb_ext.FetchNone, status, "floor status should be none as fetcher instance does not created")
 }
+
+func TestPriceFloorFetcherReady(t *testing.T) {
+	fetcher := &PriceFloorFetcher{configReceiver: make(chan fetchInfo, 2)}
+	assert.NoError(t, fetcher.Ready(context.Background()))
+
+	fetcher.configReceiver <- fetchInfo{}
+	assert.NoError(t, fetcher.Ready(context.Background()))
+
+	fetcher.configReceiver <- fetchInfo{}
+	assert.EqualError(t, fetcher.Ready(context.Background()), "the floors fetch queue is full with 2 of 2 fetches")
+}
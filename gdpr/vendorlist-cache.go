@@ -0,0 +1,236 @@
+package gdpr
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prebid/go-gdpr/api"
+	"github.com/prebid/go-gdpr/vendorlist"
+	"github.com/prebid/go-gdpr/vendorlist2"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/metrics"
+)
+
+// Sources of the cached vendor lists
+const (
+	VendorListSourceNetwork  = "network"
+	VendorListSourceDisk     = "disk"
+	VendorListSourceSnapshot = "snapshot"
+)
+
+// VendorListVersions describes the cached vendor lists of a spec version
+type VendorListVersions struct {
+	SpecVersion  uint16   `json:"spec_version"`
+	ListVersions []uint16 `json:"list_versions"`
+	// Latest is the latest cached list version, which was fetched at LatestFetched from LatestSource. Lists loaded
+	// from disk were fetched when their file was written.
+	Latest        uint16    `json:"latest"`
+	LatestFetched time.Time `json:"latest_fetched"`
+	LatestSource  string    `json:"latest_source"`
+}
+
+// vendorListCache keeps the vendor lists in memory, and persists the fetched vendor lists to a directory if
+// configured, so they can be loaded at startup before any network fetch.
+type vendorListCache struct {
+	lists         sync.Map
+	mutex         sync.Mutex
+	versions      map[uint16]*VendorListVersions
+	dir           string
+	metricsEngine metrics.MetricsEngine
+}
+
+var vendorListFilePattern = regexp.MustCompile(`^vendor-list-v([0-9]+)\.json$`)
+
+func newVendorListCache(dir string, metricsEngine metrics.MetricsEngine) *vendorListCache {
+	return &vendorListCache{
+		versions:      make(map[uint16]*VendorListVersions),
+		dir:           dir,
+		metricsEngine: metricsEngine,
+	}
+}
+
+// save caches a vendor list fetched from the network, and persists it
+func (c *vendorListCache) save(specVersion, listVersion uint16, list api.VendorList, body []byte) {
+	c.store(specVersion, listVersion, list, time.Now(), VendorListSourceNetwork)
+
+	if c.dir == "" {
+		return
+	}
+	if err := writeVendorListFile(c.dir, specVersion, listVersion, body); err != nil {
+		logger.Errorf("Failed to persist the gdpr vendor list spec version %d list version %d: %v", specVersion, listVersion, err)
+	}
+}
+
+func (c *vendorListCache) load(specVersion, listVersion uint16) api.VendorList {
+	if list, ok := c.lists.Load(vendorListKey(specVersion, listVersion)); ok {
+		return list.(vendorlist.VendorList)
+	}
+	return nil
+}
+
+func (c *vendorListCache) store(specVersion, listVersion uint16, list api.VendorList, fetched time.Time, source string) {
+	c.lists.Store(vendorListKey(specVersion, listVersion), list)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	versions, ok := c.versions[specVersion]
+	if !ok {
+		versions = &VendorListVersions{SpecVersion: specVersion}
+		c.versions[specVersion] = versions
+	}
+	if i, found := slices.BinarySearch(versions.ListVersions, listVersion); !found {
+		versions.ListVersions = slices.Insert(versions.ListVersions, i, listVersion)
+	}
+	if listVersion >= versions.Latest {
+		versions.Latest = listVersion
+		versions.LatestFetched = fetched
+		versions.LatestSource = source
+		c.metricsEngine.RecordGvlListLatest(specVersion, listVersion, fetched)
+	}
+}
+
+// hasSpecVersion returns true if a vendor list of the spec version is cached
+func (c *vendorListCache) hasSpecVersion(specVersion uint16) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, ok := c.versions[specVersion]
+	return ok
+}
+
+// Versions returns the cached vendor lists of every spec version, ordered by spec version
+func (c *vendorListCache) Versions() []VendorListVersions {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	versions := make([]VendorListVersions, 0, len(c.versions))
+	for _, v := range c.versions {
+		copied := *v
+		copied.ListVersions = append([]uint16(nil), v.ListVersions...)
+		versions = append(versions, copied)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].SpecVersion < versions[j].SpecVersion })
+	return versions
+}
+
+// loadDir caches the vendor lists of dir, which follows the layout of the vendor list URLs, like
+// "v3/vendor-list-v42.json". A missing dir has no vendor lists. It returns the number of vendor lists loaded.
+func (c *vendorListCache) loadDir(dir, source string) int {
+	if dir == "" {
+		return 0
+	}
+
+	specDirs, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Errorf("Failed to read the gdpr vendor lists of %s: %v", dir, err)
+		}
+		return 0
+	}
+
+	loaded := 0
+	for _, specDir := range specDirs {
+		specVersion, ok := parseVersion(specDir.Name(), "v")
+		if !ok || !specDir.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(dir, specDir.Name()))
+		if err != nil {
+			logger.Errorf("Failed to read the gdpr vendor lists of %s: %v", filepath.Join(dir, specDir.Name()), err)
+			continue
+		}
+		for _, file := range files {
+			if c.loadFile(filepath.Join(dir, specDir.Name()), file, specVersion, source) {
+				loaded++
+			}
+		}
+	}
+	return loaded
+}
+
+func (c *vendorListCache) loadFile(dir string, file fs.DirEntry, specVersion uint16, source string) bool {
+	match := vendorListFilePattern.FindStringSubmatch(file.Name())
+	if match == nil || file.IsDir() {
+		return false
+	}
+	listVersion, ok := parseVersion(match[1], "")
+	if !ok || c.load(specVersion, listVersion) != nil {
+		return false
+	}
+
+	path := filepath.Join(dir, file.Name())
+	info, err := file.Info()
+	if err != nil {
+		logger.Errorf("Failed to read the gdpr vendor list %s: %v", path, err)
+		return false
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		logger.Errorf("Failed to read the gdpr vendor list %s: %v", path, err)
+		return false
+	}
+	list, err := vendorlist2.ParseEagerly(body)
+	if err != nil {
+		logger.Errorf("The gdpr vendor list %s is malformed: %v", path, err)
+		return false
+	}
+	if list.SpecVersion() != specVersion || list.Version() != listVersion {
+		logger.Errorf("The gdpr vendor list %s has spec version %d list version %d", path, list.SpecVersion(), list.Version())
+		return false
+	}
+
+	c.store(specVersion, listVersion, list, info.ModTime(), source)
+	return true
+}
+
+// writeVendorListFile writes the vendor list to a temporary file renamed once complete, so a crash never leaves a
+// partial vendor list behind
+func writeVendorListFile(dir string, specVersion, listVersion uint16, body []byte) error {
+	path := vendorListFile(dir, specVersion, listVersion)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func vendorListFile(dir string, specVersion, listVersion uint16) string {
+	return filepath.Join(dir, "v"+strconv.Itoa(int(specVersion)), "vendor-list-v"+strconv.Itoa(int(listVersion))+".json")
+}
+
+func vendorListKey(specVersion, listVersion uint16) string {
+	return fmt.Sprint(specVersion) + "-" + fmt.Sprint(listVersion)
+}
+
+func parseVersion(name, prefix string) (uint16, bool) {
+	if len(name) <= len(prefix) || name[:len(prefix)] != prefix {
+		return 0, false
+	}
+	version, err := strconv.ParseUint(name[len(prefix):], 10, 16)
+	if err != nil || version == 0 {
+		return 0, false
+	}
+	return uint16(version), true
+}
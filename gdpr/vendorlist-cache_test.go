@@ -0,0 +1,141 @@
+package gdpr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVendorListCachePersistence(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
+		vendorListLatestVersion: 2,
+		vendorLists: map[int]map[int]string{
+			3: {1: vendorList1, 2: vendorList2},
+		},
+	})))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.VendorListCache.Dir = dir
+	m := &metrics.MetricsEngineMock{}
+	m.On("RecordGvlListRequest")
+	m.On("RecordGvlListLatest", mock.Anything, mock.Anything, mock.Anything)
+
+	_, status := NewVendorListFetcherWithStatus(context.Background(), cfg, server.Client(), m, testURLMaker(server))
+	assert.FileExists(t, filepath.Join(dir, "v3", "vendor-list-v2.json"))
+	versions := status.Versions()
+	require.Len(t, versions, 1)
+	assert.Equal(t, VendorListSourceNetwork, versions[0].LatestSource)
+
+	// The vendor list host is down after a restart
+	server.Close()
+	fetcher, status := NewVendorListFetcherWithStatus(context.Background(), cfg, server.Client(), m, testURLMaker(server))
+
+	list, err := fetcher(context.Background(), 3, 2, m)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(2), list.Version())
+	versions = status.Versions()
+	require.Len(t, versions, 1)
+	assert.Equal(t, uint16(3), versions[0].SpecVersion)
+	assert.Equal(t, []uint16{1, 2}, versions[0].ListVersions)
+	assert.Equal(t, VendorListSourceDisk, versions[0].LatestSource)
+	assert.NotContains(t, status.missing, uint16(3))
+}
+
+func TestVendorListCacheLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "v3", "vendor-list-v1.json"), vendorList1)
+	writeTestFile(t, filepath.Join(dir, "v3", "vendor-list-v2.json"), vendorList2)
+	writeTestFile(t, filepath.Join(dir, "v3", "vendor-list-v3.json"), "malformed")
+	writeTestFile(t, filepath.Join(dir, "v3", "vendor-list-v4.json"), vendorList2) // Mismatched list version
+	writeTestFile(t, filepath.Join(dir, "v2", "vendor-list-v2.json"), vendorList2) // Mismatched spec version
+	writeTestFile(t, filepath.Join(dir, "v3", "notes.txt"), "ignored")
+	writeTestFile(t, filepath.Join(dir, "other", "vendor-list-v1.json"), vendorList1)
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "v3", "vendor-list-v2.json"), modTime, modTime))
+
+	m := &metrics.MetricsEngineMock{}
+	m.On("RecordGvlListLatest", mock.Anything, mock.Anything, mock.Anything)
+	cache := newVendorListCache("", m)
+
+	assert.Equal(t, 2, cache.loadDir(dir, VendorListSourceSnapshot))
+	assert.NotNil(t, cache.load(3, 1))
+	assert.NotNil(t, cache.load(3, 2))
+	assert.Nil(t, cache.load(3, 3))
+	assert.Nil(t, cache.load(3, 4))
+	assert.Nil(t, cache.load(2, 2))
+	assert.True(t, cache.hasSpecVersion(3))
+	assert.False(t, cache.hasSpecVersion(2))
+	assert.Equal(t, []VendorListVersions{
+		{SpecVersion: 3, ListVersions: []uint16{1, 2}, Latest: 2, LatestFetched: modTime.Local(), LatestSource: VendorListSourceSnapshot},
+	}, cache.Versions())
+	m.AssertCalled(t, "RecordGvlListLatest", uint16(3), uint16(2), modTime.Local())
+
+	// The vendor lists already cached are not loaded again
+	assert.Equal(t, 0, cache.loadDir(dir, VendorListSourceDisk))
+}
+
+// TestVendorListCacheLoadSnapshot checks that every vendor list of the snapshot committed to static/vendorlist is
+// loaded at startup, i.e. that none is malformed or misplaced
+func TestVendorListCacheLoadSnapshot(t *testing.T) {
+	const snapshotDir = "../static/vendorlist"
+	files, err := filepath.Glob(filepath.Join(snapshotDir, "v*", "vendor-list-v*.json"))
+	require.NoError(t, err)
+	if len(files) == 0 {
+		t.Skip("The snapshot has no vendor lists. Run scripts/update_vendorlist_snapshot.sh to download them.")
+	}
+
+	m := &metrics.MetricsEngineMock{}
+	m.On("RecordGvlListLatest", mock.Anything, mock.Anything, mock.Anything)
+	cache := newVendorListCache("", m)
+
+	assert.Equal(t, len(files), cache.loadDir(snapshotDir, VendorListSourceSnapshot), "every vendor list of the snapshot must load")
+	for _, versions := range cache.Versions() {
+		assert.Equal(t, VendorListSourceSnapshot, versions.LatestSource)
+	}
+}
+
+func TestVendorListCacheLoadMissingDir(t *testing.T) {
+	cache := newVendorListCache("", &metrics.MetricsEngineMock{})
+
+	assert.Equal(t, 0, cache.loadDir("", VendorListSourceDisk))
+	assert.Equal(t, 0, cache.loadDir(filepath.Join(t.TempDir(), "missing"), VendorListSourceDisk))
+	assert.Empty(t, cache.Versions())
+}
+
+func TestVendorListCacheVersions(t *testing.T) {
+	m := &metrics.MetricsEngineMock{}
+	m.On("RecordGvlListLatest", mock.Anything, mock.Anything, mock.Anything)
+	cache := newVendorListCache("", m)
+	first := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+
+	cache.store(3, 5, nil, first, VendorListSourceDisk)
+	cache.store(2, 1, nil, first, VendorListSourceSnapshot)
+	cache.store(3, 3, nil, second, VendorListSourceNetwork)
+
+	versions := cache.Versions()
+	assert.Equal(t, []VendorListVersions{
+		{SpecVersion: 2, ListVersions: []uint16{1}, Latest: 1, LatestFetched: first, LatestSource: VendorListSourceSnapshot},
+		{SpecVersion: 3, ListVersions: []uint16{3, 5}, Latest: 5, LatestFetched: first, LatestSource: VendorListSourceDisk},
+	}, versions)
+
+	// The versions returned are copies
+	versions[1].ListVersions[0] = 4
+	assert.Equal(t, []uint16{3, 5}, cache.Versions()[1].ListVersions)
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -19,7 +20,8 @@ import (
 	"golang.org/x/net/context/ctxhttp"
 )
 
-type saveVendors func(uint16, uint16, api.VendorList)
+type saveVendors func(specVersion, listVersion uint16, list api.VendorList, body []byte)
+type loadVendors func(specVersion, listVersion uint16) api.VendorList
 type VendorListFetcher func(ctx context.Context, specVersion uint16, listVersion uint16, metricsEngine metrics.MetricsEngine) (vendorlist.VendorList, error)
 
 // This file provides the vendorlist-fetching function for Prebid Server.
@@ -29,11 +31,39 @@ type VendorListFetcher func(ctx context.Context, specVersion uint16, listVersion
 // Nothing in this file is exported. Public APIs can be found in gdpr.go
 
 func NewVendorListFetcher(initCtx context.Context, cfg config.GDPR, client *http.Client, metricsEngine metrics.MetricsEngine, urlMaker func(uint16, uint16) string) VendorListFetcher {
-	cacheSave, cacheLoad := newVendorListCache()
+	fetcher, _ := NewVendorListFetcherWithStatus(initCtx, cfg, client, metricsEngine, urlMaker)
+	return fetcher
+}
+
+// NewVendorListFetcherWithStatus returns a vendor list fetcher, along with the status of the preloaded vendor lists.
+func NewVendorListFetcherWithStatus(initCtx context.Context, cfg config.GDPR, client *http.Client, metricsEngine metrics.MetricsEngine, urlMaker func(uint16, uint16) string) (VendorListFetcher, *VendorListStatus) {
+	cache := newVendorListCache(cfg.VendorListCache.Dir, metricsEngine)
+	cacheSave, cacheLoad := cache.save, cache.load
+
+	// The vendor lists on disk are loaded first, so TCF can be enforced even if the vendor lists can't be fetched
+	if loaded := cache.loadDir(cfg.VendorListCache.Dir, VendorListSourceDisk); loaded > 0 {
+		logger.Infof("Loaded %d gdpr vendor lists from %s", loaded, cfg.VendorListCache.Dir)
+	}
+	if loaded := cache.loadDir(cfg.VendorListCache.SnapshotDir, VendorListSourceSnapshot); loaded > 0 {
+		logger.Infof("Loaded %d gdpr vendor lists from %s", loaded, cfg.VendorListCache.SnapshotDir)
+	}
 
 	preloadContext, cancel := context.WithTimeout(initCtx, cfg.Timeouts.InitTimeout())
 	defer cancel()
-	preloadCache(preloadContext, client, urlMaker, cacheSave, metricsEngine)
+	missing := preloadCache(preloadContext, client, urlMaker, cacheSave, cacheLoad, metricsEngine)
+	// The spec versions whose vendor lists were loaded from disk are usable, even if the latest failed to load
+	missing = slices.DeleteFunc(missing, cache.hasSpecVersion)
+
+	status := &VendorListStatus{
+		cache:   cache,
+		missing: missing,
+		retry: func(specVersion uint16) bool {
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeouts.ActiveTimeout())
+			defer cancel()
+			return saveOne(ctx, client, urlMaker(specVersion, 0), cacheSave, metricsEngine) != 0
+		},
+		retryInterval: vendorListRetryInterval,
+	}
 
 	saveOneRateLimited := newOccasionalSaver(cfg.Timeouts.ActiveTimeout())
 	return func(ctx context.Context, specVersion, listVersion uint16, metricsEngine metrics.MetricsEngine) (vendorlist.VendorList, error) {
@@ -54,15 +84,67 @@ func NewVendorListFetcher(initCtx context.Context, cfg config.GDPR, client *http
 
 		// Give Up
 		return nil, makeVendorListNotFoundError(specVersion, listVersion)
+	}, status
+}
+
+// vendorListRetryInterval is the minimum time between two retries of the vendor lists which failed to preload
+const vendorListRetryInterval = time.Minute
+
+// VendorListStatus reports whether the latest vendor list of every spec version was loaded, and which vendor lists
+// are cached.
+type VendorListStatus struct {
+	cache         *vendorListCache
+	mutex         sync.Mutex
+	missing       []uint16
+	retrying      bool
+	lastRetry     time.Time
+	retry         func(specVersion uint16) bool
+	retryInterval time.Duration
+}
+
+// Ready returns an error if the latest vendor list of a spec version failed to load. The missing vendor lists are
+// fetched again in the background, at most once per retry interval, until they load.
+func (s *VendorListStatus) Ready(_ context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.missing) == 0 {
+		return nil
+	}
+	if !s.retrying && time.Since(s.lastRetry) >= s.retryInterval {
+		s.retrying = true
+		s.lastRetry = time.Now()
+		go s.retryMissing(append([]uint16(nil), s.missing...))
 	}
+	return fmt.Errorf("the latest gdpr vendor lists of spec versions %v failed to load", s.missing)
+}
+
+// Versions returns the cached vendor lists of every spec version
+func (s *VendorListStatus) Versions() []VendorListVersions {
+	return s.cache.Versions()
+}
+
+func (s *VendorListStatus) retryMissing(specVersions []uint16) {
+	var missing []uint16
+	for _, specVersion := range specVersions {
+		if !s.retry(specVersion) {
+			missing = append(missing, specVersion)
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.missing = missing
+	s.retrying = false
 }
 
 func makeVendorListNotFoundError(specVersion, listVersion uint16) error {
 	return fmt.Errorf("gdpr vendor list spec version %d list version %d does not exist, or has not been loaded yet. Try again in a few minutes", specVersion, listVersion)
 }
 
-// preloadCache saves all the known versions of the vendor list for future use.
-func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, saver saveVendors, metricsEngine metrics.MetricsEngine) {
+// preloadCache saves all the known versions of the vendor list for future use, skipping the versions already
+// loaded. It returns the spec versions whose latest vendor list failed to load.
+func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, saver saveVendors, loader loadVendors, metricsEngine metrics.MetricsEngine) (missing []uint16) {
 	versions := [2]struct {
 		specVersion      uint16
 		firstListVersion uint16
@@ -78,11 +160,17 @@ func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16
 	}
 	for _, v := range versions {
 		latestVersion := saveOne(ctx, client, urlMaker(v.specVersion, 0), saver, metricsEngine)
+		if latestVersion == 0 {
+			missing = append(missing, v.specVersion)
+		}
 
 		for i := v.firstListVersion; i < latestVersion; i++ {
-			saveOne(ctx, client, urlMaker(v.specVersion, i), saver, metricsEngine)
+			if loader(v.specVersion, i) == nil {
+				saveOne(ctx, client, urlMaker(v.specVersion, i), saver, metricsEngine)
+			}
 		}
 	}
+	return
 }
 
 // Make a URL which can be used to fetch a given version of the Global Vendor List. If the version is 0,
@@ -149,25 +237,6 @@ func saveOne(ctx context.Context, client *http.Client, url string, saver saveVen
 		return 0
 	}
 
-	saver(newList.SpecVersion(), newList.Version(), newList)
+	saver(newList.SpecVersion(), newList.Version(), newList, respBody)
 	return newList.Version()
 }
-
-func newVendorListCache() (save func(specVersion, listVersion uint16, list api.VendorList), load func(specVersion, listVersion uint16) api.VendorList) {
-	cache := &sync.Map{}
-
-	save = func(specVersion uint16, listVersion uint16, list api.VendorList) {
-		key := fmt.Sprint(specVersion) + "-" + fmt.Sprint(listVersion)
-		cache.Store(key, list)
-	}
-
-	load = func(specVersion, listVersion uint16) api.VendorList {
-		key := fmt.Sprint(specVersion) + "-" + fmt.Sprint(listVersion)
-		list, ok := cache.Load(key)
-		if ok {
-			return list.(vendorlist.VendorList)
-		}
-		return nil
-	}
-	return
-}
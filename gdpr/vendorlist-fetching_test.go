@@ -5,9 +5,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/prebid/go-gdpr/api"
 	"github.com/prebid/go-gdpr/consentconstants"
@@ -98,6 +101,7 @@ func TestFetcherThrottling(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(3)
+	m.On("RecordGvlListLatest", mock.Anything, mock.Anything, mock.Anything)
 	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
 
 	// Dynamically Load List 2 Successfully
@@ -123,6 +127,7 @@ func TestMalformedVendorlist(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(3)
+	m.On("RecordGvlListLatest", mock.Anything, mock.Anything, mock.Anything)
 	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
 	_, err := fetcher(context.Background(), 3, 1, m)
 
@@ -138,6 +143,7 @@ func TestServerUrlInvalid(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(2)
+	m.On("RecordGvlListLatest", mock.Anything, mock.Anything, mock.Anything)
 	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, invalidURLGenerator)
 	_, err := fetcher(context.Background(), 3, 1, m)
 
@@ -150,6 +156,7 @@ func TestServerUnavailable(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(2)
+	m.On("RecordGvlListLatest", mock.Anything, mock.Anything, mock.Anything)
 	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
 	_, err := fetcher(context.Background(), 3, 1, m)
 
@@ -201,7 +208,11 @@ type versionInfo struct {
 }
 type saver []versionInfo
 
-func (s *saver) saveVendorLists(specVersion uint16, listVersion uint16, gvl api.VendorList) {
+func noVendorLists(uint16, uint16) api.VendorList {
+	return nil
+}
+
+func (s *saver) saveVendorLists(specVersion uint16, listVersion uint16, gvl api.VendorList, body []byte) {
 	vi := versionInfo{
 		specVersion: specVersion,
 		listVersion: listVersion,
@@ -264,7 +275,7 @@ func TestPreloadCache(t *testing.T) {
 	s := make(saver, 0, 5)
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(5)
-	preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists, m)
+	missing := preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists, noVendorLists, m)
 
 	expectedLoadedVersions := []versionInfo{
 		{specVersion: 2, listVersion: 2},
@@ -275,6 +286,41 @@ func TestPreloadCache(t *testing.T) {
 	}
 
 	assert.ElementsMatch(t, expectedLoadedVersions, s)
+	assert.Empty(t, missing)
+}
+
+func TestVendorListStatus(t *testing.T) {
+	handler := mockServer(serverSettings{
+		vendorListLatestVersion: 1,
+		vendorLists: map[int]map[int]string{
+			2: {1: MarshalVendorList(vendorList{GVLSpecificationVersion: 2, VendorListVersion: 1})},
+			3: {1: vendorList1},
+		},
+	})
+	var specVersion2Available atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("specversion") == "2" && !specVersion2Available.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler(w, req)
+	}))
+	defer server.Close()
+
+	m := &metrics.MetricsEngineMock{}
+	m.On("RecordGvlListRequest")
+	m.On("RecordGvlListLatest", mock.Anything, mock.Anything, mock.Anything)
+	_, status := NewVendorListFetcherWithStatus(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
+	status.retryInterval = 0
+
+	// The spec version 2 vendor list failed to preload
+	assert.EqualError(t, status.Ready(context.Background()), "the latest gdpr vendor lists of spec versions [2] failed to load")
+
+	// Until it's available, and loaded by a retry
+	specVersion2Available.Store(true)
+	assert.Eventually(t, func() bool {
+		return status.Ready(context.Background()) == nil
+	}, time.Second, 10*time.Millisecond)
 }
 
 var vendorList1 = MarshalVendorList(vendorList{
@@ -393,6 +439,7 @@ func runTest(t *testing.T, test test, server *httptest.Server) {
 	config := testConfig()
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(3)
+	m.On("RecordGvlListLatest", mock.Anything, mock.Anything, mock.Anything)
 	fetcher := NewVendorListFetcher(context.Background(), config, server.Client(), m, testURLMaker(server))
 	vendorList, err := fetcher(context.Background(), test.setup.specVersion, test.setup.listVersion, m)
 
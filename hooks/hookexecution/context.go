@@ -1,12 +1,17 @@
 package hookexecution
 
 import (
+	"context"
+	"slices"
 	"sync"
 
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 // executionContext holds information passed to module's hook during hook execution.
@@ -17,6 +22,9 @@ type executionContext struct {
 	account         *config.Account
 	moduleContexts  *moduleContexts
 	activityControl privacy.ActivityControl
+	logFields       []any
+	// spanCtx carries the parent of the spans of the hooks, which is nil before the entrypoint stage
+	spanCtx context.Context
 }
 
 func (ctx executionContext) getModuleContext(moduleName string) hookstage.ModuleInvocationContext {
@@ -30,7 +38,7 @@ func (ctx executionContext) getModuleContext(moduleName string) hookstage.Module
 	if ctx.account != nil {
 		cfg, err := ctx.account.Hooks.Modules.ModuleConfig(moduleName)
 		if err != nil {
-			logger.Warnf("Failed to get account config for %s module: %s", moduleName, err)
+			ctx.logger(moduleName).Warnf("Failed to get account config for %s module: %s", moduleName, err)
 		}
 
 		moduleInvocationCtx.AccountID = ctx.accountID
@@ -40,6 +48,24 @@ func (ctx executionContext) getModuleContext(moduleName string) hookstage.Module
 	return moduleInvocationCtx
 }
 
+// logger returns the logger adding the fields of the request, its account and the module to the messages
+func (ctx executionContext) logger(moduleName string) logger.Logger {
+	fields := append(slices.Clip(ctx.logFields), logger.FieldModule, moduleName)
+	if ctx.accountID != "" {
+		fields = append(fields, logger.FieldAccountID, ctx.accountID)
+	}
+	return logger.With(fields...)
+}
+
+// startSpan starts a span as a child of the span of the request, if any
+func (ctx executionContext) startSpan(name string, attrs ...attribute.KeyValue) (context.Context, oteltrace.Span) {
+	parent := ctx.spanCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+	return tracing.Start(parent, name, attrs...)
+}
+
 // moduleContexts preserves data the module wants to pass to itself from earlier stages to later stages.
 type moduleContexts struct {
 	sync.RWMutex
@@ -11,11 +11,11 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/hooks"
 	"github.com/prebid/prebid-server/v3/hooks/hookstage"
-	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/util/iputil"
 )
 
@@ -40,6 +40,13 @@ func executeStage[H any, P any](
 	hookHandler hookHandler[H, P],
 	metricEngine metrics.MetricsEngine,
 ) (StageOutcome, P, stageModuleContext, *RejectError) {
+	spanCtx, span := executionCtx.startSpan(tracing.SpanHookStage,
+		tracing.AttributeStage.String(executionCtx.stage),
+		tracing.AttributeEndpoint.String(executionCtx.endpoint),
+	)
+	defer span.End()
+	executionCtx.spanCtx = spanCtx
+
 	stageOutcome := StageOutcome{}
 	stageOutcome.Groups = make([]GroupOutcome, 0, len(plan))
 	stageModuleCtx := stageModuleContext{}
@@ -78,7 +85,7 @@ func executeGroup[H any, P any](
 		wg.Add(1)
 		go func(hw hooks.HookWrapper[H], moduleCtx hookstage.ModuleInvocationContext) {
 			defer wg.Done()
-			executeHook(moduleCtx, hw, newPayload, hookHandler, group.Timeout, resp, rejected)
+			executeHook(executionCtx, moduleCtx, hw, newPayload, hookHandler, group.Timeout, resp, rejected)
 		}(hook, mCtx)
 	}
 
@@ -93,6 +100,7 @@ func executeGroup[H any, P any](
 }
 
 func executeHook[H any, P any](
+	executionCtx executionContext,
 	moduleCtx hookstage.ModuleInvocationContext,
 	hw hooks.HookWrapper[H],
 	payload P,
@@ -104,16 +112,22 @@ func executeHook[H any, P any](
 	hookRespCh := make(chan hookResponse[P], 1)
 	startTime := time.Now()
 	hookId := HookID{ModuleCode: hw.Module, HookImplCode: hw.Code}
+	hookLogger := executionCtx.logger(hw.Module)
+	spanCtx, span := executionCtx.startSpan(tracing.SpanHook,
+		tracing.AttributeModule.String(hw.Module),
+		tracing.AttributeHook.String(hw.Code),
+	)
 
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
-				logger.Errorf("OpenRTB auction recovered panic in module hook %s.%s: %v, Stack trace is: %v",
+				hookLogger.Errorf("OpenRTB auction recovered panic in module hook %s.%s: %v, Stack trace is: %v",
 					hw.Module, hw.Code, r, string(debug.Stack()))
 			}
 		}()
 
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		// The span is passed to the hook, so the module can add its own spans to the trace
+		ctx, cancel := context.WithTimeout(tracing.ContextWithSpanFrom(context.Background(), spanCtx), timeout)
 		defer cancel()
 		result, err := hookHandler(ctx, moduleCtx, hw.Hook, payload)
 		hookRespCh <- hookResponse[P]{
@@ -124,10 +138,12 @@ func executeHook[H any, P any](
 
 	select {
 	case res := <-hookRespCh:
+		tracing.End(span, res.Err)
 		res.HookID = hookId
 		res.ExecutionTime = time.Since(startTime)
 		resp <- res
 	case <-time.After(timeout):
+		tracing.End(span, TimeoutError{})
 		resp <- hookResponse[P]{
 			Err:           TimeoutError{},
 			ExecutionTime: time.Since(startTime),
@@ -135,6 +151,7 @@ func executeHook[H any, P any](
 			Result:        hookstage.HookResult[P]{},
 		}
 	case <-rejected:
+		span.End()
 		return
 	}
 }
@@ -361,6 +378,7 @@ func handleModuleActivities[P any](hookCode string, activityControl privacy.Acti
 				IPV6: config.IPv6{AnonKeepBits: iputil.IPv6DefaultMaskingBitSize},
 				IPV4: config.IPv4{AnonKeepBits: iputil.IPv4DefaultMaskingBitSize}}
 		}
+		ipConf.Precision = activityControl.GeoPrecision(scopeGeneral, privacy.ActivityRequest{})
 
 		privacy.ScrubGeoAndDeviceIP(bidderReqCopy, ipConf)
 	}
@@ -11,9 +11,11 @@ import (
 	"github.com/prebid/prebid-server/v3/exchange/entities"
 	"github.com/prebid/prebid-server/v3/hooks"
 	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/tracing"
 )
 
 const (
@@ -59,6 +61,10 @@ type hookExecutor struct {
 	moduleContexts  *moduleContexts
 	metricEngine    metrics.MetricsEngine
 	activityControl privacy.ActivityControl
+	// logFields are the fields of the request added to the logs about the hooks
+	logFields []any
+	// spanCtx carries the span of the request, parent of the spans of the stages
+	spanCtx context.Context
 	// Mutex needed for BidderRequest and RawBidderResponse Stages as they are run in several goroutines
 	sync.Mutex
 }
@@ -91,6 +97,9 @@ func (e *hookExecutor) GetOutcomes() []StageOutcome {
 }
 
 func (e *hookExecutor) ExecuteEntrypointStage(req *http.Request, body []byte) ([]byte, *RejectError) {
+	e.logFields = logger.Fields(req.Context())
+	e.spanCtx = tracing.ContextWithSpanFrom(context.Background(), req.Context())
+
 	plan := e.planBuilder.PlanForEntrypointStage(e.endpoint)
 	if len(plan) == 0 {
 		return body, nil
@@ -334,6 +343,8 @@ func (e *hookExecutor) newContext(stage string) executionContext {
 		moduleContexts:  e.moduleContexts,
 		stage:           stage,
 		activityControl: e.activityControl,
+		logFields:       e.logFields,
+		spanCtx:         e.spanCtx,
 	}
 }
 
@@ -16,6 +16,7 @@ import (
 	"github.com/prebid/prebid-server/v3/hooks"
 	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
 	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/metrics"
 	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
@@ -3085,3 +3086,16 @@ func (e TestMultipleHooksUpdatePayloadBuilder) PlanForExitpointStage(_ string, _
 		},
 	}
 }
+
+func TestExecutorKeepsRequestLogFields(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	req = req.WithContext(logger.WithFields(req.Context(), logger.FieldRequestID, "request-id"))
+
+	exec := NewHookExecutor(hooks.EmptyPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	exec.ExecuteEntrypointStage(req, nil)
+	exec.SetAccount(&config.Account{ID: "account-id"})
+
+	executionCtx := exec.newContext(hooks.StageRawAuctionRequest.String())
+	assert.Equal(t, []any{logger.FieldRequestID, "request-id"}, executionCtx.logFields)
+	assert.Equal(t, "account-id", executionCtx.accountID)
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Keys of the fields carried by the contexts of the requests, to correlate their logs
+const (
+	FieldRequestID = "request_id"
+	FieldAccountID = "account_id"
+	FieldEndpoint  = "endpoint"
+	FieldBidder    = "bidder"
+	FieldModule    = "module"
+)
+
+type fieldsKey struct{}
+
+// WithFields returns a copy of ctx carrying the key/value pairs, in addition to the fields already carried by ctx.
+// The keys are strings, and the values anything which can be logged.
+func WithFields(ctx context.Context, keyvals ...any) context.Context {
+	if len(keyvals) == 0 {
+		return ctx
+	}
+	parent := Fields(ctx)
+	fields := make([]any, 0, len(parent)+len(keyvals))
+	fields = append(fields, parent...)
+	fields = append(fields, keyvals...)
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// Fields returns the key/value pairs carried by ctx
+func Fields(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]any)
+	return fields
+}
+
+// WithContext returns the logger adding the fields carried by ctx to the messages
+func WithContext(ctx context.Context) Logger {
+	return With(Fields(ctx)...)
+}
+
+// With returns the logger adding the key/value pairs to the messages. The pairs are added to the messages as
+// "key=value" if the logger doesn't support fields.
+func With(keyvals ...any) Logger {
+	if fieldLogger, ok := logger.(FieldLogger); ok {
+		return fieldLogger.With(keyvals...)
+	}
+	if len(keyvals) == 0 {
+		return logger
+	}
+	return &prefixLogger{logger: logger, prefix: formatFields(keyvals)}
+}
+
+// formatFields formats the key/value pairs as "[key=value key=value] ", to prefix format strings. Any % of the pairs
+// is escaped, so it's not taken as a verb.
+func formatFields(keyvals []any) string {
+	if len(keyvals) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := 0; i < len(keyvals); i += 2 {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		if i+1 < len(keyvals) {
+			fmt.Fprintf(&sb, "%v=%v", keyvals[i], keyvals[i+1])
+		} else {
+			fmt.Fprintf(&sb, "%v", keyvals[i])
+		}
+	}
+	sb.WriteString("] ")
+	return strings.ReplaceAll(sb.String(), "%", "%%")
+}
+
+// prefixLogger adds the fields as a prefix of the messages, for the loggers which don't support fields
+type prefixLogger struct {
+	logger Logger
+	prefix string
+}
+
+func (l *prefixLogger) Debugf(msg string, args ...any) {
+	l.logger.Debugf(l.prefix+msg, args...)
+}
+
+func (l *prefixLogger) Infof(msg string, args ...any) {
+	l.logger.Infof(l.prefix+msg, args...)
+}
+
+func (l *prefixLogger) Warnf(msg string, args ...any) {
+	l.logger.Warnf(l.prefix+msg, args...)
+}
+
+func (l *prefixLogger) Errorf(msg string, args ...any) {
+	l.logger.Errorf(l.prefix+msg, args...)
+}
+
+func (l *prefixLogger) Fatalf(msg string, args ...any) {
+	l.logger.Fatalf(l.prefix+msg, args...)
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFields(t *testing.T) {
+	ctx := WithFields(context.Background(), FieldRequestID, "id", FieldEndpoint, "/openrtb2/auction")
+	bidderCtx := WithFields(ctx, FieldBidder, "appnexus")
+	otherBidderCtx := WithFields(ctx, FieldBidder, "rubicon")
+
+	assert.Nil(t, Fields(context.Background()))
+	assert.Equal(t, []any{FieldRequestID, "id", FieldEndpoint, "/openrtb2/auction"}, Fields(ctx))
+	assert.Equal(t, []any{FieldRequestID, "id", FieldEndpoint, "/openrtb2/auction", FieldBidder, "appnexus"}, Fields(bidderCtx))
+	assert.Equal(t, []any{FieldRequestID, "id", FieldEndpoint, "/openrtb2/auction", FieldBidder, "rubicon"}, Fields(otherBidderCtx))
+	assert.Equal(t, ctx, WithFields(ctx), "No fields should return the context as is")
+}
+
+func TestWithContext(t *testing.T) {
+	defer func(original Logger) { logger = original }(logger)
+	mock := newMockLogger()
+	logger = mock
+
+	ctx := WithFields(context.Background(), FieldRequestID, "id", FieldAccountID, 1234)
+	WithContext(ctx).Errorf("error: %s", "message")
+	WithContext(context.Background()).Warnf("warning")
+
+	assert.Equal(t, []logCall{{msg: "[request_id=id account_id=1234] error: %s", args: []any{"message"}}}, mock.errorCalls)
+	assert.Equal(t, []logCall{{msg: "warning"}}, mock.warnCalls)
+}
+
+func TestFormatFields(t *testing.T) {
+	testCases := []struct {
+		name     string
+		keyvals  []any
+		expected string
+	}{
+		{
+			name:     "none",
+			expected: "",
+		},
+		{
+			name:     "pairs",
+			keyvals:  []any{"a", 1, "b", "two"},
+			expected: "[a=1 b=two] ",
+		},
+		{
+			name:     "percent",
+			keyvals:  []any{"a", "100%d"},
+			expected: "[a=100%%d] ",
+		},
+		{
+			name:     "odd",
+			keyvals:  []any{"a", 1, "b"},
+			expected: "[a=1 b] ",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, formatFields(test.keyvals))
+		})
+	}
+}
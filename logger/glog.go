@@ -1,37 +1,81 @@
 package logger
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 )
 
 // GlogLogger implements the Logger interface for logging using the glog library with configurable call depth.
 type GlogLogger struct {
 	depth int
+	// fields are added to the messages as their prefix, formatted as "[key=value key=value] "
+	fields  []any
+	prefix  string
+	limiter *RateLimiter
 }
 
 // Debug logs a debug-level message with the specified format and arguments.
 func (logger *GlogLogger) Debugf(msg string, args ...any) {
-	glog.InfoDepthf(logger.depth, msg, args...)
+	if msg, ok := logger.limit(msg, args); ok {
+		glog.InfoDepthf(logger.depth, logger.prefix+msg, args...)
+	}
 }
 
 // Info logs an informational-level message with the specified format and optional arguments.
 func (logger *GlogLogger) Infof(msg string, args ...any) {
-	glog.InfoDepthf(logger.depth, msg, args...)
+	if msg, ok := logger.limit(msg, args); ok {
+		glog.InfoDepthf(logger.depth, logger.prefix+msg, args...)
+	}
 }
 
 // Warn logs a warning-level message with the specified format and arguments.
 func (logger *GlogLogger) Warnf(msg string, args ...any) {
-	glog.WarningDepthf(logger.depth, msg, args...)
+	if msg, ok := logger.limit(msg, args); ok {
+		glog.WarningDepthf(logger.depth, logger.prefix+msg, args...)
+	}
 }
 
 // Error logs an error-level message with the specified format and arguments.
 func (logger *GlogLogger) Errorf(msg string, args ...any) {
-	glog.ErrorDepthf(logger.depth, msg, args...)
+	if msg, ok := logger.limit(msg, args); ok {
+		glog.ErrorDepthf(logger.depth, logger.prefix+msg, args...)
+	}
 }
 
 // Fatal logs a fatal-level message with the specified format and arguments, then exits the application.
 func (logger *GlogLogger) Fatalf(msg string, args ...any) {
-	glog.FatalDepthf(logger.depth, msg, args...)
+	glog.FatalDepthf(logger.depth, logger.prefix+msg, args...)
+}
+
+// With returns a logger adding the key/value pairs to the messages, formatted as "[key=value key=value] "
+func (logger *GlogLogger) With(keyvals ...any) Logger {
+	if len(keyvals) == 0 && logger.depth == 1 {
+		return logger
+	}
+	fields := make([]any, 0, len(logger.fields)+len(keyvals))
+	fields = append(fields, logger.fields...)
+	fields = append(fields, keyvals...)
+	// The returned logger is called directly rather than through the package functions, so its caller is 1 frame
+	// above its methods
+	return &GlogLogger{
+		depth:   1,
+		fields:  fields,
+		prefix:  formatFields(fields),
+		limiter: logger.limiter,
+	}
+}
+
+// limit applies the rate limit to the formatted message, noting how many times it was suppressed since last logged
+func (logger *GlogLogger) limit(msg string, args []any) (string, bool) {
+	if logger.limiter == nil {
+		return msg, true
+	}
+	allowed, suppressed := logger.limiter.Allow(fmt.Sprintf(msg, args...))
+	if suppressed > 0 {
+		msg += fmt.Sprintf(" (repeated %d times since last logged)", suppressed)
+	}
+	return msg, allowed
 }
 
 func NewGlogLogger() Logger {
@@ -39,3 +83,12 @@ func NewGlogLogger() Logger {
 		depth: 1,
 	}
 }
+
+// NewRateLimitedGlogLogger returns a glog logger which suppresses the messages repeated more often than allowed by
+// the limiter
+func NewRateLimitedGlogLogger(limiter *RateLimiter) Logger {
+	return &GlogLogger{
+		depth:   1,
+		limiter: limiter,
+	}
+}
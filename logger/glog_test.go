@@ -2,9 +2,16 @@ package logger
 
 import (
 	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
 	"testing"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewGlogLogger(t *testing.T) {
@@ -169,3 +176,51 @@ func TestGlogLogger_SpecialCharacters(t *testing.T) {
 		logger.Infof("message with special chars: \n\t\"quotes\" and 'apostrophes'")
 	}, "Messages with special characters should not panic")
 }
+
+func TestGlogLogger_With(t *testing.T) {
+	logger := NewGlogLogger().(FieldLogger)
+
+	requestLogger := logger.With(FieldRequestID, "id")
+	bidderLogger := requestLogger.(FieldLogger).With(FieldBidder, "appnexus")
+
+	assert.Equal(t, 1, requestLogger.(*GlogLogger).depth, "Loggers with fields are called directly")
+	assert.Equal(t, "[request_id=id] ", requestLogger.(*GlogLogger).prefix)
+	assert.Equal(t, "[request_id=id bidder=appnexus] ", bidderLogger.(*GlogLogger).prefix)
+}
+
+func TestGlogLogger_WithSource(t *testing.T) {
+	flag.Set("logtostderr", "true")
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	stderr := os.Stderr
+	os.Stderr = writer
+	defer func() { os.Stderr = stderr }()
+
+	NewGlogLogger().(FieldLogger).With(FieldRequestID, "id").Infof("with source")
+	_, _, line, _ := runtime.Caller(0)
+	writer.Close()
+
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Contains(t, string(output), fmt.Sprintf("glog_test.go:%d] [request_id=id] with source", line-1))
+}
+
+func TestGlogLogger_Limit(t *testing.T) {
+	clk := clock.NewMock()
+	logger := NewRateLimitedGlogLogger(NewRateLimiter(1, time.Minute, clk)).(*GlogLogger)
+
+	msg, allowed := logger.limit("message %s", []any{"a"})
+	assert.True(t, allowed)
+	assert.Equal(t, "message %s", msg)
+
+	_, allowed = logger.limit("message %s", []any{"a"})
+	assert.False(t, allowed)
+
+	_, allowed = logger.limit("message %s", []any{"b"})
+	assert.True(t, allowed, "Messages sharing a format aren't suppressed by each other")
+
+	clk.Add(time.Minute)
+	msg, allowed = logger.limit("message %s", []any{"a"})
+	assert.True(t, allowed)
+	assert.Equal(t, "message %s (repeated 1 times since last logged)", msg)
+}
@@ -16,3 +16,11 @@ type Logger interface {
 	// Fatalf level logging
 	Fatalf(msg string, args ...any)
 }
+
+// FieldLogger is implemented by the loggers which can add key/value fields to their messages
+type FieldLogger interface {
+	Logger
+
+	// With returns a logger adding the key/value pairs to the messages of this logger
+	With(keyvals ...any) Logger
+}
@@ -2,6 +2,12 @@ package logger
 
 var logger Logger = NewGlogLogger()
 
+// SetLogger replaces the logger used by the package functions. It isn't safe for concurrent use, and is meant to be
+// called on startup.
+func SetLogger(l Logger) {
+	logger = l
+}
+
 // Debugf level logging
 func Debugf(msg string, args ...any) {
 	logger.Debugf(msg, args...)
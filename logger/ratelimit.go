@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// maxRateLimitedMessages bounds the number of distinct messages tracked by a RateLimiter. The counts are reset once
+// it's reached, so messages holding values unique to each request, like their IDs, are never suppressed.
+const maxRateLimitedMessages = 10000
+
+// RateLimiter limits how many times a message is logged per interval. Messages are identified by their formatted
+// text, so unrelated messages sharing a format string, e.g. "%v", don't suppress each other.
+type RateLimiter struct {
+	burst    int
+	interval time.Duration
+	clock    clock.Clock
+
+	mux      sync.Mutex
+	messages map[string]*messageCount
+}
+
+type messageCount struct {
+	windowStart time.Time
+	logged      int
+	suppressed  int
+}
+
+// NewRateLimiter returns a RateLimiter logging each message up to burst times per interval
+func NewRateLimiter(burst int, interval time.Duration, clock clock.Clock) *RateLimiter {
+	return &RateLimiter{
+		burst:    burst,
+		interval: interval,
+		clock:    clock,
+		messages: make(map[string]*messageCount),
+	}
+}
+
+// Allow tells whether the formatted message should be logged. Once allowed again after being suppressed, it also
+// returns the number of times it was suppressed in between.
+func (r *RateLimiter) Allow(msg string) (bool, int) {
+	if r == nil {
+		return true, 0
+	}
+	now := r.clock.Now()
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	count, ok := r.messages[msg]
+	if !ok {
+		if len(r.messages) >= maxRateLimitedMessages {
+			r.messages = make(map[string]*messageCount)
+		}
+		count = &messageCount{windowStart: now}
+		r.messages[msg] = count
+	}
+	if now.Sub(count.windowStart) >= r.interval {
+		count.windowStart = now
+		count.logged = 0
+	}
+	if count.logged >= r.burst {
+		count.suppressed++
+		return false, 0
+	}
+	count.logged++
+	suppressed := count.suppressed
+	count.suppressed = 0
+	return true, suppressed
+}
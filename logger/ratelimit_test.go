@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	clk := clock.NewMock()
+	limiter := NewRateLimiter(2, time.Minute, clk)
+
+	assertAllow := func(msg string, expectedAllowed bool, expectedSuppressed int) {
+		t.Helper()
+		allowed, suppressed := limiter.Allow(msg)
+		assert.Equal(t, expectedAllowed, allowed)
+		assert.Equal(t, expectedSuppressed, suppressed)
+	}
+
+	assertAllow("repeated %s", true, 0)
+	assertAllow("repeated %s", true, 0)
+	assertAllow("repeated %s", false, 0)
+	assertAllow("repeated %s", false, 0)
+	assertAllow("other", true, 0)
+
+	clk.Add(59 * time.Second)
+	assertAllow("repeated %s", false, 0)
+
+	clk.Add(time.Second)
+	assertAllow("repeated %s", true, 3)
+	assertAllow("repeated %s", true, 0)
+	assertAllow("repeated %s", false, 0)
+}
+
+func TestRateLimiterNil(t *testing.T) {
+	var limiter *RateLimiter
+	for i := 0; i < 10; i++ {
+		allowed, suppressed := limiter.Allow("message")
+		assert.True(t, allowed)
+		assert.Zero(t, suppressed)
+	}
+}
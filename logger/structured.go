@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+)
+
+// LevelFatal is the level of the messages logged by Fatalf, above slog.LevelError
+const LevelFatal = slog.Level(12)
+
+// StructuredLogger implements the Logger interface by writing JSON objects, one per line. Each object holds the time,
+// level, source and message, along with the fields added by With.
+type StructuredLogger struct {
+	handler slog.Handler
+	depth   int
+	fields  []any
+	limiter *RateLimiter
+	exit    func(code int)
+}
+
+// NewStructuredLogger returns a logger writing the messages of the level or above as JSON lines to w. The limiter
+// is optional.
+func NewStructuredLogger(w io.Writer, level slog.Level, limiter *RateLimiter) Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       level,
+		ReplaceAttr: replaceLevelName,
+	})
+	return &StructuredLogger{
+		handler: handler,
+		depth:   1,
+		limiter: limiter,
+		exit:    os.Exit,
+	}
+}
+
+// ParseLevel parses the name of a level: debug, info, warn or error
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return level, fmt.Errorf("unknown log level %q, must be debug, info, warn or error", name)
+	}
+	return level, nil
+}
+
+func replaceLevelName(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Key == slog.LevelKey && len(groups) == 0 {
+		if level, ok := attr.Value.Any().(slog.Level); ok && level >= LevelFatal {
+			attr.Value = slog.StringValue("FATAL")
+		}
+	}
+	return attr
+}
+
+func (l *StructuredLogger) Debugf(msg string, args ...any) {
+	l.log(slog.LevelDebug, msg, args)
+}
+
+func (l *StructuredLogger) Infof(msg string, args ...any) {
+	l.log(slog.LevelInfo, msg, args)
+}
+
+func (l *StructuredLogger) Warnf(msg string, args ...any) {
+	l.log(slog.LevelWarn, msg, args)
+}
+
+func (l *StructuredLogger) Errorf(msg string, args ...any) {
+	l.log(slog.LevelError, msg, args)
+}
+
+// Fatalf logs the message, which is never rate limited, then exits the application
+func (l *StructuredLogger) Fatalf(msg string, args ...any) {
+	l.write(LevelFatal, fmt.Sprintf(msg, args...), 0, 0)
+	l.exit(255)
+}
+
+// With returns a logger adding the key/value pairs to the messages
+func (l *StructuredLogger) With(keyvals ...any) Logger {
+	fields := make([]any, 0, len(l.fields)+len(keyvals))
+	fields = append(fields, l.fields...)
+	fields = append(fields, keyvals...)
+	// The returned logger is called directly rather than through the package functions, hence the depth of 0
+	return &StructuredLogger{
+		handler: l.handler,
+		fields:  fields,
+		limiter: l.limiter,
+		exit:    l.exit,
+	}
+}
+
+func (l *StructuredLogger) log(level slog.Level, msg string, args []any) {
+	if !l.handler.Enabled(context.Background(), level) {
+		return
+	}
+	msg = fmt.Sprintf(msg, args...)
+	allowed, suppressed := l.limiter.Allow(msg)
+	if !allowed {
+		return
+	}
+	l.write(level, msg, suppressed, 1)
+}
+
+// write logs the message, with the source of the call to the logger. The skip frames are those between write and the
+// method of the level.
+func (l *StructuredLogger) write(level slog.Level, msg string, suppressed int, skip int) {
+	// Skips runtime.Callers, write, the method of the level, and the package function unless called directly
+	var pcs [1]uintptr
+	runtime.Callers(3+skip+l.depth, pcs[:])
+
+	record := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	record.Add(l.fields...)
+	if suppressed > 0 {
+		record.Add("suppressed", suppressed)
+	}
+	l.handler.Handle(context.Background(), record)
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructuredLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStructuredLogger(&buf, slog.LevelInfo, nil)
+
+	l.Debugf("debug")
+	l.Infof("info %d", 1)
+	l.Warnf("warn")
+	l.Errorf("error")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "INFO", lines[0]["level"])
+	assert.Equal(t, "info 1", lines[0]["msg"])
+	assert.Equal(t, "WARN", lines[1]["level"])
+	assert.Equal(t, "ERROR", lines[2]["level"])
+}
+
+func TestStructuredLoggerFatal(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStructuredLogger(&buf, slog.LevelInfo, nil).(*StructuredLogger)
+	var exitCode int
+	l.exit = func(code int) { exitCode = code }
+
+	l.Fatalf("fatal %s", "error")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "FATAL", lines[0]["level"])
+	assert.Equal(t, "fatal error", lines[0]["msg"])
+	assert.Equal(t, 255, exitCode)
+}
+
+func TestStructuredLoggerWith(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStructuredLogger(&buf, slog.LevelInfo, nil).(*StructuredLogger)
+
+	requestLogger := l.With(FieldRequestID, "id", FieldAccountID, "1234")
+	requestLogger.(FieldLogger).With(FieldBidder, "appnexus").Errorf("bidder error")
+	requestLogger.Infof("request info")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "bidder error", lines[0]["msg"])
+	assert.Equal(t, "id", lines[0][FieldRequestID])
+	assert.Equal(t, "1234", lines[0][FieldAccountID])
+	assert.Equal(t, "appnexus", lines[0][FieldBidder])
+	assert.Equal(t, "id", lines[1][FieldRequestID])
+	assert.NotContains(t, lines[1], FieldBidder)
+}
+
+func TestStructuredLoggerSource(t *testing.T) {
+	defer func(original Logger) { logger = original }(logger)
+	var buf bytes.Buffer
+	SetLogger(NewStructuredLogger(&buf, slog.LevelInfo, nil))
+
+	Errorf("through the package")
+	With(FieldBidder, "appnexus").Errorf("through a field logger")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		source, ok := line["source"].(map[string]any)
+		require.True(t, ok, "The source should be logged")
+		assert.True(t, strings.HasSuffix(source["file"].(string), "structured_test.go"), "The source should be the caller of the logger, got %s", source["file"])
+	}
+}
+
+func TestStructuredLoggerRateLimit(t *testing.T) {
+	var buf bytes.Buffer
+	clk := clock.NewMock()
+	l := NewStructuredLogger(&buf, slog.LevelInfo, NewRateLimiter(1, time.Minute, clk))
+
+	l.Errorf("%v", "repeated")
+	l.Errorf("%v", "repeated")
+	l.Errorf("%v", "other")
+	l.Errorf("%v", "repeated")
+	clk.Add(time.Minute)
+	l.Errorf("%v", "repeated")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "repeated", lines[0]["msg"])
+	assert.NotContains(t, lines[0], "suppressed")
+	assert.Equal(t, "other", lines[1]["msg"], "Messages sharing a format aren't suppressed by each other")
+	assert.Equal(t, "repeated", lines[2]["msg"])
+	assert.Equal(t, float64(2), lines[2]["suppressed"])
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("warn")
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+
+	_, err = ParseLevel("verbose")
+	assert.EqualError(t, err, `unknown log level "verbose", must be debug, info, warn or error`)
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, data := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(data) == 0 {
+			continue
+		}
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(data, &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -34,6 +34,9 @@ type UserSyncPrivacy struct {
 	USPrivacy   string
 	GPP         string
 	GPPSID      string
+	// ExternalURL overrides the external URL of the host which the syncers redirect to, e.g. with the CNAME of Prebid
+	// Server of an account in the first_party mode of the uids cookie. The external URLs of the syncers are kept.
+	ExternalURL string
 }
 
 // ResolveMacros resolves macros in the given template with the provided params
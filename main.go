@@ -3,10 +3,12 @@ package main
 import (
 	"flag"
 	"net/http"
+	"os"
 	"path/filepath"
 	"runtime"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
@@ -41,6 +43,7 @@ func main() {
 	if err != nil {
 		logger.Fatalf("Configuration could not be loaded or did not pass validation: %v", err)
 	}
+	logger.SetLogger(newLogger(cfg.Logging))
 
 	// Create a soft memory limit on the total amount of memory that PBS uses to tune the behavior
 	// of the Go garbage collector. In summary, `cfg.GarbageCollectorThreshold` serves as a fixed cost
@@ -65,6 +68,21 @@ func loadConfig(bidderInfos config.BidderInfos) (*config.Configuration, error) {
 	return config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
 }
 
+// newLogger returns the application logger selected by the config, which has been validated
+func newLogger(cfg config.Logging) logger.Logger {
+	var limiter *logger.RateLimiter
+	if cfg.RateLimit.Enabled {
+		interval, _ := time.ParseDuration(cfg.RateLimit.Interval)
+		limiter = logger.NewRateLimiter(cfg.RateLimit.Burst, interval, clock.New())
+	}
+
+	if cfg.Format == config.LogFormatJSON {
+		level, _ := logger.ParseLevel(cfg.Level)
+		return logger.NewStructuredLogger(os.Stderr, level, limiter)
+	}
+	return logger.NewRateLimitedGlogLogger(limiter)
+}
+
 func serve(cfg *config.Configuration) error {
 	httpTimeout := time.Duration(cfg.CurrencyConverter.FetchTimeoutMilliseconds) * time.Millisecond
 	fetchingInterval := time.Duration(cfg.CurrencyConverter.FetchIntervalSeconds) * time.Second
@@ -80,7 +98,7 @@ func serve(cfg *config.Configuration) error {
 	}
 
 	corsRouter := router.SupportCORS(r)
-	if err := server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval), r.MetricsEngine); err != nil {
+	if err := server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval, r), r.MetricsEngine); err != nil {
 		logger.Fatalf("prebid-server returned an error: %v", err)
 	}
 
@@ -1,11 +1,15 @@
 package config
 
 import (
+	"context"
 	"time"
 
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/metrics"
+	otlpmetrics "github.com/prebid/prebid-server/v3/metrics/otlp"
 	prometheusmetrics "github.com/prebid/prebid-server/v3/metrics/prometheus"
+	statsdmetrics "github.com/prebid/prebid-server/v3/metrics/statsd"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	gometrics "github.com/rcrowley/go-metrics"
 	influxdb "github.com/vrischmann/go-metrics-influxdb"
@@ -40,9 +44,27 @@ func NewMetricsEngine(cfg *config.Configuration, adapterList []openrtb_ext.Bidde
 	}
 	if cfg.Metrics.Prometheus.Port != 0 {
 		// Set up the Prometheus metrics.
-		returnEngine.PrometheusMetrics = prometheusmetrics.NewMetrics(cfg.Metrics.Prometheus, cfg.Metrics.Disabled, syncerKeys, moduleStageNames)
+		returnEngine.PrometheusMetrics = prometheusmetrics.NewMetrics(cfg.Metrics.Prometheus, cfg.Metrics.Disabled, cfg.Metrics.Auction, syncerKeys, moduleStageNames)
 		engineList = append(engineList, returnEngine.PrometheusMetrics)
 	}
+	if cfg.Metrics.StatsD.Address != "" {
+		// Set up the StatsD metrics, sent to the agent over UDP or a Unix socket
+		statsdMetrics, err := statsdmetrics.NewMetrics(cfg.Metrics.StatsD, cfg.Metrics.Disabled)
+		if err != nil {
+			logger.Errorf("[StatsDMetrics] Failed to set up the client of %s: %v", cfg.Metrics.StatsD.Address, err)
+		} else {
+			returnEngine.StatsDMetrics = statsdMetrics
+			engineList = append(engineList, returnEngine.StatsDMetrics)
+		}
+	}
+	if cfg.Metrics.OTLP.Enabled {
+		// Set up the OpenTelemetry metrics, exported to the collector in the background
+		if err := returnEngine.startOTLP(cfg); err != nil {
+			logger.Errorf("[OTLPMetrics] Failed to set up the export of the metrics: %v", err)
+		} else {
+			engineList = append(engineList, returnEngine.OTLPMetrics)
+		}
+	}
 
 	// Now return the proper metrics engine
 	if len(engineList) > 1 {
@@ -61,6 +83,50 @@ type DetailedMetricsEngine struct {
 	metrics.MetricsEngine
 	GoMetrics         *metrics.Metrics
 	PrometheusMetrics *prometheusmetrics.Metrics
+	OTLPMetrics       *otlpmetrics.Metrics
+	StatsDMetrics     *statsdmetrics.Metrics
+
+	shutdownOTLP func(context.Context) error
+	otlpTimeout  time.Duration
+}
+
+func (me *DetailedMetricsEngine) startOTLP(cfg *config.Configuration) error {
+	provider, err := otlpmetrics.NewMeterProvider(cfg.Metrics.OTLP, cfg.DataCenter)
+	if err != nil {
+		return err
+	}
+	if me.OTLPMetrics, err = otlpmetrics.NewMetrics(provider, cfg.Metrics.Disabled, cfg.Metrics.Auction); err != nil {
+		provider.Shutdown(context.Background())
+		return err
+	}
+	me.shutdownOTLP = provider.Shutdown
+	me.otlpTimeout = time.Duration(cfg.Metrics.OTLP.TimeoutMS) * time.Millisecond
+	return nil
+}
+
+// Shutdown sends the remaining StatsD and OpenTelemetry metrics, if any
+func (me *DetailedMetricsEngine) Shutdown() {
+	if me.StatsDMetrics != nil {
+		me.StatsDMetrics.Shutdown()
+	}
+	if me.shutdownOTLP == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), me.otlpTimeout)
+	defer cancel()
+	if err := me.shutdownOTLP(ctx); err != nil {
+		logger.Errorf("[OTLPMetrics] Failed to export the remaining metrics: %v", err)
+	}
+}
+
+// RecordRequestTimeWithContext on the underlying engines, with the trace of ctx for the engines supporting it
+func (me *DetailedMetricsEngine) RecordRequestTimeWithContext(ctx context.Context, labels metrics.Labels, length time.Duration) {
+	metrics.RecordRequestTime(ctx, me.MetricsEngine, labels, length)
+}
+
+// RecordAdapterTimeWithContext on the underlying engines, with the trace of ctx for the engines supporting it
+func (me *DetailedMetricsEngine) RecordAdapterTimeWithContext(ctx context.Context, labels metrics.AdapterLabels, length time.Duration) {
+	metrics.RecordAdapterTime(ctx, me.MetricsEngine, labels, length)
 }
 
 // MultiMetricsEngine logs metrics to multiple metrics databases The can be useful in transitioning
@@ -106,6 +172,13 @@ func (me *MultiMetricsEngine) RecordRequestTime(labels metrics.Labels, length ti
 	}
 }
 
+// RecordRequestTimeWithContext across all engines, with the trace of ctx for the engines supporting it
+func (me *MultiMetricsEngine) RecordRequestTimeWithContext(ctx context.Context, labels metrics.Labels, length time.Duration) {
+	for _, thisME := range *me {
+		metrics.RecordRequestTime(ctx, thisME, labels, length)
+	}
+}
+
 // RecordStoredDataFetchTime across all engines
 func (me *MultiMetricsEngine) RecordStoredDataFetchTime(labels metrics.StoredDataLabels, length time.Duration) {
 	for _, thisME := range *me {
@@ -175,6 +248,20 @@ func (me *MultiMetricsEngine) RecordAdapterPrice(labels metrics.AdapterLabels, c
 	}
 }
 
+// RecordAdapterAuctionImps across all engines
+func (me *MultiMetricsEngine) RecordAdapterAuctionImps(adapter openrtb_ext.BidderName, pubID string, imps int) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterAuctionImps(adapter, pubID, imps)
+	}
+}
+
+// RecordAuctionBid across all engines
+func (me *MultiMetricsEngine) RecordAuctionBid(labels metrics.AuctionBidLabels, cpm float64, floorRatio float64) {
+	for _, thisME := range *me {
+		thisME.RecordAuctionBid(labels, cpm, floorRatio)
+	}
+}
+
 // RecordAdapterTime across all engines
 func (me *MultiMetricsEngine) RecordAdapterTime(labels metrics.AdapterLabels, length time.Duration) {
 	for _, thisME := range *me {
@@ -182,6 +269,13 @@ func (me *MultiMetricsEngine) RecordAdapterTime(labels metrics.AdapterLabels, le
 	}
 }
 
+// RecordAdapterTimeWithContext across all engines, with the trace of ctx for the engines supporting it
+func (me *MultiMetricsEngine) RecordAdapterTimeWithContext(ctx context.Context, labels metrics.AdapterLabels, length time.Duration) {
+	for _, thisME := range *me {
+		metrics.RecordAdapterTime(ctx, thisME, labels, length)
+	}
+}
+
 // RecordOverheadTime across all engines
 func (me *MultiMetricsEngine) RecordOverheadTime(overhead metrics.OverheadType, length time.Duration) {
 	for _, thisME := range *me {
@@ -273,6 +367,20 @@ func (me *MultiMetricsEngine) RecordAdapterBuyerUIDScrubbed(adapter openrtb_ext.
 	}
 }
 
+// RecordAdapterEIDsRemoved across all engines
+func (me *MultiMetricsEngine) RecordAdapterEIDsRemoved(adapter openrtb_ext.BidderName, source string, count int) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterEIDsRemoved(adapter, source, count)
+	}
+}
+
+// RecordOptOutMatch across all engines
+func (me *MultiMetricsEngine) RecordOptOutMatch(entryType metrics.OptOutEntryType) {
+	for _, thisME := range *me {
+		thisME.RecordOptOutMatch(entryType)
+	}
+}
+
 // RecordAdapterGDPRRequestBlocked across all engines
 func (me *MultiMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ext.BidderName) {
 	for _, thisME := range *me {
@@ -299,6 +407,13 @@ func (me *MultiMetricsEngine) RecordGvlListRequest() {
 	}
 }
 
+// RecordGvlListLatest across all engines
+func (me *MultiMetricsEngine) RecordGvlListLatest(specVersion uint16, listVersion uint16, fetched time.Time) {
+	for _, thisME := range *me {
+		thisME.RecordGvlListLatest(specVersion, listVersion, fetched)
+	}
+}
+
 func (me *MultiMetricsEngine) RecordAdsCertReq(success bool) {
 	for _, thisME := range *me {
 		thisME.RecordAdsCertReq(success)
@@ -464,6 +579,14 @@ func (me *NilMetricsEngine) RecordAdapterBidReceived(labels metrics.AdapterLabel
 func (me *NilMetricsEngine) RecordAdapterPrice(labels metrics.AdapterLabels, cpm float64) {
 }
 
+// RecordAdapterAuctionImps as a noop
+func (me *NilMetricsEngine) RecordAdapterAuctionImps(adapter openrtb_ext.BidderName, pubID string, imps int) {
+}
+
+// RecordAuctionBid as a noop
+func (me *NilMetricsEngine) RecordAuctionBid(labels metrics.AuctionBidLabels, cpm float64, floorRatio float64) {
+}
+
 // RecordAdapterTime as a noop
 func (me *NilMetricsEngine) RecordAdapterTime(labels metrics.AdapterLabels, length time.Duration) {
 }
@@ -520,6 +643,14 @@ func (me *NilMetricsEngine) RecordRequestPrivacy(privacy metrics.PrivacyLabels)
 func (me *NilMetricsEngine) RecordAdapterBuyerUIDScrubbed(adapter openrtb_ext.BidderName) {
 }
 
+// RecordAdapterEIDsRemoved as a noop
+func (me *NilMetricsEngine) RecordAdapterEIDsRemoved(adapter openrtb_ext.BidderName, source string, count int) {
+}
+
+// RecordOptOutMatch as a noop
+func (me *NilMetricsEngine) RecordOptOutMatch(entryType metrics.OptOutEntryType) {
+}
+
 // RecordAdapterGDPRRequestBlocked as a noop
 func (me *NilMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ext.BidderName) {
 }
@@ -534,6 +665,10 @@ func (me *NilMetricsEngine) RecordStoredResponse(pubId string) {
 func (me *NilMetricsEngine) RecordGvlListRequest() {
 }
 
+// RecordGvlListLatest as a noop
+func (me *NilMetricsEngine) RecordGvlListLatest(specVersion uint16, listVersion uint16, fetched time.Time) {
+}
+
 func (me *NilMetricsEngine) RecordAdsCertReq(success bool) {
 
 }
@@ -39,6 +39,39 @@ func TestGoMetricsEngine(t *testing.T) {
 	}
 }
 
+func TestOTLPMetricsEngine(t *testing.T) {
+	cfg := mainConfig.Configuration{}
+	cfg.Metrics.OTLP = mainConfig.OTLPMetrics{
+		Enabled:          true,
+		Endpoint:         "localhost:4318",
+		ServiceName:      "prebid-server",
+		ExportIntervalMS: 60000,
+		TimeoutMS:        1,
+	}
+	testEngine := NewMetricsEngine(&cfg, nil, nil, modulesStages)
+	if testEngine.OTLPMetrics == nil || testEngine.MetricsEngine != testEngine.OTLPMetrics {
+		t.Error("Expected the OTLP metrics as MetricsEngine, but didn't get it")
+	}
+	if _, ok := metrics.MetricsEngine(testEngine).(metrics.ContextMetricsEngine); !ok {
+		t.Error("Expected the DetailedMetricsEngine to record the timings with a context")
+	}
+	testEngine.Shutdown()
+}
+
+func TestStatsDMetricsEngine(t *testing.T) {
+	cfg := mainConfig.Configuration{}
+	cfg.Metrics.StatsD = mainConfig.StatsDMetrics{
+		Address:               "127.0.0.1:8125",
+		SampleRate:            1,
+		AggregationIntervalMS: 1000,
+	}
+	testEngine := NewMetricsEngine(&cfg, nil, nil, modulesStages)
+	if testEngine.StatsDMetrics == nil || testEngine.MetricsEngine != testEngine.StatsDMetrics {
+		t.Error("Expected the StatsD metrics as MetricsEngine, but didn't get it")
+	}
+	testEngine.Shutdown()
+}
+
 func TestMultiMetricsEngine(t *testing.T) {
 	cfg := mainConfig.Configuration{}
 	cfg.Metrics.Influxdb.Host = "localhost"
@@ -36,6 +36,7 @@ type Metrics struct {
 	BidderServerResponseTimer      metrics.Timer
 	StoredResponsesMeter           metrics.Meter
 	GvlListRequestsMeter           metrics.Meter
+	OptOutMatchMeter               map[OptOutEntryType]metrics.Meter
 
 	// Metrics for OpenRTB requests specifically
 	RequestStatuses       map[RequestType]map[RequestStatus]metrics.Meter
@@ -106,6 +107,17 @@ type AdapterMetrics struct {
 	BuyerUIDScrubbed   metrics.Meter
 	GDPRRequestBlocked metrics.Meter
 	ThrottledMeter     metrics.Meter
+	EIDsRemovedMeter   metrics.Meter
+
+	// The bids which took part in the auction, with their CPM in thousandths of the reference currency
+	// and their ratio to the floor of their imp in percent. The histograms are sampled like the other
+	// go-metrics histograms, so metrics.auction.price_buckets and floor_ratio_buckets don't apply.
+	AuctionImpsMeter           metrics.Meter
+	AuctionBidsMeter           metrics.Meter
+	AuctionDealBidsMeter       metrics.Meter
+	AuctionWinsMeter           metrics.Meter
+	AuctionCPMHistogram        metrics.Histogram
+	AuctionFloorRatioHistogram metrics.Histogram
 
 	BidValidationCreativeSizeErrorMeter metrics.Meter
 	BidValidationCreativeSizeWarnMeter  metrics.Meter
@@ -188,6 +200,7 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		SyncerSetsMeter:                make(map[string]map[SyncerSetUidStatus]metrics.Meter),
 		StoredResponsesMeter:           blankMeter,
 		GvlListRequestsMeter:           blankMeter,
+		OptOutMatchMeter:               make(map[OptOutEntryType]metrics.Meter, len(OptOutEntryTypes())),
 
 		ImpsTypeBanner: blankMeter,
 		ImpsTypeVideo:  blankMeter,
@@ -251,6 +264,10 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		newMetrics.PrivacyTCFRequestVersion[v] = blankMeter
 	}
 
+	for _, t := range OptOutEntryTypes() {
+		newMetrics.OptOutMatchMeter[t] = blankMeter
+	}
+
 	for _, dt := range StoredDataTypes() {
 		newMetrics.StoredDataFetchTimer[dt] = make(map[StoredDataFetchType]metrics.Timer)
 		newMetrics.StoredDataErrorMeter[dt] = make(map[StoredDataError]metrics.Meter)
@@ -314,6 +331,9 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 	newMetrics.PrebidCacheRequestTimerError = metrics.GetOrRegisterTimer("prebid_cache_request_time.err", registry)
 	newMetrics.StoredResponsesMeter = metrics.GetOrRegisterMeter("stored_responses", registry)
 	newMetrics.GvlListRequestsMeter = metrics.GetOrRegisterMeter("gvl_requests", registry)
+	for _, entryType := range OptOutEntryTypes() {
+		newMetrics.OptOutMatchMeter[entryType] = metrics.GetOrRegisterMeter(fmt.Sprintf("optout.match.%s", entryType), registry)
+	}
 	newMetrics.OverheadTimer = makeOverheadTimerMetrics(registry)
 	newMetrics.BidderServerResponseTimer = metrics.GetOrRegisterTimer("bidder_server_response_time_seconds", registry)
 
@@ -420,6 +440,14 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 		PanicMeter:        blankMeter,
 		MarkupMetrics:     makeBlankBidMarkupMetrics(),
 		ThrottledMeter:    blankMeter,
+		EIDsRemovedMeter:  blankMeter,
+
+		AuctionImpsMeter:           blankMeter,
+		AuctionBidsMeter:           blankMeter,
+		AuctionDealBidsMeter:       blankMeter,
+		AuctionWinsMeter:           blankMeter,
+		AuctionCPMHistogram:        &metrics.NilHistogram{},
+		AuctionFloorRatioHistogram: &metrics.NilHistogram{},
 	}
 	if !disabledMetrics.AdapterConnectionMetrics {
 		newAdapter.ConnCreated = metrics.NilCounter{}
@@ -515,6 +543,14 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	am.BuyerUIDScrubbed = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.buyeruid_scrubbed", adapterOrAccount, exchange), registry)
 	am.GDPRRequestBlocked = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.gdpr_request_blocked", adapterOrAccount, exchange), registry)
 	am.ThrottledMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.requests.throttled", adapterOrAccount, exchange), registry)
+	am.EIDsRemovedMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.eids_removed", adapterOrAccount, exchange), registry)
+
+	am.AuctionImpsMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.auction.imps", adapterOrAccount, exchange), registry)
+	am.AuctionBidsMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.auction.bids", adapterOrAccount, exchange), registry)
+	am.AuctionDealBidsMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.auction.deal_bids", adapterOrAccount, exchange), registry)
+	am.AuctionWinsMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.auction.wins", adapterOrAccount, exchange), registry)
+	am.AuctionCPMHistogram = metrics.GetOrRegisterHistogram(fmt.Sprintf("%[1]s.%[2]s.auction.bid_cpm", adapterOrAccount, exchange), registry, metrics.NewExpDecaySample(1028, 0.015))
+	am.AuctionFloorRatioHistogram = metrics.GetOrRegisterHistogram(fmt.Sprintf("%[1]s.%[2]s.auction.floor_ratio", adapterOrAccount, exchange), registry, metrics.NewExpDecaySample(1028, 0.015))
 
 	am.BidValidationCreativeSizeErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.err", adapterOrAccount, exchange), registry)
 	am.BidValidationCreativeSizeWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.warn", adapterOrAccount, exchange), registry)
@@ -658,6 +694,17 @@ func (me *Metrics) RecordGvlListRequest() {
 	me.GvlListRequestsMeter.Mark(1)
 }
 
+func (me *Metrics) RecordOptOutMatch(entryType OptOutEntryType) {
+	me.OptOutMatchMeter[entryType].Mark(1)
+}
+
+// RecordGvlListLatest implements a part of the MetricsEngine interface. The gauges are registered for the spec
+// versions as they are cached, and the staleness is the time since the fetch timestamp.
+func (me *Metrics) RecordGvlListLatest(specVersion uint16, listVersion uint16, fetched time.Time) {
+	metrics.GetOrRegisterGauge(fmt.Sprintf("gvl.v%d.latest_version", specVersion), me.MetricsRegistry).Update(int64(listVersion))
+	metrics.GetOrRegisterGauge(fmt.Sprintf("gvl.v%d.latest_fetch_timestamp", specVersion), me.MetricsRegistry).Update(fetched.Unix())
+}
+
 func (me *Metrics) RecordImps(labels ImpLabels) {
 	me.ImpMeter.Mark(int64(1))
 	if labels.BannerImps {
@@ -849,6 +896,52 @@ func (me *Metrics) RecordAdapterPrice(labels AdapterLabels, cpm float64) {
 	}
 }
 
+// RecordAdapterAuctionImps implements a part of the MetricsEngine interface. Records the imps a bidder was offered in the auction
+func (me *Metrics) RecordAdapterAuctionImps(adapter openrtb_ext.BidderName, pubID string, imps int) {
+	lowercaseAdapter := strings.ToLower(string(adapter))
+	am, ok := me.AdapterMetrics[lowercaseAdapter]
+	if !ok {
+		logger.Errorf("Trying to run adapter auction imps metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	// Adapter metrics
+	am.AuctionImpsMeter.Mark(int64(imps))
+	// Account-Adapter metrics
+	if aam, ok := me.getAccountMetrics(pubID).adapterMetrics[lowercaseAdapter]; ok {
+		aam.AuctionImpsMeter.Mark(int64(imps))
+	}
+}
+
+// RecordAuctionBid implements a part of the MetricsEngine interface. Records a bid which took part in the auction
+func (me *Metrics) RecordAuctionBid(labels AuctionBidLabels, cpm float64, floorRatio float64) {
+	lowercaseAdapter := strings.ToLower(string(labels.Adapter))
+	am, ok := me.AdapterMetrics[lowercaseAdapter]
+	if !ok {
+		logger.Errorf("Trying to run adapter auction bid metrics on %s: adapter metrics not found", string(labels.Adapter))
+		return
+	}
+	// Adapter metrics
+	recordAuctionBid(am, labels, cpm, floorRatio)
+	// Account-Adapter metrics
+	if aam, ok := me.getAccountMetrics(labels.PubID).adapterMetrics[lowercaseAdapter]; ok {
+		recordAuctionBid(aam, labels, cpm, floorRatio)
+	}
+}
+
+func recordAuctionBid(am *AdapterMetrics, labels AuctionBidLabels, cpm float64, floorRatio float64) {
+	am.AuctionBidsMeter.Mark(1)
+	if labels.Deal {
+		am.AuctionDealBidsMeter.Mark(1)
+	}
+	if labels.Won {
+		am.AuctionWinsMeter.Mark(1)
+	}
+	am.AuctionCPMHistogram.Update(int64(cpm * 1000))
+	if floorRatio > 0 {
+		am.AuctionFloorRatioHistogram.Update(int64(floorRatio * 100))
+	}
+}
+
 // RecordAdapterTime implements a part of the MetricsEngine interface. Records the adapter response time
 func (me *Metrics) RecordAdapterTime(labels AdapterLabels, length time.Duration) {
 	adapterStr := string(labels.Adapter)
@@ -988,6 +1081,19 @@ func (me *Metrics) RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderN
 	am.BuyerUIDScrubbed.Mark(1)
 }
 
+// RecordAdapterEIDsRemoved implements a part of the MetricsEngine interface. The source is not recorded, to keep the
+// number of metrics bounded.
+func (me *Metrics) RecordAdapterEIDsRemoved(adapterName openrtb_ext.BidderName, source string, count int) {
+	adapterStr := adapterName.String()
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		logger.Errorf("Trying to log adapter eids removed metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	am.EIDsRemovedMeter.Mark(int64(count))
+}
+
 func (me *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName) {
 	adapterStr := string(adapterName)
 	if me.MetricsDisabled.AdapterGDPRRequestBlocked {
@@ -35,6 +35,7 @@ func TestNewMetrics(t *testing.T) {
 	ensureContains(t, registry, "setuid_requests.opt_out", m.SetUidStatusMeter[SetUidOptOut])
 	ensureContains(t, registry, "setuid_requests.gdpr_blocked_host_cookie", m.SetUidStatusMeter[SetUidGDPRHostCookieBlocked])
 	ensureContains(t, registry, "setuid_requests.syncer_unknown", m.SetUidStatusMeter[SetUidSyncerUnknown])
+	ensureContains(t, registry, "setuid_requests.id_store_error", m.SetUidStatusMeter[SetUidIDStoreError])
 	ensureContains(t, registry, "stored_responses", m.StoredResponsesMeter)
 	ensureContains(t, registry, "gvl_requests", m.GvlListRequestsMeter)
 
@@ -1215,6 +1216,63 @@ func TestRecordAdapterPrice(t *testing.T) {
 	assert.Equal(t, m.getAccountMetrics(pubID).adapterMetrics[lowerCaseAdapterName].PriceHistogram.Max(), int64(1000))
 }
 
+func TestRecordAuctionBid(t *testing.T) {
+	registry := metrics.NewRegistry()
+	adapter := "AnyName"
+	lowerCaseAdapterName := "anyname"
+	pubID := "pub1"
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName(adapter), openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterAuctionImps(openrtb_ext.BidderName(adapter), pubID, 3)
+	m.RecordAuctionBid(AuctionBidLabels{Adapter: openrtb_ext.BidderName(adapter), PubID: pubID, Deal: true, Won: true}, 2.5, 1.25)
+	m.RecordAuctionBid(AuctionBidLabels{Adapter: openrtb_ext.BidderName(adapter), PubID: pubID}, 0.75, 0)
+
+	for _, am := range []*AdapterMetrics{m.AdapterMetrics[lowerCaseAdapterName], m.getAccountMetrics(pubID).adapterMetrics[lowerCaseAdapterName]} {
+		assert.Equal(t, int64(3), am.AuctionImpsMeter.Count())
+		assert.Equal(t, int64(2), am.AuctionBidsMeter.Count())
+		assert.Equal(t, int64(1), am.AuctionDealBidsMeter.Count())
+		assert.Equal(t, int64(1), am.AuctionWinsMeter.Count())
+		assert.Equal(t, int64(2500), am.AuctionCPMHistogram.Max())
+		assert.Equal(t, int64(750), am.AuctionCPMHistogram.Min())
+		assert.Equal(t, int64(1), am.AuctionFloorRatioHistogram.Count())
+		assert.Equal(t, int64(125), am.AuctionFloorRatioHistogram.Max())
+	}
+}
+
+func TestRecordGvlListLatest(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, nil, config.DisabledMetrics{}, nil, nil)
+
+	fetched := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m.RecordGvlListLatest(3, 42, fetched)
+
+	assert.Equal(t, int64(42), registry.Get("gvl.v3.latest_version").(metrics.Gauge).Value())
+	assert.Equal(t, fetched.Unix(), registry.Get("gvl.v3.latest_fetch_timestamp").(metrics.Gauge).Value())
+}
+
+func TestRecordAdapterEIDsRemoved(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterEIDsRemoved(openrtb_ext.BidderAppnexus, "liveramp.com", 2)
+	m.RecordAdapterEIDsRemoved(openrtb_ext.BidderName("unknown"), "liveramp.com", 1)
+
+	assert.Equal(t, int64(2), m.AdapterMetrics[string(openrtb_ext.BidderAppnexus)].EIDsRemovedMeter.Count())
+}
+
+func TestRecordOptOutMatch(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordOptOutMatch(OptOutEntryIFA)
+	m.RecordOptOutMatch(OptOutEntryIFA)
+	m.RecordOptOutMatch(OptOutEntryEID)
+
+	assert.Equal(t, int64(2), registry.Get("optout.match.ifa").(metrics.Meter).Count())
+	assert.Equal(t, int64(0), registry.Get("optout.match.hem").(metrics.Meter).Count())
+	assert.Equal(t, int64(1), registry.Get("optout.match.eid").(metrics.Meter).Count())
+}
+
 func TestRecordAdapterTime(t *testing.T) {
 	registry := metrics.NewRegistry()
 	syncerKeys := []string{"foo"}
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"context"
 	"time"
 
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
@@ -27,6 +28,14 @@ type AdapterLabels struct {
 	AdapterErrors map[AdapterError]struct{}
 }
 
+// AuctionBidLabels defines the labels describing a bid which took part in the auction of Prebid Server.
+type AuctionBidLabels struct {
+	Adapter openrtb_ext.BidderName
+	PubID   string // exchange specific ID, so we cannot compile in values
+	Deal    bool
+	Won     bool // the bid is the highest of its imp, across all the bidders
+}
+
 // OverheadType: overhead type enumeration
 type OverheadType string
 
@@ -98,6 +107,23 @@ func StoredDataTypes() []StoredDataType {
 	}
 }
 
+// OptOutEntryType is the type of the entry of the opt-out registry a request matched
+type OptOutEntryType string
+
+const (
+	OptOutEntryIFA         OptOutEntryType = "ifa"
+	OptOutEntryHashedEmail OptOutEntryType = "hem"
+	OptOutEntryEID         OptOutEntryType = "eid"
+)
+
+func OptOutEntryTypes() []OptOutEntryType {
+	return []OptOutEntryType{
+		OptOutEntryIFA,
+		OptOutEntryHashedEmail,
+		OptOutEntryEID,
+	}
+}
+
 type StoredDataFetchType string
 
 const (
@@ -123,12 +149,14 @@ type StoredDataError string
 const (
 	StoredDataErrorNetwork   StoredDataError = "network"
 	StoredDataErrorUndefined StoredDataError = "undefined"
+	StoredDataErrorInvalid   StoredDataError = "invalid"
 )
 
 func StoredDataErrors() []StoredDataError {
 	return []StoredDataError{
 		StoredDataErrorNetwork,
 		StoredDataErrorUndefined,
+		StoredDataErrorInvalid,
 	}
 }
 
@@ -330,6 +358,14 @@ const (
 	// CacheMiss represents a cache miss i.e that key wasn't found in cache
 	// and had to be fetched from the backend
 	CacheMiss CacheResult = "miss"
+	// CacheNotFoundHit represents a key which was remembered in cache as unknown to the backend
+	CacheNotFoundHit CacheResult = "not_found_hit"
+	// CacheStaleHit represents a key which was found in cache past its TTL, and was served
+	// while it is refreshed in the background
+	CacheStaleHit CacheResult = "stale_hit"
+	// CacheStaleOnError represents a key which the backend failed to return, and was served
+	// from cache past its TTL instead
+	CacheStaleOnError CacheResult = "stale_on_error"
 )
 
 // CacheResults returns possible cache results i.e. cache hit or miss
@@ -337,6 +373,9 @@ func CacheResults() []CacheResult {
 	return []CacheResult{
 		CacheHit,
 		CacheMiss,
+		CacheNotFoundHit,
+		CacheStaleHit,
+		CacheStaleOnError,
 	}
 }
 
@@ -424,6 +463,7 @@ const (
 	SetUidAccountConfigMalformed SetUidStatus = "acct_config_malformed"
 	SetUidAccountInvalid         SetUidStatus = "acct_invalid"
 	SetUidSyncerUnknown          SetUidStatus = "syncer_unknown"
+	SetUidIDStoreError           SetUidStatus = "id_store_error"
 )
 
 // SetUidStatuses returns possible setuid statuses.
@@ -437,6 +477,7 @@ func SetUidStatuses() []SetUidStatus {
 		SetUidAccountConfigMalformed,
 		SetUidAccountInvalid,
 		SetUidSyncerUnknown,
+		SetUidIDStoreError,
 	}
 }
 
@@ -479,6 +520,8 @@ type MetricsEngine interface {
 	RecordAdapterBidReceived(labels AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool)
 	RecordAdapterPrice(labels AdapterLabels, cpm float64)
 	RecordAdapterTime(labels AdapterLabels, length time.Duration)
+	RecordAdapterAuctionImps(adapter openrtb_ext.BidderName, pubID string, imps int)
+	RecordAuctionBid(labels AuctionBidLabels, cpm float64, floorRatio float64) // cpm in the reference currency, floorRatio is 0 if the imp had no floor
 	RecordCookieSync(status CookieSyncStatus)
 	RecordSyncerRequest(key string, status SyncerCookieSyncStatus)
 	RecordSetUid(status SetUidStatus)
@@ -494,9 +537,12 @@ type MetricsEngine interface {
 	RecordRequestPrivacy(privacy PrivacyLabels)
 	RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderName)
 	RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName)
+	RecordAdapterEIDsRemoved(adapterName openrtb_ext.BidderName, source string, count int) // the user EIDs of the source removed by the account EID permissions
+	RecordOptOutMatch(entryType OptOutEntryType)
 	RecordDebugRequest(debugEnabled bool, pubId string)
 	RecordStoredResponse(pubId string)
 	RecordGvlListRequest()
+	RecordGvlListLatest(specVersion uint16, listVersion uint16, fetched time.Time) // the latest cached vendor list of the spec version, fetched from the GVL at the given time
 	RecordAdsCertReq(success bool)
 	RecordAdsCertSignTime(adsCertSignTime time.Duration)
 	RecordBidValidationCreativeSizeError(adapter openrtb_ext.BidderName, account string)
@@ -514,3 +560,28 @@ type MetricsEngine interface {
 	RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName)
 	RecordAdapterConnectionDialTime(adapterName openrtb_ext.BidderName, dialStartTime time.Duration)
 }
+
+// ContextMetricsEngine is implemented by the metrics engines which link the timings of a request to its trace, so the
+// slow requests can be looked up from their metrics.
+type ContextMetricsEngine interface {
+	RecordRequestTimeWithContext(ctx context.Context, labels Labels, length time.Duration)
+	RecordAdapterTimeWithContext(ctx context.Context, labels AdapterLabels, length time.Duration)
+}
+
+// RecordRequestTime records the time of a request on me, with the trace of ctx if me is a ContextMetricsEngine
+func RecordRequestTime(ctx context.Context, me MetricsEngine, labels Labels, length time.Duration) {
+	if contextEngine, ok := me.(ContextMetricsEngine); ok {
+		contextEngine.RecordRequestTimeWithContext(ctx, labels, length)
+		return
+	}
+	me.RecordRequestTime(labels, length)
+}
+
+// RecordAdapterTime records the time of an adapter request on me, with the trace of ctx if me is a ContextMetricsEngine
+func RecordAdapterTime(ctx context.Context, me MetricsEngine, labels AdapterLabels, length time.Duration) {
+	if contextEngine, ok := me.(ContextMetricsEngine); ok {
+		contextEngine.RecordAdapterTimeWithContext(ctx, labels, length)
+		return
+	}
+	me.RecordAdapterTime(labels, length)
+}
@@ -96,6 +96,16 @@ func (me *MetricsEngineMock) RecordAdapterTime(labels AdapterLabels, length time
 	me.Called(labels, length)
 }
 
+// RecordAdapterAuctionImps mock
+func (me *MetricsEngineMock) RecordAdapterAuctionImps(adapter openrtb_ext.BidderName, pubID string, imps int) {
+	me.Called(adapter, pubID, imps)
+}
+
+// RecordAuctionBid mock
+func (me *MetricsEngineMock) RecordAuctionBid(labels AuctionBidLabels, cpm float64, floorRatio float64) {
+	me.Called(labels, cpm, floorRatio)
+}
+
 // RecordOverheadTime mock
 func (me *MetricsEngineMock) RecordOverheadTime(overhead OverheadType, length time.Duration) {
 	me.Called(overhead, length)
@@ -166,6 +176,16 @@ func (me *MetricsEngineMock) RecordAdapterGDPRRequestBlocked(adapterName openrtb
 	me.Called(adapterName)
 }
 
+// RecordAdapterEIDsRemoved mock
+func (me *MetricsEngineMock) RecordAdapterEIDsRemoved(adapterName openrtb_ext.BidderName, source string, count int) {
+	me.Called(adapterName, source, count)
+}
+
+// RecordOptOutMatch mock
+func (me *MetricsEngineMock) RecordOptOutMatch(entryType OptOutEntryType) {
+	me.Called(entryType)
+}
+
 // RecordDebugRequest mock
 func (me *MetricsEngineMock) RecordDebugRequest(debugEnabled bool, pubId string) {
 	me.Called(debugEnabled, pubId)
@@ -179,6 +199,10 @@ func (me *MetricsEngineMock) RecordGvlListRequest() {
 	me.Called()
 }
 
+func (me *MetricsEngineMock) RecordGvlListLatest(specVersion uint16, listVersion uint16, fetched time.Time) {
+	me.Called(specVersion, listVersion, fetched)
+}
+
 func (me *MetricsEngineMock) RecordAdsCertReq(success bool) {
 	me.Called(success)
 }
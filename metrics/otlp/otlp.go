@@ -0,0 +1,786 @@
+package otlpmetrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/prebid/prebid-server/v3/metrics/otlp"
+
+// Metrics defines the OpenTelemetry metrics backing the MetricsEngine implementation. The metrics are named as the
+// Prometheus ones, without the unit suffix the collectors add when translating them to Prometheus.
+type Metrics struct {
+	// General Metrics
+	tmaxTimeout                  metric.Int64Counter
+	connectionsClosed            metric.Int64Counter
+	connectionsError             metric.Int64Counter
+	connectionsOpened            metric.Int64Counter
+	cookieSync                   metric.Int64Counter
+	setUid                       metric.Int64Counter
+	impressions                  metric.Int64Counter
+	prebidCacheWriteTimer        metric.Float64Histogram
+	requests                     metric.Int64Counter
+	requestsSize                 metric.Float64Histogram
+	debugRequests                metric.Int64Counter
+	requestsTimer                metric.Float64Histogram
+	requestsQueueTimer           metric.Float64Histogram
+	requestsWithoutCookie        metric.Int64Counter
+	storedImpressionsCacheResult metric.Int64Counter
+	storedRequestCacheResult     metric.Int64Counter
+	accountCacheResult           metric.Int64Counter
+	storedDataFetchTimers        map[metrics.StoredDataType]metric.Float64Histogram
+	storedDataErrors             map[metrics.StoredDataType]metric.Int64Counter
+	timeoutNotifications         metric.Int64Counter
+	dnsLookupTimer               metric.Float64Histogram
+	tlsHandhakeTimer             metric.Float64Histogram
+	privacyCCPA                  metric.Int64Counter
+	privacyCOPPA                 metric.Int64Counter
+	privacyLMT                   metric.Int64Counter
+	privacyTCF                   metric.Int64Counter
+	storedResponses              metric.Int64Counter
+	gvlListRequests              metric.Int64Counter
+	optOutMatches                metric.Int64Counter
+	gvlListLatestVersion         metric.Int64Gauge
+	gvlListLatestFetch           metric.Int64Gauge
+	adsCertRequests              metric.Int64Counter
+	adsCertSignTimer             metric.Float64Histogram
+	bidderServerResponseTimer    metric.Float64Histogram
+
+	// Adapter Metrics
+	adapterBids                           metric.Int64Counter
+	adapterErrors                         metric.Int64Counter
+	adapterPanics                         metric.Int64Counter
+	adapterPrices                         metric.Float64Histogram
+	adapterRequests                       metric.Int64Counter
+	overheadTimer                         metric.Float64Histogram
+	adapterRequestsTimer                  metric.Float64Histogram
+	adapterReusedConnections              metric.Int64Counter
+	adapterCreatedConnections             metric.Int64Counter
+	adapterConnectionWaitTime             metric.Float64Histogram
+	adapterScrubbedBuyerUIDs              metric.Int64Counter
+	adapterGDPRBlockedRequests            metric.Int64Counter
+	adapterEIDsRemoved                    metric.Int64Counter
+	adapterBidResponseValidationSizeError metric.Int64Counter
+	adapterBidResponseValidationSizeWarn  metric.Int64Counter
+	adapterBidResponseSecureMarkupError   metric.Int64Counter
+	adapterBidResponseSecureMarkupWarn    metric.Int64Counter
+	adapterThrottled                      metric.Int64Counter
+	adapterConnectionDialErrors           metric.Int64Counter
+	adapterConnectionDialTime             metric.Float64Histogram
+	adapterAuctionImps                    metric.Int64Counter
+	adapterAuctionBids                    metric.Int64Counter
+	adapterAuctionBidCPM                  metric.Float64Histogram
+	adapterAuctionBidFloorRatio           metric.Float64Histogram
+
+	// Syncer Metrics
+	syncerRequests metric.Int64Counter
+	syncerSets     metric.Int64Counter
+
+	// Account Metrics
+	accountRequests                       metric.Int64Counter
+	accountDebugRequests                  metric.Int64Counter
+	accountStoredResponses                metric.Int64Counter
+	accountBidResponseValidationSizeError metric.Int64Counter
+	accountBidResponseValidationSizeWarn  metric.Int64Counter
+	accountBidResponseSecureMarkupError   metric.Int64Counter
+	accountBidResponseSecureMarkupWarn    metric.Int64Counter
+
+	// Module Metrics, labeled by module and stage
+	moduleDuration        metric.Float64Histogram
+	moduleCalls           metric.Int64Counter
+	moduleFailures        metric.Int64Counter
+	moduleSuccessNoops    metric.Int64Counter
+	moduleSuccessUpdates  metric.Int64Counter
+	moduleSuccessRejects  metric.Int64Counter
+	moduleExecutionErrors metric.Int64Counter
+	moduleTimeouts        metric.Int64Counter
+
+	metricsDisabled config.DisabledMetrics
+}
+
+const (
+	accountLabel         = attribute.Key("account")
+	adapterErrorLabel    = attribute.Key("adapter_error")
+	adapterLabel         = attribute.Key("adapter")
+	cacheResultLabel     = attribute.Key("cache_result")
+	connectionErrorLabel = attribute.Key("connection_error")
+	cookieLabel          = attribute.Key("cookie")
+	dealLabel            = attribute.Key("deal")
+	eidSourceLabel       = attribute.Key("eid_source")
+	entryTypeLabel       = attribute.Key("entry_type")
+	hasBidsLabel         = attribute.Key("has_bids")
+	isAudioLabel         = attribute.Key("audio")
+	isBannerLabel        = attribute.Key("banner")
+	isNativeLabel        = attribute.Key("native")
+	isVideoLabel         = attribute.Key("video")
+	markupDeliveryLabel  = attribute.Key("delivery")
+	moduleLabel          = attribute.Key("module")
+	optOutLabel          = attribute.Key("opt_out")
+	overheadTypeLabel    = attribute.Key("overhead_type")
+	requestStatusLabel   = attribute.Key("request_status")
+	requestTypeLabel     = attribute.Key("request_type")
+	requestEndpointLabel = attribute.Key("request_size")
+	specVersionLabel     = attribute.Key("spec_version")
+	stageLabel           = attribute.Key("stage")
+	statusLabel          = attribute.Key("status")
+	successLabel         = attribute.Key("success")
+	syncerLabel          = attribute.Key("syncer")
+	versionLabel         = attribute.Key("version")
+	wonLabel             = attribute.Key("won")
+
+	sourceLabel              = attribute.Key("source")
+	storedDataFetchTypeLabel = attribute.Key("stored_data_fetch_type")
+	storedDataErrorLabel     = attribute.Key("stored_data_error")
+)
+
+const (
+	connectionAcceptError = "accept"
+	connectionCloseError  = "close"
+)
+
+const (
+	markupDeliveryAdm  = "adm"
+	markupDeliveryNurl = "nurl"
+)
+
+const (
+	requestSuccessLabel = "requestAcceptedLabel"
+	requestRejectLabel  = "requestRejectedLabel"
+)
+
+const (
+	requestSuccessful = "ok"
+	requestFailed     = "failed"
+)
+
+const sourceRequest = "request"
+
+const unitSeconds = "s"
+
+var (
+	standardTimeBuckets      = []float64{0.05, 0.1, 0.15, 0.20, 0.25, 0.3, 0.4, 0.5, 0.75, 1}
+	cacheWriteTimeBuckets    = []float64{0.001, 0.002, 0.005, 0.01, 0.025, 0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 1}
+	priceBuckets             = []float64{250, 500, 750, 1000, 1500, 2000, 2500, 3000, 3500, 4000}
+	queuedRequestTimeBuckets = []float64{0, 1, 5, 30, 60, 120, 180, 240, 300}
+	overheadTimeBuckets      = []float64{0.05, 0.06, 0.07, 0.08, 0.09, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1}
+	requestSizeBuckets       = []float64{100, 500, 750, 1000, 2000, 4000, 7000, 10000, 15000, 20000, 50000, 75000}
+	dialTimeBuckets          = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 15, 30}
+)
+
+// instruments creates the instruments of a meter, keeping the errors met
+type instruments struct {
+	meter metric.Meter
+	errs  []error
+}
+
+func (i *instruments) counter(name, description string) metric.Int64Counter {
+	counter, err := i.meter.Int64Counter(name, metric.WithDescription(description))
+	if err != nil {
+		i.errs = append(i.errs, err)
+	}
+	return counter
+}
+
+func (i *instruments) gauge(name, description, unit string) metric.Int64Gauge {
+	gauge, err := i.meter.Int64Gauge(name, metric.WithDescription(description), metric.WithUnit(unit))
+	if err != nil {
+		i.errs = append(i.errs, err)
+	}
+	return gauge
+}
+
+func (i *instruments) histogram(name, description, unit string, buckets []float64) metric.Float64Histogram {
+	histogram, err := i.meter.Float64Histogram(name,
+		metric.WithDescription(description),
+		metric.WithUnit(unit),
+		metric.WithExplicitBucketBoundaries(buckets...))
+	if err != nil {
+		i.errs = append(i.errs, err)
+	}
+	return histogram
+}
+
+// NewMetrics creates the OpenTelemetry metrics on the meters of provider
+func NewMetrics(provider metric.MeterProvider, disabledMetrics config.DisabledMetrics, auctionMetrics config.AuctionMetrics) (*Metrics, error) {
+	i := instruments{meter: provider.Meter(meterName)}
+	m := Metrics{metricsDisabled: disabledMetrics}
+
+	m.connectionsClosed = i.counter("connections_closed",
+		"Count of successful connections closed to Prebid Server.")
+	m.connectionsError = i.counter("connections_error",
+		"Count of errors for connection open and close attempts to Prebid Server labeled by type.")
+	m.connectionsOpened = i.counter("connections_opened",
+		"Count of successful connections opened to Prebid Server.")
+	m.tmaxTimeout = i.counter("tmax_timeout",
+		"Count of requests rejected due to Tmax timeout exceed.")
+	m.cookieSync = i.counter("cookie_sync_requests",
+		"Count of cookie sync requests to Prebid Server.")
+	m.setUid = i.counter("setuid_requests",
+		"Count of set uid requests to Prebid Server.")
+	m.impressions = i.counter("impressions_requests",
+		"Count of requested impressions to Prebid Server labeled by type.")
+	m.prebidCacheWriteTimer = i.histogram("prebidcache_write_time",
+		"Seconds to write to Prebid Cache labeled by success or failure. Failure timing is limited by Prebid Server enforced timeouts.",
+		unitSeconds, cacheWriteTimeBuckets)
+	m.requests = i.counter("requests",
+		"Count of total requests to Prebid Server labeled by type and status.")
+	m.requestsSize = i.histogram("request_size",
+		"Count that keeps track of incoming request size in bytes labeled by endpoint.",
+		"By", requestSizeBuckets)
+	m.debugRequests = i.counter("debug_requests",
+		"Count of total requests to Prebid Server that have debug enabled")
+	m.requestsTimer = i.histogram("request_time",
+		"Seconds to resolve successful Prebid Server requests labeled by type.",
+		unitSeconds, standardTimeBuckets)
+	m.requestsWithoutCookie = i.counter("requests_without_cookie",
+		"Count of total requests to Prebid Server without a cookie labeled by type.")
+	m.storedImpressionsCacheResult = i.counter("stored_impressions_cache_performance",
+		"Count of stored impression cache requests attempts by hits or miss.")
+	m.storedRequestCacheResult = i.counter("stored_request_cache_performance",
+		"Count of stored request cache requests attempts by hits or miss.")
+	m.accountCacheResult = i.counter("account_cache_performance",
+		"Count of account cache lookups by hits or miss.")
+
+	m.storedDataFetchTimers = make(map[metrics.StoredDataType]metric.Float64Histogram)
+	m.storedDataErrors = make(map[metrics.StoredDataType]metric.Int64Counter)
+	for _, dataType := range metrics.StoredDataTypes() {
+		m.storedDataFetchTimers[dataType] = i.histogram(fmt.Sprintf("stored_%s_fetch_time", dataType),
+			fmt.Sprintf("Seconds to fetch stored %s data labeled by fetch type", dataType),
+			unitSeconds, standardTimeBuckets)
+		m.storedDataErrors[dataType] = i.counter(fmt.Sprintf("stored_%s_errors", dataType),
+			fmt.Sprintf("Count of stored %s data errors by error type", dataType))
+	}
+
+	m.timeoutNotifications = i.counter("timeout_notification",
+		"Count of timeout notifications triggered, and if they were successfully sent.")
+	m.dnsLookupTimer = i.histogram("dns_lookup_time",
+		"Seconds to resolve DNS",
+		unitSeconds, standardTimeBuckets)
+	m.tlsHandhakeTimer = i.histogram("tls_handshake_time",
+		"Seconds to perform TLS Handshake",
+		unitSeconds, standardTimeBuckets)
+	m.privacyCCPA = i.counter("privacy_ccpa",
+		"Count of total requests to Prebid Server where CCPA was provided by source and opt-out .")
+	m.privacyCOPPA = i.counter("privacy_coppa",
+		"Count of total requests to Prebid Server where the COPPA flag was set by source")
+	m.privacyTCF = i.counter("privacy_tcf",
+		"Count of TCF versions for requests where GDPR was enforced by source and version.")
+	m.privacyLMT = i.counter("privacy_lmt",
+		"Count of total requests to Prebid Server where the LMT flag was set by source")
+	if !m.metricsDisabled.AdapterBuyerUIDScrubbed {
+		m.adapterScrubbedBuyerUIDs = i.counter("adapter_buyeruids_scrubbed",
+			"Count of total bidder requests with a scrubbed buyeruid due to a privacy policy")
+	}
+	if !m.metricsDisabled.AdapterGDPRRequestBlocked {
+		m.adapterGDPRBlockedRequests = i.counter("adapter_gdpr_requests_blocked",
+			"Count of total bidder requests blocked due to unsatisfied GDPR purpose 2 legal basis")
+	}
+	m.adapterEIDsRemoved = i.counter("adapter_eids_removed",
+		"Count of user EIDs removed from bidder requests by the account EID permissions, by EID source")
+	m.storedResponses = i.counter("stored_responses",
+		"Count of total requests to Prebid Server that have stored responses")
+	m.gvlListRequests = i.counter("gvl_requests",
+		"Count number of times GVL list is fetched")
+	m.optOutMatches = i.counter("optout_matches",
+		"Count of requests whose user matched an entry of the opt-out registry, by entry type")
+	m.gvlListLatestVersion = i.gauge("gvl_latest_version",
+		"Version of the latest cached GVL list labeled by spec version.", "")
+	m.gvlListLatestFetch = i.gauge("gvl_latest_fetch_timestamp",
+		"Unix time the latest cached GVL list was fetched labeled by spec version.", unitSeconds)
+	m.adapterBids = i.counter("adapter_bids",
+		"Count of bids labeled by adapter and markup delivery type (adm or nurl).")
+	m.adapterErrors = i.counter("adapter_errors",
+		"Count of errors labeled by adapter and error type.")
+	m.adapterPanics = i.counter("adapter_panics",
+		"Count of panics labeled by adapter.")
+	m.adapterPrices = i.histogram("adapter_prices",
+		"Monetary value of the bids labeled by adapter.",
+		"", priceBuckets)
+	m.adapterRequests = i.counter("adapter_requests",
+		"Count of requests labeled by adapter, if has a cookie, and if it resulted in bids.")
+	if !m.metricsDisabled.AdapterConnectionMetrics {
+		m.adapterCreatedConnections = i.counter("adapter_connection_created",
+			"Count that keeps track of new connections when contacting adapter bidder endpoints.")
+		m.adapterReusedConnections = i.counter("adapter_connection_reused",
+			"Count that keeps track of reused connections when contacting adapter bidder endpoints.")
+		m.adapterConnectionWaitTime = i.histogram("adapter_connection_wait",
+			"Seconds from when the connection was requested until it is either created or reused",
+			unitSeconds, standardTimeBuckets)
+		if !m.metricsDisabled.AdapterConnectionDialMetrics {
+			m.adapterConnectionDialErrors = i.counter("adapter_connection_dial_errors",
+				"Count when a connection dial returns an error.")
+			m.adapterConnectionDialTime = i.histogram("adapter_connection_dial_time",
+				"Seconds adapter bidder connection dial lasted",
+				unitSeconds, dialTimeBuckets)
+		}
+	}
+	m.adapterBidResponseValidationSizeError = i.counter("adapter_response_validation_size_err",
+		"Count that tracks number of bids removed from bid response that had a creative size greater than maxWidth/maxHeight")
+	m.adapterBidResponseValidationSizeWarn = i.counter("adapter_response_validation_size_warn",
+		"Count that tracks number of bids removed from bid response that had a creative size greater than maxWidth/maxHeight (warn)")
+	m.adapterBidResponseSecureMarkupError = i.counter("adapter_response_validation_secure_err",
+		"Count that tracks number of bids removed from bid response that had a invalid bidAdm")
+	m.adapterBidResponseSecureMarkupWarn = i.counter("adapter_response_validation_secure_warn",
+		"Count that tracks number of bids removed from bid response that had a invalid bidAdm (warn)")
+	m.adapterAuctionImps = i.counter("adapter_auction_imps",
+		"Count of imps offered to the adapters in the auctions labeled by adapter.")
+	m.adapterAuctionBids = i.counter("adapter_auction_bids",
+		"Count of bids which took part in the auctions labeled by adapter, if a deal, and if it won its imp.")
+	m.adapterAuctionBidCPM = i.histogram("adapter_auction_bid_cpm",
+		"CPM of the bids which took part in the auctions, in the reference currency, labeled by adapter and if a deal.",
+		"", auctionMetrics.PriceBuckets)
+	m.adapterAuctionBidFloorRatio = i.histogram("adapter_auction_bid_floor_ratio",
+		"Ratio of the bids which took part in the auctions to the floor of their imp labeled by adapter.",
+		"", auctionMetrics.FloorRatioBuckets)
+	m.adapterThrottled = i.counter("adapter_throttled",
+		"Count of requests throttled labeled by adapter.")
+	m.overheadTimer = i.histogram("overhead_time",
+		"Seconds to prepare adapter request or resolve adapter response",
+		unitSeconds, overheadTimeBuckets)
+	m.adapterRequestsTimer = i.histogram("adapter_request_time",
+		"Seconds to resolve each successful request labeled by adapter.",
+		unitSeconds, standardTimeBuckets)
+	m.bidderServerResponseTimer = i.histogram("bidder_server_response_time",
+		"Duration needed to send HTTP request and receive response back from bidder server.",
+		unitSeconds, standardTimeBuckets)
+	m.syncerRequests = i.counter("syncer_requests",
+		"Count of cookie sync requests where a syncer is a candidate to be synced labeled by syncer key and status.")
+	m.syncerSets = i.counter("syncer_sets",
+		"Count of setuid set requests for a syncer labeled by syncer key and status.")
+	m.accountRequests = i.counter("account_requests",
+		"Count of total requests to Prebid Server labeled by account.")
+	m.accountDebugRequests = i.counter("account_debug_requests",
+		"Count of total requests to Prebid Server that have debug enabled labled by account")
+	m.accountBidResponseValidationSizeError = i.counter("account_response_validation_size_err",
+		"Count that tracks number of bids removed from bid response that had a creative size greater than maxWidth/maxHeight labeled by account (enforce) ")
+	m.accountBidResponseValidationSizeWarn = i.counter("account_response_validation_size_warn",
+		"Count that tracks number of bids removed from bid response that had a creative size greater than maxWidth/maxHeight labeled by account (warn)")
+	m.accountBidResponseSecureMarkupError = i.counter("account_response_validation_secure_err",
+		"Count that tracks number of bids removed from bid response that had a invalid bidAdm labeled by account (enforce) ")
+	m.accountBidResponseSecureMarkupWarn = i.counter("account_response_validation_secure_warn",
+		"Count that tracks number of bids removed from bid response that had a invalid bidAdm labeled by account (warn)")
+	m.requestsQueueTimer = i.histogram("request_queue_time",
+		"Seconds request was waiting in queue",
+		unitSeconds, queuedRequestTimeBuckets)
+	m.accountStoredResponses = i.counter("account_stored_responses",
+		"Count of total requests to Prebid Server that have stored responses labled by account")
+	m.adsCertSignTimer = i.histogram("ads_cert_sign_time",
+		"Seconds to generate an AdsCert header",
+		unitSeconds, standardTimeBuckets)
+	m.adsCertRequests = i.counter("ads_cert_requests",
+		"Count of AdsCert request, and if they were successfully sent.")
+
+	m.moduleDuration = i.histogram("modules_duration",
+		"Amount of seconds a module processed a hook labeled by module and stage name.",
+		unitSeconds, standardTimeBuckets)
+	m.moduleCalls = i.counter("modules_called",
+		"Count of module calls labeled by module and stage name.")
+	m.moduleFailures = i.counter("modules_failed",
+		"Count of module fails labeled by module and stage name.")
+	m.moduleSuccessNoops = i.counter("modules_success_noops",
+		"Count of module successful noops labeled by module and stage name.")
+	m.moduleSuccessUpdates = i.counter("modules_success_updates",
+		"Count of module successful updates labeled by module and stage name.")
+	m.moduleSuccessRejects = i.counter("modules_success_rejects",
+		"Count of module successful rejects labeled by module and stage name.")
+	m.moduleExecutionErrors = i.counter("modules_execution_errors",
+		"Count of module execution errors labeled by module and stage name.")
+	m.moduleTimeouts = i.counter("modules_timeouts",
+		"Count of module timeouts labeled by module and stage name.")
+
+	if len(i.errs) > 0 {
+		return nil, errors.Join(i.errs...)
+	}
+	return &m, nil
+}
+
+func inc(counter metric.Int64Counter, attrs ...attribute.KeyValue) {
+	counter.Add(context.Background(), 1, metric.WithAttributes(attrs...))
+}
+
+func observe(histogram metric.Float64Histogram, value float64, attrs ...attribute.KeyValue) {
+	histogram.Record(context.Background(), value, metric.WithAttributes(attrs...))
+}
+
+func adapterAttr(adapter openrtb_ext.BidderName) attribute.KeyValue {
+	return adapterLabel.String(strings.ToLower(string(adapter)))
+}
+
+// adapterAttrs returns the attributes of the adapter metrics, with the account unless the account adapter details
+// are disabled
+func (m *Metrics) adapterAttrs(labels metrics.AdapterLabels, attrs ...attribute.KeyValue) []attribute.KeyValue {
+	attrs = append(attrs, adapterAttr(labels.Adapter))
+	if !m.metricsDisabled.AccountAdapterDetails && labels.PubID != "" && labels.PubID != metrics.PublisherUnknown {
+		attrs = append(attrs, accountLabel.String(labels.PubID))
+	}
+	return attrs
+}
+
+// moduleAttrs returns the attributes of the module metrics, with the account unless the account modules metrics are
+// disabled
+func (m *Metrics) moduleAttrs(labels metrics.ModuleLabels) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{moduleLabel.String(labels.Module), stageLabel.String(labels.Stage)}
+	if !m.metricsDisabled.AccountModulesMetrics && labels.AccountID != "" && labels.AccountID != metrics.PublisherUnknown {
+		attrs = append(attrs, accountLabel.String(labels.AccountID))
+	}
+	return attrs
+}
+
+func (m *Metrics) RecordConnectionAccept(success bool) {
+	if success {
+		inc(m.connectionsOpened)
+	} else {
+		inc(m.connectionsError, connectionErrorLabel.String(connectionAcceptError))
+	}
+}
+
+func (m *Metrics) RecordTMaxTimeout() {
+	inc(m.tmaxTimeout)
+}
+
+func (m *Metrics) RecordConnectionClose(success bool) {
+	if success {
+		inc(m.connectionsClosed)
+	} else {
+		inc(m.connectionsError, connectionErrorLabel.String(connectionCloseError))
+	}
+}
+
+func (m *Metrics) RecordRequest(labels metrics.Labels) {
+	inc(m.requests, requestTypeLabel.String(string(labels.RType)), requestStatusLabel.String(string(labels.RequestStatus)))
+
+	if labels.RequestSize > 0 && labels.RType != metrics.ReqTypeAMP {
+		endpoint := metrics.GetEndpointFromRequestType(labels.RType)
+		observe(m.requestsSize, float64(labels.RequestSize), requestEndpointLabel.String(string(endpoint)))
+	}
+
+	if labels.CookieFlag == metrics.CookieFlagNo {
+		inc(m.requestsWithoutCookie, requestTypeLabel.String(string(labels.RType)))
+	}
+
+	if labels.PubID != metrics.PublisherUnknown {
+		inc(m.accountRequests, accountLabel.String(labels.PubID))
+	}
+}
+
+func (m *Metrics) RecordDebugRequest(debugEnabled bool, pubID string) {
+	if debugEnabled {
+		inc(m.debugRequests)
+		if !m.metricsDisabled.AccountDebug && pubID != metrics.PublisherUnknown {
+			inc(m.accountDebugRequests, accountLabel.String(pubID))
+		}
+	}
+}
+
+func (m *Metrics) RecordStoredResponse(pubId string) {
+	inc(m.storedResponses)
+	if !m.metricsDisabled.AccountStoredResponses && pubId != metrics.PublisherUnknown {
+		inc(m.accountStoredResponses, accountLabel.String(pubId))
+	}
+}
+
+func (m *Metrics) RecordGvlListRequest() {
+	inc(m.gvlListRequests)
+}
+
+func (m *Metrics) RecordOptOutMatch(entryType metrics.OptOutEntryType) {
+	inc(m.optOutMatches, entryTypeLabel.String(string(entryType)))
+}
+
+func (m *Metrics) RecordGvlListLatest(specVersion uint16, listVersion uint16, fetched time.Time) {
+	attrs := metric.WithAttributes(specVersionLabel.Int(int(specVersion)))
+	m.gvlListLatestVersion.Record(context.Background(), int64(listVersion), attrs)
+	m.gvlListLatestFetch.Record(context.Background(), fetched.Unix(), attrs)
+}
+
+func (m *Metrics) RecordImps(labels metrics.ImpLabels) {
+	inc(m.impressions,
+		isBannerLabel.Bool(labels.BannerImps),
+		isVideoLabel.Bool(labels.VideoImps),
+		isAudioLabel.Bool(labels.AudioImps),
+		isNativeLabel.Bool(labels.NativeImps))
+}
+
+func (m *Metrics) RecordRequestTime(labels metrics.Labels, length time.Duration) {
+	m.RecordRequestTimeWithContext(context.Background(), labels, length)
+}
+
+// RecordRequestTimeWithContext records the time of a successful request, with the trace of ctx as an exemplar
+func (m *Metrics) RecordRequestTimeWithContext(ctx context.Context, labels metrics.Labels, length time.Duration) {
+	if labels.RequestStatus == metrics.RequestStatusOK {
+		m.requestsTimer.Record(ctx, length.Seconds(), metric.WithAttributes(requestTypeLabel.String(string(labels.RType))))
+	}
+}
+
+func (m *Metrics) RecordStoredDataFetchTime(labels metrics.StoredDataLabels, length time.Duration) {
+	if timer, ok := m.storedDataFetchTimers[labels.DataType]; ok {
+		observe(timer, length.Seconds(), storedDataFetchTypeLabel.String(string(labels.DataFetchType)))
+	}
+}
+
+func (m *Metrics) RecordStoredDataError(labels metrics.StoredDataLabels) {
+	if counter, ok := m.storedDataErrors[labels.DataType]; ok {
+		inc(counter, storedDataErrorLabel.String(string(labels.Error)))
+	}
+}
+
+func (m *Metrics) RecordAdapterRequest(labels metrics.AdapterLabels) {
+	inc(m.adapterRequests, m.adapterAttrs(labels,
+		cookieLabel.String(string(labels.CookieFlag)),
+		hasBidsLabel.Bool(labels.AdapterBids == metrics.AdapterBidPresent))...)
+
+	for err := range labels.AdapterErrors {
+		inc(m.adapterErrors, m.adapterAttrs(labels, adapterErrorLabel.String(string(err)))...)
+	}
+}
+
+// Keeps track of created and reused connections to adapter bidders and the time from the
+// connection request, to the connection creation, or reuse from the pool across all engines
+func (m *Metrics) RecordAdapterConnections(adapterName openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration) {
+	if m.metricsDisabled.AdapterConnectionMetrics {
+		return
+	}
+
+	if connWasReused {
+		inc(m.adapterReusedConnections, adapterAttr(adapterName))
+	} else {
+		inc(m.adapterCreatedConnections, adapterAttr(adapterName))
+	}
+	observe(m.adapterConnectionWaitTime, connWaitTime.Seconds(), adapterAttr(adapterName))
+}
+
+func (m *Metrics) RecordDNSTime(dnsLookupTime time.Duration) {
+	observe(m.dnsLookupTimer, dnsLookupTime.Seconds())
+}
+
+func (m *Metrics) RecordTLSHandshakeTime(tlsHandshakeTime time.Duration) {
+	observe(m.tlsHandhakeTimer, tlsHandshakeTime.Seconds())
+}
+
+func (m *Metrics) RecordBidderServerResponseTime(bidderServerResponseTime time.Duration) {
+	observe(m.bidderServerResponseTimer, bidderServerResponseTime.Seconds())
+}
+
+func (m *Metrics) RecordAdapterPanic(labels metrics.AdapterLabels) {
+	inc(m.adapterPanics, adapterAttr(labels.Adapter))
+}
+
+func (m *Metrics) RecordAdapterBidReceived(labels metrics.AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool) {
+	markupDelivery := markupDeliveryNurl
+	if hasAdm {
+		markupDelivery = markupDeliveryAdm
+	}
+	inc(m.adapterBids, m.adapterAttrs(labels, markupDeliveryLabel.String(markupDelivery))...)
+}
+
+func (m *Metrics) RecordAdapterPrice(labels metrics.AdapterLabels, cpm float64) {
+	observe(m.adapterPrices, cpm, m.adapterAttrs(labels)...)
+}
+
+func (m *Metrics) RecordAdapterAuctionImps(adapter openrtb_ext.BidderName, pubID string, imps int) {
+	attrs := m.adapterAttrs(metrics.AdapterLabels{Adapter: adapter, PubID: pubID})
+	m.adapterAuctionImps.Add(context.Background(), int64(imps), metric.WithAttributes(attrs...))
+}
+
+func (m *Metrics) RecordAuctionBid(labels metrics.AuctionBidLabels, cpm float64, floorRatio float64) {
+	adapterLabels := metrics.AdapterLabels{Adapter: labels.Adapter, PubID: labels.PubID}
+	inc(m.adapterAuctionBids, m.adapterAttrs(adapterLabels, dealLabel.Bool(labels.Deal), wonLabel.Bool(labels.Won))...)
+	observe(m.adapterAuctionBidCPM, cpm, m.adapterAttrs(adapterLabels, dealLabel.Bool(labels.Deal))...)
+	if floorRatio > 0 {
+		observe(m.adapterAuctionBidFloorRatio, floorRatio, adapterAttr(labels.Adapter))
+	}
+}
+
+func (m *Metrics) RecordOverheadTime(overhead metrics.OverheadType, duration time.Duration) {
+	observe(m.overheadTimer, duration.Seconds(), overheadTypeLabel.String(overhead.String()))
+}
+
+func (m *Metrics) RecordAdapterTime(labels metrics.AdapterLabels, length time.Duration) {
+	m.RecordAdapterTimeWithContext(context.Background(), labels, length)
+}
+
+// RecordAdapterTimeWithContext records the time of a successful adapter request, with the trace of ctx as an exemplar
+func (m *Metrics) RecordAdapterTimeWithContext(ctx context.Context, labels metrics.AdapterLabels, length time.Duration) {
+	if len(labels.AdapterErrors) == 0 {
+		m.adapterRequestsTimer.Record(ctx, length.Seconds(), metric.WithAttributes(m.adapterAttrs(labels)...))
+	}
+}
+
+func (m *Metrics) RecordCookieSync(status metrics.CookieSyncStatus) {
+	inc(m.cookieSync, statusLabel.String(string(status)))
+}
+
+func (m *Metrics) RecordSyncerRequest(key string, status metrics.SyncerCookieSyncStatus) {
+	inc(m.syncerRequests, syncerLabel.String(key), statusLabel.String(string(status)))
+}
+
+func (m *Metrics) RecordSetUid(status metrics.SetUidStatus) {
+	inc(m.setUid, statusLabel.String(string(status)))
+}
+
+func (m *Metrics) RecordSyncerSet(key string, status metrics.SyncerSetUidStatus) {
+	inc(m.syncerSets, syncerLabel.String(key), statusLabel.String(string(status)))
+}
+
+func (m *Metrics) RecordStoredReqCacheResult(cacheResult metrics.CacheResult, count int) {
+	m.storedRequestCacheResult.Add(context.Background(), int64(count), metric.WithAttributes(cacheResultLabel.String(string(cacheResult))))
+}
+
+func (m *Metrics) RecordStoredImpCacheResult(cacheResult metrics.CacheResult, count int) {
+	m.storedImpressionsCacheResult.Add(context.Background(), int64(count), metric.WithAttributes(cacheResultLabel.String(string(cacheResult))))
+}
+
+func (m *Metrics) RecordAccountCacheResult(cacheResult metrics.CacheResult, count int) {
+	m.accountCacheResult.Add(context.Background(), int64(count), metric.WithAttributes(cacheResultLabel.String(string(cacheResult))))
+}
+
+func (m *Metrics) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
+	observe(m.prebidCacheWriteTimer, length.Seconds(), successLabel.Bool(success))
+}
+
+func (m *Metrics) RecordRequestQueueTime(success bool, requestType metrics.RequestType, length time.Duration) {
+	successLabelFormatted := requestRejectLabel
+	if success {
+		successLabelFormatted = requestSuccessLabel
+	}
+	observe(m.requestsQueueTimer, length.Seconds(),
+		requestTypeLabel.String(string(requestType)),
+		requestStatusLabel.String(successLabelFormatted))
+}
+
+func (m *Metrics) RecordTimeoutNotice(success bool) {
+	if success {
+		inc(m.timeoutNotifications, successLabel.String(requestSuccessful))
+	} else {
+		inc(m.timeoutNotifications, successLabel.String(requestFailed))
+	}
+}
+
+func (m *Metrics) RecordRequestPrivacy(privacy metrics.PrivacyLabels) {
+	if privacy.CCPAProvided {
+		inc(m.privacyCCPA, sourceLabel.String(sourceRequest), optOutLabel.Bool(privacy.CCPAEnforced))
+	}
+
+	if privacy.COPPAEnforced {
+		inc(m.privacyCOPPA, sourceLabel.String(sourceRequest))
+	}
+
+	if privacy.GDPREnforced {
+		inc(m.privacyTCF, versionLabel.String(string(privacy.GDPRTCFVersion)), sourceLabel.String(sourceRequest))
+	}
+
+	if privacy.LMTEnforced {
+		inc(m.privacyLMT, sourceLabel.String(sourceRequest))
+	}
+}
+
+func (m *Metrics) RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterBuyerUIDScrubbed {
+		return
+	}
+	inc(m.adapterScrubbedBuyerUIDs, adapterAttr(adapterName))
+}
+
+func (m *Metrics) RecordAdapterEIDsRemoved(adapterName openrtb_ext.BidderName, source string, count int) {
+	m.adapterEIDsRemoved.Add(context.Background(), int64(count), metric.WithAttributes(adapterAttr(adapterName), eidSourceLabel.String(source)))
+}
+
+func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterGDPRRequestBlocked {
+		return
+	}
+	inc(m.adapterGDPRBlockedRequests, adapterAttr(adapterName))
+}
+
+func (m *Metrics) RecordAdsCertReq(success bool) {
+	if success {
+		inc(m.adsCertRequests, successLabel.String(requestSuccessful))
+	} else {
+		inc(m.adsCertRequests, successLabel.String(requestFailed))
+	}
+}
+
+func (m *Metrics) RecordAdsCertSignTime(adsCertSignTime time.Duration) {
+	observe(m.adsCertSignTimer, adsCertSignTime.Seconds())
+}
+
+func (m *Metrics) recordBidValidation(adapterCounter, accountCounter metric.Int64Counter, adapter openrtb_ext.BidderName, account string) {
+	inc(adapterCounter, adapterAttr(adapter))
+
+	if !m.metricsDisabled.AccountAdapterDetails && account != metrics.PublisherUnknown {
+		inc(accountCounter, accountLabel.String(account))
+	}
+}
+
+func (m *Metrics) RecordBidValidationCreativeSizeError(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation(m.adapterBidResponseValidationSizeError, m.accountBidResponseValidationSizeError, adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationCreativeSizeWarn(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation(m.adapterBidResponseValidationSizeWarn, m.accountBidResponseValidationSizeWarn, adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationSecureMarkupError(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation(m.adapterBidResponseSecureMarkupError, m.accountBidResponseSecureMarkupError, adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation(m.adapterBidResponseSecureMarkupWarn, m.accountBidResponseSecureMarkupWarn, adapter, account)
+}
+
+func (m *Metrics) RecordModuleCalled(labels metrics.ModuleLabels, duration time.Duration) {
+	attrs := m.moduleAttrs(labels)
+	inc(m.moduleCalls, attrs...)
+	observe(m.moduleDuration, duration.Seconds(), attrs...)
+}
+
+func (m *Metrics) RecordModuleFailed(labels metrics.ModuleLabels) {
+	inc(m.moduleFailures, m.moduleAttrs(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessNooped(labels metrics.ModuleLabels) {
+	inc(m.moduleSuccessNoops, m.moduleAttrs(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessUpdated(labels metrics.ModuleLabels) {
+	inc(m.moduleSuccessUpdates, m.moduleAttrs(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessRejected(labels metrics.ModuleLabels) {
+	inc(m.moduleSuccessRejects, m.moduleAttrs(labels)...)
+}
+
+func (m *Metrics) RecordModuleExecutionError(labels metrics.ModuleLabels) {
+	inc(m.moduleExecutionErrors, m.moduleAttrs(labels)...)
+}
+
+func (m *Metrics) RecordModuleTimeout(labels metrics.ModuleLabels) {
+	inc(m.moduleTimeouts, m.moduleAttrs(labels)...)
+}
+
+func (m *Metrics) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
+	inc(m.adapterThrottled, adapterAttr(adapterName))
+}
+
+func (m *Metrics) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterConnectionMetrics || m.metricsDisabled.AdapterConnectionDialMetrics {
+		return
+	}
+	inc(m.adapterConnectionDialErrors, adapterAttr(adapterName))
+}
+
+func (m *Metrics) RecordAdapterConnectionDialTime(adapterName openrtb_ext.BidderName, dialStartTime time.Duration) {
+	if m.metricsDisabled.AdapterConnectionMetrics || m.metricsDisabled.AdapterConnectionDialMetrics {
+		return
+	}
+	observe(m.adapterConnectionDialTime, dialStartTime.Seconds(), adapterAttr(adapterName))
+}
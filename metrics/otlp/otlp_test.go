@@ -0,0 +1,320 @@
+package otlpmetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var auctionMetrics = config.AuctionMetrics{
+	Currency:          "USD",
+	PriceBuckets:      []float64{0.5, 1, 2, 5, 10},
+	FloorRatioBuckets: []float64{1, 1.5, 2},
+}
+
+func createMetricsForTesting(t *testing.T, disabledMetrics config.DisabledMetrics) (*Metrics, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	m, err := NewMetrics(newMeterProvider(reader, "prebid-server", "us-east"), disabledMetrics, auctionMetrics)
+	require.NoError(t, err)
+	return m, reader
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) metricdata.ResourceMetrics {
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+	return data
+}
+
+func findMetric(data metricdata.ResourceMetrics, name string) *metricdata.Metrics {
+	for _, scope := range data.ScopeMetrics {
+		for i := range scope.Metrics {
+			if scope.Metrics[i].Name == name {
+				return &scope.Metrics[i]
+			}
+		}
+	}
+	return nil
+}
+
+// counterValue returns the value of the counter with exactly the attributes attrs
+func counterValue(t *testing.T, data metricdata.ResourceMetrics, name string, attrs ...attribute.KeyValue) int64 {
+	found := findMetric(data, name)
+	if found == nil {
+		return 0
+	}
+	sum, ok := found.Data.(metricdata.Sum[int64])
+	require.True(t, ok, "%s isn't a counter", name)
+	expected := attribute.NewSet(attrs...)
+	for _, point := range sum.DataPoints {
+		if point.Attributes.Equals(&expected) {
+			return point.Value
+		}
+	}
+	return 0
+}
+
+func histogramPoint(t *testing.T, data metricdata.ResourceMetrics, name string, attrs ...attribute.KeyValue) metricdata.HistogramDataPoint[float64] {
+	found := findMetric(data, name)
+	require.NotNil(t, found, "%s wasn't recorded", name)
+	histogram, ok := found.Data.(metricdata.Histogram[float64])
+	require.True(t, ok, "%s isn't a histogram", name)
+	expected := attribute.NewSet(attrs...)
+	for _, point := range histogram.DataPoints {
+		if point.Attributes.Equals(&expected) {
+			return point
+		}
+	}
+	require.Failf(t, "missing data point", "%s has no data point with %v", name, attrs)
+	return metricdata.HistogramDataPoint[float64]{}
+}
+
+// gaugeValue returns the value of the gauge with exactly the attributes attrs
+func gaugeValue(t *testing.T, data metricdata.ResourceMetrics, name string, attrs ...attribute.KeyValue) int64 {
+	found := findMetric(data, name)
+	require.NotNil(t, found, "%s wasn't recorded", name)
+	gauge, ok := found.Data.(metricdata.Gauge[int64])
+	require.True(t, ok, "%s isn't a gauge", name)
+	expected := attribute.NewSet(attrs...)
+	for _, point := range gauge.DataPoints {
+		if point.Attributes.Equals(&expected) {
+			return point.Value
+		}
+	}
+	require.Failf(t, "missing data point", "%s has no data point with %v", name, attrs)
+	return 0
+}
+
+func TestResource(t *testing.T) {
+	_, reader := createMetricsForTesting(t, config.DisabledMetrics{})
+
+	data := collect(t, reader)
+	serviceName, _ := data.Resource.Set().Value("service.name")
+	dataCenter, _ := data.Resource.Set().Value(dataCenterAttribute)
+	_, hasVersion := data.Resource.Set().Value("service.version")
+	assert.Equal(t, "prebid-server", serviceName.AsString())
+	assert.Equal(t, "us-east", dataCenter.AsString())
+	assert.True(t, hasVersion)
+}
+
+func TestRecordRequest(t *testing.T) {
+	m, reader := createMetricsForTesting(t, config.DisabledMetrics{})
+
+	m.RecordRequest(metrics.Labels{
+		RType:         metrics.ReqTypeORTB2Web,
+		RequestStatus: metrics.RequestStatusOK,
+		CookieFlag:    metrics.CookieFlagNo,
+		PubID:         "acct-1",
+		RequestSize:   600,
+	})
+	m.RecordRequest(metrics.Labels{
+		RType:         metrics.ReqTypeAMP,
+		RequestStatus: metrics.RequestStatusBadInput,
+		CookieFlag:    metrics.CookieFlagYes,
+		PubID:         metrics.PublisherUnknown,
+		RequestSize:   600,
+	})
+
+	data := collect(t, reader)
+	assert.Equal(t, int64(1), counterValue(t, data, "requests", requestTypeLabel.String("openrtb2-web"), requestStatusLabel.String("ok")))
+	assert.Equal(t, int64(1), counterValue(t, data, "requests", requestTypeLabel.String("amp"), requestStatusLabel.String("badinput")))
+	assert.Equal(t, int64(1), counterValue(t, data, "requests_without_cookie", requestTypeLabel.String("openrtb2-web")))
+	assert.Equal(t, int64(1), counterValue(t, data, "account_requests", accountLabel.String("acct-1")))
+	assert.Equal(t, int64(0), counterValue(t, data, "account_requests", accountLabel.String(metrics.PublisherUnknown)))
+
+	size := histogramPoint(t, data, "request_size", requestEndpointLabel.String("auction"))
+	assert.Equal(t, uint64(1), size.Count)
+	assert.Equal(t, requestSizeBuckets, size.Bounds)
+}
+
+func TestRecordRequestTimeExemplar(t *testing.T) {
+	m, reader := createMetricsForTesting(t, config.DisabledMetrics{})
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+	labels := metrics.Labels{RType: metrics.ReqTypeORTB2App, RequestStatus: metrics.RequestStatusOK}
+
+	metrics.RecordRequestTime(ctx, m, labels, 120*time.Millisecond)
+	metrics.RecordRequestTime(ctx, m, metrics.Labels{RType: metrics.ReqTypeORTB2App, RequestStatus: metrics.RequestStatusErr}, time.Second)
+
+	point := histogramPoint(t, collect(t, reader), "request_time", requestTypeLabel.String("openrtb2-app"))
+	assert.Equal(t, uint64(1), point.Count)
+	assert.InDelta(t, 0.12, point.Sum, 0.0001)
+	assert.Equal(t, standardTimeBuckets, point.Bounds)
+	require.NotEmpty(t, point.Exemplars)
+	assert.Equal(t, spanContext.TraceID().String(), trace.TraceID(point.Exemplars[0].TraceID).String())
+}
+
+func TestRecordAdapterAccountDetails(t *testing.T) {
+	testCases := []struct {
+		description   string
+		disabled      bool
+		expectedAttrs []attribute.KeyValue
+	}{
+		{
+			description:   "Account details enabled",
+			disabled:      false,
+			expectedAttrs: []attribute.KeyValue{adapterLabel.String("appnexus"), accountLabel.String("acct-1")},
+		},
+		{
+			description:   "Account details disabled",
+			disabled:      true,
+			expectedAttrs: []attribute.KeyValue{adapterLabel.String("appnexus")},
+		},
+	}
+
+	for _, test := range testCases {
+		m, reader := createMetricsForTesting(t, config.DisabledMetrics{AccountAdapterDetails: test.disabled})
+		labels := metrics.AdapterLabels{
+			Adapter:       openrtb_ext.BidderName("AppNexus"),
+			PubID:         "acct-1",
+			CookieFlag:    metrics.CookieFlagYes,
+			AdapterBids:   metrics.AdapterBidPresent,
+			AdapterErrors: map[metrics.AdapterError]struct{}{metrics.AdapterErrorTimeout: {}},
+		}
+
+		m.RecordAdapterRequest(labels)
+		m.RecordAdapterBidReceived(labels, openrtb_ext.BidTypeBanner, true)
+		m.RecordAdapterPrice(labels, 1200)
+		m.RecordBidValidationCreativeSizeError(labels.Adapter, "acct-1")
+
+		data := collect(t, reader)
+		requestAttrs := append([]attribute.KeyValue{cookieLabel.String("exists"), hasBidsLabel.Bool(true)}, test.expectedAttrs...)
+		assert.Equal(t, int64(1), counterValue(t, data, "adapter_requests", requestAttrs...), test.description)
+		errorAttrs := append([]attribute.KeyValue{adapterErrorLabel.String("timeout")}, test.expectedAttrs...)
+		assert.Equal(t, int64(1), counterValue(t, data, "adapter_errors", errorAttrs...), test.description)
+		bidAttrs := append([]attribute.KeyValue{markupDeliveryLabel.String("adm")}, test.expectedAttrs...)
+		assert.Equal(t, int64(1), counterValue(t, data, "adapter_bids", bidAttrs...), test.description)
+		assert.Equal(t, uint64(1), histogramPoint(t, data, "adapter_prices", test.expectedAttrs...).Count, test.description)
+
+		assert.Equal(t, int64(1), counterValue(t, data, "adapter_response_validation_size_err", adapterLabel.String("appnexus")), test.description)
+		expectedAccountCount := int64(1)
+		if test.disabled {
+			expectedAccountCount = 0
+		}
+		assert.Equal(t, expectedAccountCount, counterValue(t, data, "account_response_validation_size_err", accountLabel.String("acct-1")), test.description)
+	}
+}
+
+func TestRecordAccountToggles(t *testing.T) {
+	testCases := []struct {
+		description string
+		disabled    config.DisabledMetrics
+		expected    int64
+	}{
+		{
+			description: "Enabled",
+			disabled:    config.DisabledMetrics{},
+			expected:    1,
+		},
+		{
+			description: "Disabled",
+			disabled:    config.DisabledMetrics{AccountDebug: true, AccountStoredResponses: true, AccountModulesMetrics: true},
+			expected:    0,
+		},
+	}
+
+	for _, test := range testCases {
+		m, reader := createMetricsForTesting(t, test.disabled)
+
+		m.RecordDebugRequest(true, "acct-1")
+		m.RecordStoredResponse("acct-1")
+		m.RecordModuleCalled(metrics.ModuleLabels{Module: "acme", Stage: "entrypoint", AccountID: "acct-1"}, time.Millisecond)
+
+		data := collect(t, reader)
+		assert.Equal(t, int64(1), counterValue(t, data, "debug_requests"), test.description)
+		assert.Equal(t, test.expected, counterValue(t, data, "account_debug_requests", accountLabel.String("acct-1")), test.description)
+		assert.Equal(t, int64(1), counterValue(t, data, "stored_responses"), test.description)
+		assert.Equal(t, test.expected, counterValue(t, data, "account_stored_responses", accountLabel.String("acct-1")), test.description)
+		assert.Equal(t, test.expected, counterValue(t, data, "modules_called", moduleLabel.String("acme"), stageLabel.String("entrypoint"), accountLabel.String("acct-1")), test.description)
+		assert.Equal(t, 1-test.expected, counterValue(t, data, "modules_called", moduleLabel.String("acme"), stageLabel.String("entrypoint")), test.description)
+	}
+}
+
+func TestRecordAdapterConnectionsDisabled(t *testing.T) {
+	m, reader := createMetricsForTesting(t, config.DisabledMetrics{AdapterConnectionMetrics: true, AdapterBuyerUIDScrubbed: true})
+
+	m.RecordAdapterConnections(openrtb_ext.BidderAppnexus, true, time.Millisecond)
+	m.RecordAdapterConnectionDialError(openrtb_ext.BidderAppnexus)
+	m.RecordAdapterConnectionDialTime(openrtb_ext.BidderAppnexus, time.Millisecond)
+	m.RecordAdapterBuyerUIDScrubbed(openrtb_ext.BidderAppnexus)
+
+	data := collect(t, reader)
+	assert.Nil(t, findMetric(data, "adapter_connection_reused"))
+	assert.Nil(t, findMetric(data, "adapter_connection_dial_errors"))
+	assert.Nil(t, findMetric(data, "adapter_buyeruids_scrubbed"))
+}
+
+func TestRecordStoredData(t *testing.T) {
+	m, reader := createMetricsForTesting(t, config.DisabledMetrics{})
+
+	m.RecordStoredDataFetchTime(metrics.StoredDataLabels{DataType: metrics.AccountDataType, DataFetchType: metrics.FetchAll}, 50*time.Millisecond)
+	m.RecordStoredDataError(metrics.StoredDataLabels{DataType: metrics.ResponseDataType, Error: metrics.StoredDataErrorNetwork})
+	m.RecordStoredReqCacheResult(metrics.CacheHit, 3)
+
+	data := collect(t, reader)
+	assert.Equal(t, uint64(1), histogramPoint(t, data, "stored_account_fetch_time", storedDataFetchTypeLabel.String("all")).Count)
+	assert.Equal(t, int64(1), counterValue(t, data, "stored_response_errors", storedDataErrorLabel.String("network")))
+	assert.Equal(t, int64(3), counterValue(t, data, "stored_request_cache_performance", cacheResultLabel.String("hit")))
+}
+
+func TestRecordAuctionBid(t *testing.T) {
+	m, reader := createMetricsForTesting(t, config.DisabledMetrics{})
+
+	m.RecordAdapterAuctionImps(openrtb_ext.BidderAppnexus, "acct-1", 3)
+	m.RecordAuctionBid(metrics.AuctionBidLabels{Adapter: openrtb_ext.BidderAppnexus, PubID: "acct-1", Deal: true, Won: true}, 2.5, 1.25)
+
+	data := collect(t, reader)
+	account := accountLabel.String("acct-1")
+	adapter := adapterLabel.String("appnexus")
+	assert.Equal(t, int64(3), counterValue(t, data, "adapter_auction_imps", adapter, account))
+	assert.Equal(t, int64(1), counterValue(t, data, "adapter_auction_bids", adapter, account, dealLabel.Bool(true), wonLabel.Bool(true)))
+
+	cpm := histogramPoint(t, data, "adapter_auction_bid_cpm", adapter, account, dealLabel.Bool(true))
+	assert.Equal(t, 2.5, cpm.Sum)
+	assert.Equal(t, auctionMetrics.PriceBuckets, cpm.Bounds)
+	assert.Equal(t, 1.25, histogramPoint(t, data, "adapter_auction_bid_floor_ratio", adapter).Sum)
+}
+
+func TestRecordAdapterEIDsRemoved(t *testing.T) {
+	m, reader := createMetricsForTesting(t, config.DisabledMetrics{})
+
+	m.RecordAdapterEIDsRemoved(openrtb_ext.BidderAppnexus, "liveramp.com", 2)
+
+	data := collect(t, reader)
+	assert.Equal(t, int64(2), counterValue(t, data, "adapter_eids_removed", adapterLabel.String("appnexus"), eidSourceLabel.String("liveramp.com")))
+}
+
+func TestRecordOptOutMatch(t *testing.T) {
+	m, reader := createMetricsForTesting(t, config.DisabledMetrics{})
+
+	m.RecordOptOutMatch(metrics.OptOutEntryHashedEmail)
+	m.RecordOptOutMatch(metrics.OptOutEntryHashedEmail)
+
+	data := collect(t, reader)
+	assert.Equal(t, int64(2), counterValue(t, data, "optout_matches", entryTypeLabel.String("hem")))
+}
+
+func TestRecordGvlListLatest(t *testing.T) {
+	m, reader := createMetricsForTesting(t, config.DisabledMetrics{})
+
+	fetched := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m.RecordGvlListLatest(3, 42, fetched)
+
+	data := collect(t, reader)
+	assert.Equal(t, int64(42), gaugeValue(t, data, "gvl_latest_version", specVersionLabel.Int(3)))
+	assert.Equal(t, fetched.Unix(), gaugeValue(t, data, "gvl_latest_fetch_timestamp", specVersionLabel.Int(3)))
+}
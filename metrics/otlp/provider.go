@@ -0,0 +1,57 @@
+package otlpmetrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/version"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// dataCenterAttribute is the resource attribute of the data center of the host
+const dataCenterAttribute = attribute.Key("pbs.datacenter")
+
+// NewMeterProvider returns a meter provider exporting the metrics to the collector of cfg, every export interval
+func NewMeterProvider(cfg config.OTLPMetrics, dataCenter string) (*sdkmetric.MeterProvider, error) {
+	timeout := time.Duration(cfg.TimeoutMS) * time.Millisecond
+	options := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(cfg.Endpoint),
+		otlpmetrichttp.WithTimeout(timeout),
+	}
+	if cfg.Insecure {
+		options = append(options, otlpmetrichttp.WithInsecure())
+	}
+	exporter, err := otlpmetrichttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithInterval(time.Duration(cfg.ExportIntervalMS)*time.Millisecond),
+		sdkmetric.WithTimeout(timeout),
+	)
+	return newMeterProvider(reader, cfg.ServiceName, dataCenter), nil
+}
+
+func newMeterProvider(reader sdkmetric.Reader, serviceName, dataCenter string) *sdkmetric.MeterProvider {
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version.Ver),
+	}
+	if dataCenter != "" {
+		attrs = append(attrs, dataCenterAttribute.String(dataCenter))
+	}
+
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attrs...)),
+		// The timings recorded within a sampled trace keep its ID as an exemplar
+		sdkmetric.WithExemplarFilter(exemplar.TraceBasedFilter),
+	)
+}
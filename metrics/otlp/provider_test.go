@@ -0,0 +1,41 @@
+package otlpmetrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMeterProvider(t *testing.T) {
+	paths := make(chan string, 10)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	provider, err := NewMeterProvider(config.OTLPMetrics{
+		Enabled:          true,
+		Endpoint:         strings.TrimPrefix(collector.URL, "http://"),
+		Insecure:         true,
+		ServiceName:      "prebid-server",
+		ExportIntervalMS: 60000,
+		TimeoutMS:        1000,
+	}, "")
+	require.NoError(t, err)
+	m, err := NewMetrics(provider, config.DisabledMetrics{}, auctionMetrics)
+	require.NoError(t, err)
+
+	m.RecordRequest(metrics.Labels{RType: metrics.ReqTypeORTB2Web, RequestStatus: metrics.RequestStatusOK})
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	require.Len(t, paths, 1)
+	assert.Equal(t, "/v1/metrics", <-paths)
+}
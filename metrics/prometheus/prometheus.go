@@ -55,6 +55,9 @@ type Metrics struct {
 	privacyTCF                   *prometheus.CounterVec
 	storedResponses              prometheus.Counter
 	gvlListRequests              prometheus.Counter
+	optOutMatches                *prometheus.CounterVec
+	gvlListLatestVersion         *prometheus.GaugeVec
+	gvlListLatestFetch           *prometheus.GaugeVec
 	storedResponsesFetchTimer    *prometheus.HistogramVec
 	storedResponsesErrors        *prometheus.CounterVec
 	adsCertRequests              *prometheus.CounterVec
@@ -74,6 +77,7 @@ type Metrics struct {
 	adapterConnectionWaitTime             *prometheus.HistogramVec
 	adapterScrubbedBuyerUIDs              *prometheus.CounterVec
 	adapterGDPRBlockedRequests            *prometheus.CounterVec
+	adapterEIDsRemoved                    *prometheus.CounterVec
 	adapterBidResponseValidationSizeError *prometheus.CounterVec
 	adapterBidResponseValidationSizeWarn  *prometheus.CounterVec
 	adapterBidResponseSecureMarkupError   *prometheus.CounterVec
@@ -81,6 +85,10 @@ type Metrics struct {
 	adapterThrottled                      *prometheus.CounterVec
 	adapterConnectionDialErrors           *prometheus.CounterVec
 	adapterConnectionDialTime             *prometheus.HistogramVec
+	adapterAuctionImps                    *prometheus.CounterVec
+	adapterAuctionBids                    *prometheus.CounterVec
+	adapterAuctionBidCPM                  *prometheus.HistogramVec
+	adapterAuctionBidFloorRatio           *prometheus.HistogramVec
 
 	// Syncer Metrics
 	syncerRequests *prometheus.CounterVec
@@ -94,6 +102,9 @@ type Metrics struct {
 	accountBidResponseValidationSizeWarn  *prometheus.CounterVec
 	accountBidResponseSecureMarkupError   *prometheus.CounterVec
 	accountBidResponseSecureMarkupWarn    *prometheus.CounterVec
+	accountAdapterAuctionImps             *prometheus.CounterVec
+	accountAdapterAuctionBids             *prometheus.CounterVec
+	accountAdapterAuctionBidCPM           *prometheus.HistogramVec
 
 	// Module Metrics as a map where the key is the module name
 	moduleDuration        map[string]*prometheus.HistogramVec
@@ -117,6 +128,9 @@ const (
 	cacheResultLabel     = "cache_result"
 	connectionErrorLabel = "connection_error"
 	cookieLabel          = "cookie"
+	dealLabel            = "deal"
+	eidSourceLabel       = "eid_source"
+	entryTypeLabel       = "entry_type"
 	hasBidsLabel         = "has_bids"
 	isAudioLabel         = "audio"
 	isBannerLabel        = "banner"
@@ -129,10 +143,12 @@ const (
 	requestStatusLabel   = "request_status"
 	requestTypeLabel     = "request_type"
 	requestEndpointLabel = "request_size"
+	specVersionLabel     = "spec_version"
 	stageLabel           = "stage"
 	statusLabel          = "status"
 	successLabel         = "success"
 	syncerLabel          = "syncer"
+	wonLabel             = "won"
 	versionLabel         = "version"
 )
 
@@ -167,7 +183,7 @@ const (
 )
 
 // NewMetrics initializes a new Prometheus metrics instance with preloaded label values.
-func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMetrics, syncerKeys []string, moduleStageNames map[string][]string) *Metrics {
+func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMetrics, auctionMetrics config.AuctionMetrics, syncerKeys []string, moduleStageNames map[string][]string) *Metrics {
 	standardTimeBuckets := []float64{0.05, 0.1, 0.15, 0.20, 0.25, 0.3, 0.4, 0.5, 0.75, 1}
 	cacheWriteTimeBuckets := []float64{0.001, 0.002, 0.005, 0.01, 0.025, 0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 1}
 	priceBuckets := []float64{250, 500, 750, 1000, 1500, 2000, 2500, 3000, 3500, 4000}
@@ -359,6 +375,10 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 			"Count of total bidder requests blocked due to unsatisfied GDPR purpose 2 legal basis",
 			[]string{adapterLabel})
 	}
+	metrics.adapterEIDsRemoved = newCounter(cfg, reg,
+		"adapter_eids_removed",
+		"Count of user EIDs removed from bidder requests by the account EID permissions, by EID source",
+		[]string{adapterLabel, eidSourceLabel})
 
 	metrics.storedResponsesFetchTimer = newHistogramVec(cfg, reg,
 		"stored_response_fetch_time_seconds",
@@ -379,6 +399,21 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"gvl_requests",
 		"Count number of times GVL list is fetched")
 
+	metrics.optOutMatches = newCounter(cfg, reg,
+		"optout_matches",
+		"Count of requests whose user matched an entry of the opt-out registry, by entry type",
+		[]string{entryTypeLabel})
+
+	metrics.gvlListLatestVersion = newGaugeVec(cfg, reg,
+		"gvl_latest_version",
+		"Version of the latest cached GVL list labeled by spec version.",
+		[]string{specVersionLabel})
+
+	metrics.gvlListLatestFetch = newGaugeVec(cfg, reg,
+		"gvl_latest_fetch_timestamp_seconds",
+		"Unix time the latest cached GVL list was fetched labeled by spec version.",
+		[]string{specVersionLabel})
+
 	metrics.adapterBids = newCounter(cfg, reg,
 		"adapter_bids",
 		"Count of bids labeled by adapter and markup delivery type (adm or nurl).",
@@ -405,6 +440,28 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of requests labeled by adapter, if has a cookie, and if it resulted in bids.",
 		[]string{adapterLabel, cookieLabel, hasBidsLabel})
 
+	metrics.adapterAuctionImps = newCounter(cfg, reg,
+		"adapter_auction_imps",
+		"Count of imps offered to the adapters in the auctions labeled by adapter.",
+		[]string{adapterLabel})
+
+	metrics.adapterAuctionBids = newCounter(cfg, reg,
+		"adapter_auction_bids",
+		"Count of bids which took part in the auctions labeled by adapter, if a deal, and if it won its imp.",
+		[]string{adapterLabel, dealLabel, wonLabel})
+
+	metrics.adapterAuctionBidCPM = newHistogramVec(cfg, reg,
+		"adapter_auction_bid_cpm",
+		"CPM of the bids which took part in the auctions, in the reference currency, labeled by adapter and if a deal.",
+		[]string{adapterLabel, dealLabel},
+		auctionMetrics.PriceBuckets)
+
+	metrics.adapterAuctionBidFloorRatio = newHistogramVec(cfg, reg,
+		"adapter_auction_bid_floor_ratio",
+		"Ratio of the bids which took part in the auctions to the floor of their imp labeled by adapter.",
+		[]string{adapterLabel},
+		auctionMetrics.FloorRatioBuckets)
+
 	if !metrics.metricsDisabled.AdapterConnectionMetrics {
 		metrics.adapterCreatedConnections = newCounter(cfg, reg,
 			"adapter_connection_created",
@@ -518,6 +575,24 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count that tracks number of bids removed from bid response that had a invalid bidAdm labeled by account (warn)",
 		[]string{accountLabel, successLabel})
 
+	if !metrics.metricsDisabled.AccountAdapterDetails {
+		metrics.accountAdapterAuctionImps = newCounter(cfg, reg,
+			"account_adapter_auction_imps",
+			"Count of imps offered to the adapters in the auctions labeled by account and adapter.",
+			[]string{accountLabel, adapterLabel})
+
+		metrics.accountAdapterAuctionBids = newCounter(cfg, reg,
+			"account_adapter_auction_bids",
+			"Count of bids which took part in the auctions labeled by account, adapter, if a deal, and if it won its imp.",
+			[]string{accountLabel, adapterLabel, dealLabel, wonLabel})
+
+		metrics.accountAdapterAuctionBidCPM = newHistogramVec(cfg, reg,
+			"account_adapter_auction_bid_cpm",
+			"CPM of the bids which took part in the auctions, in the reference currency, labeled by account and adapter.",
+			[]string{accountLabel, adapterLabel},
+			auctionMetrics.PriceBuckets)
+	}
+
 	metrics.requestsQueueTimer = newHistogramVec(cfg, reg,
 		"request_queue_time",
 		"Seconds request was waiting in queue",
@@ -639,6 +714,18 @@ func newCounterWithoutLabels(cfg config.PrometheusMetrics, registry *prometheus.
 	return counter
 }
 
+func newGaugeVec(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string) *prometheus.GaugeVec {
+	opts := prometheus.GaugeOpts{
+		Namespace: cfg.Namespace,
+		Subsystem: cfg.Subsystem,
+		Name:      name,
+		Help:      help,
+	}
+	gauge := prometheus.NewGaugeVec(opts, labels)
+	registry.MustRegister(gauge)
+	return gauge
+}
+
 func newHistogramVec(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string, buckets []float64) *prometheus.HistogramVec {
 	opts := prometheus.HistogramOpts{
 		Namespace: cfg.Namespace,
@@ -739,6 +826,18 @@ func (m *Metrics) RecordGvlListRequest() {
 	m.gvlListRequests.Inc()
 }
 
+func (m *Metrics) RecordOptOutMatch(entryType metrics.OptOutEntryType) {
+	m.optOutMatches.With(prometheus.Labels{
+		entryTypeLabel: string(entryType),
+	}).Inc()
+}
+
+func (m *Metrics) RecordGvlListLatest(specVersion uint16, listVersion uint16, fetched time.Time) {
+	labels := prometheus.Labels{specVersionLabel: strconv.Itoa(int(specVersion))}
+	m.gvlListLatestVersion.With(labels).Set(float64(listVersion))
+	m.gvlListLatestFetch.With(labels).Set(float64(fetched.Unix()))
+}
+
 func (m *Metrics) RecordImps(labels metrics.ImpLabels) {
 	m.impressions.With(prometheus.Labels{
 		isBannerLabel: strconv.FormatBool(labels.BannerImps),
@@ -889,6 +988,54 @@ func (m *Metrics) RecordAdapterPrice(labels metrics.AdapterLabels, cpm float64)
 	}).Observe(cpm)
 }
 
+func (m *Metrics) RecordAdapterAuctionImps(adapter openrtb_ext.BidderName, pubID string, imps int) {
+	lowerCasedAdapter := strings.ToLower(string(adapter))
+	m.adapterAuctionImps.With(prometheus.Labels{
+		adapterLabel: lowerCasedAdapter,
+	}).Add(float64(imps))
+
+	if !m.metricsDisabled.AccountAdapterDetails && pubID != metrics.PublisherUnknown {
+		m.accountAdapterAuctionImps.With(prometheus.Labels{
+			accountLabel: pubID,
+			adapterLabel: lowerCasedAdapter,
+		}).Add(float64(imps))
+	}
+}
+
+func (m *Metrics) RecordAuctionBid(labels metrics.AuctionBidLabels, cpm float64, floorRatio float64) {
+	lowerCasedAdapter := strings.ToLower(string(labels.Adapter))
+	deal := strconv.FormatBool(labels.Deal)
+	won := strconv.FormatBool(labels.Won)
+
+	m.adapterAuctionBids.With(prometheus.Labels{
+		adapterLabel: lowerCasedAdapter,
+		dealLabel:    deal,
+		wonLabel:     won,
+	}).Inc()
+	m.adapterAuctionBidCPM.With(prometheus.Labels{
+		adapterLabel: lowerCasedAdapter,
+		dealLabel:    deal,
+	}).Observe(cpm)
+	if floorRatio > 0 {
+		m.adapterAuctionBidFloorRatio.With(prometheus.Labels{
+			adapterLabel: lowerCasedAdapter,
+		}).Observe(floorRatio)
+	}
+
+	if !m.metricsDisabled.AccountAdapterDetails && labels.PubID != metrics.PublisherUnknown {
+		m.accountAdapterAuctionBids.With(prometheus.Labels{
+			accountLabel: labels.PubID,
+			adapterLabel: lowerCasedAdapter,
+			dealLabel:    deal,
+			wonLabel:     won,
+		}).Inc()
+		m.accountAdapterAuctionBidCPM.With(prometheus.Labels{
+			accountLabel: labels.PubID,
+			adapterLabel: lowerCasedAdapter,
+		}).Observe(cpm)
+	}
+}
+
 func (m *Metrics) RecordOverheadTime(overhead metrics.OverheadType, duration time.Duration) {
 	m.overheadTimer.With(prometheus.Labels{
 		overheadTypeLabel: overhead.String(),
@@ -1014,6 +1161,13 @@ func (m *Metrics) RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderNa
 	}).Inc()
 }
 
+func (m *Metrics) RecordAdapterEIDsRemoved(adapterName openrtb_ext.BidderName, source string, count int) {
+	m.adapterEIDsRemoved.With(prometheus.Labels{
+		adapterLabel:   strings.ToLower(string(adapterName)),
+		eidSourceLabel: source,
+	}).Add(float64(count))
+}
+
 func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName) {
 	if m.metricsDisabled.AdapterGDPRRequestBlocked {
 		return
@@ -16,13 +16,19 @@ import (
 
 var modulesStages = map[string][]string{"foobar": {"entry", "raw"}, "another_module": {"raw", "auction"}}
 
+var auctionMetrics = config.AuctionMetrics{
+	Currency:          "USD",
+	PriceBuckets:      []float64{0.5, 1, 2, 5, 10},
+	FloorRatioBuckets: []float64{1, 1.5, 2},
+}
+
 func createMetricsForTesting() *Metrics {
 	syncerKeys := []string{}
 	return NewMetrics(config.PrometheusMetrics{
 		Port:      8080,
 		Namespace: "prebid",
 		Subsystem: "server",
-	}, config.DisabledMetrics{}, syncerKeys, modulesStages)
+	}, config.DisabledMetrics{}, auctionMetrics, syncerKeys, modulesStages)
 }
 
 func TestMetricCountGatekeeping(t *testing.T) {
@@ -885,6 +891,44 @@ func TestRecordAdapterPriceMetric(t *testing.T) {
 	assertHistogram(t, "adapterPrices", result, expectedCount, expectedSum)
 }
 
+func TestRecordAuctionBidMetrics(t *testing.T) {
+	m := createMetricsForTesting()
+	adapter := openrtb_ext.BidderName("anyName")
+
+	m.RecordAdapterAuctionImps(adapter, "acct-1", 3)
+	m.RecordAuctionBid(metrics.AuctionBidLabels{Adapter: adapter, PubID: "acct-1", Deal: true, Won: true}, 2.5, 1.25)
+	m.RecordAuctionBid(metrics.AuctionBidLabels{Adapter: adapter, PubID: "acct-1"}, 0.75, 0)
+
+	assertCounterVecValue(t, "", "adapterAuctionImps", m.adapterAuctionImps, 3, prometheus.Labels{adapterLabel: "anyname"})
+	assertCounterVecValue(t, "", "adapterAuctionBids", m.adapterAuctionBids, 1, prometheus.Labels{adapterLabel: "anyname", dealLabel: "true", wonLabel: "true"})
+	assertCounterVecValue(t, "", "adapterAuctionBids", m.adapterAuctionBids, 1, prometheus.Labels{adapterLabel: "anyname", dealLabel: "false", wonLabel: "false"})
+	assertCounterVecValue(t, "", "accountAdapterAuctionImps", m.accountAdapterAuctionImps, 3, prometheus.Labels{accountLabel: "acct-1", adapterLabel: "anyname"})
+	assertCounterVecValue(t, "", "accountAdapterAuctionBids", m.accountAdapterAuctionBids, 1, prometheus.Labels{accountLabel: "acct-1", adapterLabel: "anyname", dealLabel: "true", wonLabel: "true"})
+
+	dealCPM := getHistogramFromHistogramVecByTwoKeys(m.adapterAuctionBidCPM, adapterLabel, "anyname", dealLabel, "true")
+	assertHistogram(t, "adapterAuctionBidCPM", dealCPM, 1, 2.5)
+	assert.Equal(t, auctionMetrics.PriceBuckets[len(auctionMetrics.PriceBuckets)-1], dealCPM.GetBucket()[len(dealCPM.GetBucket())-1].GetUpperBound(), "configured buckets")
+
+	accountCPM := getHistogramFromHistogramVecByTwoKeys(m.accountAdapterAuctionBidCPM, accountLabel, "acct-1", adapterLabel, "anyname")
+	assertHistogram(t, "accountAdapterAuctionBidCPM", accountCPM, 2, 3.25)
+
+	floorRatio, found := getHistogramFromHistogramVec(m.adapterAuctionBidFloorRatio, adapterLabel, "anyname")
+	assert.True(t, found)
+	assertHistogram(t, "adapterAuctionBidFloorRatio", floorRatio, 1, 1.25)
+}
+
+func TestRecordAuctionBidMetricsAccountDisabled(t *testing.T) {
+	m := NewMetrics(config.PrometheusMetrics{}, config.DisabledMetrics{AccountAdapterDetails: true}, auctionMetrics, nil, nil)
+	adapter := openrtb_ext.BidderName("anyName")
+
+	m.RecordAdapterAuctionImps(adapter, "acct-1", 3)
+	m.RecordAuctionBid(metrics.AuctionBidLabels{Adapter: adapter, PubID: "acct-1"}, 0.75, 0)
+
+	assert.Nil(t, m.accountAdapterAuctionImps)
+	assert.Nil(t, m.accountAdapterAuctionBids)
+	assertCounterVecValue(t, "", "adapterAuctionImps", m.adapterAuctionImps, 3, prometheus.Labels{adapterLabel: "anyname"})
+}
+
 func TestAdapterRequestMetrics(t *testing.T) {
 	adapterName := "anyName"
 	lowerCasedAdapterName := "anyname"
@@ -1687,7 +1731,7 @@ func TestDisabledMetrics(t *testing.T) {
 		AdapterConnectionMetrics:  true,
 		AdapterGDPRRequestBlocked: true,
 	},
-		nil, nil)
+		auctionMetrics, nil, nil)
 
 	// Assert counter vector was not initialized
 	assert.Nil(t, prometheusMetrics.adapterReusedConnections, "Counter Vector adapterReusedConnections should be nil")
@@ -1788,6 +1832,12 @@ func assertCounterVecValue(t *testing.T, description, name string, counterVec *p
 	assertCounterValue(t, description, name, counter, expected)
 }
 
+func assertGaugeVecValue(t *testing.T, description string, gaugeVec *prometheus.GaugeVec, expected float64, labels prometheus.Labels) {
+	m := dto.Metric{}
+	gaugeVec.With(labels).Write(&m)
+	assert.Equal(t, expected, m.GetGauge().GetValue(), description)
+}
+
 func getHistogramFromHistogramVec(histogram *prometheus.HistogramVec, labelKey, labelValue string) (dto.Histogram, bool) {
 	var result dto.Histogram
 	var found bool
@@ -1953,6 +2003,40 @@ func TestRecordGvlListRequest(t *testing.T) {
 	assertCounterValue(t, "Record instance of fetched GVL list", "success", m.gvlListRequests, 1.00)
 }
 
+func TestRecordGvlListLatest(t *testing.T) {
+	m := createMetricsForTesting()
+
+	fetched := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	m.RecordGvlListLatest(3, 42, fetched)
+
+	labels := prometheus.Labels{specVersionLabel: "3"}
+	assertGaugeVecValue(t, "latest version", m.gvlListLatestVersion, 42, labels)
+	assertGaugeVecValue(t, "latest fetch", m.gvlListLatestFetch, float64(fetched.Unix()), labels)
+}
+
+func TestRecordAdapterEIDsRemoved(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterEIDsRemoved(openrtb_ext.BidderAppnexus, "liveramp.com", 2)
+	m.RecordAdapterEIDsRemoved(openrtb_ext.BidderAppnexus, "liveramp.com", 1)
+
+	assertCounterVecValue(t, "", "adapter_eids_removed", m.adapterEIDsRemoved, 3, prometheus.Labels{
+		adapterLabel:   string(openrtb_ext.BidderAppnexus),
+		eidSourceLabel: "liveramp.com",
+	})
+}
+
+func TestRecordOptOutMatch(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordOptOutMatch(metrics.OptOutEntryIFA)
+	m.RecordOptOutMatch(metrics.OptOutEntryIFA)
+
+	assertCounterVecValue(t, "", "optout_matches", m.optOutMatches, 2, prometheus.Labels{
+		entryTypeLabel: string(metrics.OptOutEntryIFA),
+	})
+}
+
 func TestRecordAdsCertReqMetric(t *testing.T) {
 	testCases := []struct {
 		description                  string
@@ -0,0 +1,504 @@
+package statsdmetrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// Metrics sends the metrics to a StatsD or DogStatsD agent, labeled with DogStatsD tags. The counters are aggregated
+// client side. The timings and histograms are sampled, and aggregated client side too with the extended aggregation.
+type Metrics struct {
+	client          statsd.ClientInterface
+	sampleRate      float64
+	metricsDisabled config.DisabledMetrics
+}
+
+const (
+	accountTag         = "account"
+	adapterErrorTag    = "adapter_error"
+	adapterTag         = "adapter"
+	cacheResultTag     = "cache_result"
+	connectionErrorTag = "connection_error"
+	cookieTag          = "cookie"
+	dataTypeTag        = "data_type"
+	dealTag            = "deal"
+	eidSourceTag       = "eid_source"
+	entryTypeTag       = "entry_type"
+	endpointTag        = "endpoint"
+	hasBidsTag         = "has_bids"
+	isAudioTag         = "audio"
+	isBannerTag        = "banner"
+	isNativeTag        = "native"
+	isVideoTag         = "video"
+	markupDeliveryTag  = "delivery"
+	moduleTag          = "module"
+	optOutTag          = "opt_out"
+	overheadTypeTag    = "overhead_type"
+	requestStatusTag   = "request_status"
+	requestTypeTag     = "request_type"
+	specVersionTag     = "spec_version"
+	stageTag           = "stage"
+	statusTag          = "status"
+	successTag         = "success"
+	syncerTag          = "syncer"
+	versionTag         = "version"
+	wonTag             = "won"
+
+	sourceTag              = "source"
+	storedDataFetchTypeTag = "stored_data_fetch_type"
+	storedDataErrorTag     = "stored_data_error"
+)
+
+const (
+	connectionAcceptError = "accept"
+	connectionCloseError  = "close"
+)
+
+const (
+	markupDeliveryAdm  = "adm"
+	markupDeliveryNurl = "nurl"
+)
+
+const (
+	requestSuccessLabel = "requestAcceptedLabel"
+	requestRejectLabel  = "requestRejectedLabel"
+)
+
+const (
+	requestSuccessful = "ok"
+	requestFailed     = "failed"
+)
+
+const sourceRequest = "request"
+
+// NewMetrics returns the metrics sent to the agent of cfg
+func NewMetrics(cfg config.StatsDMetrics, disabledMetrics config.DisabledMetrics) (*Metrics, error) {
+	options := []statsd.Option{
+		statsd.WithNamespace(cfg.Namespace),
+		statsd.WithTags(cfg.Tags),
+		statsd.WithClientSideAggregation(),
+		statsd.WithAggregationInterval(time.Duration(cfg.AggregationIntervalMS) * time.Millisecond),
+		statsd.WithoutTelemetry(),
+		statsd.WithErrorHandler(func(err error) {
+			logger.Warnf("[StatsDMetrics] Failed to send the metrics: %v", err)
+		}),
+	}
+	if cfg.ExtendedAggregation {
+		options = append(options, statsd.WithExtendedClientSideAggregation())
+	}
+	client, err := statsd.New(cfg.Address, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &Metrics{
+		client:          client,
+		sampleRate:      cfg.SampleRate,
+		metricsDisabled: disabledMetrics,
+	}, nil
+}
+
+// Shutdown sends the aggregated metrics and closes the client
+func (m *Metrics) Shutdown() {
+	if err := m.client.Close(); err != nil {
+		logger.Errorf("[StatsDMetrics] Failed to send the remaining metrics: %v", err)
+	}
+}
+
+func tag(key, value string) string {
+	return key + ":" + value
+}
+
+func boolTag(key string, value bool) string {
+	return tag(key, strconv.FormatBool(value))
+}
+
+func adapterTagOf(adapter openrtb_ext.BidderName) string {
+	return tag(adapterTag, strings.ToLower(string(adapter)))
+}
+
+func (m *Metrics) incr(name string, tags ...string) {
+	m.client.Incr(name, tags, 1)
+}
+
+func (m *Metrics) count(name string, value int, tags ...string) {
+	m.client.Count(name, int64(value), tags, 1)
+}
+
+func (m *Metrics) gauge(name string, value float64, tags ...string) {
+	m.client.Gauge(name, value, tags, 1)
+}
+
+func (m *Metrics) timing(name string, value time.Duration, tags ...string) {
+	m.client.Timing(name, value, tags, m.sampleRate)
+}
+
+func (m *Metrics) histogram(name string, value float64, tags ...string) {
+	m.client.Histogram(name, value, tags, m.sampleRate)
+}
+
+// adapterTags returns the tags of the adapter metrics, with the account unless the account adapter details are
+// disabled
+func (m *Metrics) adapterTags(labels metrics.AdapterLabels, tags ...string) []string {
+	tags = append(tags, adapterTagOf(labels.Adapter))
+	if !m.metricsDisabled.AccountAdapterDetails && labels.PubID != "" && labels.PubID != metrics.PublisherUnknown {
+		tags = append(tags, tag(accountTag, labels.PubID))
+	}
+	return tags
+}
+
+// moduleTags returns the tags of the module metrics, with the account unless the account modules metrics are disabled
+func (m *Metrics) moduleTags(labels metrics.ModuleLabels) []string {
+	tags := []string{tag(moduleTag, labels.Module), tag(stageTag, labels.Stage)}
+	if !m.metricsDisabled.AccountModulesMetrics && labels.AccountID != "" && labels.AccountID != metrics.PublisherUnknown {
+		tags = append(tags, tag(accountTag, labels.AccountID))
+	}
+	return tags
+}
+
+func (m *Metrics) RecordConnectionAccept(success bool) {
+	if success {
+		m.incr("connections_opened")
+	} else {
+		m.incr("connections_error", tag(connectionErrorTag, connectionAcceptError))
+	}
+}
+
+func (m *Metrics) RecordTMaxTimeout() {
+	m.incr("tmax_timeout")
+}
+
+func (m *Metrics) RecordConnectionClose(success bool) {
+	if success {
+		m.incr("connections_closed")
+	} else {
+		m.incr("connections_error", tag(connectionErrorTag, connectionCloseError))
+	}
+}
+
+func (m *Metrics) RecordRequest(labels metrics.Labels) {
+	m.incr("requests", tag(requestTypeTag, string(labels.RType)), tag(requestStatusTag, string(labels.RequestStatus)))
+
+	if labels.RequestSize > 0 && labels.RType != metrics.ReqTypeAMP {
+		endpoint := metrics.GetEndpointFromRequestType(labels.RType)
+		m.histogram("request_size_bytes", float64(labels.RequestSize), tag(endpointTag, string(endpoint)))
+	}
+
+	if labels.CookieFlag == metrics.CookieFlagNo {
+		m.incr("requests_without_cookie", tag(requestTypeTag, string(labels.RType)))
+	}
+
+	if labels.PubID != metrics.PublisherUnknown {
+		m.incr("account_requests", tag(accountTag, labels.PubID))
+	}
+}
+
+func (m *Metrics) RecordDebugRequest(debugEnabled bool, pubID string) {
+	if debugEnabled {
+		m.incr("debug_requests")
+		if !m.metricsDisabled.AccountDebug && pubID != metrics.PublisherUnknown {
+			m.incr("account_debug_requests", tag(accountTag, pubID))
+		}
+	}
+}
+
+func (m *Metrics) RecordStoredResponse(pubId string) {
+	m.incr("stored_responses")
+	if !m.metricsDisabled.AccountStoredResponses && pubId != metrics.PublisherUnknown {
+		m.incr("account_stored_responses", tag(accountTag, pubId))
+	}
+}
+
+func (m *Metrics) RecordGvlListRequest() {
+	m.incr("gvl_requests")
+}
+
+func (m *Metrics) RecordOptOutMatch(entryType metrics.OptOutEntryType) {
+	m.incr("optout_matches", tag(entryTypeTag, string(entryType)))
+}
+
+func (m *Metrics) RecordGvlListLatest(specVersion uint16, listVersion uint16, fetched time.Time) {
+	specVersionTagValue := tag(specVersionTag, strconv.Itoa(int(specVersion)))
+	m.gauge("gvl_latest_version", float64(listVersion), specVersionTagValue)
+	m.gauge("gvl_latest_fetch_timestamp", float64(fetched.Unix()), specVersionTagValue)
+}
+
+func (m *Metrics) RecordImps(labels metrics.ImpLabels) {
+	m.incr("impressions_requests",
+		boolTag(isBannerTag, labels.BannerImps),
+		boolTag(isVideoTag, labels.VideoImps),
+		boolTag(isAudioTag, labels.AudioImps),
+		boolTag(isNativeTag, labels.NativeImps))
+}
+
+func (m *Metrics) RecordRequestTime(labels metrics.Labels, length time.Duration) {
+	if labels.RequestStatus == metrics.RequestStatusOK {
+		m.timing("request_time", length, tag(requestTypeTag, string(labels.RType)))
+	}
+}
+
+func (m *Metrics) RecordStoredDataFetchTime(labels metrics.StoredDataLabels, length time.Duration) {
+	m.timing("stored_data_fetch_time", length,
+		tag(dataTypeTag, string(labels.DataType)),
+		tag(storedDataFetchTypeTag, string(labels.DataFetchType)))
+}
+
+func (m *Metrics) RecordStoredDataError(labels metrics.StoredDataLabels) {
+	m.incr("stored_data_errors",
+		tag(dataTypeTag, string(labels.DataType)),
+		tag(storedDataErrorTag, string(labels.Error)))
+}
+
+func (m *Metrics) RecordAdapterRequest(labels metrics.AdapterLabels) {
+	m.incr("adapter_requests", m.adapterTags(labels,
+		tag(cookieTag, string(labels.CookieFlag)),
+		boolTag(hasBidsTag, labels.AdapterBids == metrics.AdapterBidPresent))...)
+
+	for err := range labels.AdapterErrors {
+		m.incr("adapter_errors", m.adapterTags(labels, tag(adapterErrorTag, string(err)))...)
+	}
+}
+
+// Keeps track of created and reused connections to adapter bidders and the time from the
+// connection request, to the connection creation, or reuse from the pool across all engines
+func (m *Metrics) RecordAdapterConnections(adapterName openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration) {
+	if m.metricsDisabled.AdapterConnectionMetrics {
+		return
+	}
+
+	if connWasReused {
+		m.incr("adapter_connection_reused", adapterTagOf(adapterName))
+	} else {
+		m.incr("adapter_connection_created", adapterTagOf(adapterName))
+	}
+	m.timing("adapter_connection_wait", connWaitTime, adapterTagOf(adapterName))
+}
+
+func (m *Metrics) RecordDNSTime(dnsLookupTime time.Duration) {
+	m.timing("dns_lookup_time", dnsLookupTime)
+}
+
+func (m *Metrics) RecordTLSHandshakeTime(tlsHandshakeTime time.Duration) {
+	m.timing("tls_handshake_time", tlsHandshakeTime)
+}
+
+func (m *Metrics) RecordBidderServerResponseTime(bidderServerResponseTime time.Duration) {
+	m.timing("bidder_server_response_time", bidderServerResponseTime)
+}
+
+func (m *Metrics) RecordAdapterPanic(labels metrics.AdapterLabels) {
+	m.incr("adapter_panics", adapterTagOf(labels.Adapter))
+}
+
+func (m *Metrics) RecordAdapterBidReceived(labels metrics.AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool) {
+	markupDelivery := markupDeliveryNurl
+	if hasAdm {
+		markupDelivery = markupDeliveryAdm
+	}
+	m.incr("adapter_bids", m.adapterTags(labels, tag(markupDeliveryTag, markupDelivery))...)
+}
+
+func (m *Metrics) RecordAdapterPrice(labels metrics.AdapterLabels, cpm float64) {
+	m.histogram("adapter_prices", cpm, m.adapterTags(labels)...)
+}
+
+func (m *Metrics) RecordAdapterAuctionImps(adapter openrtb_ext.BidderName, pubID string, imps int) {
+	m.count("adapter_auction_imps", imps, m.adapterTags(metrics.AdapterLabels{Adapter: adapter, PubID: pubID})...)
+}
+
+func (m *Metrics) RecordAuctionBid(labels metrics.AuctionBidLabels, cpm float64, floorRatio float64) {
+	adapterLabels := metrics.AdapterLabels{Adapter: labels.Adapter, PubID: labels.PubID}
+	m.incr("adapter_auction_bids", m.adapterTags(adapterLabels, boolTag(dealTag, labels.Deal), boolTag(wonTag, labels.Won))...)
+	m.histogram("adapter_auction_bid_cpm", cpm, m.adapterTags(adapterLabels, boolTag(dealTag, labels.Deal))...)
+	if floorRatio > 0 {
+		m.histogram("adapter_auction_bid_floor_ratio", floorRatio, adapterTagOf(labels.Adapter))
+	}
+}
+
+func (m *Metrics) RecordOverheadTime(overhead metrics.OverheadType, duration time.Duration) {
+	m.timing("overhead_time", duration, tag(overheadTypeTag, overhead.String()))
+}
+
+func (m *Metrics) RecordAdapterTime(labels metrics.AdapterLabels, length time.Duration) {
+	if len(labels.AdapterErrors) == 0 {
+		m.timing("adapter_request_time", length, m.adapterTags(labels)...)
+	}
+}
+
+func (m *Metrics) RecordCookieSync(status metrics.CookieSyncStatus) {
+	m.incr("cookie_sync_requests", tag(statusTag, string(status)))
+}
+
+func (m *Metrics) RecordSyncerRequest(key string, status metrics.SyncerCookieSyncStatus) {
+	m.incr("syncer_requests", tag(syncerTag, key), tag(statusTag, string(status)))
+}
+
+func (m *Metrics) RecordSetUid(status metrics.SetUidStatus) {
+	m.incr("setuid_requests", tag(statusTag, string(status)))
+}
+
+func (m *Metrics) RecordSyncerSet(key string, status metrics.SyncerSetUidStatus) {
+	m.incr("syncer_sets", tag(syncerTag, key), tag(statusTag, string(status)))
+}
+
+func (m *Metrics) RecordStoredReqCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.count("stored_request_cache_performance", inc, tag(cacheResultTag, string(cacheResult)))
+}
+
+func (m *Metrics) RecordStoredImpCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.count("stored_impressions_cache_performance", inc, tag(cacheResultTag, string(cacheResult)))
+}
+
+func (m *Metrics) RecordAccountCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.count("account_cache_performance", inc, tag(cacheResultTag, string(cacheResult)))
+}
+
+func (m *Metrics) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
+	m.timing("prebidcache_write_time", length, boolTag(successTag, success))
+}
+
+func (m *Metrics) RecordRequestQueueTime(success bool, requestType metrics.RequestType, length time.Duration) {
+	successLabelFormatted := requestRejectLabel
+	if success {
+		successLabelFormatted = requestSuccessLabel
+	}
+	m.timing("request_queue_time", length,
+		tag(requestTypeTag, string(requestType)),
+		tag(requestStatusTag, successLabelFormatted))
+}
+
+func (m *Metrics) RecordTimeoutNotice(success bool) {
+	if success {
+		m.incr("timeout_notification", tag(successTag, requestSuccessful))
+	} else {
+		m.incr("timeout_notification", tag(successTag, requestFailed))
+	}
+}
+
+func (m *Metrics) RecordRequestPrivacy(privacy metrics.PrivacyLabels) {
+	if privacy.CCPAProvided {
+		m.incr("privacy_ccpa", tag(sourceTag, sourceRequest), boolTag(optOutTag, privacy.CCPAEnforced))
+	}
+
+	if privacy.COPPAEnforced {
+		m.incr("privacy_coppa", tag(sourceTag, sourceRequest))
+	}
+
+	if privacy.GDPREnforced {
+		m.incr("privacy_tcf", tag(versionTag, string(privacy.GDPRTCFVersion)), tag(sourceTag, sourceRequest))
+	}
+
+	if privacy.LMTEnforced {
+		m.incr("privacy_lmt", tag(sourceTag, sourceRequest))
+	}
+}
+
+func (m *Metrics) RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterBuyerUIDScrubbed {
+		return
+	}
+	m.incr("adapter_buyeruids_scrubbed", adapterTagOf(adapterName))
+}
+
+func (m *Metrics) RecordAdapterEIDsRemoved(adapterName openrtb_ext.BidderName, source string, count int) {
+	m.count("adapter_eids_removed", count, adapterTagOf(adapterName), tag(eidSourceTag, source))
+}
+
+func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterGDPRRequestBlocked {
+		return
+	}
+	m.incr("adapter_gdpr_requests_blocked", adapterTagOf(adapterName))
+}
+
+func (m *Metrics) RecordAdsCertReq(success bool) {
+	if success {
+		m.incr("ads_cert_requests", tag(successTag, requestSuccessful))
+	} else {
+		m.incr("ads_cert_requests", tag(successTag, requestFailed))
+	}
+}
+
+func (m *Metrics) RecordAdsCertSignTime(adsCertSignTime time.Duration) {
+	m.timing("ads_cert_sign_time", adsCertSignTime)
+}
+
+// recordBidValidation records a bid removed by the validation of the bid responses, for the adapter and the account
+func (m *Metrics) recordBidValidation(validation string, adapter openrtb_ext.BidderName, account string) {
+	m.incr(fmt.Sprintf("adapter_response_validation_%s", validation), adapterTagOf(adapter))
+
+	if !m.metricsDisabled.AccountAdapterDetails && account != metrics.PublisherUnknown {
+		m.incr(fmt.Sprintf("account_response_validation_%s", validation), tag(accountTag, account))
+	}
+}
+
+func (m *Metrics) RecordBidValidationCreativeSizeError(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("size_err", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationCreativeSizeWarn(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("size_warn", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationSecureMarkupError(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("secure_err", adapter, account)
+}
+
+func (m *Metrics) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.BidderName, account string) {
+	m.recordBidValidation("secure_warn", adapter, account)
+}
+
+func (m *Metrics) RecordModuleCalled(labels metrics.ModuleLabels, duration time.Duration) {
+	tags := m.moduleTags(labels)
+	m.incr("modules_called", tags...)
+	m.timing("modules_duration", duration, tags...)
+}
+
+func (m *Metrics) RecordModuleFailed(labels metrics.ModuleLabels) {
+	m.incr("modules_failed", m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessNooped(labels metrics.ModuleLabels) {
+	m.incr("modules_success_noops", m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessUpdated(labels metrics.ModuleLabels) {
+	m.incr("modules_success_updates", m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleSuccessRejected(labels metrics.ModuleLabels) {
+	m.incr("modules_success_rejects", m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleExecutionError(labels metrics.ModuleLabels) {
+	m.incr("modules_execution_errors", m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordModuleTimeout(labels metrics.ModuleLabels) {
+	m.incr("modules_timeouts", m.moduleTags(labels)...)
+}
+
+func (m *Metrics) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
+	m.incr("adapter_throttled", adapterTagOf(adapterName))
+}
+
+func (m *Metrics) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
+	if m.metricsDisabled.AdapterConnectionMetrics || m.metricsDisabled.AdapterConnectionDialMetrics {
+		return
+	}
+	m.incr("adapter_connection_dial_errors", adapterTagOf(adapterName))
+}
+
+func (m *Metrics) RecordAdapterConnectionDialTime(adapterName openrtb_ext.BidderName, dialStartTime time.Duration) {
+	if m.metricsDisabled.AdapterConnectionMetrics || m.metricsDisabled.AdapterConnectionDialMetrics {
+		return
+	}
+	m.timing("adapter_connection_dial_time", dialStartTime, adapterTagOf(adapterName))
+}
@@ -0,0 +1,211 @@
+package statsdmetrics
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listen returns the address of a local UDP agent, and a function returning the lines it received
+func listen(t *testing.T) (string, func() []string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn.LocalAddr().String(), func() []string { return readLines(t, conn) }
+}
+
+func readLines(t *testing.T, conn net.PacketConn) []string {
+	var lines []string
+	buffer := make([]byte, 65536)
+	for {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return lines
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(buffer[:n])), "\n") {
+			lines = append(lines, line)
+		}
+	}
+}
+
+func newTestMetrics(t *testing.T, address string, disabled config.DisabledMetrics) *Metrics {
+	m, err := NewMetrics(config.StatsDMetrics{
+		Address:               address,
+		Namespace:             "prebidserver.",
+		Tags:                  []string{"datacenter:us-east"},
+		SampleRate:            1,
+		AggregationIntervalMS: 100,
+	}, disabled)
+	require.NoError(t, err)
+	return m
+}
+
+func TestRecordRequest(t *testing.T) {
+	address, received := listen(t)
+	m := newTestMetrics(t, address, config.DisabledMetrics{})
+
+	labels := metrics.Labels{
+		RType:         metrics.ReqTypeORTB2Web,
+		RequestStatus: metrics.RequestStatusOK,
+		CookieFlag:    metrics.CookieFlagNo,
+		PubID:         "acct-1",
+		RequestSize:   600,
+	}
+	m.RecordRequest(labels)
+	m.RecordRequest(labels)
+	m.RecordRequestTime(labels, 120*time.Millisecond)
+	m.RecordStoredReqCacheResult(metrics.CacheHit, 3)
+	m.Shutdown()
+
+	lines := received()
+	assert.Contains(t, lines, "prebidserver.requests:2|c|#datacenter:us-east,request_type:openrtb2-web,request_status:ok")
+	assert.Contains(t, lines, "prebidserver.requests_without_cookie:2|c|#datacenter:us-east,request_type:openrtb2-web")
+	assert.Contains(t, lines, "prebidserver.account_requests:2|c|#datacenter:us-east,account:acct-1")
+	assert.Contains(t, lines, "prebidserver.request_size_bytes:600|h|#datacenter:us-east,endpoint:auction")
+	assert.Contains(t, lines, "prebidserver.request_time:120.000000|ms|#datacenter:us-east,request_type:openrtb2-web")
+	assert.Contains(t, lines, "prebidserver.stored_request_cache_performance:3|c|#datacenter:us-east,cache_result:hit")
+}
+
+func TestRecordAdapterAccountDetails(t *testing.T) {
+	testCases := []struct {
+		description  string
+		disabled     bool
+		expectedTags string
+	}{
+		{
+			description:  "Account details enabled",
+			disabled:     false,
+			expectedTags: "adapter:appnexus,account:acct-1",
+		},
+		{
+			description:  "Account details disabled",
+			disabled:     true,
+			expectedTags: "adapter:appnexus",
+		},
+	}
+
+	for _, test := range testCases {
+		address, received := listen(t)
+		m := newTestMetrics(t, address, config.DisabledMetrics{AccountAdapterDetails: test.disabled})
+		labels := metrics.AdapterLabels{
+			Adapter:     openrtb_ext.BidderName("AppNexus"),
+			PubID:       "acct-1",
+			CookieFlag:  metrics.CookieFlagYes,
+			AdapterBids: metrics.AdapterBidPresent,
+		}
+
+		m.RecordAdapterRequest(labels)
+		m.RecordAdapterBidReceived(labels, openrtb_ext.BidTypeBanner, false)
+		m.RecordBidValidationSecureMarkupWarn(labels.Adapter, "acct-1")
+		m.Shutdown()
+
+		lines := received()
+		assert.Contains(t, lines, "prebidserver.adapter_requests:1|c|#datacenter:us-east,cookie:exists,has_bids:true,"+test.expectedTags, test.description)
+		assert.Contains(t, lines, "prebidserver.adapter_bids:1|c|#datacenter:us-east,delivery:nurl,"+test.expectedTags, test.description)
+		assert.Contains(t, lines, "prebidserver.adapter_response_validation_secure_warn:1|c|#datacenter:us-east,adapter:appnexus", test.description)
+		if test.disabled {
+			assert.NotContains(t, lines, "prebidserver.account_response_validation_secure_warn:1|c|#datacenter:us-east,account:acct-1", test.description)
+		} else {
+			assert.Contains(t, lines, "prebidserver.account_response_validation_secure_warn:1|c|#datacenter:us-east,account:acct-1", test.description)
+		}
+	}
+}
+
+func TestRecordModule(t *testing.T) {
+	address, received := listen(t)
+	m := newTestMetrics(t, address, config.DisabledMetrics{AccountModulesMetrics: true})
+
+	labels := metrics.ModuleLabels{Module: "acme", Stage: "entrypoint", AccountID: "acct-1"}
+	m.RecordModuleCalled(labels, 5*time.Millisecond)
+	m.RecordModuleTimeout(labels)
+	m.Shutdown()
+
+	lines := received()
+	assert.Contains(t, lines, "prebidserver.modules_called:1|c|#datacenter:us-east,module:acme,stage:entrypoint")
+	assert.Contains(t, lines, "prebidserver.modules_duration:5.000000|ms|#datacenter:us-east,module:acme,stage:entrypoint")
+	assert.Contains(t, lines, "prebidserver.modules_timeouts:1|c|#datacenter:us-east,module:acme,stage:entrypoint")
+}
+
+func TestRecordAdapterConnectionsDisabled(t *testing.T) {
+	address, received := listen(t)
+	m := newTestMetrics(t, address, config.DisabledMetrics{AdapterConnectionMetrics: true, AdapterGDPRRequestBlocked: true})
+
+	m.RecordAdapterConnections(openrtb_ext.BidderAppnexus, true, time.Millisecond)
+	m.RecordAdapterConnectionDialTime(openrtb_ext.BidderAppnexus, time.Millisecond)
+	m.RecordAdapterGDPRRequestBlocked(openrtb_ext.BidderAppnexus)
+	m.RecordGvlListRequest()
+	m.Shutdown()
+
+	assert.Equal(t, []string{"prebidserver.gvl_requests:1|c|#datacenter:us-east"}, received())
+}
+
+func TestUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dsd.socket")
+	conn, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	m := newTestMetrics(t, "unix://"+path, config.DisabledMetrics{})
+	m.RecordTMaxTimeout()
+	m.Shutdown()
+
+	assert.Equal(t, []string{"prebidserver.tmax_timeout:1|c|#datacenter:us-east"}, readLines(t, conn))
+}
+
+func TestRecordAuctionBid(t *testing.T) {
+	address, received := listen(t)
+	m := newTestMetrics(t, address, config.DisabledMetrics{})
+
+	m.RecordAdapterAuctionImps(openrtb_ext.BidderAppnexus, "acct-1", 3)
+	m.RecordAuctionBid(metrics.AuctionBidLabels{Adapter: openrtb_ext.BidderAppnexus, PubID: "acct-1", Deal: true}, 2.5, 0)
+	m.Shutdown()
+
+	lines := received()
+	assert.Contains(t, lines, "prebidserver.adapter_auction_imps:3|c|#datacenter:us-east,adapter:appnexus,account:acct-1")
+	assert.Contains(t, lines, "prebidserver.adapter_auction_bids:1|c|#datacenter:us-east,deal:true,won:false,adapter:appnexus,account:acct-1")
+	assert.Contains(t, lines, "prebidserver.adapter_auction_bid_cpm:2.5|h|#datacenter:us-east,deal:true,adapter:appnexus,account:acct-1")
+	for _, line := range lines {
+		assert.NotContains(t, line, "adapter_auction_bid_floor_ratio")
+	}
+}
+
+func TestRecordAdapterEIDsRemoved(t *testing.T) {
+	address, received := listen(t)
+	m := newTestMetrics(t, address, config.DisabledMetrics{})
+
+	m.RecordAdapterEIDsRemoved(openrtb_ext.BidderAppnexus, "liveramp.com", 2)
+	m.Shutdown()
+
+	assert.Contains(t, received(), "prebidserver.adapter_eids_removed:2|c|#datacenter:us-east,adapter:appnexus,eid_source:liveramp.com")
+}
+
+func TestRecordOptOutMatch(t *testing.T) {
+	address, received := listen(t)
+	m := newTestMetrics(t, address, config.DisabledMetrics{})
+
+	m.RecordOptOutMatch(metrics.OptOutEntryEID)
+	m.Shutdown()
+
+	assert.Contains(t, received(), "prebidserver.optout_matches:1|c|#datacenter:us-east,entry_type:eid")
+}
+
+func TestRecordGvlListLatest(t *testing.T) {
+	address, received := listen(t)
+	m := newTestMetrics(t, address, config.DisabledMetrics{})
+
+	m.RecordGvlListLatest(3, 42, time.Unix(1790000000, 0))
+	m.Shutdown()
+
+	lines := received()
+	assert.Contains(t, lines, "prebidserver.gvl_latest_version:42|g|#datacenter:us-east,spec_version:3")
+	assert.Contains(t, lines, "prebidserver.gvl_latest_fetch_timestamp:1790000000|g|#datacenter:us-east,spec_version:3")
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/readiness"
 )
 
 // ModuleDeps provides dependencies that custom modules may need for hooks execution.
@@ -12,4 +13,6 @@ type ModuleDeps struct {
 	HTTPClient    *http.Client
 	RateConvertor *currency.RateConverter
 	Geoscope      map[string][]string
+	// Readiness receives the readiness checks of the modules. It's nil if the readiness endpoint is disabled.
+	Readiness *readiness.Checks
 }
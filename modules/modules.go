@@ -46,6 +46,7 @@ type builder struct {
 //
 // Method returns a hooks.HookRepository and a map of modules to a list of stage names
 // for which module provides hooks or an error occurred during modules initialization.
+// The modules implementing the Readier interface are added to the readiness checks of deps.
 func (m *builder) Build(
 	cfg config.Modules,
 	deps moduledeps.ModuleDeps,
@@ -92,6 +93,7 @@ func (m *builder) Build(
 	repo, err := hooks.NewHookRepository(modules)
 
 	sdm := NewShutdownModules(modules)
+	addReadinessChecks(deps.Readiness, modules)
 
 	return repo, collection, sdm, err
 }
@@ -0,0 +1,22 @@
+package modules
+
+import (
+	"context"
+
+	"github.com/prebid/prebid-server/v3/readiness"
+)
+
+// Readier is an interface that defines a method for checking whether a module is ready to serve requests.
+type Readier interface {
+	Ready(ctx context.Context) error
+}
+
+// addReadinessChecks adds a readiness check named "modules.<vendor>.<module>" for each module implementing the
+// Readier interface.
+func addReadinessChecks(checks *readiness.Checks, modules map[string]interface{}) {
+	for id, module := range modules {
+		if v, ok := module.(Readier); ok {
+			checks.Add("modules."+id, v.Ready)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/readiness"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockReadyModule is a test implementation of the Readier interface
+type mockReadyModule struct {
+	err error
+}
+
+func (m mockReadyModule) Ready(_ context.Context) error {
+	return m.err
+}
+
+func TestAddReadinessChecks(t *testing.T) {
+	checks := readiness.NewChecks(config.Readiness{Enabled: true, TimeoutMS: 100, Critical: []string{"modules.acme"}})
+	modules := map[string]interface{}{
+		"acme.ready":     mockReadyModule{},
+		"acme.not_ready": mockReadyModule{err: errors.New("model not loaded")},
+		"other.module":   &nonShutdownModule{name: "module"},
+	}
+
+	addReadinessChecks(checks, modules)
+
+	assert.Equal(t, readiness.Report{
+		Ready: false,
+		Checks: map[string]readiness.Result{
+			"modules.acme.ready":     {Status: readiness.StatusReady, Critical: true},
+			"modules.acme.not_ready": {Status: readiness.StatusNotReady, Critical: true, Error: "model not loaded"},
+		},
+	}, checks.Run(context.Background()))
+}
+
+func TestAddReadinessChecksDisabled(t *testing.T) {
+	assert.NotPanics(t, func() {
+		addReadinessChecks(nil, map[string]interface{}{"acme.ready": mockReadyModule{}})
+	})
+}
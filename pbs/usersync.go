@@ -25,6 +25,7 @@ type UserSyncDeps struct {
 	HostCookieConfig *config.HostCookie
 	PriorityGroups   [][]string
 	CertPool         *x509.CertPool
+	CookieStore      *usersync.CookieStore
 }
 
 // Struct for parsing json in google's response
@@ -85,8 +86,15 @@ func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httpr
 	}
 
 	// Read Cookie
-	pc := usersync.ReadCookie(r, decoder, deps.HostCookieConfig)
+	pc := deps.CookieStore.ReadCookie(r, decoder, deps.HostCookieConfig)
 	usersync.SyncHostCookie(r, pc, deps.HostCookieConfig)
+	if optout != "" {
+		if err := deps.CookieStore.Forget(r.Context(), pc); err != nil {
+			logger.Errorf("Opt Out failed to remove the UIDs from the id store: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
 	pc.SetOptOut(optout != "")
 
 	// Write Cookie
@@ -15,6 +15,8 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/readiness"
+	"github.com/prebid/prebid-server/v3/tracing"
 
 	"github.com/buger/jsonparser"
 	"golang.org/x/net/context/ctxhttp"
@@ -62,6 +64,24 @@ func NewClient(httpClient *http.Client, conf *config.Cache, extCache *config.Ext
 	}
 }
 
+// NewStatusCheck returns a readiness check which fails unless Prebid Cache responds successfully to GET /status
+func NewStatusCheck(httpClient *http.Client, conf *config.Cache) readiness.Check {
+	statusUrl := conf.GetBaseURL() + "/status"
+	return func(ctx context.Context) error {
+		resp, err := ctxhttp.Get(ctx, httpClient, statusUrl)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("GET %s returned %d", statusUrl, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
 type clientImpl struct {
 	httpClient          *http.Client
 	putUrl              string
@@ -90,6 +110,11 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 		return nil, errs
 	}
 
+	ctx, span := tracing.StartClient(ctx, tracing.SpanCachePut)
+	defer func() {
+		tracing.EndWithErrors(span, errs)
+	}()
+
 	uuidsToReturn := make([]string, len(values))
 
 	postBody, err := encodeValues(values)
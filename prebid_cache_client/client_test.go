@@ -169,6 +169,46 @@ func TestSuccessfulPut(t *testing.T) {
 	metricsMock.AssertExpectations(t)
 }
 
+func TestStatusCheck(t *testing.T) {
+	testCases := []struct {
+		name        string
+		statusCode  int
+		expectedErr bool
+	}{
+		{
+			name:       "ok",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "no-content",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:        "unavailable",
+			statusCode:  http.StatusServiceUnavailable,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/status", r.URL.Path)
+				w.WriteHeader(test.statusCode)
+			}))
+			defer server.Close()
+
+			check := NewStatusCheck(server.Client(), &config.Cache{Scheme: "http", Host: server.Listener.Addr().String()})
+			err := check(context.Background())
+			if test.expectedErr {
+				assert.EqualError(t, err, fmt.Sprintf("GET %s/status returned %d", server.URL, test.statusCode))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestEncodeValueToBuffer(t *testing.T) {
 	buf := new(bytes.Buffer)
 	testCache := Cacheable{
@@ -3,6 +3,7 @@ package privacy
 import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy/pseudonym"
 )
 
 type ActivityResult int
@@ -26,6 +27,11 @@ func NewRequestFromBidRequest(r openrtb_ext.RequestWrapper) ActivityRequest {
 type ActivityRequest struct {
 	policies   *Policies
 	bidRequest *openrtb_ext.RequestWrapper
+	// gpcHeader is the Sec-GPC header of the HTTP request, set by the ActivityControl evaluating the request
+	gpcHeader bool
+	// endpointChannel is the channel of the endpoint of the HTTP request, set by the ActivityControl evaluating the
+	// request
+	endpointChannel string
 }
 
 func (r ActivityRequest) IsPolicies() bool {
@@ -40,28 +46,59 @@ type ActivityControl struct {
 	plans      map[Activity]ActivityPlan
 	IPv6Config config.IPv6
 	IPv4Config config.IPv4
+
+	precisionPolicies map[string]config.PrecisionPolicy
+	precisionPolicy   string
+
+	pseudonymization config.AccountPseudonymization
+	pseudonymKeys    *pseudonym.Keyring
+	account          string
+
+	optedOut        bool
+	gpcHeader       bool
+	endpointChannel string
 }
 
 func NewActivityControl(cfg *config.AccountPrivacy) ActivityControl {
 	ac := ActivityControl{}
 
-	if cfg == nil || cfg.AllowActivities == nil {
+	if cfg == nil {
 		return ac
 	}
 
-	plans := make(map[Activity]ActivityPlan, 8)
-	plans[ActivitySyncUser] = buildPlan(cfg.AllowActivities.SyncUser)
-	plans[ActivityFetchBids] = buildPlan(cfg.AllowActivities.FetchBids)
-	plans[ActivityEnrichUserFPD] = buildPlan(cfg.AllowActivities.EnrichUserFPD)
-	plans[ActivityReportAnalytics] = buildPlan(cfg.AllowActivities.ReportAnalytics)
-	plans[ActivityTransmitUserFPD] = buildPlan(cfg.AllowActivities.TransmitUserFPD)
-	plans[ActivityTransmitPreciseGeo] = buildPlan(cfg.AllowActivities.TransmitPreciseGeo)
-	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(cfg.AllowActivities.TransmitUniqueRequestIds)
-	plans[ActivityTransmitTIDs] = buildPlan(cfg.AllowActivities.TransmitTids)
-	ac.plans = plans
-
 	ac.IPv4Config = cfg.IPv4Config
 	ac.IPv6Config = cfg.IPv6Config
+	ac.precisionPolicies = cfg.PrecisionPolicies
+	ac.precisionPolicy = cfg.PrecisionPolicy
+	ac.pseudonymization = cfg.Pseudonymization
+
+	usnatRules := newUSNatRules(cfg.Modules.USNat)
+	if cfg.AllowActivities == nil && usnatRules == nil {
+		return ac
+	}
+
+	var allowActivities config.AllowActivities
+	if cfg.AllowActivities != nil {
+		allowActivities = *cfg.AllowActivities
+	}
+
+	plans := make(map[Activity]ActivityPlan, 8)
+	plans[ActivitySyncUser] = buildPlan(allowActivities.SyncUser)
+	plans[ActivityFetchBids] = buildPlan(allowActivities.FetchBids)
+	plans[ActivityEnrichUserFPD] = buildPlan(allowActivities.EnrichUserFPD)
+	plans[ActivityReportAnalytics] = buildPlan(allowActivities.ReportAnalytics)
+	plans[ActivityTransmitUserFPD] = buildPlan(allowActivities.TransmitUserFPD)
+	plans[ActivityTransmitPreciseGeo] = buildPlan(allowActivities.TransmitPreciseGeo)
+	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(allowActivities.TransmitUniqueRequestIds)
+	plans[ActivityTransmitTIDs] = buildPlan(allowActivities.TransmitTids)
+
+	// the privacy modules enforce the privacy signals of the request before the rules of the account
+	for activity, rule := range usnatRules {
+		plan := plans[activity]
+		plan.rules = append([]Rule{rule}, plan.rules...)
+		plans[activity] = plan
+	}
+	ac.plans = plans
 
 	return ac
 }
@@ -83,9 +120,16 @@ func cfgToRules(rules []config.ActivityRule) []Rule {
 		}
 
 		er := ConditionRule{
-			result:        result,
-			componentName: r.Condition.ComponentName,
-			componentType: r.Condition.ComponentType,
+			result:          result,
+			componentName:   r.Condition.ComponentName,
+			componentType:   r.Condition.ComponentType,
+			gppSID:          r.Condition.GPPSID,
+			geo:             r.Condition.Geo,
+			gpc:             r.Condition.GPC,
+			coppa:           r.Condition.COPPA,
+			tcfInScope:      r.Condition.TCFInScope,
+			channel:         r.Condition.Channel,
+			precisionPolicy: r.PrecisionPolicy,
 		}
 		enfRules = append(enfRules, er)
 	}
@@ -99,7 +143,31 @@ func cfgToDefaultResult(activityDefault *bool) bool {
 	return *activityDefault
 }
 
+// SetOptedOut denies every activity but fetchBids, for the users of the opt-out registry. They're still shown ads,
+// but none of their data is shared.
+func (e *ActivityControl) SetOptedOut() {
+	e.optedOut = true
+}
+
+// SetGPCHeader sets the Sec-GPC header of the HTTP request, which the gpc condition of the rules evaluates along with
+// the GPC signal of the request itself
+func (e *ActivityControl) SetGPCHeader(secGPC string) {
+	e.gpcHeader = secGPC == "1"
+}
+
+// SetEndpointChannel sets the channel of the endpoint, like "amp" or "video", which the channel condition of the rules
+// evaluates when the request doesn't have a channel
+func (e *ActivityControl) SetEndpointChannel(channel config.ChannelType) {
+	e.endpointChannel = string(channel)
+}
+
 func (e ActivityControl) Allow(activity Activity, target Component, request ActivityRequest) bool {
+	if e.optedOut && activity != ActivityFetchBids {
+		return false
+	}
+	request.gpcHeader = e.gpcHeader
+	request.endpointChannel = e.endpointChannel
+
 	plan, planDefined := e.plans[activity]
 
 	if !planDefined {
@@ -109,17 +177,41 @@ func (e ActivityControl) Allow(activity Activity, target Component, request Acti
 	return plan.Evaluate(target, request)
 }
 
+// GeoPrecision returns the precision policy reducing the IP addresses and geo sent to the target when it can't
+// receive precise geo: the policy of the transmitPreciseGeo rule denying it, or else the default policy of the account
+func (e ActivityControl) GeoPrecision(target Component, request ActivityRequest) config.PrecisionPolicy {
+	name := e.precisionPolicy
+	request.gpcHeader = e.gpcHeader
+	request.endpointChannel = e.endpointChannel
+	if plan, ok := e.plans[ActivityTransmitPreciseGeo]; ok {
+		if rule, result := plan.evaluateRules(target, request); result == ActivityDeny {
+			if conditionRule, ok := rule.(ConditionRule); ok && conditionRule.precisionPolicy != "" {
+				name = conditionRule.precisionPolicy
+			}
+		}
+	}
+	return e.precisionPolicies[name]
+}
+
 type ActivityPlan struct {
 	defaultResult bool
 	rules         []Rule
 }
 
 func (p ActivityPlan) Evaluate(target Component, request ActivityRequest) bool {
+	if _, result := p.evaluateRules(target, request); result != ActivityAbstain {
+		return result == ActivityAllow
+	}
+	return p.defaultResult
+}
+
+// evaluateRules returns the first rule allowing or denying the activity with its result, or abstains
+func (p ActivityPlan) evaluateRules(target Component, request ActivityRequest) (Rule, ActivityResult) {
 	for _, rule := range p.rules {
 		result := rule.Evaluate(target, request)
 		if result == ActivityDeny || result == ActivityAllow {
-			return result == ActivityAllow
+			return rule, result
 		}
 	}
-	return p.defaultResult
+	return nil, ActivityAbstain
 }
@@ -61,6 +61,37 @@ func TestNewActivityControl(t *testing.T) {
 	}
 }
 
+func TestCfgToRules(t *testing.T) {
+	rules := cfgToRules([]config.ActivityRule{
+		{
+			Allow: false,
+			Condition: config.ActivityCondition{
+				ComponentName: []string{"bidderA"},
+				GPPSID:        []int8{7},
+				Geo:           []string{"USA.CA"},
+				GPC:           ptrutil.ToPtr(true),
+				COPPA:         ptrutil.ToPtr(false),
+				TCFInScope:    ptrutil.ToPtr(false),
+				Channel:       []string{"web"},
+			},
+		},
+	})
+
+	expected := []Rule{
+		ConditionRule{
+			result:        ActivityDeny,
+			componentName: []string{"bidderA"},
+			gppSID:        []int8{7},
+			geo:           []string{"USA.CA"},
+			gpc:           ptrutil.ToPtr(true),
+			coppa:         ptrutil.ToPtr(false),
+			tcfInScope:    ptrutil.ToPtr(false),
+			channel:       []string{"web"},
+		},
+	}
+	assert.Equal(t, expected, rules)
+}
+
 func TestCfgToDefaultResult(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -131,6 +162,37 @@ func TestActivityControlAllow(t *testing.T) {
 			target:         Component{Type: "bidder", Name: "bidderA"},
 			activityResult: true,
 		},
+		{
+			name: "opted_out_denied_despite_plan",
+			activityControl: ActivityControl{optedOut: true, plans: map[Activity]ActivityPlan{
+				ActivityTransmitUserFPD: getTestActivityPlan(ActivityAllow)}},
+			activity:       ActivityTransmitUserFPD,
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			activityResult: false,
+		},
+		{
+			name: "gpc_header_denied",
+			activityControl: ActivityControl{gpcHeader: true, plans: map[Activity]ActivityPlan{
+				ActivityTransmitUserFPD: {defaultResult: true, rules: []Rule{ConditionRule{result: ActivityDeny, gpc: ptrutil.ToPtr(true)}}}}},
+			activity:       ActivityTransmitUserFPD,
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			activityResult: false,
+		},
+		{
+			name: "endpoint_channel_denied",
+			activityControl: ActivityControl{endpointChannel: "video", plans: map[Activity]ActivityPlan{
+				ActivityTransmitUserFPD: {defaultResult: true, rules: []Rule{ConditionRule{result: ActivityDeny, channel: []string{"video"}}}}}},
+			activity:       ActivityTransmitUserFPD,
+			target:         Component{Type: "bidder", Name: "bidderA"},
+			activityResult: false,
+		},
+		{
+			name:            "opted_out_fetch_bids_allowed",
+			activityControl: ActivityControl{optedOut: true},
+			activity:        ActivityFetchBids,
+			target:          Component{Type: "bidder", Name: "bidderA"},
+			activityResult:  true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -142,6 +204,78 @@ func TestActivityControlAllow(t *testing.T) {
 	}
 }
 
+func TestActivityControlGeoPrecision(t *testing.T) {
+	policies := map[string]config.PrecisionPolicy{
+		"default": {IPv4KeepBits: ptrutil.ToPtr(24)},
+		"coarse":  {IPv4KeepBits: ptrutil.ToPtr(16), DropZip: true},
+	}
+	cfg := &config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitPreciseGeo: config.Activity{
+				Rules: []config.ActivityRule{
+					{Allow: false, Condition: config.ActivityCondition{ComponentName: []string{"bidderA"}}, PrecisionPolicy: "coarse"},
+					{Allow: false, Condition: config.ActivityCondition{ComponentName: []string{"bidderB"}}},
+					{Allow: true, Condition: config.ActivityCondition{ComponentName: []string{"bidderC"}}, PrecisionPolicy: "coarse"},
+				},
+			},
+		},
+		PrecisionPolicies: policies,
+		PrecisionPolicy:   "default",
+	}
+
+	testCases := []struct {
+		name              string
+		cfg               *config.AccountPrivacy
+		target            string
+		expectedPrecision config.PrecisionPolicy
+	}{
+		{
+			name:              "rule_policy",
+			cfg:               cfg,
+			target:            "bidderA",
+			expectedPrecision: policies["coarse"],
+		},
+		{
+			name:              "rule_without_policy",
+			cfg:               cfg,
+			target:            "bidderB",
+			expectedPrecision: policies["default"],
+		},
+		{
+			name:              "allow_rule",
+			cfg:               cfg,
+			target:            "bidderC",
+			expectedPrecision: policies["default"],
+		},
+		{
+			name:              "no_rule",
+			cfg:               cfg,
+			target:            "bidderD",
+			expectedPrecision: policies["default"],
+		},
+		{
+			name:              "no_activities",
+			cfg:               &config.AccountPrivacy{PrecisionPolicies: policies, PrecisionPolicy: "coarse"},
+			target:            "bidderA",
+			expectedPrecision: policies["coarse"],
+		},
+		{
+			name:              "no_policies",
+			cfg:               &config.AccountPrivacy{},
+			target:            "bidderA",
+			expectedPrecision: config.PrecisionPolicy{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			ac := NewActivityControl(test.cfg)
+			precision := ac.GeoPrecision(Component{Type: ComponentTypeBidder, Name: test.target}, ActivityRequest{})
+			assert.Equal(t, test.expectedPrecision, precision)
+		})
+	}
+}
+
 func TestActivityRequest(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		r := ActivityRequest{}
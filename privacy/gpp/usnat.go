@@ -0,0 +1,203 @@
+package gpp
+
+import (
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspct"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+)
+
+// Values of the US National and US State fields. Notices are provided or not, opt-outs are opted out or not, and
+// consents are refused or given. Zero is always "not applicable".
+const (
+	USNatNotApplicable byte = 0
+	USNatProvided      byte = 1
+	USNatNotProvided   byte = 2
+	USNatOptedOut      byte = 1
+	USNatDidNotOptOut  byte = 2
+	USNatNoConsent     byte = 1
+	USNatConsent       byte = 2
+	USNatYes           byte = 1
+	USNatNo            byte = 2
+)
+
+// US National sensitive data categories, as indexes of USNat.SensitiveDataProcessing
+const (
+	USNatSensitiveRacialOrigin = iota
+	USNatSensitiveReligiousBeliefs
+	USNatSensitiveHealth
+	USNatSensitiveSexLife
+	USNatSensitiveCitizenship
+	USNatSensitiveGenetic
+	USNatSensitiveBiometric
+	USNatSensitivePreciseGeolocation
+	USNatSensitiveIdentificationDocuments
+	USNatSensitiveFinancialAccount
+	USNatSensitiveUnionMembership
+	USNatSensitiveCommunications
+	usNatSensitiveCategories
+)
+
+// USNat holds the fields of the US National section. The fields of the US State sections are mapped onto it.
+type USNat struct {
+	SectionID                           gppConstants.SectionID
+	SharingNotice                       byte
+	SaleOptOutNotice                    byte
+	SharingOptOutNotice                 byte
+	TargetedAdvertisingOptOutNotice     byte
+	SensitiveDataProcessingOptOutNotice byte
+	SensitiveDataLimitUseNotice         byte
+	SaleOptOut                          byte
+	SharingOptOut                       byte
+	TargetedAdvertisingOptOut           byte
+	// SensitiveDataProcessing is indexed by the US National sensitive data categories. The states opting out of the
+	// processing of sensitive data, and those requiring consent to it, both use 1 to forbid it.
+	SensitiveDataProcessing         [usNatSensitiveCategories]byte
+	KnownChildSensitiveDataConsents []byte
+	PersonalDataConsents            byte
+	MspaCoveredTransaction          byte
+	MspaOptOutOptionMode            byte
+	MspaServiceProviderMode         byte
+	Gpc                             bool
+}
+
+// sensitiveCategoriesCA maps the sensitive data fields of California onto the US National categories. Its racial
+// origin field also covers the religious beliefs and the union membership.
+var sensitiveCategoriesCA = []int{
+	USNatSensitiveIdentificationDocuments,
+	USNatSensitiveFinancialAccount,
+	USNatSensitivePreciseGeolocation,
+	USNatSensitiveRacialOrigin,
+	USNatSensitiveCommunications,
+	USNatSensitiveGenetic,
+	USNatSensitiveBiometric,
+	USNatSensitiveHealth,
+	USNatSensitiveSexLife,
+}
+
+// sensitiveCategoriesUT maps the sensitive data fields of Utah onto the US National categories
+var sensitiveCategoriesUT = []int{
+	USNatSensitiveRacialOrigin,
+	USNatSensitiveReligiousBeliefs,
+	USNatSensitiveSexLife,
+	USNatSensitiveCitizenship,
+	USNatSensitiveHealth,
+	USNatSensitiveGenetic,
+	USNatSensitiveBiometric,
+	USNatSensitivePreciseGeolocation,
+}
+
+// ReadUSNat returns the US National fields of the section, and true if it's the US National section or a US State
+// section mapped onto it. Sections which failed to parse, or which aren't US sections, return false.
+func ReadUSNat(section gpplib.Section) (USNat, bool) {
+	switch s := section.(type) {
+	case uspnat.USPNAT:
+		core := s.CoreSegment
+		usnat := USNat{
+			SectionID:                           gppConstants.SectionUSPNAT,
+			SharingNotice:                       core.SharingNotice,
+			SaleOptOutNotice:                    core.SaleOptOutNotice,
+			SharingOptOutNotice:                 core.SharingOptOutNotice,
+			TargetedAdvertisingOptOutNotice:     core.TargetedAdvertisingOptOutNotice,
+			SensitiveDataProcessingOptOutNotice: core.SensitiveDataProcessingOptOutNotice,
+			SensitiveDataLimitUseNotice:         core.SensitiveDataLimitUseNotice,
+			SaleOptOut:                          core.SaleOptOut,
+			SharingOptOut:                       core.SharingOptOut,
+			TargetedAdvertisingOptOut:           core.TargetedAdvertisingOptOut,
+			KnownChildSensitiveDataConsents:     core.KnownChildSensitiveDataConsents,
+			PersonalDataConsents:                core.PersonalDataConsents,
+			MspaCoveredTransaction:              core.MspaCoveredTransaction,
+			MspaOptOutOptionMode:                core.MspaOptOutOptionMode,
+			MspaServiceProviderMode:             core.MspaServiceProviderMode,
+			Gpc:                                 s.GPCSegment.Gpc,
+		}
+		copy(usnat.SensitiveDataProcessing[:], core.SensitiveDataProcessing)
+		return usnat, true
+	case uspca.USPCA:
+		core := s.CoreSegment
+		usnat := USNat{
+			SectionID:                       gppConstants.SectionUSPCA,
+			SaleOptOutNotice:                core.SaleOptOutNotice,
+			SharingOptOutNotice:             core.SharingOptOutNotice,
+			SensitiveDataLimitUseNotice:     core.SensitiveDataLimitUseNotice,
+			SaleOptOut:                      core.SaleOptOut,
+			SharingOptOut:                   core.SharingOptOut,
+			KnownChildSensitiveDataConsents: core.KnownChildSensitiveDataConsents,
+			PersonalDataConsents:            core.PersonalDataConsents,
+			MspaCoveredTransaction:          core.MspaCoveredTransaction,
+			MspaOptOutOptionMode:            core.MspaOptOutOptionMode,
+			MspaServiceProviderMode:         core.MspaServiceProviderMode,
+			Gpc:                             s.GPCSegment.Gpc,
+		}
+		usnat.mapSensitiveDataProcessing(core.SensitiveDataProcessing, sensitiveCategoriesCA)
+		usnat.SensitiveDataProcessing[USNatSensitiveReligiousBeliefs] = usnat.SensitiveDataProcessing[USNatSensitiveRacialOrigin]
+		usnat.SensitiveDataProcessing[USNatSensitiveUnionMembership] = usnat.SensitiveDataProcessing[USNatSensitiveRacialOrigin]
+		return usnat, true
+	case uspva.USPVA:
+		return readCommonUS(gppConstants.SectionUSPVA, s.CoreSegment, sections.CommonUSGPCSegment{}), true
+	case uspco.USPCO:
+		return readCommonUS(gppConstants.SectionUSPCO, s.CoreSegment, s.GPCSegment), true
+	case uspct.USPCT:
+		return readCommonUS(gppConstants.SectionUSPCT, s.CoreSegment, s.GPCSegment), true
+	case usput.USPUT:
+		core := s.CoreSegment
+		usnat := USNat{
+			SectionID:                           gppConstants.SectionUSPUT,
+			SharingNotice:                       core.SharingNotice,
+			SaleOptOutNotice:                    core.SaleOptOutNotice,
+			TargetedAdvertisingOptOutNotice:     core.TargetedAdvertisingOptOutNotice,
+			SensitiveDataProcessingOptOutNotice: core.SensitiveDataProcessingOptOutNotice,
+			SaleOptOut:                          core.SaleOptOut,
+			TargetedAdvertisingOptOut:           core.TargetedAdvertisingOptOut,
+			KnownChildSensitiveDataConsents:     []byte{core.KnownChildSensitiveDataConsents},
+			MspaCoveredTransaction:              core.MspaCoveredTransaction,
+			MspaOptOutOptionMode:                core.MspaOptOutOptionMode,
+			MspaServiceProviderMode:             core.MspaServiceProviderMode,
+		}
+		usnat.mapSensitiveDataProcessing(core.SensitiveDataProcessing, sensitiveCategoriesUT)
+		return usnat, true
+	}
+	return USNat{}, false
+}
+
+// readCommonUS maps the states sharing the common US section layout, whose sensitive data fields are the first US
+// National categories in the same order.
+func readCommonUS(sectionID gppConstants.SectionID, core sections.CommonUSCoreSegment, gpc sections.CommonUSGPCSegment) USNat {
+	usnat := USNat{
+		SectionID:                       sectionID,
+		SharingNotice:                   core.SharingNotice,
+		SaleOptOutNotice:                core.SaleOptOutNotice,
+		TargetedAdvertisingOptOutNotice: core.TargetedAdvertisingOptOutNotice,
+		SaleOptOut:                      core.SaleOptOut,
+		TargetedAdvertisingOptOut:       core.TargetedAdvertisingOptOut,
+		KnownChildSensitiveDataConsents: core.KnownChildSensitiveDataConsents,
+		MspaCoveredTransaction:          core.MspaCoveredTransaction,
+		MspaOptOutOptionMode:            core.MspaOptOutOptionMode,
+		MspaServiceProviderMode:         core.MspaServiceProviderMode,
+		Gpc:                             gpc.Gpc,
+	}
+	copy(usnat.SensitiveDataProcessing[:], core.SensitiveDataProcessing)
+	return usnat
+}
+
+func (usnat *USNat) mapSensitiveDataProcessing(fields []byte, categories []int) {
+	for i, value := range fields {
+		if i < len(categories) {
+			usnat.SensitiveDataProcessing[categories[i]] = value
+		}
+	}
+}
+
+// OptedOut returns true if the user opted out of the sale or sharing of their data, or of targeted advertising,
+// either explicitly or through the Global Privacy Control
+func (usnat USNat) OptedOut() bool {
+	return usnat.Gpc ||
+		usnat.SaleOptOut == USNatOptedOut ||
+		usnat.SharingOptOut == USNatOptedOut ||
+		usnat.TargetedAdvertisingOptOut == USNatOptedOut
+}
@@ -0,0 +1,153 @@
+package gpp
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadUSNat(t *testing.T) {
+	testCases := []struct {
+		name          string
+		section       gpplib.Section
+		expectedUSNat USNat
+		expectedOK    bool
+	}{
+		{
+			name: "usnat",
+			section: uspnat.USPNAT{
+				CoreSegment: uspnat.USPNATCoreSegment{
+					SharingNotice:                   1,
+					SaleOptOut:                      1,
+					SharingOptOut:                   2,
+					SensitiveDataProcessing:         []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 2},
+					KnownChildSensitiveDataConsents: []byte{2, 1},
+					PersonalDataConsents:            2,
+					MspaCoveredTransaction:          1,
+					MspaServiceProviderMode:         2,
+				},
+				GPCSegment: sections.CommonUSGPCSegment{Gpc: true},
+			},
+			expectedUSNat: USNat{
+				SectionID:                       gppConstants.SectionUSPNAT,
+				SharingNotice:                   1,
+				SaleOptOut:                      1,
+				SharingOptOut:                   2,
+				SensitiveDataProcessing:         [usNatSensitiveCategories]byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 2},
+				KnownChildSensitiveDataConsents: []byte{2, 1},
+				PersonalDataConsents:            2,
+				MspaCoveredTransaction:          1,
+				MspaServiceProviderMode:         2,
+				Gpc:                             true,
+			},
+			expectedOK: true,
+		},
+		{
+			name: "california",
+			section: uspca.USPCA{
+				CoreSegment: uspca.USPCACoreSegment{
+					SharingOptOutNotice:     1,
+					SharingOptOut:           1,
+					SensitiveDataProcessing: []byte{1, 2, 1, 1, 0, 0, 0, 2, 0},
+				},
+			},
+			expectedUSNat: USNat{
+				SectionID:               gppConstants.SectionUSPCA,
+				SharingOptOutNotice:     1,
+				SharingOptOut:           1,
+				SensitiveDataProcessing: [usNatSensitiveCategories]byte{1, 1, 2, 0, 0, 0, 0, 1, 1, 2, 1, 0},
+			},
+			expectedOK: true,
+		},
+		{
+			name: "virginia",
+			section: uspva.USPVA{
+				CoreSegment: sections.CommonUSCoreSegment{
+					TargetedAdvertisingOptOut:       1,
+					SensitiveDataProcessing:         []byte{0, 0, 0, 0, 0, 0, 0, 1},
+					KnownChildSensitiveDataConsents: []byte{1},
+				},
+			},
+			expectedUSNat: USNat{
+				SectionID:                       gppConstants.SectionUSPVA,
+				TargetedAdvertisingOptOut:       1,
+				SensitiveDataProcessing:         [usNatSensitiveCategories]byte{0, 0, 0, 0, 0, 0, 0, 1},
+				KnownChildSensitiveDataConsents: []byte{1},
+			},
+			expectedOK: true,
+		},
+		{
+			name: "colorado",
+			section: uspco.USPCO{
+				CoreSegment: sections.CommonUSCoreSegment{SaleOptOut: 2},
+				GPCSegment:  sections.CommonUSGPCSegment{Gpc: true},
+			},
+			expectedUSNat: USNat{
+				SectionID:  gppConstants.SectionUSPCO,
+				SaleOptOut: 2,
+				Gpc:        true,
+			},
+			expectedOK: true,
+		},
+		{
+			name: "utah",
+			section: usput.USPUT{
+				CoreSegment: usput.USPUTCoreSegment{
+					SensitiveDataProcessingOptOutNotice: 2,
+					SensitiveDataProcessing:             []byte{0, 0, 1, 0, 2, 0, 0, 1},
+					KnownChildSensitiveDataConsents:     1,
+				},
+			},
+			expectedUSNat: USNat{
+				SectionID:                           gppConstants.SectionUSPUT,
+				SensitiveDataProcessingOptOutNotice: 2,
+				SensitiveDataProcessing:             [usNatSensitiveCategories]byte{0, 0, 2, 1, 0, 0, 0, 1},
+				KnownChildSensitiveDataConsents:     []byte{1},
+			},
+			expectedOK: true,
+		},
+		{
+			name:          "not_a_us_section",
+			section:       gpplib.GenericSection{},
+			expectedUSNat: USNat{},
+			expectedOK:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			usnat, ok := ReadUSNat(test.section)
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expectedUSNat, usnat)
+		})
+	}
+}
+
+func TestUSNatOptedOut(t *testing.T) {
+	testCases := []struct {
+		name     string
+		usnat    USNat
+		expected bool
+	}{
+		{name: "empty", usnat: USNat{}, expected: false},
+		{name: "did_not_opt_out", usnat: USNat{SaleOptOut: USNatDidNotOptOut, SharingOptOut: USNatDidNotOptOut}, expected: false},
+		{name: "sale", usnat: USNat{SaleOptOut: USNatOptedOut}, expected: true},
+		{name: "sharing", usnat: USNat{SharingOptOut: USNatOptedOut}, expected: true},
+		{name: "targeted_advertising", usnat: USNat{TargetedAdvertisingOptOut: USNatOptedOut}, expected: true},
+		{name: "gpc", usnat: USNat{Gpc: true}, expected: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.usnat.OptedOut())
+		})
+	}
+}
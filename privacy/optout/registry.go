@@ -0,0 +1,449 @@
+package optout
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+	httpEvents "github.com/prebid/prebid-server/v3/stored_requests/events/http"
+)
+
+// EntryType is the type of the ID of an entry
+type EntryType string
+
+const (
+	// EntryIFA entries are written "ifa:<device IFA>"
+	EntryIFA EntryType = "ifa"
+	// EntryHashedEmail entries are written "hem:<SHA-256 of the email, in hex>". They match the user EIDs of any
+	// source with this ID, as the hashed emails are sent by the identity providers.
+	EntryHashedEmail EntryType = "hem"
+	// EntryEID entries are written "eid:<source>:<ID>"
+	EntryEID EntryType = "eid"
+)
+
+const hashedEmailSize = 64
+
+// ErrPersist is wrapped by the errors of the updates of the entries which couldn't be written to the file
+var ErrPersist = errors.New("failed to persist the opt-out entries")
+
+// ParseEntry returns the normalized entry, so that the entries of the same ID are equal whatever their case. Only the
+// IDs of the EIDs are case sensitive.
+func ParseEntry(entry string) (string, error) {
+	entryType, id, _ := strings.Cut(strings.TrimSpace(entry), ":")
+	switch EntryType(strings.ToLower(entryType)) {
+	case EntryIFA:
+		if id == "" {
+			return "", fmt.Errorf("the opt-out entry %q has no IFA", entry)
+		}
+		return ifaEntry(id), nil
+	case EntryHashedEmail:
+		if _, err := hex.DecodeString(id); err != nil || len(id) != hashedEmailSize {
+			return "", fmt.Errorf("the opt-out entry %q isn't a SHA-256 hashed email in hex", entry)
+		}
+		return hashedEmailEntry(id), nil
+	case EntryEID:
+		source, uid, _ := strings.Cut(id, ":")
+		if source == "" || uid == "" {
+			return "", fmt.Errorf("the opt-out entry %q isn't an EID written eid:<source>:<ID>", entry)
+		}
+		return eidEntry(source, uid), nil
+	}
+	return "", fmt.Errorf("the opt-out entry %q must be written ifa:<IFA>, hem:<hashed email> or eid:<source>:<ID>", entry)
+}
+
+func ifaEntry(ifa string) string {
+	return string(EntryIFA) + ":" + strings.ToLower(ifa)
+}
+
+func hashedEmailEntry(hashedEmail string) string {
+	return string(EntryHashedEmail) + ":" + strings.ToLower(hashedEmail)
+}
+
+func eidEntry(source, id string) string {
+	return string(EntryEID) + ":" + strings.ToLower(source) + ":" + id
+}
+
+// Registry holds the entries of the users who opted out. It's safe for concurrent use, and a nil Registry has no
+// entries.
+type Registry struct {
+	mutex   sync.RWMutex
+	entries map[string]struct{}
+
+	// fileMutex serializes the loads of the file and the writes of the entries added or removed by Add and Remove
+	fileMutex   sync.Mutex
+	file        string
+	fileModTime time.Time
+	// fileEntries are the entries of the last load of the file, removed when they're removed from the file
+	fileEntries map[string]struct{}
+
+	httpEvents *httpEvents.HTTPEvents
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewRegistry loads the entries of cfg.File and of cfg.HTTPEvents, and keeps them up to date until Shutdown. It
+// returns a nil Registry if the opt-out registry is disabled.
+//
+// The HTTP events API of the stored requests is reused for the entries: they're the IDs of the "requests" it returns,
+// whose data is ignored, and deleted when their data is {"deleted": true}. The "imps", "accounts" and "responses" are
+// ignored.
+func NewRegistry(cfg config.OptOut, client *http.Client) (*Registry, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	r := &Registry{
+		entries: make(map[string]struct{}),
+		file:    cfg.File,
+		done:    make(chan struct{}),
+	}
+	if r.file != "" {
+		if err := r.reloadFile(); err != nil {
+			return nil, err
+		}
+		r.wg.Add(1)
+		go r.watchFile(time.Duration(cfg.RefreshIntervalSeconds) * time.Second)
+	}
+	if cfg.HTTPEvents.Endpoint != "" {
+		ctxProducer := func() (context.Context, func()) {
+			return context.WithTimeout(context.Background(), cfg.HTTPEvents.TimeoutDuration())
+		}
+		r.httpEvents = httpEvents.NewHTTPEvents(client, cfg.HTTPEvents.Endpoint, ctxProducer, cfg.HTTPEvents.RefreshRateDuration())
+		r.wg.Add(1)
+		go r.listen(r.httpEvents)
+	}
+	return r, nil
+}
+
+// Shutdown stops the updates of the entries
+func (r *Registry) Shutdown() {
+	if r == nil {
+		return
+	}
+	close(r.done)
+	r.wg.Wait()
+	if r.httpEvents != nil {
+		r.httpEvents.Stop()
+	}
+}
+
+// watchFile reloads the file when it's modified
+func (r *Registry) watchFile(interval time.Duration) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.reloadFile(); err != nil {
+				logger.Errorf("Failed to reload the opt-out file, keeping the previous entries: %v", err)
+			}
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// reloadFile replaces the entries of the previous load of the file with its current ones, if it was modified since
+func (r *Registry) reloadFile() error {
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+	return r.reloadFileLocked()
+}
+
+func (r *Registry) reloadFileLocked() error {
+	info, err := os.Stat(r.file)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(r.fileModTime) {
+		return nil
+	}
+
+	entries, err := loadFile(r.file)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for entry := range r.fileEntries {
+		if _, ok := entries[entry]; !ok {
+			delete(r.entries, entry)
+		}
+	}
+	for entry := range entries {
+		r.entries[entry] = struct{}{}
+	}
+	r.fileEntries = entries
+	r.fileModTime = info.ModTime()
+	return nil
+}
+
+// loadFile loads the entries of the file, one per line. The blank lines and the lines starting with # are ignored.
+func loadFile(file string) (map[string]struct{}, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		entry, err := ParseEntry(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", file, line, err)
+		}
+		entries[entry] = struct{}{}
+	}
+	return entries, scanner.Err()
+}
+
+// writeFile adds the entries to the file, or removes their lines from it, and reloads it. The entries of the other
+// lines, including those added to the file since its last load, are kept.
+func (r *Registry) writeFile(entries []string, add bool) error {
+	r.fileMutex.Lock()
+	defer r.fileMutex.Unlock()
+
+	content, err := os.ReadFile(r.file)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	var updated []string
+	if add {
+		updated = append(updated, lines...)
+		if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+			updated = append(updated, "\n")
+		}
+		written := make(map[string]struct{}, len(lines)+len(entries))
+		for _, line := range lines {
+			if entry, err := ParseEntry(line); err == nil {
+				written[entry] = struct{}{}
+			}
+		}
+		for _, entry := range entries {
+			if _, ok := written[entry]; ok {
+				continue
+			}
+			written[entry] = struct{}{}
+			updated = append(updated, entry+"\n")
+		}
+	} else {
+		removed := make(map[string]struct{}, len(entries))
+		for _, entry := range entries {
+			removed[entry] = struct{}{}
+		}
+		for _, line := range lines {
+			if entry, err := ParseEntry(line); err == nil {
+				if _, ok := removed[entry]; ok {
+					continue
+				}
+			}
+			updated = append(updated, line)
+		}
+	}
+
+	// The file is replaced, so that it's never read partially written
+	tmp, err := os.CreateTemp(filepath.Dir(r.file), filepath.Base(r.file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(strings.Join(updated, "")); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if info, err := os.Stat(r.file); err == nil {
+		if err := os.Chmod(tmp.Name(), info.Mode()); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmp.Name(), r.file); err != nil {
+		return err
+	}
+
+	r.fileModTime = time.Time{}
+	return r.reloadFileLocked()
+}
+
+// listen adds the IDs of the stored requests saved by the producer, and removes the invalidated ones
+func (r *Registry) listen(producer events.EventProducer) {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case save := <-producer.Saves():
+			ids := make([]string, 0, len(save.Requests))
+			for id := range save.Requests {
+				ids = append(ids, id)
+			}
+			r.update(ids, true)
+		case invalidation := <-producer.Invalidations():
+			r.update(invalidation.Requests, false)
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// update adds or removes the valid entries, logging the others
+func (r *Registry) update(entries []string, add bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, entry := range entries {
+		entry, err := ParseEntry(entry)
+		if err != nil {
+			logger.Warnf("Ignoring an invalid stored opt-out entry: %v", err)
+			continue
+		}
+		if add {
+			r.entries[entry] = struct{}{}
+		} else {
+			delete(r.entries, entry)
+		}
+	}
+}
+
+// Add adds the entries, and writes them to the file, if any, so that they're kept across restarts. It returns an error
+// and adds none of them if any is invalid. The errors of the write wrap ErrPersist. Without a file, the entries are
+// only kept in memory.
+func (r *Registry) Add(entries []string) error {
+	parsed, err := parseEntries(entries)
+	if err != nil {
+		return err
+	}
+	if r.file != "" {
+		if err := r.writeFile(parsed, true); err != nil {
+			return fmt.Errorf("%w: %v", ErrPersist, err)
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, entry := range parsed {
+		r.entries[entry] = struct{}{}
+	}
+	return nil
+}
+
+// Remove removes the entries, and their lines from the file, if any. It returns an error and removes none of them if
+// any is invalid. The errors of the write wrap ErrPersist. The entries of the HTTP events are added back when they're
+// saved again.
+func (r *Registry) Remove(entries []string) error {
+	parsed, err := parseEntries(entries)
+	if err != nil {
+		return err
+	}
+	if r.file != "" {
+		if err := r.writeFile(parsed, false); err != nil {
+			return fmt.Errorf("%w: %v", ErrPersist, err)
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, entry := range parsed {
+		delete(r.entries, entry)
+	}
+	return nil
+}
+
+func parseEntries(entries []string) ([]string, error) {
+	parsed := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		entry, err := ParseEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, entry)
+	}
+	return parsed, nil
+}
+
+// Contains returns true if the registry has the entry
+func (r *Registry) Contains(entry string) bool {
+	parsed, err := ParseEntry(entry)
+	if err != nil {
+		return false
+	}
+	return r.contains(parsed)
+}
+
+func (r *Registry) contains(entry string) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	_, ok := r.entries[entry]
+	return ok
+}
+
+// Counts returns the number of entries of each type
+func (r *Registry) Counts() map[EntryType]int {
+	counts := map[EntryType]int{EntryIFA: 0, EntryHashedEmail: 0, EntryEID: 0}
+	if r == nil {
+		return counts
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for entry := range r.entries {
+		entryType, _, _ := strings.Cut(entry, ":")
+		counts[EntryType(entryType)]++
+	}
+	return counts
+}
+
+// Match returns the type of the first entry matching the device IFA or the user EIDs, and false if none of them does
+func (r *Registry) Match(user *openrtb2.User, device *openrtb2.Device) (EntryType, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	if device != nil && device.IFA != "" && r.contains(ifaEntry(device.IFA)) {
+		return EntryIFA, true
+	}
+	if user == nil {
+		return "", false
+	}
+	for _, eid := range user.EIDs {
+		for _, uid := range eid.UIDs {
+			if uid.ID == "" {
+				continue
+			}
+			if r.contains(eidEntry(eid.Source, uid.ID)) {
+				return EntryEID, true
+			}
+			if len(uid.ID) == hashedEmailSize && r.contains(hashedEmailEntry(uid.ID)) {
+				return EntryHashedEmail, true
+			}
+		}
+	}
+	return "", false
+}
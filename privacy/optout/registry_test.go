@@ -0,0 +1,245 @@
+package optout
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const hashedEmail = "B4C9A289323B21A01C3E940F150EB9B8C542587F1ABFD8F0E1CC1FFC5E475514"
+
+func TestParseEntry(t *testing.T) {
+	testCases := []struct {
+		name          string
+		entry         string
+		expectedEntry string
+		expectedErr   bool
+	}{
+		{name: "ifa", entry: " IFA:AB-CD ", expectedEntry: "ifa:ab-cd"},
+		{name: "hashed_email", entry: "hem:" + hashedEmail, expectedEntry: "hem:" + strings.ToLower(hashedEmail)},
+		{name: "eid", entry: "eid:LiveRamp.com:XY:Z", expectedEntry: "eid:liveramp.com:XY:Z"},
+		{name: "empty_ifa", entry: "ifa:", expectedErr: true},
+		{name: "short_hashed_email", entry: "hem:abc", expectedErr: true},
+		{name: "non_hex_hashed_email", entry: "hem:" + strings.Repeat("z", 64), expectedErr: true},
+		{name: "eid_without_id", entry: "eid:liveramp.com", expectedErr: true},
+		{name: "unknown_type", entry: "email:someone@example.com", expectedErr: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			entry, err := ParseEntry(test.entry)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedEntry, entry)
+		})
+	}
+}
+
+func TestNewRegistryDisabled(t *testing.T) {
+	registry, err := NewRegistry(config.OptOut{File: "/missing"}, nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, registry)
+
+	_, ok := registry.Match(&openrtb2.User{}, &openrtb2.Device{IFA: "ab-cd"})
+	assert.False(t, ok, "a nil registry has no entries")
+	assert.Equal(t, map[EntryType]int{EntryIFA: 0, EntryHashedEmail: 0, EntryEID: 0}, registry.Counts())
+	registry.Shutdown()
+}
+
+func TestNewRegistryInvalidFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "optout.txt")
+	require.NoError(t, os.WriteFile(file, []byte("ifa:ab-cd\nemail:someone@example.com\n"), 0644))
+
+	registry, err := NewRegistry(config.OptOut{Enabled: true, File: file, RefreshIntervalSeconds: 60}, nil)
+
+	assert.EqualError(t, err, file+`:2: the opt-out entry "email:someone@example.com" must be written ifa:<IFA>, hem:<hashed email> or eid:<source>:<ID>`)
+	assert.Nil(t, registry)
+}
+
+func TestRegistryReloadFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "optout.txt")
+	require.NoError(t, os.WriteFile(file, []byte("# opted out users\nifa:ab-cd\n\neid:liveramp.com:xyz\n"), 0644))
+
+	registry, err := NewRegistry(config.OptOut{Enabled: true, File: file, RefreshIntervalSeconds: 3600}, nil)
+	require.NoError(t, err)
+	defer registry.Shutdown()
+
+	assert.True(t, registry.Contains("ifa:AB-CD"))
+	assert.True(t, registry.Contains("eid:liveramp.com:xyz"))
+
+	require.NoError(t, os.WriteFile(file, []byte("eid:liveramp.com:xyz\nhem:"+hashedEmail+"\n"), 0644))
+	require.NoError(t, os.Chtimes(file, time.Now(), time.Now().Add(time.Minute)))
+	require.NoError(t, registry.reloadFile())
+
+	assert.False(t, registry.Contains("ifa:ab-cd"), "the entries removed from the file are removed")
+	assert.True(t, registry.Contains("eid:liveramp.com:xyz"))
+	assert.True(t, registry.Contains("hem:"+hashedEmail))
+	assert.Equal(t, map[EntryType]int{EntryIFA: 0, EntryHashedEmail: 1, EntryEID: 1}, registry.Counts())
+}
+
+func TestRegistryAddRemovePersisted(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "optout.txt")
+	require.NoError(t, os.WriteFile(file, []byte("# opted out users\nifa:ab-cd\neid:liveramp.com:xyz"), 0600))
+	cfg := config.OptOut{Enabled: true, File: file, RefreshIntervalSeconds: 3600}
+
+	registry, err := NewRegistry(cfg, nil)
+	require.NoError(t, err)
+	require.NoError(t, registry.Add([]string{"IFA:EF-GH", "ifa:ab-cd", "ifa:ef-gh"}))
+	require.NoError(t, registry.Remove([]string{"eid:liveramp.com:xyz"}))
+	registry.Shutdown()
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "# opted out users\nifa:ab-cd\nifa:ef-gh\n", string(content), "the entries are added once, and the comments are kept")
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "the mode of the file is kept")
+
+	restarted, err := NewRegistry(cfg, nil)
+	require.NoError(t, err)
+	defer restarted.Shutdown()
+	assert.True(t, restarted.Contains("ifa:ef-gh"), "the added entries are kept across restarts")
+	assert.False(t, restarted.Contains("eid:liveramp.com:xyz"), "the removed entries are kept removed across restarts")
+}
+
+func TestRegistryAddRemove(t *testing.T) {
+	registry, err := NewRegistry(config.OptOut{Enabled: true}, nil)
+	require.NoError(t, err)
+	defer registry.Shutdown()
+
+	require.NoError(t, registry.Add([]string{"ifa:ab-cd", "eid:liveramp.com:xyz"}))
+	assert.Error(t, registry.Add([]string{"ifa:ef-gh", "hem:abc"}))
+	assert.False(t, registry.Contains("ifa:ef-gh"), "no entry is added if any is invalid")
+
+	require.NoError(t, registry.Remove([]string{"IFA:AB-CD"}))
+	assert.False(t, registry.Contains("ifa:ab-cd"))
+	assert.True(t, registry.Contains("eid:liveramp.com:xyz"))
+	assert.False(t, registry.Contains("invalid"))
+}
+
+type fakeProducer struct {
+	saves         chan events.Save
+	invalidations chan events.Invalidation
+}
+
+func (p *fakeProducer) Saves() <-chan events.Save {
+	return p.saves
+}
+
+func (p *fakeProducer) Invalidations() <-chan events.Invalidation {
+	return p.invalidations
+}
+
+func TestRegistryListen(t *testing.T) {
+	registry := &Registry{entries: make(map[string]struct{}), done: make(chan struct{})}
+	producer := &fakeProducer{saves: make(chan events.Save), invalidations: make(chan events.Invalidation)}
+	registry.wg.Add(1)
+	go registry.listen(producer)
+
+	// Only the IDs of the stored requests are entries, the imps, accounts and responses are ignored
+	producer.saves <- events.Save{
+		Requests:  map[string]json.RawMessage{"ifa:ab-cd": json.RawMessage(`{}`), "ifa:ef-gh": json.RawMessage(`{}`), "invalid": json.RawMessage(`{}`)},
+		Imps:      map[string]json.RawMessage{"ifa:imp": json.RawMessage(`{}`)},
+		Accounts:  map[string]json.RawMessage{"ifa:account": json.RawMessage(`{}`)},
+		Responses: map[string]json.RawMessage{"ifa:response": json.RawMessage(`{}`)},
+	}
+	producer.invalidations <- events.Invalidation{Requests: []string{"ifa:ef-gh"}, Imps: []string{"ifa:ab-cd"}}
+	registry.Shutdown()
+
+	assert.True(t, registry.Contains("ifa:ab-cd"))
+	assert.False(t, registry.Contains("ifa:ef-gh"))
+	assert.Equal(t, map[EntryType]int{EntryIFA: 1, EntryHashedEmail: 0, EntryEID: 0}, registry.Counts())
+}
+
+func TestRegistryHTTPEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"requests":{"ifa:ab-cd":{}},"imps":{"ifa:ef-gh":{}}}`))
+	}))
+	defer server.Close()
+
+	registry, err := NewRegistry(config.OptOut{Enabled: true, HTTPEvents: config.HTTPEventsConfig{Endpoint: server.URL, RefreshRate: 1, Timeout: 1000}}, server.Client())
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return registry.Contains("ifa:ab-cd") }, time.Second, 10*time.Millisecond)
+	assert.False(t, registry.Contains("ifa:ef-gh"), "the IDs of the stored imps aren't entries")
+
+	shutdown := make(chan struct{})
+	go func() {
+		registry.Shutdown()
+		close(shutdown)
+	}()
+	select {
+	case <-shutdown:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown must stop the HTTP events")
+	}
+}
+
+func TestRegistryMatch(t *testing.T) {
+	registry, err := NewRegistry(config.OptOut{Enabled: true}, nil)
+	require.NoError(t, err)
+	defer registry.Shutdown()
+	require.NoError(t, registry.Add([]string{"ifa:ab-cd", "hem:" + hashedEmail, "eid:liveramp.com:xyz"}))
+
+	testCases := []struct {
+		name         string
+		user         *openrtb2.User
+		device       *openrtb2.Device
+		expectedType EntryType
+		expectedOk   bool
+	}{
+		{
+			name:         "ifa",
+			device:       &openrtb2.Device{IFA: "AB-CD"},
+			expectedType: EntryIFA,
+			expectedOk:   true,
+		},
+		{
+			name:         "hashed_email_of_any_source",
+			user:         &openrtb2.User{EIDs: []openrtb2.EID{{Source: "hem.example.com", UIDs: []openrtb2.UID{{ID: strings.ToLower(hashedEmail)}}}}},
+			expectedType: EntryHashedEmail,
+			expectedOk:   true,
+		},
+		{
+			name:         "eid",
+			user:         &openrtb2.User{EIDs: []openrtb2.EID{{Source: "LiveRamp.com", UIDs: []openrtb2.UID{{ID: "abc"}, {ID: "xyz"}}}}},
+			expectedType: EntryEID,
+			expectedOk:   true,
+		},
+		{
+			name: "eid_of_another_source",
+			user: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "id5-sync.com", UIDs: []openrtb2.UID{{ID: "xyz"}}}}},
+		},
+		{
+			name:   "no_match",
+			user:   &openrtb2.User{ID: "ab-cd"},
+			device: &openrtb2.Device{IFA: "ef-gh"},
+		},
+		{
+			name: "no_user_nor_device",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			entryType, ok := registry.Match(test.user, test.device)
+			assert.Equal(t, test.expectedType, entryType)
+			assert.Equal(t, test.expectedOk, ok)
+		})
+	}
+}
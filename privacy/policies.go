@@ -2,5 +2,8 @@ package privacy
 
 // Policies contains privacy signals and consent for non-OpenRTB activities.
 type Policies struct {
-	GPPSID []int8
+	GPPSID     []int8
+	GPP        string
+	GPC        bool
+	TCFInScope bool
 }
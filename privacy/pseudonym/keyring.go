@@ -0,0 +1,163 @@
+package pseudonym
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+)
+
+const (
+	keyFileSuffix     = ".key"
+	keyFileDateLayout = "2006-01-02"
+	// minKeySize is the size of the keys resisting a brute force of the HMAC
+	minKeySize = 16
+)
+
+type key struct {
+	activeFrom time.Time
+	secret     []byte
+}
+
+// Keyring holds the keys of the pseudonyms, loaded from the key files of a directory. It's safe for concurrent use,
+// and a nil Keyring has no keys.
+type Keyring struct {
+	dir   string
+	now   func() time.Time
+	mutex sync.RWMutex
+	// keys are ordered by activation
+	keys []key
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewKeyring loads the keys of cfg.KeyDir and reloads them every cfg.RefreshIntervalSeconds until Shutdown. It
+// returns a nil Keyring if no key dir is configured.
+func NewKeyring(cfg config.Pseudonymization) (*Keyring, error) {
+	if cfg.KeyDir == "" {
+		return nil, nil
+	}
+
+	k := &Keyring{
+		dir:  cfg.KeyDir,
+		now:  time.Now,
+		done: make(chan struct{}),
+	}
+	keys, err := loadKeys(k.dir)
+	if err != nil {
+		return nil, err
+	}
+	k.keys = keys
+
+	k.wg.Add(1)
+	go k.refresh(time.Duration(cfg.RefreshIntervalSeconds) * time.Second)
+	return k, nil
+}
+
+func (k *Keyring) refresh(interval time.Duration) {
+	defer k.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			keys, err := loadKeys(k.dir)
+			if err != nil {
+				logger.Errorf("Failed to reload the pseudonymization keys, keeping the previous ones: %v", err)
+				continue
+			}
+			k.mutex.Lock()
+			k.keys = keys
+			k.mutex.Unlock()
+		case <-k.done:
+			return
+		}
+	}
+}
+
+// Shutdown stops the reloading of the keys
+func (k *Keyring) Shutdown() {
+	if k == nil {
+		return
+	}
+	close(k.done)
+	k.wg.Wait()
+}
+
+// Pseudonym returns the pseudonym of the ID for the recipient of the account, computed with the active key. It
+// returns false if no key is active.
+func (k *Keyring) Pseudonym(account, recipient, id string) (string, bool) {
+	secret, ok := k.activeKey()
+	if !ok {
+		return "", false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	// the fields are separated by a byte which can't be part of them, so they can't be shifted across each other
+	mac.Write([]byte(account))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strings.ToLower(recipient)))
+	mac.Write([]byte{0})
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil)), true
+}
+
+func (k *Keyring) activeKey() ([]byte, bool) {
+	if k == nil {
+		return nil, false
+	}
+
+	now := k.now()
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		if !k.keys[i].activeFrom.After(now) {
+			return k.keys[i].secret, true
+		}
+	}
+	return nil, false
+}
+
+// loadKeys loads the key files of dir, named after the UTC date they're active from. The other files are ignored.
+func loadKeys(dir string) ([]key, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []key
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, keyFileSuffix) {
+			continue
+		}
+		activeFrom, err := time.Parse(keyFileDateLayout, strings.TrimSuffix(name, keyFileSuffix))
+		if err != nil {
+			return nil, fmt.Errorf("the pseudonymization key %s isn't named after the date it's active from, like 2026-10-01.key", name)
+		}
+		secret, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		secret = bytes.TrimSpace(secret)
+		if len(secret) < minKeySize {
+			return nil, fmt.Errorf("the pseudonymization key %s must have at least %d bytes", name, minKeySize)
+		}
+		keys = append(keys, key{activeFrom: activeFrom, secret: secret})
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].activeFrom.Before(keys[j].activeFrom) })
+	return keys, nil
+}
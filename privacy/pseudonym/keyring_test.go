@@ -0,0 +1,118 @@
+package pseudonym
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyringWithoutKeyDir(t *testing.T) {
+	keyring, err := NewKeyring(config.Pseudonymization{})
+
+	assert.NoError(t, err)
+	assert.Nil(t, keyring)
+
+	_, ok := keyring.Pseudonym("account", "bidderA", "id")
+	assert.False(t, ok, "a nil keyring has no keys")
+	keyring.Shutdown()
+}
+
+func TestNewKeyringErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+	}{
+		{
+			name:  "misnamed_key",
+			files: map[string]string{"current.key": "0123456789abcdef"},
+		},
+		{
+			name:  "short_key",
+			files: map[string]string{"2026-10-01.key": "0123"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeKeys(t, dir, test.files)
+
+			keyring, err := NewKeyring(config.Pseudonymization{KeyDir: dir, RefreshIntervalSeconds: 3600})
+
+			assert.Error(t, err)
+			assert.Nil(t, keyring)
+		})
+	}
+
+	t.Run("missing_dir", func(t *testing.T) {
+		_, err := NewKeyring(config.Pseudonymization{KeyDir: filepath.Join(t.TempDir(), "missing"), RefreshIntervalSeconds: 3600})
+		assert.Error(t, err)
+	})
+}
+
+func TestKeyringPseudonym(t *testing.T) {
+	dir := t.TempDir()
+	writeKeys(t, dir, map[string]string{
+		"2026-09-01.key": "september-secret-key\n",
+		"2026-10-01.key": "october-secret-key",
+		"README.md":      "ignored",
+	})
+	keyring, err := NewKeyring(config.Pseudonymization{KeyDir: dir, RefreshIntervalSeconds: 3600})
+	require.NoError(t, err)
+	defer keyring.Shutdown()
+
+	keyring.now = func() time.Time { return time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC) }
+	september, ok := keyring.Pseudonym("account", "bidderA", "id")
+	require.True(t, ok)
+	assert.Len(t, september, 64)
+	assert.NotEqual(t, "id", september)
+
+	again, _ := keyring.Pseudonym("account", "BidderA", "id")
+	assert.Equal(t, september, again, "the pseudonyms should be stable for a recipient")
+
+	otherRecipient, _ := keyring.Pseudonym("account", "bidderB", "id")
+	assert.NotEqual(t, september, otherRecipient, "the pseudonyms should differ across recipients")
+
+	otherAccount, _ := keyring.Pseudonym("account2", "bidderA", "id")
+	assert.NotEqual(t, september, otherAccount, "the pseudonyms should differ across accounts")
+
+	keyring.now = func() time.Time { return time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC) }
+	october, ok := keyring.Pseudonym("account", "bidderA", "id")
+	require.True(t, ok)
+	assert.NotEqual(t, september, october, "the pseudonyms should change with the key")
+
+	keyring.now = func() time.Time { return time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC) }
+	_, ok = keyring.Pseudonym("account", "bidderA", "id")
+	assert.False(t, ok, "no key should be active before the first one")
+}
+
+func TestKeyringRefresh(t *testing.T) {
+	dir := t.TempDir()
+	writeKeys(t, dir, map[string]string{"2026-09-01.key": "september-secret-key"})
+	keyring, err := NewKeyring(config.Pseudonymization{KeyDir: dir, RefreshIntervalSeconds: 3600})
+	require.NoError(t, err)
+	keyring.Shutdown()
+
+	writeKeys(t, dir, map[string]string{"2026-10-01.key": "october-secret-key"})
+	keyring.done = make(chan struct{})
+	keyring.wg.Add(1)
+	go keyring.refresh(time.Millisecond)
+	defer keyring.Shutdown()
+
+	assert.Eventually(t, func() bool {
+		keyring.mutex.RLock()
+		defer keyring.mutex.RUnlock()
+		return len(keyring.keys) == 2
+	}, time.Second, time.Millisecond)
+}
+
+func writeKeys(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+}
@@ -0,0 +1,93 @@
+package privacy
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy/pseudonym"
+)
+
+// SetPseudonymKeys sets the keys of the pseudonyms replacing the user IDs of the account
+func (e *ActivityControl) SetPseudonymKeys(keys *pseudonym.Keyring, account string) {
+	e.pseudonymKeys = keys
+	e.account = account
+}
+
+// Pseudonymize replaces the user IDs sent to the target with pseudonyms, if the pseudonymization of the account
+// selects it. The IDs are removed if no key is active, so they're never sent as is.
+func (e ActivityControl) Pseudonymize(reqWrapper *openrtb_ext.RequestWrapper, target Component) {
+	if !e.Pseudonymizes(target) {
+		return
+	}
+
+	pseudonymize := func(id string) string {
+		if id == "" {
+			return ""
+		}
+		pseudonym, _ := e.pseudonymKeys.Pseudonym(e.account, target.Name, id)
+		return pseudonym
+	}
+
+	// the user and the device may be shared with the other recipients, so they're copied rather than modified
+	if reqWrapper.User != nil {
+		user := *reqWrapper.User
+		if e.pseudonymization.UserID {
+			user.ID = pseudonymize(user.ID)
+		}
+		user.EIDs = e.pseudonymizeEIDs(user.EIDs, pseudonymize)
+		reqWrapper.User = &user
+	}
+	if reqWrapper.Device != nil && e.pseudonymization.DeviceIFA {
+		device := *reqWrapper.Device
+		device.IFA = pseudonymize(device.IFA)
+		reqWrapper.Device = &device
+	}
+}
+
+// Pseudonymizes returns true if the pseudonymization of the account selects the target
+func (e ActivityControl) Pseudonymizes(target Component) bool {
+	if !e.pseudonymization.Enabled {
+		return false
+	}
+
+	var recipients []string
+	switch {
+	case target.MatchesType(ComponentTypeBidder):
+		recipients = e.pseudonymization.Bidders
+	case target.MatchesType(ComponentTypeAnalytics):
+		recipients = e.pseudonymization.Analytics
+	}
+	return slices.ContainsFunc(recipients, func(recipient string) bool {
+		return recipient == "*" || target.MatchesName(recipient)
+	})
+}
+
+func (e ActivityControl) pseudonymizeEIDs(eids []openrtb2.EID, pseudonymize func(string) string) []openrtb2.EID {
+	sources := e.pseudonymization.EIDSources
+	if len(eids) == 0 || len(sources) == 0 {
+		return eids
+	}
+
+	pseudonymized := make([]openrtb2.EID, 0, len(eids))
+	for _, eid := range eids {
+		if slices.ContainsFunc(sources, func(source string) bool { return source == "*" || strings.EqualFold(source, eid.Source) }) {
+			uids := make([]openrtb2.UID, 0, len(eid.UIDs))
+			for _, uid := range eid.UIDs {
+				if uid.ID = pseudonymize(uid.ID); uid.ID != "" {
+					uids = append(uids, uid)
+				}
+			}
+			if len(uids) == 0 {
+				continue
+			}
+			eid.UIDs = uids
+		}
+		pseudonymized = append(pseudonymized, eid)
+	}
+	if len(pseudonymized) == 0 {
+		return nil
+	}
+	return pseudonymized
+}
@@ -0,0 +1,152 @@
+package privacy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy/pseudonym"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityControlPseudonymizes(t *testing.T) {
+	pseudonymization := config.AccountPseudonymization{
+		Enabled:   true,
+		Bidders:   []string{"bidderA"},
+		Analytics: []string{"*"},
+	}
+
+	testCases := []struct {
+		name             string
+		pseudonymization config.AccountPseudonymization
+		target           Component
+		expected         bool
+	}{
+		{
+			name:             "bidder_selected",
+			pseudonymization: pseudonymization,
+			target:           Component{Type: ComponentTypeBidder, Name: "BidderA"},
+			expected:         true,
+		},
+		{
+			name:             "bidder_not_selected",
+			pseudonymization: pseudonymization,
+			target:           Component{Type: ComponentTypeBidder, Name: "bidderB"},
+			expected:         false,
+		},
+		{
+			name:             "all_analytics",
+			pseudonymization: pseudonymization,
+			target:           Component{Type: ComponentTypeAnalytics, Name: "pubstack"},
+			expected:         true,
+		},
+		{
+			name:             "other_component_type",
+			pseudonymization: pseudonymization,
+			target:           Component{Type: ComponentTypeRealTimeData, Name: "bidderA"},
+			expected:         false,
+		},
+		{
+			name:             "disabled",
+			pseudonymization: config.AccountPseudonymization{Bidders: []string{"*"}},
+			target:           Component{Type: ComponentTypeBidder, Name: "bidderA"},
+			expected:         false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			ac := NewActivityControl(&config.AccountPrivacy{Pseudonymization: test.pseudonymization})
+			assert.Equal(t, test.expected, ac.Pseudonymizes(test.target))
+		})
+	}
+}
+
+func TestActivityControlPseudonymize(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2020-01-01.key"), []byte("0123456789abcdef"), 0600))
+	keys, err := pseudonym.NewKeyring(config.Pseudonymization{KeyDir: dir, RefreshIntervalSeconds: 3600})
+	require.NoError(t, err)
+	defer keys.Shutdown()
+
+	target := Component{Type: ComponentTypeBidder, Name: "bidderA"}
+	pseudonymOf := func(id string) string {
+		pseudonym, ok := keys.Pseudonym("account", "bidderA", id)
+		require.True(t, ok)
+		return pseudonym
+	}
+
+	user := &openrtb2.User{
+		ID:       "user-id",
+		BuyerUID: "buyer-uid",
+		EIDs: []openrtb2.EID{
+			{Source: "liveramp.com", UIDs: []openrtb2.UID{{ID: "liveramp-id"}}},
+			{Source: "id5-sync.com", UIDs: []openrtb2.UID{{ID: "id5-id"}}},
+		},
+	}
+	device := &openrtb2.Device{IFA: "ifa", IP: "1.2.3.4"}
+
+	testCases := []struct {
+		name             string
+		pseudonymization config.AccountPseudonymization
+		keys             *pseudonym.Keyring
+		expectedUser     *openrtb2.User
+		expectedDevice   *openrtb2.Device
+	}{
+		{
+			name:             "all_fields",
+			pseudonymization: config.AccountPseudonymization{Enabled: true, Bidders: []string{"*"}, UserID: true, DeviceIFA: true, EIDSources: []string{"LiveRamp.com"}},
+			keys:             keys,
+			expectedUser: &openrtb2.User{
+				ID:       pseudonymOf("user-id"),
+				BuyerUID: "buyer-uid",
+				EIDs: []openrtb2.EID{
+					{Source: "liveramp.com", UIDs: []openrtb2.UID{{ID: pseudonymOf("liveramp-id")}}},
+					{Source: "id5-sync.com", UIDs: []openrtb2.UID{{ID: "id5-id"}}},
+				},
+			},
+			expectedDevice: &openrtb2.Device{IFA: pseudonymOf("ifa"), IP: "1.2.3.4"},
+		},
+		{
+			name:             "device_ifa_only",
+			pseudonymization: config.AccountPseudonymization{Enabled: true, Bidders: []string{"bidderA"}, DeviceIFA: true},
+			keys:             keys,
+			expectedUser:     user,
+			expectedDevice:   &openrtb2.Device{IFA: pseudonymOf("ifa"), IP: "1.2.3.4"},
+		},
+		{
+			name:             "target_not_selected",
+			pseudonymization: config.AccountPseudonymization{Enabled: true, Bidders: []string{"bidderB"}, UserID: true, DeviceIFA: true},
+			keys:             keys,
+			expectedUser:     user,
+			expectedDevice:   device,
+		},
+		{
+			name:             "no_keys",
+			pseudonymization: config.AccountPseudonymization{Enabled: true, Bidders: []string{"*"}, UserID: true, DeviceIFA: true, EIDSources: []string{"*"}},
+			keys:             nil,
+			expectedUser:     &openrtb2.User{BuyerUID: "buyer-uid"},
+			expectedDevice:   &openrtb2.Device{IP: "1.2.3.4"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			ac := NewActivityControl(&config.AccountPrivacy{Pseudonymization: test.pseudonymization})
+			ac.SetPseudonymKeys(test.keys, "account")
+			reqWrapper := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: user, Device: device}}
+
+			ac.Pseudonymize(reqWrapper, target)
+
+			assert.Equal(t, test.expectedUser, reqWrapper.User)
+			assert.Equal(t, test.expectedDevice, reqWrapper.Device)
+			assert.Equal(t, "user-id", user.ID, "the user must not be modified")
+			assert.Equal(t, "liveramp-id", user.EIDs[0].UIDs[0].ID, "the eids must not be modified")
+			assert.Equal(t, "ifa", device.IFA, "the device must not be modified")
+		})
+	}
+}
@@ -1,13 +1,31 @@
 package privacy
 
+import (
+	"strings"
+
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/privacy/gpp"
+)
+
 // noClausesDefinedResult represents the default return when there is no matching criteria specified.
 const noClausesDefinedResult = true
 
+// channelDOOH is the channel of the requests for digital out-of-home ads, which isn't an account channel type
+const channelDOOH = "dooh"
+
 type ConditionRule struct {
 	result        ActivityResult
 	componentName []string
 	componentType []string
 	gppSID        []int8
+	geo           []string
+	gpc           *bool
+	coppa         *bool
+	tcfInScope    *bool
+	channel       []string
+	// precisionPolicy names the precision policy applied when the rule denies transmitPreciseGeo
+	precisionPolicy string
 }
 
 func (r ConditionRule) Evaluate(target Component, request ActivityRequest) ActivityResult {
@@ -23,6 +41,26 @@ func (r ConditionRule) Evaluate(target Component, request ActivityRequest) Activ
 		return ActivityAbstain
 	}
 
+	if matched := evaluateGeo(r.geo, request); !matched {
+		return ActivityAbstain
+	}
+
+	if matched := evaluateSignal(r.gpc, getGPC(request)); !matched {
+		return ActivityAbstain
+	}
+
+	if matched := evaluateSignal(r.coppa, getCOPPA(request)); !matched {
+		return ActivityAbstain
+	}
+
+	if matched := evaluateSignal(r.tcfInScope, getTCFInScope(request)); !matched {
+		return ActivityAbstain
+	}
+
+	if matched := evaluateChannel(r.channel, request); !matched {
+		return ActivityAbstain
+	}
+
 	return r.result
 }
 
@@ -82,3 +120,123 @@ func getGPPSID(request ActivityRequest) []int8 {
 
 	return nil
 }
+
+// evaluateGeo matches the country, or the country and region, of the device to the "COUNTRY" or "COUNTRY.REGION"
+// clauses
+func evaluateGeo(geo []string, request ActivityRequest) bool {
+	if len(geo) == 0 {
+		return noClausesDefinedResult
+	}
+
+	country, region := getGeo(request)
+	if country == "" {
+		return false
+	}
+
+	for _, g := range geo {
+		clauseCountry, clauseRegion, hasRegion := strings.Cut(g, ".")
+		if !strings.EqualFold(clauseCountry, country) {
+			continue
+		}
+		if !hasRegion || strings.EqualFold(clauseRegion, region) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateSignal matches a signal of the request to the clause requiring it to be set or unset
+func evaluateSignal(clause *bool, signal bool) bool {
+	if clause == nil {
+		return noClausesDefinedResult
+	}
+	return *clause == signal
+}
+
+func evaluateChannel(channels []string, request ActivityRequest) bool {
+	if len(channels) == 0 {
+		return noClausesDefinedResult
+	}
+
+	channel := getChannel(request)
+	for _, c := range channels {
+		if channel != "" && strings.EqualFold(c, channel) {
+			return true
+		}
+	}
+	return false
+}
+
+func getGeo(request ActivityRequest) (country, region string) {
+	if request.IsBidRequest() && request.bidRequest.Device != nil && request.bidRequest.Device.Geo != nil {
+		return request.bidRequest.Device.Geo.Country, request.bidRequest.Device.Geo.Region
+	}
+	return "", ""
+}
+
+func getGPC(request ActivityRequest) bool {
+	if request.gpcHeader {
+		return true
+	}
+
+	if request.IsPolicies() {
+		return request.policies.GPC
+	}
+
+	if request.IsBidRequest() {
+		regExt, err := request.bidRequest.GetRegExt()
+		if err != nil {
+			return false
+		}
+		gpc := regExt.GetGPC()
+		return gpc != nil && *gpc == "1"
+	}
+
+	return false
+}
+
+func getCOPPA(request ActivityRequest) bool {
+	if request.IsBidRequest() && request.bidRequest.Regs != nil {
+		return request.bidRequest.Regs.COPPA == 1
+	}
+	return false
+}
+
+func getTCFInScope(request ActivityRequest) bool {
+	if request.IsPolicies() {
+		return request.policies.TCFInScope
+	}
+
+	if request.IsBidRequest() && request.bidRequest.Regs != nil {
+		regs := request.bidRequest.Regs
+		return (regs.GDPR != nil && *regs.GDPR == 1) || gpp.IsSIDInList(regs.GPPSID, gppConstants.SectionTCFEU2)
+	}
+
+	return false
+}
+
+// getChannel returns the channel of ext.prebid.channel, which most requests don't set. It defaults to the channel of
+// the endpoint, and then to the channel of the object of the request.
+func getChannel(request ActivityRequest) string {
+	if !request.IsBidRequest() {
+		return request.endpointChannel
+	}
+
+	if requestExt, err := request.bidRequest.GetRequestExt(); err == nil {
+		if prebid := requestExt.GetPrebid(); prebid != nil && prebid.Channel != nil && prebid.Channel.Name != "" {
+			return prebid.Channel.Name
+		}
+	}
+
+	switch {
+	case request.endpointChannel != "":
+		return request.endpointChannel
+	case request.bidRequest.App != nil:
+		return string(config.ChannelApp)
+	case request.bidRequest.DOOH != nil:
+		return channelDOOH
+	case request.bidRequest.Site != nil:
+		return string(config.ChannelWeb)
+	}
+	return ""
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -276,3 +277,259 @@ func TestGetGPPSID(t *testing.T) {
 		})
 	}
 }
+
+func newBidRequest(bidRequest *openrtb2.BidRequest) ActivityRequest {
+	return ActivityRequest{bidRequest: &openrtb_ext.RequestWrapper{BidRequest: bidRequest}}
+}
+
+func TestEvaluateGeo(t *testing.T) {
+	californiaRequest := newBidRequest(&openrtb2.BidRequest{Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA", Region: "CA"}}})
+
+	testCases := []struct {
+		name     string
+		geo      []string
+		request  ActivityRequest
+		expected bool
+	}{
+		{
+			name:     "no-clauses",
+			geo:      nil,
+			request:  ActivityRequest{},
+			expected: true,
+		},
+		{
+			name:     "country",
+			geo:      []string{"GBR", "usa"},
+			request:  californiaRequest,
+			expected: true,
+		},
+		{
+			name:     "country-region",
+			geo:      []string{"USA.TX", "USA.ca"},
+			request:  californiaRequest,
+			expected: true,
+		},
+		{
+			name:     "other-region",
+			geo:      []string{"USA.TX"},
+			request:  californiaRequest,
+			expected: false,
+		},
+		{
+			name:     "other-country",
+			geo:      []string{"CAN.CA"},
+			request:  californiaRequest,
+			expected: false,
+		},
+		{
+			name:     "no-device-geo",
+			geo:      []string{"USA"},
+			request:  newBidRequest(&openrtb2.BidRequest{Device: &openrtb2.Device{}}),
+			expected: false,
+		},
+		{
+			name:     "policies",
+			geo:      []string{"USA"},
+			request:  ActivityRequest{policies: &Policies{}},
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, evaluateGeo(test.geo, test.request))
+		})
+	}
+}
+
+func TestEvaluateChannel(t *testing.T) {
+	ampRequest := newBidRequest(&openrtb2.BidRequest{Ext: []byte(`{"prebid":{"channel":{"name":"amp"}}}`)})
+
+	testCases := []struct {
+		name     string
+		channel  []string
+		request  ActivityRequest
+		expected bool
+	}{
+		{
+			name:     "no-clauses",
+			channel:  nil,
+			request:  ActivityRequest{},
+			expected: true,
+		},
+		{
+			name:     "matched",
+			channel:  []string{"web", "AMP"},
+			request:  ampRequest,
+			expected: true,
+		},
+		{
+			name:     "not-matched",
+			channel:  []string{"app"},
+			request:  ampRequest,
+			expected: false,
+		},
+		{
+			name:     "no-channel",
+			channel:  []string{"app"},
+			request:  newBidRequest(&openrtb2.BidRequest{}),
+			expected: false,
+		},
+		{
+			name:     "malformed-ext",
+			channel:  []string{"amp"},
+			request:  newBidRequest(&openrtb2.BidRequest{Ext: []byte(`malformed`)}),
+			expected: false,
+		},
+		{
+			name:     "web-without-channel",
+			channel:  []string{"web"},
+			request:  newBidRequest(&openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://example.com"}}),
+			expected: true,
+		},
+		{
+			name:     "app-without-channel",
+			channel:  []string{"app"},
+			request:  newBidRequest(&openrtb2.BidRequest{App: &openrtb2.App{Bundle: "com.example"}}),
+			expected: true,
+		},
+		{
+			name:     "dooh-without-channel",
+			channel:  []string{"dooh"},
+			request:  newBidRequest(&openrtb2.BidRequest{DOOH: &openrtb2.DOOH{ID: "screen"}}),
+			expected: true,
+		},
+		{
+			name:     "app-not-web",
+			channel:  []string{"web"},
+			request:  newBidRequest(&openrtb2.BidRequest{App: &openrtb2.App{Bundle: "com.example"}}),
+			expected: false,
+		},
+		{
+			name:     "endpoint-channel",
+			channel:  []string{"video"},
+			request:  ActivityRequest{bidRequest: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Site: &openrtb2.Site{}}}, endpointChannel: "video"},
+			expected: true,
+		},
+		{
+			name:     "explicit-channel-over-endpoint",
+			channel:  []string{"amp"},
+			request:  ActivityRequest{bidRequest: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Ext: []byte(`{"prebid":{"channel":{"name":"amp"}}}`)}}, endpointChannel: "video"},
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, evaluateChannel(test.channel, test.request))
+		})
+	}
+}
+
+func TestGetSignals(t *testing.T) {
+	testCases := []struct {
+		name               string
+		request            ActivityRequest
+		expectedGPC        bool
+		expectedCOPPA      bool
+		expectedTCFInScope bool
+	}{
+		{
+			name:    "empty",
+			request: ActivityRequest{},
+		},
+		{
+			name:               "policies",
+			request:            ActivityRequest{policies: &Policies{GPC: true, TCFInScope: true}},
+			expectedGPC:        true,
+			expectedTCFInScope: true,
+		},
+		{
+			name:        "gpc-header",
+			request:     ActivityRequest{bidRequest: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}, gpcHeader: true},
+			expectedGPC: true,
+		},
+		{
+			name:    "request-regs-nil",
+			request: newBidRequest(&openrtb2.BidRequest{}),
+		},
+		{
+			name: "request-regs",
+			request: newBidRequest(&openrtb2.BidRequest{Regs: &openrtb2.Regs{
+				COPPA: 1,
+				GDPR:  ptrutil.ToPtr[int8](1),
+				Ext:   []byte(`{"gpc":"1"}`),
+			}}),
+			expectedGPC:        true,
+			expectedCOPPA:      true,
+			expectedTCFInScope: true,
+		},
+		{
+			name:               "request-regs-gppsid",
+			request:            newBidRequest(&openrtb2.BidRequest{Regs: &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](0), GPPSID: []int8{2}}}),
+			expectedTCFInScope: true,
+		},
+		{
+			name:    "request-regs-signals-unset",
+			request: newBidRequest(&openrtb2.BidRequest{Regs: &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](0), Ext: []byte(`{"gpc":"0"}`)}}),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedGPC, getGPC(test.request), "gpc")
+			assert.Equal(t, test.expectedCOPPA, getCOPPA(test.request), "coppa")
+			assert.Equal(t, test.expectedTCFInScope, getTCFInScope(test.request), "tcfInScope")
+		})
+	}
+}
+
+func TestConditionRuleEvaluateSignals(t *testing.T) {
+	request := newBidRequest(&openrtb2.BidRequest{
+		Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA", Region: "TX"}},
+		Regs:   &openrtb2.Regs{COPPA: 1},
+	})
+
+	testCases := []struct {
+		name     string
+		rule     ConditionRule
+		expected ActivityResult
+	}{
+		{
+			name:     "all-matched",
+			rule:     ConditionRule{result: ActivityDeny, componentName: []string{"bidderA"}, geo: []string{"USA.CA", "USA.TX"}, coppa: ptrutil.ToPtr(true), gpc: ptrutil.ToPtr(false)},
+			expected: ActivityDeny,
+		},
+		{
+			name:     "geo-not-matched",
+			rule:     ConditionRule{result: ActivityDeny, geo: []string{"USA.CA"}, coppa: ptrutil.ToPtr(true)},
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "coppa-not-matched",
+			rule:     ConditionRule{result: ActivityDeny, coppa: ptrutil.ToPtr(false)},
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "gpc-not-matched",
+			rule:     ConditionRule{result: ActivityDeny, gpc: ptrutil.ToPtr(true)},
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "tcf-not-matched",
+			rule:     ConditionRule{result: ActivityDeny, tcfInScope: ptrutil.ToPtr(true)},
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "channel-not-matched",
+			rule:     ConditionRule{result: ActivityDeny, channel: []string{"app"}},
+			expected: ActivityAbstain,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.rule.Evaluate(Component{Type: "bidder", Name: "bidderA"}, request))
+		})
+	}
+}
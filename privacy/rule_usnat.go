@@ -0,0 +1,182 @@
+package privacy
+
+import (
+	"sync"
+
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/privacy/gpp"
+)
+
+// usnatActivities are the activities restricted by the US National and US State sections
+var usnatActivities = []Activity{
+	ActivitySyncUser,
+	ActivityTransmitUserFPD,
+	ActivityTransmitPreciseGeo,
+	ActivityTransmitUniqueRequestIDs,
+}
+
+// USNatRule denies the activities the US National and US State sections of the GPP string don't allow, and abstains
+// otherwise so the rules of the account still apply. Only the sections which apply to the request, as listed by its
+// GPP SIDs, are enforced. The US State sections are mapped onto the US National section, which the account config
+// validation requires. The newer US State sections which can't be decoded yet are ignored.
+type USNatRule struct {
+	activity Activity
+	skipSIDs []int8
+	sections *usnatSections
+}
+
+// usnatSections parses the GPP string once for all the rules of an activity control
+type usnatSections struct {
+	mutex     sync.Mutex
+	gppString string
+	parsed    bool
+	sections  []gpp.USNat
+}
+
+// newUSNatRules returns the rules of the module for each activity it restricts, or nil if the module is disabled
+func newUSNatRules(cfg config.USNatModule) map[Activity]Rule {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	sections := &usnatSections{}
+	rules := make(map[Activity]Rule, len(usnatActivities))
+	for _, activity := range usnatActivities {
+		rules[activity] = USNatRule{
+			activity: activity,
+			skipSIDs: cfg.SkipSIDs,
+			sections: sections,
+		}
+	}
+	return rules
+}
+
+func (r USNatRule) Evaluate(_ Component, request ActivityRequest) ActivityResult {
+	sids := getGPPSID(request)
+	if len(sids) == 0 {
+		return ActivityAbstain
+	}
+
+	for _, usnat := range r.sections.get(getGPP(request)) {
+		if !gpp.IsSIDInList(sids, usnat.SectionID) || gpp.IsSIDInList(r.skipSIDs, usnat.SectionID) {
+			continue
+		}
+		if !usnatAllows(r.activity, usnat) {
+			return ActivityDeny
+		}
+	}
+	return ActivityAbstain
+}
+
+// get returns the US sections of the GPP string, parsing it only if it changed since the last call
+func (s *usnatSections) get(gppString string) []gpp.USNat {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.parsed && s.gppString == gppString {
+		return s.sections
+	}
+
+	s.gppString = gppString
+	s.parsed = true
+	s.sections = nil
+	if gppString == "" {
+		return nil
+	}
+
+	// Sections which fail to parse are left empty, and don't restrict any activity
+	container, _ := gpplib.Parse(gppString)
+	for _, section := range container.Sections {
+		if usnat, ok := gpp.ReadUSNat(section); ok {
+			s.sections = append(s.sections, usnat)
+		}
+	}
+	return s.sections
+}
+
+func getGPP(request ActivityRequest) string {
+	if request.IsPolicies() {
+		return request.policies.GPP
+	}
+
+	if request.IsBidRequest() && request.bidRequest.Regs != nil {
+		return request.bidRequest.Regs.GPP
+	}
+
+	return ""
+}
+
+// usnatAllows returns false if the section forbids the activity
+func usnatAllows(activity Activity, usnat gpp.USNat) bool {
+	switch activity {
+	case ActivityTransmitPreciseGeo:
+		return allowsPreciseGeo(usnat)
+	case ActivityTransmitUserFPD:
+		return allowsSale(usnat) && allowsSensitiveData(usnat)
+	default:
+		return allowsSale(usnat)
+	}
+}
+
+// allowsSale returns false if the user opted out of the sale or sharing of their data, or of targeted advertising,
+// or wasn't given the notice to do so
+func allowsSale(usnat gpp.USNat) bool {
+	if isServiceProvider(usnat) || usnat.Gpc {
+		return false
+	}
+	if usnat.SaleOptOut == gpp.USNatOptedOut ||
+		usnat.SharingOptOut == gpp.USNatOptedOut ||
+		usnat.TargetedAdvertisingOptOut == gpp.USNatOptedOut {
+		return false
+	}
+	if usnat.SaleOptOutNotice == gpp.USNatNotProvided ||
+		usnat.SharingOptOutNotice == gpp.USNatNotProvided ||
+		usnat.TargetedAdvertisingOptOutNotice == gpp.USNatNotProvided {
+		return false
+	}
+	return !isKnownChild(usnat) && usnat.PersonalDataConsents != gpp.USNatNoConsent
+}
+
+// allowsSensitiveData returns false if the processing of any sensitive data category is forbidden, or if the user
+// wasn't given the notice to opt out of it
+func allowsSensitiveData(usnat gpp.USNat) bool {
+	if usnat.SensitiveDataProcessingOptOutNotice == gpp.USNatNotProvided ||
+		usnat.SensitiveDataLimitUseNotice == gpp.USNatNotProvided {
+		return false
+	}
+	for _, value := range usnat.SensitiveDataProcessing {
+		if value == gpp.USNatOptedOut {
+			return false
+		}
+	}
+	return true
+}
+
+// allowsPreciseGeo returns false if the processing of the precise geolocation is forbidden
+func allowsPreciseGeo(usnat gpp.USNat) bool {
+	if isServiceProvider(usnat) || usnat.Gpc || isKnownChild(usnat) {
+		return false
+	}
+	if usnat.SensitiveDataProcessingOptOutNotice == gpp.USNatNotProvided ||
+		usnat.SensitiveDataLimitUseNotice == gpp.USNatNotProvided {
+		return false
+	}
+	return usnat.SensitiveDataProcessing[gpp.USNatSensitivePreciseGeolocation] != gpp.USNatOptedOut
+}
+
+// isServiceProvider returns true if the publisher acts as a service provider of a transaction covered by the MSPA,
+// which forbids selling or sharing the data
+func isServiceProvider(usnat gpp.USNat) bool {
+	return usnat.MspaCoveredTransaction == gpp.USNatYes && usnat.MspaServiceProviderMode == gpp.USNatYes
+}
+
+// isKnownChild returns true if the consent to process the data of a known child was refused
+func isKnownChild(usnat gpp.USNat) bool {
+	for _, value := range usnat.KnownChildSensitiveDataConsents {
+		if value == gpp.USNatNoConsent {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+package privacy
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeGPP(t *testing.T, gppSections ...gpplib.Section) string {
+	gppString, err := gpplib.Encode(gppSections)
+	require.NoError(t, err)
+	return gppString
+}
+
+func newUSNatSection(core uspnat.USPNATCoreSegment) uspnat.USPNAT {
+	core.Version = 1
+	if core.SensitiveDataProcessing == nil {
+		core.SensitiveDataProcessing = make([]byte, 12)
+	}
+	if core.KnownChildSensitiveDataConsents == nil {
+		core.KnownChildSensitiveDataConsents = make([]byte, 2)
+	}
+	return uspnat.USPNAT{SectionID: 7, CoreSegment: core, GPCSegment: sections.CommonUSGPCSegment{SubsectionType: 1}}
+}
+
+func newUSCASection(core uspca.USPCACoreSegment) uspca.USPCA {
+	core.Version = 1
+	if core.SensitiveDataProcessing == nil {
+		core.SensitiveDataProcessing = make([]byte, 9)
+	}
+	if core.KnownChildSensitiveDataConsents == nil {
+		core.KnownChildSensitiveDataConsents = make([]byte, 2)
+	}
+	return uspca.USPCA{SectionID: 8, CoreSegment: core, GPCSegment: sections.CommonUSGPCSegment{SubsectionType: 1}}
+}
+
+func TestUSNatRuleEvaluate(t *testing.T) {
+	geoOptOut := make([]byte, 12)
+	geoOptOut[7] = 1
+	healthOptOut := make([]byte, 12)
+	healthOptOut[2] = 1
+
+	testCases := []struct {
+		name     string
+		cfg      config.USNatModule
+		gpp      string
+		gppSID   []int8
+		expected map[Activity]ActivityResult
+	}{
+		{
+			name:   "no_gpp",
+			cfg:    config.USNatModule{Enabled: true},
+			gpp:    "",
+			gppSID: []int8{7},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityAbstain,
+				ActivityTransmitUserFPD:          ActivityAbstain,
+				ActivityTransmitPreciseGeo:       ActivityAbstain,
+				ActivityTransmitUniqueRequestIDs: ActivityAbstain,
+			},
+		},
+		{
+			name:   "no_opt_out",
+			cfg:    config.USNatModule{Enabled: true},
+			gpp:    encodeGPP(t, newUSNatSection(uspnat.USPNATCoreSegment{SaleOptOut: 2, SaleOptOutNotice: 1})),
+			gppSID: []int8{7},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityAbstain,
+				ActivityTransmitUserFPD:          ActivityAbstain,
+				ActivityTransmitPreciseGeo:       ActivityAbstain,
+				ActivityTransmitUniqueRequestIDs: ActivityAbstain,
+			},
+		},
+		{
+			name:   "sale_opt_out",
+			cfg:    config.USNatModule{Enabled: true},
+			gpp:    encodeGPP(t, newUSNatSection(uspnat.USPNATCoreSegment{SaleOptOut: 1})),
+			gppSID: []int8{7},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityDeny,
+				ActivityTransmitUserFPD:          ActivityDeny,
+				ActivityTransmitPreciseGeo:       ActivityAbstain,
+				ActivityTransmitUniqueRequestIDs: ActivityDeny,
+			},
+		},
+		{
+			name:   "sale_opt_out_section_not_applicable",
+			cfg:    config.USNatModule{Enabled: true},
+			gpp:    encodeGPP(t, newUSNatSection(uspnat.USPNATCoreSegment{SaleOptOut: 1})),
+			gppSID: []int8{2},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityAbstain,
+				ActivityTransmitUserFPD:          ActivityAbstain,
+				ActivityTransmitPreciseGeo:       ActivityAbstain,
+				ActivityTransmitUniqueRequestIDs: ActivityAbstain,
+			},
+		},
+		{
+			name:   "sale_opt_out_section_skipped",
+			cfg:    config.USNatModule{Enabled: true, SkipSIDs: []int8{7}},
+			gpp:    encodeGPP(t, newUSNatSection(uspnat.USPNATCoreSegment{SaleOptOut: 1})),
+			gppSID: []int8{7},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityAbstain,
+				ActivityTransmitUserFPD:          ActivityAbstain,
+				ActivityTransmitPreciseGeo:       ActivityAbstain,
+				ActivityTransmitUniqueRequestIDs: ActivityAbstain,
+			},
+		},
+		{
+			name:   "sensitive_data_opt_out",
+			cfg:    config.USNatModule{Enabled: true},
+			gpp:    encodeGPP(t, newUSNatSection(uspnat.USPNATCoreSegment{SensitiveDataProcessing: healthOptOut})),
+			gppSID: []int8{7},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityAbstain,
+				ActivityTransmitUserFPD:          ActivityDeny,
+				ActivityTransmitPreciseGeo:       ActivityAbstain,
+				ActivityTransmitUniqueRequestIDs: ActivityAbstain,
+			},
+		},
+		{
+			name:   "precise_geo_opt_out",
+			cfg:    config.USNatModule{Enabled: true},
+			gpp:    encodeGPP(t, newUSNatSection(uspnat.USPNATCoreSegment{SensitiveDataProcessing: geoOptOut})),
+			gppSID: []int8{7},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityAbstain,
+				ActivityTransmitUserFPD:          ActivityDeny,
+				ActivityTransmitPreciseGeo:       ActivityDeny,
+				ActivityTransmitUniqueRequestIDs: ActivityAbstain,
+			},
+		},
+		{
+			name:   "known_child",
+			cfg:    config.USNatModule{Enabled: true},
+			gpp:    encodeGPP(t, newUSNatSection(uspnat.USPNATCoreSegment{KnownChildSensitiveDataConsents: []byte{0, 1}})),
+			gppSID: []int8{7},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityDeny,
+				ActivityTransmitUserFPD:          ActivityDeny,
+				ActivityTransmitPreciseGeo:       ActivityDeny,
+				ActivityTransmitUniqueRequestIDs: ActivityDeny,
+			},
+		},
+		{
+			name:   "mspa_service_provider",
+			cfg:    config.USNatModule{Enabled: true},
+			gpp:    encodeGPP(t, newUSNatSection(uspnat.USPNATCoreSegment{MspaCoveredTransaction: 1, MspaServiceProviderMode: 1})),
+			gppSID: []int8{7},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityDeny,
+				ActivityTransmitUserFPD:          ActivityDeny,
+				ActivityTransmitPreciseGeo:       ActivityDeny,
+				ActivityTransmitUniqueRequestIDs: ActivityDeny,
+			},
+		},
+		{
+			name:   "mspa_service_provider_not_covered",
+			cfg:    config.USNatModule{Enabled: true},
+			gpp:    encodeGPP(t, newUSNatSection(uspnat.USPNATCoreSegment{MspaCoveredTransaction: 2, MspaServiceProviderMode: 1})),
+			gppSID: []int8{7},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityAbstain,
+				ActivityTransmitUserFPD:          ActivityAbstain,
+				ActivityTransmitPreciseGeo:       ActivityAbstain,
+				ActivityTransmitUniqueRequestIDs: ActivityAbstain,
+			},
+		},
+		{
+			name:   "state_normalized",
+			cfg:    config.USNatModule{Enabled: true, Normalize: true},
+			gpp:    encodeGPP(t, newUSCASection(uspca.USPCACoreSegment{SensitiveDataProcessing: []byte{0, 0, 1, 0, 0, 0, 0, 0, 0}})),
+			gppSID: []int8{8},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityAbstain,
+				ActivityTransmitUserFPD:          ActivityDeny,
+				ActivityTransmitPreciseGeo:       ActivityDeny,
+				ActivityTransmitUniqueRequestIDs: ActivityAbstain,
+			},
+		},
+		{
+			name:   "state_opted_out",
+			cfg:    config.USNatModule{Enabled: true, Normalize: true},
+			gpp:    encodeGPP(t, newUSCASection(uspca.USPCACoreSegment{SaleOptOut: 1})),
+			gppSID: []int8{8},
+			expected: map[Activity]ActivityResult{
+				ActivitySyncUser:                 ActivityDeny,
+				ActivityTransmitUserFPD:          ActivityDeny,
+				ActivityTransmitPreciseGeo:       ActivityAbstain,
+				ActivityTransmitUniqueRequestIDs: ActivityDeny,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			rules := newUSNatRules(test.cfg)
+			request := NewRequestFromPolicies(Policies{GPPSID: test.gppSID, GPP: test.gpp})
+			for activity, expected := range test.expected {
+				assert.Equal(t, expected, rules[activity].Evaluate(Component{Type: "bidder", Name: "bidderA"}, request), activity.String())
+			}
+		})
+	}
+}
+
+func TestUSNatRuleEvaluateBidRequest(t *testing.T) {
+	gppString := encodeGPP(t, uspnat.USPNAT{
+		SectionID: 7,
+		CoreSegment: uspnat.USPNATCoreSegment{
+			Version:                         1,
+			SensitiveDataProcessing:         make([]byte, 12),
+			KnownChildSensitiveDataConsents: make([]byte, 2),
+		},
+		GPCSegment: sections.CommonUSGPCSegment{SubsectionType: 1, Gpc: true},
+	})
+	rules := newUSNatRules(config.USNatModule{Enabled: true})
+
+	request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Regs: &openrtb2.Regs{GPP: gppString, GPPSID: []int8{7}},
+	}})
+	assert.Equal(t, ActivityDeny, rules[ActivitySyncUser].Evaluate(Component{Type: "bidder", Name: "bidderA"}, request))
+
+	request = NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}})
+	assert.Equal(t, ActivityAbstain, rules[ActivitySyncUser].Evaluate(Component{Type: "bidder", Name: "bidderA"}, request))
+}
+
+func TestNewUSNatRulesDisabled(t *testing.T) {
+	assert.Nil(t, newUSNatRules(config.USNatModule{Enabled: false}))
+}
+
+func TestNewActivityControlUSNat(t *testing.T) {
+	gppString := encodeGPP(t, newUSNatSection(uspnat.USPNATCoreSegment{SaleOptOut: 1}))
+	request := NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: gppString})
+	bidder := Component{Type: "bidder", Name: "bidderA"}
+
+	ac := NewActivityControl(&config.AccountPrivacy{Modules: config.PrivacyModules{USNat: config.USNatModule{Enabled: true}}})
+	assert.False(t, ac.Allow(ActivitySyncUser, bidder, request), "module only")
+	assert.True(t, ac.Allow(ActivityFetchBids, bidder, request), "module only, unrestricted activity")
+
+	ac = NewActivityControl(&config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{SyncUser: getTestActivityConfig(true)},
+		Modules:         config.PrivacyModules{USNat: config.USNatModule{Enabled: true}},
+	})
+	assert.False(t, ac.Allow(ActivitySyncUser, bidder, request), "module denies before the account rules")
+
+	ac = NewActivityControl(&config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{SyncUser: getTestActivityConfig(true)},
+	})
+	assert.True(t, ac.Allow(ActivitySyncUser, bidder, request), "module disabled")
+}
@@ -2,6 +2,7 @@ package privacy
 
 import (
 	"encoding/json"
+	"math"
 	"net"
 
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
@@ -15,6 +16,25 @@ import (
 type IPConf struct {
 	IPV6 config.IPv6
 	IPV4 config.IPv4
+	// Precision overrides the IP masking and the geo rounding
+	Precision config.PrecisionPolicy
+}
+
+// defaultGeoDecimals are the decimals the lat/lon are rounded to without a precision policy
+const defaultGeoDecimals = 2
+
+func (c IPConf) ipv4KeepBits() int {
+	if c.Precision.IPv4KeepBits != nil {
+		return *c.Precision.IPv4KeepBits
+	}
+	return c.IPV4.AnonKeepBits
+}
+
+func (c IPConf) ipv6KeepBits() int {
+	if c.Precision.IPv6KeepBits != nil {
+		return *c.Precision.IPv6KeepBits
+	}
+	return c.IPV6.AnonKeepBits
 }
 
 func scrubDeviceIDs(reqWrapper *openrtb_ext.RequestWrapper) {
@@ -86,15 +106,15 @@ func ScrubTID(reqWrapper *openrtb_ext.RequestWrapper) {
 	reqWrapper.SetImp(impWrapper)
 }
 
-func scrubGEO(reqWrapper *openrtb_ext.RequestWrapper) {
+func scrubGEO(reqWrapper *openrtb_ext.RequestWrapper, precision config.PrecisionPolicy) {
 	//round user's geographic location by rounding off IP address and lat/lng data.
 	//this applies to both device.geo and user.geo
 	if reqWrapper.User != nil && reqWrapper.User.Geo != nil {
-		reqWrapper.User.Geo = scrubGeoPrecision(reqWrapper.User.Geo)
+		reqWrapper.User.Geo = scrubGeoPrecision(reqWrapper.User.Geo, precision)
 	}
 
 	if reqWrapper.Device != nil && reqWrapper.Device.Geo != nil {
-		reqWrapper.Device.Geo = scrubGeoPrecision(reqWrapper.Device.Geo)
+		reqWrapper.Device.Geo = scrubGeoPrecision(reqWrapper.Device.Geo, precision)
 	}
 }
 
@@ -110,8 +130,8 @@ func scrubGeoFull(reqWrapper *openrtb_ext.RequestWrapper) {
 
 func scrubDeviceIP(reqWrapper *openrtb_ext.RequestWrapper, ipConf IPConf) {
 	if reqWrapper.Device != nil {
-		reqWrapper.Device.IP = scrubIP(reqWrapper.Device.IP, ipConf.IPV4.AnonKeepBits, iputil.IPv4BitSize)
-		reqWrapper.Device.IPv6 = scrubIP(reqWrapper.Device.IPv6, ipConf.IPV6.AnonKeepBits, iputil.IPv6BitSize)
+		reqWrapper.Device.IP = scrubIP(reqWrapper.Device.IP, ipConf.ipv4KeepBits(), iputil.IPv4BitSize)
+		reqWrapper.Device.IPv6 = scrubIP(reqWrapper.Device.IPv6, ipConf.ipv6KeepBits(), iputil.IPv6BitSize)
 	}
 }
 
@@ -124,7 +144,7 @@ func ScrubDeviceIDsIPsUserDemoExt(reqWrapper *openrtb_ext.RequestWrapper, ipConf
 	if scrubFullGeo {
 		scrubGeoFull(reqWrapper)
 	} else {
-		scrubGEO(reqWrapper)
+		scrubGEO(reqWrapper, ipConf.Precision)
 	}
 }
 
@@ -143,7 +163,7 @@ func ScrubGdprID(reqWrapper *openrtb_ext.RequestWrapper) {
 
 func ScrubGeoAndDeviceIP(reqWrapper *openrtb_ext.RequestWrapper, ipConf IPConf) {
 	scrubDeviceIP(reqWrapper, ipConf)
-	scrubGEO(reqWrapper)
+	scrubGEO(reqWrapper, ipConf.Precision)
 }
 
 func scrubIP(ip string, ones, bits int) string {
@@ -155,25 +175,38 @@ func scrubIP(ip string, ones, bits int) string {
 	return ipMasked.String()
 }
 
-func scrubGeoPrecision(geo *openrtb2.Geo) *openrtb2.Geo {
+func scrubGeoPrecision(geo *openrtb2.Geo, precision config.PrecisionPolicy) *openrtb2.Geo {
 	if geo == nil {
 		return nil
 	}
 
 	geoCopy := *geo
 
+	decimals := defaultGeoDecimals
+	if precision.GeoDecimals != nil {
+		decimals = *precision.GeoDecimals
+	}
+	scale := math.Pow10(decimals)
+
 	if geoCopy.Lat != nil {
 		lat := *geo.Lat
-		lat = float64(int(lat*100.0+0.5)) / 100.0
+		lat = float64(int(lat*scale+0.5)) / scale
 		geoCopy.Lat = &lat
 	}
 
 	if geoCopy.Lon != nil {
 		lon := *geo.Lon
-		lon = float64(int(lon*100.0+0.5)) / 100.0
+		lon = float64(int(lon*scale+0.5)) / scale
 		geoCopy.Lon = &lon
 	}
 
+	if precision.DropZip {
+		geoCopy.ZIP = ""
+	}
+	if precision.DropMetro {
+		geoCopy.Metro = ""
+	}
+
 	return &geoCopy
 }
 
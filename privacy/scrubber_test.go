@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
@@ -280,7 +281,7 @@ func TestScrubGEO(t *testing.T) {
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
 			brw := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: test.userIn, Device: test.deviceIn}}
-			scrubGEO(brw)
+			scrubGEO(brw, config.PrecisionPolicy{})
 			brw.RebuildRequest()
 			assert.Equal(t, test.expectedUser, brw.User)
 			assert.Equal(t, test.expectedDevice, brw.Device)
@@ -431,16 +432,63 @@ func TestScrubGeoPrecision(t *testing.T) {
 		ZIP:   "some zip",
 	}
 
-	result := scrubGeoPrecision(geo)
+	result := scrubGeoPrecision(geo, config.PrecisionPolicy{})
 
 	assert.Equal(t, geoExpected, result)
 }
 
 func TestScrubGeoPrecisionWhenNil(t *testing.T) {
-	result := scrubGeoPrecision(nil)
+	result := scrubGeoPrecision(nil, config.PrecisionPolicy{})
 	assert.Nil(t, result)
 }
 
+func TestScrubGeoPrecisionPolicy(t *testing.T) {
+	geo := &openrtb2.Geo{
+		Lat:   ptrutil.ToPtr(123.456),
+		Lon:   ptrutil.ToPtr(678.89),
+		Metro: "some metro",
+		City:  "some city",
+		ZIP:   "some zip",
+	}
+	geoExpected := &openrtb2.Geo{
+		Lat:  ptrutil.ToPtr(123.5),
+		Lon:  ptrutil.ToPtr(678.9),
+		City: "some city",
+	}
+
+	result := scrubGeoPrecision(geo, config.PrecisionPolicy{GeoDecimals: ptrutil.ToPtr(1), DropZip: true, DropMetro: true})
+
+	assert.Equal(t, geoExpected, result)
+	assert.Equal(t, "some zip", geo.ZIP, "the geo must not be modified")
+}
+
+func TestScrubDeviceIPPrecisionPolicy(t *testing.T) {
+	testCases := []struct {
+		name           string
+		precision      config.PrecisionPolicy
+		expectedDevice *openrtb2.Device
+	}{
+		{
+			name:           "no_policy",
+			precision:      config.PrecisionPolicy{},
+			expectedDevice: &openrtb2.Device{IP: "43.77.114.0", IPv6: "2001:1db8:abcd:1200::"},
+		},
+		{
+			name:           "policy",
+			precision:      config.PrecisionPolicy{IPv4KeepBits: ptrutil.ToPtr(16), IPv6KeepBits: ptrutil.ToPtr(32)},
+			expectedDevice: &openrtb2.Device{IP: "43.77.0.0", IPv6: "2001:1db8::"},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			brw := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "43.77.114.227", IPv6: "2001:1db8:abcd:1234:3c3e:b3d3:8e39:9a01"}}}
+			ipConf := IPConf{IPV4: config.IPv4{AnonKeepBits: 24}, IPV6: config.IPv6{AnonKeepBits: 56}, Precision: test.precision}
+			scrubDeviceIP(brw, ipConf)
+			assert.Equal(t, test.expectedDevice, brw.Device)
+		})
+	}
+}
+
 func TestScrubUserExtIDs(t *testing.T) {
 	testCases := []struct {
 		description string
@@ -0,0 +1,141 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+)
+
+const (
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// Check returns an error if the dependency it checks isn't ready to serve requests
+type Check func(ctx context.Context) error
+
+// Checks runs the readiness checks registered by the dependencies of Prebid Server
+type Checks struct {
+	mutex    sync.Mutex
+	names    []string
+	checks   map[string]Check
+	critical []string
+	timeout  time.Duration
+}
+
+// Report is the result of all the checks
+type Report struct {
+	// Ready is true if all the critical checks passed
+	Ready  bool              `json:"ready"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Result is the result of a single check
+type Result struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+}
+
+// NewChecks returns an empty set of checks, or nil if readiness is disabled. Checks can be added to a nil set, and are
+// then ignored.
+func NewChecks(cfg config.Readiness) *Checks {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Checks{
+		checks:   make(map[string]Check),
+		critical: cfg.Critical,
+		timeout:  time.Duration(cfg.TimeoutMS) * time.Millisecond,
+	}
+}
+
+// Add registers the check under the given name, replacing any check of the same name. Names use dots to group checks,
+// like "stored_requests.http_events".
+func (c *Checks) Add(name string, check Check) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Run runs all the checks concurrently. The checks which don't complete before the timeout fail.
+func (c *Checks) Run(ctx context.Context) Report {
+	c.mutex.Lock()
+	names := append([]string(nil), c.names...)
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = c.checks[name]
+	}
+	c.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			errs[i] = runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{Ready: true, Checks: make(map[string]Result, len(names))}
+	for i, name := range names {
+		result := Result{Status: StatusReady, Critical: c.isCritical(name)}
+		if errs[i] != nil {
+			result.Status = StatusNotReady
+			result.Error = errs[i].Error()
+			if result.Critical {
+				report.Ready = false
+			}
+		}
+		report.Checks[name] = result
+	}
+	return report
+}
+
+// runCheck returns the error of the check, or the error of the context if it's done before the check completes
+func runCheck(ctx context.Context, check Check) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- errors.New("the check panicked")
+			}
+		}()
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.New("the check timed out")
+	}
+}
+
+// isCritical returns true if the check is listed as critical, either by name or by one of its groups
+func (c *Checks) isCritical(name string) bool {
+	for _, critical := range c.critical {
+		if name == critical || strings.HasPrefix(name, critical+".") {
+			return true
+		}
+	}
+	return false
+}
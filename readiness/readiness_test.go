@@ -0,0 +1,118 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func ready(context.Context) error {
+	return nil
+}
+
+func notReady(context.Context) error {
+	return errors.New("not loaded")
+}
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checks         map[string]Check
+		expectedReport Report
+	}{
+		{
+			name:           "no-checks",
+			checks:         map[string]Check{},
+			expectedReport: Report{Ready: true, Checks: map[string]Result{}},
+		},
+		{
+			name: "all-ready",
+			checks: map[string]Check{
+				"currency_rates":              ready,
+				"stored_requests.http_events": ready,
+				"prebid_cache":                ready,
+			},
+			expectedReport: Report{Ready: true, Checks: map[string]Result{
+				"currency_rates":              {Status: StatusReady, Critical: true},
+				"stored_requests.http_events": {Status: StatusReady, Critical: true},
+				"prebid_cache":                {Status: StatusReady},
+			}},
+		},
+		{
+			name: "non-critical-not-ready",
+			checks: map[string]Check{
+				"currency_rates": ready,
+				"prebid_cache":   notReady,
+			},
+			expectedReport: Report{Ready: true, Checks: map[string]Result{
+				"currency_rates": {Status: StatusReady, Critical: true},
+				"prebid_cache":   {Status: StatusNotReady, Error: "not loaded"},
+			}},
+		},
+		{
+			name: "critical-group-not-ready",
+			checks: map[string]Check{
+				"currency_rates":              ready,
+				"stored_requests.http_events": notReady,
+				"stored_requests_amp":         ready,
+			},
+			expectedReport: Report{Ready: false, Checks: map[string]Result{
+				"currency_rates":              {Status: StatusReady, Critical: true},
+				"stored_requests.http_events": {Status: StatusNotReady, Critical: true, Error: "not loaded"},
+				"stored_requests_amp":         {Status: StatusReady},
+			}},
+		},
+		{
+			name: "timed-out",
+			checks: map[string]Check{
+				"currency_rates": func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				},
+			},
+			expectedReport: Report{Ready: false, Checks: map[string]Result{
+				"currency_rates": {Status: StatusNotReady, Critical: true, Error: "the check timed out"},
+			}},
+		},
+		{
+			name: "panicked",
+			checks: map[string]Check{
+				"currency_rates": func(context.Context) error {
+					panic("oops")
+				},
+			},
+			expectedReport: Report{Ready: false, Checks: map[string]Result{
+				"currency_rates": {Status: StatusNotReady, Critical: true, Error: "the check panicked"},
+			}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			checks := NewChecks(config.Readiness{Enabled: true, TimeoutMS: 50, Critical: []string{"currency_rates", "stored_requests"}})
+			for name, check := range test.checks {
+				checks.Add(name, check)
+			}
+
+			assert.Equal(t, test.expectedReport, checks.Run(context.Background()))
+		})
+	}
+}
+
+func TestAddReplaces(t *testing.T) {
+	checks := NewChecks(config.Readiness{Enabled: true, TimeoutMS: 50})
+	checks.Add("prebid_cache", notReady)
+	checks.Add("prebid_cache", ready)
+
+	assert.Equal(t, Report{Ready: true, Checks: map[string]Result{"prebid_cache": {Status: StatusReady}}}, checks.Run(context.Background()))
+}
+
+func TestDisabled(t *testing.T) {
+	checks := NewChecks(config.Readiness{Enabled: false})
+	assert.Nil(t, checks)
+
+	assert.NotPanics(t, func() { checks.Add("prebid_cache", ready) })
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/prebid/prebid-server/v3/version"
 )
 
-func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration) *http.ServeMux {
+func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration, r *Router) *http.ServeMux {
 	// Add endpoints to the admin server
 	// Making sure to add pprof routes
 	mux := http.NewServeMux()
@@ -23,5 +23,8 @@ func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval
 	// Register prebid-server defined admin handlers
 	mux.HandleFunc("/currency/rates", endpoints.NewCurrencyRatesEndpoint(rateConverter, rateConverterFetchingInterval))
 	mux.HandleFunc("/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
+	for path, handler := range r.adminHandlers {
+		mux.HandleFunc(path, handler)
+	}
 	return mux
 }
@@ -0,0 +1,101 @@
+package aspects
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/accesslog"
+	"github.com/prebid/prebid-server/v3/logger"
+)
+
+// AccessLog writes an entry to the access log per request sampled, with the details of the HTTP request and response.
+// The endpoint adds those of the transaction to the entry, which it gets from the context of the request. The handler
+// is returned as it is if the access log is disabled.
+func AccessLog(f httprouter.Handle, endpoint string, accessLog *accesslog.Logger) httprouter.Handle {
+	if accessLog == nil {
+		return f
+	}
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if !accessLog.Sampled() {
+			f(w, r, params)
+			return
+		}
+
+		entry := accessLog.NewEntry(r, endpoint)
+		entry.RequestID = requestID(r.Context())
+
+		body := &countingReader{reader: r.Body}
+		if r.Body != nil {
+			r.Body = body
+		}
+		writer := &countingResponseWriter{ResponseWriter: w}
+
+		f(writer, r.WithContext(accesslog.NewContext(r.Context(), entry)), params)
+
+		entry.Status = writer.status
+		if entry.Status == 0 {
+			entry.Status = http.StatusOK
+		}
+		entry.RequestBytes = body.count
+		entry.ResponseBytes = writer.count
+		accessLog.Log(entry)
+	}
+}
+
+// requestID returns the ID of the request from the fields of its logs, set by LogFields
+func requestID(ctx context.Context) string {
+	fields := logger.Fields(ctx)
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i] == logger.FieldRequestID {
+			id, _ := fields[i+1].(string)
+			return id
+		}
+	}
+	return ""
+}
+
+// countingReader counts the bytes read from the body of the request
+type countingReader struct {
+	reader io.ReadCloser
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+func (r *countingReader) Close() error {
+	return r.reader.Close()
+}
+
+// countingResponseWriter records the status and counts the bytes of the response
+type countingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	count  int64
+}
+
+func (w *countingResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *countingResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.count += int64(n)
+	return n, err
+}
+
+// Unwrap gives http.ResponseController access to the underlying writer
+func (w *countingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,81 @@
+package aspects
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/accesslog"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/iputil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLogDisabled(t *testing.T) {
+	called := false
+	handle := AccessLog(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		called = true
+		assert.Nil(t, accesslog.FromContext(r.Context()))
+	}, "/event", nil)
+
+	handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/event", nil), nil)
+	assert.True(t, called)
+}
+
+func TestAccessLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	accessLog, err := accesslog.NewLogger(config.AccessLog{
+		Enabled:       true,
+		Filename:      path,
+		MaxSize:       "1MB",
+		SampleRate:    1,
+		Anonymize:     config.AccessLogAnonymizeNever,
+		QueueSize:     10,
+		FlushInterval: "1s",
+	}, config.AccountPrivacy{}, iputil.VersionIPValidator{}, clock.NewMock())
+	require.NoError(t, err)
+
+	endpoint := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		io.ReadAll(r.Body)
+		accesslog.FromContext(r.Context()).SetAccount(&config.Account{ID: "account"})
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("invalid"))
+	}
+	handle := LogFields(AccessLog(endpoint, "/openrtb2/auction", accessLog), "/openrtb2/auction", fakeUUIDGenerator{})
+
+	request := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", strings.NewReader(`{"id":"1"}`))
+	request.Header.Set(RequestIDHeader, "infra-id")
+	handle(httptest.NewRecorder(), request, nil)
+	accessLog.Shutdown()
+
+	// the file is rotated on shutdown
+	rotated, err := filepath.Glob(filepath.Join(filepath.Dir(path), "access-*.log"))
+	require.NoError(t, err)
+	require.Len(t, rotated, 1)
+	content, err := os.ReadFile(rotated[0])
+	require.NoError(t, err)
+	var entry accesslog.Entry
+	require.NoError(t, jsonutil.Unmarshal(content, &entry))
+	assert.Equal(t, "infra-id", entry.RequestID)
+	assert.Equal(t, "/openrtb2/auction", entry.Endpoint)
+	assert.Equal(t, "account", entry.Account)
+	assert.Equal(t, http.StatusBadRequest, entry.Status)
+	assert.Equal(t, int64(10), entry.RequestBytes)
+	assert.Equal(t, int64(7), entry.ResponseBytes)
+}
+
+func TestCountingResponseWriterDefaultStatus(t *testing.T) {
+	writer := &countingResponseWriter{ResponseWriter: httptest.NewRecorder()}
+	writer.Write([]byte("ok"))
+	writer.WriteHeader(http.StatusInternalServerError)
+	assert.Equal(t, http.StatusOK, writer.status)
+	assert.Equal(t, int64(2), writer.count)
+}
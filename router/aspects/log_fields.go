@@ -0,0 +1,28 @@
+package aspects
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/util/uuidutil"
+)
+
+// RequestIDHeader carries the ID of a request, set by the infrastructure in front of Prebid Server to correlate its
+// logs with ours. An ID is generated for the requests without one.
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength bounds the size of the request IDs taken from the header, since they're logged
+const maxRequestIDLength = 128
+
+// LogFields adds the ID of the request and the endpoint to the context of the request, so they're added to its logs
+func LogFields(f httprouter.Handle, endpoint string, generator uuidutil.UUIDGenerator) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID, _ = generator.Generate()
+		}
+		ctx := logger.WithFields(r.Context(), logger.FieldRequestID, requestID, logger.FieldEndpoint, endpoint)
+		f(w, r.WithContext(ctx), params)
+	}
+}
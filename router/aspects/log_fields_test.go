@@ -0,0 +1,58 @@
+package aspects
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeUUIDGenerator struct{}
+
+func (fakeUUIDGenerator) Generate() (string, error) {
+	return "generated-id", nil
+}
+
+func TestLogFields(t *testing.T) {
+	testCases := []struct {
+		name            string
+		requestIDHeader string
+		expectedID      string
+	}{
+		{
+			name:       "no-header",
+			expectedID: "generated-id",
+		},
+		{
+			name:            "header",
+			requestIDHeader: "infra-id",
+			expectedID:      "infra-id",
+		},
+		{
+			name:            "header-too-long",
+			requestIDHeader: strings.Repeat("a", maxRequestIDLength+1),
+			expectedID:      "generated-id",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var fields []any
+			handle := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				fields = logger.Fields(r.Context())
+			}
+
+			req := httptest.NewRequest("POST", "/openrtb2/auction", nil)
+			if test.requestIDHeader != "" {
+				req.Header.Set(RequestIDHeader, test.requestIDHeader)
+			}
+			LogFields(handle, "/openrtb2/auction", fakeUUIDGenerator{})(httptest.NewRecorder(), req, nil)
+
+			assert.Equal(t, []any{logger.FieldRequestID, test.expectedID, logger.FieldEndpoint, "/openrtb2/auction"}, fields)
+		})
+	}
+}
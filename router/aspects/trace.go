@@ -0,0 +1,36 @@
+package aspects
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/tracing"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Trace starts the server span of the request, continuing the trace of its W3C traceparent header if any. The span
+// is carried by the context of the request, so the spans of the endpoint are its children.
+func Trace(f httprouter.Handle, endpoint string) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		ctx := tracing.Extract(r.Context(), r.Header)
+		ctx, span := tracing.StartServer(ctx, endpoint, tracing.AttributeEndpoint.String(endpoint))
+		defer span.End()
+		if !span.IsRecording() {
+			f(w, r.WithContext(ctx), params)
+			return
+		}
+
+		span.SetAttributes(tracing.AttributeRequestID.String(requestID(r.Context())))
+		writer := &countingResponseWriter{ResponseWriter: w}
+		f(writer, r.WithContext(ctx), params)
+
+		status := writer.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(tracing.AttributeStatus.Int(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package aspects
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v3/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTrace(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	tests := []struct {
+		description    string
+		status         int
+		expectedStatus codes.Code
+	}{
+		{
+			description:    "Success",
+			status:         http.StatusOK,
+			expectedStatus: codes.Unset,
+		},
+		{
+			description:    "Bad request",
+			status:         http.StatusBadRequest,
+			expectedStatus: codes.Unset,
+		},
+		{
+			description:    "Server error",
+			status:         http.StatusServiceUnavailable,
+			expectedStatus: codes.Error,
+		},
+	}
+
+	for _, test := range tests {
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		var endpointSpan trace.SpanContext
+		endpoint := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			endpointSpan = trace.SpanContextFromContext(r.Context())
+			w.WriteHeader(test.status)
+		}
+		handle := LogFields(Trace(endpoint, "/openrtb2/auction"), "/openrtb2/auction", fakeUUIDGenerator{})
+
+		request := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+		request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		handle(httptest.NewRecorder(), request, nil)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1, test.description)
+		span := spans[0]
+		assert.Equal(t, "/openrtb2/auction", span.Name(), test.description)
+		assert.Equal(t, trace.SpanKindServer, span.SpanKind(), test.description)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String(), test.description)
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String(), test.description)
+		assert.Equal(t, span.SpanContext(), endpointSpan, test.description)
+		assert.Contains(t, span.Attributes(), tracing.AttributeRequestID.String("generated-id"), test.description)
+		assert.Contains(t, span.Attributes(), tracing.AttributeStatus.Int(test.status), test.description)
+		assert.Equal(t, test.expectedStatus, span.Status().Code, test.description)
+	}
+}
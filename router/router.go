@@ -12,6 +12,7 @@ import (
 	"time"
 
 	openrtb2model "github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/accesslog"
 	analyticsBuild "github.com/prebid/prebid-server/v3/analytics/build"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
@@ -35,14 +36,23 @@ import (
 	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/pbs"
 	pbc "github.com/prebid/prebid-server/v3/prebid_cache_client"
+	"github.com/prebid/prebid-server/v3/privacy/optout"
+	"github.com/prebid/prebid-server/v3/privacy/pseudonym"
+	"github.com/prebid/prebid-server/v3/readiness"
 	"github.com/prebid/prebid-server/v3/router/aspects"
 	"github.com/prebid/prebid-server/v3/server/ssl"
+	"github.com/prebid/prebid-server/v3/stored_requests"
 	storedRequestsConf "github.com/prebid/prebid-server/v3/stored_requests/config"
+	"github.com/prebid/prebid-server/v3/stored_requests/validation"
+	"github.com/prebid/prebid-server/v3/tracing"
 	"github.com/prebid/prebid-server/v3/usersync"
+	"github.com/prebid/prebid-server/v3/usersync/idstore"
+	"github.com/prebid/prebid-server/v3/util/iputil"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/uuidutil"
 	"github.com/prebid/prebid-server/v3/version"
 
+	"github.com/benbjohnson/clock"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/julienschmidt/httprouter"
 	_ "github.com/lib/pq"
@@ -113,12 +123,17 @@ func (m NoCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	m.Handler.ServeHTTP(w, r)
 }
 
+// storedDataQuarantineSize is the maximum number of invalid stored data kept for inspection
+const storedDataQuarantineSize = 1000
+
 type Router struct {
 	*httprouter.Router
 	MetricsEngine   *metricsConf.DetailedMetricsEngine
 	ParamsValidator openrtb_ext.BidderParamValidator
 
-	shutdowns []func()
+	// adminHandlers are the admin endpoints which depend on the dependencies built for the public endpoints
+	adminHandlers map[string]http.HandlerFunc
+	shutdowns     []func()
 }
 
 func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *Router, err error) {
@@ -207,8 +222,14 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		syncerKeys = append(syncerKeys, k)
 	}
 
+	// Readiness checks are added by the dependencies as they're built, and are nil if the endpoint is disabled
+	readinessChecks := readiness.NewChecks(cfg.Readiness)
+	if rateConvertor != nil && cfg.CurrencyConverter.FetchIntervalSeconds > 0 {
+		readinessChecks.Add("currency_rates", rateConvertor.Ready)
+	}
+
 	normalizedGeoscopes := getNormalizedGeoscopes(cfg.BidderInfos)
-	moduleDeps := moduledeps.ModuleDeps{HTTPClient: generalHttpClient, RateConvertor: rateConvertor, Geoscope: normalizedGeoscopes}
+	moduleDeps := moduledeps.ModuleDeps{HTTPClient: generalHttpClient, RateConvertor: rateConvertor, Geoscope: normalizedGeoscopes, Readiness: readinessChecks}
 	repo, moduleStageNames, shutdownModules, err := modules.NewBuilder().Build(cfg.Hooks.Modules, moduleDeps)
 	if err != nil {
 		logger.Fatalf("Failed to init hook modules: %v", err)
@@ -216,12 +237,6 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 
 	// Metrics engine
 	r.MetricsEngine = metricsConf.NewMetricsEngine(cfg, openrtb_ext.CoreBidderNames(), syncerKeys, moduleStageNames)
-	shutdown, fetcher, ampFetcher, accounts, categoriesFetcher, videoFetcher, storedRespFetcher := storedRequestsConf.NewStoredRequests(cfg, r.MetricsEngine, generalHttpClient, r.Router)
-
-	analyticsRunner := analyticsBuild.New(&cfg.Analytics)
-
-	// register the analytics runner for shutdown
-	r.shutdowns = append(r.shutdowns, shutdown, analyticsRunner.Shutdown, shutdownModules.Shutdown)
 
 	paramsValidator, err := openrtb_ext.NewBidderParamsValidator(schemaDirectory)
 	if err != nil {
@@ -230,15 +245,28 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 
 	activeBidders := exchange.GetActiveBidders(cfg.BidderInfos)
 	disabledBidders := exchange.GetDisabledBidderWarningMessages(cfg.BidderInfos)
+	requestValidator := ortb.NewRequestValidator(activeBidders, disabledBidders, paramsValidator)
+
+	storedDataQuarantine := validation.NewQuarantine(storedDataQuarantineSize)
+	shutdown, fetcher, ampFetcher, accounts, categoriesFetcher, videoFetcher, storedRespFetcher := storedRequestsConf.NewStoredRequests(cfg, r.MetricsEngine, generalHttpClient, r.Router, requestValidator, paramsValidator, storedDataQuarantine, readinessChecks)
+
+	analyticsRunner := analyticsBuild.New(&cfg.Analytics)
+
+	// register the analytics runner for shutdown
+	r.shutdowns = append(r.shutdowns, shutdown, analyticsRunner.Shutdown, shutdownModules.Shutdown)
 
 	defReqJSON := readDefaultRequest(cfg.DefReqConfig)
 
 	gvlVendorIDs := cfg.BidderInfos.ToGVLVendorIDMap()
-	vendorListFetcher := gdpr.NewVendorListFetcher(context.Background(), cfg.GDPR, generalHttpClient, r.MetricsEngine, gdpr.VendorListURLMaker)
+	vendorListFetcher, vendorListStatus := gdpr.NewVendorListFetcherWithStatus(context.Background(), cfg.GDPR, generalHttpClient, r.MetricsEngine, gdpr.VendorListURLMaker)
+	readinessChecks.Add("gdpr_vendor_list", vendorListStatus.Ready)
 	gdprPermsBuilder := gdpr.NewPermissionsBuilder(cfg.GDPR, gvlVendorIDs, vendorListFetcher, r.MetricsEngine)
 	tcf2CfgBuilder := gdpr.NewTCF2Config
 
 	cacheClient := pbc.NewClient(cacheHttpClient, &cfg.CacheURL, &cfg.ExtCacheURL, r.MetricsEngine)
+	if cfg.CacheURL.Host != "" {
+		readinessChecks.Add("prebid_cache", pbc.NewStatusCheck(cacheHttpClient, &cfg.CacheURL))
+	}
 
 	adapters, singleFormatAdapters, adaptersErrs := exchange.BuildAdapters(generalHttpClient, cfg, cfg.BidderInfos, r.MetricsEngine)
 	if len(adaptersErrs) > 0 {
@@ -250,25 +278,78 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		logger.Fatalf("Failed to create ads cert signer: %v", err)
 	}
 
-	requestValidator := ortb.NewRequestValidator(activeBidders, disabledBidders, paramsValidator)
 	priceFloorFetcher := floors.NewPriceFloorFetcher(cfg.PriceFloors, floorFechterHttpClient, r.MetricsEngine)
+	if priceFloorFetcher != nil {
+		readinessChecks.Add("floors_fetcher", priceFloorFetcher.Ready)
+	}
 
 	tmaxAdjustments := exchange.ProcessTMaxAdjustments(cfg.TmaxAdjustments)
 	planBuilder := hooks.NewExecutionPlanBuilder(cfg.Hooks, repo)
 	macroReplacer := macros.NewStringIndexBasedReplacer()
 	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, requestValidator, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, rateConvertor, categoriesFetcher, adsCertSigner, macroReplacer, priceFloorFetcher, singleFormatAdapters)
 	var uuidGenerator uuidutil.UUIDRandomGenerator
-	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
+	var accessLog *accesslog.Logger
+	if cfg.Logging.AccessLog.Enabled {
+		ipValidator := iputil.PublicNetworkIPValidator{
+			IPv4PrivateNetworks: cfg.RequestValidation.IPv4PrivateNetworksParsed,
+			IPv6PrivateNetworks: cfg.RequestValidation.IPv6PrivateNetworksParsed,
+		}
+		if accessLog, err = accesslog.NewLogger(cfg.Logging.AccessLog, cfg.AccountDefaults.Privacy, ipValidator, clock.New()); err != nil {
+			return nil, fmt.Errorf("logging.access_log: %v", err)
+		}
+		r.shutdowns = append(r.shutdowns, accessLog.Shutdown)
+	}
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err := tracing.NewProvider(cfg.Tracing)
+		if err != nil {
+			return nil, fmt.Errorf("tracing: %v", err)
+		}
+		r.shutdowns = append(r.shutdowns, shutdownTracing)
+	}
+	pseudonymKeys, err := pseudonym.NewKeyring(cfg.Pseudonymization)
+	if err != nil {
+		return nil, fmt.Errorf("pseudonymization: %v", err)
+	}
+	if pseudonymKeys != nil {
+		r.shutdowns = append(r.shutdowns, pseudonymKeys.Shutdown)
+	}
+	optOut, err := optout.NewRegistry(cfg.OptOut, generalHttpClient)
+	if err != nil {
+		return nil, fmt.Errorf("optout: %v", err)
+	}
+	if optOut != nil {
+		r.shutdowns = append(r.shutdowns, optOut.Shutdown)
+	}
+	var cookieStore *usersync.CookieStore
+	if cfg.UserSync.IDStore.Enabled {
+		diskStore, err := idstore.NewDiskStore(cfg.UserSync.IDStore)
+		if err != nil {
+			return nil, fmt.Errorf("user_sync.id_store: %v", err)
+		}
+		r.shutdowns = append(r.shutdowns, diskStore.Shutdown)
+		cookieStore = usersync.NewCookieStore(cfg.UserSync.IDStore, diskStore)
+	}
+	// The metrics are exported last, to include those recorded while shutting down
+	r.shutdowns = append(r.shutdowns, r.MetricsEngine.Shutdown)
+	// publicEndpoint adds the log fields, the trace and the access log to the handler of a public endpoint
+	publicEndpoint := func(handle httprouter.Handle, endpoint string) httprouter.Handle {
+		handle = aspects.AccessLog(handle, endpoint, accessLog)
+		if cfg.Tracing.Enabled {
+			handle = aspects.Trace(handle, endpoint)
+		}
+		return aspects.LogFields(handle, endpoint, uuidGenerator)
+	}
+	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments, pseudonymKeys, optOut, cookieStore)
 	if err != nil {
 		logger.Fatalf("Failed to create the openrtb2 endpoint handler. %v", err)
 	}
 
-	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, requestValidator, ampFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
+	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, requestValidator, ampFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments, pseudonymKeys, optOut, cookieStore)
 	if err != nil {
 		logger.Fatalf("Failed to create the amp endpoint handler. %v", err)
 	}
 
-	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, videoFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, cacheClient, tmaxAdjustments)
+	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, videoFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, cacheClient, tmaxAdjustments, pseudonymKeys, optOut, cookieStore)
 	if err != nil {
 		logger.Fatalf("Failed to create the video endpoint handler. %v", err)
 	}
@@ -278,14 +359,17 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		videoEndpoint = aspects.QueuedRequestTimeout(videoEndpoint, cfg.RequestTimeoutHeaders, r.MetricsEngine, metrics.ReqTypeVideo)
 	}
 
-	r.POST("/openrtb2/auction", openrtbEndpoint)
-	r.POST("/openrtb2/video", videoEndpoint)
-	r.GET("/openrtb2/amp", ampEndpoint)
+	r.POST("/openrtb2/auction", publicEndpoint(openrtbEndpoint, "/openrtb2/auction"))
+	r.POST("/openrtb2/video", publicEndpoint(videoEndpoint, "/openrtb2/video"))
+	r.GET("/openrtb2/amp", publicEndpoint(ampEndpoint, "/openrtb2/amp"))
 	r.GET("/info/bidders", infoEndpoints.NewBiddersEndpoint(cfg.BidderInfos))
 	r.GET("/info/bidders/:bidderName", infoEndpoints.NewBiddersDetailEndpoint(cfg.BidderInfos))
 	r.GET("/bidders/params", NewJsonDirectoryServer(schemaDirectory, paramsValidator))
-	r.POST("/cookie_sync", endpoints.NewCookieSyncEndpoint(syncersByBidder, cfg, gdprPermsBuilder, tcf2CfgBuilder, r.MetricsEngine, analyticsRunner, accounts, activeBidders).Handle)
+	r.POST("/cookie_sync", publicEndpoint(endpoints.NewCookieSyncEndpoint(syncersByBidder, cfg, gdprPermsBuilder, tcf2CfgBuilder, r.MetricsEngine, analyticsRunner, accounts, activeBidders, cookieStore).Handle, "/cookie_sync"))
 	r.GET("/status", endpoints.NewStatusEndpoint(cfg.StatusResponse))
+	if readinessChecks != nil {
+		r.GET("/status/ready", endpoints.NewReadinessEndpoint(readinessChecks))
+	}
 	r.GET("/", serveIndex)
 	r.Handler("GET", "/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
 	r.ServeFiles("/static/*filepath", http.Dir("static"))
@@ -293,12 +377,12 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	// vtrack endpoint
 	if cfg.VTrack.Enabled {
 		vtrackEndpoint := events.NewVTrackEndpoint(cfg, accounts, cacheClient, cfg.BidderInfos, r.MetricsEngine)
-		r.POST("/vtrack", vtrackEndpoint)
+		r.POST("/vtrack", publicEndpoint(vtrackEndpoint, "/vtrack"))
 	}
 
 	// event endpoint
 	eventEndpoint := events.NewEventEndpoint(cfg, accounts, analyticsRunner, r.MetricsEngine)
-	r.GET("/event", eventEndpoint)
+	r.GET("/event", publicEndpoint(eventEndpoint, "/event"))
 
 	userSyncDeps := &pbs.UserSyncDeps{
 		HostCookieConfig: &(cfg.HostCookie),
@@ -306,13 +390,29 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		RecaptchaSecret:  cfg.RecaptchaSecret,
 		PriorityGroups:   cfg.UserSync.PriorityGroups,
 		CertPool:         certPool,
+		CookieStore:      cookieStore,
 	}
 
-	r.GET("/setuid", endpoints.NewSetUIDEndpoint(cfg, syncersByBidder, gdprPermsBuilder, tcf2CfgBuilder, analyticsRunner, accounts, r.MetricsEngine))
-	r.GET("/getuids", endpoints.NewGetUIDsEndpoint(cfg.HostCookie))
+	r.GET("/setuid", publicEndpoint(endpoints.NewSetUIDEndpoint(cfg, syncersByBidder, gdprPermsBuilder, tcf2CfgBuilder, analyticsRunner, accounts, r.MetricsEngine, cookieStore), "/setuid"))
+	r.GET("/getuids", endpoints.NewGetUIDsEndpoint(cfg.HostCookie, cookieStore))
 	r.POST("/optout", userSyncDeps.OptOut)
 	r.GET("/optout", userSyncDeps.OptOut)
 
+	r.adminHandlers = map[string]http.HandlerFunc{
+		"/account": endpoints.NewAccountConfigEndpoint(cfg, accounts),
+		"/storedrequests": endpoints.NewStoredRequestsEndpoint(map[string]stored_requests.Fetcher{
+			"auction": fetcher,
+			"amp":     ampFetcher,
+			"video":   videoFetcher,
+		}),
+		"/storedrequests/quarantine": endpoints.NewStoredDataQuarantineEndpoint(storedDataQuarantine),
+		"/gdpr/vendorlists":          endpoints.NewVendorListsEndpoint(vendorListStatus.Versions),
+		"/privacy/debug":             endpoints.NewPrivacyDebugEndpoint(cfg, accounts, gdprPermsBuilder, tcf2CfgBuilder, pseudonymKeys, optOut),
+	}
+	if optOut != nil {
+		r.adminHandlers["/privacy/optout"] = endpoints.NewOptOutEndpoint(optOut)
+	}
+
 	return r, nil
 }
 
@@ -0,0 +1,19 @@
+#!/bin/bash
+
+die() { echo -e "$@" 1>&2 ; exit 1;  }
+
+# Downloads the latest GDPR Global Vendor Lists of the supported spec versions to the snapshot directory loaded at
+# startup, named after their list version. Run it from the root of the project, then commit the new files.
+SNAPSHOT_DIR="${1:-static/vendorlist}"
+
+for SPEC_VERSION in 2 3; do
+  TMP_FILE=$(mktemp) || die "Failed to create a temporary file"
+  curl -fsS "https://vendor-list.consensu.org/v$SPEC_VERSION/vendor-list.json" -o "$TMP_FILE" || die "Failed to download the spec version $SPEC_VERSION vendor list"
+  LIST_VERSION=$(grep -o '"vendorListVersion" *: *[0-9]*' "$TMP_FILE" | grep -o '[0-9]*$')
+  [ -n "$LIST_VERSION" ] || die "The spec version $SPEC_VERSION vendor list has no vendorListVersion"
+
+  mkdir -p "$SNAPSHOT_DIR/v$SPEC_VERSION"
+  mv "$TMP_FILE" "$SNAPSHOT_DIR/v$SPEC_VERSION/vendor-list-v$LIST_VERSION.json"
+  chmod 644 "$SNAPSHOT_DIR/v$SPEC_VERSION/vendor-list-v$LIST_VERSION.json"
+  echo "Saved $SNAPSHOT_DIR/v$SPEC_VERSION/vendor-list-v$LIST_VERSION.json"
+done
@@ -0,0 +1,165 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	jsonpatch "gopkg.in/evanphx/json-patch.v5"
+)
+
+// AccountHierarchyFetcher is an AllFetcher which resolves accounts that declare a "parent" account.
+//
+// Every account in the chain is fetched on its own from the wrapped fetcher and merged over its parent,
+// so that an account only needs to define the settings which differ from its parent. The account defaults
+// are applied beneath the root of the chain.
+//
+// Resolved accounts are saved in their own cache. A resolved account depends on every account in its chain,
+// so saving or invalidating an account also invalidates every resolved account which descends from it.
+// To receive those events, AccountHierarchyFetcher implements CacheJSON and should be composed with the
+// account cache which the event listeners update.
+type AccountHierarchyFetcher struct {
+	AllFetcher
+	resolved CacheJSON
+	maxDepth int
+
+	lock sync.Mutex
+	// dependents maps an account ID to the IDs of the resolved accounts which inherit from it
+	dependents map[string]map[string]struct{}
+	// generation is incremented on every invalidation so that resolutions racing with it are not cached
+	generation uint64
+}
+
+// WithAccountHierarchy returns a fetcher which resolves account hierarchies of up to maxDepth parents
+// using the given fetcher, and caches the resolved accounts in the given cache.
+func WithAccountHierarchy(fetcher AllFetcher, resolved CacheJSON, maxDepth int) *AccountHierarchyFetcher {
+	return &AccountHierarchyFetcher{
+		AllFetcher: fetcher,
+		resolved:   resolved,
+		maxDepth:   maxDepth,
+		dependents: make(map[string]map[string]struct{}),
+	}
+}
+
+type accountParent struct {
+	Parent string `json:"parent"`
+}
+
+// FetchAccount fetches the account and all of its parents, and merges them over the account defaults
+func (f *AccountHierarchyFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	if account, ok := f.resolved.Get(ctx, []string{accountID})[accountID]; ok {
+		return account, nil
+	}
+
+	f.lock.Lock()
+	generation := f.generation
+	f.lock.Unlock()
+
+	chainIDs, chain, errs := f.fetchChain(ctx, accountID)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	account := accountDefaultsJSON
+	for i := len(chain) - 1; i >= 0; i-- {
+		if account == nil {
+			account = chain[i]
+			continue
+		}
+		merged, err := jsonpatch.MergePatch(account, chain[i])
+		if err != nil {
+			return nil, []error{fmt.Errorf("merging account %s over its parent: %v", chainIDs[i], err)}
+		}
+		account = merged
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if generation != f.generation {
+		return account, nil
+	}
+	for _, id := range chainIDs {
+		if f.dependents[id] == nil {
+			f.dependents[id] = make(map[string]struct{})
+		}
+		f.dependents[id][accountID] = struct{}{}
+	}
+	f.resolved.Save(ctx, map[string]json.RawMessage{accountID: account})
+
+	return account, nil
+}
+
+// fetchChain fetches the unmerged account data of the account and its parents, starting with the account itself
+func (f *AccountHierarchyFetcher) fetchChain(ctx context.Context, accountID string) (ids []string, chain []json.RawMessage, errs []error) {
+	visited := make(map[string]struct{})
+
+	for id := accountID; id != ""; {
+		if _, ok := visited[id]; ok {
+			return nil, nil, []error{fmt.Errorf("account %s has a cyclic parent hierarchy through account %s", accountID, id)}
+		}
+		if len(ids) > f.maxDepth {
+			return nil, nil, []error{fmt.Errorf("account %s exceeds the maximum hierarchy depth of %d", accountID, f.maxDepth)}
+		}
+		visited[id] = struct{}{}
+
+		account, fetchErrs := f.AllFetcher.FetchAccount(ctx, nil, id)
+		if len(fetchErrs) > 0 {
+			if id == accountID {
+				return nil, nil, fetchErrs
+			}
+			// a missing parent is a misconfiguration of the child, which must not be mistaken for an unknown account
+			for _, err := range fetchErrs {
+				errs = append(errs, fmt.Errorf("fetching parent of account %s: %v", accountID, err))
+			}
+			return nil, nil, errs
+		}
+
+		var parent accountParent
+		if err := jsonutil.Unmarshal(account, &parent); err != nil {
+			return nil, nil, []error{fmt.Errorf("reading parent of account %s: %v", id, err)}
+		}
+
+		ids = append(ids, id)
+		chain = append(chain, account)
+		id = parent.Parent
+	}
+
+	return ids, chain, nil
+}
+
+// Get does not return any data. Resolved accounts are only available through FetchAccount.
+func (f *AccountHierarchyFetcher) Get(ctx context.Context, ids []string) map[string]json.RawMessage {
+	return nil
+}
+
+// Save invalidates the resolved accounts which inherit from the saved accounts
+func (f *AccountHierarchyFetcher) Save(ctx context.Context, data map[string]json.RawMessage) {
+	ids := make([]string, 0, len(data))
+	for id := range data {
+		ids = append(ids, id)
+	}
+	f.Invalidate(ctx, ids)
+}
+
+// Invalidate invalidates the given accounts and the resolved accounts which inherit from them
+func (f *AccountHierarchyFetcher) Invalidate(ctx context.Context, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.generation++
+	invalidIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		invalidIDs = append(invalidIDs, id)
+		for dependent := range f.dependents[id] {
+			invalidIDs = append(invalidIDs, dependent)
+		}
+		delete(f.dependents, id)
+	}
+	f.resolved.Invalidate(ctx, invalidIDs)
+}
@@ -0,0 +1,218 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountHierarchyFetchAccount(t *testing.T) {
+	accounts := map[string]json.RawMessage{
+		"network":   json.RawMessage(`{"id":"network","debug_allow":false,"price_floors":{"enabled":true,"max_rules":10}}`),
+		"publisher": json.RawMessage(`{"id":"publisher","parent":"network","price_floors":{"max_rules":20}}`),
+		"site":      json.RawMessage(`{"id":"site","parent":"publisher","truncate_target_attr":15}`),
+		"orphan":    json.RawMessage(`{"id":"orphan","parent":"missing"}`),
+		"cycle-a":   json.RawMessage(`{"id":"cycle-a","parent":"cycle-b"}`),
+		"cycle-b":   json.RawMessage(`{"id":"cycle-b","parent":"cycle-a"}`),
+		"self":      json.RawMessage(`{"id":"self","parent":"self"}`),
+	}
+
+	testCases := []struct {
+		description     string
+		accountDefaults json.RawMessage
+		accountID       string
+		maxDepth        int
+		expectedAccount string
+		expectedErrs    int
+		expectNotFound  bool
+	}{
+		{
+			description:     "no-parent",
+			accountDefaults: json.RawMessage(`{"debug_allow":true,"default_bid_limit":3}`),
+			accountID:       "network",
+			maxDepth:        5,
+			expectedAccount: `{"id":"network","debug_allow":false,"default_bid_limit":3,"price_floors":{"enabled":true,"max_rules":10}}`,
+		},
+		{
+			description:     "no-defaults",
+			accountID:       "publisher",
+			maxDepth:        5,
+			expectedAccount: `{"id":"publisher","parent":"network","debug_allow":false,"price_floors":{"enabled":true,"max_rules":20}}`,
+		},
+		{
+			description:     "two-parents",
+			accountDefaults: json.RawMessage(`{"debug_allow":true,"default_bid_limit":3}`),
+			accountID:       "site",
+			maxDepth:        5,
+			expectedAccount: `{"id":"site","parent":"publisher","debug_allow":false,"default_bid_limit":3,"truncate_target_attr":15,"price_floors":{"enabled":true,"max_rules":20}}`,
+		},
+		{
+			description:     "max-depth-reached",
+			accountID:       "site",
+			maxDepth:        2,
+			expectedAccount: `{"id":"site","parent":"publisher","debug_allow":false,"truncate_target_attr":15,"price_floors":{"enabled":true,"max_rules":20}}`,
+		},
+		{
+			description:  "max-depth-exceeded",
+			accountID:    "site",
+			maxDepth:     1,
+			expectedErrs: 1,
+		},
+		{
+			description:    "account-not-found",
+			accountID:      "unknown",
+			maxDepth:       5,
+			expectedErrs:   1,
+			expectNotFound: true,
+		},
+		{
+			description:  "parent-not-found",
+			accountID:    "orphan",
+			maxDepth:     5,
+			expectedErrs: 1,
+		},
+		{
+			description:  "cycle",
+			accountID:    "cycle-a",
+			maxDepth:     5,
+			expectedErrs: 1,
+		},
+		{
+			description:  "own-parent",
+			accountID:    "self",
+			maxDepth:     5,
+			expectedErrs: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			fetcher := WithAccountHierarchy(&accountMapFetcher{accounts: accounts}, newMapCache(), test.maxDepth)
+
+			account, errs := fetcher.FetchAccount(context.Background(), test.accountDefaults, test.accountID)
+
+			require.Len(t, errs, test.expectedErrs)
+			if test.expectedErrs > 0 {
+				assert.Nil(t, account)
+				_, isNotFound := errs[0].(NotFoundError)
+				assert.Equal(t, test.expectNotFound, isNotFound)
+				return
+			}
+			assert.JSONEq(t, test.expectedAccount, string(account))
+		})
+	}
+}
+
+func TestAccountHierarchyCachesResolvedAccount(t *testing.T) {
+	accountFetcher := &accountMapFetcher{accounts: map[string]json.RawMessage{
+		"network":   json.RawMessage(`{"debug_allow":false}`),
+		"publisher": json.RawMessage(`{"parent":"network"}`),
+	}}
+	fetcher := WithAccountHierarchy(accountFetcher, newMapCache(), 5)
+
+	first, errs := fetcher.FetchAccount(context.Background(), nil, "publisher")
+	require.Empty(t, errs)
+	second, errs := fetcher.FetchAccount(context.Background(), nil, "publisher")
+	require.Empty(t, errs)
+
+	assert.JSONEq(t, string(first), string(second))
+	assert.Equal(t, 2, accountFetcher.calls, "the resolved account should be served from the cache")
+}
+
+func TestAccountHierarchyInvalidationCascades(t *testing.T) {
+	testCases := []struct {
+		description string
+		update      func(f *AccountHierarchyFetcher, accounts map[string]json.RawMessage)
+		expected    string
+	}{
+		{
+			description: "save-parent",
+			update: func(f *AccountHierarchyFetcher, accounts map[string]json.RawMessage) {
+				accounts["network"] = json.RawMessage(`{"debug_allow":true}`)
+				f.Save(context.Background(), map[string]json.RawMessage{"network": accounts["network"]})
+			},
+			expected: `{"parent":"network","debug_allow":true}`,
+		},
+		{
+			description: "invalidate-parent",
+			update: func(f *AccountHierarchyFetcher, accounts map[string]json.RawMessage) {
+				accounts["network"] = json.RawMessage(`{"debug_allow":true}`)
+				f.Invalidate(context.Background(), []string{"network"})
+			},
+			expected: `{"parent":"network","debug_allow":true}`,
+		},
+		{
+			description: "invalidate-unrelated",
+			update: func(f *AccountHierarchyFetcher, accounts map[string]json.RawMessage) {
+				accounts["network"] = json.RawMessage(`{"debug_allow":true}`)
+				f.Invalidate(context.Background(), []string{"other"})
+			},
+			expected: `{"parent":"network","debug_allow":false}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			accounts := map[string]json.RawMessage{
+				"network":   json.RawMessage(`{"debug_allow":false}`),
+				"publisher": json.RawMessage(`{"parent":"network"}`),
+			}
+			fetcher := WithAccountHierarchy(&accountMapFetcher{accounts: accounts}, newMapCache(), 5)
+
+			_, errs := fetcher.FetchAccount(context.Background(), nil, "publisher")
+			require.Empty(t, errs)
+
+			test.update(fetcher, accounts)
+
+			account, errs := fetcher.FetchAccount(context.Background(), nil, "publisher")
+			require.Empty(t, errs)
+			assert.JSONEq(t, test.expected, string(account))
+		})
+	}
+}
+
+// accountMapFetcher serves unmerged accounts from a map and counts the fetches
+type accountMapFetcher struct {
+	mockFetcher
+	accounts map[string]json.RawMessage
+	calls    int
+}
+
+func (f *accountMapFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	f.calls++
+	if account, ok := f.accounts[accountID]; ok {
+		return account, nil
+	}
+	return nil, []error{NotFoundError{ID: accountID, DataType: "Account"}}
+}
+
+type mapCache map[string]json.RawMessage
+
+func newMapCache() mapCache {
+	return make(mapCache)
+}
+
+func (c mapCache) Get(ctx context.Context, ids []string) map[string]json.RawMessage {
+	data := make(map[string]json.RawMessage, len(ids))
+	for _, id := range ids {
+		if value, ok := c[id]; ok {
+			data[id] = value
+		}
+	}
+	return data
+}
+
+func (c mapCache) Save(ctx context.Context, data map[string]json.RawMessage) {
+	for id, value := range data {
+		c[id] = value
+	}
+}
+
+func (c mapCache) Invalidate(ctx context.Context, ids []string) {
+	for _, id := range ids {
+		delete(c, id)
+	}
+}
@@ -10,6 +10,17 @@ import (
 	"github.com/prebid/prebid-server/v3/stored_requests"
 )
 
+// Options configures the optional behaviors of an LRU in-memory Cache
+type Options struct {
+	// NotFoundTTL is the number of seconds IDs which are unknown to the backend are remembered.
+	// Values <= 0 disable the caching of unknown IDs.
+	NotFoundTTL int
+	// StaleWhileRevalidate is the number of seconds past the TTL during which data may be served while it is refreshed
+	StaleWhileRevalidate int
+	// StaleIfError is the number of seconds past the TTL during which data may be served if the backend is failing
+	StaleIfError int
+}
+
 // NewCache returns an in-memory Cache which evicts items if:
 //
 // 1. They haven't been used within the TTL.
@@ -17,11 +28,21 @@ import (
 //
 // For no TTL, use ttlSeconds <= 0
 func NewCache(size int, ttl int, dataType string) stored_requests.CacheJSON {
+	return NewCacheWithOptions(size, ttl, dataType, Options{})
+}
+
+// NewCacheWithOptions works like NewCache, but the LRU cache may also remember unknown IDs and retain stale data
+// as configured by the options. Options are ignored for unbounded caches.
+func NewCacheWithOptions(size int, ttl int, dataType string, options Options) stored_requests.CacheJSON {
 	if ttl > 0 && size <= 0 {
 		// a positive ttl indicates "LRU" cache type, while unlimited size indicates an "unbounded" cache type
 		logger.Fatalf("unbounded in-memory %s cache with TTL not allowed. Config validation should have caught this. Failing fast because something is buggy.", dataType)
 	}
-	if size > 0 {
+	if size > 0 && options != (Options{}) {
+		logger.Infof("Using a Stored %s in-memory cache. Max size: %d bytes. TTL: %d seconds. Not found TTL: %d seconds. Stale while revalidate: %d seconds. Stale if error: %d seconds.",
+			dataType, size, ttl, options.NotFoundTTL, options.StaleWhileRevalidate, options.StaleIfError)
+		return newStaleCache(size, systemTimer{}, ttl, options)
+	} else if size > 0 {
 		logger.Infof("Using a Stored %s in-memory cache. Max size: %d bytes. TTL: %d seconds.", dataType, size, ttl)
 		return &cache{
 			dataType: dataType,
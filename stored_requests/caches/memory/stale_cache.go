@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/coocood/freecache"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+)
+
+// notFoundValue marks the IDs which are unknown to the backend. It can't be mistaken for data, which is valid JSON.
+var notFoundValue = []byte{0}
+
+// systemTimer gives freecache the current time, like its default timer does
+type systemTimer struct{}
+
+func (systemTimer) Now() uint32 {
+	return uint32(time.Now().Unix())
+}
+
+// staleCache is an LRU cache which retains data past its TTL and remembers IDs unknown to the backend.
+//
+// Data is saved with an expiration of its TTL plus the longest period it may be served stale. It is considered
+// fresh until the TTL elapses, and stale for the remainder of its lifetime.
+type staleCache struct {
+	cache      *freecache.Cache
+	timer      freecache.Timer
+	ttlSeconds int
+	// staleSeconds is the number of seconds data is retained past its TTL
+	staleSeconds int
+	options      Options
+}
+
+func newStaleCache(size int, timer freecache.Timer, ttl int, options Options) *staleCache {
+	staleSeconds := 0
+	if ttl > 0 {
+		staleSeconds = max(options.StaleWhileRevalidate, options.StaleIfError, 0)
+	}
+	return &staleCache{
+		cache:        freecache.NewCacheCustomTimer(size, timer),
+		timer:        timer,
+		ttlSeconds:   ttl,
+		staleSeconds: staleSeconds,
+		options:      options,
+	}
+}
+
+func (c *staleCache) Get(ctx context.Context, ids []string) (data map[string]json.RawMessage) {
+	data = make(map[string]json.RawMessage, len(ids))
+	now := c.timer.Now()
+	for _, id := range ids {
+		if value, expireAt, ok := c.get(id); ok && !bytes.Equal(value, notFoundValue) && c.staleFor(expireAt, now) < 0 {
+			data[id] = value
+		}
+	}
+	return
+}
+
+func (c *staleCache) GetStale(ctx context.Context, ids []string) map[string]stored_requests.StaleData {
+	data := make(map[string]stored_requests.StaleData)
+	now := c.timer.Now()
+	for _, id := range ids {
+		value, expireAt, ok := c.get(id)
+		if !ok || bytes.Equal(value, notFoundValue) {
+			continue
+		}
+		if staleFor := c.staleFor(expireAt, now); staleFor >= 0 {
+			data[id] = stored_requests.StaleData{
+				Data:       value,
+				Revalidate: staleFor < int64(c.options.StaleWhileRevalidate),
+				IfError:    staleFor < int64(c.options.StaleIfError),
+			}
+		}
+	}
+	return data
+}
+
+func (c *staleCache) GetNotFound(ctx context.Context, ids []string) []string {
+	var notFound []string
+	for _, id := range ids {
+		if value, _, ok := c.get(id); ok && bytes.Equal(value, notFoundValue) {
+			notFound = append(notFound, id)
+		}
+	}
+	return notFound
+}
+
+func (c *staleCache) Save(ctx context.Context, data map[string]json.RawMessage) {
+	expireSeconds := 0
+	if c.ttlSeconds > 0 {
+		expireSeconds = c.ttlSeconds + c.staleSeconds
+	}
+	for id, value := range data {
+		c.set(id, value, expireSeconds)
+	}
+}
+
+func (c *staleCache) SaveNotFound(ctx context.Context, ids []string) {
+	if c.options.NotFoundTTL <= 0 {
+		return
+	}
+	for _, id := range ids {
+		c.set(id, notFoundValue, c.options.NotFoundTTL)
+	}
+}
+
+func (c *staleCache) Invalidate(ctx context.Context, ids []string) {
+	for _, id := range ids {
+		c.cache.Del([]byte(id))
+	}
+}
+
+func (c *staleCache) get(id string) ([]byte, uint32, bool) {
+	value, expireAt, err := c.cache.GetWithExpiration([]byte(id))
+	if err == nil {
+		return value, expireAt, true
+	}
+	if err != freecache.ErrNotFound {
+		logger.Errorf("unexpected error from freecache: %v", err)
+	}
+	return nil, 0, false
+}
+
+func (c *staleCache) set(id string, value []byte, expireSeconds int) {
+	if err := c.cache.Set([]byte(id), value, expireSeconds); err != nil {
+		logger.Errorf("error saving value in freecache: %v", err)
+	}
+}
+
+// staleFor returns the number of seconds the data with the given expiration has been past its TTL,
+// or a negative number if it is still fresh
+func (c *staleCache) staleFor(expireAt uint32, now uint32) int64 {
+	if c.staleSeconds == 0 || expireAt == 0 {
+		return -1
+	}
+	return int64(now) - (int64(expireAt) - int64(c.staleSeconds))
+}
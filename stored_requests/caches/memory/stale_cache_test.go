@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/caches/cachestest"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTimer struct {
+	now uint32
+}
+
+func (t *fakeTimer) Now() uint32 {
+	return t.now
+}
+
+func TestStaleCacheRobustness(t *testing.T) {
+	cachestest.AssertCacheRobustness(t, func() stored_requests.CacheJSON {
+		return NewCacheWithOptions(256*1024, -1, "TestData", Options{NotFoundTTL: 30})
+	})
+}
+
+func TestRaceStaleCacheConcurrency(t *testing.T) {
+	cache := NewCacheWithOptions(256*1024, 60, "TestData", Options{StaleWhileRevalidate: 30})
+	doRaceTest(t, cache)
+}
+
+func TestStaleCacheLifetime(t *testing.T) {
+	ctx := context.Background()
+	timer := &fakeTimer{now: 1000}
+	cache := newStaleCache(256*1024, timer, 60, Options{StaleWhileRevalidate: 30, StaleIfError: 120})
+	cache.Save(ctx, map[string]json.RawMessage{"id": json.RawMessage(`{"a":1}`)})
+
+	testCases := []struct {
+		description        string
+		elapsed            uint32
+		expectFresh        bool
+		expectStale        bool
+		expectRevalidate   bool
+		expectStaleIfError bool
+	}{
+		{
+			description: "fresh",
+			elapsed:     59,
+			expectFresh: true,
+		},
+		{
+			description:        "stale-while-revalidate",
+			elapsed:            60,
+			expectStale:        true,
+			expectRevalidate:   true,
+			expectStaleIfError: true,
+		},
+		{
+			description:        "stale-if-error",
+			elapsed:            90,
+			expectStale:        true,
+			expectStaleIfError: true,
+		},
+		{
+			description: "expired",
+			elapsed:     180,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			timer.now = 1000 + test.elapsed
+
+			fresh := cache.Get(ctx, []string{"id"})
+			stale := cache.GetStale(ctx, []string{"id"})
+
+			if test.expectFresh {
+				assert.JSONEq(t, `{"a":1}`, string(fresh["id"]))
+			} else {
+				assert.Empty(t, fresh)
+			}
+			if test.expectStale {
+				assert.JSONEq(t, `{"a":1}`, string(stale["id"].Data))
+				assert.Equal(t, test.expectRevalidate, stale["id"].Revalidate)
+				assert.Equal(t, test.expectStaleIfError, stale["id"].IfError)
+			} else {
+				assert.Empty(t, stale)
+			}
+		})
+	}
+}
+
+func TestStaleCacheNotFound(t *testing.T) {
+	ctx := context.Background()
+	timer := &fakeTimer{now: 1000}
+	cache := newStaleCache(256*1024, timer, 60, Options{NotFoundTTL: 10, StaleIfError: 60})
+
+	cache.SaveNotFound(ctx, []string{"missing"})
+
+	assert.Equal(t, []string{"missing"}, cache.GetNotFound(ctx, []string{"missing", "other"}))
+	assert.Empty(t, cache.Get(ctx, []string{"missing"}), "unknown IDs must not be served as data")
+	assert.Empty(t, cache.GetStale(ctx, []string{"missing"}), "unknown IDs must not be served as stale data")
+
+	timer.now += 10
+	assert.Empty(t, cache.GetNotFound(ctx, []string{"missing"}), "unknown IDs must expire after the not found TTL")
+
+	cache.SaveNotFound(ctx, []string{"saved"})
+	cache.Save(ctx, map[string]json.RawMessage{"saved": json.RawMessage(`{}`)})
+	assert.Empty(t, cache.GetNotFound(ctx, []string{"saved"}), "saved data must replace unknown IDs")
+
+	cache.SaveNotFound(ctx, []string{"invalidated"})
+	cache.Invalidate(ctx, []string{"invalidated"})
+	assert.Empty(t, cache.GetNotFound(ctx, []string{"invalidated"}), "invalidations must clear unknown IDs")
+}
+
+func TestStaleCacheNotFoundDisabled(t *testing.T) {
+	ctx := context.Background()
+	cache := newStaleCache(256*1024, &fakeTimer{now: 1000}, 60, Options{StaleWhileRevalidate: 30})
+
+	cache.SaveNotFound(ctx, []string{"missing"})
+
+	assert.Empty(t, cache.GetNotFound(ctx, []string{"missing"}))
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -9,6 +11,9 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/logger"
 	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/readiness"
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
@@ -21,6 +26,7 @@ import (
 	apiEvents "github.com/prebid/prebid-server/v3/stored_requests/events/api"
 	databaseEvents "github.com/prebid/prebid-server/v3/stored_requests/events/database"
 	httpEvents "github.com/prebid/prebid-server/v3/stored_requests/events/http"
+	"github.com/prebid/prebid-server/v3/stored_requests/validation"
 	"github.com/prebid/prebid-server/v3/util/task"
 )
 
@@ -34,7 +40,7 @@ import (
 //
 // As a side-effect, it will add some endpoints to the router if the config calls for it.
 // In the future we should look for ways to simplify this so that it's not doing two things.
-func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher, shutdown func()) {
+func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, provider db_provider.DbProvider, validator events.Validator, quarantine *validation.Quarantine, checks *readiness.Checks) (fetcher stored_requests.AllFetcher, shutdown func()) {
 	// Create database connection if given options for one
 	if cfg.Database.ConnectionInfo.Database != "" {
 		if provider == nil {
@@ -52,9 +58,13 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 		if provider.Config() != cfg.Database.ConnectionInfo {
 			logger.Fatalf("Multiple database connection settings found in config, only a single database connection is currently supported.")
 		}
+		checks.Add(cfg.Section()+".database", func(context.Context) error {
+			return provider.Ping()
+		})
 	}
 
-	eventProducers := newEventProducers(cfg, client, provider, metricsEngine, router)
+	eventProducers := newEventProducers(cfg, client, provider, metricsEngine, router, validator)
+	addPollerChecks(cfg, eventProducers, checks)
 	fetcher = newFetcher(cfg, client, provider)
 
 	var shutdown1 func()
@@ -62,7 +72,19 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 	if cfg.InMemoryCache.Type != "" {
 		cache := newCache(cfg)
 		fetcher = stored_requests.WithCache(fetcher, cache, metricsEngine)
-		shutdown1 = addListeners(cache, eventProducers)
+		if cfg.Hierarchy.Enabled {
+			accountHierarchy := newAccountHierarchy(cfg, fetcher)
+			cache.Accounts = stored_requests.ComposedCache{cache.Accounts, accountHierarchy}
+			fetcher = accountHierarchy
+		}
+		shutdown1 = addListeners(cache, eventProducers, validator)
+	} else if cfg.Hierarchy.Enabled {
+		fetcher = newAccountHierarchy(cfg, fetcher)
+	}
+
+	// accounts are validated once complete, after the hierarchy is resolved
+	if cfg.DataType() == config.AccountDataType && cfg.Validation.Enabled {
+		fetcher = validation.WithAccountValidation(fetcher, metricsEngine, sectionQuarantine(cfg, quarantine))
 	}
 
 	shutdown = func() {
@@ -96,7 +118,13 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 //
 // As a side-effect, it will add some endpoints to the router if the config calls for it.
 // In the future we should look for ways to simplify this so that it's not doing two things.
-func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router) (shutdown func(),
+//
+// The data saved to the caches of the sections with validation enabled is checked with the given validators,
+// and invalid data is kept in the quarantine of the sections which require it. Accounts are also validated when
+// they're loaded, once merged with their parents and the account defaults.
+//
+// The databases and the polled event sources of each section are added to the readiness checks.
+func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, requestValidator ortb.RequestValidator, paramsValidator openrtb_ext.BidderParamValidator, quarantine *validation.Quarantine, checks *readiness.Checks) (shutdown func(),
 	fetcher stored_requests.Fetcher,
 	ampFetcher stored_requests.Fetcher,
 	accountsFetcher stored_requests.AccountFetcher,
@@ -106,12 +134,12 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 
 	var provider db_provider.DbProvider
 
-	fetcher1, shutdown1 := CreateStoredRequests(&cfg.StoredRequests, metricsEngine, client, router, provider)
-	fetcher2, shutdown2 := CreateStoredRequests(&cfg.StoredRequestsAMP, metricsEngine, client, router, provider)
-	fetcher3, shutdown3 := CreateStoredRequests(&cfg.CategoryMapping, metricsEngine, client, router, provider)
-	fetcher4, shutdown4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, provider)
-	fetcher5, shutdown5 := CreateStoredRequests(&cfg.Accounts, metricsEngine, client, router, provider)
-	fetcher6, shutdown6 := CreateStoredRequests(&cfg.StoredResponses, metricsEngine, client, router, provider)
+	fetcher1, shutdown1 := CreateStoredRequests(&cfg.StoredRequests, metricsEngine, client, router, provider, newValidator(&cfg.StoredRequests, metricsEngine, requestValidator, paramsValidator, quarantine), quarantine, checks)
+	fetcher2, shutdown2 := CreateStoredRequests(&cfg.StoredRequestsAMP, metricsEngine, client, router, provider, newValidator(&cfg.StoredRequestsAMP, metricsEngine, requestValidator, paramsValidator, quarantine), quarantine, checks)
+	fetcher3, shutdown3 := CreateStoredRequests(&cfg.CategoryMapping, metricsEngine, client, router, provider, newValidator(&cfg.CategoryMapping, metricsEngine, requestValidator, paramsValidator, quarantine), quarantine, checks)
+	fetcher4, shutdown4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, provider, newValidator(&cfg.StoredVideo, metricsEngine, requestValidator, paramsValidator, quarantine), quarantine, checks)
+	fetcher5, shutdown5 := CreateStoredRequests(&cfg.Accounts, metricsEngine, client, router, provider, newValidator(&cfg.Accounts, metricsEngine, requestValidator, paramsValidator, quarantine), quarantine, checks)
+	fetcher6, shutdown6 := CreateStoredRequests(&cfg.StoredResponses, metricsEngine, client, router, provider, newValidator(&cfg.StoredResponses, metricsEngine, requestValidator, paramsValidator, quarantine), quarantine, checks)
 
 	fetcher = fetcher1.(stored_requests.Fetcher)
 	ampFetcher = fetcher2.(stored_requests.Fetcher)
@@ -132,11 +160,35 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 	return
 }
 
-func addListeners(cache stored_requests.Cache, eventProducers []events.EventProducer) (shutdown func()) {
+// newValidator returns the validator of the stored data saved to the caches of the section, or nil if validation is disabled
+func newValidator(cfg *config.StoredRequests, metricsEngine metrics.MetricsEngine, requestValidator ortb.RequestValidator, paramsValidator openrtb_ext.BidderParamValidator, quarantine *validation.Quarantine) events.Validator {
+	if !cfg.Validation.Enabled {
+		return nil
+	}
+	quarantine = sectionQuarantine(cfg, quarantine)
+	logger.Infof("Validating Stored %s data saved to the caches. Quarantine: %t", cfg.DataType(), quarantine != nil)
+	if cfg.DataType() == config.AccountDataType && cfg.Hierarchy.Enabled {
+		return validation.NewAccountHierarchyValidator(metricsEngine, quarantine)
+	}
+	return validation.NewValidator(cfg.DataType(), requestValidator, paramsValidator, metricsEngine, quarantine)
+}
+
+// sectionQuarantine returns the quarantine of the section, or nil if it doesn't keep its invalid data
+func sectionQuarantine(cfg *config.StoredRequests, quarantine *validation.Quarantine) *validation.Quarantine {
+	if !cfg.Validation.Quarantine {
+		return nil
+	}
+	return quarantine
+}
+
+func addListeners(cache stored_requests.Cache, eventProducers []events.EventProducer, validator events.Validator) (shutdown func()) {
 	listeners := make([]*events.EventListener, 0, len(eventProducers))
 
 	for _, ep := range eventProducers {
 		listener := events.SimpleEventListener()
+		if validator != nil {
+			listener = events.NewValidatingEventListener(validator, nil, nil)
+		}
 		go listener.Listen(cache, ep)
 		listeners = append(listeners, listener)
 	}
@@ -183,18 +235,37 @@ func newCache(cfg *config.StoredRequests) stored_requests.Cache {
 	case cfg.InMemoryCache.Type == "none":
 		logger.Warnf("No %s cache configured. The %s Fetcher backend will be used for all data requests", cfg.DataType(), cfg.DataType())
 	case cfg.DataType() == config.AccountDataType:
-		cache.Accounts = memory.NewCache(cfg.InMemoryCache.Size, cfg.InMemoryCache.TTL, "Accounts")
+		cache.Accounts = memory.NewCacheWithOptions(cfg.InMemoryCache.Size, cfg.InMemoryCache.TTL, "Accounts", cacheOptions(cfg))
 	default:
-		cache.Requests = memory.NewCache(cfg.InMemoryCache.RequestCacheSize, cfg.InMemoryCache.TTL, "Requests")
-		cache.Imps = memory.NewCache(cfg.InMemoryCache.ImpCacheSize, cfg.InMemoryCache.TTL, "Imps")
-		cache.Responses = memory.NewCache(cfg.InMemoryCache.RespCacheSize, cfg.InMemoryCache.TTL, "Responses")
+		cache.Requests = memory.NewCacheWithOptions(cfg.InMemoryCache.RequestCacheSize, cfg.InMemoryCache.TTL, "Requests", cacheOptions(cfg))
+		cache.Imps = memory.NewCacheWithOptions(cfg.InMemoryCache.ImpCacheSize, cfg.InMemoryCache.TTL, "Imps", cacheOptions(cfg))
+		cache.Responses = memory.NewCacheWithOptions(cfg.InMemoryCache.RespCacheSize, cfg.InMemoryCache.TTL, "Responses", cacheOptions(cfg))
 	}
 	return cache
 }
 
-func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, metricsEngine metrics.MetricsEngine, router *httprouter.Router) (eventProducers []events.EventProducer) {
+func cacheOptions(cfg *config.StoredRequests) memory.Options {
+	return memory.Options{
+		NotFoundTTL:          cfg.InMemoryCache.NotFoundTTL,
+		StaleWhileRevalidate: cfg.InMemoryCache.StaleWhileRevalidate,
+		StaleIfError:         cfg.InMemoryCache.StaleIfError,
+	}
+}
+
+// newAccountHierarchy wraps the fetcher to resolve accounts which inherit from a parent account. Resolved accounts
+// are cached alongside the account cache, with the same size and TTL.
+func newAccountHierarchy(cfg *config.StoredRequests, fetcher stored_requests.AllFetcher) *stored_requests.AccountHierarchyFetcher {
+	var resolved stored_requests.CacheJSON = &nil_cache.NilCache{}
+	if cfg.InMemoryCache.Type != "" && cfg.InMemoryCache.Type != "none" {
+		resolved = memory.NewCache(cfg.InMemoryCache.Size, cfg.InMemoryCache.TTL, "Resolved Accounts")
+	}
+	logger.Infof("Resolving account hierarchies with a maximum depth of %d", cfg.Hierarchy.MaxDepth)
+	return stored_requests.WithAccountHierarchy(fetcher, resolved, cfg.Hierarchy.MaxDepth)
+}
+
+func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, metricsEngine metrics.MetricsEngine, router *httprouter.Router, validator events.Validator) (eventProducers []events.EventProducer) {
 	if cfg.CacheEvents.Enabled {
-		eventProducers = append(eventProducers, newEventsAPI(router, cfg.CacheEvents.Endpoint))
+		eventProducers = append(eventProducers, newEventsAPI(router, cfg.CacheEvents.Endpoint, validator))
 	}
 	if cfg.HTTPEvents.RefreshRate != 0 && cfg.HTTPEvents.Endpoint != "" {
 		eventProducers = append(eventProducers, newHttpEvents(client, cfg.HTTPEvents.TimeoutDuration(), cfg.HTTPEvents.RefreshRateDuration(), cfg.HTTPEvents.Endpoint))
@@ -218,8 +289,37 @@ func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider
 	return
 }
 
-func newEventsAPI(router *httprouter.Router, endpoint string) events.EventProducer {
-	producer, handler := apiEvents.NewEventsAPI()
+// pollStaleIntervals is the number of refresh intervals without a successful poll after which a source is not ready
+const pollStaleIntervals = 3
+
+// addPollerChecks adds a readiness check for each event producer polling a source of stored data. The check fails if
+// the source was never polled successfully, or if the last successful poll is older than a few refresh intervals.
+func addPollerChecks(cfg *config.StoredRequests, eventProducers []events.EventProducer, checks *readiness.Checks) {
+	for _, ep := range eventProducers {
+		switch producer := ep.(type) {
+		case *httpEvents.HTTPEvents:
+			checks.Add(cfg.Section()+".http_events", newPollerCheck(producer, cfg.HTTPEvents.RefreshRateDuration()))
+		case *databaseEvents.DatabaseEventProducer:
+			checks.Add(cfg.Section()+".database_events", newPollerCheck(producer, time.Duration(cfg.Database.PollUpdates.RefreshRate)*time.Second))
+		}
+	}
+}
+
+func newPollerCheck(poller events.Poller, refreshRate time.Duration) readiness.Check {
+	return func(context.Context) error {
+		lastPoll := poller.LastPoll()
+		if lastPoll.IsZero() {
+			return errors.New("the stored data was never polled successfully")
+		}
+		if refreshRate > 0 && time.Since(lastPoll) > pollStaleIntervals*refreshRate {
+			return fmt.Errorf("the stored data was last polled successfully at %s", lastPoll.Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+func newEventsAPI(router *httprouter.Router, endpoint string, validator events.Validator) events.EventProducer {
+	producer, handler := apiEvents.NewValidatingEventsAPI(validator)
 	router.POST(endpoint, handler)
 	router.DELETE(endpoint, handler)
 	return producer
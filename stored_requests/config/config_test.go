@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/readiness"
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
@@ -158,11 +160,85 @@ func TestNewHTTPEvents(t *testing.T) {
 
 	metricsMock := &metrics.MetricsEngineMock{}
 
-	evProducers := newEventProducers(cfg, server1.Client(), nil, metricsMock, nil)
+	evProducers := newEventProducers(cfg, server1.Client(), nil, metricsMock, nil, nil)
 	assertSliceLength(t, evProducers, 1)
 	assertHttpWithURL(t, evProducers[0], server1.URL)
 }
 
+func TestHTTPEventsReadinessCheck(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	cfg := typedConfig(config.RequestDataType, &config.StoredRequests{
+		HTTPEvents: config.HTTPEventsConfig{
+			Endpoint:    server.URL,
+			RefreshRate: 100,
+			Timeout:     1000,
+		},
+	})
+	checks := readiness.NewChecks(config.Readiness{Enabled: true, TimeoutMS: 100, Critical: []string{"stored_requests"}})
+
+	addPollerChecks(cfg, newEventProducers(cfg, server.Client(), nil, &metrics.MetricsEngineMock{}, nil, nil), checks)
+
+	report := checks.Run(context.Background())
+	assert.False(t, report.Ready)
+	assert.Equal(t, map[string]readiness.Result{
+		"stored_requests.http_events": {Status: readiness.StatusNotReady, Critical: true, Error: "the stored data was never polled successfully"},
+	}, report.Checks)
+}
+
+type fakePoller time.Time
+
+func (p fakePoller) LastPoll() time.Time {
+	return time.Time(p)
+}
+
+func TestPollerCheck(t *testing.T) {
+	lastPoll := time.Now().Add(-time.Minute).UTC()
+	testCases := []struct {
+		name        string
+		lastPoll    time.Time
+		refreshRate time.Duration
+		expectedErr string
+	}{
+		{
+			name:        "never-polled",
+			refreshRate: time.Minute,
+			expectedErr: "the stored data was never polled successfully",
+		},
+		{
+			name:        "recent",
+			lastPoll:    lastPoll,
+			refreshRate: time.Minute,
+		},
+		{
+			name:        "stale",
+			lastPoll:    lastPoll,
+			refreshRate: 10 * time.Second,
+			expectedErr: "the stored data was last polled successfully at " + lastPoll.Format(time.RFC3339),
+		},
+		{
+			name:        "never-refreshed",
+			lastPoll:    lastPoll,
+			refreshRate: -1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := newPollerCheck(fakePoller(test.lastPoll), test.refreshRate)(context.Background())
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
 func TestNewEmptyCache(t *testing.T) {
 	cache := newCache(&config.StoredRequests{InMemoryCache: config.InMemoryCache{Type: "none"}})
 	assert.True(t, isEmptyCacheType(cache.Requests), "The newCache method should return an empty Request cache")
@@ -224,7 +300,7 @@ func TestNewDatabaseEventProducers(t *testing.T) {
 	}
 	mock.ExpectQuery("^" + regexp.QuoteMeta(cfg.Database.CacheInitialization.Query) + "$").WillReturnError(errors.New("Query failed"))
 
-	evProducers := newEventProducers(cfg, client, provider, metricsMock, nil)
+	evProducers := newEventProducers(cfg, client, provider, metricsMock, nil, nil)
 	assertProducerLength(t, evProducers, 1)
 
 	assertExpectationsMet(t, mock)
@@ -233,7 +309,7 @@ func TestNewDatabaseEventProducers(t *testing.T) {
 
 func TestNewEventsAPI(t *testing.T) {
 	router := httprouter.New()
-	newEventsAPI(router, "/test-endpoint")
+	newEventsAPI(router, "/test-endpoint", nil)
 	if handle, _, _ := router.Lookup("POST", "/test-endpoint"); handle == nil {
 		t.Error("The newEventsAPI method didn't add a POST /test-endpoint route")
 	}
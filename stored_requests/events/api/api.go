@@ -12,6 +12,17 @@ import (
 type eventsAPI struct {
 	saves         chan events.Save
 	invalidations chan events.Invalidation
+	validator     events.Validator
+}
+
+type validationErrorsResponse struct {
+	Errors []validationErrorResponse `json:"errors"`
+}
+
+type validationErrorResponse struct {
+	DataType string `json:"type"`
+	ID       string `json:"id"`
+	Error    string `json:"error"`
 }
 
 // NewEventsAPI creates an EventProducer that generates cache events from HTTP requests.
@@ -26,9 +37,18 @@ type eventsAPI struct {
 // The returned HTTP endpoint should not be exposed on a public network without authentication
 // as it allows direct writing to the cache via Update.
 func NewEventsAPI() (events.EventProducer, httprouter.Handle) {
+	return NewValidatingEventsAPI(nil)
+}
+
+// NewValidatingEventsAPI works like NewEventsAPI, but validates the stored data of updates before producing save
+// events. Valid data is saved even if some of the update is invalid, in which case the response has status
+// 422 Unprocessable Entity and lists the invalid IDs with the reasons they were rejected. The caches keep serving the
+// previous version of the rejected data, if any.
+func NewValidatingEventsAPI(validator events.Validator) (events.EventProducer, httprouter.Handle) {
 	api := &eventsAPI{
 		invalidations: make(chan events.Invalidation),
 		saves:         make(chan events.Save),
+		validator:     validator,
 	}
 	return api, httprouter.Handle(api.HandleEvent)
 }
@@ -49,7 +69,17 @@ func (api *eventsAPI) HandleEvent(w http.ResponseWriter, r *http.Request, _ http
 			return
 		}
 
+		var validationErrs []events.ValidationError
+		if api.validator != nil {
+			save, validationErrs = api.validator.Validate(save)
+			save.Validated = true
+		}
+
 		api.saves <- save
+
+		if len(validationErrs) > 0 {
+			writeValidationErrors(w, validationErrs)
+		}
 	} else if r.Method == "DELETE" {
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
@@ -71,6 +101,26 @@ func (api *eventsAPI) HandleEvent(w http.ResponseWriter, r *http.Request, _ http
 	}
 }
 
+func writeValidationErrors(w http.ResponseWriter, validationErrs []events.ValidationError) {
+	response := validationErrorsResponse{Errors: make([]validationErrorResponse, 0, len(validationErrs))}
+	for _, err := range validationErrs {
+		response.Errors = append(response.Errors, validationErrorResponse{
+			DataType: err.DataType,
+			ID:       err.ID,
+			Error:    err.Err.Error(),
+		})
+	}
+
+	body, err := jsonutil.Marshal(response)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	w.Write(body)
+}
+
 func (api *eventsAPI) Invalidations() <-chan events.Invalidation {
 	return api.invalidations
 }
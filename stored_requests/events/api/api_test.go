@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +13,7 @@ import (
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_requests/caches/memory"
 	"github.com/prebid/prebid-server/v3/stored_requests/events"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGoodRequests(t *testing.T) {
@@ -119,6 +121,52 @@ func TestBadRequests(t *testing.T) {
 	}
 }
 
+func TestValidatedRequests(t *testing.T) {
+	cache := stored_requests.Cache{
+		Requests:  memory.NewCache(256*1024, -1, "Requests"),
+		Imps:      memory.NewCache(256*1024, -1, "Imps"),
+		Responses: memory.NewCache(256*1024, -1, "Responses"),
+		Accounts:  memory.NewCache(256*1024, -1, "Account"),
+	}
+	apiEvents, endpoint := NewValidatingEventsAPI(rejectingValidator{})
+
+	updateOccurred := make(chan struct{})
+	listener := events.NewEventListener(func() { updateOccurred <- struct{}{} }, nil)
+	go listener.Listen(cache, apiEvents)
+	defer listener.Stop()
+
+	request := newRequest("POST", `{"imps": {"valid": {"valid": true}, "invalid": {"valid": false}}}`)
+	recorder := httptest.NewRecorder()
+	handled := make(chan struct{})
+	go func() {
+		endpoint(recorder, request, nil)
+		close(handled)
+	}()
+	<-updateOccurred
+	<-handled
+
+	impData := cache.Imps.Get(context.Background(), []string{"valid", "invalid"})
+	assertMapLength(t, 1, impData)
+	assertHasValue(t, impData, "valid", `{"valid": true}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.JSONEq(t, `{"errors":[{"type":"Imp","id":"invalid","error":"rejected"}]}`, recorder.Body.String())
+}
+
+// rejectingValidator rejects the imps with the ID "invalid"
+type rejectingValidator struct{}
+
+func (rejectingValidator) Validate(save events.Save) (events.Save, []events.ValidationError) {
+	var errs []events.ValidationError
+	if _, ok := save.Imps["invalid"]; ok {
+		delete(save.Imps, "invalid")
+		errs = append(errs, events.ValidationError{DataType: "Imp", ID: "invalid", Err: errors.New("rejected")})
+	}
+	return save, errs
+}
+
+func (rejectingValidator) Invalidated(invalidation events.Invalidation) {}
+
 func newRequest(method string, body string) *http.Request {
 	return httptest.NewRequest(method, "/stored_requests", strings.NewReader(body))
 }
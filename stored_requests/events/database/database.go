@@ -40,6 +40,7 @@ type DatabaseEventProducerConfig struct {
 }
 
 type DatabaseEventProducer struct {
+	events.PollStatus
 	cfg           DatabaseEventProducerConfig
 	lastUpdate    time.Time
 	invalidations chan events.Invalidation
@@ -111,6 +112,7 @@ func (e *DatabaseEventProducer) fetchAll() (fetchErr error) {
 	}
 
 	e.lastUpdate = startTime
+	e.Polled(startTime)
 	return nil
 }
 
@@ -153,6 +155,7 @@ func (e *DatabaseEventProducer) fetchDelta() (fetchErr error) {
 	}
 
 	e.lastUpdate = startTime
+	e.Polled(startTime)
 	return nil
 }
 
@@ -135,6 +135,7 @@ func TestFetchAllSuccess(t *testing.T) {
 
 		assert.Nil(t, err, tt.description)
 		assert.Equal(t, tt.wantLastUpdate, eventProducer.lastUpdate, tt.description)
+		assert.Equal(t, tt.wantLastUpdate, eventProducer.LastPoll(), tt.description)
 
 		var saves events.Save
 		// Read data from saves channel with timeout to avoid test suite deadlock
@@ -226,6 +227,7 @@ func TestFetchAllErrors(t *testing.T) {
 
 		assert.NotNil(t, err, tt.description)
 		assert.Equal(t, tt.wantLastUpdate, eventProducer.lastUpdate, tt.description)
+		assert.True(t, eventProducer.LastPoll().IsZero(), tt.description)
 
 		var saves events.Save
 		// Read data from saves channel with timeout to avoid test suite deadlock
@@ -372,6 +374,7 @@ func TestFetchDeltaSuccess(t *testing.T) {
 
 		assert.Nil(t, err, tt.description)
 		assert.Equal(t, tt.wantLastUpdate, eventProducer.lastUpdate, tt.description)
+		assert.Equal(t, tt.wantLastUpdate, eventProducer.LastPoll(), tt.description)
 
 		var saves events.Save
 		// Read data from saves channel with timeout to avoid test suite deadlock
@@ -3,6 +3,9 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/stored_requests"
 )
@@ -13,6 +16,9 @@ type Save struct {
 	Imps      map[string]json.RawMessage `json:"imps"`
 	Accounts  map[string]json.RawMessage `json:"accounts"`
 	Responses map[string]json.RawMessage `json:"responses"`
+	// Validated is set by the EventProducers which validate the data themselves, so that listeners don't
+	// validate it again
+	Validated bool `json:"-"`
 }
 
 // Invalidation represents a bulk invalidation
@@ -29,12 +35,56 @@ type EventProducer interface {
 	Invalidations() <-chan Invalidation
 }
 
+// Poller is implemented by the event producers which poll a source of stored data
+type Poller interface {
+	// LastPoll returns the time of the last successful poll, or the zero time if the source was never polled successfully
+	LastPoll() time.Time
+}
+
+// PollStatus keeps the time of the last successful poll of an event producer. It can be embedded to implement Poller.
+type PollStatus struct {
+	lastPoll atomic.Int64
+}
+
+// Polled records a successful poll at the given time
+func (s *PollStatus) Polled(t time.Time) {
+	s.lastPoll.Store(t.UnixNano())
+}
+
+func (s *PollStatus) LastPoll() time.Time {
+	if lastPoll := s.lastPoll.Load(); lastPoll != 0 {
+		return time.Unix(0, lastPoll).UTC()
+	}
+	return time.Time{}
+}
+
+// ValidationError reports stored data which was kept out of the caches because it is invalid
+type ValidationError struct {
+	// DataType is one of "Request", "Imp", "Account" or "Response"
+	DataType string `json:"type"`
+	ID       string `json:"id"`
+	Err      error  `json:"-"`
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("Stored %s %s is invalid: %v", e.DataType, e.ID, e.Err)
+}
+
+// Validator checks the stored data of saves before it reaches the caches
+type Validator interface {
+	// Validate returns the save without the invalid data, along with the reasons it is invalid
+	Validate(save Save) (Save, []ValidationError)
+	// Invalidated tells the validator that data was invalidated, so that it can forget about it
+	Invalidated(invalidation Invalidation)
+}
+
 // EventListener provides information about how many events a listener has processed
 // and a mechanism to stop the listener goroutine
 type EventListener struct {
 	stop         chan struct{}
 	onSave       func()
 	onInvalidate func()
+	validator    Validator
 }
 
 // SimpleEventListener creates a new EventListener that solely propagates cache updates and invalidations
@@ -55,6 +105,18 @@ func NewEventListener(onSave func(), onInvalidate func()) *EventListener {
 	}
 }
 
+// NewValidatingEventListener creates a new EventListener that keeps the data rejected by the validator out of the cache,
+// and may perform additional work after propagating cache saves and invalidations. The cache keeps serving the previous
+// version of rejected data, if any. Saves which are already validated by their EventProducer are not validated again.
+func NewValidatingEventListener(validator Validator, onSave func(), onInvalidate func()) *EventListener {
+	return &EventListener{
+		stop:         make(chan struct{}),
+		onSave:       onSave,
+		onInvalidate: onInvalidate,
+		validator:    validator,
+	}
+}
+
 // Stop the event listener
 func (e *EventListener) Stop() {
 	e.stop <- struct{}{}
@@ -65,6 +127,9 @@ func (e *EventListener) Listen(cache stored_requests.Cache, events EventProducer
 	for {
 		select {
 		case save := <-events.Saves():
+			if e.validator != nil && !save.Validated {
+				save, _ = e.validator.Validate(save)
+			}
 			cache.Requests.Save(context.Background(), save.Requests)
 			cache.Imps.Save(context.Background(), save.Imps)
 			cache.Accounts.Save(context.Background(), save.Accounts)
@@ -77,6 +142,9 @@ func (e *EventListener) Listen(cache stored_requests.Cache, events EventProducer
 			cache.Imps.Invalidate(context.Background(), invalidation.Imps)
 			cache.Accounts.Invalidate(context.Background(), invalidation.Accounts)
 			cache.Responses.Invalidate(context.Background(), invalidation.Responses)
+			if e.validator != nil {
+				e.validator.Invalidated(invalidation)
+			}
 			if e.onInvalidate != nil {
 				e.onInvalidate()
 			}
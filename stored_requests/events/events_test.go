@@ -87,6 +87,67 @@ func TestListen(t *testing.T) {
 	}
 }
 
+func TestListenWithValidator(t *testing.T) {
+	ep := &fakeProducer{
+		saves:         make(chan Save),
+		invalidations: make(chan Invalidation),
+	}
+	cache := stored_requests.Cache{
+		Requests:  memory.NewCache(256*1024, -1, "Requests"),
+		Imps:      memory.NewCache(256*1024, -1, "Imps"),
+		Responses: memory.NewCache(256*1024, -1, "Responses"),
+		Accounts:  memory.NewCache(256*1024, -1, "Account"),
+	}
+	validator := &fakeValidator{}
+
+	saveOccurred := make(chan struct{})
+	invalidateOccurred := make(chan struct{})
+	listener := NewValidatingEventListener(
+		validator,
+		func() { saveOccurred <- struct{}{} },
+		func() { invalidateOccurred <- struct{}{} },
+	)
+
+	go listener.Listen(cache, ep)
+	defer listener.Stop()
+
+	ep.saves <- Save{Imps: map[string]json.RawMessage{
+		"valid":   json.RawMessage(`{"valid":true}`),
+		"invalid": json.RawMessage(`{"valid":false}`),
+	}}
+	<-saveOccurred
+
+	impData := cache.Imps.Get(context.Background(), []string{"valid", "invalid"})
+	if !reflect.DeepEqual(impData, map[string]json.RawMessage{"valid": json.RawMessage(`{"valid":true}`)}) {
+		t.Errorf("Invalid data must be kept out of the cache. Got %v", impData)
+	}
+
+	ep.invalidations <- Invalidation{Imps: []string{"invalid"}}
+	<-invalidateOccurred
+
+	if !reflect.DeepEqual(validator.invalidated, []Invalidation{{Imps: []string{"invalid"}}}) {
+		t.Errorf("The validator must be told about invalidations. Got %v", validator.invalidated)
+	}
+}
+
+// fakeValidator rejects the data with the ID "invalid"
+type fakeValidator struct {
+	invalidated []Invalidation
+}
+
+func (v *fakeValidator) Validate(save Save) (Save, []ValidationError) {
+	var errs []ValidationError
+	if _, ok := save.Imps["invalid"]; ok {
+		delete(save.Imps, "invalid")
+		errs = append(errs, ValidationError{DataType: "Imp", ID: "invalid", Err: fmt.Errorf("invalid")})
+	}
+	return save, errs
+}
+
+func (v *fakeValidator) Invalidated(invalidation Invalidation) {
+	v.invalidated = append(v.invalidated, invalidation)
+}
+
 type fakeProducer struct {
 	saves         chan Save
 	invalidations chan Invalidation
@@ -99,3 +160,30 @@ func (p *fakeProducer) Saves() <-chan Save {
 func (p *fakeProducer) Invalidations() <-chan Invalidation {
 	return p.invalidations
 }
+
+func TestListenWithValidatorSkipsValidatedSaves(t *testing.T) {
+	ep := &fakeProducer{
+		saves:         make(chan Save),
+		invalidations: make(chan Invalidation),
+	}
+	cache := stored_requests.Cache{
+		Requests:  memory.NewCache(256*1024, -1, "Requests"),
+		Imps:      memory.NewCache(256*1024, -1, "Imps"),
+		Responses: memory.NewCache(256*1024, -1, "Responses"),
+		Accounts:  memory.NewCache(256*1024, -1, "Account"),
+	}
+
+	saveOccurred := make(chan struct{})
+	listener := NewValidatingEventListener(&fakeValidator{}, func() { saveOccurred <- struct{}{} }, nil)
+
+	go listener.Listen(cache, ep)
+	defer listener.Stop()
+
+	ep.saves <- Save{Imps: map[string]json.RawMessage{"invalid": json.RawMessage(`{"valid":false}`)}, Validated: true}
+	<-saveOccurred
+
+	impData := cache.Imps.Get(context.Background(), []string{"invalid"})
+	if len(impData) != 1 {
+		t.Errorf("Data validated by the producer must not be validated again. Got %v", impData)
+	}
+}
@@ -74,30 +74,48 @@ func NewHTTPEvents(client *httpCore.Client, endpoint string, ctxProducer func()
 		lastUpdate:    time.Now().UTC(),
 		saves:         make(chan events.Save, 1),
 		invalidations: make(chan events.Invalidation, 1),
+		stop:          make(chan struct{}),
+		stopped:       make(chan struct{}),
 	}
 	logger.Infof("Loading HTTP cache from GET %s", endpoint)
 	e.fetchAll()
 
-	go e.refresh(time.Tick(refreshRate))
+	go func() {
+		defer close(e.stopped)
+		e.refresh(time.Tick(refreshRate))
+	}()
 	return e
 }
 
 type HTTPEvents struct {
+	events.PollStatus
 	client        *httpCore.Client
 	ctxProducer   func() (ctx context.Context, canceller func())
 	Endpoint      string
 	invalidations chan events.Invalidation
 	lastUpdate    time.Time
 	saves         chan events.Save
+	stop          chan struct{}
+	stopped       chan struct{}
+}
+
+// Stop stops the refreshes, and waits for the one in progress, if any. The events which aren't received yet are dropped.
+func (e *HTTPEvents) Stop() {
+	close(e.stop)
+	<-e.stopped
 }
 
 func (e *HTTPEvents) fetchAll() {
 	ctx, cancel := e.ctxProducer()
 	defer cancel()
 
+	thisTime := time.Now().UTC()
 	resp, err := ctxhttp.Get(ctx, e.client, e.Endpoint)
-	if respObj, ok := e.parse(e.Endpoint, resp, err); ok &&
-		(len(respObj.StoredRequests) > 0 || len(respObj.StoredImps) > 0 || len(respObj.StoredResponses) > 0 || len(respObj.Accounts) > 0) {
+	respObj, ok := e.parse(e.Endpoint, resp, err)
+	if !ok {
+		return
+	}
+	if len(respObj.StoredRequests) > 0 || len(respObj.StoredImps) > 0 || len(respObj.StoredResponses) > 0 || len(respObj.Accounts) > 0 {
 		e.saves <- events.Save{
 			Requests:  respObj.StoredRequests,
 			Imps:      respObj.StoredImps,
@@ -105,10 +123,21 @@ func (e *HTTPEvents) fetchAll() {
 			Accounts:  respObj.Accounts,
 		}
 	}
+	e.Polled(thisTime)
 }
 
 func (e *HTTPEvents) refresh(ticker <-chan time.Time) {
-	for thisTime := range ticker {
+	for {
+		var thisTime time.Time
+		select {
+		case tick, ok := <-ticker:
+			if !ok {
+				return
+			}
+			thisTime = tick
+		case <-e.stop:
+			return
+		}
 		thisTimeInUTC := thisTime.UTC()
 
 		// Parse the endpoint url defined
@@ -144,22 +173,40 @@ func (e *HTTPEvents) refresh(ticker <-chan time.Time) {
 				Accounts:  extractInvalidations(respObj.Accounts),
 			}
 			if len(respObj.StoredRequests) > 0 || len(respObj.StoredImps) > 0 || len(respObj.StoredResponses) > 0 || len(respObj.Accounts) > 0 {
-				e.saves <- events.Save{
+				save := events.Save{
 					Requests:  respObj.StoredRequests,
 					Imps:      respObj.StoredImps,
 					Responses: respObj.StoredResponses,
 					Accounts:  respObj.Accounts,
 				}
+				if !send(e.saves, save, e.stop) {
+					cancel()
+					return
+				}
 			}
 			if len(invalidations.Requests) > 0 || len(invalidations.Imps) > 0 || len(invalidations.Responses) > 0 || len(invalidations.Accounts) > 0 {
-				e.invalidations <- invalidations
+				if !send(e.invalidations, invalidations, e.stop) {
+					cancel()
+					return
+				}
 			}
 			e.lastUpdate = thisTimeInUTC
+			e.Polled(thisTimeInUTC)
 		}
 		cancel()
 	}
 }
 
+// send sends the event, unless the refreshes are stopped since its listener may be gone. It returns false if they are.
+func send[T any](ch chan<- T, event T, stop <-chan struct{}) bool {
+	select {
+	case ch <- event:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
 // parse unpacks the HTTP response and sends the relevant events to the channels.
 // It returns true if everything was successful, and false if any errors occurred.
 func (e *HTTPEvents) parse(endpoint string, resp *httpCore.Response, err error) (*responseContract, bool) {
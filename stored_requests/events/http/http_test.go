@@ -169,6 +169,41 @@ func TestStartup(t *testing.T) {
 	}
 }
 
+func TestLastPoll(t *testing.T) {
+	handler := &mockResponseHandler{statusCode: httpCore.StatusInternalServerError}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ev := NewHTTPEvents(server.Client(), server.URL, nil, -1)
+	assert.True(t, ev.LastPoll().IsZero(), "A failed poll must not be recorded")
+
+	handler.statusCode = httpCore.StatusOK
+	handler.response = `{}`
+	ev.fetchAll()
+	assert.False(t, ev.LastPoll().IsZero(), "A successful poll must be recorded")
+}
+
+func TestStop(t *testing.T) {
+	handler := &mockResponseHandler{statusCode: httpCore.StatusOK, response: `{"requests":{"request1":{"value":1}}}`}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// The save of the initial load fills the channel, so that the refreshes block until they're stopped
+	ev := NewHTTPEvents(server.Client(), server.URL, nil, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		ev.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop must end the refreshes blocked on their events")
+	}
+}
+
 type mockResponseHandler struct {
 	statusCode int
 	response   string
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/metrics"
 )
@@ -90,6 +92,31 @@ type CacheJSON interface {
 	Save(ctx context.Context, data map[string]json.RawMessage)
 }
 
+// NotFoundCacheJSON is implemented by caches which can also remember the IDs which are unknown to the backend,
+// so that repeated lookups of those IDs don't reach the backend. Saving data for an ID forgets that it was not found.
+type NotFoundCacheJSON interface {
+	// SaveNotFound remembers that the given IDs were not found by the backend
+	SaveNotFound(ctx context.Context, ids []string)
+	// GetNotFound returns the subset of the given IDs which are remembered as not found
+	GetNotFound(ctx context.Context, ids []string) []string
+}
+
+// StaleCacheJSON is implemented by caches which retain data past its TTL, so that it can still be served
+// while it is refreshed or while the backend is failing. Get never returns stale data.
+type StaleCacheJSON interface {
+	// GetStale returns the data of the given IDs which is past its TTL but still retained
+	GetStale(ctx context.Context, ids []string) map[string]StaleData
+}
+
+// StaleData is data held by a StaleCacheJSON past its TTL
+type StaleData struct {
+	Data json.RawMessage
+	// Revalidate is true if the data may be served while it is refreshed in the background
+	Revalidate bool
+	// IfError is true if the data may be served when the backend fails to return it
+	IfError bool
+}
+
 // ComposedCache creates an interface to treat a slice of caches as a single cache
 type ComposedCache []CacheJSON
 
@@ -149,79 +176,176 @@ type fetcherWithCache struct {
 	fetcher       AllFetcher
 	cache         Cache
 	metricsEngine metrics.MetricsEngine
+	lastFetched   *fetchTimes
+	// revalidating holds the IDs of the stale data being refreshed in the background
+	revalidating sync.Map
+}
+
+// revalidationTimeout bounds the background refreshes of stale data, which are detached from the requests serving it
+const revalidationTimeout = 10 * time.Second
+
+// maxFetchTimes bounds the number of fetch times kept for inspection by each generation of fetchTimes
+const maxFetchTimes = 10000
+
+// fetchTimes keeps track of the last time stored data was fetched from the backend, for inspection. It holds two
+// generations of at most maxFetchTimes entries: once the current one is full it replaces the previous one, so that
+// the times of data which is no longer fetched are eventually dropped.
+type fetchTimes struct {
+	lock     sync.RWMutex
+	current  map[storedDataKey]time.Time
+	previous map[storedDataKey]time.Time
+}
+
+func (t *fetchTimes) record(dataType string, data map[string]json.RawMessage) {
+	if len(data) == 0 {
+		return
+	}
+
+	now := time.Now()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for id := range data {
+		if len(t.current) >= maxFetchTimes {
+			t.previous = t.current
+			t.current = nil
+		}
+		if t.current == nil {
+			t.current = make(map[storedDataKey]time.Time)
+		}
+		t.current[storedDataKey{dataType, id}] = now
+	}
+}
+
+func (t *fetchTimes) get(dataType string, id string) *time.Time {
+	key := storedDataKey{dataType, id}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	if fetched, ok := t.current[key]; ok {
+		return &fetched
+	}
+	if fetched, ok := t.previous[key]; ok {
+		return &fetched
+	}
+	return nil
 }
 
 // WithCache returns a Fetcher which uses the given Caches before delegating to the original.
 // This can be called multiple times to compose Cache layers onto the backing Fetcher, though
 // it is usually more desirable to first compose caches with Compose, ensuring propagation of updates
 // and invalidations through all cache layers.
+//
+// Caches which implement NotFoundCacheJSON remember the IDs the backend doesn't know about. Caches which
+// implement StaleCacheJSON serve data past its TTL while it is refreshed in the background, or while the
+// backend fails to return it.
 func WithCache(fetcher AllFetcher, cache Cache, metricsEngine metrics.MetricsEngine) AllFetcher {
 	return &fetcherWithCache{
 		cache:         cache,
 		fetcher:       fetcher,
 		metricsEngine: metricsEngine,
+		lastFetched:   &fetchTimes{},
 	}
 }
 
 func (f *fetcherWithCache) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (requestData map[string]json.RawMessage, impData map[string]json.RawMessage, errs []error) {
+	requests := lookupCache(ctx, f.cache.Requests, "Request", requestIDs)
+	imps := lookupCache(ctx, f.cache.Imps, "Imp", impIDs)
 
-	requestData = f.cache.Requests.Get(ctx, requestIDs)
-	impData = f.cache.Imps.Get(ctx, impIDs)
-
-	// Fixes #311
-	leftoverImps := findLeftovers(impIDs, impData)
-	leftoverReqs := findLeftovers(requestIDs, requestData)
-
-	// Record cache hits for stored requests and stored imps
-	f.metricsEngine.RecordStoredReqCacheResult(metrics.CacheHit, len(requestIDs)-len(leftoverReqs))
-	f.metricsEngine.RecordStoredImpCacheResult(metrics.CacheHit, len(impIDs)-len(leftoverImps))
-	// Record cache misses for stored requests and stored imps
-	f.metricsEngine.RecordStoredReqCacheResult(metrics.CacheMiss, len(leftoverReqs))
-	f.metricsEngine.RecordStoredImpCacheResult(metrics.CacheMiss, len(leftoverImps))
+	requests.record(f.metricsEngine.RecordStoredReqCacheResult)
+	imps.record(f.metricsEngine.RecordStoredImpCacheResult)
 
-	if len(leftoverReqs) > 0 || len(leftoverImps) > 0 {
-		fetcherReqData, fetcherImpData, fetcherErrs := f.fetcher.FetchRequests(ctx, leftoverReqs, leftoverImps)
-		errs = fetcherErrs
+	if len(requests.leftovers) > 0 || len(imps.leftovers) > 0 {
+		fetcherReqData, fetcherImpData, fetcherErrs := f.fetcher.FetchRequests(ctx, requests.leftovers, imps.leftovers)
 
 		f.cache.Requests.Save(ctx, fetcherReqData)
 		f.cache.Imps.Save(ctx, fetcherImpData)
+		f.lastFetched.record("Request", fetcherReqData)
+		f.lastFetched.record("Imp", fetcherImpData)
 
-		requestData = mergeData(requestData, fetcherReqData)
-		impData = mergeData(impData, fetcherImpData)
-	}
+		_, notFound := splitNotFound(fetcherErrs)
+		staleReqs := requests.resolve(ctx, f.cache.Requests, fetcherReqData, notFound)
+		staleImps := imps.resolve(ctx, f.cache.Imps, fetcherImpData, notFound)
+		recordPositiveCacheResult(f.metricsEngine.RecordStoredReqCacheResult, metrics.CacheStaleOnError, len(staleReqs))
+		recordPositiveCacheResult(f.metricsEngine.RecordStoredImpCacheResult, metrics.CacheStaleOnError, len(staleImps))
 
-	return
+		errs = filterServedErrors(fetcherErrs, append(staleReqs, staleImps...), requests.resolved() && imps.resolved())
+	}
+	errs = requests.appendNotFoundErrors(errs)
+	errs = imps.appendNotFoundErrors(errs)
+
+	f.revalidate("Request", requests.revalidate, func(ctx context.Context, ids []string) {
+		data, _, errs := f.fetcher.FetchRequests(ctx, ids, nil)
+		f.cache.Requests.Save(ctx, data)
+		f.lastFetched.record("Request", data)
+		evictNotFound(ctx, f.cache.Requests, "Request", errs)
+	})
+	f.revalidate("Imp", imps.revalidate, func(ctx context.Context, ids []string) {
+		_, data, errs := f.fetcher.FetchRequests(ctx, nil, ids)
+		f.cache.Imps.Save(ctx, data)
+		f.lastFetched.record("Imp", data)
+		evictNotFound(ctx, f.cache.Imps, "Imp", errs)
+	})
+
+	return requests.data, imps.data, errs
 }
 
 func (f *fetcherWithCache) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
-	data = f.cache.Responses.Get(ctx, ids)
+	responses := lookupCache(ctx, f.cache.Responses, "Response", ids)
 
-	leftoverResp := findLeftovers(ids, data)
-
-	if len(leftoverResp) > 0 {
-		fetcherRespData, fetcherErrs := f.fetcher.FetchResponses(ctx, leftoverResp)
-		errs = fetcherErrs
+	if len(responses.leftovers) > 0 {
+		fetcherRespData, fetcherErrs := f.fetcher.FetchResponses(ctx, responses.leftovers)
 
 		f.cache.Responses.Save(ctx, fetcherRespData)
 
-		data = mergeData(data, fetcherRespData)
+		_, notFound := splitNotFound(fetcherErrs)
+		staleResps := responses.resolve(ctx, f.cache.Responses, fetcherRespData, notFound)
+		errs = filterServedErrors(fetcherErrs, staleResps, responses.resolved())
 	}
+	errs = responses.appendNotFoundErrors(errs)
 
-	return
+	f.revalidate("Response", responses.revalidate, func(ctx context.Context, ids []string) {
+		data, errs := f.fetcher.FetchResponses(ctx, ids)
+		f.cache.Responses.Save(ctx, data)
+		evictNotFound(ctx, f.cache.Responses, "Response", errs)
+	})
+
+	return responses.data, errs
 }
 
 func (f *fetcherWithCache) FetchAccount(ctx context.Context, acccountDefaultJSON json.RawMessage, accountID string) (account json.RawMessage, errs []error) {
-	accountData := f.cache.Accounts.Get(ctx, []string{accountID})
-	// TODO: add metrics
-	if account, ok := accountData[accountID]; ok {
+	accounts := lookupCache(ctx, f.cache.Accounts, "Account", []string{accountID})
+
+	switch {
+	case len(accounts.notFound) > 0:
+		f.metricsEngine.RecordAccountCacheResult(metrics.CacheNotFoundHit, 1)
+		return nil, accounts.appendNotFoundErrors(nil)
+	case len(accounts.revalidate) > 0:
+		f.metricsEngine.RecordAccountCacheResult(metrics.CacheStaleHit, 1)
+		f.revalidate("Account", accounts.revalidate, func(ctx context.Context, ids []string) {
+			account, errs := f.fetcher.FetchAccount(ctx, acccountDefaultJSON, accountID)
+			if len(errs) == 0 {
+				f.cache.Accounts.Save(ctx, map[string]json.RawMessage{accountID: account})
+				f.lastFetched.record("Account", map[string]json.RawMessage{accountID: account})
+			}
+			evictNotFound(ctx, f.cache.Accounts, "Account", errs)
+		})
+		return accounts.data[accountID], nil
+	case len(accounts.leftovers) == 0:
 		f.metricsEngine.RecordAccountCacheResult(metrics.CacheHit, 1)
-		return account, errs
-	} else {
-		f.metricsEngine.RecordAccountCacheResult(metrics.CacheMiss, 1)
+		return accounts.data[accountID], nil
 	}
+
+	f.metricsEngine.RecordAccountCacheResult(metrics.CacheMiss, 1)
 	account, errs = f.fetcher.FetchAccount(ctx, acccountDefaultJSON, accountID)
 	if len(errs) == 0 {
 		f.cache.Accounts.Save(ctx, map[string]json.RawMessage{accountID: account})
+		f.lastFetched.record("Account", map[string]json.RawMessage{accountID: account})
+		return account, errs
+	}
+
+	_, notFound := splitNotFound(errs)
+	if staleAccounts := accounts.resolve(ctx, f.cache.Accounts, nil, notFound); len(staleAccounts) > 0 {
+		f.metricsEngine.RecordAccountCacheResult(metrics.CacheStaleOnError, 1)
+		return accounts.data[accountID], nil
 	}
 	return account, errs
 }
@@ -230,25 +354,198 @@ func (f *fetcherWithCache) FetchCategories(ctx context.Context, primaryAdServer,
 	return "", nil
 }
 
-func findLeftovers(ids []string, data map[string]json.RawMessage) (leftovers []string) {
-	leftovers = make([]string, 0, len(ids)-len(data))
+// revalidate refreshes the stale data of the given IDs in the background, unless a refresh is already in flight
+func (f *fetcherWithCache) revalidate(dataType string, ids []string, refresh func(ctx context.Context, ids []string)) {
+	var refreshIDs []string
 	for _, id := range ids {
-		if _, ok := data[id]; !ok {
-			leftovers = append(leftovers, id)
+		if _, inFlight := f.revalidating.LoadOrStore(storedDataKey{dataType, id}, struct{}{}); !inFlight {
+			refreshIDs = append(refreshIDs, id)
 		}
 	}
-	return
+	if len(refreshIDs) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), revalidationTimeout)
+		defer cancel()
+		refresh(ctx, refreshIDs)
+		for _, id := range refreshIDs {
+			f.revalidating.Delete(storedDataKey{dataType, id})
+		}
+	}()
+}
+
+// cacheLookup is the outcome of looking up IDs in a cache, before the leftovers are fetched from the backend
+type cacheLookup struct {
+	dataType string
+	data     map[string]json.RawMessage
+	hits     int
+	// leftovers are the IDs which must be fetched from the backend
+	leftovers []string
+	// notFound are the IDs which the cache remembers as unknown to the backend
+	notFound []string
+	// revalidate are the IDs served from stale data, which must be refreshed in the background
+	revalidate []string
+	// staleIfError is the stale data which may be served if the backend fails to return it
+	staleIfError map[string]json.RawMessage
+	// unresolved is the number of leftovers which the backend and stale data could not provide
+	unresolved int
+}
+
+func lookupCache(ctx context.Context, cache CacheJSON, dataType string, ids []string) *cacheLookup {
+	lookup := &cacheLookup{
+		dataType: dataType,
+		data:     cache.Get(ctx, ids),
+	}
+	if lookup.data == nil {
+		lookup.data = make(map[string]json.RawMessage, len(ids))
+	}
+
+	// Fixes #311
+	lookup.leftovers = findLeftovers(ids, lookup.data)
+	lookup.hits = len(ids) - len(lookup.leftovers)
+
+	if notFoundCache, ok := cache.(NotFoundCacheJSON); ok && len(lookup.leftovers) > 0 {
+		lookup.notFound = notFoundCache.GetNotFound(ctx, lookup.leftovers)
+		lookup.leftovers = removeIDs(lookup.leftovers, lookup.notFound)
+	}
+
+	if staleCache, ok := cache.(StaleCacheJSON); ok && len(lookup.leftovers) > 0 {
+		staleData := staleCache.GetStale(ctx, lookup.leftovers)
+		leftovers := make([]string, 0, len(lookup.leftovers))
+		for _, id := range lookup.leftovers {
+			stale, ok := staleData[id]
+			switch {
+			case ok && stale.Revalidate:
+				lookup.data[id] = stale.Data
+				lookup.revalidate = append(lookup.revalidate, id)
+			case ok && stale.IfError:
+				if lookup.staleIfError == nil {
+					lookup.staleIfError = make(map[string]json.RawMessage)
+				}
+				lookup.staleIfError[id] = stale.Data
+				leftovers = append(leftovers, id)
+			default:
+				leftovers = append(leftovers, id)
+			}
+		}
+		lookup.leftovers = leftovers
+	}
+
+	return lookup
+}
+
+// record records the cache results of the lookup. Cache hits and misses are always recorded.
+func (l *cacheLookup) record(recordCacheResult func(metrics.CacheResult, int)) {
+	recordCacheResult(metrics.CacheHit, l.hits)
+	recordCacheResult(metrics.CacheMiss, len(l.leftovers))
+	recordPositiveCacheResult(recordCacheResult, metrics.CacheNotFoundHit, len(l.notFound))
+	recordPositiveCacheResult(recordCacheResult, metrics.CacheStaleHit, len(l.revalidate))
 }
 
-func mergeData(cachedData map[string]json.RawMessage, fetchedData map[string]json.RawMessage) (mergedData map[string]json.RawMessage) {
-	mergedData = cachedData
-	if mergedData == nil {
-		mergedData = fetchedData
-	} else {
-		for key, value := range fetchedData {
-			mergedData[key] = value
+// resolve completes the lookup with the data fetched from the backend. The leftovers which the backend doesn't know
+// about are evicted from the cache and remembered as not found, since the backend is authoritative about them. The
+// other leftovers which the backend didn't return, because it failed, are served from stale data when allowed.
+// It returns the keys of the data served stale.
+func (l *cacheLookup) resolve(ctx context.Context, cache CacheJSON, fetched map[string]json.RawMessage, notFound map[storedDataKey]struct{}) (stale []storedDataKey) {
+	var newNotFound []string
+	for _, id := range l.leftovers {
+		_, isNotFound := notFound[storedDataKey{l.dataType, id}]
+		if data, ok := fetched[id]; ok {
+			l.data[id] = data
+		} else if data, ok := l.staleIfError[id]; ok && !isNotFound {
+			l.data[id] = data
+			stale = append(stale, storedDataKey{l.dataType, id})
+		} else {
+			l.unresolved++
+			if isNotFound {
+				newNotFound = append(newNotFound, id)
+			}
 		}
 	}
 
+	evict(ctx, cache, newNotFound)
+	return stale
+}
+
+// resolved returns true if all the IDs of the lookup have data
+func (l *cacheLookup) resolved() bool {
+	return l.unresolved == 0 && len(l.notFound) == 0
+}
+
+// appendNotFoundErrors appends the errors for the IDs which the cache remembers as not found
+func (l *cacheLookup) appendNotFoundErrors(errs []error) []error {
+	for _, id := range l.notFound {
+		errs = append(errs, NotFoundError{ID: id, DataType: l.dataType})
+	}
+	return errs
+}
+
+// filterServedErrors drops the errors of the backend if some IDs were served from stale data and every ID was served,
+// since the backend failures they report didn't prevent serving the data. The IDs which the backend doesn't know about
+// are never served stale, so their NotFoundErrors are always kept.
+func filterServedErrors(errs []error, served []storedDataKey, allResolved bool) []error {
+	if len(served) == 0 || !allResolved {
+		return errs
+	}
+	return nil
+}
+
+// evictNotFound evicts the IDs of the NotFoundErrors of the given data type from the cache
+func evictNotFound(ctx context.Context, cache CacheJSON, dataType string, errs []error) {
+	_, notFound := splitNotFound(errs)
+	var ids []string
+	for key := range notFound {
+		if key.dataType == dataType {
+			ids = append(ids, key.id)
+		}
+	}
+	evict(ctx, cache, ids)
+}
+
+// evict removes the data of the IDs which the backend doesn't know about from the cache, since they were deleted,
+// and remembers them as not found if the cache supports it
+func evict(ctx context.Context, cache CacheJSON, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	cache.Invalidate(ctx, ids)
+	if notFoundCache, ok := cache.(NotFoundCacheJSON); ok {
+		notFoundCache.SaveNotFound(ctx, ids)
+	}
+}
+
+// recordPositiveCacheResult records the cache result only if it happened, unlike hits and misses which are always recorded
+func recordPositiveCacheResult(recordCacheResult func(metrics.CacheResult, int), result metrics.CacheResult, count int) {
+	if count > 0 {
+		recordCacheResult(result, count)
+	}
+}
+
+func removeIDs(ids []string, remove []string) []string {
+	if len(remove) == 0 {
+		return ids
+	}
+	removeSet := make(map[string]struct{}, len(remove))
+	for _, id := range remove {
+		removeSet[id] = struct{}{}
+	}
+	remaining := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := removeSet[id]; !ok {
+			remaining = append(remaining, id)
+		}
+	}
+	return remaining
+}
+
+func findLeftovers(ids []string, data map[string]json.RawMessage) (leftovers []string) {
+	leftovers = make([]string, 0, len(ids)-len(data))
+	for _, id := range ids {
+		if _, ok := data[id]; !ok {
+			leftovers = append(leftovers, id)
+		}
+	}
 	return
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/stored_requests/caches/nil_cache"
@@ -333,6 +335,205 @@ func TestComposedCache(t *testing.T) {
 	assert.JSONEq(t, `{"id": "3"}`, string(respData["3"]), "FetchResponses should fetch the right resp data")
 }
 
+func TestNotFoundCache(t *testing.T) {
+	impCache := newStaleMapCache()
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, impCache, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine)
+	ctx := context.Background()
+
+	fetcher.On("FetchRequests", ctx, []string{}, []string{"missing"}).Return(
+		map[string]json.RawMessage{},
+		map[string]json.RawMessage{},
+		[]error{NotFoundError{ID: "missing", DataType: "Imp"}},
+	).Once()
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheMiss, 0)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheMiss, 1).Once()
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheMiss, 0).Once()
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheNotFoundHit, 1).Once()
+
+	_, _, errs := aFetcherWithCache.FetchRequests(ctx, nil, []string{"missing"})
+	assert.Equal(t, []error{NotFoundError{ID: "missing", DataType: "Imp"}}, errs)
+
+	_, impData, errs := aFetcherWithCache.FetchRequests(ctx, nil, []string{"missing"})
+	assert.Empty(t, impData)
+	assert.Equal(t, []error{NotFoundError{ID: "missing", DataType: "Imp"}}, errs, "unknown IDs must still be reported")
+
+	fetcher.AssertExpectations(t)
+	metricsEngine.AssertExpectations(t)
+}
+
+func TestNotFoundCacheIgnoresOtherErrors(t *testing.T) {
+	respCache := newStaleMapCache()
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, respCache, &nil_cache.NilCache{}}, &metrics.MetricsEngineMock{})
+	ctx := context.Background()
+
+	fetcher.On("FetchResponses", ctx, []string{"resp"}).Return(map[string]json.RawMessage{}, []error{errors.New("timeout")}).Twice()
+
+	for i := 0; i < 2; i++ {
+		_, errs := aFetcherWithCache.FetchResponses(ctx, []string{"resp"})
+		assert.Equal(t, []error{errors.New("timeout")}, errs)
+	}
+	fetcher.AssertExpectations(t)
+	assert.Empty(t, respCache.GetNotFound(ctx, []string{"resp"}), "only IDs unknown to the backend may be remembered")
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	reqCache := newStaleMapCache()
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{reqCache, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine)
+	ctx := context.Background()
+
+	reqCache.stale["req"] = StaleData{Data: json.RawMessage(`{"old":true}`), Revalidate: true, IfError: true}
+	fetcher.On("FetchRequests", mock.Anything, []string{"req"}, []string(nil)).Return(
+		map[string]json.RawMessage{"req": json.RawMessage(`{"new":true}`)},
+		map[string]json.RawMessage{},
+		[]error{},
+	).Once()
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheMiss, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheStaleHit, 1)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheMiss, 0)
+
+	reqData, _, errs := aFetcherWithCache.FetchRequests(ctx, []string{"req"}, nil)
+
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"old":true}`, string(reqData["req"]), "stale data must be served while it is revalidated")
+	assert.Eventually(t, func() bool {
+		return string(reqCache.Get(ctx, []string{"req"})["req"]) == `{"new":true}`
+	}, time.Second, 10*time.Millisecond, "stale data must be refreshed in the background")
+	fetcher.AssertExpectations(t)
+	metricsEngine.AssertExpectations(t)
+}
+
+func TestStaleWhileRevalidateNotFound(t *testing.T) {
+	reqCache := newStaleMapCache()
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{reqCache, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine)
+	ctx := context.Background()
+
+	reqCache.stale["req"] = StaleData{Data: json.RawMessage(`{"old":true}`), Revalidate: true, IfError: true}
+	fetcher.On("FetchRequests", mock.Anything, []string{"req"}, []string(nil)).Return(
+		map[string]json.RawMessage{},
+		map[string]json.RawMessage{},
+		[]error{NotFoundError{ID: "req", DataType: "Request"}},
+	).Once()
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheMiss, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheStaleHit, 1)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheMiss, 0)
+
+	aFetcherWithCache.FetchRequests(ctx, []string{"req"}, nil)
+
+	assert.Eventually(t, func() bool {
+		return len(reqCache.GetNotFound(ctx, []string{"req"})) == 1
+	}, time.Second, 10*time.Millisecond, "data deleted from the backend must be remembered as not found")
+	assert.Empty(t, reqCache.GetStale(ctx, []string{"req"}), "data deleted from the backend must be evicted")
+	fetcher.AssertExpectations(t)
+}
+
+func TestStaleIfError(t *testing.T) {
+	impCache := newStaleMapCache()
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, impCache, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine)
+	ctx := context.Background()
+
+	impCache.stale["stale"] = StaleData{Data: json.RawMessage(`{"old":true}`), IfError: true}
+	impCache.stale["deleted"] = StaleData{Data: json.RawMessage(`{"old":true}`), IfError: true}
+	fetcher.On("FetchRequests", ctx, []string{}, []string{"stale", "deleted", "missing"}).Return(
+		map[string]json.RawMessage{},
+		map[string]json.RawMessage{},
+		[]error{NotFoundError{ID: "deleted", DataType: "Imp"}, NotFoundError{ID: "missing", DataType: "Imp"}, errors.New("db down")},
+	)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheMiss, 0)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheMiss, 3)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheStaleOnError, 1)
+
+	_, impData, errs := aFetcherWithCache.FetchRequests(ctx, nil, []string{"stale", "deleted", "missing"})
+
+	assert.JSONEq(t, `{"old":true}`, string(impData["stale"]), "stale data must be served if the backend fails")
+	assert.NotContains(t, impData, "deleted", "stale data must not be served if the backend doesn't know about it")
+	assert.Equal(t, []error{NotFoundError{ID: "deleted", DataType: "Imp"}, NotFoundError{ID: "missing", DataType: "Imp"}, errors.New("db down")}, errs)
+	assert.NotContains(t, impCache.GetStale(ctx, []string{"stale", "deleted"}), "deleted", "data unknown to the backend must be evicted")
+	assert.Equal(t, []string{"deleted", "missing"}, impCache.GetNotFound(ctx, []string{"stale", "deleted", "missing"}))
+	metricsEngine.AssertExpectations(t)
+}
+
+func TestStaleIfErrorAllServed(t *testing.T) {
+	impCache := newStaleMapCache()
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, impCache, &nil_cache.NilCache{}, &nil_cache.NilCache{}}, metricsEngine)
+	ctx := context.Background()
+
+	impCache.stale["stale"] = StaleData{Data: json.RawMessage(`{"old":true}`), IfError: true}
+	fetcher.On("FetchRequests", ctx, []string{}, []string{"stale"}).Return(
+		map[string]json.RawMessage{},
+		map[string]json.RawMessage{},
+		[]error{errors.New("db down")},
+	)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredReqCacheResult", metrics.CacheMiss, 0)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheHit, 0)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheMiss, 1)
+	metricsEngine.On("RecordStoredImpCacheResult", metrics.CacheStaleOnError, 1)
+
+	_, impData, errs := aFetcherWithCache.FetchRequests(ctx, nil, []string{"stale"})
+
+	assert.JSONEq(t, `{"old":true}`, string(impData["stale"]))
+	assert.Empty(t, errs, "backend failures must be dropped once every ID is served")
+	metricsEngine.AssertExpectations(t)
+}
+
+func TestAccountStaleIfError(t *testing.T) {
+	accCache := newStaleMapCache()
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, accCache}, metricsEngine)
+	ctx := context.Background()
+
+	accCache.stale["account"] = StaleData{Data: json.RawMessage(`{"old":true}`), IfError: true}
+	fetcher.On("FetchAccount", ctx, json.RawMessage(`{}`), "account").Return(json.RawMessage(nil), []error{errors.New("timeout")})
+	metricsEngine.On("RecordAccountCacheResult", metrics.CacheMiss, 1)
+	metricsEngine.On("RecordAccountCacheResult", metrics.CacheStaleOnError, 1)
+
+	account, errs := aFetcherWithCache.FetchAccount(ctx, json.RawMessage(`{}`), "account")
+
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"old":true}`, string(account))
+	metricsEngine.AssertExpectations(t)
+}
+
+func TestAccountStaleIfErrorNotFound(t *testing.T) {
+	accCache := newStaleMapCache()
+	metricsEngine := &metrics.MetricsEngineMock{}
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, accCache}, metricsEngine)
+	ctx := context.Background()
+
+	accCache.stale["account"] = StaleData{Data: json.RawMessage(`{"old":true}`), IfError: true}
+	fetcher.On("FetchAccount", ctx, json.RawMessage(`{}`), "account").Return(json.RawMessage(nil), []error{NotFoundError{ID: "account", DataType: "Account"}})
+	metricsEngine.On("RecordAccountCacheResult", metrics.CacheMiss, 1)
+
+	account, errs := aFetcherWithCache.FetchAccount(ctx, json.RawMessage(`{}`), "account")
+
+	assert.Nil(t, account, "a deleted account must not be served stale")
+	assert.Equal(t, []error{NotFoundError{ID: "account", DataType: "Account"}}, errs)
+	assert.Empty(t, accCache.GetStale(ctx, []string{"account"}), "a deleted account must be evicted")
+	assert.Equal(t, []string{"account"}, accCache.GetNotFound(ctx, []string{"account"}))
+	metricsEngine.AssertExpectations(t)
+}
+
 type mockFetcher struct {
 	mock.Mock
 }
@@ -372,3 +573,83 @@ func (c *mockCache) Save(ctx context.Context, data map[string]json.RawMessage) {
 func (c *mockCache) Invalidate(ctx context.Context, ids []string) {
 	c.Called(ctx, ids)
 }
+
+// staleMapCache is a cache which supports negative caching and serving stale data
+type staleMapCache struct {
+	lock     sync.Mutex
+	data     map[string]json.RawMessage
+	stale    map[string]StaleData
+	notFound map[string]struct{}
+}
+
+func newStaleMapCache() *staleMapCache {
+	return &staleMapCache{
+		data:     make(map[string]json.RawMessage),
+		stale:    make(map[string]StaleData),
+		notFound: make(map[string]struct{}),
+	}
+}
+
+func (c *staleMapCache) Get(ctx context.Context, ids []string) map[string]json.RawMessage {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	data := make(map[string]json.RawMessage, len(ids))
+	for _, id := range ids {
+		if value, ok := c.data[id]; ok {
+			data[id] = value
+		}
+	}
+	return data
+}
+
+func (c *staleMapCache) GetStale(ctx context.Context, ids []string) map[string]StaleData {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	data := make(map[string]StaleData, len(ids))
+	for _, id := range ids {
+		if value, ok := c.stale[id]; ok {
+			data[id] = value
+		}
+	}
+	return data
+}
+
+func (c *staleMapCache) GetNotFound(ctx context.Context, ids []string) []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var notFound []string
+	for _, id := range ids {
+		if _, ok := c.notFound[id]; ok {
+			notFound = append(notFound, id)
+		}
+	}
+	return notFound
+}
+
+func (c *staleMapCache) Save(ctx context.Context, data map[string]json.RawMessage) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for id, value := range data {
+		c.data[id] = value
+		delete(c.stale, id)
+		delete(c.notFound, id)
+	}
+}
+
+func (c *staleMapCache) SaveNotFound(ctx context.Context, ids []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, id := range ids {
+		c.notFound[id] = struct{}{}
+	}
+}
+
+func (c *staleMapCache) Invalidate(ctx context.Context, ids []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, id := range ids {
+		delete(c.data, id)
+		delete(c.stale, id)
+		delete(c.notFound, id)
+	}
+}
@@ -0,0 +1,161 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/metrics"
+)
+
+// CacheNone is the cache result reported by an Inspection when the data is not served through a cache
+const CacheNone metrics.CacheResult = "none"
+
+// Inspection describes how a Fetcher resolves a single Stored Request, Imp or Account, for debugging.
+type Inspection struct {
+	ID       string `json:"id"`
+	DataType string `json:"type"`
+	// Cache reports whether the data is currently served from the cache
+	Cache metrics.CacheResult `json:"cache"`
+	// LastFetched is the last time the data was fetched from the backend and saved in the cache
+	LastFetched *time.Time `json:"last_fetched,omitempty"`
+	// Cached is the data currently held in the cache
+	Cached json.RawMessage `json:"cached,omitempty"`
+	// Backend is the data currently returned by the backend, without any account defaults applied
+	Backend json.RawMessage `json:"backend,omitempty"`
+	// NotFound is true if the backend does not know about the ID
+	NotFound bool `json:"not_found,omitempty"`
+}
+
+// Inspector is implemented by Fetchers which can describe how they resolve stored data.
+//
+// Inspecting must not change the state of the Fetcher. In particular, data fetched from the backend
+// is not saved in the cache.
+type Inspector interface {
+	// InspectRequests describes the resolution of the given Stored Request and Imp IDs. The returned errors are
+	// the ones which can not be attributed to a single ID.
+	InspectRequests(ctx context.Context, requestIDs []string, impIDs []string) ([]Inspection, []error)
+	// InspectAccount describes the resolution of the given account ID
+	InspectAccount(ctx context.Context, accountID string) (Inspection, []error)
+}
+
+// InspectRequests describes the resolution of the given Stored Request and Imp IDs by the fetcher.
+// Fetchers which don't implement Inspector are reported as not cached.
+func InspectRequests(ctx context.Context, fetcher Fetcher, requestIDs []string, impIDs []string) ([]Inspection, []error) {
+	if inspector, ok := fetcher.(Inspector); ok {
+		return inspector.InspectRequests(ctx, requestIDs, impIDs)
+	}
+
+	requestData, impData, errs := fetcher.FetchRequests(ctx, requestIDs, impIDs)
+	errs, notFound := splitNotFound(errs)
+
+	inspections := make([]Inspection, 0, len(requestIDs)+len(impIDs))
+	inspections = appendInspections(inspections, "Request", requestIDs, nil, requestData, notFound, nil)
+	inspections = appendInspections(inspections, "Imp", impIDs, nil, impData, notFound, nil)
+	return inspections, errs
+}
+
+// InspectAccount describes the resolution of the given account ID by the fetcher.
+// Fetchers which don't implement Inspector are reported as not cached.
+func InspectAccount(ctx context.Context, fetcher AccountFetcher, accountID string) (Inspection, []error) {
+	if inspector, ok := fetcher.(Inspector); ok {
+		return inspector.InspectAccount(ctx, accountID)
+	}
+
+	account, errs := fetcher.FetchAccount(ctx, nil, accountID)
+	errs, notFound := splitNotFound(errs)
+
+	inspections := appendInspections(nil, "Account", []string{accountID}, nil, map[string]json.RawMessage{accountID: account}, notFound, nil)
+	return inspections[0], errs
+}
+
+// Uncached returns a fetcher which fetches accounts like the given one, but without going through its caches, so
+// that the accounts it returns are current and fetching them doesn't change the state of the caches nor record
+// cache metrics.
+func Uncached(fetcher AccountFetcher) AccountFetcher {
+	switch f := fetcher.(type) {
+	case *fetcherWithCache:
+		return Uncached(f.fetcher)
+	case *AccountHierarchyFetcher:
+		if uncached, ok := Uncached(f.AllFetcher).(AllFetcher); ok {
+			return WithAccountHierarchy(uncached, ComposedCache{}, f.maxDepth)
+		}
+	}
+	return fetcher
+}
+
+func (f *fetcherWithCache) InspectRequests(ctx context.Context, requestIDs []string, impIDs []string) ([]Inspection, []error) {
+	cachedRequests := f.cache.Requests.Get(ctx, requestIDs)
+	cachedImps := f.cache.Imps.Get(ctx, impIDs)
+
+	requestData, impData, errs := f.fetcher.FetchRequests(ctx, requestIDs, impIDs)
+	errs, notFound := splitNotFound(errs)
+
+	inspections := make([]Inspection, 0, len(requestIDs)+len(impIDs))
+	inspections = appendInspections(inspections, "Request", requestIDs, cachedRequests, requestData, notFound, f.lastFetched)
+	inspections = appendInspections(inspections, "Imp", impIDs, cachedImps, impData, notFound, f.lastFetched)
+	return inspections, errs
+}
+
+func (f *fetcherWithCache) InspectAccount(ctx context.Context, accountID string) (Inspection, []error) {
+	cachedAccounts := f.cache.Accounts.Get(ctx, []string{accountID})
+
+	account, errs := f.fetcher.FetchAccount(ctx, nil, accountID)
+	errs, notFound := splitNotFound(errs)
+
+	inspections := appendInspections(nil, "Account", []string{accountID}, cachedAccounts, map[string]json.RawMessage{accountID: account}, notFound, f.lastFetched)
+	return inspections[0], errs
+}
+
+// InspectRequests describes the resolution of stored requests by the wrapped fetcher
+func (f *AccountHierarchyFetcher) InspectRequests(ctx context.Context, requestIDs []string, impIDs []string) ([]Inspection, []error) {
+	return InspectRequests(ctx, f.AllFetcher, requestIDs, impIDs)
+}
+
+// InspectAccount describes the resolution of the account itself by the wrapped fetcher. Its parents
+// can be inspected separately.
+func (f *AccountHierarchyFetcher) InspectAccount(ctx context.Context, accountID string) (Inspection, []error) {
+	return InspectAccount(ctx, f.AllFetcher, accountID)
+}
+
+// storedDataKey identifies stored data across data types, which may share IDs
+type storedDataKey struct {
+	dataType string
+	id       string
+}
+
+// splitNotFound separates the NotFoundErrors, which can be attributed to a single ID, from the other errors
+func splitNotFound(errs []error) ([]error, map[storedDataKey]struct{}) {
+	var otherErrs []error
+	notFound := make(map[storedDataKey]struct{})
+	for _, err := range errs {
+		if nf, ok := err.(NotFoundError); ok {
+			notFound[storedDataKey{nf.DataType, nf.ID}] = struct{}{}
+		} else {
+			otherErrs = append(otherErrs, err)
+		}
+	}
+	return otherErrs, notFound
+}
+
+func appendInspections(inspections []Inspection, dataType string, ids []string, cached, backend map[string]json.RawMessage, notFound map[storedDataKey]struct{}, lastFetched *fetchTimes) []Inspection {
+	for _, id := range ids {
+		inspection := Inspection{
+			ID:       id,
+			DataType: dataType,
+			Cache:    CacheNone,
+			Backend:  backend[id],
+		}
+		_, inspection.NotFound = notFound[storedDataKey{dataType, id}]
+		if lastFetched != nil {
+			inspection.Cache = metrics.CacheMiss
+			if data, ok := cached[id]; ok {
+				inspection.Cache = metrics.CacheHit
+				inspection.Cached = data
+			}
+			inspection.LastFetched = lastFetched.get(dataType, id)
+		}
+		inspections = append(inspections, inspection)
+	}
+	return inspections
+}
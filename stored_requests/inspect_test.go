@@ -0,0 +1,164 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/stored_requests/caches/nil_cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectRequestsWithCache(t *testing.T) {
+	reqCache, impCache, _, fetcher, aFetcherWithCache, metricsEngine := setupFetcherWithCacheDeps()
+	ctx := context.Background()
+	reqIDs := []string{"req-id"}
+	impIDs := []string{"cached", "fetched", "unknown"}
+	metricsEngine.On("RecordStoredReqCacheResult", mock.Anything, mock.Anything)
+	metricsEngine.On("RecordStoredImpCacheResult", mock.Anything, mock.Anything)
+
+	// an auction fetches and caches the "fetched" imp
+	reqCache.On("Get", ctx, []string(nil)).Return(map[string]json.RawMessage{})
+	impCache.On("Get", ctx, []string{"fetched"}).Return(map[string]json.RawMessage{}).Once()
+	fetcher.On("FetchRequests", ctx, []string{}, []string{"fetched"}).Return(
+		map[string]json.RawMessage{},
+		map[string]json.RawMessage{"fetched": json.RawMessage(`{"old":true}`)},
+		[]error{},
+	).Once()
+	reqCache.On("Save", ctx, map[string]json.RawMessage{})
+	impCache.On("Save", ctx, map[string]json.RawMessage{"fetched": json.RawMessage(`{"old":true}`)}).Once()
+	_, _, errs := aFetcherWithCache.FetchRequests(ctx, nil, []string{"fetched"})
+	require.Empty(t, errs)
+
+	reqCache.On("Get", ctx, reqIDs).Return(map[string]json.RawMessage{})
+	impCache.On("Get", ctx, impIDs).Return(map[string]json.RawMessage{
+		"cached":  json.RawMessage(`{"cached":true}`),
+		"fetched": json.RawMessage(`{"old":true}`),
+	})
+	fetcher.On("FetchRequests", ctx, reqIDs, impIDs).Return(
+		map[string]json.RawMessage{"req-id": json.RawMessage(`{"req":true}`)},
+		map[string]json.RawMessage{
+			"cached":  json.RawMessage(`{"cached":true}`),
+			"fetched": json.RawMessage(`{"new":true}`),
+		},
+		[]error{NotFoundError{ID: "unknown", DataType: "Imp"}, errors.New("other")},
+	)
+
+	inspections, errs := InspectRequests(ctx, aFetcherWithCache, reqIDs, impIDs)
+
+	// inspecting must not save anything to the caches
+	reqCache.AssertExpectations(t)
+	impCache.AssertExpectations(t)
+	assert.Equal(t, []error{errors.New("other")}, errs)
+	require.Len(t, inspections, 4)
+
+	assert.Equal(t, "req-id", inspections[0].ID)
+	assert.Equal(t, "Request", inspections[0].DataType)
+	assert.Equal(t, metrics.CacheMiss, inspections[0].Cache)
+	assert.Nil(t, inspections[0].LastFetched)
+	assert.JSONEq(t, `{"req":true}`, string(inspections[0].Backend))
+
+	assert.Equal(t, metrics.CacheHit, inspections[1].Cache)
+	assert.JSONEq(t, `{"cached":true}`, string(inspections[1].Cached))
+	assert.Nil(t, inspections[1].LastFetched, "data saved through events is not fetched")
+
+	assert.Equal(t, metrics.CacheHit, inspections[2].Cache)
+	assert.JSONEq(t, `{"old":true}`, string(inspections[2].Cached))
+	assert.JSONEq(t, `{"new":true}`, string(inspections[2].Backend))
+	assert.NotNil(t, inspections[2].LastFetched)
+
+	assert.Equal(t, "unknown", inspections[3].ID)
+	assert.Equal(t, metrics.CacheMiss, inspections[3].Cache)
+	assert.True(t, inspections[3].NotFound)
+	assert.Nil(t, inspections[3].Backend)
+}
+
+func TestInspectAccount(t *testing.T) {
+	ctx := context.Background()
+	accountCache := &mockCache{}
+	fetcher := &mockFetcher{}
+	metricsEngine := &metrics.MetricsEngineMock{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, accountCache}, metricsEngine)
+
+	accountCache.On("Get", ctx, []string{"account"}).Return(map[string]json.RawMessage{"account": json.RawMessage(`{"cached":true}`)})
+	fetcher.On("FetchAccount", ctx, json.RawMessage(nil), "account").Return(json.RawMessage(`{"backend":true}`), []error{})
+
+	testCases := []struct {
+		description   string
+		fetcher       AccountFetcher
+		expectedCache metrics.CacheResult
+	}{
+		{
+			description:   "with-cache",
+			fetcher:       aFetcherWithCache,
+			expectedCache: metrics.CacheHit,
+		},
+		{
+			description:   "hierarchy-with-cache",
+			fetcher:       WithAccountHierarchy(aFetcherWithCache.(*fetcherWithCache), newMapCache(), 5),
+			expectedCache: metrics.CacheHit,
+		},
+		{
+			description:   "without-cache",
+			fetcher:       fetcher,
+			expectedCache: CacheNone,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			inspection, errs := InspectAccount(ctx, test.fetcher, "account")
+
+			assert.Empty(t, errs)
+			assert.Equal(t, "account", inspection.ID)
+			assert.Equal(t, "Account", inspection.DataType)
+			assert.Equal(t, test.expectedCache, inspection.Cache)
+			assert.JSONEq(t, `{"backend":true}`, string(inspection.Backend))
+		})
+	}
+}
+
+func TestUncached(t *testing.T) {
+	ctx := context.Background()
+	fetcher := &mockFetcher{}
+	aFetcherWithCache := WithCache(fetcher, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, &mockCache{}}, &metrics.MetricsEngineMock{})
+	resolved := newMapCache()
+	resolved["account"] = json.RawMessage(`{"cached":true}`)
+	hierarchy := WithAccountHierarchy(aFetcherWithCache.(*fetcherWithCache), resolved, 5)
+
+	fetcher.On("FetchAccount", ctx, json.RawMessage(nil), "account").Return(json.RawMessage(`{"backend":true}`), []error{})
+
+	assert.Same(t, fetcher, Uncached(fetcher), "fetchers without caches are used as is")
+	assert.Same(t, fetcher, Uncached(aFetcherWithCache))
+
+	// the account cache and the metrics engine mocks fail the test if they are used
+	account, errs := Uncached(hierarchy).FetchAccount(ctx, nil, "account")
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"backend":true}`, string(account))
+	assert.Equal(t, mapCache{"account": json.RawMessage(`{"cached":true}`)}, resolved, "the resolved accounts are not cached")
+}
+
+func TestFetchTimesEviction(t *testing.T) {
+	times := &fetchTimes{}
+	times.record("Account", map[string]json.RawMessage{"first": nil})
+	for i := 0; i < maxFetchTimes; i++ {
+		times.record("Request", map[string]json.RawMessage{strconv.Itoa(i): nil})
+	}
+
+	assert.NotNil(t, times.get("Account", "first"), "the previous generation is kept")
+	assert.NotNil(t, times.get("Request", "0"))
+	assert.Nil(t, times.get("Imp", "0"))
+
+	for i := 0; i < maxFetchTimes; i++ {
+		times.record("Imp", map[string]json.RawMessage{strconv.Itoa(i): nil})
+	}
+
+	assert.Nil(t, times.get("Account", "first"), "older generations are dropped")
+	assert.NotNil(t, times.get("Imp", "0"))
+	assert.LessOrEqual(t, len(times.current)+len(times.previous), 2*maxFetchTimes)
+}
@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+)
+
+type accountFetcher struct {
+	stored_requests.AllFetcher
+	validator *validator
+
+	lock sync.RWMutex
+	// validated maps account IDs to the outcome of the validation of their latest data
+	validated map[string]accountValidation
+}
+
+type accountValidation struct {
+	checksum [sha256.Size]byte
+	err      error
+}
+
+// WithAccountValidation returns a fetcher validating the accounts returned by the given one. It is meant to wrap the
+// caches and the account hierarchy, so that the complete account is validated, merged with its parents and the
+// account defaults.
+//
+// Accounts are validated again only when their data changes, so rejected accounts are served by the caches beneath
+// like valid ones, without reaching the backend. Invalid accounts are returned as a MalformedAcct error. They are
+// logged, counted in the stored data error metrics and, if a quarantine is given, kept there for inspection.
+func WithAccountValidation(fetcher stored_requests.AllFetcher, metricsEngine metrics.MetricsEngine, quarantine *Quarantine) stored_requests.AllFetcher {
+	return &accountFetcher{
+		AllFetcher: fetcher,
+		validator: &validator{
+			dataType:      config.AccountDataType,
+			metricsEngine: metricsEngine,
+			quarantine:    quarantine,
+		},
+		validated: make(map[string]accountValidation),
+	}
+}
+
+func (f *accountFetcher) FetchAccount(ctx context.Context, accountDefaultJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	account, errs := f.AllFetcher.FetchAccount(ctx, accountDefaultJSON, accountID)
+	if len(errs) > 0 || account == nil {
+		return account, errs
+	}
+
+	if err := f.validate(accountID, account); err != nil {
+		return nil, []error{&errortypes.MalformedAcct{
+			Message: fmt.Sprintf("The prebid-server account config for account id \"%s\" is invalid. Please reach out to the prebid server host.", accountID),
+		}}
+	}
+	return account, nil
+}
+
+// validate returns the outcome of the validation of the account, which is only validated again if its data changed
+func (f *accountFetcher) validate(accountID string, account json.RawMessage) error {
+	checksum := sha256.Sum256(account)
+
+	f.lock.RLock()
+	previous, ok := f.validated[accountID]
+	f.lock.RUnlock()
+	if ok && previous.checksum == checksum {
+		return previous.err
+	}
+
+	result := accountValidation{checksum: checksum, err: validateAccount(account)}
+	if result.err != nil {
+		f.validator.reject(events.ValidationError{DataType: "Account", ID: accountID, Err: result.err}, account)
+	} else if f.validator.quarantine != nil {
+		f.validator.quarantine.release(config.AccountDataType, "Account", []string{accountID})
+	}
+
+	f.lock.Lock()
+	f.validated[accountID] = result
+	f.lock.Unlock()
+	return result.err
+}
@@ -0,0 +1,95 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAccountFetcher struct {
+	stored_requests.AllFetcher
+	accounts map[string]json.RawMessage
+}
+
+func (f *mockAccountFetcher) FetchAccount(ctx context.Context, accountDefaultJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	if account, ok := f.accounts[accountID]; ok {
+		return account, nil
+	}
+	return nil, []error{stored_requests.NotFoundError{ID: accountID, DataType: "Account"}}
+}
+
+func TestWithAccountValidation(t *testing.T) {
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordStoredDataError", metrics.StoredDataLabels{DataType: metrics.AccountDataType, Error: metrics.StoredDataErrorInvalid})
+	fetcher := WithAccountValidation(&mockAccountFetcher{accounts: map[string]json.RawMessage{
+		"valid":             json.RawMessage(`{"privacy":{"precision_policies":{"coarse":{"ipv4_keep_bits":16}}}}`),
+		"invalid-policy":    json.RawMessage(`{"privacy":{"precision_policies":{"coarse":{"ipv4_keep_bits":64}}}}`),
+		"unknown-policy":    json.RawMessage(`{"privacy":{"precision_policy":"coarse"}}`),
+		"invalid-analytics": json.RawMessage(`{"analytics":{"modules":{"agma":{"sample_rate":2}}}}`),
+		"invalid-eids":      json.RawMessage(`{"privacy":{"eidpermissions":[{"bidders":["*"]}]}}`),
+		"invalid-cookie":    json.RawMessage(`{"cookie_sync":{"cookie":{"mode":"first_party","name":"pbs_uids"}}}`),
+		"malformed-account": json.RawMessage(`{"disabled":"invalid type"}`),
+	}}, metricsEngine, nil)
+
+	testCases := []struct {
+		accountID string
+		wantErr   error
+	}{
+		{accountID: "valid"},
+		{accountID: "invalid-policy", wantErr: &errortypes.MalformedAcct{}},
+		{accountID: "unknown-policy", wantErr: &errortypes.MalformedAcct{}},
+		{accountID: "invalid-analytics", wantErr: &errortypes.MalformedAcct{}},
+		{accountID: "invalid-eids", wantErr: &errortypes.MalformedAcct{}},
+		{accountID: "invalid-cookie", wantErr: &errortypes.MalformedAcct{}},
+		{accountID: "malformed-account", wantErr: &errortypes.MalformedAcct{}},
+		{accountID: "missing", wantErr: stored_requests.NotFoundError{}},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.accountID, func(t *testing.T) {
+			account, errs := fetcher.FetchAccount(context.Background(), nil, test.accountID)
+			if test.wantErr == nil {
+				assert.Empty(t, errs)
+				assert.NotNil(t, account)
+				return
+			}
+			assert.Nil(t, account)
+			if assert.Len(t, errs, 1) {
+				assert.IsType(t, test.wantErr, errs[0])
+			}
+		})
+	}
+}
+
+func TestWithAccountValidationValidatesChangesOnly(t *testing.T) {
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordStoredDataError", metrics.StoredDataLabels{DataType: metrics.AccountDataType, Error: metrics.StoredDataErrorInvalid})
+	quarantine := NewQuarantine(10)
+	backend := &mockAccountFetcher{accounts: map[string]json.RawMessage{
+		"account": json.RawMessage(`{"privacy":{"precision_policy":"coarse"}}`),
+	}}
+	fetcher := WithAccountValidation(backend, metricsEngine, quarantine)
+
+	for i := 0; i < 2; i++ {
+		_, errs := fetcher.FetchAccount(context.Background(), nil, "account")
+		require.Len(t, errs, 1)
+		assert.IsType(t, &errortypes.MalformedAcct{}, errs[0])
+	}
+	metricsEngine.AssertNumberOfCalls(t, "RecordStoredDataError", 1)
+	quarantined := quarantine.Data()
+	require.Len(t, quarantined, 1)
+	assert.Equal(t, "accounts", quarantined[0].Section)
+	assert.Equal(t, "account", quarantined[0].ID)
+
+	backend.accounts["account"] = json.RawMessage(`{"privacy":{"precision_policy":"coarse","precision_policies":{"coarse":{"ipv4_keep_bits":16}}}}`)
+	account, errs := fetcher.FetchAccount(context.Background(), nil, "account")
+	assert.Empty(t, errs)
+	assert.JSONEq(t, string(backend.accounts["account"]), string(account))
+	assert.Empty(t, quarantine.Data())
+}
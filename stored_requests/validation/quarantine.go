@@ -0,0 +1,108 @@
+package validation
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+)
+
+// QuarantinedData is stored data which was rejected by validation
+type QuarantinedData struct {
+	// Section is the config section the data was saved to, e.g. "stored_requests" or "accounts"
+	Section string `json:"section"`
+	// DataType is one of "Request", "Imp", "Account" or "Response"
+	DataType string          `json:"type"`
+	ID       string          `json:"id"`
+	Data     json.RawMessage `json:"data"`
+	Error    string          `json:"error"`
+	Time     time.Time       `json:"time"`
+
+	// sequence orders the quarantined data, since it may be added faster than the clock resolution
+	sequence uint64
+}
+
+type quarantineKey struct {
+	section  string
+	dataType string
+	id       string
+}
+
+// Quarantine holds the latest invalid version of stored data, until valid data is saved for the same ID or it is
+// invalidated. When full, the oldest data is dropped.
+type Quarantine struct {
+	lock       sync.Mutex
+	data       map[quarantineKey]QuarantinedData
+	maxEntries int
+	sequence   uint64
+}
+
+// NewQuarantine returns a Quarantine which holds up to maxEntries rejected stored data
+func NewQuarantine(maxEntries int) *Quarantine {
+	return &Quarantine{
+		data:       make(map[quarantineKey]QuarantinedData),
+		maxEntries: maxEntries,
+	}
+}
+
+// Data returns the quarantined data, oldest first
+func (q *Quarantine) Data() []QuarantinedData {
+	q.lock.Lock()
+	data := make([]QuarantinedData, 0, len(q.data))
+	for _, quarantined := range q.data {
+		data = append(data, quarantined)
+	}
+	q.lock.Unlock()
+
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].sequence < data[j].sequence
+	})
+	return data
+}
+
+func (q *Quarantine) add(dataType config.DataType, err events.ValidationError, data json.RawMessage) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	key := quarantineKey{dataType.Section(), err.DataType, err.ID}
+	if _, ok := q.data[key]; !ok && len(q.data) >= q.maxEntries {
+		q.evictOldest()
+	}
+	q.sequence++
+	q.data[key] = QuarantinedData{
+		Section:  key.section,
+		DataType: key.dataType,
+		ID:       key.id,
+		Data:     data,
+		Error:    err.Err.Error(),
+		Time:     time.Now(),
+		sequence: q.sequence,
+	}
+}
+
+func (q *Quarantine) release(dataType config.DataType, storedDataType string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for _, id := range ids {
+		delete(q.data, quarantineKey{dataType.Section(), storedDataType, id})
+	}
+}
+
+func (q *Quarantine) evictOldest() {
+	var oldest quarantineKey
+	var oldestSequence uint64
+	for key, quarantined := range q.data {
+		if oldestSequence == 0 || quarantined.sequence < oldestSequence {
+			oldest = key
+			oldestSequence = quarantined.sequence
+		}
+	}
+	delete(q.data, oldest)
+}
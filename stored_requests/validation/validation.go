@@ -0,0 +1,254 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// storedImpID stands in for the ID of stored imps which don't have one, since it is usually given by the incoming request
+const storedImpID = "stored-imp"
+
+var storedDataTypeMetricMap = map[config.DataType]metrics.StoredDataType{
+	config.RequestDataType:    metrics.RequestDataType,
+	config.VideoDataType:      metrics.VideoDataType,
+	config.AMPRequestDataType: metrics.AMPDataType,
+	config.AccountDataType:    metrics.AccountDataType,
+	config.CategoryDataType:   metrics.CategoryDataType,
+	config.ResponseDataType:   metrics.ResponseDataType,
+}
+
+type validator struct {
+	dataType         config.DataType
+	requestValidator ortb.RequestValidator
+	paramsValidator  openrtb_ext.BidderParamValidator
+	metricsEngine    metrics.MetricsEngine
+	quarantine       *Quarantine
+	// partialAccounts is true if the accounts only hold the settings which differ from their parent account
+	partialAccounts bool
+}
+
+// NewValidator returns a Validator for the stored data of the given config section. It checks that:
+//
+// 1. Stored Requests and Imps parse, and their imps pass the ortb.RequestValidator rules.
+// 2. Bidder params of partially stored imps, which lack media types, pass the bidder params JSON schemas.
+// 3. Accounts unmarshal into config.Account, and pass its validation.
+// 4. Stored Responses are valid JSON.
+//
+// Invalid data is logged, counted in the stored data error metrics and, if a quarantine is given, kept there for inspection.
+// It is kept out of the caches, which keep serving its previous version, if any.
+func NewValidator(dataType config.DataType, requestValidator ortb.RequestValidator, paramsValidator openrtb_ext.BidderParamValidator, metricsEngine metrics.MetricsEngine, quarantine *Quarantine) events.Validator {
+	return &validator{
+		dataType:         dataType,
+		requestValidator: requestValidator,
+		paramsValidator:  paramsValidator,
+		metricsEngine:    metricsEngine,
+		quarantine:       quarantine,
+	}
+}
+
+// NewAccountHierarchyValidator returns a Validator for the accounts of an account hierarchy. Those only hold the
+// settings which differ from their parent account, so they are only checked to unmarshal into config.Account. The
+// merged accounts are validated when they're loaded, by the fetcher returned by WithAccountValidation.
+func NewAccountHierarchyValidator(metricsEngine metrics.MetricsEngine, quarantine *Quarantine) events.Validator {
+	return &validator{
+		dataType:        config.AccountDataType,
+		metricsEngine:   metricsEngine,
+		quarantine:      quarantine,
+		partialAccounts: true,
+	}
+}
+
+func (v *validator) Validate(save events.Save) (events.Save, []events.ValidationError) {
+	var errs []events.ValidationError
+	validateRequest := v.validateRequest
+	if v.dataType == config.VideoDataType {
+		validateRequest = validateVideoRequest
+	}
+	validateAccountData := validateAccount
+	if v.partialAccounts {
+		validateAccountData = unmarshalAccount
+	}
+
+	var valid events.Save
+	valid.Requests, errs = v.validateAll("Request", save.Requests, validateRequest, errs)
+	valid.Imps, errs = v.validateAll("Imp", save.Imps, v.validateImp, errs)
+	valid.Accounts, errs = v.validateAll("Account", save.Accounts, validateAccountData, errs)
+	valid.Responses, errs = v.validateAll("Response", save.Responses, validateResponse, errs)
+	return valid, errs
+}
+
+func (v *validator) Invalidated(invalidation events.Invalidation) {
+	if v.quarantine == nil {
+		return
+	}
+	v.quarantine.release(v.dataType, "Request", invalidation.Requests)
+	v.quarantine.release(v.dataType, "Imp", invalidation.Imps)
+	v.quarantine.release(v.dataType, "Account", invalidation.Accounts)
+	v.quarantine.release(v.dataType, "Response", invalidation.Responses)
+}
+
+func (v *validator) validateAll(dataType string, data map[string]json.RawMessage, validate func(json.RawMessage) error, errs []events.ValidationError) (map[string]json.RawMessage, []events.ValidationError) {
+	if data == nil {
+		return nil, errs
+	}
+
+	valid := make(map[string]json.RawMessage, len(data))
+	var validIDs []string
+	for id, value := range data {
+		if err := validate(value); err != nil {
+			validationErr := events.ValidationError{DataType: dataType, ID: id, Err: err}
+			v.reject(validationErr, value)
+			errs = append(errs, validationErr)
+			continue
+		}
+		valid[id] = value
+		validIDs = append(validIDs, id)
+	}
+
+	if v.quarantine != nil {
+		v.quarantine.release(v.dataType, dataType, validIDs)
+	}
+	return valid, errs
+}
+
+func (v *validator) reject(err events.ValidationError, data json.RawMessage) {
+	logger.Warnf("Rejected invalid %s data: %v", v.dataType, err)
+	v.metricsEngine.RecordStoredDataError(metrics.StoredDataLabels{
+		DataType: storedDataTypeMetricMap[v.dataType],
+		Error:    metrics.StoredDataErrorInvalid,
+	})
+	if v.quarantine != nil {
+		v.quarantine.add(v.dataType, err, data)
+	}
+}
+
+func (v *validator) validateRequest(data json.RawMessage) error {
+	var request openrtb2.BidRequest
+	if err := jsonutil.UnmarshalValid(data, &request); err != nil {
+		return err
+	}
+
+	requestWrapper := &openrtb_ext.RequestWrapper{BidRequest: &request}
+	requestExt, err := requestWrapper.GetRequestExt()
+	if err != nil {
+		return err
+	}
+	var aliases map[string]string
+	if prebid := requestExt.GetPrebid(); prebid != nil {
+		aliases = prebid.Aliases
+	}
+
+	for index, imp := range requestWrapper.GetImp() {
+		if err := v.validateImpRules(imp, index, aliases); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateImp(data json.RawMessage) error {
+	var imp openrtb2.Imp
+	if err := jsonutil.UnmarshalValid(data, &imp); err != nil {
+		return err
+	}
+	return v.validateImpRules(&openrtb_ext.ImpWrapper{Imp: &imp}, 0, nil)
+}
+
+func (v *validator) validateImpRules(imp *openrtb_ext.ImpWrapper, index int, aliases map[string]string) error {
+	if imp.Banner == nil && imp.Video == nil && imp.Audio == nil && imp.Native == nil {
+		// Partially stored imps get their media types from the incoming request, so only their bidder params can be checked
+		return v.validateBidderParams(imp, index, aliases)
+	}
+
+	if imp.ID == "" {
+		imp.ID = storedImpID
+	}
+	impExt, err := imp.GetImpExt()
+	if err != nil {
+		return err
+	}
+
+	// Stored bid responses are resolved during the auction, so their presence is all that can be checked
+	var storedBidResponses map[string]map[string]json.RawMessage
+	if prebid := impExt.GetPrebid(); prebid != nil && len(prebid.StoredBidResponse) > 0 {
+		storedBidResponses = map[string]map[string]json.RawMessage{imp.ID: {}}
+		for _, storedBidResponse := range prebid.StoredBidResponse {
+			storedBidResponses[imp.ID][storedBidResponse.Bidder] = nil
+		}
+	}
+
+	errs := v.requestValidator.ValidateImp(imp, ortb.ValidationConfig{}, index, aliases, false, storedBidResponses)
+	return errors.Join(errortypes.FatalOnly(errs)...)
+}
+
+func (v *validator) validateBidderParams(imp *openrtb_ext.ImpWrapper, index int, aliases map[string]string) error {
+	if len(imp.Ext) == 0 {
+		return nil
+	}
+	impExt, err := imp.GetImpExt()
+	if err != nil {
+		return err
+	}
+
+	bidderParams := make(map[string]json.RawMessage)
+	for bidder, params := range impExt.GetExt() {
+		if openrtb_ext.IsPotentialBidder(bidder) {
+			bidderParams[bidder] = params
+		}
+	}
+	if prebid := impExt.GetPrebid(); prebid != nil {
+		for bidder, params := range prebid.Bidder {
+			bidderParams[bidder] = params
+		}
+	}
+
+	for bidder, params := range bidderParams {
+		coreBidder := bidder
+		if alias, isAlias := aliases[bidder]; isAlias {
+			coreBidder = alias
+		}
+		// Bidders may be aliases defined by the incoming request, which can't be checked
+		if bidderName, ok := openrtb_ext.NormalizeBidderName(coreBidder); ok {
+			if err := v.paramsValidator.Validate(bidderName, params); err != nil {
+				return fmt.Errorf("request.imp[%d].ext.prebid.bidder.%s failed validation.\n%v", index, bidder, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateVideoRequest(data json.RawMessage) error {
+	var request openrtb_ext.BidRequestVideo
+	return jsonutil.UnmarshalValid(data, &request)
+}
+
+func validateAccount(data json.RawMessage) error {
+	var account config.Account
+	if err := jsonutil.UnmarshalValid(data, &account); err != nil {
+		return err
+	}
+	return errors.Join(account.Validate(nil)...)
+}
+
+func unmarshalAccount(data json.RawMessage) error {
+	var account config.Account
+	return jsonutil.UnmarshalValid(data, &account)
+}
+
+func validateResponse(data json.RawMessage) error {
+	if !json.Valid(data) {
+		return errors.New("malformed JSON")
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestValidator(t *testing.T, dataType config.DataType, metricsEngine metrics.MetricsEngine, quarantine *Quarantine) events.Validator {
+	paramsValidator, err := openrtb_ext.NewBidderParamsValidator("../../static/bidder-params")
+	require.NoError(t, err)
+	requestValidator := ortb.NewRequestValidator(openrtb_ext.BuildBidderMap(), map[string]string{}, paramsValidator)
+	return NewValidator(dataType, requestValidator, paramsValidator, metricsEngine, quarantine)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		description string
+		save        events.Save
+		expectedIDs []string
+	}{
+		{
+			description: "complete-imp",
+			save:        events.Save{Imps: map[string]json.RawMessage{"imp": json.RawMessage(`{"banner":{"w":300,"h":250},"ext":{"prebid":{"bidder":{"appnexus":{"placement_id":1}}}}}`)}},
+		},
+		{
+			description: "partial-imp",
+			save:        events.Save{Imps: map[string]json.RawMessage{"imp": json.RawMessage(`{"ext":{"appnexus":{"placement_id":1},"unknownalias":{"any":true}}}`)}},
+		},
+		{
+			description: "malformed-imp",
+			save:        events.Save{Imps: map[string]json.RawMessage{"imp": json.RawMessage(`{"banner":`)}},
+			expectedIDs: []string{"imp"},
+		},
+		{
+			description: "imp-failing-rules",
+			save:        events.Save{Imps: map[string]json.RawMessage{"imp": json.RawMessage(`{"banner":{"format":[{"w":0,"h":0}]},"ext":{"prebid":{"bidder":{"appnexus":{"placement_id":1}}}}}`)}},
+			expectedIDs: []string{"imp"},
+		},
+		{
+			description: "partial-imp-failing-bidder-params",
+			save:        events.Save{Imps: map[string]json.RawMessage{"imp": json.RawMessage(`{"ext":{"prebid":{"bidder":{"appnexus":{"placement_id":true}}}}}`)}},
+			expectedIDs: []string{"imp"},
+		},
+		{
+			description: "request-with-aliased-imp",
+			save: events.Save{Requests: map[string]json.RawMessage{"req": json.RawMessage(`{
+				"imp":[{"id":"1","banner":{"w":300,"h":250},"ext":{"prebid":{"bidder":{"alias":{"placement_id":1}}}}}],
+				"ext":{"prebid":{"aliases":{"alias":"appnexus"}}}
+			}`)}},
+		},
+		{
+			description: "request-with-invalid-imp",
+			save: events.Save{Requests: map[string]json.RawMessage{"req": json.RawMessage(`{
+				"imp":[{"id":"1","banner":{"w":300,"h":250},"ext":{"prebid":{"bidder":{"appnexus":{"placement_id":true}}}}}]
+			}`)}},
+			expectedIDs: []string{"req"},
+		},
+		{
+			description: "accounts",
+			save: events.Save{Accounts: map[string]json.RawMessage{
+				"valid":             json.RawMessage(`{"id":"valid","disabled":false}`),
+				"invalid":           json.RawMessage(`{"id":"invalid","disabled":"no"}`),
+				"invalid-analytics": json.RawMessage(`{"id":"invalid-analytics","analytics":{"modules":{"agma":{"sample_rate":2}}}}`),
+			}},
+			expectedIDs: []string{"invalid", "invalid-analytics"},
+		},
+		{
+			description: "responses",
+			save: events.Save{Responses: map[string]json.RawMessage{
+				"valid":   json.RawMessage(`[{"bid":[]}]`),
+				"invalid": json.RawMessage(`[{"bid":`),
+			}},
+			expectedIDs: []string{"invalid"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsEngine := &metrics.MetricsEngineMock{}
+			for range test.expectedIDs {
+				metricsEngine.On("RecordStoredDataError", metrics.StoredDataLabels{DataType: metrics.RequestDataType, Error: metrics.StoredDataErrorInvalid}).Once()
+			}
+			validator := newTestValidator(t, config.RequestDataType, metricsEngine, nil)
+
+			valid, errs := validator.Validate(test.save)
+
+			var invalidIDs []string
+			for _, err := range errs {
+				invalidIDs = append(invalidIDs, err.ID)
+			}
+			assert.ElementsMatch(t, test.expectedIDs, invalidIDs)
+			assert.Equal(t, countData(test.save)-len(test.expectedIDs), countData(valid), "valid data must be kept")
+			metricsEngine.AssertExpectations(t)
+		})
+	}
+}
+
+func TestValidateVideoRequests(t *testing.T) {
+	validator := newTestValidator(t, config.VideoDataType, &metrics.MetricsEngineMock{}, nil)
+
+	valid, errs := validator.Validate(events.Save{Requests: map[string]json.RawMessage{
+		"video": json.RawMessage(`{"podconfig":{"durationrangesec":[30]},"video":{"mimes":["video/mp4"]}}`),
+	}})
+
+	assert.Empty(t, errs)
+	assert.Len(t, valid.Requests, 1)
+}
+
+func TestValidateQuarantine(t *testing.T) {
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordStoredDataError", metrics.StoredDataLabels{DataType: metrics.AccountDataType, Error: metrics.StoredDataErrorInvalid})
+	quarantine := NewQuarantine(10)
+	validator := newTestValidator(t, config.AccountDataType, metricsEngine, quarantine)
+
+	validator.Validate(events.Save{Accounts: map[string]json.RawMessage{
+		"fixed":       json.RawMessage(`{"disabled":"no"}`),
+		"invalidated": json.RawMessage(`{"disabled":"no"}`),
+		"kept":        json.RawMessage(`{"disabled":"no"}`),
+	}})
+	require.Len(t, quarantine.Data(), 3)
+
+	validator.Validate(events.Save{Accounts: map[string]json.RawMessage{"fixed": json.RawMessage(`{"disabled":false}`)}})
+	validator.Invalidated(events.Invalidation{Accounts: []string{"invalidated"}})
+
+	quarantined := quarantine.Data()
+	require.Len(t, quarantined, 1)
+	assert.Equal(t, "accounts", quarantined[0].Section)
+	assert.Equal(t, "Account", quarantined[0].DataType)
+	assert.Equal(t, "kept", quarantined[0].ID)
+	assert.JSONEq(t, `{"disabled":"no"}`, string(quarantined[0].Data))
+	assert.NotEmpty(t, quarantined[0].Error)
+}
+
+func TestAccountHierarchyValidator(t *testing.T) {
+	validator := NewAccountHierarchyValidator(&metrics.MetricsEngineMock{}, nil)
+
+	valid, errs := validator.Validate(events.Save{Accounts: map[string]json.RawMessage{
+		"child": json.RawMessage(`{"parent":"parent","privacy":{"precision_policy":"coarse"}}`),
+	}})
+	assert.Empty(t, errs)
+	assert.Len(t, valid.Accounts, 1)
+}
+
+func TestQuarantineEvictsOldest(t *testing.T) {
+	quarantine := NewQuarantine(2)
+	for _, id := range []string{"1", "2", "3"} {
+		quarantine.add(config.RequestDataType, events.ValidationError{DataType: "Imp", ID: id, Err: assert.AnError}, json.RawMessage(`{}`))
+	}
+
+	quarantined := quarantine.Data()
+	require.Len(t, quarantined, 2)
+	assert.Equal(t, "2", quarantined[0].ID)
+	assert.Equal(t, "3", quarantined[1].ID)
+}
+
+func countData(save events.Save) int {
+	return len(save.Requests) + len(save.Imps) + len(save.Accounts) + len(save.Responses)
+}
+
+func TestStoredDataTypeMetricMap(t *testing.T) {
+	dataTypes := []config.DataType{config.RequestDataType, config.CategoryDataType, config.VideoDataType, config.AMPRequestDataType, config.AccountDataType, config.ResponseDataType}
+	for _, dataType := range dataTypes {
+		assert.Contains(t, metrics.StoredDataTypes(), storedDataTypeMetricMap[dataType], "data type %s", dataType)
+	}
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// NewProvider sets the global tracer provider to one exporting the spans to the collector of cfg. The returned function
+// exports the remaining spans and stops the provider.
+func NewProvider(cfg config.Tracing) (func(), error) {
+	timeout := time.Duration(cfg.TimeoutMS) * time.Millisecond
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(cfg.Endpoint),
+		otlptracehttp.WithTimeout(timeout),
+	}
+	if cfg.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return startProvider(cfg, exporter, timeout), nil
+}
+
+func startProvider(cfg config.Tracing, exporter sdktrace.SpanExporter, timeout time.Duration) func() {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(version.Ver),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	logger.Infof("[Tracing] Exporting the traces to %s", cfg.Endpoint)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			logger.Errorf("[Tracing] Failed to export the remaining spans: %v", err)
+		}
+	}
+}
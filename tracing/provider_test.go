@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewProvider(t *testing.T) {
+	paths := make(chan string, 10)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	shutdown, err := NewProvider(config.Tracing{
+		Enabled:     true,
+		Endpoint:    strings.TrimPrefix(collector.URL, "http://"),
+		Insecure:    true,
+		ServiceName: "prebid-server",
+		SampleRate:  1,
+		TimeoutMS:   1000,
+	})
+	require.NoError(t, err)
+
+	_, span := Start(context.Background(), SpanFetchAccount)
+	assert.True(t, span.IsRecording())
+	span.End()
+	shutdown()
+
+	require.Len(t, paths, 1)
+	assert.Equal(t, "/v1/traces", <-paths)
+}
+
+func TestStartProviderSampling(t *testing.T) {
+	tests := []struct {
+		description string
+		sampleRate  float64
+		expected    int
+	}{
+		{
+			description: "Sample all",
+			sampleRate:  1,
+			expected:    1,
+		},
+		{
+			description: "Sample none",
+			sampleRate:  0,
+			expected:    0,
+		},
+	}
+
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	for _, test := range tests {
+		exporter := keptSpansExporter{tracetest.NewInMemoryExporter()}
+		shutdown := startProvider(config.Tracing{ServiceName: "prebid-server", SampleRate: test.sampleRate}, exporter, time.Second)
+
+		_, span := Start(context.Background(), SpanFetchAccount)
+		span.End()
+		shutdown()
+
+		assert.Len(t, exporter.GetSpans(), test.expected, test.description)
+	}
+}
+
+// keptSpansExporter keeps the exported spans on shutdown, which the in-memory exporter drops
+type keptSpansExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (keptSpansExporter) Shutdown(context.Context) error {
+	return nil
+}
@@ -0,0 +1,116 @@
+// Package tracing traces the requests across the auction pipeline with OpenTelemetry. Spans are started on the
+// global tracer provider, which doesn't record anything unless tracing is enabled by NewProvider.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/prebid/prebid-server/v3"
+
+// Names of the spans
+const (
+	SpanParseRequest        = "parse_request"
+	SpanFetchStoredRequests = "fetch_stored_requests"
+	SpanFetchAccount        = "fetch_account"
+	SpanHookStage           = "hook_stage"
+	SpanHook                = "hook"
+	SpanMakeRequests        = "bidder.make_requests"
+	SpanBidderHTTPRequest   = "bidder.http_request"
+	SpanMakeBids            = "bidder.make_bids"
+	SpanCurrencyConversion  = "currency_conversion"
+	SpanFloorsResolution    = "floors_resolution"
+	SpanCachePut            = "prebid_cache.put"
+)
+
+// Names of the events of the bidder HTTP requests, timing the connection to the bidder
+const (
+	EventGetConn      = "get_conn"
+	EventDNS          = "dns"
+	EventConnect      = "connect"
+	EventTLSHandshake = "tls_handshake"
+)
+
+// Keys of the attributes of the spans
+const (
+	AttributeEndpoint   = attribute.Key("pbs.endpoint")
+	AttributeRequestID  = attribute.Key("pbs.request_id")
+	AttributeAccount    = attribute.Key("pbs.account")
+	AttributeBidder     = attribute.Key("pbs.bidder")
+	AttributeStage      = attribute.Key("pbs.stage")
+	AttributeModule     = attribute.Key("pbs.module")
+	AttributeHook       = attribute.Key("pbs.hook")
+	AttributeStatus     = attribute.Key("http.response.status_code")
+	AttributeConnReused = attribute.Key("pbs.conn_reused")
+	AttributeDuration   = attribute.Key("pbs.duration_ms")
+)
+
+// propagator reads and writes the W3C traceparent and tracestate headers
+var propagator = propagation.TraceContext{}
+
+// Start starts a span as a child of the span of ctx, if any
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartServer starts the span of a request received by Prebid Server
+func StartServer(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...), trace.WithSpanKind(trace.SpanKindServer))
+}
+
+// StartClient starts the span of a request sent by Prebid Server
+func StartClient(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...), trace.WithSpanKind(trace.SpanKindClient))
+}
+
+// End ends the span, marking it as failed if err isn't nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// EndWithErrors ends the span, marking it as failed with the first error, if any
+func EndWithErrors(span trace.Span, errs []error) {
+	if len(errs) > 0 {
+		End(span, errs[0])
+		return
+	}
+	span.End()
+}
+
+// AddDurationEvent adds an event to the span of ctx, with its duration in milliseconds
+func AddDurationEvent(ctx context.Context, name string, duration time.Duration, attrs ...attribute.KeyValue) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	attrs = append(attrs, AttributeDuration.Float64(float64(duration)/float64(time.Millisecond)))
+	span.AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// ContextWithSpanFrom returns a copy of ctx carrying the span of from. It's used by the contexts which outlive the
+// request, to keep their spans in its trace.
+func ContextWithSpanFrom(ctx context.Context, from context.Context) context.Context {
+	return trace.ContextWithSpan(ctx, trace.SpanFromContext(from))
+}
+
+// Extract returns a copy of ctx carrying the remote span of the W3C traceparent header, if any
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject sets the W3C traceparent header of the span of ctx, if any, so the server continues the trace
+func Inject(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
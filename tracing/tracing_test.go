@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestStartWithoutProvider(t *testing.T) {
+	ctx, span := Start(context.Background(), SpanFetchAccount)
+	defer span.End()
+
+	assert.False(t, span.IsRecording())
+	AddDurationEvent(ctx, EventDNS, time.Millisecond)
+}
+
+func TestEnd(t *testing.T) {
+	recorder := recordSpans(t)
+
+	_, span := Start(context.Background(), SpanFetchAccount, AttributeAccount.String("account"))
+	End(span, nil)
+	_, span = StartClient(context.Background(), SpanCachePut)
+	End(span, errors.New("cache unavailable"))
+	_, span = StartServer(context.Background(), "/openrtb2/auction")
+	EndWithErrors(span, []error{errors.New("first"), errors.New("second")})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, SpanFetchAccount, spans[0].Name())
+	assert.Equal(t, trace.SpanKindInternal, spans[0].SpanKind())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), AttributeAccount.String("account"))
+
+	assert.Equal(t, trace.SpanKindClient, spans[1].SpanKind())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "cache unavailable", spans[1].Status().Description)
+
+	assert.Equal(t, trace.SpanKindServer, spans[2].SpanKind())
+	assert.Equal(t, "first", spans[2].Status().Description)
+}
+
+func TestAddDurationEvent(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctx, span := Start(context.Background(), SpanBidderHTTPRequest)
+	AddDurationEvent(ctx, EventGetConn, 1500*time.Microsecond, AttributeConnReused.Bool(true))
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Len(t, spans[0].Events(), 1)
+	event := spans[0].Events()[0]
+	assert.Equal(t, EventGetConn, event.Name)
+	assert.ElementsMatch(t, []attribute.KeyValue{AttributeConnReused.Bool(true), AttributeDuration.Float64(1.5)}, event.Attributes)
+}
+
+func TestContextWithSpanFrom(t *testing.T) {
+	recorder := recordSpans(t)
+
+	requestCtx, requestSpan := StartServer(context.Background(), "/openrtb2/auction")
+	canceledCtx, cancel := context.WithCancel(requestCtx)
+	cancel()
+
+	ctx := ContextWithSpanFrom(context.Background(), canceledCtx)
+	assert.NoError(t, ctx.Err())
+	_, span := Start(ctx, SpanCachePut)
+	span.End()
+	requestSpan.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+func TestExtractAndInject(t *testing.T) {
+	recorder := recordSpans(t)
+
+	header := http.Header{}
+	header.Set("traceparent", traceparent)
+	ctx, span := StartServer(Extract(context.Background(), header), "/openrtb2/auction")
+	defer span.End()
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+
+	outgoing := http.Header{}
+	Inject(ctx, outgoing)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+span.SpanContext().SpanID().String()+"-01", outgoing.Get("traceparent"))
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestInjectWithoutSpan(t *testing.T) {
+	header := http.Header{}
+	Inject(context.Background(), header)
+	assert.Empty(t, header)
+}
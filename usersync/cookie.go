@@ -23,6 +23,8 @@ const uidTTL = 14 * 24 * time.Hour
 type Cookie struct {
 	uids   map[string]UIDEntry
 	optOut bool
+	// storeKey is the key of the UIDs kept in the IDStore, if any
+	storeKey string
 }
 
 // UIDEntry bundles the UID with an Expiration date.
@@ -47,7 +49,7 @@ func ReadCookie(r *http.Request, decoder Decoder, host *config.HostCookie) *Cook
 	}
 
 	// Read cookie from request
-	cookieFromRequest, err := r.Cookie(uidCookieName)
+	cookieFromRequest, err := r.Cookie(host.UIDsCookie())
 	if err != nil {
 		return NewCookie()
 	}
@@ -66,7 +68,7 @@ func (cookie *Cookie) PrepareCookieForWrite(cfg *config.HostCookie, encoder Enco
 
 		// Convert to HTTP Cookie to Get Size
 		httpCookie := &http.Cookie{
-			Name:    uidCookieName,
+			Name:    cfg.UIDsCookie(),
 			Value:   encodedCookie,
 			Expires: time.Now().Add(cfg.TTLDuration()),
 			Path:    "/",
@@ -89,12 +91,12 @@ func (cookie *Cookie) PrepareCookieForWrite(cfg *config.HostCookie, encoder Enco
 	return "", nil
 }
 
-// WriteCookie sets the prepared cookie onto the header
+// WriteCookie sets the prepared cookie onto the header, in the mode of the config
 func WriteCookie(w http.ResponseWriter, encodedCookie string, cfg *config.HostCookie, setSiteCookie bool) {
 	ttl := cfg.TTLDuration()
 
 	httpCookie := &http.Cookie{
-		Name:    uidCookieName,
+		Name:    cfg.UIDsCookie(),
 		Value:   encodedCookie,
 		Expires: time.Now().Add(ttl),
 		Path:    "/",
@@ -104,9 +106,23 @@ func WriteCookie(w http.ResponseWriter, encodedCookie string, cfg *config.HostCo
 		httpCookie.Domain = cfg.Domain
 	}
 
-	if setSiteCookie {
+	switch cfg.UIDsCookieMode() {
+	case config.UIDsCookieModeFirstParty:
+		// the cookie is only read by the CNAME of Prebid Server on the sites of the publisher
 		httpCookie.Secure = true
-		httpCookie.SameSite = http.SameSiteNoneMode
+		httpCookie.SameSite = http.SameSiteLaxMode
+	case config.UIDsCookieModePartitioned:
+		// the browsers only accept secure partitioned cookies
+		httpCookie.Secure = true
+		httpCookie.Partitioned = true
+		if setSiteCookie {
+			httpCookie.SameSite = http.SameSiteNoneMode
+		}
+	default:
+		if setSiteCookie {
+			httpCookie.Secure = true
+			httpCookie.SameSite = http.SameSiteNoneMode
+		}
 	}
 
 	w.Header().Add("Set-Cookie", httpCookie.String())
@@ -163,6 +179,7 @@ func (cookie *Cookie) SetOptOut(optOut bool) {
 
 	if optOut {
 		cookie.uids = make(map[string]UIDEntry)
+		cookie.storeKey = ""
 	}
 }
 
@@ -221,14 +238,16 @@ func checkAudienceNetwork(key string, uid string) bool {
 // This exists so that Cookie (which is public) can have private fields, and the rest of
 // the code doesn't have to worry about the cookie data storage format.
 type cookieJson struct {
-	UIDs   map[string]UIDEntry `json:"tempUIDs,omitempty"`
-	OptOut bool                `json:"optout,omitempty"`
+	UIDs     map[string]UIDEntry `json:"tempUIDs,omitempty"`
+	OptOut   bool                `json:"optout,omitempty"`
+	StoreKey string              `json:"key,omitempty"`
 }
 
 func (cookie *Cookie) MarshalJSON() ([]byte, error) { // nosemgrep: marshal-json-pointer-receiver
 	return jsonutil.Marshal(cookieJson{
-		UIDs:   cookie.uids,
-		OptOut: cookie.optOut,
+		UIDs:     cookie.uids,
+		OptOut:   cookie.optOut,
+		StoreKey: cookie.storeKey,
 	})
 }
 
@@ -244,6 +263,7 @@ func (cookie *Cookie) UnmarshalJSON(b []byte) error {
 		cookie.uids = nil
 	} else {
 		cookie.uids = cookieContract.UIDs
+		cookie.storeKey = cookieContract.StoreKey
 	}
 
 	if cookie.uids == nil {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"testing"
 	"time"
 
@@ -377,6 +378,61 @@ func TestWriteCookieUserAgent(t *testing.T) {
 	}
 }
 
+func TestWriteCookieMode(t *testing.T) {
+	testCases := []struct {
+		name               string
+		givenHostCookie    *config.HostCookie
+		givenSetSiteCookie bool
+		expectedCookie     string
+	}{
+		{
+			name:               "third-party",
+			givenHostCookie:    &config.HostCookie{Domain: "pbs.example.com"},
+			givenSetSiteCookie: true,
+			expectedCookie:     "uids=value; Path=/; Domain=pbs.example.com; Secure; SameSite=None",
+		},
+		{
+			name:               "partitioned",
+			givenHostCookie:    &config.HostCookie{Domain: "pbs.example.com", Mode: config.UIDsCookieModePartitioned},
+			givenSetSiteCookie: true,
+			expectedCookie:     "uids=value; Path=/; Domain=pbs.example.com; Secure; SameSite=None; Partitioned",
+		},
+		{
+			name:            "partitioned-without-site-cookie",
+			givenHostCookie: &config.HostCookie{Mode: config.UIDsCookieModePartitioned},
+			expectedCookie:  "uids=value; Path=/; Secure; Partitioned",
+		},
+		{
+			name:               "first-party",
+			givenHostCookie:    &config.HostCookie{Domain: "publisher.com", Mode: config.UIDsCookieModeFirstParty, UIDsCookieName: "pbs_uids"},
+			givenSetSiteCookie: true,
+			expectedCookie:     "pbs_uids=value; Path=/; Domain=publisher.com; Secure; SameSite=Lax",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			WriteCookie(w, "value", test.givenHostCookie, test.givenSetSiteCookie)
+
+			// the expiry isn't compared
+			setCookie := regexp.MustCompile(`; Expires=[^;]+`).ReplaceAllString(w.Header().Get("Set-Cookie"), "")
+			assert.Equal(t, test.expectedCookie, setCookie)
+		})
+	}
+}
+
+func TestReadCookieName(t *testing.T) {
+	encodedCookie, err := Base64Encoder{}.Encode(&Cookie{uids: map[string]UIDEntry{"adnxs": {UID: "UID", Expires: time.Now().Add(time.Hour)}}})
+	assert.NoError(t, err)
+	request := httptest.NewRequest("POST", "http://www.prebid.com", nil)
+	request.AddCookie(&http.Cookie{Name: "pbs_uids", Value: encodedCookie})
+
+	assert.Equal(t, map[string]string{}, ReadCookie(request, Base64Decoder{}, &config.HostCookie{}).GetUIDs())
+	assert.Equal(t, map[string]string{"adnxs": "UID"}, ReadCookie(request, Base64Decoder{}, &config.HostCookie{UIDsCookieName: "pbs_uids"}).GetUIDs())
+}
+
 func TestPrepareCookieForWrite(t *testing.T) {
 	encoder := Base64Encoder{}
 	decoder := Base64Decoder{}
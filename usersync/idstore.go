@@ -0,0 +1,101 @@
+package usersync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/util/uuidutil"
+)
+
+// IDStore keeps the UIDs of the users server side, by the key of the user. External stores only need to implement it
+// to be used in place of the embedded one.
+type IDStore interface {
+	// Get returns the UIDs of the key, or none if the key is unknown or expired
+	Get(ctx context.Context, key string) (map[string]UIDEntry, error)
+	// Set replaces the UIDs of the key, removing the key if there are none
+	Set(ctx context.Context, key string, uids map[string]UIDEntry) error
+}
+
+// ErrIDStore is wrapped by the errors of the IDStore, which aren't caused by the request
+var ErrIDStore = errors.New("id store")
+
+// CookieStore reads and writes the uids cookies whose UIDs are kept in an IDStore, so that the cookies only carry the
+// key of the user. A nil CookieStore keeps the UIDs in the cookies.
+type CookieStore struct {
+	store     IDStore
+	keyCookie string
+	keys      uuidutil.UUIDGenerator
+}
+
+// NewCookieStore returns the CookieStore of the store, or nil if the id store is disabled
+func NewCookieStore(cfg config.UserSyncIDStore, store IDStore) *CookieStore {
+	if !cfg.Enabled || store == nil {
+		return nil
+	}
+	return &CookieStore{
+		store:     store,
+		keyCookie: cfg.KeyCookie,
+		keys:      uuidutil.UUIDRandomGenerator{},
+	}
+}
+
+// ReadCookie reads the cookie from the request, with the UIDs kept in the store under the key of the user. The UIDs
+// of the cookies written before the store was used are kept, so that they're moved to the store on the next write.
+func (s *CookieStore) ReadCookie(r *http.Request, decoder Decoder, host *config.HostCookie) *Cookie {
+	cookie := ReadCookie(r, decoder, host)
+	if s == nil || !cookie.AllowSyncs() {
+		return cookie
+	}
+
+	if s.keyCookie != "" {
+		if keyCookie, err := r.Cookie(s.keyCookie); err == nil && keyCookie.Value != "" {
+			cookie.storeKey = keyCookie.Value
+		}
+	}
+	if cookie.storeKey == "" {
+		return cookie
+	}
+
+	uids, err := s.store.Get(r.Context(), cookie.storeKey)
+	if err != nil {
+		logger.Warnf("Failed to get the UIDs of the user from the id store: %v", err)
+		return cookie
+	}
+	for key, uid := range uids {
+		cookie.uids[key] = uid
+	}
+	return cookie
+}
+
+// PrepareCookieForWrite saves the UIDs of the cookie in the store under the key of the user, issuing one if needed,
+// and returns the encoded cookie carrying only the key. Without a store, the UIDs are ejected as long as the cookie
+// is too full.
+func (s *CookieStore) PrepareCookieForWrite(ctx context.Context, cookie *Cookie, cfg *config.HostCookie, encoder Encoder, ejector Ejector) (string, error) {
+	if s == nil || !cookie.AllowSyncs() {
+		return cookie.PrepareCookieForWrite(cfg, encoder, ejector)
+	}
+
+	if cookie.storeKey == "" {
+		key, err := s.keys.Generate()
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrIDStore, err)
+		}
+		cookie.storeKey = key
+	}
+	if err := s.store.Set(ctx, cookie.storeKey, cookie.uids); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrIDStore, err)
+	}
+	return encoder.Encode(&Cookie{storeKey: cookie.storeKey})
+}
+
+// Forget removes the UIDs kept in the store under the key of the user, when they opt out
+func (s *CookieStore) Forget(ctx context.Context, cookie *Cookie) error {
+	if s == nil || cookie.storeKey == "" {
+		return nil
+	}
+	return s.store.Set(ctx, cookie.storeKey, nil)
+}
@@ -0,0 +1,278 @@
+package idstore
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/logger"
+	"github.com/prebid/prebid-server/v3/usersync"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const entrySuffix = ".json"
+
+// entry is the content of the file of a key
+type entry struct {
+	Expires time.Time                    `json:"expires"`
+	UIDs    map[string]usersync.UIDEntry `json:"uids"`
+}
+
+// indexedFile is a file of the store, as tracked by its index
+type indexedFile struct {
+	path    string
+	size    int64
+	expires time.Time
+}
+
+// DiskStore is an embedded IDStore keeping the UIDs of each key in a file of a directory, until they expire. The
+// files are named after the SHA-256 of the keys, spread across subdirectories named after their first byte.
+//
+// The files are indexed in memory in the order they expire, which is the order they were set since they all live
+// for the same TTL. The index is built from the modification times of the files at startup, so that removing the
+// expired files, or the oldest ones once the store exceeds its max size, doesn't need to read the directory.
+type DiskStore struct {
+	dir     string
+	ttl     time.Duration
+	maxSize int64
+	now     func() time.Time
+	done    chan struct{}
+	wg      sync.WaitGroup
+
+	lock sync.Mutex
+	// files holds the indexedFiles, from the first to expire to the last
+	files  *list.List
+	byPath map[string]*list.Element
+	size   int64
+	// evicted counts the files removed to stay within the max size since the last cleanup, which logs it
+	evicted int
+}
+
+// NewDiskStore returns the DiskStore of cfg.Dir, which removes the expired files every cfg.CleanupIntervalSeconds
+// until Shutdown
+func NewDiskStore(cfg config.UserSyncIDStore) (*DiskStore, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	s := &DiskStore{
+		dir:     cfg.Dir,
+		ttl:     time.Duration(cfg.TTLDays) * 24 * time.Hour,
+		maxSize: int64(cfg.MaxSizeMB) * 1024 * 1024,
+		now:     time.Now,
+		done:    make(chan struct{}),
+		files:   list.New(),
+		byPath:  make(map[string]*list.Element),
+	}
+	if err := s.loadIndex(); err != nil {
+		return nil, err
+	}
+	s.wg.Add(1)
+	go s.cleanup(time.Duration(cfg.CleanupIntervalSeconds) * time.Second)
+	return s, nil
+}
+
+// Shutdown stops the removal of the expired files
+func (s *DiskStore) Shutdown() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+func (s *DiskStore) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(hash[:])
+	return filepath.Join(s.dir, name[:2], name+entrySuffix)
+}
+
+// loadIndex indexes the files of the directory, and removes the temporary files left by a crash
+func (s *DiskStore) loadIndex() error {
+	var files []indexedFile
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if !strings.HasSuffix(path, entrySuffix) {
+			os.Remove(path)
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, indexedFile{path: path, size: info.Size(), expires: info.ModTime().Add(s.ttl)})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].expires.Before(files[j].expires)
+	})
+	for _, file := range files {
+		s.byPath[file.path] = s.files.PushBack(file)
+		s.size += file.size
+	}
+	s.evict()
+	return nil
+}
+
+func (s *DiskStore) Get(ctx context.Context, key string) (map[string]usersync.UIDEntry, error) {
+	path := s.path(key)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var e entry
+	if err := jsonutil.UnmarshalValid(data, &e); err != nil {
+		s.remove(path)
+		return nil, err
+	}
+	if now := s.now(); !now.Before(e.Expires) {
+		s.removeIfExpired(path, now)
+		return nil, nil
+	}
+	return e.UIDs, nil
+}
+
+// Set writes the UIDs to a temporary file renamed over the file of the key, so that it's never read half written.
+// The files which were set the longest ago are then removed while the store exceeds its max size.
+func (s *DiskStore) Set(ctx context.Context, key string, uids map[string]usersync.UIDEntry) error {
+	path := s.path(key)
+	if len(uids) == 0 {
+		return s.remove(path)
+	}
+
+	expires := s.now().Add(s.ttl)
+	data, err := jsonutil.Marshal(entry{Expires: expires, UIDs: uids})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	s.unindex(path)
+	s.byPath[path] = s.files.PushBack(indexedFile{path: path, size: int64(len(data)), expires: expires})
+	s.size += int64(len(data))
+	s.evict()
+	return nil
+}
+
+// remove removes the file and its index entry
+func (s *DiskStore) remove(path string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.unindex(path)
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// removeIfExpired removes the file unless it was set again since it was read
+func (s *DiskStore) removeIfExpired(path string, now time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if element, ok := s.byPath[path]; ok && now.Before(element.Value.(indexedFile).expires) {
+		return
+	}
+	s.unindex(path)
+	os.Remove(path)
+}
+
+// unindex removes the index entry of the file, if any. The lock must be held.
+func (s *DiskStore) unindex(path string) {
+	if element, ok := s.byPath[path]; ok {
+		s.size -= element.Value.(indexedFile).size
+		s.files.Remove(element)
+		delete(s.byPath, path)
+	}
+}
+
+// evict removes the first files to expire while the store exceeds its max size. The lock must be held.
+func (s *DiskStore) evict() {
+	for s.size > s.maxSize && s.files.Len() > 0 {
+		file := s.files.Front().Value.(indexedFile)
+		s.unindex(file.path)
+		os.Remove(file.path)
+		s.evicted++
+	}
+}
+
+func (s *DiskStore) cleanup(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if removed := s.removeExpired(); removed > 0 {
+				logger.Infof("Removed the expired UIDs of %d users from the id store", removed)
+			}
+			s.lock.Lock()
+			evicted := s.evicted
+			s.evicted = 0
+			s.lock.Unlock()
+			if evicted > 0 {
+				logger.Warnf("Removed the UIDs of %d users set the longest ago, since the id store reached its max size", evicted)
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// removeExpired removes the files of the expired keys, and returns how many were removed
+func (s *DiskStore) removeExpired() int {
+	now := s.now()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	removed := 0
+	for s.files.Len() > 0 {
+		file := s.files.Front().Value.(indexedFile)
+		if now.Before(file.expires) {
+			break
+		}
+		s.unindex(file.path)
+		if os.Remove(file.path) == nil {
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,140 @@
+package idstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/usersync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDiskStore(t *testing.T) *DiskStore {
+	return openTestDiskStore(t, t.TempDir())
+}
+
+func openTestDiskStore(t *testing.T, dir string) *DiskStore {
+	store, err := NewDiskStore(config.UserSyncIDStore{Enabled: true, Dir: dir, TTLDays: 1, CleanupIntervalSeconds: 3600, MaxSizeMB: 1})
+	require.NoError(t, err)
+	t.Cleanup(store.Shutdown)
+	return store
+}
+
+func TestDiskStoreGetSet(t *testing.T) {
+	store := newTestDiskStore(t)
+	ctx := context.Background()
+	uids := map[string]usersync.UIDEntry{"adnxs": {UID: "adnxs-uid", Expires: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}}
+
+	stored, err := store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, stored, "unknown key")
+
+	require.NoError(t, store.Set(ctx, "key", uids))
+	stored, err = store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, uids, stored)
+
+	_, err = os.Stat(store.path("key"))
+	assert.NoError(t, err)
+	matches, err := filepath.Glob(filepath.Join(store.dir, "*", "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, matches, "no temporary file is left")
+
+	require.NoError(t, store.Set(ctx, "key", nil))
+	stored, err = store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, stored, "removed key")
+	assert.NoError(t, store.Set(ctx, "key", nil), "removing a missing key")
+}
+
+func TestDiskStoreExpiry(t *testing.T) {
+	store := newTestDiskStore(t)
+	ctx := context.Background()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	uids := map[string]usersync.UIDEntry{"adnxs": {UID: "adnxs-uid"}}
+
+	require.NoError(t, store.Set(ctx, "expired", uids))
+	require.NoError(t, store.Set(ctx, "removed-by-cleanup", uids))
+	now = now.Add(12 * time.Hour)
+	require.NoError(t, store.Set(ctx, "live", uids))
+	now = now.Add(13 * time.Hour)
+
+	stored, err := store.Get(ctx, "expired")
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+	_, err = os.Stat(store.path("expired"))
+	assert.True(t, os.IsNotExist(err), "the expired key is removed when read")
+
+	assert.Equal(t, 1, store.removeExpired())
+	_, err = os.Stat(store.path("removed-by-cleanup"))
+	assert.True(t, os.IsNotExist(err))
+
+	stored, err = store.Get(ctx, "live")
+	require.NoError(t, err)
+	assert.Equal(t, uids, stored)
+}
+
+func TestDiskStoreCorruptedFile(t *testing.T) {
+	store := newTestDiskStore(t)
+	path := store.path("key")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err := store.Get(context.Background(), "key")
+	assert.Error(t, err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "the corrupted file is removed when read")
+}
+
+func TestDiskStoreLoadIndex(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestDiskStore(t, dir)
+	ctx := context.Background()
+	uids := map[string]usersync.UIDEntry{"adnxs": {UID: "adnxs-uid"}}
+
+	require.NoError(t, store.Set(ctx, "old", uids))
+	require.NoError(t, store.Set(ctx, "new", uids))
+	old := time.Now().Add(-25 * time.Hour)
+	require.NoError(t, os.Chtimes(store.path("old"), old, old))
+	tmp := store.path("crashed") + ".123.tmp"
+	require.NoError(t, os.MkdirAll(filepath.Dir(tmp), 0o700))
+	require.NoError(t, os.WriteFile(tmp, []byte("{"), 0o600))
+
+	reopened := openTestDiskStore(t, dir)
+	assert.Equal(t, 2, reopened.files.Len())
+	_, err := os.Stat(tmp)
+	assert.True(t, os.IsNotExist(err), "the temporary files are removed")
+
+	assert.Equal(t, 1, reopened.removeExpired(), "the files are indexed by modification time")
+	_, err = os.Stat(reopened.path("old"))
+	assert.True(t, os.IsNotExist(err))
+	stored, err := reopened.Get(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, uids, stored)
+}
+
+func TestDiskStoreMaxSize(t *testing.T) {
+	store := newTestDiskStore(t)
+	ctx := context.Background()
+	uids := map[string]usersync.UIDEntry{"adnxs": {UID: strings.Repeat("u", 300*1024)}}
+
+	for _, key := range []string{"first", "second", "third"} {
+		require.NoError(t, store.Set(ctx, key, uids))
+	}
+	require.NoError(t, store.Set(ctx, "first", uids), "setting a key again makes it the newest")
+	require.NoError(t, store.Set(ctx, "fourth", uids))
+
+	for key, kept := range map[string]bool{"first": true, "second": false, "third": true, "fourth": true} {
+		stored, err := store.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, kept, stored != nil, key)
+	}
+	assert.LessOrEqual(t, store.size, store.maxSize)
+	assert.Equal(t, 1, store.evicted)
+}
@@ -0,0 +1,209 @@
+package usersync
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIDStore struct {
+	uids map[string]map[string]UIDEntry
+	err  error
+}
+
+func (s *fakeIDStore) Get(ctx context.Context, key string) (map[string]UIDEntry, error) {
+	return s.uids[key], s.err
+}
+
+func (s *fakeIDStore) Set(ctx context.Context, key string, uids map[string]UIDEntry) error {
+	if s.err != nil {
+		return s.err
+	}
+	if len(uids) == 0 {
+		delete(s.uids, key)
+		return nil
+	}
+	s.uids[key] = uids
+	return nil
+}
+
+type fakeKeyGenerator struct{}
+
+func (fakeKeyGenerator) Generate() (string, error) {
+	return "generated-key", nil
+}
+
+func TestNewCookieStore(t *testing.T) {
+	store := &fakeIDStore{}
+
+	assert.Nil(t, NewCookieStore(config.UserSyncIDStore{}, store), "disabled")
+	assert.Nil(t, NewCookieStore(config.UserSyncIDStore{Enabled: true}, nil), "no store")
+	assert.NotNil(t, NewCookieStore(config.UserSyncIDStore{Enabled: true}, store))
+}
+
+func TestCookieStoreReadCookie(t *testing.T) {
+	expires := time.Now().Add(time.Hour).UTC()
+	store := &fakeIDStore{uids: map[string]map[string]UIDEntry{
+		"pbs-key":         {"adnxs": {UID: "stored-adnxs", Expires: expires}},
+		"first-party-key": {"rubicon": {UID: "stored-rubicon", Expires: expires}},
+	}}
+
+	testCases := []struct {
+		name         string
+		keyCookie    string
+		cookie       *Cookie
+		httpCookies  []*http.Cookie
+		storeErr     error
+		expectedUIDs map[string]string
+		expectedKey  string
+	}{
+		{
+			name:         "pbs-key",
+			cookie:       &Cookie{uids: map[string]UIDEntry{"legacy": {UID: "legacy-uid", Expires: expires}}, storeKey: "pbs-key"},
+			expectedUIDs: map[string]string{"adnxs": "stored-adnxs", "legacy": "legacy-uid"},
+			expectedKey:  "pbs-key",
+		},
+		{
+			name:         "first-party-key",
+			keyCookie:    "fpid",
+			cookie:       &Cookie{storeKey: "pbs-key"},
+			httpCookies:  []*http.Cookie{{Name: "fpid", Value: "first-party-key"}},
+			expectedUIDs: map[string]string{"rubicon": "stored-rubicon"},
+			expectedKey:  "first-party-key",
+		},
+		{
+			name:         "first-party-key-missing",
+			keyCookie:    "fpid",
+			cookie:       &Cookie{storeKey: "pbs-key"},
+			expectedUIDs: map[string]string{"adnxs": "stored-adnxs"},
+			expectedKey:  "pbs-key",
+		},
+		{
+			name:         "no-key",
+			cookie:       &Cookie{},
+			expectedUIDs: map[string]string{},
+		},
+		{
+			name:         "opted-out",
+			cookie:       &Cookie{optOut: true},
+			expectedUIDs: map[string]string{},
+		},
+		{
+			name:         "store-error",
+			cookie:       &Cookie{uids: map[string]UIDEntry{"legacy": {UID: "legacy-uid", Expires: expires}}, storeKey: "pbs-key"},
+			storeErr:     errors.New("failed"),
+			expectedUIDs: map[string]string{"legacy": "legacy-uid"},
+			expectedKey:  "pbs-key",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			store.err = test.storeErr
+			cookieStore := NewCookieStore(config.UserSyncIDStore{Enabled: true, KeyCookie: test.keyCookie}, store)
+
+			request := httptest.NewRequest("POST", "http://www.prebid.com", nil)
+			httpCookie, err := ToHTTPCookie(test.cookie)
+			require.NoError(t, err)
+			request.AddCookie(httpCookie)
+			for _, c := range test.httpCookies {
+				request.AddCookie(c)
+			}
+
+			cookie := cookieStore.ReadCookie(request, Base64Decoder{}, &config.HostCookie{})
+
+			assert.Equal(t, test.expectedUIDs, cookie.GetUIDs())
+			assert.Equal(t, test.expectedKey, cookie.storeKey)
+		})
+	}
+}
+
+func TestCookieStorePrepareCookieForWrite(t *testing.T) {
+	expires := time.Now().Add(time.Hour).UTC()
+	uids := map[string]UIDEntry{"adnxs": {UID: "adnxs-uid", Expires: expires}}
+
+	testCases := []struct {
+		name           string
+		cookie         *Cookie
+		storeErr       error
+		expectedKey    string
+		expectedStored map[string]map[string]UIDEntry
+		expectedErr    error
+	}{
+		{
+			name:           "new-key",
+			cookie:         &Cookie{uids: uids},
+			expectedKey:    "generated-key",
+			expectedStored: map[string]map[string]UIDEntry{"generated-key": uids},
+		},
+		{
+			name:           "existing-key",
+			cookie:         &Cookie{uids: uids, storeKey: "pbs-key"},
+			expectedKey:    "pbs-key",
+			expectedStored: map[string]map[string]UIDEntry{"pbs-key": uids},
+		},
+		{
+			name:           "opted-out",
+			cookie:         &Cookie{uids: map[string]UIDEntry{}, optOut: true},
+			expectedStored: map[string]map[string]UIDEntry{},
+		},
+		{
+			name:           "store-error",
+			cookie:         &Cookie{uids: uids, storeKey: "pbs-key"},
+			storeErr:       errors.New("failed"),
+			expectedStored: map[string]map[string]UIDEntry{},
+			expectedErr:    ErrIDStore,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			store := &fakeIDStore{uids: map[string]map[string]UIDEntry{}, err: test.storeErr}
+			cookieStore := &CookieStore{store: store, keys: fakeKeyGenerator{}}
+
+			encoded, err := cookieStore.PrepareCookieForWrite(context.Background(), test.cookie, &config.HostCookie{}, Base64Encoder{}, &OldestEjector{})
+
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedStored, store.uids)
+
+			written := Base64Decoder{}.Decode(encoded)
+			assert.Equal(t, test.expectedKey, written.storeKey)
+			if test.expectedKey != "" {
+				assert.Empty(t, written.uids, "the cookie only carries the key")
+			}
+		})
+	}
+}
+
+func TestCookieStoreForget(t *testing.T) {
+	store := &fakeIDStore{uids: map[string]map[string]UIDEntry{"pbs-key": {"adnxs": {UID: "adnxs-uid"}}}}
+	cookieStore := &CookieStore{store: store, keys: fakeKeyGenerator{}}
+	cookie := &Cookie{storeKey: "pbs-key"}
+
+	require.NoError(t, cookieStore.Forget(context.Background(), cookie))
+	cookie.SetOptOut(true)
+
+	assert.Empty(t, store.uids)
+	assert.Empty(t, cookie.storeKey)
+}
+
+func TestNilCookieStore(t *testing.T) {
+	var cookieStore *CookieStore
+	cookie := &Cookie{uids: map[string]UIDEntry{"adnxs": {UID: "adnxs-uid", Expires: time.Now().Add(time.Hour)}}}
+
+	encoded, err := cookieStore.PrepareCookieForWrite(context.Background(), cookie, &config.HostCookie{}, Base64Encoder{}, &OldestEjector{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"adnxs": "adnxs-uid"}, Base64Decoder{}.Decode(encoded).GetUIDs())
+	assert.NoError(t, cookieStore.Forget(context.Background(), cookie))
+}
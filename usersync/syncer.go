@@ -130,8 +130,15 @@ func buildTemplate(bidderName, syncTypeValue string, hostConfig config.UserSync,
 	redirectURL = macroRegexBidderName.ReplaceAllLiteralString(redirectURL, bidderName)
 	redirectURL = macroRegexSyncType.ReplaceAllLiteralString(redirectURL, syncTypeValue)
 	redirectURL = macroRegexUserMacro.ReplaceAllLiteralString(redirectURL, syncerEndpoint.UserMacro)
-	redirectURL = macroRegexExternalHost.ReplaceAllLiteralString(redirectURL, externalURL)
+	// The external URL of the host is resolved with the sync, so that it can be overridden by the ExternalURL macro
+	hostExternalURL := syncerEndpoint.ExternalURL == "" && syncerExternalURL == ""
+	if !hostExternalURL {
+		redirectURL = macroRegexExternalHost.ReplaceAllLiteralString(redirectURL, externalURL)
+	}
 	redirectURL = escapeTemplate(redirectURL)
+	if hostExternalURL {
+		redirectURL = macroRegexExternalHost.ReplaceAllLiteralString(redirectURL, "{{if .ExternalURL}}{{urlquery .ExternalURL}}{{else}}"+url.QueryEscape(externalURL)+"{{end}}")
+	}
 
 	url := macroRegexRedirect.ReplaceAllString(syncerEndpoint.URL, redirectURL)
 
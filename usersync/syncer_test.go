@@ -337,6 +337,45 @@ func TestBuildTemplate(t *testing.T) {
 	}
 }
 
+func TestBuildTemplateExternalURLMacro(t *testing.T) {
+	hostConfig := config.UserSync{ExternalURL: "http://host.com", RedirectURL: "{{.ExternalURL}}/setuid?bidder={{.SyncerKey}}&gdpr={{.GDPR}}"}
+	macroValues := macros.UserSyncPrivacy{GDPR: "A", ExternalURL: "https://pbs.publisher.com"}
+
+	testCases := []struct {
+		description            string
+		givenSyncerExternalURL string
+		givenSyncerEndpoint    config.SyncerEndpoint
+		expectedRendered       string
+	}{
+		{
+			description:         "External URL From Host",
+			givenSyncerEndpoint: config.SyncerEndpoint{URL: "https://bidder.com/sync?redirect={{.RedirectURL}}"},
+			expectedRendered:    "https://bidder.com/sync?redirect=https%3A%2F%2Fpbs.publisher.com%2Fsetuid%3Fbidder%3DanyKey%26gdpr%3DA",
+		},
+		{
+			description:         "External URL From Syncer Endpoint",
+			givenSyncerEndpoint: config.SyncerEndpoint{URL: "https://bidder.com/sync?redirect={{.RedirectURL}}", ExternalURL: "http://syncer.com"},
+			expectedRendered:    "https://bidder.com/sync?redirect=http%3A%2F%2Fsyncer.com%2Fsetuid%3Fbidder%3DanyKey%26gdpr%3DA",
+		},
+		{
+			description:            "External URL From Syncer Config",
+			givenSyncerExternalURL: "http://syncershared.com",
+			givenSyncerEndpoint:    config.SyncerEndpoint{URL: "https://bidder.com/sync?redirect={{.RedirectURL}}"},
+			expectedRendered:       "https://bidder.com/sync?redirect=http%3A%2F%2Fsyncershared.com%2Fsetuid%3Fbidder%3DanyKey%26gdpr%3DA",
+		},
+	}
+
+	for _, test := range testCases {
+		result, err := buildTemplate("anyKey", "x", hostConfig, test.givenSyncerExternalURL, test.givenSyncerEndpoint, "")
+		if assert.NoError(t, err, test.description+":err") {
+			resultRendered, err := macros.ResolveMacros(result, macroValues)
+			if assert.NoError(t, err, test.description+":template_render") {
+				assert.Equal(t, test.expectedRendered, resultRendered, test.description+":template")
+			}
+		}
+	}
+}
+
 func TestChooseExternalURL(t *testing.T) {
 	testCases := []struct {
 		description            string
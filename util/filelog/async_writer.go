@@ -0,0 +1,106 @@
+// Package filelog writes records to files from a single goroutine, for the access log and the file analytics modules.
+package filelog
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/prebid-server/v3/logger"
+)
+
+// Sink receives the items of an AsyncWriter. Its methods are only called by the writing goroutine, so it doesn't
+// need to be safe for concurrent use. It reports its own errors.
+type Sink[T any] interface {
+	// Write writes a queued item
+	Write(item T)
+	// Tick is called every tick interval, to flush buffers or rotate files
+	Tick()
+	// Close is called once the queue is drained on shutdown
+	Close()
+}
+
+// AsyncWriter writes items to a Sink from a single goroutine, through a bounded queue. Items are dropped when the
+// queue is full, and the number of dropped items is logged every tick.
+type AsyncWriter[T any] struct {
+	// name prefixes the log messages, e.g. "[AccessLog]", and items names what is dropped, e.g. "entries"
+	name    string
+	items   string
+	sink    Sink[T]
+	dropped atomic.Int64
+
+	// closed guards the queue, which is closed on shutdown
+	mux    sync.RWMutex
+	closed bool
+	queue  chan T
+	done   chan struct{}
+}
+
+// NewAsyncWriter returns an AsyncWriter which queues up to queueSize items, and starts its writing goroutine
+func NewAsyncWriter[T any](name, items string, sink Sink[T], queueSize int, tickInterval time.Duration, clock clock.Clock) *AsyncWriter[T] {
+	w := &AsyncWriter[T]{
+		name:  name,
+		items: items,
+		sink:  sink,
+		queue: make(chan T, queueSize),
+		done:  make(chan struct{}),
+	}
+	go w.start(clock.Ticker(tickInterval))
+	return w
+}
+
+func (w *AsyncWriter[T]) start(ticker *clock.Ticker) {
+	defer ticker.Stop()
+
+	for {
+		select {
+		case item, ok := <-w.queue:
+			if !ok {
+				w.sink.Close()
+				w.reportDropped()
+				close(w.done)
+				return
+			}
+			w.sink.Write(item)
+		case <-ticker.C:
+			w.sink.Tick()
+			w.reportDropped()
+		}
+	}
+}
+
+func (w *AsyncWriter[T]) reportDropped() {
+	if dropped := w.dropped.Swap(0); dropped > 0 {
+		logger.Warnf("%s Dropped %d %s since the write queue was full", w.name, dropped, w.items)
+	}
+}
+
+// Write queues the item, or drops it if the queue is full or the writer is shut down
+func (w *AsyncWriter[T]) Write(item T) {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+	if w.closed {
+		return
+	}
+	select {
+	case w.queue <- item:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// Shutdown writes the queued items and closes the sink
+func (w *AsyncWriter[T]) Shutdown() {
+	w.mux.Lock()
+	if w.closed {
+		w.mux.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.queue)
+	w.mux.Unlock()
+
+	logger.Infof("%s Shutdown, writing the queued %s", w.name, w.items)
+	<-w.done
+}
@@ -0,0 +1,54 @@
+package filelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSink struct {
+	items  []string
+	ticks  int
+	closed bool
+}
+
+func (s *recordingSink) Write(item string) {
+	s.items = append(s.items, item)
+}
+
+func (s *recordingSink) Tick() {
+	s.ticks++
+}
+
+func (s *recordingSink) Close() {
+	s.closed = true
+}
+
+func TestAsyncWriter(t *testing.T) {
+	sink := &recordingSink{}
+	clockMock := clock.NewMock()
+	w := NewAsyncWriter[string]("[Test]", "items", sink, 10, time.Second, clockMock)
+
+	w.Write("first")
+	w.Write("second")
+	w.Shutdown()
+
+	assert.Equal(t, []string{"first", "second"}, sink.items, "the queued items are written on shutdown")
+	assert.True(t, sink.closed)
+
+	w.Write("after-shutdown")
+	w.Shutdown()
+	assert.Equal(t, []string{"first", "second"}, sink.items)
+}
+
+func TestAsyncWriterDropsItemsWhenQueueIsFull(t *testing.T) {
+	w := &AsyncWriter[string]{queue: make(chan string, 1)}
+
+	w.Write("queued")
+	w.Write("dropped")
+
+	assert.Len(t, w.queue, 1)
+	assert.Equal(t, int64(1), w.dropped.Load())
+}
@@ -0,0 +1,242 @@
+package filelog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/prebid-server/v3/logger"
+)
+
+const (
+	compressedSuffix = ".gz"
+	// partialSuffix marks files being compressed, which must not be ingested yet
+	partialSuffix = ".tmp"
+	// rotatedTimeFormat names the rotated files after the time they were started, so they sort chronologically
+	rotatedTimeFormat = "20060102T150405.000000000Z"
+	writeBufferSize   = 64 * 1024
+)
+
+// RotatingFileConfig configures a RotatingFile
+type RotatingFileConfig struct {
+	Path string
+	// MaxSize is the size above which the file is rotated
+	MaxSize int64
+	// MaxAge is the age at which the file is rotated on Flush, or 0 to only rotate by size
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files kept, or 0 to keep all of them
+	MaxBackups int
+	// Compress gzips the rotated files
+	Compress bool
+}
+
+// RotatingFile appends lines to a file, created on the first write. Once the file would exceed its max size, or on
+// Flush once it reached its max age, it's closed and renamed after the time it was started: "dir/name.ext" becomes
+// "dir/name-<start time>.ext", then gzipped if compression is enabled. Rotated files are never written again, so they
+// are safe to ingest. A file left by a previous run is rotated as it is, and so is the file on Close.
+// A RotatingFile isn't safe for concurrent use.
+type RotatingFile struct {
+	cfg   RotatingFileConfig
+	clock clock.Clock
+
+	file    *os.File
+	writer  *bufio.Writer
+	size    int64
+	started time.Time
+
+	compressing sync.WaitGroup
+}
+
+// NewRotatingFile returns the RotatingFile of cfg.Path, creating its directory
+func NewRotatingFile(cfg RotatingFileConfig, clock clock.Clock) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0755); err != nil {
+		return nil, err
+	}
+	return &RotatingFile{cfg: cfg, clock: clock}, nil
+}
+
+// Write appends the line, rotating the file first if the line would make it exceed its max size
+func (f *RotatingFile) Write(line []byte) error {
+	if f.file != nil && f.size > 0 && f.size+int64(len(line)) > f.cfg.MaxSize {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+
+	n, err := f.writer.Write(line)
+	f.size += int64(n)
+	return err
+}
+
+// Flush writes the buffered lines to the file, or rotates it if it reached its max age
+func (f *RotatingFile) Flush() error {
+	if f.file == nil {
+		return nil
+	}
+	if f.cfg.MaxAge > 0 && f.clock.Since(f.started) >= f.cfg.MaxAge {
+		return f.rotate()
+	}
+	return f.writer.Flush()
+}
+
+// Close rotates the file and waits for the rotated files to be compressed
+func (f *RotatingFile) Close() error {
+	var err error
+	if f.file != nil {
+		err = f.rotate()
+	}
+	f.compressing.Wait()
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	// A file left by a previous run, which didn't shut down cleanly, is rotated as it is
+	if info, err := os.Stat(f.cfg.Path); err == nil {
+		if err := f.archive(f.cfg.Path, info.ModTime()); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(f.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	f.file = file
+	f.writer = bufio.NewWriterSize(file, writeBufferSize)
+	f.size = 0
+	f.started = f.clock.Now()
+	return nil
+}
+
+func (f *RotatingFile) rotate() error {
+	file := f.file
+	err := f.writer.Flush()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	f.file = nil
+	f.writer = nil
+	if err != nil {
+		return err
+	}
+
+	if f.size == 0 {
+		return os.Remove(file.Name())
+	}
+	return f.archive(file.Name(), f.started)
+}
+
+// archive renames the closed file after its start time, compresses it and removes the oldest rotated files
+func (f *RotatingFile) archive(path string, started time.Time) error {
+	rotatedPath := f.rotatedPath(started)
+	if err := os.Rename(path, rotatedPath); err != nil {
+		return err
+	}
+
+	if f.cfg.Compress {
+		f.compressing.Add(1)
+		go func() {
+			defer f.compressing.Done()
+			if err := compressFile(rotatedPath); err != nil {
+				logger.Errorf("Failed to compress %s: %v", rotatedPath, err)
+			}
+		}()
+	}
+	return f.removeOldBackups()
+}
+
+func (f *RotatingFile) splitPath() (base, ext string) {
+	ext = filepath.Ext(f.cfg.Path)
+	return strings.TrimSuffix(f.cfg.Path, ext), ext
+}
+
+// rotatedPath names the rotated file after its start time, adding a counter in the unlikely case the name is taken
+func (f *RotatingFile) rotatedPath(started time.Time) string {
+	base, ext := f.splitPath()
+	base += "-" + started.UTC().Format(rotatedTimeFormat)
+	path := base + ext
+	for i := 1; fileExists(path) || fileExists(path+compressedSuffix); i++ {
+		path = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	return path
+}
+
+// removeOldBackups removes the oldest rotated files beyond the max number of backups, if any
+func (f *RotatingFile) removeOldBackups() error {
+	if f.cfg.MaxBackups <= 0 {
+		return nil
+	}
+	base, ext := f.splitPath()
+	matches, err := filepath.Glob(base + "-*" + ext + "*")
+	if err != nil {
+		return err
+	}
+
+	var backups []string
+	for _, match := range matches {
+		if strings.HasSuffix(match, ext) || strings.HasSuffix(match, ext+compressedSuffix) {
+			backups = append(backups, match)
+		}
+	}
+	if len(backups) <= f.cfg.MaxBackups {
+		return nil
+	}
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-f.cfg.MaxBackups] {
+		if err := os.Remove(backup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// compressFile gzips the file to "<path>.gz" and removes it. The compressed file only gets its name once complete.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	partialPath := path + compressedSuffix + partialSuffix
+	dst, err := os.OpenFile(partialPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(partialPath)
+		return err
+	}
+
+	if err := os.Rename(partialPath, path+compressedSuffix); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,134 @@
+package filelog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listFiles(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var reader io.Reader = file
+	if filepath.Ext(path) == compressedSuffix {
+		gz, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		reader = gz
+	}
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRotatingFileRotatesBySize(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	clockMock := clock.NewMock()
+	f, err := NewRotatingFile(RotatingFileConfig{Path: filepath.Join(dir, "access.log"), MaxSize: 10, MaxBackups: 2}, clockMock)
+	require.NoError(t, err)
+
+	for _, line := range []string{"line1\n", "line2\n", "line3\n", "line4\n"} {
+		require.NoError(t, f.Write([]byte(line)))
+		clockMock.Add(time.Second)
+	}
+	require.NoError(t, f.Flush())
+
+	// Each line makes the file exceed the max size, so every one rotated the previous. Only 2 backups are kept.
+	assert.Equal(t, []string{"access-19700101T000001.000000000Z.log", "access-19700101T000002.000000000Z.log", "access.log"}, listFiles(t, dir))
+	assert.Equal(t, "line2\n", readFile(t, filepath.Join(dir, "access-19700101T000001.000000000Z.log")))
+	assert.Equal(t, "line3\n", readFile(t, filepath.Join(dir, "access-19700101T000002.000000000Z.log")))
+	assert.Equal(t, "line4\n", readFile(t, filepath.Join(dir, "access.log")))
+}
+
+func TestRotatingFileRotatesByAge(t *testing.T) {
+	dir := t.TempDir()
+	clockMock := clock.NewMock()
+	f, err := NewRotatingFile(RotatingFileConfig{Path: filepath.Join(dir, "amp.jsonl"), MaxSize: 1000, MaxAge: time.Minute}, clockMock)
+	require.NoError(t, err)
+
+	require.NoError(t, f.Flush())
+	assert.Empty(t, listFiles(t, dir), "the file is created on the first write")
+
+	require.NoError(t, f.Write([]byte("1\n")))
+	require.NoError(t, f.Flush())
+	assert.Equal(t, []string{"amp.jsonl"}, listFiles(t, dir), "the file should be kept until its max age")
+	assert.Equal(t, "1\n", readFile(t, filepath.Join(dir, "amp.jsonl")))
+
+	clockMock.Add(time.Minute)
+	require.NoError(t, f.Flush())
+	assert.Equal(t, []string{"amp-19700101T000000.000000000Z.jsonl"}, listFiles(t, dir))
+}
+
+func TestRotatingFileCompresses(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewRotatingFile(RotatingFileConfig{Path: filepath.Join(dir, "video.jsonl"), MaxSize: 1000, Compress: true}, clock.NewMock())
+	require.NoError(t, err)
+
+	require.NoError(t, f.Write([]byte("1\n")))
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, []string{"video-19700101T000000.000000000Z.jsonl.gz"}, listFiles(t, dir))
+	assert.Equal(t, "1\n", readFile(t, filepath.Join(dir, "video-19700101T000000.000000000Z.jsonl.gz")))
+}
+
+func TestRotatingFileRotatesLeftoverFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "auction.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0644))
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	f, err := NewRotatingFile(RotatingFileConfig{Path: path, MaxSize: 1000}, clock.NewMock())
+	require.NoError(t, err)
+	require.NoError(t, f.Write([]byte("new\n")))
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, []string{"auction-19700101T000000.000000000Z.jsonl", "auction-20240101T000000.000000000Z.jsonl"}, listFiles(t, dir))
+	assert.Equal(t, "old\n", readFile(t, filepath.Join(dir, "auction-20240101T000000.000000000Z.jsonl")))
+	assert.Equal(t, "new\n", readFile(t, filepath.Join(dir, "auction-19700101T000000.000000000Z.jsonl")))
+}
+
+func TestRotatingFileNameCollision(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewRotatingFile(RotatingFileConfig{Path: filepath.Join(dir, "auction.jsonl"), MaxSize: 2}, clock.NewMock())
+	require.NoError(t, err)
+
+	require.NoError(t, f.Write([]byte("1\n")))
+	require.NoError(t, f.Write([]byte("2\n")))
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, []string{"auction-19700101T000000.000000000Z-1.jsonl", "auction-19700101T000000.000000000Z.jsonl"}, listFiles(t, dir))
+}
+
+func TestRotatingFileRemovesEmptyFile(t *testing.T) {
+	dir := t.TempDir()
+	clockMock := clock.NewMock()
+	f, err := NewRotatingFile(RotatingFileConfig{Path: filepath.Join(dir, "setuid.jsonl"), MaxSize: 1000, MaxAge: time.Minute}, clockMock)
+	require.NoError(t, err)
+
+	require.NoError(t, f.Write(nil))
+	clockMock.Add(time.Minute)
+	require.NoError(t, f.Flush())
+
+	assert.Empty(t, listFiles(t, dir))
+}